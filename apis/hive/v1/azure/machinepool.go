@@ -41,6 +41,22 @@ type MachinePool struct {
 	// +kubebuilder:validation:Enum="Accelerated"; "Basic"
 	// +optional
 	VMNetworkingType string `json:"vmNetworkingType,omitempty"`
+
+	// SpotVMOptions allows users to configure instances to be run using Azure Spot VMs.
+	// +optional
+	SpotVMOptions *SpotVMOptions `json:"spotVMOptions,omitempty"`
+}
+
+// SpotVMOptions defines the options available to a user when configuring
+// Machines to run on Azure Spot VMs.
+// Most users should provide an empty struct.
+// Evicted Spot VMs are deleted, and the MachineSet replaces them once capacity is available again.
+type SpotVMOptions struct {
+	// MaxPrice is the maximum price, in US dollars per hour, the user is willing to pay for their instances.
+	// The value "-1" means the instances are only evicted for capacity, never for price.
+	// Default: On-Demand price
+	// +optional
+	MaxPrice *string `json:"maxPrice,omitempty"`
 }
 
 // OSImage is the image to use for the OS of a machine.
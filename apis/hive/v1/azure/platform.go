@@ -24,6 +24,40 @@ type Platform struct {
 	// If empty, the value is equal to "AzurePublicCloud".
 	// +optional
 	CloudName CloudEnvironment `json:"cloudName,omitempty"`
+
+	// PrivateLink allows users to enable access to the cluster's API server using Azure Private Link.
+	// Hive creates a private link service for the cluster's internal API load balancer, and a private
+	// endpoint for it in a virtual network of the hub.
+	// +optional
+	PrivateLink *PrivateLink `json:"privateLink,omitempty"`
+}
+
+// PrivateLink configures access to the cluster API using Azure Private Link.
+type PrivateLink struct {
+	// Enabled specifies if Private Link is to be enabled on the cluster.
+	Enabled bool `json:"enabled"`
+}
+
+// PlatformStatus contains the observed state on Azure platform.
+type PlatformStatus struct {
+	// PrivateLink contains the private link resource references
+	// +optional
+	PrivateLink *PrivateLinkStatus `json:"privateLink,omitempty"`
+}
+
+// PrivateLinkStatus contains the observed state for Azure Private Link resources.
+type PrivateLinkStatus struct {
+	// PrivateLinkService is the resource ID of the private link service created for the cluster.
+	// +optional
+	PrivateLinkService string `json:"privateLinkService,omitempty"`
+
+	// PrivateEndpoint is the resource ID of the private endpoint created for the cluster.
+	// +optional
+	PrivateEndpoint string `json:"privateEndpoint,omitempty"`
+
+	// PrivateDNSZone is the resource ID of the private DNS zone created for the cluster's API domain.
+	// +optional
+	PrivateDNSZone string `json:"privateDNSZone,omitempty"`
 }
 
 // CloudEnvironment is the name of the Azure cloud environment
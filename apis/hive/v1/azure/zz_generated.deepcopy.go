@@ -35,6 +35,11 @@ func (in *MachinePool) DeepCopyInto(out *MachinePool) {
 		*out = new(OSImage)
 		**out = **in
 	}
+	if in.SpotVMOptions != nil {
+		in, out := &in.SpotVMOptions, &out.SpotVMOptions
+		*out = new(SpotVMOptions)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -110,6 +115,11 @@ func (in *OSImage) DeepCopy() *OSImage {
 func (in *Platform) DeepCopyInto(out *Platform) {
 	*out = *in
 	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.PrivateLink != nil {
+		in, out := &in.PrivateLink, &out.PrivateLink
+		*out = new(PrivateLink)
+		**out = **in
+	}
 	return
 }
 
@@ -122,3 +132,77 @@ func (in *Platform) DeepCopy() *Platform {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlatformStatus) DeepCopyInto(out *PlatformStatus) {
+	*out = *in
+	if in.PrivateLink != nil {
+		in, out := &in.PrivateLink, &out.PrivateLink
+		*out = new(PrivateLinkStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlatformStatus.
+func (in *PlatformStatus) DeepCopy() *PlatformStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PlatformStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLink) DeepCopyInto(out *PrivateLink) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLink.
+func (in *PrivateLink) DeepCopy() *PrivateLink {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrivateLinkStatus) DeepCopyInto(out *PrivateLinkStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrivateLinkStatus.
+func (in *PrivateLinkStatus) DeepCopy() *PrivateLinkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PrivateLinkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpotVMOptions) DeepCopyInto(out *SpotVMOptions) {
+	*out = *in
+	if in.MaxPrice != nil {
+		in, out := &in.MaxPrice, &out.MaxPrice
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpotVMOptions.
+func (in *SpotVMOptions) DeepCopy() *SpotVMOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SpotVMOptions)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,148 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterAdoptionSpec defines the adoption of a running cluster into Hive.
+type ClusterAdoptionSpec struct {
+	// AdminKubeconfigSecretRef references the secret containing an admin kubeconfig for the cluster.
+	// The kubeconfig must be in a data field where the key is "kubeconfig". It becomes the admin kubeconfig secret of
+	// the ClusterDeployment.
+	AdminKubeconfigSecretRef corev1.LocalObjectReference `json:"adminKubeconfigSecretRef"`
+
+	// CredentialsSecretRef references the secret containing the cloud credentials of the cluster, in the format
+	// expected by its platform. It is required to adopt clusters on cloud platforms.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// PullSecretRef is the reference to the secret to use when pulling images for the ClusterDeployment.
+	// +optional
+	PullSecretRef *corev1.LocalObjectReference `json:"pullSecretRef,omitempty"`
+
+	// PreserveOnDelete is set on the ClusterDeployment, so that deleting it does not destroy the cluster.
+	// +optional
+	PreserveOnDelete bool `json:"preserveOnDelete,omitempty"`
+
+	// Adopt creates a ClusterDeployment for the cluster, and MachinePools for its MachineSets, once the cluster passes
+	// validation. When false, the cluster is only validated, and the report is in the status.
+	// +optional
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// ClusterAdoptionCheckResult is the result of a validation check of a cluster to adopt.
+// +kubebuilder:validation:Enum=Passed;Warning;Failed
+type ClusterAdoptionCheckResult string
+
+const (
+	// ClusterAdoptionCheckPassed means the check passed.
+	ClusterAdoptionCheckPassed ClusterAdoptionCheckResult = "Passed"
+	// ClusterAdoptionCheckWarning means the cluster can be adopted, with the limitation described by the check.
+	ClusterAdoptionCheckWarning ClusterAdoptionCheckResult = "Warning"
+	// ClusterAdoptionCheckFailed means the cluster cannot be adopted.
+	ClusterAdoptionCheckFailed ClusterAdoptionCheckResult = "Failed"
+)
+
+// ClusterAdoptionCheck is a validation check of a cluster to adopt.
+type ClusterAdoptionCheck struct {
+	// Name is the name of the check.
+	Name string `json:"name"`
+
+	// Result is the result of the check.
+	Result ClusterAdoptionCheckResult `json:"result"`
+
+	// Message is a human-readable message about the result of the check.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterAdoptionReport is what was found out about a cluster to adopt, and whether it can be adopted.
+type ClusterAdoptionReport struct {
+	// ClusterName is the name of the cluster, from its DNS configuration.
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// BaseDomain is the base domain of the cluster, from its DNS configuration.
+	// +optional
+	BaseDomain string `json:"baseDomain,omitempty"`
+
+	// ClusterID is the ID of the cluster, from its ClusterVersion.
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+
+	// InfraID is the infrastructure ID of the cluster, from its Infrastructure.
+	// +optional
+	InfraID string `json:"infraID,omitempty"`
+
+	// Platform is the platform of the cluster, from its Infrastructure.
+	// +optional
+	Platform string `json:"platform,omitempty"`
+
+	// Region is the region of the cluster.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Version is the OpenShift version of the cluster, from its ClusterVersion.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// MachinePools are the names of the MachinePools reflecting the MachineSets of the cluster.
+	// +optional
+	MachinePools []string `json:"machinePools,omitempty"`
+
+	// Checks are the validation checks of the cluster.
+	// +optional
+	Checks []ClusterAdoptionCheck `json:"checks,omitempty"`
+
+	// Valid is true when none of the checks failed.
+	Valid bool `json:"valid"`
+}
+
+// ClusterAdoptionStatus defines the observed state of ClusterAdoption.
+type ClusterAdoptionStatus struct {
+	// Report is the result of the last validation of the cluster.
+	// +optional
+	Report *ClusterAdoptionReport `json:"report,omitempty"`
+
+	// LastValidationTime is the last time the cluster was validated.
+	// +optional
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"`
+
+	// ClusterDeploymentRef references the ClusterDeployment created for the cluster once adopted.
+	// +optional
+	ClusterDeploymentRef *corev1.LocalObjectReference `json:"clusterDeploymentRef,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterAdoption validates a running cluster for adoption into Hive, and adopts it by creating a ClusterDeployment
+// with the same name, and MachinePools reflecting its MachineSets.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ClusterName",type="string",JSONPath=".status.report.clusterName"
+// +kubebuilder:printcolumn:name="Platform",type="string",JSONPath=".status.report.platform"
+// +kubebuilder:printcolumn:name="Valid",type="boolean",JSONPath=".status.report.valid"
+// +kubebuilder:printcolumn:name="Adopted",type="string",JSONPath=".status.clusterDeploymentRef.name"
+// +kubebuilder:resource:path=clusteradoptions,shortName=cadopt,scope=Namespaced
+type ClusterAdoption struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterAdoptionSpec   `json:"spec,omitempty"`
+	Status ClusterAdoptionStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterAdoptionList contains a list of ClusterAdoption
+type ClusterAdoptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAdoption `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterAdoption{}, &ClusterAdoptionList{})
+}
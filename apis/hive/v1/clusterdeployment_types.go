@@ -48,6 +48,10 @@ const (
 	// FinalizerArgoCDCluster is used on ClusterDeployments to ensure we clean up the ArgoCD cluster
 	// secret before cleaning up the API object.
 	FinalizerArgoCDCluster = "hive.openshift.io/argocd-cluster"
+
+	// FinalizerGitOpsRegistration is used on ClusterDeployments to ensure we clean up the objects registering the
+	// cluster with GitOps systems before cleaning up the API object.
+	FinalizerGitOpsRegistration = "hive.openshift.io/gitops-registration"
 )
 
 // ClusterPowerState is used to indicate whether a cluster is running or in a
@@ -128,6 +132,14 @@ type ClusterDeploymentSpec struct {
 	// +optional
 	PreserveOnDelete bool `json:"preserveOnDelete,omitempty"`
 
+	// DeleteAfter is the maximum lifetime of the ClusterDeployment, counted from its creation. Once it has elapsed,
+	// the ClusterDeployment is deleted, unless it is protected from deletion or PreserveOnDelete is set. It can be
+	// extended with the hive.openshift.io/delete-after-extension annotation. Takes precedence over the
+	// hive.openshift.io/delete-after annotation. The hive.openshift.io/delete-after annotation, unlike this field, is
+	// not blocked by PreserveOnDelete.
+	// +optional
+	DeleteAfter *metav1.Duration `json:"deleteAfter,omitempty"`
+
 	// ControlPlaneConfig contains additional configuration for the target cluster's control plane
 	// +optional
 	ControlPlaneConfig ControlPlaneConfigSpec `json:"controlPlaneConfig,omitempty"`
@@ -186,6 +198,12 @@ type ClusterDeploymentSpec struct {
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
 	HibernateAfter *metav1.Duration `json:"hibernateAfter,omitempty"`
 
+	// PowerSchedule keeps the cluster running and hibernating on a recurring schedule. PowerState is only changed
+	// at the scheduled transitions, so manual changes to PowerState hold until the next one. Clusters belonging to
+	// a ClusterPool are only affected once claimed.
+	// +optional
+	PowerSchedule *PowerSchedule `json:"powerSchedule,omitempty"`
+
 	// InstallAttemptsLimit is the maximum number of times Hive will attempt to install the cluster.
 	// +optional
 	InstallAttemptsLimit *int32 `json:"installAttemptsLimit,omitempty"`
@@ -258,6 +276,77 @@ type Provisioning struct {
 	// additional features of the installer.
 	// +optional
 	InstallerEnv []corev1.EnvVar `json:"installerEnv,omitempty"`
+
+	// CustomInstaller, if set, is run in place of `openshift-install create cluster`. This may be used to wrap the
+	// installer with additional steps, or to run an alternate install flow, without modifying Hive.
+	// +optional
+	CustomInstaller *CustomInstaller `json:"customInstaller,omitempty"`
+
+	// PreInstallHooks are Jobs run, in order, before the first install attempt. Provisioning does not start until
+	// all of them have succeeded.
+	// +optional
+	PreInstallHooks []InstallHook `json:"preInstallHooks,omitempty"`
+
+	// PostInstallHooks are Jobs run, in order, once the cluster has been provisioned. They are given the cluster's
+	// admin kubeconfig and metadata. The ClusterDeployment is not marked Installed until all of them have succeeded.
+	// +optional
+	PostInstallHooks []InstallHook `json:"postInstallHooks,omitempty"`
+}
+
+// InstallHook is a Job run by Hive before or after provisioning a cluster.
+//
+// Hive creates the Job in the ClusterDeployment's namespace, owned by the ClusterDeployment, from the template
+// referenced by JobTemplateRef. Every container of the Job is given the following environment variables:
+// CLUSTER_DEPLOYMENT_NAME, CLUSTER_DEPLOYMENT_NAMESPACE, CLUSTER_NAME and BASE_DOMAIN. Post-install hooks are
+// additionally given CLUSTER_ID, INFRA_ID and API_URL, and the cluster's admin kubeconfig mounted at
+// /etc/hive/cluster/kubeconfig, with KUBECONFIG pointing at it unless the container sets KUBECONFIG itself.
+//
+// The Job runs as the cluster-install-hook service account, which has no permissions on the hub cluster and whose
+// token is not mounted. The serviceAccountName, securityContext, host namespace and host port settings of the template
+// are dropped. Templates are rejected if they use a Secret not listed in SecretRefs, or a volume other than a secret,
+// projected, configMap, downwardAPI, emptyDir, persistentVolumeClaim or ephemeral volume.
+//
+// A hook that fails blocks provisioning (pre-install) or Installed (post-install) until its Job is deleted, at which
+// point Hive runs it again.
+type InstallHook struct {
+	// Name identifies the hook. It must be unique within its list of hooks and is used to name the Job.
+	Name string `json:"name"`
+
+	// JobTemplateRef is a reference to a ConfigMap in the ClusterDeployment's namespace containing a batch/v1 Job
+	// manifest under the "job.yaml" key. The name and namespace of the manifest are ignored.
+	JobTemplateRef corev1.LocalObjectReference `json:"jobTemplateRef"`
+
+	// SecretRefs are the Secrets in the ClusterDeployment's namespace which the Job may use, in secret or projected
+	// volumes, or in environment variables through secretKeyRef or envFrom.
+	// +optional
+	SecretRefs []corev1.LocalObjectReference `json:"secretRefs,omitempty"`
+}
+
+// CustomInstaller is a binary run in place of `openshift-install create cluster`.
+//
+// The binary is run from the install pod's asset directory, after openshift-install has generated the manifests and
+// ignition configs there. The path to openshift-install is passed in the OPENSHIFT_INSTALL_BINARY environment
+// variable so that wrappers can delegate to it. On success, the binary must leave metadata.json, auth/kubeconfig and
+// auth/kubeadmin-password in the asset directory; Hive publishes these as it would for openshift-install. A nonzero
+// exit code listed in RetryableExitCodes fails the install attempt but allows further attempts. Any other nonzero
+// exit code fails the install attempt and stops provisioning.
+type CustomInstaller struct {
+	// Image is the container image containing the binary. Defaults to the installer image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Path is the absolute path of the binary within the image. It must be clean, without `.` or `..` elements,
+	// repeated or trailing slashes.
+	Path string `json:"path"`
+
+	// Args are passed to the binary. Defaults to `create cluster`.
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// RetryableExitCodes are the nonzero exit codes with which the binary reports a failure that may succeed on a
+	// later install attempt.
+	// +optional
+	RetryableExitCodes []int32 `json:"retryableExitCodes,omitempty"`
 }
 
 // ClusterImageSetReference is a reference to a ClusterImageSet
@@ -361,6 +450,16 @@ type ClusterDeploymentStatus struct {
 	// InstalledTimestamp is the time we first detected that the cluster has been successfully installed.
 	InstalledTimestamp *metav1.Time `json:"installedTimestamp,omitempty"`
 
+	// ExpiryTimestamp is the time after which the ClusterDeployment is deleted, per spec.deleteAfter or the
+	// hive.openshift.io/delete-after annotation and the hive.openshift.io/delete-after-extension annotation.
+	// +optional
+	ExpiryTimestamp *metav1.Time `json:"expiryTimestamp,omitempty"`
+
+	// ExpiryWarningTimestamp is the last time a warning event was emitted because the ClusterDeployment was about
+	// to expire.
+	// +optional
+	ExpiryWarningTimestamp *metav1.Time `json:"expiryWarningTimestamp,omitempty"`
+
 	// PowerState indicates the powerstate of cluster
 	// +optional
 	PowerState ClusterPowerState `json:"powerState,omitempty"`
@@ -373,6 +472,20 @@ type ClusterDeploymentStatus struct {
 	// perform the installation.
 	// +optional
 	Platform *PlatformStatus `json:"platformStatus,omitempty"`
+
+	// CostEstimate is an estimate of what the cluster costs to run. It is only set when cost estimation is
+	// configured in HiveConfig.
+	// +optional
+	CostEstimate *ClusterCostEstimate `json:"costEstimate,omitempty"`
+
+	// ControlPlaneMachines is the observed state of the ControlPlaneMachineSet of the cluster. Only set when
+	// spec.controlPlaneConfig.machines is set.
+	// +optional
+	ControlPlaneMachines *ControlPlaneMachinesStatus `json:"controlPlaneMachines,omitempty"`
+
+	// PowerSchedule is the observed state of spec.powerSchedule.
+	// +optional
+	PowerSchedule *PowerScheduleStatus `json:"powerSchedule,omitempty"`
 }
 
 // ClusterDeploymentCondition contains details for the current condition of a cluster deployment
@@ -429,6 +542,10 @@ const (
 	// API URL override.
 	ActiveAPIURLOverrideCondition ClusterDeploymentConditionType = "ActiveAPIURLOverride"
 
+	// APIServerTunnelConnectedCondition indicates whether the agent on the remote cluster is connected to the API
+	// server tunnel endpoint of Hive. It is only set on ClusterDeployments with the API server tunnel enabled.
+	APIServerTunnelConnectedCondition ClusterDeploymentConditionType = "APIServerTunnelConnected"
+
 	// DNSNotReadyCondition indicates that the the DNSZone object created for the clusterDeployment
 	// (ie manageDNS==true) has not yet indicated that the DNS zone is successfully responding to queries.
 	DNSNotReadyCondition ClusterDeploymentConditionType = "DNSNotReady"
@@ -465,6 +582,20 @@ const (
 	// retries (without InstallAttemptsLimit changes or other hive configuration stopping further retries).
 	ProvisionStoppedCondition ClusterDeploymentConditionType = "ProvisionStopped"
 
+	// PreflightChecksFailedCondition is True when the cloud account lacks the quota or permissions needed to
+	// install the cluster. No install attempt is made while this condition is True.
+	PreflightChecksFailedCondition ClusterDeploymentConditionType = "PreflightChecksFailed"
+
+	// InstallProgressingCondition is True while the installer is running, with the installer's current phase as the
+	// reason. It mirrors the progress reported on the current ClusterProvision.
+	InstallProgressingCondition ClusterDeploymentConditionType = "InstallProgressing"
+
+	// PreInstallHooksFailedCondition is true when a pre-install hook Job has failed, and unknown while one is running.
+	PreInstallHooksFailedCondition ClusterDeploymentConditionType = "PreInstallHooksFailed"
+
+	// PostInstallHooksFailedCondition is true when a post-install hook Job has failed, and unknown while one is running.
+	PostInstallHooksFailedCondition ClusterDeploymentConditionType = "PostInstallHooksFailed"
+
 	// Provisioned is True when a cluster is installed; False while it is provisioning or deprovisioning.
 	// The Reason indicates where it is in that lifecycle.
 	ProvisionedCondition ClusterDeploymentConditionType = "Provisioned"
@@ -508,6 +639,7 @@ const (
 // All cluster deployment condition types that are not in this slice are assumed to have negative polarity
 var PositivePolarityClusterDeploymentConditions = []ClusterDeploymentConditionType{
 	ActiveAPIURLOverrideCondition,
+	APIServerTunnelConnectedCondition,
 	ClusterHibernatingCondition,
 	ClusterReadyCondition,
 	AWSPrivateLinkReadyClusterDeploymentCondition,
@@ -676,6 +808,9 @@ type PlatformStatus struct {
 
 	// GCP is the observed state on GCP
 	GCP *gcp.PlatformStatus `json:"gcp,omitempty"`
+
+	// Azure is the observed state on Azure.
+	Azure *azure.PlatformStatus `json:"azure,omitempty"`
 }
 
 // ClusterIngress contains the configurable pieces for any ClusterIngress objects
@@ -730,6 +865,36 @@ type ControlPlaneConfigSpec struct {
 	// This field can be used when repointing the APIServer's DNS is not viable option.
 	// +optional
 	APIServerIPOverride string `json:"apiServerIPOverride,omitempty"`
+
+	// APIServerProxy is the optional proxy through which Hive connects to the API server of the remote cluster.
+	// APIServerIPOverride is not used when a proxy is set.
+	// +optional
+	APIServerProxy *APIServerProxy `json:"apiServerProxy,omitempty"`
+
+	// APIServerTunnel, when true, makes Hive connect to the API server of the remote cluster through a reverse tunnel
+	// opened by an agent running on the remote cluster, for clusters Hive cannot reach directly. Hive syncs the agent
+	// to the remote cluster with a SyncSet; the tunnel endpoint must be configured in HiveConfig. APIServerProxy and
+	// APIServerIPOverride are not used when the tunnel is enabled.
+	// +optional
+	APIServerTunnel bool `json:"apiServerTunnel,omitempty"`
+
+	// Machines configures the control plane machines of the remote cluster through its ControlPlaneMachineSet.
+	// Only supported on AWS, Azure and GCP.
+	// +optional
+	Machines *ControlPlaneMachinesSpec `json:"machines,omitempty"`
+}
+
+// APIServerProxy is a proxy through which Hive connects to the API server of a remote cluster.
+type APIServerProxy struct {
+	// URL is the URL of the proxy. The http and https schemes select an HTTP CONNECT proxy, and the socks5 scheme a
+	// SOCKS5 proxy.
+	// +kubebuilder:validation:Pattern=`^(http|https|socks5)://`
+	URL string `json:"url"`
+
+	// CredentialsSecretRef references a secret in the namespace of the ClusterDeployment with the username and
+	// password keys used to authenticate to the proxy.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
 // ControlPlaneServingCertificateSpec specifies serving certificate settings for
@@ -763,7 +928,9 @@ type CertificateBundleSpec struct {
 	// +required
 	Name string `json:"name"`
 
-	// Generate indicates whether this bundle should have real certificates generated for it.
+	// Generate indicates whether this bundle should have real certificates generated for it. Certificates are
+	// issued for the domains of the control plane and ingresses using the bundle, by the certificate authority
+	// configured in HiveConfig spec.certificateIssuer, and renewed before they expire. Requires manageDNS.
 	// +optional
 	Generate bool `json:"generate,omitempty"`
 
@@ -782,6 +949,38 @@ type CertificateBundleStatus struct {
 
 	// Generated indicates whether the certificate bundle was generated
 	Generated bool `json:"generated"`
+
+	// NotAfter is the expiry time of the generated certificate.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// RenewalTime is the time after which the generated certificate is renewed.
+	// +optional
+	RenewalTime *metav1.Time `json:"renewalTime,omitempty"`
+
+	// Message is a human-readable message about the last failure to generate the certificate bundle.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// PendingOrder is the ACME order under way to issue a new certificate for the bundle.
+	// +optional
+	PendingOrder *CertificateOrder `json:"pendingOrder,omitempty"`
+}
+
+// CertificateOrder is an ACME order under way to issue a generated certificate. It is completed over several
+// reconciles: the TXT records of its DNS-01 challenges are published, the challenges are accepted once the records
+// had time to propagate, and the order is finalized once the certificate authority validated them.
+type CertificateOrder struct {
+	// URL of the order with the certificate authority.
+	URL string `json:"url"`
+
+	// AuthorizationURLs are the URLs of the authorizations of the order whose DNS-01 challenge records were
+	// published.
+	// +optional
+	AuthorizationURLs []string `json:"authorizationURLs,omitempty"`
+
+	// ChallengeRecordsPublishedTime is when the TXT records of the DNS-01 challenges were published.
+	ChallengeRecordsPublishedTime metav1.Time `json:"challengeRecordsPublishedTime"`
 }
 
 // RelocateStatus is the status of a cluster relocate.
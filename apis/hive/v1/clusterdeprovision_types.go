@@ -25,6 +25,13 @@ type ClusterDeprovisionSpec struct {
 
 	// Platform contains platform-specific configuration for a ClusterDeprovision
 	Platform ClusterDeprovisionPlatform `json:"platform,omitempty"`
+
+	// DryRun lists the cloud resources of the cluster, which the uninstaller would delete, in
+	// status.dryRunResources instead of deleting them. Dry runs do not need an owning ClusterDeployment being
+	// deleted. The ClusterDeprovision must not be named after a ClusterDeployment, as that name is used for the
+	// real deprovision of the cluster. Supported on AWS, GCP and Azure.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // ClusterDeprovisionStatus defines the observed state of ClusterDeprovision
@@ -35,6 +42,26 @@ type ClusterDeprovisionStatus struct {
 	// Conditions includes more detailed status for the cluster deprovision
 	// +optional
 	Conditions []ClusterDeprovisionCondition `json:"conditions,omitempty"`
+
+	// DryRunResources are the cloud resources the deprovision would delete. Only set for dry runs.
+	// +optional
+	DryRunResources *ClusterDeprovisionResources `json:"dryRunResources,omitempty"`
+
+	// LeakedResources are the cloud resources of the cluster still found once the deprovision completed. Checked on
+	// AWS, GCP and Azure.
+	// +optional
+	LeakedResources *ClusterDeprovisionResources `json:"leakedResources,omitempty"`
+}
+
+// ClusterDeprovisionResources is an inventory of the cloud resources of a cluster.
+type ClusterDeprovisionResources struct {
+	// Count is the number of resources found.
+	Count int `json:"count"`
+
+	// Resources are the identifiers of the resources found, which are ARNs on AWS, self links on GCP and resource
+	// IDs on Azure. Only the first 500 are listed.
+	// +optional
+	Resources []string `json:"resources,omitempty"`
 }
 
 // ClusterDeprovisionPlatform contains platform-specific configuration for the
@@ -159,6 +186,7 @@ type IBMClusterDeprovision struct {
 // +kubebuilder:printcolumn:name="InfraID",type="string",JSONPath=".spec.infraID"
 // +kubebuilder:printcolumn:name="ClusterID",type="string",JSONPath=".spec.clusterID"
 // +kubebuilder:printcolumn:name="Completed",type="boolean",JSONPath=".status.completed"
+// +kubebuilder:printcolumn:name="DryRun",type="boolean",JSONPath=".spec.dryRun",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=clusterdeprovisions,shortName=cdr,scope=Namespaced
 type ClusterDeprovision struct {
@@ -208,6 +236,14 @@ const (
 
 	// DeprovisionFailedClusterDeprovisionCondition is true when deprovision attempt failed
 	DeprovisionFailedClusterDeprovisionCondition ClusterDeprovisionConditionType = "DeprovisionFailed"
+
+	// ResourcesLeakedClusterDeprovisionCondition is true when cloud resources of the cluster remain once the
+	// deprovision completed. Checked on AWS, GCP and Azure.
+	ResourcesLeakedClusterDeprovisionCondition ClusterDeprovisionConditionType = "ResourcesLeaked"
+
+	// ResourceInventoryFailedClusterDeprovisionCondition is true when the cloud resources of the cluster
+	// could not be listed, for a dry run or to check for leaked resources, or when a dry run is not supported
+	ResourceInventoryFailedClusterDeprovisionCondition ClusterDeprovisionConditionType = "ResourceInventoryFailed"
 )
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -77,6 +77,11 @@ type ClusterPoolSpec struct {
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
 	HibernateAfter *metav1.Duration `json:"hibernateAfter,omitempty"`
 
+	// PowerSchedule will be applied to new ClusterDeployments created for the pool. It keeps clusters running and
+	// hibernating on a recurring schedule, once they are claimed.
+	// +optional
+	PowerSchedule *PowerSchedule `json:"powerSchedule,omitempty"`
+
 	// InstallAttemptsLimit is the maximum number of times Hive will attempt to install the cluster.
 	// +optional
 	InstallAttemptsLimit *int32 `json:"installAttemptsLimit,omitempty"`
@@ -178,6 +183,11 @@ type ClusterPoolStatus struct {
 	// Conditions includes more detailed status for the cluster pool
 	// +optional
 	Conditions []ClusterPoolCondition `json:"conditions,omitempty"`
+
+	// CostEstimate is the sum of the cost estimates of the pool's current clusters, claimed or not. It is only set
+	// when cost estimation is configured in HiveConfig.
+	// +optional
+	CostEstimate *CostSummary `json:"costEstimate,omitempty"`
 }
 
 // ClusterPoolCondition contains details for the current condition of a cluster pool
@@ -65,8 +65,60 @@ type ClusterProvisionStatus struct {
 	// Conditions includes more detailed status for the cluster provision
 	// +optional
 	Conditions []ClusterProvisionCondition `json:"conditions,omitempty"`
+
+	// Progress is the progress of the installer, as parsed from its log while the install is running.
+	// +optional
+	Progress *ClusterProvisionProgress `json:"progress,omitempty"`
+}
+
+// ClusterProvisionProgress describes how far the installer has progressed through installing the cluster.
+type ClusterProvisionProgress struct {
+	// Phase is the installer phase currently underway.
+	Phase ClusterProvisionPhase `json:"phase"`
+
+	// Percent is a rough estimate of how complete the install is, from 0 to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent int32 `json:"percent"`
+
+	// Phases lists the installer phases observed so far, in the order they were entered.
+	// +optional
+	Phases []ClusterProvisionPhaseStatus `json:"phases,omitempty"`
 }
 
+// ClusterProvisionPhaseStatus records when the installer entered and left a phase.
+type ClusterProvisionPhaseStatus struct {
+	// Name is the name of the phase.
+	Name ClusterProvisionPhase `json:"name"`
+
+	// StartedTime is when the installer entered the phase.
+	StartedTime metav1.Time `json:"startedTime"`
+
+	// FinishedTime is when the installer left the phase. Unset while the phase is underway.
+	// +optional
+	FinishedTime *metav1.Time `json:"finishedTime,omitempty"`
+}
+
+// ClusterProvisionPhase is a phase of the installer's progress, in the order the installer reaches them.
+// +kubebuilder:validation:Enum=InfrastructureProvisioning;WaitingForAPI;Bootstrapping;OperatorsProgressing;Complete
+type ClusterProvisionPhase string
+
+const (
+	// ClusterProvisionPhaseInfrastructureProvisioning indicates that the installer is creating cloud infrastructure.
+	ClusterProvisionPhaseInfrastructureProvisioning ClusterProvisionPhase = "InfrastructureProvisioning"
+	// ClusterProvisionPhaseWaitingForAPI indicates that the infrastructure is created and the installer is waiting
+	// for the bootstrap Kubernetes API to come up.
+	ClusterProvisionPhaseWaitingForAPI ClusterProvisionPhase = "WaitingForAPI"
+	// ClusterProvisionPhaseBootstrapping indicates that the API is up and the installer is waiting for
+	// bootstrapping to complete.
+	ClusterProvisionPhaseBootstrapping ClusterProvisionPhase = "Bootstrapping"
+	// ClusterProvisionPhaseOperatorsProgressing indicates that bootstrapping is complete and the installer is waiting
+	// for the cluster operators to roll out.
+	ClusterProvisionPhaseOperatorsProgressing ClusterProvisionPhase = "OperatorsProgressing"
+	// ClusterProvisionPhaseComplete indicates that the installer has reported the install complete.
+	ClusterProvisionPhaseComplete ClusterProvisionPhase = "Complete"
+)
+
 // ClusterProvisionStage is the stage of provisioning.
 type ClusterProvisionStage string
 
@@ -131,6 +183,16 @@ const (
 	InstallPodStuckCondition ClusterProvisionConditionType = "InstallPodStuck"
 )
 
+// ClusterProvisionFailedCondition reasons set by the install manager when a custom installer fails.
+const (
+	// CustomInstallerRetryableFailureReason is used when the custom installer exits with one of its retryable exit
+	// codes.
+	CustomInstallerRetryableFailureReason = "CustomInstallerRetryableFailure"
+	// CustomInstallerFatalFailureReason is used when the custom installer exits with any other nonzero exit code.
+	// No further install attempts are made.
+	CustomInstallerFatalFailureReason = "CustomInstallerFatalFailure"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
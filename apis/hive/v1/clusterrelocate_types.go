@@ -12,6 +12,47 @@ type ClusterRelocateSpec struct {
 
 	// ClusterDeploymentSelector is a LabelSelector indicating which clusters will be relocated.
 	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector"`
+
+	// AdditionalResources selects resources to relocate from the namespace of each ClusterDeployment, on top of the
+	// Secrets, ConfigMaps, MachinePools, SyncSets, SyncIdentityProviders and DNSZone that are always relocated.
+	// Kinds outside of the hive.openshift.io API group need extra RBAC: the list verb for the hive-controllers service
+	// account in this cluster, and the get, create and delete verbs for the destination kubeconfig. Without it, the
+	// RelocationFailed condition of the ClusterDeployment has the AdditionalResourcesForbidden reason.
+	// +optional
+	AdditionalResources []RelocateResourceSelector `json:"additionalResources,omitempty"`
+
+	// Verification, when set, keeps each ClusterDeployment on this Hive instance after it has been copied until the
+	// destination Hive instance has taken ownership of the cluster. The relocation is rolled back when the destination
+	// does not take ownership in time.
+	// +optional
+	Verification *RelocateVerification `json:"verification,omitempty"`
+
+	// Rollback rolls back the relocations that have not completed, removing the ClusterDeployments from the
+	// destination Hive instance and handing the clusters back to this one. No new relocations start while it is set.
+	// +optional
+	Rollback bool `json:"rollback,omitempty"`
+}
+
+// RelocateResourceSelector selects resources of a kind to relocate along with a ClusterDeployment.
+type RelocateResourceSelector struct {
+	// APIVersion is the API version of the resources, for example "v1" or "example.com/v1alpha1".
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the resources.
+	Kind string `json:"kind"`
+
+	// Selector is a LabelSelector indicating which resources of the kind in the namespace of the ClusterDeployment
+	// will be relocated. All of them are relocated when empty.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RelocateVerification configures the verification of relocated clusters.
+type RelocateVerification struct {
+	// Timeout is how long to wait for the destination Hive instance to take ownership of a cluster before rolling
+	// its relocation back. Defaults to 1h.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
 }
 
 // KubeconfigSecretReference is a reference to a secret containing the kubeconfig for a remote cluster.
@@ -22,8 +63,50 @@ type KubeconfigSecretReference struct {
 	Namespace string `json:"namespace"`
 }
 
+// ClusterRelocationPhase is the relocation phase of a cluster.
+// +kubebuilder:validation:Enum=Copying;Verifying;Completed;Failed;RolledBack
+type ClusterRelocationPhase string
+
+const (
+	// ClusterRelocationPhaseCopying means the resources of the cluster are being copied to the destination.
+	ClusterRelocationPhaseCopying ClusterRelocationPhase = "Copying"
+	// ClusterRelocationPhaseVerifying means the resources of the cluster have been copied, and the destination has
+	// not taken ownership of the cluster yet.
+	ClusterRelocationPhaseVerifying ClusterRelocationPhase = "Verifying"
+	// ClusterRelocationPhaseCompleted means the cluster has been relocated and removed from this Hive instance.
+	ClusterRelocationPhaseCompleted ClusterRelocationPhase = "Completed"
+	// ClusterRelocationPhaseFailed means the last attempt at relocating the cluster failed. It is retried.
+	ClusterRelocationPhaseFailed ClusterRelocationPhase = "Failed"
+	// ClusterRelocationPhaseRolledBack means the relocation of the cluster was rolled back. It is not retried.
+	ClusterRelocationPhaseRolledBack ClusterRelocationPhase = "RolledBack"
+)
+
+// ClusterRelocationStatus is the relocation progress of a single cluster.
+type ClusterRelocationStatus struct {
+	// Namespace is the namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClusterDeployment.
+	Name string `json:"name"`
+
+	// Phase is the relocation phase of the cluster.
+	Phase ClusterRelocationPhase `json:"phase"`
+
+	// LastTransitionTime is the last time the phase changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Message is a human-readable message about the state of the relocation of the cluster.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ClusterRelocateStatus defines the observed state of ClusterRelocate.
-type ClusterRelocateStatus struct{}
+type ClusterRelocateStatus struct {
+	// Clusters is the relocation progress of each selected cluster.
+	// +optional
+	Clusters []ClusterRelocationStatus `json:"clusters,omitempty"`
+}
 
 // +genclient:nonNamespaced
 // +genclient
@@ -18,6 +18,34 @@ type ClusterStateStatus struct {
 	// ClusterOperators contains the state for every cluster operator in the
 	// target cluster
 	ClusterOperators []ClusterOperatorState `json:"clusterOperators,omitempty"`
+
+	// History contains the most recent transitions of the status of the conditions of the cluster operators in the
+	// target cluster, oldest first. It holds at most 100 transitions, shared by all the conditions. Once it is full,
+	// the oldest transition of the condition with the most transitions is dropped for each new one, so that a flapping
+	// condition does not evict the history of the others.
+	// A condition first seen in a status other than its steady one (Available and Upgradeable True, Degraded and
+	// Progressing False) is recorded as a transition from its steady status.
+	// +optional
+	History []ClusterOperatorConditionTransition `json:"history,omitempty"`
+}
+
+// ClusterOperatorConditionTransition is a transition of the status of a condition of a cluster operator
+type ClusterOperatorConditionTransition struct {
+	// Operator is the name of the cluster operator
+	Operator string `json:"operator"`
+
+	// Type is the type of the condition
+	Type configv1.ClusterStatusConditionType `json:"type"`
+
+	// Status is the status of the condition after the transition
+	Status configv1.ConditionStatus `json:"status"`
+
+	// Reason is the reason of the condition after the transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// LastTransitionTime is the time of the transition, as reported by the cluster operator
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
 }
 
 // ClusterOperatorState summarizes the status of a single cluster operator
@@ -0,0 +1,167 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterUpgradePlanSpec defines the upgrade of a fleet of clusters to an OpenShift release.
+type ClusterUpgradePlanSpec struct {
+	// ImageSetRef is a reference to the ClusterImageSet of the release to upgrade the clusters to.
+	ImageSetRef ClusterImageSetReference `json:"imageSetRef"`
+
+	// ClusterDeploymentSelector is a LabelSelector indicating which clusters will be upgraded.
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector"`
+
+	// MaxConcurrent is the number of clusters upgrading at the same time. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+
+	// MaintenanceWindows are the time windows during which cluster upgrades may start. Upgrades may start at any time
+	// when empty. Upgrades in progress continue after a window ends.
+	// +optional
+	MaintenanceWindows []ScheduleWindow `json:"maintenanceWindows,omitempty"`
+
+	// TimeZone is the IANA time zone of the maintenance windows, for example "Europe/Paris". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Force is set on the desired update of the ClusterVersion of the clusters, to upgrade them even when the release
+	// fails verification or upgrade preconditions.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// Paused stops cluster upgrades from starting. Upgrades in progress are not interrupted.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// ClusterUpgradePhase is the upgrade phase of a cluster.
+// +kubebuilder:validation:Enum=Pending;Upgrading;Completed;Failed
+type ClusterUpgradePhase string
+
+const (
+	// ClusterUpgradePhasePending means the upgrade of the cluster has not started yet.
+	ClusterUpgradePhasePending ClusterUpgradePhase = "Pending"
+	// ClusterUpgradePhaseUpgrading means the cluster is upgrading.
+	ClusterUpgradePhaseUpgrading ClusterUpgradePhase = "Upgrading"
+	// ClusterUpgradePhaseCompleted means the cluster runs the release of the plan.
+	ClusterUpgradePhaseCompleted ClusterUpgradePhase = "Completed"
+	// ClusterUpgradePhaseFailed means the ClusterVersion of the cluster reports that the upgrade is failing.
+	ClusterUpgradePhaseFailed ClusterUpgradePhase = "Failed"
+)
+
+// ClusterUpgradeStatus is the upgrade progress of a single cluster.
+type ClusterUpgradeStatus struct {
+	// Namespace is the namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClusterDeployment.
+	Name string `json:"name"`
+
+	// Phase is the upgrade phase of the cluster.
+	Phase ClusterUpgradePhase `json:"phase"`
+
+	// Version is the version reported by the ClusterVersion of the cluster.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// StartTime is the time the upgrade of the cluster was requested.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the upgrade of the cluster completed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message is a human-readable message about the state of the upgrade of the cluster.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterUpgradePlanStatus defines the observed state of ClusterUpgradePlan.
+type ClusterUpgradePlanStatus struct {
+	// Clusters is the upgrade progress of each selected cluster.
+	// +optional
+	Clusters []ClusterUpgradeStatus `json:"clusters,omitempty"`
+
+	// Conditions includes more detailed status for the upgrade plan.
+	// +optional
+	Conditions []ClusterUpgradePlanCondition `json:"conditions,omitempty"`
+}
+
+// ClusterUpgradePlanCondition contains details for the current condition of a ClusterUpgradePlan.
+type ClusterUpgradePlanCondition struct {
+	// Type is the type of the condition.
+	Type ClusterUpgradePlanConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time we probed the condition.
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterUpgradePlanConditionType is a valid value for ClusterUpgradePlanCondition.Type
+type ClusterUpgradePlanConditionType string
+
+// ConditionType satisfies the conditions.Condition interface
+func (c ClusterUpgradePlanCondition) ConditionType() ConditionType {
+	return c.Type
+}
+
+// String satisfies the conditions.ConditionType interface
+func (t ClusterUpgradePlanConditionType) String() string {
+	return string(t)
+}
+
+const (
+	// ClusterUpgradePlanPausedCondition is true when no cluster upgrade may start, because the plan is paused, an
+	// upgrade failed, an upgraded cluster has failing ClusterOperators, or outside of the maintenance windows.
+	ClusterUpgradePlanPausedCondition ClusterUpgradePlanConditionType = "Paused"
+
+	// ClusterUpgradePlanCompletedCondition is true when all the selected clusters have been upgraded.
+	ClusterUpgradePlanCompletedCondition ClusterUpgradePlanConditionType = "Completed"
+)
+
+// +genclient:nonNamespaced
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePlan upgrades the selected ClusterDeployments to an OpenShift release, in batches and during
+// maintenance windows.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ImageSet",type="string",JSONPath=".spec.imageSetRef.name"
+// +kubebuilder:printcolumn:name="Completed",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].status"
+// +kubebuilder:printcolumn:name="Paused",type="string",JSONPath=".status.conditions[?(@.type=='Paused')].status"
+// +kubebuilder:resource:path=clusterupgradeplans,scope=Cluster
+type ClusterUpgradePlan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterUpgradePlanSpec   `json:"spec,omitempty"`
+	Status ClusterUpgradePlanStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePlanList contains a list of ClusterUpgradePlan
+type ClusterUpgradePlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterUpgradePlan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterUpgradePlan{}, &ClusterUpgradePlanList{})
+}
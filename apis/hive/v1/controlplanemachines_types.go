@@ -0,0 +1,69 @@
+package v1
+
+// ControlPlaneMachinesSpec configures the control plane machines of a cluster through its ControlPlaneMachineSet.
+type ControlPlaneMachinesSpec struct {
+	// InstanceType is the instance type of the control plane machines: the instance type on AWS, the VM size on
+	// Azure and the machine type on GCP. Changing it replaces the control plane machines according to the update
+	// strategy of the ControlPlaneMachineSet. When empty, the instance type is left unchanged.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// UpdateStrategy is the strategy used by the ControlPlaneMachineSet to replace outdated control plane machines.
+	// RollingUpdate replaces machines one at a time, creating the new machine first. OnDelete only replaces
+	// machines once they are deleted. When empty, the update strategy is left unchanged.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +optional
+	UpdateStrategy ControlPlaneUpdateStrategyType `json:"updateStrategy,omitempty"`
+
+	// Activate activates the ControlPlaneMachineSet when it is inactive, which is the case on clusters installed
+	// before control plane machine sets were introduced. Changes only take effect on active ControlPlaneMachineSets.
+	// ControlPlaneMachineSets cannot be deactivated.
+	// +optional
+	Activate bool `json:"activate,omitempty"`
+}
+
+// ControlPlaneUpdateStrategyType is the strategy used to replace outdated control plane machines.
+type ControlPlaneUpdateStrategyType string
+
+const (
+	// ControlPlaneUpdateStrategyRollingUpdate replaces control plane machines one at a time, creating each new
+	// machine before removing the machine it replaces.
+	ControlPlaneUpdateStrategyRollingUpdate ControlPlaneUpdateStrategyType = "RollingUpdate"
+	// ControlPlaneUpdateStrategyOnDelete only replaces control plane machines once they are deleted.
+	ControlPlaneUpdateStrategyOnDelete ControlPlaneUpdateStrategyType = "OnDelete"
+)
+
+// ControlPlaneMachinesStatus is the observed state of the ControlPlaneMachineSet of a cluster.
+type ControlPlaneMachinesStatus struct {
+	// State is the state of the ControlPlaneMachineSet, Active or Inactive.
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// InstanceType is the instance type in the template of the ControlPlaneMachineSet.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// UpdateStrategy is the update strategy of the ControlPlaneMachineSet.
+	// +optional
+	UpdateStrategy ControlPlaneUpdateStrategyType `json:"updateStrategy,omitempty"`
+
+	// Replicas is the number of control plane machines.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// ReadyReplicas is the number of control plane machines that are ready.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// UpdatedReplicas is the number of control plane machines matching the template of the ControlPlaneMachineSet.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// UnavailableReplicas is the number of control plane machines that are unavailable.
+	// +optional
+	UnavailableReplicas int32 `json:"unavailableReplicas,omitempty"`
+
+	// Message explains why the ControlPlaneMachineSet cannot be managed, or why it is degraded.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CostSummary is an estimate of what one or more clusters cost to run, based on the hourly prices of their machines
+// in the price table configured in HiveConfig. Costs are decimal strings in the price table's currency.
+type CostSummary struct {
+	// Currency is the currency of the costs, as given by the price table.
+	// +optional
+	Currency string `json:"currency,omitempty"`
+
+	// HourlyCost is the estimated cost per hour of running the machines currently configured.
+	HourlyCost string `json:"hourlyCost"`
+
+	// TotalCost is the estimated cost accrued while running since Hive began estimating.
+	TotalCost string `json:"totalCost"`
+}
+
+// ClusterCostEstimate is an estimate of what a cluster costs to run. Time spent hibernating accrues no cost.
+type ClusterCostEstimate struct {
+	CostSummary `json:",inline"`
+
+	// RunningSeconds is the time the cluster has spent running since Hive began estimating.
+	RunningSeconds int64 `json:"runningSeconds"`
+
+	// HibernatingSeconds is the time the cluster has spent hibernating since Hive began estimating.
+	HibernatingSeconds int64 `json:"hibernatingSeconds"`
+
+	// UnpricedInstanceTypes lists the cluster's instance types missing from the price table, which are left out of
+	// the estimate.
+	// +optional
+	UnpricedInstanceTypes []string `json:"unpricedInstanceTypes,omitempty"`
+
+	// LastUpdatedTime is the time up to which the estimate has accrued.
+	LastUpdatedTime metav1.Time `json:"lastUpdatedTime"`
+}
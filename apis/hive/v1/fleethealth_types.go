@@ -0,0 +1,162 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FleetHealthSpec defines the clusters summarized by a FleetHealth.
+type FleetHealthSpec struct {
+	// ClusterDeploymentSelector selects the ClusterDeployments summarized. An empty selector selects all the
+	// ClusterDeployments.
+	// +optional
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// NamespaceSelector selects the namespaces of the ClusterDeployments summarized. An empty selector selects all the
+	// namespaces.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// LabelKeys are the keys of the labels of the ClusterDeployments copied to the clusters listed in the status, so
+	// that the lists can be filtered by these labels.
+	// +optional
+	LabelKeys []string `json:"labelKeys,omitempty"`
+
+	// MaxClustersPerCategory is the maximum number of clusters listed in each category of the status. The count of a
+	// category always includes all its clusters. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxClustersPerCategory *int32 `json:"maxClustersPerCategory,omitempty"`
+
+	// PowerStateTimeout is how long a cluster may take to hibernate or resume before it is considered stuck.
+	// Defaults to 1h.
+	// +optional
+	PowerStateTimeout *metav1.Duration `json:"powerStateTimeout,omitempty"`
+}
+
+// FleetHealthCategory is a reason for which clusters are unhealthy.
+// +kubebuilder:validation:Enum=Unreachable;SyncFailed;OperatorsDegraded;PowerStateStuck;MachinePoolErrors
+type FleetHealthCategory string
+
+const (
+	// FleetHealthUnreachable is the category of the clusters whose API cannot be reached.
+	FleetHealthUnreachable FleetHealthCategory = "Unreachable"
+	// FleetHealthSyncFailed is the category of the clusters with SyncSets or SelectorSyncSets failing to apply.
+	FleetHealthSyncFailed FleetHealthCategory = "SyncFailed"
+	// FleetHealthOperatorsDegraded is the category of the clusters with cluster operators degraded or unavailable.
+	FleetHealthOperatorsDegraded FleetHealthCategory = "OperatorsDegraded"
+	// FleetHealthPowerStateStuck is the category of the clusters that have not reached the requested power state
+	// within the power state timeout.
+	FleetHealthPowerStateStuck FleetHealthCategory = "PowerStateStuck"
+	// FleetHealthMachinePoolErrors is the category of the clusters with MachinePools reporting errors.
+	FleetHealthMachinePoolErrors FleetHealthCategory = "MachinePoolErrors"
+)
+
+// FleetHealthResourceReference references a resource explaining why a cluster is unhealthy.
+type FleetHealthResourceReference struct {
+	// APIVersion is the API version of the resource.
+	APIVersion string `json:"apiVersion"`
+	// Kind is the kind of the resource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource. It is empty for cluster-scoped resources.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+}
+
+// FleetHealthCluster is an unhealthy cluster.
+type FleetHealthCluster struct {
+	// Namespace is the namespace of the ClusterDeployment.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the ClusterDeployment.
+	Name string `json:"name"`
+
+	// Labels are the labels of the ClusterDeployment whose keys are in the label keys of the spec.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Since is when the cluster became unhealthy for this category, when known.
+	// +optional
+	Since *metav1.Time `json:"since,omitempty"`
+
+	// Message explains why the cluster is unhealthy.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Resources reference the resources explaining why the cluster is unhealthy.
+	// +optional
+	Resources []FleetHealthResourceReference `json:"resources,omitempty"`
+}
+
+// FleetHealthCategoryStatus lists the clusters unhealthy for a category.
+type FleetHealthCategoryStatus struct {
+	// Category is the category of the unhealthy clusters.
+	Category FleetHealthCategory `json:"category"`
+
+	// Count is the number of clusters unhealthy for the category.
+	Count int32 `json:"count"`
+
+	// Clusters are the clusters unhealthy for the category, sorted by namespace and name, up to the maximum number of
+	// clusters per category.
+	// +optional
+	Clusters []FleetHealthCluster `json:"clusters,omitempty"`
+}
+
+// FleetHealthStatus defines the observed health of the clusters.
+type FleetHealthStatus struct {
+	// Clusters is the number of ClusterDeployments selected.
+	// +optional
+	Clusters int32 `json:"clusters,omitempty"`
+
+	// HealthyClusters is the number of ClusterDeployments selected which are not unhealthy for any category. Clusters
+	// that are not installed yet are neither healthy nor unhealthy.
+	// +optional
+	HealthyClusters int32 `json:"healthyClusters,omitempty"`
+
+	// UnhealthyClusters is the number of ClusterDeployments selected which are unhealthy for at least one category.
+	// +optional
+	UnhealthyClusters int32 `json:"unhealthyClusters,omitempty"`
+
+	// Categories are the unhealthy clusters, by category. All the categories are listed, even when empty.
+	// +optional
+	Categories []FleetHealthCategoryStatus `json:"categories,omitempty"`
+
+	// LastUpdated is the last time the status was computed.
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +genclient:nonNamespaced
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FleetHealth summarizes the health of the ClusterDeployments it selects: which clusters are unreachable, failing to
+// sync, have degraded operators, are stuck hibernating or resuming, or have MachinePool errors.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Clusters",type="integer",JSONPath=".status.clusters"
+// +kubebuilder:printcolumn:name="Healthy",type="integer",JSONPath=".status.healthyClusters"
+// +kubebuilder:printcolumn:name="Unhealthy",type="integer",JSONPath=".status.unhealthyClusters"
+// +kubebuilder:printcolumn:name="LastUpdated",type="date",JSONPath=".status.lastUpdated"
+// +kubebuilder:resource:path=fleethealths,scope=Cluster
+type FleetHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FleetHealthSpec   `json:"spec,omitempty"`
+	Status FleetHealthStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FleetHealthList contains a list of FleetHealth
+type FleetHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FleetHealth `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&FleetHealth{}, &FleetHealthList{})
+}
@@ -49,8 +49,30 @@ type MachinePool struct {
 	// tag key and tag value resource. Consumer is responsible for using this only for spokes
 	// where custom tags are supported.
 	UserTags []installerplatform.UserTag `json:"userTags,omitempty"`
+
+	// ProvisioningModel is the provisioning model of the instances. "Preemptible" instances are charged a
+	// discounted price, with no maximum price to set, can be reclaimed by GCP at any time, and run for at most 24
+	// hours. Reclaimed instances are deleted and replaced by the MachineSet once capacity is available again.
+	// Preemptible instances cannot be live migrated, so OnHostMaintenance must not be "Migrate". GCP Spot VMs are
+	// not supported, as the Machine API cannot create them yet.
+	// If omitted, defaults to "Standard".
+	// +kubebuilder:validation:Enum=Standard;Preemptible
+	// +optional
+	ProvisioningModel ProvisioningModel `json:"provisioningModel,omitempty"`
 }
 
+// ProvisioningModel is the provisioning model of GCP instances.
+type ProvisioningModel string
+
+const (
+	// ProvisioningModelStandard runs instances on regular capacity.
+	ProvisioningModelStandard ProvisioningModel = "Standard"
+
+	// ProvisioningModelPreemptible runs instances as preemptible VMs, on discounted capacity GCP can reclaim at any
+	// time and after 24 hours.
+	ProvisioningModelPreemptible ProvisioningModel = "Preemptible"
+)
+
 // OSDisk defines the disk for machines on GCP.
 type OSDisk struct {
 	// DiskType defines the type of disk.
@@ -88,6 +88,29 @@ type HiveConfigSpec struct {
 	// +optional
 	DeleteProtection DeleteProtectionType `json:"deleteProtection,omitempty"`
 
+	// PreflightChecks can be set to "enabled" to have Hive check the cloud account's quotas and permissions
+	// against the install-config before launching each install attempt. A ClusterDeployment failing these checks
+	// gets the PreflightChecksFailed condition and is rechecked periodically; no install attempt is consumed.
+	// Individual ClusterDeployments can opt out with the "hive.openshift.io/skip-preflight-checks" annotation.
+	// +kubebuilder:validation:Enum=enabled
+	// +optional
+	PreflightChecks PreflightChecksType `json:"preflightChecks,omitempty"`
+
+	// CostEstimation enables estimating what each ClusterDeployment costs to run. The estimates are published on
+	// ClusterDeployment and ClusterPool status and as metrics.
+	// +optional
+	CostEstimation *CostEstimationConfig `json:"costEstimation,omitempty"`
+
+	// CertificateIssuer enables issuing the certificate bundles of ClusterDeployments that set generate, through an
+	// ACME certificate authority.
+	// +optional
+	CertificateIssuer *CertificateIssuerConfig `json:"certificateIssuer,omitempty"`
+
+	// APIServerTunnel enables the endpoint to which the agents of ClusterDeployments with the API server tunnel
+	// enabled connect.
+	// +optional
+	APIServerTunnel *APIServerTunnelConfig `json:"apiServerTunnel,omitempty"`
+
 	// DisabledControllers allows selectively disabling Hive controllers by name.
 	// The name of an individual controller matches the name of the controller as seen in the Hive logging output.
 	DisabledControllers []string `json:"disabledControllers,omitempty"`
@@ -151,6 +174,12 @@ type HiveConfigSpec struct {
 	// clusters to ArgoCD, and remove them when they are deprovisioned.
 	ArgoCD ArgoCDConfig `json:"argoCDConfig,omitempty"`
 
+	// GitOpsRegistrations lists the GitOps systems, such as Flux, installed clusters are registered with. For each
+	// installed cluster, Hive creates the objects rendered from the template of each registration, and deletes them
+	// when the cluster is deprovisioned.
+	// +optional
+	GitOpsRegistrations []GitOpsRegistration `json:"gitOpsRegistrations,omitempty"`
+
 	FeatureGates *FeatureGateSelection `json:"featureGates,omitempty"`
 
 	// ExportMetrics has been disabled and has no effect. If upgrading from a version where it was
@@ -168,6 +197,72 @@ type HiveConfigSpec struct {
 	MetricsConfig *metricsconfig.MetricsConfig `json:"metricsConfig,omitempty"`
 }
 
+// CostEstimationConfig contains the configuration for estimating cluster costs.
+type CostEstimationConfig struct {
+	// PriceTableConfigMapRef is a reference to a ConfigMap in the TargetNamespace holding the hourly price of each
+	// instance type, per platform, as YAML under the "prices.yaml" key. For example:
+	//
+	//	currency: USD
+	//	prices:
+	//	  aws:
+	//	    m6i.xlarge: 0.192
+	//	  gcp:
+	//	    n2-standard-4: 0.194
+	//
+	// Platforms are named as in the hive.openshift.io/cluster-platform label.
+	PriceTableConfigMapRef corev1.LocalObjectReference `json:"priceTableConfigMapRef"`
+}
+
+// CertificateIssuerConfig contains the configuration for issuing certificates through an ACME certificate authority.
+// Control of the domains is proven with DNS-01 challenges in the DNSZones of ClusterDeployments with manageDNS.
+type CertificateIssuerConfig struct {
+	// DirectoryURL is the URL of the ACME directory of the certificate authority, for example
+	// https://acme-v02.api.letsencrypt.org/directory.
+	DirectoryURL string `json:"directoryURL"`
+
+	// Email is the contact email address of the ACME account.
+	// +optional
+	Email string `json:"email,omitempty"`
+
+	// AccountKeySecretRef is a reference to a secret in the TargetNamespace holding the PEM encoded private key of the
+	// ACME account under the "tls.key" key. The secret is created with a new key when it does not exist.
+	AccountKeySecretRef corev1.LocalObjectReference `json:"accountKeySecretRef"`
+
+	// CACertificatesSecretRef is a reference to a secret in the TargetNamespace holding PEM encoded certificate
+	// authorities under the "ca.crt" key, trusted in addition to the system ones when connecting to the ACME
+	// directory. This is useful with test certificate authorities such as Pebble.
+	// +optional
+	CACertificatesSecretRef *corev1.LocalObjectReference `json:"caCertificatesSecretRef,omitempty"`
+
+	// RenewBefore is how long before they expire certificates are renewed. Defaults to 720h (30 days).
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// APIServerTunnelConfig contains the configuration of the API server tunnel endpoint. The endpoint is served by
+// hive-controllers on the tunnel port of the hive-controllers Service, which must be exposed, for instance with a
+// Route or a LoadBalancer Service, so that the agents on the remote clusters can reach it. The tunnel-proxy port
+// must not be exposed outside of the hub cluster.
+type APIServerTunnelConfig struct {
+	// URL is the URL at which the agents on the remote clusters reach the tunnel endpoint, for example
+	// https://hive-tunnel.apps.hub.example.com.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// ServingCertificateSecretRef is a reference to a secret in the TargetNamespace holding the PEM encoded
+	// certificate and key served by the tunnel endpoint under the "tls.crt" and "tls.key" keys. PEM encoded
+	// certificate authorities under the "ca.crt" key, if any, are trusted by the agents in addition to the system
+	// ones. It is required unless TLSTerminatedExternally is set.
+	// +optional
+	ServingCertificateSecretRef *corev1.LocalObjectReference `json:"servingCertificateSecretRef,omitempty"`
+
+	// TLSTerminatedExternally declares that TLS is terminated in front of the tunnel endpoint, for instance by an
+	// edge Route, so that the endpoint may serve plain HTTP when ServingCertificateSecretRef is not set. The agents
+	// send their tokens to the endpoint, so they must never reach it over plain HTTP.
+	// +optional
+	TLSTerminatedExternally bool `json:"tlsTerminatedExternally,omitempty"`
+}
+
 // ReleaseImageVerificationConfigMapReference is a reference to the ConfigMap that
 // will be used to verify release images.
 type ReleaseImageVerificationConfigMapReference struct {
@@ -177,11 +272,32 @@ type ReleaseImageVerificationConfigMapReference struct {
 	Name string `json:"name"`
 }
 
+// PrivateLinkHubPlatform is the cloud platform of the privatelink hub.
+// +kubebuilder:validation:Enum=AWS;Azure
+type PrivateLinkHubPlatform string
+
+const (
+	// PrivateLinkHubPlatformAWS manages the DNS records of the clusters in Route53 private hosted zones.
+	PrivateLinkHubPlatformAWS PrivateLinkHubPlatform = "AWS"
+	// PrivateLinkHubPlatformAzure manages the DNS records of the clusters in Azure private DNS zones.
+	PrivateLinkHubPlatformAzure PrivateLinkHubPlatform = "Azure"
+)
+
 // PrivateLinkConfig defines the configuration for the privatelink controller.
 type PrivateLinkConfig struct {
+	// HubPlatform is the cloud platform of the hub, where the DNS records resolving the API of the clusters to
+	// their private endpoints are created. The Azure hub only supports Azure clusters.
+	// Defaults to AWS.
+	// +optional
+	HubPlatform PrivateLinkHubPlatform `json:"hubPlatform,omitempty"`
+
 	// GCP is the configuration for GCP hub and link resources.
 	// +optional
 	GCP *GCPPrivateServiceConnectConfig `json:"gcp,omitempty"`
+
+	// Azure is the configuration for Azure hub and link resources.
+	// +optional
+	Azure *AzurePrivateLinkConfig `json:"azure,omitempty"`
 }
 
 // AWSPrivateLinkConfig defines the configuration for the aws-private-link controller.
@@ -293,6 +409,40 @@ type GCPPrivateServiceConnectConfig struct {
 	EndpointVPCInventory []GCPPrivateServiceConnectInventory `json:"endpointVPCInventory,omitempty"`
 }
 
+// AzurePrivateLinkConfig defines the Azure Private Link config for the private-link controller.
+type AzurePrivateLinkConfig struct {
+	// CredentialsSecretRef references a secret in the TargetNamespace that will be used to authenticate with
+	// Azure for creating the private endpoints and private DNS zones in the hub subscription.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+
+	// CloudName is the name of the Azure cloud environment of the hub subscription.
+	// If empty, the value is equal to "AzurePublicCloud".
+	// +optional
+	CloudName azure.CloudEnvironment `json:"cloudName,omitempty"`
+
+	// ResourceGroupName is the resource group of the hub subscription in which the private endpoints and
+	// private DNS zones are created.
+	ResourceGroupName string `json:"resourceGroupName"`
+
+	// EndpointSubnetInventory is a list of subnets in various Azure regions. The controller uses this list to
+	// choose a subnet for creating private endpoints. Since private endpoints must be in the same region as
+	// the ClusterDeployment, we must have subnets in that region to be able to setup Private Link.
+	// +optional
+	EndpointSubnetInventory []AzurePrivateLinkSubnet `json:"endpointSubnetInventory,omitempty"`
+
+	// AssociatedVirtualNetworks is a list of resource IDs of virtual networks that must be able to resolve the
+	// API of clusters using Private Link. The private DNS zone of each cluster is linked to these virtual
+	// networks, as well as to the virtual network of its private endpoint.
+	// +optional
+	AssociatedVirtualNetworks []string `json:"associatedVirtualNetworks,omitempty"`
+}
+
+// AzurePrivateLinkSubnet is a subnet, by resource ID, and the Azure region it is in.
+type AzurePrivateLinkSubnet struct {
+	Subnet string `json:"subnet"`
+	Region string `json:"region"`
+}
+
 // GCPPrivateServiceConnectInventory is a VPC and its corresponding subnets.
 // This VPC will be used to create a GCP Endpoint whenever there is a Private Service Connect
 // service created for a ClusterDeployment.
@@ -407,11 +557,101 @@ type ArgoCDConfig struct {
 	Enabled bool `json:"enabled"`
 
 	// Namespace specifies the namespace where ArgoCD is installed. Used for the location of cluster secrets.
-	// Defaults to "argocd"
+	// Defaults to "argocd". Ignored when Instances are specified.
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
+
+	// Project is the ArgoCD project the clusters are registered in. If not specified, the clusters are available to
+	// all projects.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Instances lists the ArgoCD instances the clusters are registered in. If not specified, the clusters are
+	// registered in the single instance installed in Namespace.
+	// +optional
+	Instances []ArgoCDInstance `json:"instances,omitempty"`
+
+	// ClusterLabels maps ClusterDeployment labels and annotations onto labels of the ArgoCD cluster secrets. If not
+	// specified, all the labels of the ClusterDeployment are copied onto the cluster secrets.
+	// +optional
+	ClusterLabels []ArgoCDClusterLabel `json:"clusterLabels,omitempty"`
+
+	// HibernatedClusters specifies whether hibernated clusters stay registered in ArgoCD. Defaults to Keep.
+	// +kubebuilder:validation:Enum=Keep;Deregister
+	// +optional
+	HibernatedClusters ArgoCDHibernationPolicy `json:"hibernatedClusters,omitempty"`
+}
+
+// GitOpsRegistration registers installed clusters with a GitOps system, by creating the objects rendered from a
+// template for each of them.
+type GitOpsRegistration struct {
+	// Name identifies the registration. The objects of a registration removed from the list are deleted.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ClusterDeploymentSelector selects the ClusterDeployments registered. If not specified, all installed
+	// ClusterDeployments are registered.
+	// +optional
+	ClusterDeploymentSelector *metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// Template is a Go text/template rendering the YAML of the objects registering a cluster, separated by "---"
+	// lines. The template is executed with the ClusterDeployment as .ClusterDeployment, the name of its admin
+	// kubeconfig secret as .KubeconfigSecretName, and the admin kubeconfig as .Kubeconfig. The functions indent,
+	// base64 and toJSON are available to embed values in YAML. Namespaced objects without a namespace are created in
+	// the namespace of the ClusterDeployment.
+	// +kubebuilder:validation:MinLength=1
+	Template string `json:"template"`
 }
 
+// ArgoCDInstance is an ArgoCD instance the clusters are registered in.
+type ArgoCDInstance struct {
+	// Namespace is the namespace where the ArgoCD instance is installed, and where its cluster secrets are created.
+	Namespace string `json:"namespace"`
+
+	// ServiceAccountName is the name of the service account of the ArgoCD server, in Namespace, whose token ArgoCD
+	// uses to connect to the clusters. Defaults to "argocd-server".
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Project is the ArgoCD project the clusters are registered in. Defaults to the Project of the ArgoCDConfig.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// ClusterDeploymentSelector selects the ClusterDeployments registered in this instance. If not specified, all
+	// ClusterDeployments are registered.
+	// +optional
+	ClusterDeploymentSelector *metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+}
+
+// ArgoCDClusterLabel maps a ClusterDeployment label or annotation onto a label of the ArgoCD cluster secrets.
+// Exactly one of FromLabel and FromAnnotation must be specified.
+type ArgoCDClusterLabel struct {
+	// FromLabel is the key of the ClusterDeployment label to copy.
+	// +optional
+	FromLabel string `json:"fromLabel,omitempty"`
+
+	// FromAnnotation is the key of the ClusterDeployment annotation to copy. Annotations whose value is not a valid
+	// label value are not copied.
+	// +optional
+	FromAnnotation string `json:"fromAnnotation,omitempty"`
+
+	// ToLabel is the key of the cluster secret label. Defaults to FromLabel or FromAnnotation.
+	// +optional
+	ToLabel string `json:"toLabel,omitempty"`
+}
+
+// ArgoCDHibernationPolicy specifies whether hibernated clusters stay registered in ArgoCD.
+type ArgoCDHibernationPolicy string
+
+const (
+	// ArgoCDHibernationPolicyKeep keeps hibernated clusters registered in ArgoCD.
+	ArgoCDHibernationPolicyKeep ArgoCDHibernationPolicy = "Keep"
+
+	// ArgoCDHibernationPolicyDeregister removes clusters from ArgoCD while they are hibernating, and registers them
+	// again once they are running.
+	ArgoCDHibernationPolicyDeregister ArgoCDHibernationPolicy = "Deregister"
+)
+
 // BackupConfig contains settings for the Velero backup integration.
 type BackupConfig struct {
 	// Velero specifies configuration for the Velero backup integration.
@@ -534,6 +774,12 @@ const (
 	DeleteProtectionEnabled DeleteProtectionType = "enabled"
 )
 
+type PreflightChecksType string
+
+const (
+	PreflightChecksEnabled PreflightChecksType = "enabled"
+)
+
 // ManageDNSAzureConfig contains Azure-specific info to manage a given domain
 type ManageDNSAzureConfig struct {
 	// CredentialsSecretRef references a secret in the TargetNamespace that will be used to authenticate with
@@ -582,7 +828,7 @@ type ControllerConfig struct {
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
-// +kubebuilder:validation:Enum=clusterDeployment;clusterrelocate;clusterstate;clusterversion;controlPlaneCerts;dnsendpoint;dnszone;remoteingress;remotemachineset;machinepool;syncidentityprovider;unreachable;velerobackup;clusterprovision;clusterDeprovision;clusterpool;clusterpoolnamespace;hibernation;clusterclaim;metrics;clustersync
+// +kubebuilder:validation:Enum=clusterDeployment;clusterrelocate;clusterstate;clusterversion;controlPlaneCerts;dnsendpoint;dnszone;remoteingress;remotemachineset;machinepool;syncidentityprovider;unreachable;velerobackup;clusterprovision;clusterDeprovision;clusterpool;clusterpoolnamespace;hibernation;clusterclaim;metrics;clustersync;clustercost;controlPlaneMachineSet;clusterUpgrade;certificate;clusterAdoption;fleetHealth;apiServerTunnel;gitOpsRegister
 type ControllerName string
 
 func (controllerName ControllerName) String() string {
@@ -605,29 +851,37 @@ func (c ControllerNames) Contains(controllerName ControllerName) bool {
 
 // WARNING: All the controller names below should also be added to the kubebuilder validation of the type ControllerName
 const (
-	ClusterClaimControllerName         ControllerName = "clusterclaim"
-	ClusterDeploymentControllerName    ControllerName = "clusterDeployment"
-	ClusterDeprovisionControllerName   ControllerName = "clusterDeprovision"
-	ClusterpoolControllerName          ControllerName = "clusterpool"
-	ClusterpoolNamespaceControllerName ControllerName = "clusterpoolnamespace"
-	ClusterProvisionControllerName     ControllerName = "clusterProvision"
-	ClusterRelocateControllerName      ControllerName = "clusterRelocate"
-	ClusterStateControllerName         ControllerName = "clusterState"
-	ClusterVersionControllerName       ControllerName = "clusterversion"
-	ControlPlaneCertsControllerName    ControllerName = "controlPlaneCerts"
-	DNSEndpointControllerName          ControllerName = "dnsendpoint"
-	DNSZoneControllerName              ControllerName = "dnszone"
-	FakeClusterInstallControllerName   ControllerName = "fakeclusterinstall"
-	HibernationControllerName          ControllerName = "hibernation"
-	RemoteIngressControllerName        ControllerName = "remoteingress"
-	SyncIdentityProviderControllerName ControllerName = "syncidentityprovider"
-	UnreachableControllerName          ControllerName = "unreachable"
-	VeleroBackupControllerName         ControllerName = "velerobackup"
-	MetricsControllerName              ControllerName = "metrics"
-	ClustersyncControllerName          ControllerName = "clustersync"
-	AWSPrivateLinkControllerName       ControllerName = "awsprivatelink"
-	PrivateLinkControllerName          ControllerName = "privatelink"
-	HiveControllerName                 ControllerName = "hive"
+	ClusterClaimControllerName           ControllerName = "clusterclaim"
+	ClusterDeploymentControllerName      ControllerName = "clusterDeployment"
+	ClusterDeprovisionControllerName     ControllerName = "clusterDeprovision"
+	ClusterpoolControllerName            ControllerName = "clusterpool"
+	ClusterpoolNamespaceControllerName   ControllerName = "clusterpoolnamespace"
+	ClusterProvisionControllerName       ControllerName = "clusterProvision"
+	ClusterRelocateControllerName        ControllerName = "clusterRelocate"
+	ClusterStateControllerName           ControllerName = "clusterState"
+	ClusterVersionControllerName         ControllerName = "clusterversion"
+	ControlPlaneCertsControllerName      ControllerName = "controlPlaneCerts"
+	DNSEndpointControllerName            ControllerName = "dnsendpoint"
+	DNSZoneControllerName                ControllerName = "dnszone"
+	FakeClusterInstallControllerName     ControllerName = "fakeclusterinstall"
+	HibernationControllerName            ControllerName = "hibernation"
+	RemoteIngressControllerName          ControllerName = "remoteingress"
+	SyncIdentityProviderControllerName   ControllerName = "syncidentityprovider"
+	UnreachableControllerName            ControllerName = "unreachable"
+	VeleroBackupControllerName           ControllerName = "velerobackup"
+	MetricsControllerName                ControllerName = "metrics"
+	ClustersyncControllerName            ControllerName = "clustersync"
+	AWSPrivateLinkControllerName         ControllerName = "awsprivatelink"
+	PrivateLinkControllerName            ControllerName = "privatelink"
+	HiveControllerName                   ControllerName = "hive"
+	ClusterCostControllerName            ControllerName = "clustercost"
+	ControlPlaneMachineSetControllerName ControllerName = "controlPlaneMachineSet"
+	ClusterUpgradeControllerName         ControllerName = "clusterUpgrade"
+	CertificateControllerName            ControllerName = "certificate"
+	ClusterAdoptionControllerName        ControllerName = "clusterAdoption"
+	FleetHealthControllerName            ControllerName = "fleetHealth"
+	APIServerTunnelControllerName        ControllerName = "apiServerTunnel"
+	GitOpsRegisterControllerName         ControllerName = "gitOpsRegister"
 
 	// DeprecatedRemoteMachinesetControllerName was deprecated but can be used to disable the
 	// MachinePool controller which supercedes it for compatability.
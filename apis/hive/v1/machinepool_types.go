@@ -3,6 +3,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/openshift/hive/apis/hive/v1/aws"
 	"github.com/openshift/hive/apis/hive/v1/azure"
@@ -70,6 +71,28 @@ type MachinePoolSpec struct {
 	// Note that taints are uniquely identified based on key+effect, not just key.
 	// +optional
 	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// SpotAllocation mixes on-demand and spot capacity within the machine pool. When set, an on-demand
+	// and a spot MachineSet are generated for each zone: OnDemandBaseReplicas replicas always run on
+	// on-demand capacity, and the remaining replicas run on spot capacity, falling back to on-demand
+	// capacity when spot capacity is unavailable.
+	// Requires spot capacity to be configured in the platform (AWS spotMarketOptions, Azure spotVMOptions
+	// or the GCP Preemptible provisioningModel), and cannot be used with autoscaling.
+	// +optional
+	SpotAllocation *MachinePoolSpotAllocation `json:"spotAllocation,omitempty"`
+
+	// RolloutStrategy enables the rolling replacement of the Machines that no longer match the provider spec of
+	// their MachineSet, for example after the instance type of the machine pool was changed. When not set, such
+	// Machines are kept until they are deleted by other means.
+	// +optional
+	RolloutStrategy *MachinePoolRolloutStrategy `json:"rolloutStrategy,omitempty"`
+
+	// ScalingSchedules override the replicas or the autoscaling bounds of the machine pool during recurring
+	// time windows, for example to scale down clusters at night without hibernating them. When several
+	// schedules are active, the first one in the list is used. Outside of the windows, replicas and autoscaling
+	// apply.
+	// +optional
+	ScalingSchedules []MachinePoolScalingSchedule `json:"scalingSchedules,omitempty"`
 }
 
 // MachinePoolAutoscaling details how the machine pool is to be auto-scaled.
@@ -81,6 +104,88 @@ type MachinePoolAutoscaling struct {
 	MaxReplicas int32 `json:"maxReplicas"`
 }
 
+// MachinePoolSpotAllocation details how the replicas of a machine pool are split between on-demand and
+// spot capacity.
+type MachinePoolSpotAllocation struct {
+	// OnDemandBaseReplicas is the number of replicas of the machine pool that always run on on-demand
+	// capacity. The remaining replicas run on spot capacity.
+	// +kubebuilder:validation:Minimum=0
+	OnDemandBaseReplicas int32 `json:"onDemandBaseReplicas"`
+
+	// FallbackRetryInterval is how long replicas that fell back to on-demand capacity because spot
+	// capacity was unavailable stay on on-demand capacity before spot capacity is tried again.
+	// Defaults to 1h.
+	// +optional
+	FallbackRetryInterval *metav1.Duration `json:"fallbackRetryInterval,omitempty"`
+}
+
+// MachinePoolRolloutStrategy details how outdated Machines of a machine pool are replaced.
+type MachinePoolRolloutStrategy struct {
+	// MaxSurge is the maximum number of Machines that can be created above the desired replicas of each
+	// MachineSet while its outdated Machines are replaced. It can be an absolute number or a percentage of the
+	// desired replicas, rounded up. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the maximum number of Machines of each MachineSet that can be unavailable while its
+	// outdated Machines are replaced. It can be an absolute number or a percentage of the desired replicas,
+	// rounded down. Defaults to 0. MaxSurge and MaxUnavailable cannot both be 0.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// DrainTimeout is how long draining the Node of a Machine being replaced can take before the Machine is
+	// deleted without draining it. When not set, the Machine is only deleted once its Node is drained.
+	// +optional
+	DrainTimeout *metav1.Duration `json:"drainTimeout,omitempty"`
+}
+
+// MachinePoolScalingSchedule overrides the replicas or the autoscaling bounds of a machine pool during a recurring
+// time window.
+type MachinePoolScalingSchedule struct {
+	// Name identifies the schedule, and is reported in the status of the machine pool while the schedule is
+	// active.
+	Name string `json:"name"`
+
+	// Days are the days of the week on which the window starts. Defaults to every day.
+	// +optional
+	Days []ScheduleDay `json:"days,omitempty"`
+
+	// Start is the time of day the window starts, in 24-hour HH:MM format.
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	Start string `json:"start"`
+
+	// End is the time of day the window ends, in 24-hour HH:MM format. When End is not after Start, the window
+	// ends on the following day.
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	End string `json:"end"`
+
+	// TimeZone is the IANA time zone of Start and End, for example "Europe/Paris". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Replicas overrides the replicas of the machine pool during the window. Only valid for machine pools using
+	// replicas.
+	// +optional
+	Replicas *int64 `json:"replicas,omitempty"`
+
+	// Autoscaling overrides the autoscaling bounds of the machine pool during the window. Only valid for machine
+	// pools using autoscaling.
+	// +optional
+	Autoscaling *MachinePoolAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// MachineSetCapacityType is the kind of capacity the machines of a machine set run on.
+type MachineSetCapacityType string
+
+const (
+	// MachineSetCapacityOnDemand is used for machine sets running on on-demand capacity.
+	MachineSetCapacityOnDemand MachineSetCapacityType = "OnDemand"
+	// MachineSetCapacitySpot is used for machine sets running on spot capacity.
+	MachineSetCapacitySpot MachineSetCapacityType = "Spot"
+)
+
 // MachinePoolPlatform is the platform-specific configuration for a machine
 // pool. Only one of the platforms should be set.
 type MachinePoolPlatform struct {
@@ -113,6 +218,16 @@ type MachinePoolStatus struct {
 	// +optional
 	Conditions []MachinePoolCondition `json:"conditions,omitempty"`
 
+	// Rollout is the progress of the rolling replacement of outdated Machines. Only set for machine pools with a
+	// rolloutStrategy.
+	// +optional
+	Rollout *MachinePoolRolloutStatus `json:"rollout,omitempty"`
+
+	// ActiveScalingSchedule is the name of the scaling schedule currently overriding the replicas or the
+	// autoscaling bounds of the machine pool, if any.
+	// +optional
+	ActiveScalingSchedule string `json:"activeScalingSchedule,omitempty"`
+
 	// OwnedLabels lists the keys of labels this MachinePool created on the remote MachineSet's
 	// MachineSpec. (In contrast with OwnedMachineLabels.)
 	// Used to identify labels to remove from the remote MachineSet when they are absent from
@@ -140,6 +255,16 @@ type MachinePoolStatus struct {
 	ControlledByReplica *int64 `json:"controlledByReplica,omitempty"`
 }
 
+// MachinePoolRolloutStatus is the progress of the rolling replacement of the outdated Machines of a machine pool.
+type MachinePoolRolloutStatus struct {
+	// UpdatedReplicas is the number of Machines matching the provider spec of their MachineSet.
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// OutdatedReplicas is the number of Machines that no longer match the provider spec of their MachineSet and
+	// are yet to be replaced.
+	OutdatedReplicas int32 `json:"outdatedReplicas"`
+}
+
 // TaintIdentifier uniquely identifies a Taint. (It turns out taints are mutually exclusive by
 // key+effect, not simply by key.)
 type TaintIdentifier struct {
@@ -178,6 +303,16 @@ type MachineSetStatus struct {
 	ErrorReason *string `json:"errorReason,omitempty"`
 	// +optional
 	ErrorMessage *string `json:"errorMessage,omitempty"`
+
+	// CapacityType is the kind of capacity the machines of the machine set run on. Only set for machine
+	// pools with spotAllocation.
+	// +optional
+	CapacityType MachineSetCapacityType `json:"capacityType,omitempty"`
+
+	// SpotFallbackReplicas is the number of replicas of an on-demand machine set that run on on-demand
+	// capacity because spot capacity was unavailable.
+	// +optional
+	SpotFallbackReplicas int32 `json:"spotFallbackReplicas,omitempty"`
 }
 
 // MachinePoolCondition contains details for the current condition of a machine pool
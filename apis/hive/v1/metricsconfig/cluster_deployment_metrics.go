@@ -0,0 +1,12 @@
+package metricsconfig
+
+// ClusterDeploymentMetricType is a valid value for MetricsConfig.ClusterDeploymentMetrics
+// +kubebuilder:validation:Enum=clusterOperatorConditionUnsteadySince;certificateBundleExpiry
+type ClusterDeploymentMetricType string
+
+const (
+	// ClusterOperatorConditionUnsteadySince corresponds to hive_cluster_operator_condition_unsteady_since_timestamp_seconds
+	ClusterOperatorConditionUnsteadySince ClusterDeploymentMetricType = "clusterOperatorConditionUnsteadySince"
+	// CertificateBundleExpiry corresponds to hive_certificate_bundle_expiry_timestamp_seconds
+	CertificateBundleExpiry ClusterDeploymentMetricType = "certificateBundleExpiry"
+)
@@ -17,4 +17,9 @@ type MetricsConfig struct {
 	// pkg/controller/metrics/metrics_with_dynamic_labels.go
 	// +optional
 	AdditionalClusterDeploymentLabels *map[string]string `json:"additionalClusterDeploymentLabels,omitempty"`
+	// ClusterDeploymentMetrics lists optional metrics reported for each ClusterDeployment, labelled with its namespace
+	// and name. As their cardinality grows with the number of ClusterDeployments, they are only reported when listed
+	// here.
+	// +optional
+	ClusterDeploymentMetrics []ClusterDeploymentMetricType `json:"clusterDeploymentMetrics,omitempty"`
 }
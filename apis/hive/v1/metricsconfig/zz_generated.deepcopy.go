@@ -30,6 +30,11 @@ func (in *MetricsConfig) DeepCopyInto(out *MetricsConfig) {
 			}
 		}
 	}
+	if in.ClusterDeploymentMetrics != nil {
+		in, out := &in.ClusterDeploymentMetrics, &out.ClusterDeploymentMetrics
+		*out = make([]ClusterDeploymentMetricType, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
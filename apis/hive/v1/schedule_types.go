@@ -0,0 +1,65 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduleDay is a day of the week.
+// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+type ScheduleDay string
+
+// PowerSchedule keeps a cluster running and hibernating on a recurring schedule. The schedule is either a list of
+// weekly RunningWindows, or a pair of Resume and Hibernate cron expressions.
+type PowerSchedule struct {
+	// RunningWindows are the time windows during which the cluster runs, and hibernates outside of them. Cannot be
+	// set together with Resume and Hibernate.
+	// +optional
+	RunningWindows []ScheduleWindow `json:"runningWindows,omitempty"`
+
+	// Resume is a cron expression of the times at which the cluster is resumed, in the standard five-field
+	// "minute hour day-of-month month day-of-week" format, for example "0 8 * * 1-5". Requires Hibernate.
+	// +optional
+	Resume string `json:"resume,omitempty"`
+
+	// Hibernate is a cron expression of the times at which the cluster is hibernated, for example "0 19 * * 1-5".
+	// Requires Resume. When Resume and Hibernate match the same time, the cluster is hibernated.
+	// +optional
+	Hibernate string `json:"hibernate,omitempty"`
+
+	// TimeZone is the IANA time zone of the windows or cron expressions, for example "Europe/Paris". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduleWindow is a time window recurring on days of the week, from a start to an end time of day with minute
+// precision.
+type ScheduleWindow struct {
+	// Days are the days of the week on which the window starts. Defaults to every day.
+	// +optional
+	Days []ScheduleDay `json:"days,omitempty"`
+
+	// Start is the time of day the window starts, in 24-hour HH:MM format.
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	Start string `json:"start"`
+
+	// End is the time of day the window ends, in 24-hour HH:MM format. When End is not after Start, the window
+	// ends on the following day.
+	// +kubebuilder:validation:Pattern="^([01][0-9]|2[0-3]):[0-5][0-9]$"
+	End string `json:"end"`
+}
+
+// PowerScheduleStatus is the observed state of the power schedule of a cluster.
+type PowerScheduleStatus struct {
+	// LastTransition is the time of the last scheduled transition applied to spec.powerState. Manual changes to
+	// spec.powerState are not overridden until the next scheduled transition.
+	// +optional
+	LastTransition *metav1.Time `json:"lastTransition,omitempty"`
+
+	// NextTransition is the time of the next scheduled transition.
+	// +optional
+	NextTransition *metav1.Time `json:"nextTransition,omitempty"`
+
+	// NextPowerState is the power state the cluster transitions to at NextTransition.
+	// +optional
+	NextPowerState ClusterPowerState `json:"nextPowerState,omitempty"`
+}
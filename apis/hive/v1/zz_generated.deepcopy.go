@@ -23,8 +23,51 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerProxy) DeepCopyInto(out *APIServerProxy) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerProxy.
+func (in *APIServerProxy) DeepCopy() *APIServerProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *APIServerTunnelConfig) DeepCopyInto(out *APIServerTunnelConfig) {
+	*out = *in
+	if in.ServingCertificateSecretRef != nil {
+		in, out := &in.ServingCertificateSecretRef, &out.ServingCertificateSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new APIServerTunnelConfig.
+func (in *APIServerTunnelConfig) DeepCopy() *APIServerTunnelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(APIServerTunnelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSAssociatedVPC) DeepCopyInto(out *AWSAssociatedVPC) {
 	*out = *in
@@ -244,9 +287,37 @@ func (in *AWSServiceProviderCredentials) DeepCopy() *AWSServiceProviderCredentia
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDClusterLabel) DeepCopyInto(out *ArgoCDClusterLabel) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDClusterLabel.
+func (in *ArgoCDClusterLabel) DeepCopy() *ArgoCDClusterLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDClusterLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ArgoCDConfig) DeepCopyInto(out *ArgoCDConfig) {
 	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]ArgoCDInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterLabels != nil {
+		in, out := &in.ClusterLabels, &out.ClusterLabels
+		*out = make([]ArgoCDClusterLabel, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -260,6 +331,27 @@ func (in *ArgoCDConfig) DeepCopy() *ArgoCDConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoCDInstance) DeepCopyInto(out *ArgoCDInstance) {
+	*out = *in
+	if in.ClusterDeploymentSelector != nil {
+		in, out := &in.ClusterDeploymentSelector, &out.ClusterDeploymentSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoCDInstance.
+func (in *ArgoCDInstance) DeepCopy() *ArgoCDInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoCDInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureClusterDeprovision) DeepCopyInto(out *AzureClusterDeprovision) {
 	*out = *in
@@ -324,6 +416,49 @@ func (in *AzureDNSZoneStatus) DeepCopy() *AzureDNSZoneStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzurePrivateLinkConfig) DeepCopyInto(out *AzurePrivateLinkConfig) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.EndpointSubnetInventory != nil {
+		in, out := &in.EndpointSubnetInventory, &out.EndpointSubnetInventory
+		*out = make([]AzurePrivateLinkSubnet, len(*in))
+		copy(*out, *in)
+	}
+	if in.AssociatedVirtualNetworks != nil {
+		in, out := &in.AssociatedVirtualNetworks, &out.AssociatedVirtualNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzurePrivateLinkConfig.
+func (in *AzurePrivateLinkConfig) DeepCopy() *AzurePrivateLinkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AzurePrivateLinkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzurePrivateLinkSubnet) DeepCopyInto(out *AzurePrivateLinkSubnet) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzurePrivateLinkSubnet.
+func (in *AzurePrivateLinkSubnet) DeepCopy() *AzurePrivateLinkSubnet {
+	if in == nil {
+		return nil
+	}
+	out := new(AzurePrivateLinkSubnet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupConfig) DeepCopyInto(out *BackupConfig) {
 	*out = *in
@@ -382,6 +517,19 @@ func (in *CertificateBundleSpec) DeepCopy() *CertificateBundleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateBundleStatus) DeepCopyInto(out *CertificateBundleStatus) {
 	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.RenewalTime != nil {
+		in, out := &in.RenewalTime, &out.RenewalTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PendingOrder != nil {
+		in, out := &in.PendingOrder, &out.PendingOrder
+		*out = new(CertificateOrder)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -395,6 +543,55 @@ func (in *CertificateBundleStatus) DeepCopy() *CertificateBundleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerConfig) DeepCopyInto(out *CertificateIssuerConfig) {
+	*out = *in
+	out.AccountKeySecretRef = in.AccountKeySecretRef
+	if in.CACertificatesSecretRef != nil {
+		in, out := &in.CACertificatesSecretRef, &out.CACertificatesSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerConfig.
+func (in *CertificateIssuerConfig) DeepCopy() *CertificateIssuerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateOrder) DeepCopyInto(out *CertificateOrder) {
+	*out = *in
+	if in.AuthorizationURLs != nil {
+		in, out := &in.AuthorizationURLs, &out.AuthorizationURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ChallengeRecordsPublishedTime.DeepCopyInto(&out.ChallengeRecordsPublishedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateOrder.
+func (in *CertificateOrder) DeepCopy() *CertificateOrder {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateOrder)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Checkpoint) DeepCopyInto(out *Checkpoint) {
 	*out = *in
@@ -491,7 +688,7 @@ func (in *CheckpointStatus) DeepCopy() *CheckpointStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterClaim) DeepCopyInto(out *ClusterClaim) {
+func (in *ClusterAdoption) DeepCopyInto(out *ClusterAdoption) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -500,18 +697,18 @@ func (in *ClusterClaim) DeepCopyInto(out *ClusterClaim) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaim.
-func (in *ClusterClaim) DeepCopy() *ClusterClaim {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoption.
+func (in *ClusterAdoption) DeepCopy() *ClusterAdoption {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterClaim)
+	out := new(ClusterAdoption)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterClaim) DeepCopyObject() runtime.Object {
+func (in *ClusterAdoption) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -519,31 +716,29 @@ func (in *ClusterClaim) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterClaimCondition) DeepCopyInto(out *ClusterClaimCondition) {
+func (in *ClusterAdoptionCheck) DeepCopyInto(out *ClusterAdoptionCheck) {
 	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimCondition.
-func (in *ClusterClaimCondition) DeepCopy() *ClusterClaimCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoptionCheck.
+func (in *ClusterAdoptionCheck) DeepCopy() *ClusterAdoptionCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterClaimCondition)
+	out := new(ClusterAdoptionCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterClaimList) DeepCopyInto(out *ClusterClaimList) {
+func (in *ClusterAdoptionList) DeepCopyInto(out *ClusterAdoptionList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterClaim, len(*in))
+		*out = make([]ClusterAdoption, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -551,18 +746,18 @@ func (in *ClusterClaimList) DeepCopyInto(out *ClusterClaimList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimList.
-func (in *ClusterClaimList) DeepCopy() *ClusterClaimList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoptionList.
+func (in *ClusterAdoptionList) DeepCopy() *ClusterAdoptionList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterClaimList)
+	out := new(ClusterAdoptionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterClaimList) DeepCopyObject() runtime.Object {
+func (in *ClusterAdoptionList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -570,61 +765,90 @@ func (in *ClusterClaimList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterClaimSpec) DeepCopyInto(out *ClusterClaimSpec) {
+func (in *ClusterAdoptionReport) DeepCopyInto(out *ClusterAdoptionReport) {
 	*out = *in
-	if in.Subjects != nil {
-		in, out := &in.Subjects, &out.Subjects
-		*out = make([]rbacv1.Subject, len(*in))
+	if in.MachinePools != nil {
+		in, out := &in.MachinePools, &out.MachinePools
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Lifetime != nil {
-		in, out := &in.Lifetime, &out.Lifetime
-		*out = new(metav1.Duration)
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]ClusterAdoptionCheck, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoptionReport.
+func (in *ClusterAdoptionReport) DeepCopy() *ClusterAdoptionReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAdoptionReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAdoptionSpec) DeepCopyInto(out *ClusterAdoptionSpec) {
+	*out = *in
+	out.AdminKubeconfigSecretRef = in.AdminKubeconfigSecretRef
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.PullSecretRef != nil {
+		in, out := &in.PullSecretRef, &out.PullSecretRef
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimSpec.
-func (in *ClusterClaimSpec) DeepCopy() *ClusterClaimSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoptionSpec.
+func (in *ClusterAdoptionSpec) DeepCopy() *ClusterAdoptionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterClaimSpec)
+	out := new(ClusterAdoptionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterClaimStatus) DeepCopyInto(out *ClusterClaimStatus) {
+func (in *ClusterAdoptionStatus) DeepCopyInto(out *ClusterAdoptionStatus) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]ClusterClaimCondition, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(ClusterAdoptionReport)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.Lifetime != nil {
-		in, out := &in.Lifetime, &out.Lifetime
-		*out = new(metav1.Duration)
+	if in.LastValidationTime != nil {
+		in, out := &in.LastValidationTime, &out.LastValidationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ClusterDeploymentRef != nil {
+		in, out := &in.ClusterDeploymentRef, &out.ClusterDeploymentRef
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimStatus.
-func (in *ClusterClaimStatus) DeepCopy() *ClusterClaimStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAdoptionStatus.
+func (in *ClusterAdoptionStatus) DeepCopy() *ClusterAdoptionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterClaimStatus)
+	out := new(ClusterAdoptionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
+func (in *ClusterClaim) DeepCopyInto(out *ClusterClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -633,18 +857,18 @@ func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeployment.
-func (in *ClusterDeployment) DeepCopy() *ClusterDeployment {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaim.
+func (in *ClusterClaim) DeepCopy() *ClusterClaim {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeployment)
+	out := new(ClusterClaim)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
+func (in *ClusterClaim) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -652,45 +876,50 @@ func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentCondition) DeepCopyInto(out *ClusterDeploymentCondition) {
+func (in *ClusterClaimCondition) DeepCopyInto(out *ClusterClaimCondition) {
 	*out = *in
 	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentCondition.
-func (in *ClusterDeploymentCondition) DeepCopy() *ClusterDeploymentCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimCondition.
+func (in *ClusterClaimCondition) DeepCopy() *ClusterClaimCondition {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeploymentCondition)
+	out := new(ClusterClaimCondition)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentCustomization) DeepCopyInto(out *ClusterDeploymentCustomization) {
+func (in *ClusterClaimList) DeepCopyInto(out *ClusterClaimList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentCustomization.
-func (in *ClusterDeploymentCustomization) DeepCopy() *ClusterDeploymentCustomization {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimList.
+func (in *ClusterClaimList) DeepCopy() *ClusterClaimList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterDeploymentCustomization)
+	out := new(ClusterClaimList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterDeploymentCustomization) DeepCopyObject() runtime.Object {
+func (in *ClusterClaimList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -698,19 +927,170 @@ func (in *ClusterDeploymentCustomization) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterDeploymentCustomizationList) DeepCopyInto(out *ClusterDeploymentCustomizationList) {
+func (in *ClusterClaimSpec) DeepCopyInto(out *ClusterClaimSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterDeploymentCustomization, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	return
-}
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimSpec.
+func (in *ClusterClaimSpec) DeepCopy() *ClusterClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaimStatus) DeepCopyInto(out *ClusterClaimStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterClaimCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimStatus.
+func (in *ClusterClaimStatus) DeepCopy() *ClusterClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCostEstimate) DeepCopyInto(out *ClusterCostEstimate) {
+	*out = *in
+	out.CostSummary = in.CostSummary
+	if in.UnpricedInstanceTypes != nil {
+		in, out := &in.UnpricedInstanceTypes, &out.UnpricedInstanceTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdatedTime.DeepCopyInto(&out.LastUpdatedTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCostEstimate.
+func (in *ClusterCostEstimate) DeepCopy() *ClusterCostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeployment.
+func (in *ClusterDeployment) DeepCopy() *ClusterDeployment {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeployment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDeployment) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentCondition) DeepCopyInto(out *ClusterDeploymentCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentCondition.
+func (in *ClusterDeploymentCondition) DeepCopy() *ClusterDeploymentCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentCustomization) DeepCopyInto(out *ClusterDeploymentCustomization) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentCustomization.
+func (in *ClusterDeploymentCustomization) DeepCopy() *ClusterDeploymentCustomization {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeploymentCustomization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterDeploymentCustomization) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeploymentCustomizationList) DeepCopyInto(out *ClusterDeploymentCustomizationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterDeploymentCustomization, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeploymentCustomizationList.
 func (in *ClusterDeploymentCustomizationList) DeepCopy() *ClusterDeploymentCustomizationList {
@@ -826,6 +1206,11 @@ func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.DeleteAfter != nil {
+		in, out := &in.DeleteAfter, &out.DeleteAfter
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 	in.ControlPlaneConfig.DeepCopyInto(&out.ControlPlaneConfig)
 	if in.Ingress != nil {
 		in, out := &in.Ingress, &out.Ingress
@@ -864,6 +1249,11 @@ func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(PowerSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstallAttemptsLimit != nil {
 		in, out := &in.InstallAttemptsLimit, &out.InstallAttemptsLimit
 		*out = new(int32)
@@ -915,7 +1305,9 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 	if in.CertificateBundles != nil {
 		in, out := &in.CertificateBundles, &out.CertificateBundles
 		*out = make([]CertificateBundleStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.InstallStartedTimestamp != nil {
 		in, out := &in.InstallStartedTimestamp, &out.InstallStartedTimestamp
@@ -925,6 +1317,14 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 		in, out := &in.InstalledTimestamp, &out.InstalledTimestamp
 		*out = (*in).DeepCopy()
 	}
+	if in.ExpiryTimestamp != nil {
+		in, out := &in.ExpiryTimestamp, &out.ExpiryTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiryWarningTimestamp != nil {
+		in, out := &in.ExpiryWarningTimestamp, &out.ExpiryWarningTimestamp
+		*out = (*in).DeepCopy()
+	}
 	if in.ProvisionRef != nil {
 		in, out := &in.ProvisionRef, &out.ProvisionRef
 		*out = new(corev1.LocalObjectReference)
@@ -935,6 +1335,21 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 		*out = new(PlatformStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(ClusterCostEstimate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ControlPlaneMachines != nil {
+		in, out := &in.ControlPlaneMachines, &out.ControlPlaneMachines
+		*out = new(ControlPlaneMachinesStatus)
+		**out = **in
+	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(PowerScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1078,6 +1493,27 @@ func (in *ClusterDeprovisionPlatform) DeepCopy() *ClusterDeprovisionPlatform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDeprovisionResources) DeepCopyInto(out *ClusterDeprovisionResources) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDeprovisionResources.
+func (in *ClusterDeprovisionResources) DeepCopy() *ClusterDeprovisionResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDeprovisionResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDeprovisionSpec) DeepCopyInto(out *ClusterDeprovisionSpec) {
 	*out = *in
@@ -1105,6 +1541,16 @@ func (in *ClusterDeprovisionStatus) DeepCopyInto(out *ClusterDeprovisionStatus)
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DryRunResources != nil {
+		in, out := &in.DryRunResources, &out.DryRunResources
+		*out = new(ClusterDeprovisionResources)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LeakedResources != nil {
+		in, out := &in.LeakedResources, &out.LeakedResources
+		*out = new(ClusterDeprovisionResources)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1319,6 +1765,23 @@ func (in *ClusterMetadata) DeepCopy() *ClusterMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterOperatorConditionTransition) DeepCopyInto(out *ClusterOperatorConditionTransition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOperatorConditionTransition.
+func (in *ClusterOperatorConditionTransition) DeepCopy() *ClusterOperatorConditionTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterOperatorConditionTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterOperatorState) DeepCopyInto(out *ClusterOperatorState) {
 	*out = *in
@@ -1547,6 +2010,11 @@ func (in *ClusterPoolSpec) DeepCopyInto(out *ClusterPoolSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(PowerSchedule)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstallAttemptsLimit != nil {
 		in, out := &in.InstallAttemptsLimit, &out.InstallAttemptsLimit
 		*out = new(int32)
@@ -1597,6 +2065,11 @@ func (in *ClusterPoolStatus) DeepCopyInto(out *ClusterPoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(CostSummary)
+		**out = **in
+	}
 	return
 }
 
@@ -1689,6 +2162,50 @@ func (in *ClusterProvisionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvisionPhaseStatus) DeepCopyInto(out *ClusterProvisionPhaseStatus) {
+	*out = *in
+	in.StartedTime.DeepCopyInto(&out.StartedTime)
+	if in.FinishedTime != nil {
+		in, out := &in.FinishedTime, &out.FinishedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionPhaseStatus.
+func (in *ClusterProvisionPhaseStatus) DeepCopy() *ClusterProvisionPhaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProvisionPhaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvisionProgress) DeepCopyInto(out *ClusterProvisionProgress) {
+	*out = *in
+	if in.Phases != nil {
+		in, out := &in.Phases, &out.Phases
+		*out = make([]ClusterProvisionPhaseStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionProgress.
+func (in *ClusterProvisionProgress) DeepCopy() *ClusterProvisionProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProvisionProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterProvisionSpec) DeepCopyInto(out *ClusterProvisionSpec) {
 	*out = *in
@@ -1772,6 +2289,11 @@ func (in *ClusterProvisionStatus) DeepCopyInto(out *ClusterProvisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(ClusterProvisionProgress)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1791,7 +2313,7 @@ func (in *ClusterRelocate) DeepCopyInto(out *ClusterRelocate) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -1851,6 +2373,18 @@ func (in *ClusterRelocateSpec) DeepCopyInto(out *ClusterRelocateSpec) {
 	*out = *in
 	out.KubeconfigSecretRef = in.KubeconfigSecretRef
 	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	if in.AdditionalResources != nil {
+		in, out := &in.AdditionalResources, &out.AdditionalResources
+		*out = make([]RelocateResourceSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(RelocateVerification)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1867,6 +2401,13 @@ func (in *ClusterRelocateSpec) DeepCopy() *ClusterRelocateSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterRelocateStatus) DeepCopyInto(out *ClusterRelocateStatus) {
 	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterRelocationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1881,7 +2422,24 @@ func (in *ClusterRelocateStatus) DeepCopy() *ClusterRelocateStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterState) DeepCopyInto(out *ClusterState) {
+func (in *ClusterRelocationStatus) DeepCopyInto(out *ClusterRelocationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRelocationStatus.
+func (in *ClusterRelocationStatus) DeepCopy() *ClusterRelocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRelocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterState) DeepCopyInto(out *ClusterState) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1971,6 +2529,13 @@ func (in *ClusterStateStatus) DeepCopyInto(out *ClusterStateStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ClusterOperatorConditionTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -1984,6 +2549,169 @@ func (in *ClusterStateStatus) DeepCopy() *ClusterStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePlan) DeepCopyInto(out *ClusterUpgradePlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlan.
+func (in *ClusterUpgradePlan) DeepCopy() *ClusterUpgradePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUpgradePlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePlanCondition) DeepCopyInto(out *ClusterUpgradePlanCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanCondition.
+func (in *ClusterUpgradePlanCondition) DeepCopy() *ClusterUpgradePlanCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePlanCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePlanList) DeepCopyInto(out *ClusterUpgradePlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterUpgradePlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanList.
+func (in *ClusterUpgradePlanList) DeepCopy() *ClusterUpgradePlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUpgradePlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePlanSpec) DeepCopyInto(out *ClusterUpgradePlanSpec) {
+	*out = *in
+	out.ImageSetRef = in.ImageSetRef
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	if in.MaxConcurrent != nil {
+		in, out := &in.MaxConcurrent, &out.MaxConcurrent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanSpec.
+func (in *ClusterUpgradePlanSpec) DeepCopy() *ClusterUpgradePlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePlanStatus) DeepCopyInto(out *ClusterUpgradePlanStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterUpgradeStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterUpgradePlanCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanStatus.
+func (in *ClusterUpgradePlanStatus) DeepCopy() *ClusterUpgradePlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeStatus) DeepCopyInto(out *ClusterUpgradeStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeStatus.
+func (in *ClusterUpgradeStatus) DeepCopy() *ClusterUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneAdditionalCertificate) DeepCopyInto(out *ControlPlaneAdditionalCertificate) {
 	*out = *in
@@ -2004,6 +2732,16 @@ func (in *ControlPlaneAdditionalCertificate) DeepCopy() *ControlPlaneAdditionalC
 func (in *ControlPlaneConfigSpec) DeepCopyInto(out *ControlPlaneConfigSpec) {
 	*out = *in
 	in.ServingCertificates.DeepCopyInto(&out.ServingCertificates)
+	if in.APIServerProxy != nil {
+		in, out := &in.APIServerProxy, &out.APIServerProxy
+		*out = new(APIServerProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Machines != nil {
+		in, out := &in.Machines, &out.Machines
+		*out = new(ControlPlaneMachinesSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -2017,6 +2755,38 @@ func (in *ControlPlaneConfigSpec) DeepCopy() *ControlPlaneConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneMachinesSpec) DeepCopyInto(out *ControlPlaneMachinesSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneMachinesSpec.
+func (in *ControlPlaneMachinesSpec) DeepCopy() *ControlPlaneMachinesSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneMachinesSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneMachinesStatus) DeepCopyInto(out *ControlPlaneMachinesStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneMachinesStatus.
+func (in *ControlPlaneMachinesStatus) DeepCopy() *ControlPlaneMachinesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneMachinesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneServingCertificateSpec) DeepCopyInto(out *ControlPlaneServingCertificateSpec) {
 	*out = *in
@@ -2137,6 +2907,65 @@ func (in *ControllersConfig) DeepCopy() *ControllersConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimationConfig) DeepCopyInto(out *CostEstimationConfig) {
+	*out = *in
+	out.PriceTableConfigMapRef = in.PriceTableConfigMapRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimationConfig.
+func (in *CostEstimationConfig) DeepCopy() *CostEstimationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostSummary) DeepCopyInto(out *CostSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostSummary.
+func (in *CostSummary) DeepCopy() *CostSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(CostSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomInstaller) DeepCopyInto(out *CustomInstaller) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryableExitCodes != nil {
+		in, out := &in.RetryableExitCodes, &out.RetryableExitCodes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomInstaller.
+func (in *CustomInstaller) DeepCopy() *CustomInstaller {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomInstaller)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSZone) DeepCopyInto(out *DNSZone) {
 	*out = *in
@@ -2352,54 +3181,246 @@ func (in *FailedProvisionConfig) DeepCopyInto(out *FailedProvisionConfig) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedProvisionConfig.
-func (in *FailedProvisionConfig) DeepCopy() *FailedProvisionConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedProvisionConfig.
+func (in *FailedProvisionConfig) DeepCopy() *FailedProvisionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedProvisionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGateSelection) DeepCopyInto(out *FeatureGateSelection) {
+	*out = *in
+	if in.Custom != nil {
+		in, out := &in.Custom, &out.Custom
+		*out = new(FeatureGatesEnabled)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGateSelection.
+func (in *FeatureGateSelection) DeepCopy() *FeatureGateSelection {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGateSelection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FeatureGatesEnabled) DeepCopyInto(out *FeatureGatesEnabled) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesEnabled.
+func (in *FeatureGatesEnabled) DeepCopy() *FeatureGatesEnabled {
+	if in == nil {
+		return nil
+	}
+	out := new(FeatureGatesEnabled)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHealth) DeepCopyInto(out *FleetHealth) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealth.
+func (in *FleetHealth) DeepCopy() *FleetHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetHealth) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHealthCategoryStatus) DeepCopyInto(out *FleetHealthCategoryStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]FleetHealthCluster, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthCategoryStatus.
+func (in *FleetHealthCategoryStatus) DeepCopy() *FleetHealthCategoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetHealthCategoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHealthCluster) DeepCopyInto(out *FleetHealthCluster) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Since != nil {
+		in, out := &in.Since, &out.Since
+		*out = (*in).DeepCopy()
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]FleetHealthResourceReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthCluster.
+func (in *FleetHealthCluster) DeepCopy() *FleetHealthCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetHealthCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHealthList) DeepCopyInto(out *FleetHealthList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FleetHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthList.
+func (in *FleetHealthList) DeepCopy() *FleetHealthList {
+	if in == nil {
+		return nil
+	}
+	out := new(FleetHealthList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FleetHealthList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FleetHealthResourceReference) DeepCopyInto(out *FleetHealthResourceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthResourceReference.
+func (in *FleetHealthResourceReference) DeepCopy() *FleetHealthResourceReference {
 	if in == nil {
 		return nil
 	}
-	out := new(FailedProvisionConfig)
+	out := new(FleetHealthResourceReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureGateSelection) DeepCopyInto(out *FeatureGateSelection) {
+func (in *FleetHealthSpec) DeepCopyInto(out *FleetHealthSpec) {
 	*out = *in
-	if in.Custom != nil {
-		in, out := &in.Custom, &out.Custom
-		*out = new(FeatureGatesEnabled)
-		(*in).DeepCopyInto(*out)
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.LabelKeys != nil {
+		in, out := &in.LabelKeys, &out.LabelKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxClustersPerCategory != nil {
+		in, out := &in.MaxClustersPerCategory, &out.MaxClustersPerCategory
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PowerStateTimeout != nil {
+		in, out := &in.PowerStateTimeout, &out.PowerStateTimeout
+		*out = new(metav1.Duration)
+		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGateSelection.
-func (in *FeatureGateSelection) DeepCopy() *FeatureGateSelection {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthSpec.
+func (in *FleetHealthSpec) DeepCopy() *FleetHealthSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureGateSelection)
+	out := new(FleetHealthSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FeatureGatesEnabled) DeepCopyInto(out *FeatureGatesEnabled) {
+func (in *FleetHealthStatus) DeepCopyInto(out *FleetHealthStatus) {
 	*out = *in
-	if in.Enabled != nil {
-		in, out := &in.Enabled, &out.Enabled
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]FleetHealthCategoryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FeatureGatesEnabled.
-func (in *FeatureGatesEnabled) DeepCopy() *FeatureGatesEnabled {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FleetHealthStatus.
+func (in *FleetHealthStatus) DeepCopy() *FleetHealthStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(FeatureGatesEnabled)
+	out := new(FleetHealthStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2529,6 +3550,27 @@ func (in *GCPPrivateServiceConnectSubnet) DeepCopy() *GCPPrivateServiceConnectSu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitOpsRegistration) DeepCopyInto(out *GitOpsRegistration) {
+	*out = *in
+	if in.ClusterDeploymentSelector != nil {
+		in, out := &in.ClusterDeploymentSelector, &out.ClusterDeploymentSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitOpsRegistration.
+func (in *GitOpsRegistration) DeepCopy() *GitOpsRegistration {
+	if in == nil {
+		return nil
+	}
+	out := new(GitOpsRegistration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HibernationConfig) DeepCopyInto(out *HibernationConfig) {
 	*out = *in
@@ -2658,6 +3700,21 @@ func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CostEstimation != nil {
+		in, out := &in.CostEstimation, &out.CostEstimation
+		*out = new(CostEstimationConfig)
+		**out = **in
+	}
+	if in.CertificateIssuer != nil {
+		in, out := &in.CertificateIssuer, &out.CertificateIssuer
+		*out = new(CertificateIssuerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.APIServerTunnel != nil {
+		in, out := &in.APIServerTunnel, &out.APIServerTunnel
+		*out = new(APIServerTunnelConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DisabledControllers != nil {
 		in, out := &in.DisabledControllers, &out.DisabledControllers
 		*out = make([]string, len(*in))
@@ -2694,7 +3751,14 @@ func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
 		*out = new(ReleaseImageVerificationConfigMapReference)
 		**out = **in
 	}
-	out.ArgoCD = in.ArgoCD
+	in.ArgoCD.DeepCopyInto(&out.ArgoCD)
+	if in.GitOpsRegistrations != nil {
+		in, out := &in.GitOpsRegistrations, &out.GitOpsRegistrations
+		*out = make([]GitOpsRegistration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.FeatureGates != nil {
 		in, out := &in.FeatureGates, &out.FeatureGates
 		*out = new(FeatureGateSelection)
@@ -2774,6 +3838,28 @@ func (in *IdentityProviderStatus) DeepCopy() *IdentityProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallHook) DeepCopyInto(out *InstallHook) {
+	*out = *in
+	out.JobTemplateRef = in.JobTemplateRef
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallHook.
+func (in *InstallHook) DeepCopy() *InstallHook {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InventoryEntry) DeepCopyInto(out *InventoryEntry) {
 	*out = *in
@@ -3045,6 +4131,84 @@ func (in *MachinePoolPlatform) DeepCopy() *MachinePoolPlatform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolRolloutStatus) DeepCopyInto(out *MachinePoolRolloutStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolRolloutStatus.
+func (in *MachinePoolRolloutStatus) DeepCopy() *MachinePoolRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolRolloutStrategy) DeepCopyInto(out *MachinePoolRolloutStrategy) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.DrainTimeout != nil {
+		in, out := &in.DrainTimeout, &out.DrainTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolRolloutStrategy.
+func (in *MachinePoolRolloutStrategy) DeepCopy() *MachinePoolRolloutStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolRolloutStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolScalingSchedule) DeepCopyInto(out *MachinePoolScalingSchedule) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]ScheduleDay, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(MachinePoolAutoscaling)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolScalingSchedule.
+func (in *MachinePoolScalingSchedule) DeepCopy() *MachinePoolScalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolScalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolSpec) DeepCopyInto(out *MachinePoolSpec) {
 	*out = *in
@@ -3081,6 +4245,23 @@ func (in *MachinePoolSpec) DeepCopyInto(out *MachinePoolSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SpotAllocation != nil {
+		in, out := &in.SpotAllocation, &out.SpotAllocation
+		*out = new(MachinePoolSpotAllocation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(MachinePoolRolloutStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScalingSchedules != nil {
+		in, out := &in.ScalingSchedules, &out.ScalingSchedules
+		*out = make([]MachinePoolScalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -3094,6 +4275,27 @@ func (in *MachinePoolSpec) DeepCopy() *MachinePoolSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachinePoolSpotAllocation) DeepCopyInto(out *MachinePoolSpotAllocation) {
+	*out = *in
+	if in.FallbackRetryInterval != nil {
+		in, out := &in.FallbackRetryInterval, &out.FallbackRetryInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachinePoolSpotAllocation.
+func (in *MachinePoolSpotAllocation) DeepCopy() *MachinePoolSpotAllocation {
+	if in == nil {
+		return nil
+	}
+	out := new(MachinePoolSpotAllocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachinePoolStatus) DeepCopyInto(out *MachinePoolStatus) {
 	*out = *in
@@ -3111,6 +4313,11 @@ func (in *MachinePoolStatus) DeepCopyInto(out *MachinePoolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(MachinePoolRolloutStatus)
+		**out = **in
+	}
 	if in.OwnedLabels != nil {
 		in, out := &in.OwnedLabels, &out.OwnedLabels
 		*out = make([]string, len(*in))
@@ -3328,7 +4535,7 @@ func (in *Platform) DeepCopyInto(out *Platform) {
 	if in.Azure != nil {
 		in, out := &in.Azure, &out.Azure
 		*out = new(azure.Platform)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.BareMetal != nil {
 		in, out := &in.BareMetal, &out.BareMetal
@@ -3396,6 +4603,11 @@ func (in *PlatformStatus) DeepCopyInto(out *PlatformStatus) {
 		*out = new(gcp.PlatformStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(azure.PlatformStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3409,6 +4621,53 @@ func (in *PlatformStatus) DeepCopy() *PlatformStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerSchedule) DeepCopyInto(out *PowerSchedule) {
+	*out = *in
+	if in.RunningWindows != nil {
+		in, out := &in.RunningWindows, &out.RunningWindows
+		*out = make([]ScheduleWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerSchedule.
+func (in *PowerSchedule) DeepCopy() *PowerSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerScheduleStatus) DeepCopyInto(out *PowerScheduleStatus) {
+	*out = *in
+	if in.LastTransition != nil {
+		in, out := &in.LastTransition, &out.LastTransition
+		*out = (*in).DeepCopy()
+	}
+	if in.NextTransition != nil {
+		in, out := &in.NextTransition, &out.NextTransition
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerScheduleStatus.
+func (in *PowerScheduleStatus) DeepCopy() *PowerScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrivateLinkConfig) DeepCopyInto(out *PrivateLinkConfig) {
 	*out = *in
@@ -3417,6 +4676,11 @@ func (in *PrivateLinkConfig) DeepCopyInto(out *PrivateLinkConfig) {
 		*out = new(GCPPrivateServiceConnectConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzurePrivateLinkConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3470,6 +4734,25 @@ func (in *Provisioning) DeepCopyInto(out *Provisioning) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CustomInstaller != nil {
+		in, out := &in.CustomInstaller, &out.CustomInstaller
+		*out = new(CustomInstaller)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PreInstallHooks != nil {
+		in, out := &in.PreInstallHooks, &out.PreInstallHooks
+		*out = make([]InstallHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostInstallHooks != nil {
+		in, out := &in.PostInstallHooks, &out.PostInstallHooks
+		*out = make([]InstallHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -3499,6 +4782,65 @@ func (in *ReleaseImageVerificationConfigMapReference) DeepCopy() *ReleaseImageVe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelocateResourceSelector) DeepCopyInto(out *RelocateResourceSelector) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelocateResourceSelector.
+func (in *RelocateResourceSelector) DeepCopy() *RelocateResourceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(RelocateResourceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelocateVerification) DeepCopyInto(out *RelocateVerification) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelocateVerification.
+func (in *RelocateVerification) DeepCopy() *RelocateVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(RelocateVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]ScheduleDay, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretMapping) DeepCopyInto(out *SecretMapping) {
 	*out = *in
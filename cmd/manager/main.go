@@ -26,9 +26,13 @@ import (
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	cmdutil "github.com/openshift/hive/cmd/util"
 	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/apiservertunnel"
 	"github.com/openshift/hive/pkg/controller/argocdregister"
 	"github.com/openshift/hive/pkg/controller/awsprivatelink"
+	"github.com/openshift/hive/pkg/controller/certificate"
+	"github.com/openshift/hive/pkg/controller/clusteradoption"
 	"github.com/openshift/hive/pkg/controller/clusterclaim"
+	"github.com/openshift/hive/pkg/controller/clustercost"
 	"github.com/openshift/hive/pkg/controller/clusterdeployment"
 	"github.com/openshift/hive/pkg/controller/clusterdeprovision"
 	"github.com/openshift/hive/pkg/controller/clusterpool"
@@ -37,11 +41,15 @@ import (
 	"github.com/openshift/hive/pkg/controller/clusterrelocate"
 	"github.com/openshift/hive/pkg/controller/clusterstate"
 	"github.com/openshift/hive/pkg/controller/clustersync"
+	"github.com/openshift/hive/pkg/controller/clusterupgrade"
 	"github.com/openshift/hive/pkg/controller/clusterversion"
 	"github.com/openshift/hive/pkg/controller/controlplanecerts"
+	"github.com/openshift/hive/pkg/controller/controlplanemachineset"
 	"github.com/openshift/hive/pkg/controller/dnsendpoint"
 	"github.com/openshift/hive/pkg/controller/dnszone"
 	"github.com/openshift/hive/pkg/controller/fakeclusterinstall"
+	"github.com/openshift/hive/pkg/controller/fleethealth"
+	"github.com/openshift/hive/pkg/controller/gitopsregister"
 	"github.com/openshift/hive/pkg/controller/hibernation"
 	"github.com/openshift/hive/pkg/controller/machinepool"
 	"github.com/openshift/hive/pkg/controller/metrics"
@@ -66,30 +74,38 @@ const (
 type controllerSetupFunc func(manager.Manager) error
 
 var controllerFuncs = map[hivev1.ControllerName]controllerSetupFunc{
-	clusterclaim.ControllerName:         clusterclaim.Add,
-	clusterdeployment.ControllerName:    clusterdeployment.Add,
-	clusterdeprovision.ControllerName:   clusterdeprovision.Add,
-	clusterpoolnamespace.ControllerName: clusterpoolnamespace.Add,
-	clusterprovision.ControllerName:     clusterprovision.Add,
-	clusterrelocate.ControllerName:      clusterrelocate.Add,
-	clusterstate.ControllerName:         clusterstate.Add,
-	clustersync.ControllerName:          clustersync.Add,
-	clusterversion.ControllerName:       clusterversion.Add,
-	controlplanecerts.ControllerName:    controlplanecerts.Add,
-	dnsendpoint.ControllerName:          dnsendpoint.Add,
-	dnszone.ControllerName:              dnszone.Add,
-	fakeclusterinstall.ControllerName:   fakeclusterinstall.Add,
-	metrics.ControllerName:              metrics.Add,
-	remoteingress.ControllerName:        remoteingress.Add,
-	machinepool.ControllerName:          machinepool.Add,
-	syncidentityprovider.ControllerName: syncidentityprovider.Add,
-	unreachable.ControllerName:          unreachable.Add,
-	velerobackup.ControllerName:         velerobackup.Add,
-	clusterpool.ControllerName:          clusterpool.Add,
-	hibernation.ControllerName:          hibernation.Add,
-	privatelink.ControllerName:          privatelink.Add,
-	awsprivatelink.ControllerName:       awsprivatelink.Add,
-	argocdregister.ControllerName:       argocdregister.Add,
+	apiservertunnel.ControllerName:        apiservertunnel.Add,
+	certificate.ControllerName:            certificate.Add,
+	clusteradoption.ControllerName:        clusteradoption.Add,
+	clusterclaim.ControllerName:           clusterclaim.Add,
+	clusterdeployment.ControllerName:      clusterdeployment.Add,
+	clusterdeprovision.ControllerName:     clusterdeprovision.Add,
+	clusterpoolnamespace.ControllerName:   clusterpoolnamespace.Add,
+	clusterprovision.ControllerName:       clusterprovision.Add,
+	clusterrelocate.ControllerName:        clusterrelocate.Add,
+	clustercost.ControllerName:            clustercost.Add,
+	clusterstate.ControllerName:           clusterstate.Add,
+	clustersync.ControllerName:            clustersync.Add,
+	clusterupgrade.ControllerName:         clusterupgrade.Add,
+	clusterversion.ControllerName:         clusterversion.Add,
+	controlplanecerts.ControllerName:      controlplanecerts.Add,
+	controlplanemachineset.ControllerName: controlplanemachineset.Add,
+	dnsendpoint.ControllerName:            dnsendpoint.Add,
+	dnszone.ControllerName:                dnszone.Add,
+	fakeclusterinstall.ControllerName:     fakeclusterinstall.Add,
+	fleethealth.ControllerName:            fleethealth.Add,
+	gitopsregister.ControllerName:         gitopsregister.Add,
+	metrics.ControllerName:                metrics.Add,
+	remoteingress.ControllerName:          remoteingress.Add,
+	machinepool.ControllerName:            machinepool.Add,
+	syncidentityprovider.ControllerName:   syncidentityprovider.Add,
+	unreachable.ControllerName:            unreachable.Add,
+	velerobackup.ControllerName:           velerobackup.Add,
+	clusterpool.ControllerName:            clusterpool.Add,
+	hibernation.ControllerName:            hibernation.Add,
+	privatelink.ControllerName:            privatelink.Add,
+	awsprivatelink.ControllerName:         awsprivatelink.Add,
+	argocdregister.ControllerName:         argocdregister.Add,
 }
 
 // disabledControllerEquivalents contains a mapping of old controller names to their new equivalent so that CLI parameters like --controllers and --disabled-controllers continue to work
@@ -8,8 +8,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/hive/contrib/pkg/adm"
+	"github.com/openshift/hive/contrib/pkg/adopt"
+	"github.com/openshift/hive/contrib/pkg/apiservertunnel"
 	"github.com/openshift/hive/contrib/pkg/awsprivatelink"
 	"github.com/openshift/hive/contrib/pkg/certificate"
+	"github.com/openshift/hive/contrib/pkg/cluster"
 	"github.com/openshift/hive/contrib/pkg/clusterpool"
 	"github.com/openshift/hive/contrib/pkg/createcluster"
 	"github.com/openshift/hive/contrib/pkg/deprovision"
@@ -53,8 +56,11 @@ func newHiveutilCommand() *cobra.Command {
 	cmd.AddCommand(report.NewClusterReportCommand())
 	cmd.AddCommand(certificate.NewCertificateCommand())
 	cmd.AddCommand(adm.NewAdmCommand())
+	cmd.AddCommand(adopt.NewAdoptCommand())
+	cmd.AddCommand(apiservertunnel.NewAPIServerTunnelCommand())
 	cmd.AddCommand(version.NewVersionCommand())
 	cmd.AddCommand(clusterpool.NewClusterPoolCommand())
+	cmd.AddCommand(cluster.NewClusterCommand())
 	cmd.AddCommand(awsprivatelink.NewAWSPrivateLinkCommand())
 
 	return cmd
@@ -0,0 +1,179 @@
+package adopt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/contrib/pkg/utils"
+	"github.com/openshift/hive/pkg/clusteradoption"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+// Options is the set of options to adopt clusters.
+type Options struct {
+	Namespace             string
+	CredentialsSecretName string
+	PullSecretName        string
+	PreserveOnDelete      bool
+	Commit                bool
+
+	log log.FieldLogger
+	out io.Writer
+}
+
+// NewAdoptCommand returns a command that validates running clusters for adoption into Hive, and adopts them.
+func NewAdoptCommand() *cobra.Command {
+	opt := &Options{log: log.WithField("command", "adopt"), out: os.Stdout}
+
+	cmd := &cobra.Command{
+		Use:   "adopt KUBECONFIG...",
+		Short: "Validates and adopts running clusters",
+		Long: `Validates running clusters for adoption, from their admin kubeconfig files, and prints a report for each.
+With --commit, creates a ClusterAdoption, and the admin kubeconfig secret, for each valid cluster. Hive then
+creates the ClusterDeployment, and MachinePools reflecting the MachineSets of the cluster.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opt.Run(args); err != nil {
+				opt.log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace to create the ClusterAdoptions in. Defaults to the current namespace.")
+	flags.StringVar(&opt.CredentialsSecretName, "credentials-secret", "", "Name of the secret, in the namespace, with the cloud credentials of the clusters")
+	flags.StringVar(&opt.PullSecretName, "pull-secret", "", "Name of the secret, in the namespace, with the pull secret of the clusters")
+	flags.BoolVar(&opt.PreserveOnDelete, "preserve-on-delete", true, "Preserve the clusters when their ClusterDeployments are deleted")
+	flags.BoolVar(&opt.Commit, "commit", false, "Create ClusterAdoptions for the valid clusters. Without it, the clusters are only validated.")
+
+	return cmd
+}
+
+// Run validates the cluster of each kubeconfig file, and creates ClusterAdoptions for the valid ones when committing.
+func (o *Options) Run(kubeconfigFiles []string) error {
+	var c client.Client
+	if o.Commit {
+		var err error
+		if c, err = utils.GetClient("hiveutil-adopt"); err != nil {
+			return errors.Wrap(err, "cannot create hub client")
+		}
+		if o.Namespace == "" {
+			if o.Namespace, err = utils.DefaultNamespace(); err != nil {
+				return errors.Wrap(err, "cannot determine default namespace")
+			}
+		}
+	}
+
+	var invalid []string
+	for _, file := range kubeconfigFiles {
+		logger := o.log.WithField("kubeconfig", file)
+		kubeconfig, cluster, err := o.introspect(file)
+		if err != nil {
+			logger.WithError(err).Error("cannot validate cluster")
+			invalid = append(invalid, file)
+			continue
+		}
+		printReport(o.out, file, cluster.Report)
+		if !cluster.Report.Valid {
+			invalid = append(invalid, file)
+			continue
+		}
+		if !o.Commit {
+			continue
+		}
+		if err := o.createAdoption(c, kubeconfig, cluster.Report); err != nil {
+			logger.WithError(err).Error("cannot create cluster adoption")
+			invalid = append(invalid, file)
+			continue
+		}
+		logger.WithField("clusterAdoption", fmt.Sprintf("%s/%s", o.Namespace, cluster.Report.ClusterName)).Info("created cluster adoption")
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("%d of %d clusters cannot be adopted: %s", len(invalid), len(kubeconfigFiles), strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+func (o *Options) introspect(file string) ([]byte, *clusteradoption.Cluster, error) {
+	kubeconfig, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot load kubeconfig")
+	}
+	remoteClient, err := client.New(cfg, client.Options{Scheme: scheme.GetScheme()})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot create cluster client")
+	}
+	return kubeconfig, clusteradoption.Introspect(context.Background(), remoteClient, o.CredentialsSecretName != ""), nil
+}
+
+// createAdoption creates the admin kubeconfig secret and the ClusterAdoption of a cluster. Both are named after the
+// cluster.
+func (o *Options) createAdoption(c client.Client, kubeconfig []byte, report *hivev1.ClusterAdoptionReport) error {
+	name := report.ClusterName
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("cluster name %q is not a valid resource name: %s", name, strings.Join(errs, ", "))
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: o.Namespace, Name: name + "-admin-kubeconfig"},
+		Type:       corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			constants.KubeconfigSecretKey:    kubeconfig,
+			constants.RawKubeconfigSecretKey: kubeconfig,
+		},
+	}
+	if err := c.Create(context.Background(), secret); err != nil {
+		return errors.Wrap(err, "cannot create admin kubeconfig secret")
+	}
+	adoption := &hivev1.ClusterAdoption{
+		ObjectMeta: metav1.ObjectMeta{Namespace: o.Namespace, Name: name},
+		Spec: hivev1.ClusterAdoptionSpec{
+			AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: secret.Name},
+			PreserveOnDelete:         o.PreserveOnDelete,
+			Adopt:                    true,
+		},
+	}
+	if o.CredentialsSecretName != "" {
+		adoption.Spec.CredentialsSecretRef = &corev1.LocalObjectReference{Name: o.CredentialsSecretName}
+	}
+	if o.PullSecretName != "" {
+		adoption.Spec.PullSecretRef = &corev1.LocalObjectReference{Name: o.PullSecretName}
+	}
+	return c.Create(context.Background(), adoption)
+}
+
+func printReport(out io.Writer, file string, report *hivev1.ClusterAdoptionReport) {
+	fmt.Fprintf(out, "Cluster %s (%s)\n", report.ClusterName, file)
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "  Base domain:\t%s\n", report.BaseDomain)
+	fmt.Fprintf(w, "  Cluster ID:\t%s\n", report.ClusterID)
+	fmt.Fprintf(w, "  Infra ID:\t%s\n", report.InfraID)
+	fmt.Fprintf(w, "  Platform:\t%s\n", report.Platform)
+	fmt.Fprintf(w, "  Region:\t%s\n", report.Region)
+	fmt.Fprintf(w, "  Version:\t%s\n", report.Version)
+	fmt.Fprintf(w, "  MachinePools:\t%s\n", strings.Join(report.MachinePools, ", "))
+	fmt.Fprintf(w, "  Valid:\t%t\n", report.Valid)
+	for _, check := range report.Checks {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", check.Name, check.Result, check.Message)
+	}
+	w.Flush()
+}
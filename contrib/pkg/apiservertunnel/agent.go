@@ -0,0 +1,35 @@
+package apiservertunnel
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/hive/pkg/apiservertunnel"
+)
+
+// NewAgentCommand returns a command running the API server tunnel agent. It runs on the remote cluster, deployed by
+// the SyncSet of the apiServerTunnel controller.
+func NewAgentCommand() *cobra.Command {
+	var configDir string
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Runs the API server tunnel agent, connecting the API server of this cluster to Hive",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			logger := log.WithField("command", "apiserver-tunnel-agent")
+			agent, err := apiservertunnel.LoadAgent(configDir, logger)
+			if err != nil {
+				logger.WithError(err).Fatal("could not load the agent configuration")
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			agent.Run(ctx)
+		},
+	}
+	cmd.Flags().StringVar(&configDir, "config-dir", "/etc/hive-apiserver-tunnel", "Directory holding the keys of the agent secret as files")
+	return cmd
+}
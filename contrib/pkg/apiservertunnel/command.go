@@ -0,0 +1,19 @@
+package apiservertunnel
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewAPIServerTunnelCommand returns a command to run and bootstrap the API server tunnel agent.
+func NewAPIServerTunnelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apiserver-tunnel",
+		Short: "Utilities for the API server tunnel agent",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Usage()
+		},
+	}
+	cmd.AddCommand(NewAgentCommand())
+	cmd.AddCommand(NewManifestsCommand())
+	return cmd
+}
@@ -0,0 +1,106 @@
+package apiservertunnel
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/printers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/contrib/pkg/utils"
+	"github.com/openshift/hive/pkg/apiservertunnel"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+// ManifestsOptions is the set of options to print the manifests of the API server tunnel agent of a cluster.
+type ManifestsOptions struct {
+	Namespace string
+
+	out io.Writer
+}
+
+// NewManifestsCommand returns a command printing the manifests of the API server tunnel agent of a cluster, to apply
+// to the cluster when Hive cannot reach it yet to sync them.
+func NewManifestsCommand() *cobra.Command {
+	opt := &ManifestsOptions{out: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "manifests CLUSTER_DEPLOYMENT",
+		Short: "Prints the manifests of the API server tunnel agent of a cluster",
+		Long: `Prints the manifests of the API server tunnel agent of a ClusterDeployment with the API server tunnel enabled,
+including its secret. Hive syncs them once it reaches the cluster through the tunnel, so they only need to be
+applied to the cluster, for instance with "oc apply -f -", when Hive cannot reach it otherwise.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opt.Run(args[0]); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace of the ClusterDeployment. Defaults to the current namespace.")
+	return cmd
+}
+
+// Run prints the resources of the agent SyncSet and the agent secret of a ClusterDeployment.
+func (o *ManifestsOptions) Run(name string) error {
+	c, err := utils.GetClient("hiveutil-apiserver-tunnel")
+	if err != nil {
+		return errors.Wrap(err, "cannot create hub client")
+	}
+	if o.Namespace == "" {
+		if o.Namespace, err = utils.DefaultNamespace(); err != nil {
+			return errors.Wrap(err, "cannot determine default namespace")
+		}
+	}
+	objects, err := o.manifests(context.Background(), c, name)
+	if err != nil {
+		return err
+	}
+	printer := printers.NewTypeSetter(scheme.GetScheme()).ToPrinter(&printers.YAMLPrinter{})
+	for _, obj := range objects {
+		if err := printer.PrintObj(obj, o.out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *ManifestsOptions) manifests(ctx context.Context, c client.Client, name string) ([]runtime.Object, error) {
+	key := types.NamespacedName{Namespace: o.Namespace, Name: apiservertunnel.AgentSecretName(name)}
+	syncSet := &hivev1.SyncSet{}
+	if err := c.Get(ctx, key, syncSet); err != nil {
+		return nil, errors.Wrap(err, "cannot get the agent syncset, is the API server tunnel enabled for the cluster deployment?")
+	}
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, key, secret); err != nil {
+		return nil, errors.Wrap(err, "cannot get the agent secret")
+	}
+
+	var objects []runtime.Object
+	for _, resource := range syncSet.Spec.Resources {
+		if resource.Object != nil {
+			objects = append(objects, resource.Object)
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(resource.Raw); err != nil {
+			return nil, errors.Wrap(err, "cannot decode the agent syncset resources")
+		}
+		objects = append(objects, obj)
+	}
+	return append(objects, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: apiservertunnel.AgentNamespace, Name: apiservertunnel.AgentName},
+		Type:       corev1.SecretTypeOpaque,
+		Data:       secret.Data,
+	}), nil
+}
@@ -0,0 +1,18 @@
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewClusterCommand returns a command with utilities to inspect the clusters managed by Hive.
+func NewClusterCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cluster",
+		Short: "Utilities to inspect the clusters managed by Hive",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Usage()
+		},
+	}
+	cmd.AddCommand(NewHealthCommand())
+	return cmd
+}
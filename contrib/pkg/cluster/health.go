@@ -0,0 +1,177 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/contrib/pkg/utils"
+	"github.com/openshift/hive/pkg/controller/clusterstate"
+)
+
+// HealthOptions is the set of options to report the health history of clusters.
+type HealthOptions struct {
+	Namespace     string
+	AllNamespaces bool
+	Since         time.Duration
+	Operator      string
+	Fleet         bool
+
+	log log.FieldLogger
+	out io.Writer
+}
+
+// NewHealthCommand returns a command that reports how long the cluster operators of clusters were out of their
+// steady status, from the history of their ClusterStates.
+func NewHealthCommand() *cobra.Command {
+	opt := &HealthOptions{log: log.WithField("command", "cluster health"), out: os.Stdout}
+
+	cmd := &cobra.Command{
+		Use:   "health [CLUSTER_DEPLOYMENT_NAME]",
+		Short: "Reports the history of the cluster operator conditions of clusters",
+		Long: `Reports, for each cluster, how long the conditions of its cluster operators were out of their steady status
+(Available and Upgradeable True, Degraded and Progressing False), and how many times they transitioned, over a period
+of time. The report is computed from the bounded history of the ClusterStates.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := ""
+			if len(args) > 0 {
+				name = args[0]
+			}
+			if err := opt.Run(name); err != nil {
+				opt.log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace of the clusters. Defaults to the current namespace.")
+	flags.BoolVarP(&opt.AllNamespaces, "all-namespaces", "A", false, "Report the clusters of all namespaces")
+	flags.DurationVar(&opt.Since, "since", 7*24*time.Hour, "Length of the period to report, ending now")
+	flags.StringVar(&opt.Operator, "operator", "", "Only report the conditions of this cluster operator")
+	flags.BoolVar(&opt.Fleet, "fleet", false, "Aggregate the conditions of all the clusters, by operator")
+
+	return cmd
+}
+
+// clusterSummary is the summary of a condition of a cluster operator of a cluster.
+type clusterSummary struct {
+	namespace string
+	name      string
+	clusterstate.ConditionSummary
+}
+
+// Run reports the history of the cluster operator conditions of the ClusterState named name, or of all the
+// ClusterStates when name is empty.
+func (o *HealthOptions) Run(name string) error {
+	c, err := utils.GetClient("hiveutil-cluster-health")
+	if err != nil {
+		return errors.Wrap(err, "cannot create client")
+	}
+	var opts []client.ListOption
+	if !o.AllNamespaces {
+		if o.Namespace == "" {
+			if o.Namespace, err = utils.DefaultNamespace(); err != nil {
+				return errors.Wrap(err, "cannot determine default namespace")
+			}
+		}
+		opts = append(opts, client.InNamespace(o.Namespace))
+	}
+	states := &hivev1.ClusterStateList{}
+	if err := c.List(context.Background(), states, opts...); err != nil {
+		return errors.Wrap(err, "cannot list cluster states")
+	}
+
+	now := time.Now()
+	var summaries []clusterSummary
+	for _, st := range states.Items {
+		if name != "" && st.Name != name {
+			continue
+		}
+		for _, summary := range clusterstate.SummarizeHistory(st.Status.History, now.Add(-o.Since), now) {
+			if o.Operator != "" && summary.Operator != o.Operator {
+				continue
+			}
+			summaries = append(summaries, clusterSummary{namespace: st.Namespace, name: st.Name, ConditionSummary: summary})
+		}
+	}
+	if o.Fleet {
+		printFleet(o.out, summaries)
+	} else {
+		printClusters(o.out, summaries)
+	}
+	return nil
+}
+
+func printClusters(out io.Writer, summaries []clusterSummary) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].UnsteadyDuration > summaries[j].UnsteadyDuration
+	})
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tCLUSTER\tOPERATOR\tCONDITION\tSTATUS\tUNSTEADY\tTRANSITIONS")
+	for _, s := range summaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", s.namespace, s.name, s.Operator, s.Type, s.Status,
+			s.UnsteadyDuration.Round(time.Second), s.Transitions)
+	}
+	w.Flush()
+}
+
+func printFleet(out io.Writer, summaries []clusterSummary) {
+	type key struct {
+		operator string
+		ctype    configv1.ClusterStatusConditionType
+	}
+	type fleetSummary struct {
+		key
+		clusters    int
+		unsteady    int
+		duration    time.Duration
+		transitions int
+	}
+	byKey := map[key]*fleetSummary{}
+	for _, s := range summaries {
+		k := key{operator: s.Operator, ctype: s.Type}
+		fs, ok := byKey[k]
+		if !ok {
+			fs = &fleetSummary{key: k}
+			byKey[k] = fs
+		}
+		fs.clusters++
+		if !clusterstate.IsSteady(s.Type, s.Status) {
+			fs.unsteady++
+		}
+		fs.duration += s.UnsteadyDuration
+		fs.transitions += s.Transitions
+	}
+	fleet := make([]*fleetSummary, 0, len(byKey))
+	for _, fs := range byKey {
+		fleet = append(fleet, fs)
+	}
+	sort.Slice(fleet, func(i, j int) bool {
+		a, b := fleet[i], fleet[j]
+		if a.duration != b.duration {
+			return a.duration > b.duration
+		}
+		return a.operator < b.operator || (a.operator == b.operator && a.ctype < b.ctype)
+	})
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPERATOR\tCONDITION\tCLUSTERS\tCURRENTLY UNSTEADY\tTOTAL UNSTEADY\tTRANSITIONS")
+	for _, fs := range fleet {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%d\n", fs.operator, fs.ctype, fs.clusters, fs.unsteady,
+			fs.duration.Round(time.Second), fs.transitions)
+	}
+	w.Flush()
+}
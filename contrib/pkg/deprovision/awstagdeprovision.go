@@ -9,6 +9,7 @@ import (
 
 	"github.com/openshift/hive/contrib/pkg/utils"
 	awsutils "github.com/openshift/hive/contrib/pkg/utils/aws"
+	"github.com/openshift/hive/pkg/awsclient"
 	"github.com/openshift/installer/pkg/destroy/aws"
 )
 
@@ -18,6 +19,7 @@ func NewDeprovisionAWSWithTagsCommand() *cobra.Command {
 	opt := &aws.ClusterUninstaller{}
 	var credsDir string
 	var logLevel string
+	var dryRun bool
 	cmd := &cobra.Command{
 		Use:   "aws-tag-deprovision KEY=VALUE ...",
 		Short: "Deprovision AWS assets (as created by openshift-installer) with the given tag(s)",
@@ -28,6 +30,13 @@ func NewDeprovisionAWSWithTagsCommand() *cobra.Command {
 				return
 			}
 
+			if dryRun {
+				if err := listAWSResources(opt); err != nil {
+					log.WithError(err).Fatal("Cannot list resources")
+				}
+				return
+			}
+
 			if credsDir != "" {
 				go terminateWhenFilesChange(credsDir)
 			}
@@ -45,6 +54,7 @@ func NewDeprovisionAWSWithTagsCommand() *cobra.Command {
 	flags.StringVar(&credsDir, "creds-dir", "", "directory of the creds. Changes in the creds will cause the program to terminate")
 	flags.StringVar(&opt.HostedZoneRole, "hosted-zone-role", "", "the role to assume when performing operations on a hosted zone owned by another account.")
 	flags.StringVar(&opt.ClusterDomain, "cluster-domain", "", "the parent DNS domain of the cluster (e.g. the thing after `api.`).")
+	flags.BoolVar(&dryRun, "dry-run", false, "list the resources with the given tag(s) instead of deleting them")
 	return cmd
 }
 
@@ -75,6 +85,36 @@ func completeAWSUninstaller(o *aws.ClusterUninstaller, logLevel string, args []s
 	return nil
 }
 
+// listAWSResources prints the ARNs of the resources matching the filters of the uninstaller. Each filter
+// holds a single tag, as parsed from the arguments of the command.
+func listAWSResources(o *aws.ClusterUninstaller) error {
+	tags := map[string]string{}
+	for _, filter := range o.Filters {
+		for key, value := range filter {
+			tags[key] = value
+		}
+	}
+	client, err := awsclient.NewClient(nil, "", "", o.Region)
+	if err != nil {
+		return err
+	}
+	var globalClient awsclient.Client
+	if globalRegion := awsclient.GlobalRegion(o.Region); globalRegion != o.Region {
+		if globalClient, err = awsclient.NewClient(nil, "", "", globalRegion); err != nil {
+			return err
+		}
+	}
+	resources, err := awsclient.ListTaggedResources(client, globalClient, tags)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		fmt.Println(resource)
+	}
+	o.Logger.Infof("Found %d resources", len(resources))
+	return nil
+}
+
 func parseFilter(filterMap aws.Filter, str string) error {
 	parts := strings.SplitN(str, "=", 2)
 	if len(parts) != 2 {
@@ -1,6 +1,9 @@
 package deprovision
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/openshift/hive/contrib/pkg/utils"
 	azureutils "github.com/openshift/hive/contrib/pkg/utils/azure"
+	"github.com/openshift/hive/pkg/azureclient"
 )
 
 // AzureOptions is the set of options to deprovision an Azure cluster
@@ -19,6 +23,7 @@ type AzureOptions struct {
 	logLevel          string
 	cloudName         string
 	resourceGroupName string
+	dryRun            bool
 }
 
 // NewDeprovisionAzureCommand is the entrypoint to create the azure deprovision subcommand
@@ -37,6 +42,12 @@ func NewDeprovisionAzureCommand() *cobra.Command {
 			if err := validate(); err != nil {
 				log.WithError(err).Fatal("Failed validating Azure credentials")
 			}
+			if opt.dryRun {
+				if err := listAzureResources(opt, args[0]); err != nil {
+					log.WithError(err).Fatal("Cannot list resources")
+				}
+				return
+			}
 
 			// ClusterQuota stomped in return
 			if _, err := uninstaller.Run(); err != nil {
@@ -48,6 +59,7 @@ func NewDeprovisionAzureCommand() *cobra.Command {
 	flags.StringVar(&opt.logLevel, "loglevel", "info", "log level, one of: debug, info, warn, error, fatal, panic")
 	flags.StringVar(&opt.cloudName, "azure-cloud-name", installertypesazure.PublicCloud.Name(), "The name of the Azure cloud environment used to configure the Azure SDK")
 	flags.StringVar(&opt.resourceGroupName, "azure-resource-group-name", "", "The name of the custom Azure resource group in which the cluster was created when not using the default installer-created resource group")
+	flags.BoolVar(&opt.dryRun, "dry-run", false, "list the resources of the cluster instead of deleting them")
 	return cmd
 }
 
@@ -85,3 +97,29 @@ func completeAzureUninstaller(logLevel, cloudName, resourceGroupName string, arg
 
 	return azure.New(logger, metadata)
 }
+
+// listAzureResources prints the IDs of the resources the uninstaller would delete: the resource group of the
+// cluster, with its resources, and the resources tagged as owned by the cluster.
+func listAzureResources(o *AzureOptions, infraID string) error {
+	logger, err := utils.NewLogger(o.logLevel)
+	if err != nil {
+		return err
+	}
+	creds, err := azureutils.GetCreds("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get Azure credentials")
+	}
+	client, err := azureclient.NewClient(creds, o.cloudName)
+	if err != nil {
+		return err
+	}
+	resources, err := azureclient.ListClusterResources(context.TODO(), client, infraID, o.resourceGroupName, logger)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		fmt.Println(resource)
+	}
+	logger.Infof("Found %d resources", len(resources))
+	return nil
+}
@@ -23,6 +23,7 @@ type gcpOptions struct {
 	region           string
 	projectID        string
 	networkProjectID string
+	dryRun           bool
 }
 
 // NewDeprovisionGCPCommand is the entrypoint to create the GCP deprovision subcommand
@@ -48,6 +49,7 @@ func NewDeprovisionGCPCommand() *cobra.Command {
 	flags.StringVar(&opt.logLevel, "loglevel", "info", "log level, one of: debug, info, warn, error, fatal, panic")
 	flags.StringVar(&opt.region, "region", "", "GCP region where the cluster is installed")
 	flags.StringVar(&opt.networkProjectID, "network-project-id", "", "For shared VPC setups")
+	flags.BoolVar(&opt.dryRun, "dry-run", false, "list the resources named after the infra ID instead of deleting them")
 	return cmd
 }
 
@@ -90,6 +92,9 @@ func (o *gcpOptions) Run() error {
 	if err != nil {
 		return err
 	}
+	if o.dryRun {
+		return listGCPResources(o.infraID, logger)
+	}
 
 	metadata := &types.ClusterMetadata{
 		InfraID: o.infraID,
@@ -111,3 +116,24 @@ func (o *gcpOptions) Run() error {
 	_, err = destroyer.Run()
 	return err
 }
+
+// listGCPResources prints the self links of the resources matching the infra ID, as the uninstaller does.
+func listGCPResources(infraID string, logger log.FieldLogger) error {
+	creds, err := gcputils.GetCreds("")
+	if err != nil {
+		return errors.Wrap(err, "failed to get GCP credentials")
+	}
+	client, err := gcpclient.NewClient(creds)
+	if err != nil {
+		return err
+	}
+	resources, err := gcpclient.ListClusterResources(client, infraID)
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		fmt.Println(resource)
+	}
+	logger.Infof("Found %d resources", len(resources))
+	return nil
+}
@@ -0,0 +1,182 @@
+package apiservertunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// AgentURLKey is the key of the agent secret holding the URL of the tunnel endpoint.
+	AgentURLKey = "url"
+	// AgentNamespaceKey is the key of the agent secret holding the namespace of the ClusterDeployment.
+	AgentNamespaceKey = "namespace"
+	// AgentNameKey is the key of the agent secret holding the name of the ClusterDeployment.
+	AgentNameKey = "name"
+	// AgentTokenKey is the key of the agent secret holding the token with which the agent authenticates.
+	AgentTokenKey = "token"
+	// AgentCAKey is the key of the agent secret holding the PEM encoded certificate authorities trusted, in addition
+	// to the system ones, when connecting to the tunnel endpoint.
+	AgentCAKey = "ca.crt"
+)
+
+// Agent connects to the tunnel endpoint and forwards the streams opened by the endpoint to the API server of its
+// cluster.
+type Agent struct {
+	// URL is the URL of the tunnel endpoint.
+	URL string
+	// Namespace is the namespace of the ClusterDeployment of the cluster.
+	Namespace string
+	// Name is the name of the ClusterDeployment of the cluster.
+	Name string
+	// Token authenticates the agent.
+	Token string
+	// RootCAs are the certificate authorities trusted when connecting to the tunnel endpoint.
+	RootCAs *x509.CertPool
+	// APIServerAddress is the address of the API server to which the streams are forwarded.
+	APIServerAddress string
+
+	Logger log.FieldLogger
+}
+
+// LoadAgent returns an Agent configured from a directory holding the keys of the agent secret as files, forwarding
+// streams to the API server of the cluster the agent runs in.
+func LoadAgent(dir string, logger log.FieldLogger) (*Agent, error) {
+	read := func(key string) (string, error) {
+		b, err := os.ReadFile(filepath.Join(dir, key))
+		return strings.TrimSpace(string(b)), err
+	}
+	a := &Agent{Logger: logger}
+	var err error
+	for key, value := range map[string]*string{
+		AgentURLKey:       &a.URL,
+		AgentNamespaceKey: &a.Namespace,
+		AgentNameKey:      &a.Name,
+		AgentTokenKey:     &a.Token,
+	} {
+		if *value, err = read(key); err != nil {
+			return nil, errors.Wrapf(err, "could not read %s", key)
+		}
+	}
+	if a.RootCAs, err = x509.SystemCertPool(); err != nil {
+		return nil, errors.Wrap(err, "could not load system certificate authorities")
+	}
+	if ca, err := os.ReadFile(filepath.Join(dir, AgentCAKey)); err == nil && len(ca) > 0 {
+		if !a.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, errors.New("invalid certificate authorities")
+		}
+	}
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a cluster: KUBERNETES_SERVICE_HOST and KUBERNETES_SERVICE_PORT are not set")
+	}
+	a.APIServerAddress = net.JoinHostPort(host, port)
+	return a, nil
+}
+
+// Run keeps the agent connected to the tunnel endpoint until the context is done, reconnecting with a backoff.
+func (a *Agent) Run(ctx context.Context) {
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 7, Cap: time.Minute}
+	for ctx.Err() == nil {
+		start := time.Now()
+		err := a.serve(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(start) > time.Minute {
+			backoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 7, Cap: time.Minute}
+		}
+		delay := backoff.Step()
+		a.Logger.WithError(err).WithField("delay", delay).Warn("disconnected from the API server tunnel endpoint")
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+	}
+}
+
+// serve connects to the tunnel endpoint and forwards streams until the connection or the context is closed.
+func (a *Agent) serve(ctx context.Context) error {
+	rt, err := spdy.NewRoundTripperWithConfig(spdy.RoundTripperConfig{
+		TLS:     &tls.Config{RootCAs: a.RootCAs, MinVersion: tls.VersionTLS12},
+		Proxier: http.ProxyFromEnvironment,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s%s%s/%s", strings.TrimSuffix(a.URL, "/"), agentPathPrefix, a.Namespace, a.Name), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	req.Header.Set(httpstream.HeaderConnection, httpstream.HeaderUpgrade)
+	req.Header.Set(httpstream.HeaderUpgrade, spdy.HeaderSpdy31)
+	conn, err := rt.Dial(req)
+	if err != nil {
+		return errors.Wrap(err, "could not connect to the API server tunnel endpoint")
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return errors.Wrap(err, "could not read the response of the API server tunnel endpoint")
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		conn.Close()
+		return fmt.Errorf("API server tunnel endpoint responded %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	// The agent is the server side of the SPDY connection, accepting the streams created by the tunnel endpoint.
+	var tunnel httpstream.Connection
+	ready := make(chan struct{})
+	tunnel, err = spdy.NewServerConnection(bufferedConn(conn, reader), func(stream httpstream.Stream, replySent <-chan struct{}) error {
+		go func() {
+			<-replySent
+			<-ready
+			a.forward(stream)
+			tunnel.RemoveStreams(stream)
+		}()
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not establish the API server tunnel")
+	}
+	close(ready)
+	a.Logger.Info("connected to the API server tunnel endpoint")
+	select {
+	case <-ctx.Done():
+		tunnel.Close()
+		return ctx.Err()
+	case <-tunnel.CloseChan():
+		return errors.New("connection closed")
+	}
+}
+
+// forward copies data between a stream and a new connection to the API server.
+func (a *Agent) forward(stream httpstream.Stream) {
+	conn, err := net.DialTimeout("tcp", a.APIServerAddress, 30*time.Second)
+	if err != nil {
+		a.Logger.WithError(err).Warn("could not connect to the API server")
+		stream.Reset()
+		return
+	}
+	splice(conn, stream)
+}
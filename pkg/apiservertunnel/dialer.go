@@ -0,0 +1,87 @@
+package apiservertunnel
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// ProxyAddress returns the address of the proxy of the tunnel endpoint, served by hive-controllers.
+func ProxyAddress() string {
+	return net.JoinHostPort(fmt.Sprintf("hive-controllers.%s.svc", controllerutils.GetHiveNamespace()), fmt.Sprint(ProxyPort))
+}
+
+// ProxyToken returns the token with which Hive controllers authenticate with the proxy, and the resource version of
+// the secret holding it.
+func ProxyToken(c client.Client) (string, string, error) {
+	secret := &corev1.Secret{}
+	key := types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: ProxyTokenSecretName}
+	if err := c.Get(context.TODO(), key, secret); err != nil {
+		return "", "", errors.Wrap(err, "could not get the API server tunnel proxy token")
+	}
+	token := string(secret.Data[ProxyTokenKey])
+	if token == "" {
+		return "", "", errors.Errorf("secret %s has no %s key", key, ProxyTokenKey)
+	}
+	return token, secret.ResourceVersion, nil
+}
+
+// NewDialer returns a function dialing connections to the API server of the cluster of a ClusterDeployment through
+// the tunnel opened by its agent, authenticating with the proxy with the given token.
+func NewDialer(token, namespace, name string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return newDialer(ProxyAddress(), token, namespace, name)
+}
+
+func newDialer(proxyAddress, token, namespace, name string) func(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dialer.DialContext(ctx, "tcp", proxyAddress)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not connect to the API server tunnel proxy")
+		}
+		conn.SetDeadline(time.Now().Add(30 * time.Second))
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: address},
+			Host:   address,
+			Header: http.Header{
+				ClusterDeploymentHeader: []string{namespace + "/" + name},
+				"Proxy-Authorization":   []string{"Bearer " + token},
+			},
+		}
+		if err := req.Write(conn); err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "could not connect to the API server tunnel proxy")
+		}
+		reader := bufio.NewReader(conn)
+		resp, err := http.ReadResponse(reader, req)
+		if err != nil {
+			conn.Close()
+			return nil, errors.Wrap(err, "could not read the response of the API server tunnel proxy")
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			conn.Close()
+			return nil, errors.New(strings.TrimSpace(string(body)))
+		}
+		conn.SetDeadline(time.Time{})
+		return bufferedConn(conn, reader), nil
+	}
+}
@@ -0,0 +1,88 @@
+package apiservertunnel
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// AgentNamespace is the namespace of the agent on the remote cluster.
+	AgentNamespace = "openshift-hive-apiserver-tunnel"
+
+	// AgentName is the name of the deployment, service account and secret of the agent on the remote cluster.
+	AgentName = "hive-apiserver-tunnel-agent"
+
+	// agentConfigDir is where the agent secret is mounted in the agent container.
+	agentConfigDir = "/etc/hive-apiserver-tunnel"
+)
+
+// AgentSecretName returns the name of the secret, in the namespace of the ClusterDeployment, holding the configuration
+// of its agent. It is also the name of the SyncSet syncing the agent to the remote cluster.
+func AgentSecretName(clusterDeploymentName string) string {
+	return clusterDeploymentName + "-apiserver-tunnel"
+}
+
+// AgentResources returns the resources running the agent on the remote cluster, except for its secret.
+func AgentResources(image string, pullPolicy corev1.PullPolicy) []runtime.Object {
+	labels := map[string]string{"app": AgentName}
+	return []runtime.Object{
+		&corev1.Namespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: AgentNamespace},
+		},
+		&corev1.ServiceAccount{
+			TypeMeta:                     metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta:                   metav1.ObjectMeta{Namespace: AgentNamespace, Name: AgentName},
+			AutomountServiceAccountToken: ptr.To(false),
+		},
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: AgentNamespace, Name: AgentName, Labels: labels},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						ServiceAccountName:           AgentName,
+						AutomountServiceAccountToken: ptr.To(false),
+						Containers: []corev1.Container{{
+							Name:            "agent",
+							Image:           image,
+							ImagePullPolicy: pullPolicy,
+							Command:         []string{"/usr/bin/hiveutil", "apiserver-tunnel", "agent", "--config-dir=" + agentConfigDir},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("10m"),
+									corev1.ResourceMemory: resource.MustParse("50Mi"),
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{{
+								Name:      "config",
+								MountPath: agentConfigDir,
+								ReadOnly:  true,
+							}},
+							SecurityContext: &corev1.SecurityContext{
+								AllowPrivilegeEscalation: ptr.To(false),
+								ReadOnlyRootFilesystem:   ptr.To(true),
+								RunAsNonRoot:             ptr.To(true),
+								Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+								SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+							},
+						}},
+						Volumes: []corev1.Volume{{
+							Name: "config",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{SecretName: AgentName},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,373 @@
+// Package apiservertunnel implements reverse tunnels through which Hive connects to the API servers of remote clusters
+// it cannot reach directly. An agent running on the remote cluster connects to the tunnel endpoint served by
+// hive-controllers and upgrades the connection to SPDY. Hive controllers then connect to the API server of the remote
+// cluster through an HTTP CONNECT proxy, also served by hive-controllers and authenticated with a token kept in the hive
+// namespace, which opens a stream on the connection of the agent for each proxied connection. The agent forwards each stream to the API server of its cluster.
+package apiservertunnel
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// AgentPort is the port of the endpoint to which the agents connect.
+	AgentPort = 8443
+
+	// ProxyPort is the port of the proxy through which Hive controllers connect to the remote clusters.
+	ProxyPort = 8444
+
+	// ClusterDeploymentHeader is the header of the CONNECT requests to the proxy naming the ClusterDeployment, as
+	// namespace/name, whose agent the connection is tunneled through.
+	ClusterDeploymentHeader = "X-Hive-ClusterDeployment"
+
+	// ProxyTokenSecretName is the name of the secret in the hive namespace holding the token with which Hive
+	// controllers authenticate with the proxy, under the ProxyTokenKey key. The server generates it when it starts.
+	ProxyTokenSecretName = "hive-apiserver-tunnel-proxy"
+
+	// ProxyTokenKey is the key of the token in the ProxyTokenSecretName secret.
+	ProxyTokenKey = "token"
+
+	// proxyTokenBytes is the number of random bytes of the proxy token.
+	proxyTokenBytes = 32
+
+	// agentPathPrefix is the prefix of the path to which agents connect, followed by the namespace and the name of the
+	// ClusterDeployment of the agent.
+	agentPathPrefix = "/agents/"
+
+	// pingPeriod is how often the connections of the agents are pinged, keeping them alive through load balancers.
+	pingPeriod = 30 * time.Second
+)
+
+// Server serves the tunnel endpoint to which the agents connect, and the proxy through which Hive controllers connect
+// to the remote clusters. It implements manager.Runnable.
+type Server struct {
+	client   client.Client
+	config   *hivev1.APIServerTunnelConfig
+	onChange func(types.NamespacedName)
+	logger   log.FieldLogger
+
+	// proxyToken is the token the CONNECT requests to the proxy must carry in their Proxy-Authorization header.
+	proxyToken []byte
+
+	mu     sync.Mutex
+	agents map[types.NamespacedName]httpstream.Connection
+}
+
+// ValidateConfig checks that the tunnel endpoint is not configured to serve the bearer tokens of the agents over plain
+// HTTP, unless TLS is explicitly terminated in front of it.
+func ValidateConfig(config *hivev1.APIServerTunnelConfig) error {
+	if config.ServingCertificateSecretRef == nil && !config.TLSTerminatedExternally {
+		return errors.New("the API server tunnel requires servingCertificateSecretRef, or tlsTerminatedExternally when TLS is terminated in front of the endpoint")
+	}
+	return nil
+}
+
+// NewServer returns a Server. onChange is called with the ClusterDeployment of an agent when the agent connects or
+// disconnects.
+func NewServer(c client.Client, config *hivev1.APIServerTunnelConfig, onChange func(types.NamespacedName), logger log.FieldLogger) *Server {
+	return &Server{
+		client:   c,
+		config:   config,
+		onChange: onChange,
+		logger:   logger,
+		agents:   map[types.NamespacedName]httpstream.Connection{},
+	}
+}
+
+// Connected returns whether the agent of a ClusterDeployment is connected.
+func (s *Server) Connected(cd types.NamespacedName) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.agents[cd]
+	return ok
+}
+
+// Start serves the tunnel endpoint and the proxy until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	if err := ValidateConfig(s.config); err != nil {
+		return err
+	}
+	token, err := s.ensureProxyToken(ctx)
+	if err != nil {
+		return err
+	}
+	s.proxyToken = token
+
+	agentServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", AgentPort),
+		Handler:           http.HandlerFunc(s.serveAgent),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	if ref := s.config.ServingCertificateSecretRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := s.client.Get(ctx, types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: ref.Name}, secret); err != nil {
+			return errors.Wrap(err, "could not get the serving certificate secret of the API server tunnel")
+		}
+		cert, err := tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return errors.Wrap(err, "invalid serving certificate for the API server tunnel")
+		}
+		agentServer.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	}
+	proxyServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", ProxyPort),
+		Handler:           http.HandlerFunc(s.serveProxy),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		s.logger.WithField("port", AgentPort).Info("serving API server tunnel endpoint")
+		if agentServer.TLSConfig != nil {
+			errs <- agentServer.ListenAndServeTLS("", "")
+		} else {
+			errs <- agentServer.ListenAndServe()
+		}
+	}()
+	go func() {
+		s.logger.WithField("port", ProxyPort).Info("serving API server tunnel proxy")
+		errs <- proxyServer.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	agentServer.Close()
+	proxyServer.Close()
+	// Hijacked connections are not closed with the servers.
+	s.mu.Lock()
+	for _, conn := range s.agents {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return err
+}
+
+// ensureProxyToken returns the token of the proxy, generating the ProxyTokenSecretName secret holding it if needed.
+func (s *Server) ensureProxyToken(ctx context.Context) ([]byte, error) {
+	key := types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: ProxyTokenSecretName}
+	secret := &corev1.Secret{}
+	switch err := s.client.Get(ctx, key, secret); {
+	case err == nil:
+		if token := secret.Data[ProxyTokenKey]; len(token) > 0 {
+			return token, nil
+		}
+		return nil, errors.Errorf("secret %s has no %s key", key, ProxyTokenKey)
+	case !apierrors.IsNotFound(err):
+		return nil, errors.Wrap(err, "could not get the API server tunnel proxy token secret")
+	}
+
+	token := make([]byte, proxyTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data:       map[string][]byte{ProxyTokenKey: []byte(hex.EncodeToString(token))},
+	}
+	if err := s.client.Create(ctx, secret); err != nil {
+		return nil, errors.Wrap(err, "could not create the API server tunnel proxy token secret")
+	}
+	s.logger.WithField("secret", key).Info("created API server tunnel proxy token")
+	return secret.Data[ProxyTokenKey], nil
+}
+
+// serveAgent authenticates an agent and upgrades its connection to SPDY. The server is the client side of the SPDY
+// connection, creating a stream for each proxied connection.
+func (s *Server) serveAgent(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, agentPathPrefix), "/")
+	if !strings.HasPrefix(req.URL.Path, agentPathPrefix) || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.NotFound(w, req)
+		return
+	}
+	cd := types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	logger := s.logger.WithField("clusterDeployment", cd)
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		http.Error(w, "missing bearer token", http.StatusUnauthorized)
+		return
+	}
+	if err := s.authenticate(req.Context(), cd, token); err != nil {
+		logger.WithError(err).Warn("rejecting API server tunnel agent")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !httpstream.IsUpgradeRequest(req) || !strings.EqualFold(req.Header.Get(httpstream.HeaderUpgrade), spdy.HeaderSpdy31) {
+		http.Error(w, "expected an upgrade to "+spdy.HeaderSpdy31, http.StatusBadRequest)
+		return
+	}
+
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		logger.WithError(err).Error("could not hijack API server tunnel agent connection")
+		return
+	}
+	if _, err := fmt.Fprintf(conn, "HTTP/1.1 101 Switching Protocols\r\n%s: %s\r\n%s: %s\r\n\r\n",
+		httpstream.HeaderConnection, httpstream.HeaderUpgrade, httpstream.HeaderUpgrade, spdy.HeaderSpdy31); err != nil {
+		logger.WithError(err).Warn("could not upgrade API server tunnel agent connection")
+		conn.Close()
+		return
+	}
+	agent, err := spdy.NewClientConnectionWithPings(bufferedConn(conn, buf.Reader), pingPeriod)
+	if err != nil {
+		logger.WithError(err).Warn("could not establish API server tunnel")
+		return
+	}
+
+	s.mu.Lock()
+	if previous, ok := s.agents[cd]; ok {
+		previous.Close()
+	}
+	s.agents[cd] = agent
+	s.mu.Unlock()
+	logger.Info("API server tunnel agent connected")
+	s.onChange(cd)
+
+	go func() {
+		<-agent.CloseChan()
+		s.mu.Lock()
+		current := s.agents[cd] == agent
+		if current {
+			delete(s.agents, cd)
+		}
+		s.mu.Unlock()
+		if current {
+			logger.Info("API server tunnel agent disconnected")
+			s.onChange(cd)
+		}
+	}()
+}
+
+// authenticate checks that the ClusterDeployment has the API server tunnel enabled and that the token is the one of
+// its agent.
+func (s *Server) authenticate(ctx context.Context, key types.NamespacedName, token string) error {
+	cd := &hivev1.ClusterDeployment{}
+	if err := s.client.Get(ctx, key, cd); err != nil {
+		return errors.Wrap(err, "could not get ClusterDeployment")
+	}
+	if !cd.Spec.ControlPlaneConfig.APIServerTunnel {
+		return errors.New("the API server tunnel is not enabled")
+	}
+	secret := &corev1.Secret{}
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: key.Namespace, Name: AgentSecretName(key.Name)}, secret); err != nil {
+		return errors.Wrap(err, "could not get agent secret")
+	}
+	expected := secret.Data[AgentTokenKey]
+	if len(expected) == 0 || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// serveProxy tunnels a CONNECT request through the agent of the ClusterDeployment named by the
+// ClusterDeploymentHeader, once it is authenticated with the proxy token. The target of the request is ignored: the
+// agent always connects to the API server of its cluster.
+func (s *Server) serveProxy(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Proxy-Authorization"), "Bearer ")
+	if !ok || len(s.proxyToken) == 0 || subtle.ConstantTimeCompare(s.proxyToken, []byte(token)) != 1 {
+		http.Error(w, "invalid or missing proxy token", http.StatusProxyAuthRequired)
+		return
+	}
+	namespace, name, ok := strings.Cut(req.Header.Get(ClusterDeploymentHeader), "/")
+	if !ok || namespace == "" || name == "" {
+		http.Error(w, "missing or invalid "+ClusterDeploymentHeader+" header", http.StatusBadRequest)
+		return
+	}
+	cd := types.NamespacedName{Namespace: namespace, Name: name}
+
+	s.mu.Lock()
+	agent, ok := s.agents[cd]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, fmt.Sprintf("no API server tunnel agent is connected for %s", cd), http.StatusServiceUnavailable)
+		return
+	}
+	stream, err := agent.CreateStream(http.Header{})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not open a stream to the API server tunnel agent of %s: %v", cd, err), http.StatusBadGateway)
+		return
+	}
+	defer agent.RemoveStreams(stream)
+
+	conn, buf, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		s.logger.WithField("clusterDeployment", cd).WithError(err).Error("could not hijack API server tunnel proxy connection")
+		stream.Reset()
+		return
+	}
+	if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		stream.Reset()
+		conn.Close()
+		return
+	}
+	splice(bufferedConn(conn, buf.Reader), stream)
+}
+
+// splice copies data between a connection and a stream until either is closed, then closes both.
+func splice(conn net.Conn, stream httpstream.Stream) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(stream, conn)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(conn, stream)
+		done <- struct{}{}
+	}()
+	<-done
+	stream.Reset()
+	conn.Close()
+	<-done
+}
+
+// bufferedConn returns a connection reading first the data already buffered by reader.
+func bufferedConn(conn net.Conn, reader *bufio.Reader) net.Conn {
+	if reader == nil || reader.Buffered() == 0 {
+		return conn
+	}
+	return &readerConn{Conn: conn, reader: reader}
+}
+
+type readerConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *readerConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
@@ -0,0 +1,205 @@
+package apiservertunnel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	testName       = "test-cluster"
+	testToken      = "test-token"
+	testProxyToken = "test-proxy-token"
+)
+
+func withAPIServerTunnel(cd *hivev1.ClusterDeployment) {
+	cd.Spec.ControlPlaneConfig.APIServerTunnel = true
+}
+
+func TestTunnel(t *testing.T) {
+	cases := []struct {
+		name          string
+		existing      []runtime.Object
+		agentToken    string
+		expectConnect bool
+	}{
+		{
+			name: "connected",
+			existing: []runtime.Object{
+				testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(withAPIServerTunnel),
+				testsecret.FullBuilder(testNamespace, AgentSecretName(testName), scheme.GetScheme()).Build(
+					testsecret.WithDataKeyValue(AgentTokenKey, []byte(testToken))),
+			},
+			agentToken:    testToken,
+			expectConnect: true,
+		},
+		{
+			name: "invalid token",
+			existing: []runtime.Object{
+				testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(withAPIServerTunnel),
+				testsecret.FullBuilder(testNamespace, AgentSecretName(testName), scheme.GetScheme()).Build(
+					testsecret.WithDataKeyValue(AgentTokenKey, []byte(testToken))),
+			},
+			agentToken: "other-token",
+		},
+		{
+			name: "tunnel not enabled",
+			existing: []runtime.Object{
+				testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(),
+				testsecret.FullBuilder(testNamespace, AgentSecretName(testName), scheme.GetScheme()).Build(
+					testsecret.WithDataKeyValue(AgentTokenKey, []byte(testToken))),
+			},
+			agentToken: testToken,
+		},
+		{
+			name: "no agent secret",
+			existing: []runtime.Object{
+				testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(withAPIServerTunnel),
+			},
+			agentToken: testToken,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := log.WithField("test", tc.name)
+			cd := types.NamespacedName{Namespace: testNamespace, Name: testName}
+			changes := make(chan types.NamespacedName, 10)
+			s := NewServer(
+				testfake.NewFakeClientBuilder().WithRuntimeObjects(tc.existing...).Build(),
+				&hivev1.APIServerTunnelConfig{},
+				func(cd types.NamespacedName) { changes <- cd },
+				logger,
+			)
+			s.proxyToken = []byte(testProxyToken)
+			agentServer := httptest.NewServer(http.HandlerFunc(s.serveAgent))
+			defer agentServer.Close()
+			proxyServer := httptest.NewServer(http.HandlerFunc(s.serveProxy))
+			defer proxyServer.Close()
+			apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				io.WriteString(w, "ok "+req.URL.Path)
+			}))
+			defer apiServer.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			agent := &Agent{
+				URL:              agentServer.URL,
+				Namespace:        testNamespace,
+				Name:             testName,
+				Token:            tc.agentToken,
+				APIServerAddress: apiServer.Listener.Addr().String(),
+				Logger:           logger,
+			}
+			agentDone := make(chan struct{})
+			go func() {
+				agent.Run(ctx)
+				close(agentDone)
+			}()
+
+			client := &http.Client{
+				Transport: &http.Transport{DialContext: newDialer(proxyServer.Listener.Addr().String(), testProxyToken, testNamespace, testName)},
+				Timeout:   10 * time.Second,
+			}
+			if !tc.expectConnect {
+				err := agent.serve(context.Background())
+				if assert.Error(t, err, "expected the agent to be rejected") {
+					assert.Contains(t, err.Error(), "401 Unauthorized")
+				}
+				assert.False(t, s.Connected(cd), "expected the agent not to be connected")
+				_, err = client.Get("http://api.test-cluster.example.com:6443/version")
+				if assert.Error(t, err, "expected the proxy to fail without agent") {
+					assert.Contains(t, err.Error(), "no API server tunnel agent is connected for test-namespace/test-cluster")
+				}
+				cancel()
+				<-agentDone
+				return
+			}
+
+			select {
+			case changed := <-changes:
+				assert.Equal(t, cd, changed, "unexpected change")
+			case <-time.After(10 * time.Second):
+				t.Fatal("agent did not connect")
+			}
+			assert.True(t, s.Connected(cd), "expected the agent to be connected")
+
+			unauthenticated := &http.Client{
+				Transport: &http.Transport{DialContext: newDialer(proxyServer.Listener.Addr().String(), "other-token", testNamespace, testName)},
+				Timeout:   10 * time.Second,
+			}
+			_, err := unauthenticated.Get("http://api.test-cluster.example.com:6443/version")
+			if assert.Error(t, err, "expected the proxy to reject an invalid token") {
+				assert.Contains(t, err.Error(), "invalid or missing proxy token")
+			}
+
+			for _, path := range []string{"/version", "/apis"} {
+				resp, err := client.Get("http://api.test-cluster.example.com:6443" + path)
+				require.NoError(t, err, "unexpected error through the tunnel")
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				require.NoError(t, err)
+				assert.Equal(t, "ok "+path, string(body), "unexpected response through the tunnel")
+			}
+
+			cancel()
+			<-agentDone
+			select {
+			case changed := <-changes:
+				assert.Equal(t, cd, changed, "unexpected change")
+			case <-time.After(10 * time.Second):
+				t.Fatal("agent did not disconnect")
+			}
+			assert.False(t, s.Connected(cd), "expected the agent to be disconnected")
+			_, err = client.Get("http://api.test-cluster.example.com:6443/version")
+			if assert.Error(t, err, "expected the proxy to fail after the agent disconnected") {
+				assert.True(t, strings.Contains(err.Error(), "no API server tunnel agent is connected"), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	assert.Error(t, ValidateConfig(&hivev1.APIServerTunnelConfig{URL: "http://hive-tunnel.example.com"}),
+		"expected plain HTTP to be rejected")
+	assert.NoError(t, ValidateConfig(&hivev1.APIServerTunnelConfig{URL: "https://hive-tunnel.example.com", TLSTerminatedExternally: true}),
+		"unexpected error with TLS terminated externally")
+	assert.NoError(t, ValidateConfig(&hivev1.APIServerTunnelConfig{
+		URL:                         "https://hive-tunnel.example.com",
+		ServingCertificateSecretRef: &corev1.LocalObjectReference{Name: "serving-cert"},
+	}), "unexpected error with a serving certificate")
+}
+
+func TestEnsureProxyToken(t *testing.T) {
+	c := testfake.NewFakeClientBuilder().Build()
+	s := NewServer(c, &hivev1.APIServerTunnelConfig{}, func(types.NamespacedName) {}, log.WithField("test", "proxy-token"))
+
+	token, err := s.ensureProxyToken(context.Background())
+	require.NoError(t, err, "unexpected error generating the proxy token")
+	assert.Len(t, token, 2*proxyTokenBytes, "unexpected proxy token length")
+
+	again, err := s.ensureProxyToken(context.Background())
+	require.NoError(t, err, "unexpected error getting the proxy token")
+	assert.Equal(t, token, again, "expected the proxy token to be kept")
+
+	fromSecret, _, err := ProxyToken(c)
+	require.NoError(t, err, "unexpected error reading the proxy token")
+	assert.Equal(t, string(token), fromSecret, "unexpected proxy token read by the dialer")
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/sts/stsiface"
 
@@ -69,6 +70,8 @@ type Client interface {
 	CreateRoute(*ec2.CreateRouteInput) (*ec2.CreateRouteOutput, error)
 	DeleteRoute(*ec2.DeleteRouteInput) (*ec2.DeleteRouteOutput, error)
 	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	DescribeInstancesPages(*ec2.DescribeInstancesInput, func(*ec2.DescribeInstancesOutput, bool) bool) error
+	DescribeInstanceTypes(*ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error)
 	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
 	TerminateInstances(*ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
 	StartInstances(*ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error)
@@ -76,6 +79,8 @@ type Client interface {
 	AuthorizeSecurityGroupIngress(*ec2.AuthorizeSecurityGroupIngressInput) (*ec2.AuthorizeSecurityGroupIngressOutput, error)
 	RevokeSecurityGroupIngress(*ec2.RevokeSecurityGroupIngressInput) (*ec2.RevokeSecurityGroupIngressOutput, error)
 	DescribeVpcs(*ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error)
+	DescribeAccountAttributes(*ec2.DescribeAccountAttributesInput) (*ec2.DescribeAccountAttributesOutput, error)
+	DescribeAddresses(*ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error)
 	CreateVpcPeeringConnection(*ec2.CreateVpcPeeringConnectionInput) (*ec2.CreateVpcPeeringConnectionOutput, error)
 	DescribeVpcPeeringConnections(*ec2.DescribeVpcPeeringConnectionsInput) (*ec2.DescribeVpcPeeringConnectionsOutput, error)
 	AcceptVpcPeeringConnection(*ec2.AcceptVpcPeeringConnectionInput) (*ec2.AcceptVpcPeeringConnectionOutput, error)
@@ -95,6 +100,7 @@ type Client interface {
 	DescribeNetworkInterfaces(input *ec2.DescribeNetworkInterfacesInput) (*ec2.DescribeNetworkInterfacesOutput, error)
 	CreateVpcEndpoint(*ec2.CreateVpcEndpointInput) (*ec2.CreateVpcEndpointOutput, error)
 	DeleteVpcEndpoints(*ec2.DeleteVpcEndpointsInput) (*ec2.DeleteVpcEndpointsOutput, error)
+	DescribeTagsPages(*ec2.DescribeTagsInput, func(*ec2.DescribeTagsOutput, bool) bool) error
 
 	// ELBV2
 	DescribeLoadBalancers(*elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error)
@@ -122,6 +128,10 @@ type Client interface {
 	// ResourceTagging
 	GetResourcesPages(input *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error
 
+	// ServiceQuotas
+	GetServiceQuota(*servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error)
+	GetAWSDefaultServiceQuota(*servicequotas.GetAWSDefaultServiceQuotaInput) (*servicequotas.GetAWSDefaultServiceQuotaOutput, error)
+
 	// STS
 	GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
 }
@@ -135,6 +145,7 @@ type awsClient struct {
 	s3Uploader    *s3manager.Uploader
 	stsClient     stsiface.STSAPI
 	tagClient     *resourcegroupstaggingapi.ResourceGroupsTaggingAPI
+	quotasClient  *servicequotas.ServiceQuotas
 }
 
 func (c *awsClient) DescribeAvailabilityZones(input *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
@@ -177,6 +188,16 @@ func (c *awsClient) DescribeInstances(input *ec2.DescribeInstancesInput) (*ec2.D
 	return c.ec2Client.DescribeInstances(input)
 }
 
+func (c *awsClient) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	metricAWSAPICalls.WithLabelValues("DescribeInstancesPages").Inc()
+	return c.ec2Client.DescribeInstancesPages(input, fn)
+}
+
+func (c *awsClient) DescribeInstanceTypes(input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeInstanceTypes").Inc()
+	return c.ec2Client.DescribeInstanceTypes(input)
+}
+
 func (c *awsClient) StopInstances(input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
 	metricAWSAPICalls.WithLabelValues("StopInstances").Inc()
 	return c.ec2Client.StopInstances(input)
@@ -297,11 +318,26 @@ func (c *awsClient) DeleteVpcEndpoints(input *ec2.DeleteVpcEndpointsInput) (*ec2
 	return c.ec2Client.DeleteVpcEndpoints(input)
 }
 
+func (c *awsClient) DescribeTagsPages(input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool) error {
+	metricAWSAPICalls.WithLabelValues("DescribeTagsPages").Inc()
+	return c.ec2Client.DescribeTagsPages(input, fn)
+}
+
 func (c *awsClient) DescribeVpcs(input *ec2.DescribeVpcsInput) (*ec2.DescribeVpcsOutput, error) {
 	metricAWSAPICalls.WithLabelValues("DescribeVpcs").Inc()
 	return c.ec2Client.DescribeVpcs(input)
 }
 
+func (c *awsClient) DescribeAccountAttributes(input *ec2.DescribeAccountAttributesInput) (*ec2.DescribeAccountAttributesOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeAccountAttributes").Inc()
+	return c.ec2Client.DescribeAccountAttributes(input)
+}
+
+func (c *awsClient) DescribeAddresses(input *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	metricAWSAPICalls.WithLabelValues("DescribeAddresses").Inc()
+	return c.ec2Client.DescribeAddresses(input)
+}
+
 func (c *awsClient) DescribeLoadBalancers(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
 	metricAWSAPICalls.WithLabelValues("DescribeLoadBalancers").Inc()
 	return c.elbv2Client.DescribeLoadBalancers(input)
@@ -355,6 +391,16 @@ func (c *awsClient) GetResourcesPages(input *resourcegroupstaggingapi.GetResourc
 	return c.tagClient.GetResourcesPages(input, fn)
 }
 
+func (c *awsClient) GetServiceQuota(input *servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error) {
+	metricAWSAPICalls.WithLabelValues("GetServiceQuota").Inc()
+	return c.quotasClient.GetServiceQuota(input)
+}
+
+func (c *awsClient) GetAWSDefaultServiceQuota(input *servicequotas.GetAWSDefaultServiceQuotaInput) (*servicequotas.GetAWSDefaultServiceQuotaOutput, error) {
+	metricAWSAPICalls.WithLabelValues("GetAWSDefaultServiceQuota").Inc()
+	return c.quotasClient.GetAWSDefaultServiceQuota(input)
+}
+
 func (c *awsClient) ListResourceRecordSets(input *route53.ListResourceRecordSetsInput) (*route53.ListResourceRecordSetsOutput, error) {
 	metricAWSAPICalls.WithLabelValues("ListResourceRecordSets").Inc()
 	return c.route53Client.ListResourceRecordSets(input)
@@ -583,6 +629,7 @@ func newClientFromSession(s *session.Session, cfgs ...*aws.Config) (Client, erro
 		route53Client: route53.New(s, cfgs...),
 		stsClient:     sts.New(s, cfgs...),
 		tagClient:     resourcegroupstaggingapi.New(s, cfgs...),
+		quotasClient:  servicequotas.New(s, cfgs...),
 	}, nil
 }
 
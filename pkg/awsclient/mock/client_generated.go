@@ -13,6 +13,7 @@ import (
 	route53 "github.com/aws/aws-sdk-go/service/route53"
 	s3iface "github.com/aws/aws-sdk-go/service/s3/s3iface"
 	s3manager "github.com/aws/aws-sdk-go/service/s3/s3manager"
+	servicequotas "github.com/aws/aws-sdk-go/service/servicequotas"
 	sts "github.com/aws/aws-sdk-go/service/sts"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -295,6 +296,36 @@ func (mr *MockClientMockRecorder) DeleteVpcPeeringConnection(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVpcPeeringConnection", reflect.TypeOf((*MockClient)(nil).DeleteVpcPeeringConnection), arg0)
 }
 
+// DescribeAccountAttributes mocks base method.
+func (m *MockClient) DescribeAccountAttributes(arg0 *ec2.DescribeAccountAttributesInput) (*ec2.DescribeAccountAttributesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAccountAttributes", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeAccountAttributesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAccountAttributes indicates an expected call of DescribeAccountAttributes.
+func (mr *MockClientMockRecorder) DescribeAccountAttributes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAccountAttributes", reflect.TypeOf((*MockClient)(nil).DescribeAccountAttributes), arg0)
+}
+
+// DescribeAddresses mocks base method.
+func (m *MockClient) DescribeAddresses(arg0 *ec2.DescribeAddressesInput) (*ec2.DescribeAddressesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAddresses", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeAddressesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddresses indicates an expected call of DescribeAddresses.
+func (mr *MockClientMockRecorder) DescribeAddresses(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddresses", reflect.TypeOf((*MockClient)(nil).DescribeAddresses), arg0)
+}
+
 // DescribeAvailabilityZones mocks base method.
 func (m *MockClient) DescribeAvailabilityZones(arg0 *ec2.DescribeAvailabilityZonesInput) (*ec2.DescribeAvailabilityZonesOutput, error) {
 	m.ctrl.T.Helper()
@@ -310,6 +341,21 @@ func (mr *MockClientMockRecorder) DescribeAvailabilityZones(arg0 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAvailabilityZones", reflect.TypeOf((*MockClient)(nil).DescribeAvailabilityZones), arg0)
 }
 
+// DescribeInstanceTypes mocks base method.
+func (m *MockClient) DescribeInstanceTypes(arg0 *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeInstanceTypes", arg0)
+	ret0, _ := ret[0].(*ec2.DescribeInstanceTypesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInstanceTypes indicates an expected call of DescribeInstanceTypes.
+func (mr *MockClientMockRecorder) DescribeInstanceTypes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceTypes", reflect.TypeOf((*MockClient)(nil).DescribeInstanceTypes), arg0)
+}
+
 // DescribeInstances mocks base method.
 func (m *MockClient) DescribeInstances(arg0 *ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error) {
 	m.ctrl.T.Helper()
@@ -325,6 +371,20 @@ func (mr *MockClientMockRecorder) DescribeInstances(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstances", reflect.TypeOf((*MockClient)(nil).DescribeInstances), arg0)
 }
 
+// DescribeInstancesPages mocks base method.
+func (m *MockClient) DescribeInstancesPages(arg0 *ec2.DescribeInstancesInput, arg1 func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeInstancesPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeInstancesPages indicates an expected call of DescribeInstancesPages.
+func (mr *MockClientMockRecorder) DescribeInstancesPages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstancesPages", reflect.TypeOf((*MockClient)(nil).DescribeInstancesPages), arg0, arg1)
+}
+
 // DescribeLoadBalancers mocks base method.
 func (m *MockClient) DescribeLoadBalancers(arg0 *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
 	m.ctrl.T.Helper()
@@ -428,6 +488,20 @@ func (mr *MockClientMockRecorder) DescribeSubnetsPages(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSubnetsPages", reflect.TypeOf((*MockClient)(nil).DescribeSubnetsPages), arg0, arg1)
 }
 
+// DescribeTagsPages mocks base method.
+func (m *MockClient) DescribeTagsPages(arg0 *ec2.DescribeTagsInput, arg1 func(*ec2.DescribeTagsOutput, bool) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeTagsPages", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DescribeTagsPages indicates an expected call of DescribeTagsPages.
+func (mr *MockClientMockRecorder) DescribeTagsPages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTagsPages", reflect.TypeOf((*MockClient)(nil).DescribeTagsPages), arg0, arg1)
+}
+
 // DescribeVpcEndpointServiceConfigurations mocks base method.
 func (m *MockClient) DescribeVpcEndpointServiceConfigurations(arg0 *ec2.DescribeVpcEndpointServiceConfigurationsInput) (*ec2.DescribeVpcEndpointServiceConfigurationsOutput, error) {
 	m.ctrl.T.Helper()
@@ -547,6 +621,21 @@ func (mr *MockClientMockRecorder) DisassociateVPCFromHostedZone(input interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisassociateVPCFromHostedZone", reflect.TypeOf((*MockClient)(nil).DisassociateVPCFromHostedZone), input)
 }
 
+// GetAWSDefaultServiceQuota mocks base method.
+func (m *MockClient) GetAWSDefaultServiceQuota(arg0 *servicequotas.GetAWSDefaultServiceQuotaInput) (*servicequotas.GetAWSDefaultServiceQuotaOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAWSDefaultServiceQuota", arg0)
+	ret0, _ := ret[0].(*servicequotas.GetAWSDefaultServiceQuotaOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAWSDefaultServiceQuota indicates an expected call of GetAWSDefaultServiceQuota.
+func (mr *MockClientMockRecorder) GetAWSDefaultServiceQuota(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAWSDefaultServiceQuota", reflect.TypeOf((*MockClient)(nil).GetAWSDefaultServiceQuota), arg0)
+}
+
 // GetCallerIdentity mocks base method.
 func (m *MockClient) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
 	m.ctrl.T.Helper()
@@ -605,6 +694,21 @@ func (mr *MockClientMockRecorder) GetS3API() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetS3API", reflect.TypeOf((*MockClient)(nil).GetS3API))
 }
 
+// GetServiceQuota mocks base method.
+func (m *MockClient) GetServiceQuota(arg0 *servicequotas.GetServiceQuotaInput) (*servicequotas.GetServiceQuotaOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceQuota", arg0)
+	ret0, _ := ret[0].(*servicequotas.GetServiceQuotaOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceQuota indicates an expected call of GetServiceQuota.
+func (mr *MockClientMockRecorder) GetServiceQuota(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceQuota", reflect.TypeOf((*MockClient)(nil).GetServiceQuota), arg0)
+}
+
 // ListHostedZonesByName mocks base method.
 func (m *MockClient) ListHostedZonesByName(input *route53.ListHostedZonesByNameInput) (*route53.ListHostedZonesByNameOutput, error) {
 	m.ctrl.T.Helper()
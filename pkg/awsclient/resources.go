@@ -0,0 +1,200 @@
+package awsclient
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ec2FilterBatchSize is the number of resource IDs passed in a single EC2 filter.
+const ec2FilterBatchSize = 100
+
+// GlobalRegion returns the region in which the tagging API reports the resources of global services, such as Route53
+// hosted zones, in the partition of the given region.
+func GlobalRegion(region string) string {
+	partition, _ := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	switch partition.ID() {
+	case endpoints.AwsCnPartitionID:
+		return endpoints.CnNorthwest1RegionID
+	case endpoints.AwsUsGovPartitionID:
+		return endpoints.UsGovWest1RegionID
+	default:
+		return endpoints.UsEast1RegionID
+	}
+}
+
+// ListTaggedResources returns the sorted ARNs of the resources in the region of the client that have any of the
+// given tags, the way the uninstaller matches the resources of a cluster. The tagging API only reports Route53 hosted
+// zones in the global region of the partition, so they are listed with globalClient, a client in that region. It may
+// be nil when the region of the client is the global region.
+//
+// The tagging API keeps returning resources for a while after they are deleted, so each resource is looked up
+// again with the API of its service: EC2 resources must still have tags and instances must not be terminated, and
+// load balancers, hosted zones and S3 buckets must still exist. Resources of other services are returned as listed.
+func ListTaggedResources(c, globalClient Client, tags map[string]string) ([]string, error) {
+	arns, err := listTaggedARNs(c, tags)
+	if err != nil {
+		return nil, err
+	}
+	if globalClient != nil {
+		globalARNs, err := listTaggedARNs(globalClient, tags)
+		if err != nil {
+			return nil, err
+		}
+		for _, resourceARN := range sets.List(globalARNs) {
+			if parsed, err := arn.Parse(resourceARN); err == nil && parsed.Service == route53.ServiceName {
+				arns.Insert(resourceARN)
+			}
+		}
+	}
+
+	var resources []string
+	// ec2Resources maps the IDs of the listed EC2 resources to their ARNs.
+	ec2Resources := map[string]string{}
+	for _, resourceARN := range sets.List(arns) {
+		parsed, err := arn.Parse(resourceARN)
+		if err != nil {
+			resources = append(resources, resourceARN)
+			continue
+		}
+		exists := true
+		switch parsed.Service {
+		case ec2.ServiceName:
+			if _, id, ok := strings.Cut(parsed.Resource, "/"); ok {
+				ec2Resources[id] = resourceARN
+				continue
+			}
+		case "elasticloadbalancing":
+			// Classic load balancers are named loadbalancer/<name>; only ELBv2 ones can be looked up here.
+			if strings.HasPrefix(parsed.Resource, "loadbalancer/app/") || strings.HasPrefix(parsed.Resource, "loadbalancer/net/") {
+				exists, err = loadBalancerExists(c, resourceARN)
+			}
+		case route53.ServiceName:
+			if id, ok := strings.CutPrefix(parsed.Resource, "hostedzone/"); ok {
+				exists, err = hostedZoneExists(c, id)
+			}
+		case s3.ServiceName:
+			if !strings.Contains(parsed.Resource, "/") {
+				exists, err = bucketExists(c, parsed.Resource)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not check whether %s exists: %w", resourceARN, err)
+		}
+		if exists {
+			resources = append(resources, resourceARN)
+		}
+	}
+
+	existing, err := existingEC2Resources(c, sets.List(sets.KeySet(ec2Resources)))
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range sets.List(existing) {
+		resources = append(resources, ec2Resources[id])
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// listTaggedARNs returns the ARNs of the resources in the region of the client that have any of the given tags.
+func listTaggedARNs(c Client, tags map[string]string) (sets.Set[string], error) {
+	arns := sets.New[string]()
+	for key, value := range tags {
+		err := c.GetResourcesPages(&resourcegroupstaggingapi.GetResourcesInput{
+			TagFilters: []*resourcegroupstaggingapi.TagFilter{{
+				Key:    aws.String(key),
+				Values: []*string{aws.String(value)},
+			}},
+		}, func(resp *resourcegroupstaggingapi.GetResourcesOutput, lastPage bool) bool {
+			for _, r := range resp.ResourceTagMappingList {
+				arns.Insert(aws.StringValue(r.ResourceARN))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not list resources tagged %s=%s: %w", key, value, err)
+		}
+	}
+	return arns, nil
+}
+
+// existingEC2Resources returns which of the given EC2 resource IDs still exist. EC2 stops reporting the tags of
+// resources once they are deleted, except for terminated instances, which are left out separately.
+func existingEC2Resources(c Client, ids []string) (sets.Set[string], error) {
+	existing := sets.New[string]()
+	for start := 0; start < len(ids); start += ec2FilterBatchSize {
+		batch := aws.StringSlice(ids[start:min(start+ec2FilterBatchSize, len(ids))])
+		err := c.DescribeTagsPages(&ec2.DescribeTagsInput{
+			Filters: []*ec2.Filter{{Name: aws.String("resource-id"), Values: batch}},
+		}, func(resp *ec2.DescribeTagsOutput, lastPage bool) bool {
+			for _, tag := range resp.Tags {
+				existing.Insert(aws.StringValue(tag.ResourceId))
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not describe the tags of EC2 resources: %w", err)
+		}
+		err = c.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("instance-id"), Values: batch},
+				{Name: aws.String("instance-state-name"), Values: []*string{aws.String(ec2.InstanceStateNameTerminated)}},
+			},
+		}, func(resp *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range resp.Reservations {
+				for _, instance := range reservation.Instances {
+					existing.Delete(aws.StringValue(instance.InstanceId))
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not list terminated instances: %w", err)
+		}
+	}
+	return existing, nil
+}
+
+func loadBalancerExists(c Client, loadBalancerARN string) (bool, error) {
+	_, err := c.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		LoadBalancerArns: []*string{aws.String(loadBalancerARN)},
+	})
+	return existsUnless(err, elbv2.ErrCodeLoadBalancerNotFoundException)
+}
+
+func hostedZoneExists(c Client, id string) (bool, error) {
+	_, err := c.GetHostedZone(&route53.GetHostedZoneInput{Id: aws.String(id)})
+	return existsUnless(err, route53.ErrCodeNoSuchHostedZone)
+}
+
+func bucketExists(c Client, bucket string) (bool, error) {
+	_, err := c.GetS3API().HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	// HeadBucket responses have no body, so a missing bucket is reported as NotFound.
+	return existsUnless(err, s3.ErrCodeNoSuchBucket, "NotFound")
+}
+
+// existsUnless reports whether a lookup found its resource, given that the lookup fails with one of the given
+// error codes when the resource does not exist.
+func existsUnless(err error, notFoundCodes ...string) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && sets.New(notFoundCodes...).Has(awsErr.Code()) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,161 @@
+package awsclient_test
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/awsclient/mock"
+)
+
+const (
+	vpcARN              = "arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1"
+	deletedSubnetARN    = "arn:aws:ec2:us-east-1:123456789012:subnet/subnet-1"
+	instanceARN         = "arn:aws:ec2:us-east-1:123456789012:instance/i-1"
+	terminatedARN       = "arn:aws:ec2:us-east-1:123456789012:instance/i-2"
+	loadBalancerARN     = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/net/lb-1/abc"
+	deletedLBARN        = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/lb-2/def"
+	hostedZoneARN       = "arn:aws:route53:::hostedzone/Z1"
+	deletedHostedZone   = "arn:aws:route53:::hostedzone/Z2"
+	bucketARN           = "arn:aws:s3:::bucket-1"
+	deletedBucketARN    = "arn:aws:s3:::bucket-2"
+	unknownServiceARN   = "arn:aws:iam::123456789012:role/role-1"
+	existingResourceTag = "kubernetes.io/cluster/infra-id"
+)
+
+// fakeS3 reports the buckets in existing as present.
+type fakeS3 struct {
+	s3iface.S3API
+	existing map[string]bool
+}
+
+func (f *fakeS3) HeadBucket(input *s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	if !f.existing[aws.StringValue(input.Bucket)] {
+		return nil, awserr.New("NotFound", "Not Found", nil)
+	}
+	return &s3.HeadBucketOutput{}, nil
+}
+
+func TestListTaggedResources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	c := mock.NewMockClient(mockCtrl)
+
+	c.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+			output := &resourcegroupstaggingapi.GetResourcesOutput{}
+			for _, arn := range []string{
+				vpcARN, deletedSubnetARN, instanceARN, terminatedARN, loadBalancerARN, deletedLBARN,
+				hostedZoneARN, deletedHostedZone, bucketARN, deletedBucketARN, unknownServiceARN,
+			} {
+				output.ResourceTagMappingList = append(output.ResourceTagMappingList, &resourcegroupstaggingapi.ResourceTagMapping{
+					ResourceARN: aws.String(arn),
+				})
+			}
+			fn(output, true)
+			return nil
+		})
+	c.EXPECT().DescribeTagsPages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool) error {
+			assert.ElementsMatch(t, []string{"i-1", "i-2", "subnet-1", "vpc-1"}, aws.StringValueSlice(input.Filters[0].Values))
+			// The tags of the terminated instance are still reported; those of the deleted subnet are not.
+			fn(&ec2.DescribeTagsOutput{Tags: []*ec2.TagDescription{
+				{ResourceId: aws.String("vpc-1")},
+				{ResourceId: aws.String("i-1")},
+			}}, false)
+			fn(&ec2.DescribeTagsOutput{Tags: []*ec2.TagDescription{
+				{ResourceId: aws.String("i-2")},
+			}}, true)
+			return nil
+		})
+	c.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+			fn(&ec2.DescribeInstancesOutput{}, false)
+			fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{
+				Instances: []*ec2.Instance{{InstanceId: aws.String("i-2")}},
+			}}}, true)
+			return nil
+		})
+	c.EXPECT().DescribeLoadBalancers(gomock.Any()).
+		DoAndReturn(func(input *elbv2.DescribeLoadBalancersInput) (*elbv2.DescribeLoadBalancersOutput, error) {
+			if aws.StringValue(input.LoadBalancerArns[0]) == deletedLBARN {
+				return nil, awserr.New(elbv2.ErrCodeLoadBalancerNotFoundException, "not found", nil)
+			}
+			return &elbv2.DescribeLoadBalancersOutput{}, nil
+		}).Times(2)
+	c.EXPECT().GetHostedZone(gomock.Any()).
+		DoAndReturn(func(input *route53.GetHostedZoneInput) (*route53.GetHostedZoneOutput, error) {
+			if aws.StringValue(input.Id) == "Z2" {
+				return nil, awserr.New(route53.ErrCodeNoSuchHostedZone, "not found", nil)
+			}
+			return &route53.GetHostedZoneOutput{}, nil
+		}).Times(2)
+	c.EXPECT().GetS3API().Return(&fakeS3{existing: map[string]bool{"bucket-1": true}}).Times(2)
+
+	resources, err := awsclient.ListTaggedResources(c, nil, map[string]string{existingResourceTag: "owned"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		instanceARN, vpcARN, loadBalancerARN, unknownServiceARN, hostedZoneARN, bucketARN,
+	}, resources)
+}
+
+func TestListTaggedResourcesLookupError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	c := mock.NewMockClient(mockCtrl)
+
+	c.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+			fn(&resourcegroupstaggingapi.GetResourcesOutput{ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+				{ResourceARN: aws.String(hostedZoneARN)},
+			}}, true)
+			return nil
+		})
+	c.EXPECT().GetHostedZone(gomock.Any()).Return(nil, awserr.New("AccessDenied", "denied", nil))
+
+	_, err := awsclient.ListTaggedResources(c, nil, map[string]string{existingResourceTag: "owned"})
+	assert.ErrorContains(t, err, hostedZoneARN)
+}
+
+func TestListTaggedResourcesGlobal(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	c := mock.NewMockClient(mockCtrl)
+	globalClient := mock.NewMockClient(mockCtrl)
+
+	c.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).Return(nil)
+	// Only the hosted zones are taken from the global region; its regional resources belong to other clusters.
+	globalClient.EXPECT().GetResourcesPages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+			fn(&resourcegroupstaggingapi.GetResourcesOutput{ResourceTagMappingList: []*resourcegroupstaggingapi.ResourceTagMapping{
+				{ResourceARN: aws.String(hostedZoneARN)},
+				{ResourceARN: aws.String(vpcARN)},
+			}}, true)
+			return nil
+		})
+	c.EXPECT().GetHostedZone(gomock.Any()).Return(&route53.GetHostedZoneOutput{}, nil)
+
+	resources, err := awsclient.ListTaggedResources(c, globalClient, map[string]string{existingResourceTag: "owned"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{hostedZoneARN}, resources)
+}
+
+func TestGlobalRegion(t *testing.T) {
+	for region, expected := range map[string]string{
+		"us-east-1":      "us-east-1",
+		"eu-west-1":      "us-east-1",
+		"cn-north-1":     "cn-northwest-1",
+		"us-gov-east-1":  "us-gov-west-1",
+		"unknown-region": "us-east-1",
+	} {
+		assert.Equal(t, expected, awsclient.GlobalRegion(region), "unexpected global region for %s", region)
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
@@ -48,6 +50,30 @@ type Client interface {
 
 	// Images
 	ListImagesByResourceGroup(ctx context.Context, resourceGroupName string) (ImageListResultPage, error)
+
+	// Usage
+	ListUsage(ctx context.Context, location string) (UsagePage, error)
+
+	// Generic resources, for resource types without a client of their own such as private link services
+	// and private endpoints. Properties are exchanged as JSON objects.
+	GetResourceByID(ctx context.Context, resourceID, apiVersion string) (resources.GenericResource, error)
+	CreateOrUpdateResourceByID(ctx context.Context, resourceID, apiVersion string, resource resources.GenericResource) (resources.GenericResource, error)
+	DeleteResourceByID(ctx context.Context, resourceID, apiVersion string) error
+	ListResources(ctx context.Context, resourceGroupName, filter string) (ResourcePage, error)
+
+	// Private DNS Zones
+	CreateOrUpdatePrivateZone(ctx context.Context, resourceGroupName string, zone string) (privatedns.PrivateZone, error)
+	GetPrivateZone(ctx context.Context, resourceGroupName string, zone string) (privatedns.PrivateZone, error)
+	DeletePrivateZone(ctx context.Context, resourceGroupName string, zone string) error
+	CreateOrUpdatePrivateRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType privatedns.RecordType, recordSet privatedns.RecordSet) (privatedns.RecordSet, error)
+
+	// Private DNS Zone Virtual Network Links
+	ListVirtualNetworkLinks(ctx context.Context, resourceGroupName string, zone string) (VirtualNetworkLinkPage, error)
+	CreateOrUpdateVirtualNetworkLink(ctx context.Context, resourceGroupName string, zone string, linkName string, virtualNetworkID string) (privatedns.VirtualNetworkLink, error)
+	DeleteVirtualNetworkLink(ctx context.Context, resourceGroupName string, zone string, linkName string) error
+
+	// SubscriptionID returns the ID of the subscription the client operates in.
+	SubscriptionID() string
 }
 
 // ResourceSKUsPage is a page of results from listing resource SKUs.
@@ -64,18 +90,45 @@ type RecordSetPage interface {
 	Values() []dns.RecordSet
 }
 
+// VirtualNetworkLinkPage is a page of results from listing the virtual network links of a private DNS zone.
+type VirtualNetworkLinkPage interface {
+	NextWithContext(ctx context.Context) error
+	NotDone() bool
+	Values() []privatedns.VirtualNetworkLink
+}
+
 type ImageListResultPage interface {
 	NextWithContext(ctx context.Context) error
 	NotDone() bool
 	Values() []compute.Image
 }
 
+// UsagePage is a page of results from listing compute resource usage.
+type UsagePage interface {
+	NextWithContext(ctx context.Context) error
+	NotDone() bool
+	Values() []compute.Usage
+}
+
+// ResourcePage is a page of results from listing generic resources.
+type ResourcePage interface {
+	NextWithContext(ctx context.Context) error
+	NotDone() bool
+	Values() []resources.GenericResourceExpanded
+}
+
 type azureClient struct {
 	resourceSKUsClient    *compute.ResourceSkusClient
 	recordSetsClient      *dns.RecordSetsClient
 	zonesClient           *dns.ZonesClient
 	virtualMachinesClient *compute.VirtualMachinesClient
 	imagesClient          *compute.ImagesClient
+	usageClient           *compute.UsageClient
+	resourcesClient       *resources.Client
+	privateZonesClient    *privatedns.PrivateZonesClient
+	privateRecordSets     *privatedns.RecordSetsClient
+	virtualNetworkLinks   *privatedns.VirtualNetworkLinksClient
+	subscriptionID        string
 }
 
 func (c *azureClient) ListResourceSKUs(ctx context.Context, filter string) (ResourceSKUsPage, error) {
@@ -183,6 +236,112 @@ func (c *azureClient) ListImagesByResourceGroup(ctx context.Context, resourceGro
 	return &page, err
 }
 
+// ListUsage lists the current compute resource usage and limits, such as vCPUs, in the specified location.
+func (c *azureClient) ListUsage(ctx context.Context, location string) (UsagePage, error) {
+	page, err := c.usageClient.List(ctx, location)
+	return &page, err
+}
+
+func (c *azureClient) GetResourceByID(ctx context.Context, resourceID, apiVersion string) (resources.GenericResource, error) {
+	return c.resourcesClient.GetByID(ctx, resourceID, apiVersion)
+}
+
+// CreateOrUpdateResourceByID creates or updates the resource with the given ID and waits for the operation to
+// complete.
+func (c *azureClient) CreateOrUpdateResourceByID(ctx context.Context, resourceID, apiVersion string, resource resources.GenericResource) (resources.GenericResource, error) {
+	future, err := c.resourcesClient.CreateOrUpdateByID(ctx, resourceID, apiVersion, resource)
+	if err != nil {
+		return resources.GenericResource{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, c.resourcesClient.Client); err != nil {
+		return resources.GenericResource{}, err
+	}
+	return future.Result(*c.resourcesClient)
+}
+
+// ListResources lists the resources of the resource group matching the filter, or those of the subscription when the
+// resource group name is empty.
+func (c *azureClient) ListResources(ctx context.Context, resourceGroupName, filter string) (ResourcePage, error) {
+	if resourceGroupName == "" {
+		page, err := c.resourcesClient.List(ctx, filter, "", nil)
+		return &page, err
+	}
+	page, err := c.resourcesClient.ListByResourceGroup(ctx, resourceGroupName, filter, "", nil)
+	return &page, err
+}
+
+// DeleteResourceByID deletes the resource with the given ID and waits for the operation to complete.
+func (c *azureClient) DeleteResourceByID(ctx context.Context, resourceID, apiVersion string) error {
+	future, err := c.resourcesClient.DeleteByID(ctx, resourceID, apiVersion)
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(ctx, c.resourcesClient.Client)
+}
+
+func (c *azureClient) CreateOrUpdatePrivateZone(ctx context.Context, resourceGroupName string, zone string) (privatedns.PrivateZone, error) {
+	future, err := c.privateZonesClient.CreateOrUpdate(ctx, resourceGroupName, zone, privatedns.PrivateZone{
+		Location: to.StringPtr("global"),
+	}, "", "")
+	if err != nil {
+		return privatedns.PrivateZone{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, c.privateZonesClient.Client); err != nil {
+		return privatedns.PrivateZone{}, err
+	}
+	return future.Result(*c.privateZonesClient)
+}
+
+func (c *azureClient) GetPrivateZone(ctx context.Context, resourceGroupName string, zone string) (privatedns.PrivateZone, error) {
+	return c.privateZonesClient.Get(ctx, resourceGroupName, zone)
+}
+
+func (c *azureClient) DeletePrivateZone(ctx context.Context, resourceGroupName string, zone string) error {
+	future, err := c.privateZonesClient.Delete(ctx, resourceGroupName, zone, "")
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(ctx, c.privateZonesClient.Client)
+}
+
+func (c *azureClient) CreateOrUpdatePrivateRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType privatedns.RecordType, recordSet privatedns.RecordSet) (privatedns.RecordSet, error) {
+	return c.privateRecordSets.CreateOrUpdate(ctx, resourceGroupName, zone, recordType, recordSetName, recordSet, "", "")
+}
+
+func (c *azureClient) ListVirtualNetworkLinks(ctx context.Context, resourceGroupName string, zone string) (VirtualNetworkLinkPage, error) {
+	page, err := c.virtualNetworkLinks.List(ctx, resourceGroupName, zone, nil)
+	return &page, err
+}
+
+func (c *azureClient) CreateOrUpdateVirtualNetworkLink(ctx context.Context, resourceGroupName string, zone string, linkName string, virtualNetworkID string) (privatedns.VirtualNetworkLink, error) {
+	future, err := c.virtualNetworkLinks.CreateOrUpdate(ctx, resourceGroupName, zone, linkName, privatedns.VirtualNetworkLink{
+		Location: to.StringPtr("global"),
+		VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+			VirtualNetwork:      &privatedns.SubResource{ID: to.StringPtr(virtualNetworkID)},
+			RegistrationEnabled: to.BoolPtr(false),
+		},
+	}, "", "")
+	if err != nil {
+		return privatedns.VirtualNetworkLink{}, err
+	}
+	if err := future.WaitForCompletionRef(ctx, c.virtualNetworkLinks.Client); err != nil {
+		return privatedns.VirtualNetworkLink{}, err
+	}
+	return future.Result(*c.virtualNetworkLinks)
+}
+
+func (c *azureClient) DeleteVirtualNetworkLink(ctx context.Context, resourceGroupName string, zone string, linkName string) error {
+	future, err := c.virtualNetworkLinks.Delete(ctx, resourceGroupName, zone, linkName, "")
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(ctx, c.virtualNetworkLinks.Client)
+}
+
+func (c *azureClient) SubscriptionID() string {
+	return c.subscriptionID
+}
+
 // NewClientFromSecret creates our client wrapper object for interacting with Azure. The Azure creds are read from the
 // specified secret.
 func NewClientFromSecret(secret *corev1.Secret, environmentName string) (Client, error) {
@@ -253,12 +412,33 @@ func newClient(authJSONSource func() ([]byte, error), environmentName string) (*
 	imagesClient := compute.NewImagesClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
 	imagesClient.Authorizer = authorizer
 
+	usageClient := compute.NewUsageClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
+	usageClient.Authorizer = authorizer
+
+	resourcesClient := resources.NewClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
+	resourcesClient.Authorizer = authorizer
+
+	privateZonesClient := privatedns.NewPrivateZonesClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
+	privateZonesClient.Authorizer = authorizer
+
+	privateRecordSets := privatedns.NewRecordSetsClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
+	privateRecordSets.Authorizer = authorizer
+
+	virtualNetworkLinks := privatedns.NewVirtualNetworkLinksClientWithBaseURI(env.ResourceManagerEndpoint, creds.SubscriptionID)
+	virtualNetworkLinks.Authorizer = authorizer
+
 	return &azureClient{
 		resourceSKUsClient:    &resourceSKUsClient,
 		recordSetsClient:      &recordSetsClient,
 		zonesClient:           &zonesClient,
 		virtualMachinesClient: &virtualMachinesClient,
 		imagesClient:          &imagesClient,
+		usageClient:           &usageClient,
+		resourcesClient:       &resourcesClient,
+		privateZonesClient:    &privateZonesClient,
+		privateRecordSets:     &privateRecordSets,
+		virtualNetworkLinks:   &virtualNetworkLinks,
+		subscriptionID:        creds.SubscriptionID,
 	}, nil
 }
 
@@ -10,6 +10,8 @@ import (
 
 	compute "github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2019-12-01/compute"
 	dns "github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2018-05-01/dns"
+	privatedns "github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	resources "github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	gomock "github.com/golang/mock/gomock"
 	azureclient "github.com/openshift/hive/pkg/azureclient"
 )
@@ -37,6 +39,36 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CreateOrUpdatePrivateRecordSet mocks base method.
+func (m *MockClient) CreateOrUpdatePrivateRecordSet(ctx context.Context, resourceGroupName, zone, recordSetName string, recordType privatedns.RecordType, recordSet privatedns.RecordSet) (privatedns.RecordSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdatePrivateRecordSet", ctx, resourceGroupName, zone, recordSetName, recordType, recordSet)
+	ret0, _ := ret[0].(privatedns.RecordSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdatePrivateRecordSet indicates an expected call of CreateOrUpdatePrivateRecordSet.
+func (mr *MockClientMockRecorder) CreateOrUpdatePrivateRecordSet(ctx, resourceGroupName, zone, recordSetName, recordType, recordSet interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdatePrivateRecordSet", reflect.TypeOf((*MockClient)(nil).CreateOrUpdatePrivateRecordSet), ctx, resourceGroupName, zone, recordSetName, recordType, recordSet)
+}
+
+// CreateOrUpdatePrivateZone mocks base method.
+func (m *MockClient) CreateOrUpdatePrivateZone(ctx context.Context, resourceGroupName, zone string) (privatedns.PrivateZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdatePrivateZone", ctx, resourceGroupName, zone)
+	ret0, _ := ret[0].(privatedns.PrivateZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdatePrivateZone indicates an expected call of CreateOrUpdatePrivateZone.
+func (mr *MockClientMockRecorder) CreateOrUpdatePrivateZone(ctx, resourceGroupName, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdatePrivateZone", reflect.TypeOf((*MockClient)(nil).CreateOrUpdatePrivateZone), ctx, resourceGroupName, zone)
+}
+
 // CreateOrUpdateRecordSet mocks base method.
 func (m *MockClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName, zone, recordSetName string, recordType dns.RecordType, recordSet dns.RecordSet) (dns.RecordSet, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +84,36 @@ func (mr *MockClientMockRecorder) CreateOrUpdateRecordSet(ctx, resourceGroupName
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateRecordSet", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateRecordSet), ctx, resourceGroupName, zone, recordSetName, recordType, recordSet)
 }
 
+// CreateOrUpdateResourceByID mocks base method.
+func (m *MockClient) CreateOrUpdateResourceByID(ctx context.Context, resourceID, apiVersion string, resource resources.GenericResource) (resources.GenericResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateResourceByID", ctx, resourceID, apiVersion, resource)
+	ret0, _ := ret[0].(resources.GenericResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateResourceByID indicates an expected call of CreateOrUpdateResourceByID.
+func (mr *MockClientMockRecorder) CreateOrUpdateResourceByID(ctx, resourceID, apiVersion, resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateResourceByID", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateResourceByID), ctx, resourceID, apiVersion, resource)
+}
+
+// CreateOrUpdateVirtualNetworkLink mocks base method.
+func (m *MockClient) CreateOrUpdateVirtualNetworkLink(ctx context.Context, resourceGroupName, zone, linkName, virtualNetworkID string) (privatedns.VirtualNetworkLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateVirtualNetworkLink", ctx, resourceGroupName, zone, linkName, virtualNetworkID)
+	ret0, _ := ret[0].(privatedns.VirtualNetworkLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrUpdateVirtualNetworkLink indicates an expected call of CreateOrUpdateVirtualNetworkLink.
+func (mr *MockClientMockRecorder) CreateOrUpdateVirtualNetworkLink(ctx, resourceGroupName, zone, linkName, virtualNetworkID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateVirtualNetworkLink", reflect.TypeOf((*MockClient)(nil).CreateOrUpdateVirtualNetworkLink), ctx, resourceGroupName, zone, linkName, virtualNetworkID)
+}
+
 // CreateOrUpdateZone mocks base method.
 func (m *MockClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName, zone string) (dns.Zone, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +144,20 @@ func (mr *MockClientMockRecorder) DeallocateVirtualMachine(ctx, resourceGroup, n
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeallocateVirtualMachine", reflect.TypeOf((*MockClient)(nil).DeallocateVirtualMachine), ctx, resourceGroup, name)
 }
 
+// DeletePrivateZone mocks base method.
+func (m *MockClient) DeletePrivateZone(ctx context.Context, resourceGroupName, zone string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrivateZone", ctx, resourceGroupName, zone)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePrivateZone indicates an expected call of DeletePrivateZone.
+func (mr *MockClientMockRecorder) DeletePrivateZone(ctx, resourceGroupName, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrivateZone", reflect.TypeOf((*MockClient)(nil).DeletePrivateZone), ctx, resourceGroupName, zone)
+}
+
 // DeleteRecordSet mocks base method.
 func (m *MockClient) DeleteRecordSet(ctx context.Context, resourceGroupName, zone, recordSetName string, recordType dns.RecordType) error {
 	m.ctrl.T.Helper()
@@ -96,6 +172,34 @@ func (mr *MockClientMockRecorder) DeleteRecordSet(ctx, resourceGroupName, zone,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecordSet", reflect.TypeOf((*MockClient)(nil).DeleteRecordSet), ctx, resourceGroupName, zone, recordSetName, recordType)
 }
 
+// DeleteResourceByID mocks base method.
+func (m *MockClient) DeleteResourceByID(ctx context.Context, resourceID, apiVersion string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResourceByID", ctx, resourceID, apiVersion)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResourceByID indicates an expected call of DeleteResourceByID.
+func (mr *MockClientMockRecorder) DeleteResourceByID(ctx, resourceID, apiVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResourceByID", reflect.TypeOf((*MockClient)(nil).DeleteResourceByID), ctx, resourceID, apiVersion)
+}
+
+// DeleteVirtualNetworkLink mocks base method.
+func (m *MockClient) DeleteVirtualNetworkLink(ctx context.Context, resourceGroupName, zone, linkName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVirtualNetworkLink", ctx, resourceGroupName, zone, linkName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVirtualNetworkLink indicates an expected call of DeleteVirtualNetworkLink.
+func (mr *MockClientMockRecorder) DeleteVirtualNetworkLink(ctx, resourceGroupName, zone, linkName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVirtualNetworkLink", reflect.TypeOf((*MockClient)(nil).DeleteVirtualNetworkLink), ctx, resourceGroupName, zone, linkName)
+}
+
 // DeleteZone mocks base method.
 func (m *MockClient) DeleteZone(ctx context.Context, resourceGroupName, zone string) error {
 	m.ctrl.T.Helper()
@@ -110,6 +214,36 @@ func (mr *MockClientMockRecorder) DeleteZone(ctx, resourceGroupName, zone interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteZone", reflect.TypeOf((*MockClient)(nil).DeleteZone), ctx, resourceGroupName, zone)
 }
 
+// GetPrivateZone mocks base method.
+func (m *MockClient) GetPrivateZone(ctx context.Context, resourceGroupName, zone string) (privatedns.PrivateZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateZone", ctx, resourceGroupName, zone)
+	ret0, _ := ret[0].(privatedns.PrivateZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateZone indicates an expected call of GetPrivateZone.
+func (mr *MockClientMockRecorder) GetPrivateZone(ctx, resourceGroupName, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateZone", reflect.TypeOf((*MockClient)(nil).GetPrivateZone), ctx, resourceGroupName, zone)
+}
+
+// GetResourceByID mocks base method.
+func (m *MockClient) GetResourceByID(ctx context.Context, resourceID, apiVersion string) (resources.GenericResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceByID", ctx, resourceID, apiVersion)
+	ret0, _ := ret[0].(resources.GenericResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceByID indicates an expected call of GetResourceByID.
+func (mr *MockClientMockRecorder) GetResourceByID(ctx, resourceID, apiVersion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceByID", reflect.TypeOf((*MockClient)(nil).GetResourceByID), ctx, resourceID, apiVersion)
+}
+
 // GetVMCapabilities mocks base method.
 func (m *MockClient) GetVMCapabilities(ctx context.Context, instanceType, region string) (map[string]string, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +349,51 @@ func (mr *MockClientMockRecorder) ListResourceSKUs(ctx, filter interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResourceSKUs", reflect.TypeOf((*MockClient)(nil).ListResourceSKUs), ctx, filter)
 }
 
+// ListResources mocks base method.
+func (m *MockClient) ListResources(ctx context.Context, resourceGroupName, filter string) (azureclient.ResourcePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListResources", ctx, resourceGroupName, filter)
+	ret0, _ := ret[0].(azureclient.ResourcePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResources indicates an expected call of ListResources.
+func (mr *MockClientMockRecorder) ListResources(ctx, resourceGroupName, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockClient)(nil).ListResources), ctx, resourceGroupName, filter)
+}
+
+// ListUsage mocks base method.
+func (m *MockClient) ListUsage(ctx context.Context, location string) (azureclient.UsagePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsage", ctx, location)
+	ret0, _ := ret[0].(azureclient.UsagePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsage indicates an expected call of ListUsage.
+func (mr *MockClientMockRecorder) ListUsage(ctx, location interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsage", reflect.TypeOf((*MockClient)(nil).ListUsage), ctx, location)
+}
+
+// ListVirtualNetworkLinks mocks base method.
+func (m *MockClient) ListVirtualNetworkLinks(ctx context.Context, resourceGroupName, zone string) (azureclient.VirtualNetworkLinkPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVirtualNetworkLinks", ctx, resourceGroupName, zone)
+	ret0, _ := ret[0].(azureclient.VirtualNetworkLinkPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVirtualNetworkLinks indicates an expected call of ListVirtualNetworkLinks.
+func (mr *MockClientMockRecorder) ListVirtualNetworkLinks(ctx, resourceGroupName, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVirtualNetworkLinks", reflect.TypeOf((*MockClient)(nil).ListVirtualNetworkLinks), ctx, resourceGroupName, zone)
+}
+
 // StartVirtualMachine mocks base method.
 func (m *MockClient) StartVirtualMachine(ctx context.Context, resourceGroup, name string) (compute.VirtualMachinesStartFuture, error) {
 	m.ctrl.T.Helper()
@@ -230,6 +409,20 @@ func (mr *MockClientMockRecorder) StartVirtualMachine(ctx, resourceGroup, name i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVirtualMachine", reflect.TypeOf((*MockClient)(nil).StartVirtualMachine), ctx, resourceGroup, name)
 }
 
+// SubscriptionID mocks base method.
+func (m *MockClient) SubscriptionID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscriptionID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// SubscriptionID indicates an expected call of SubscriptionID.
+func (mr *MockClientMockRecorder) SubscriptionID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscriptionID", reflect.TypeOf((*MockClient)(nil).SubscriptionID))
+}
+
 // MockResourceSKUsPage is a mock of ResourceSKUsPage interface.
 type MockResourceSKUsPage struct {
 	ctrl     *gomock.Controller
@@ -360,6 +553,71 @@ func (mr *MockRecordSetPageMockRecorder) Values() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockRecordSetPage)(nil).Values))
 }
 
+// MockVirtualNetworkLinkPage is a mock of VirtualNetworkLinkPage interface.
+type MockVirtualNetworkLinkPage struct {
+	ctrl     *gomock.Controller
+	recorder *MockVirtualNetworkLinkPageMockRecorder
+}
+
+// MockVirtualNetworkLinkPageMockRecorder is the mock recorder for MockVirtualNetworkLinkPage.
+type MockVirtualNetworkLinkPageMockRecorder struct {
+	mock *MockVirtualNetworkLinkPage
+}
+
+// NewMockVirtualNetworkLinkPage creates a new mock instance.
+func NewMockVirtualNetworkLinkPage(ctrl *gomock.Controller) *MockVirtualNetworkLinkPage {
+	mock := &MockVirtualNetworkLinkPage{ctrl: ctrl}
+	mock.recorder = &MockVirtualNetworkLinkPageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockVirtualNetworkLinkPage) EXPECT() *MockVirtualNetworkLinkPageMockRecorder {
+	return m.recorder
+}
+
+// NextWithContext mocks base method.
+func (m *MockVirtualNetworkLinkPage) NextWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NextWithContext indicates an expected call of NextWithContext.
+func (mr *MockVirtualNetworkLinkPageMockRecorder) NextWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextWithContext", reflect.TypeOf((*MockVirtualNetworkLinkPage)(nil).NextWithContext), ctx)
+}
+
+// NotDone mocks base method.
+func (m *MockVirtualNetworkLinkPage) NotDone() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotDone")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NotDone indicates an expected call of NotDone.
+func (mr *MockVirtualNetworkLinkPageMockRecorder) NotDone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotDone", reflect.TypeOf((*MockVirtualNetworkLinkPage)(nil).NotDone))
+}
+
+// Values mocks base method.
+func (m *MockVirtualNetworkLinkPage) Values() []privatedns.VirtualNetworkLink {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Values")
+	ret0, _ := ret[0].([]privatedns.VirtualNetworkLink)
+	return ret0
+}
+
+// Values indicates an expected call of Values.
+func (mr *MockVirtualNetworkLinkPageMockRecorder) Values() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockVirtualNetworkLinkPage)(nil).Values))
+}
+
 // MockImageListResultPage is a mock of ImageListResultPage interface.
 type MockImageListResultPage struct {
 	ctrl     *gomock.Controller
@@ -424,3 +682,133 @@ func (mr *MockImageListResultPageMockRecorder) Values() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockImageListResultPage)(nil).Values))
 }
+
+// MockUsagePage is a mock of UsagePage interface.
+type MockUsagePage struct {
+	ctrl     *gomock.Controller
+	recorder *MockUsagePageMockRecorder
+}
+
+// MockUsagePageMockRecorder is the mock recorder for MockUsagePage.
+type MockUsagePageMockRecorder struct {
+	mock *MockUsagePage
+}
+
+// NewMockUsagePage creates a new mock instance.
+func NewMockUsagePage(ctrl *gomock.Controller) *MockUsagePage {
+	mock := &MockUsagePage{ctrl: ctrl}
+	mock.recorder = &MockUsagePageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUsagePage) EXPECT() *MockUsagePageMockRecorder {
+	return m.recorder
+}
+
+// NextWithContext mocks base method.
+func (m *MockUsagePage) NextWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NextWithContext indicates an expected call of NextWithContext.
+func (mr *MockUsagePageMockRecorder) NextWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextWithContext", reflect.TypeOf((*MockUsagePage)(nil).NextWithContext), ctx)
+}
+
+// NotDone mocks base method.
+func (m *MockUsagePage) NotDone() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotDone")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NotDone indicates an expected call of NotDone.
+func (mr *MockUsagePageMockRecorder) NotDone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotDone", reflect.TypeOf((*MockUsagePage)(nil).NotDone))
+}
+
+// Values mocks base method.
+func (m *MockUsagePage) Values() []compute.Usage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Values")
+	ret0, _ := ret[0].([]compute.Usage)
+	return ret0
+}
+
+// Values indicates an expected call of Values.
+func (mr *MockUsagePageMockRecorder) Values() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockUsagePage)(nil).Values))
+}
+
+// MockResourcePage is a mock of ResourcePage interface.
+type MockResourcePage struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourcePageMockRecorder
+}
+
+// MockResourcePageMockRecorder is the mock recorder for MockResourcePage.
+type MockResourcePageMockRecorder struct {
+	mock *MockResourcePage
+}
+
+// NewMockResourcePage creates a new mock instance.
+func NewMockResourcePage(ctrl *gomock.Controller) *MockResourcePage {
+	mock := &MockResourcePage{ctrl: ctrl}
+	mock.recorder = &MockResourcePageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourcePage) EXPECT() *MockResourcePageMockRecorder {
+	return m.recorder
+}
+
+// NextWithContext mocks base method.
+func (m *MockResourcePage) NextWithContext(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextWithContext", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NextWithContext indicates an expected call of NextWithContext.
+func (mr *MockResourcePageMockRecorder) NextWithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextWithContext", reflect.TypeOf((*MockResourcePage)(nil).NextWithContext), ctx)
+}
+
+// NotDone mocks base method.
+func (m *MockResourcePage) NotDone() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotDone")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NotDone indicates an expected call of NotDone.
+func (mr *MockResourcePageMockRecorder) NotDone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotDone", reflect.TypeOf((*MockResourcePage)(nil).NotDone))
+}
+
+// Values mocks base method.
+func (m *MockResourcePage) Values() []resources.GenericResourceExpanded {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Values")
+	ret0, _ := ret[0].([]resources.GenericResourceExpanded)
+	return ret0
+}
+
+// Values indicates an expected call of Values.
+func (mr *MockResourcePageMockRecorder) Values() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Values", reflect.TypeOf((*MockResourcePage)(nil).Values))
+}
@@ -0,0 +1,47 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// ListClusterResources returns the sorted IDs of the resources the uninstaller deletes for a cluster: its resource
+// group, with all of its resources, and the resources of the subscription tagged as owned by the cluster. The
+// resource group defaults to the one the installer creates when its name is empty.
+func ListClusterResources(ctx context.Context, c Client, infraID, resourceGroupName string, logger log.FieldLogger) ([]string, error) {
+	if resourceGroupName == "" {
+		resourceGroupName = infraID + "-rg"
+	}
+	ids := sets.New[string]()
+	listResources := func(resourceGroupName, filter string) error {
+		page, err := c.ListResources(ctx, resourceGroupName, filter)
+		for ; err == nil && page.NotDone(); err = page.NextWithContext(ctx) {
+			for _, resource := range page.Values() {
+				if resource.ID != nil {
+					ids.Insert(*resource.ID)
+				}
+			}
+		}
+		return err
+	}
+
+	var de autorest.DetailedError
+	switch err := listResources(resourceGroupName, ""); {
+	case errors.As(err, &de) && de.StatusCode == http.StatusNotFound:
+		logger.WithField("resourceGroup", resourceGroupName).Debug("resource group of the cluster not found")
+	case err != nil:
+		return nil, fmt.Errorf("failed to list the resources of resource group %s: %w", resourceGroupName, err)
+	default:
+		ids.Insert(fmt.Sprintf("/subscriptions/%s/resourceGroups/%s", c.SubscriptionID(), resourceGroupName))
+	}
+	if err := listResources("", fmt.Sprintf("tagName eq 'kubernetes.io_cluster.%s' and tagValue eq 'owned'", infraID)); err != nil {
+		return nil, fmt.Errorf("failed to list the resources tagged for the cluster: %w", err)
+	}
+	return sets.List(ids), nil
+}
@@ -0,0 +1,437 @@
+// Package clusteradoption introspects running clusters to adopt them into Hive.
+package clusteradoption
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/apis/hive/v1/aws"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	hivev1gcp "github.com/openshift/hive/apis/hive/v1/gcp"
+	hivev1none "github.com/openshift/hive/apis/hive/v1/none"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	machineAPINamespace      = "openshift-machine-api"
+	clusterObjectName        = "cluster"
+	clusterVersionObjectName = "version"
+
+	// The MachinePool webhook rejects these pool names. The installer names the worker MachineSets of older GCP
+	// clusters <infraID>-w-<zone>; they are adopted into the worker pool, which the GCP actuator keeps naming so.
+	masterPoolName       = "master"
+	legacyWorkerPoolName = "w"
+	workerPoolName       = "worker"
+
+	// Names of the validation checks.
+	CheckAPIReachable      = "APIReachable"
+	CheckClusterIdentity   = "ClusterIdentity"
+	CheckPlatform          = "Platform"
+	CheckCredentials       = "Credentials"
+	CheckMachineSets       = "MachineSets"
+	CheckClusterDeployment = "ClusterDeployment"
+)
+
+// Cluster is what was found out about a cluster to adopt.
+type Cluster struct {
+	// Report is the validation report of the cluster.
+	Report *hivev1.ClusterAdoptionReport
+
+	// Platform is the platform of the ClusterDeployment, without credentials.
+	Platform hivev1.Platform
+
+	// MetadataPlatform is the platform metadata of the ClusterDeployment.
+	MetadataPlatform *hivev1.ClusterPlatformMetadata
+
+	// MachinePools are the specs of the MachinePools reflecting the MachineSets of the cluster, without a
+	// ClusterDeployment reference.
+	MachinePools []hivev1.MachinePoolSpec
+
+	// MachineSets are the names of the MachineSets of the cluster, by MachinePool name.
+	MachineSets map[string][]string
+}
+
+// AddCheck adds a check to the report, and marks the report invalid when the check failed.
+func (c *Cluster) AddCheck(name string, result hivev1.ClusterAdoptionCheckResult, format string, args ...interface{}) {
+	c.Report.Checks = append(c.Report.Checks, hivev1.ClusterAdoptionCheck{
+		Name:    name,
+		Result:  result,
+		Message: fmt.Sprintf(format, args...),
+	})
+	if result == hivev1.ClusterAdoptionCheckFailed {
+		c.Report.Valid = false
+	}
+}
+
+// Introspect reads the Infrastructure, DNS, ClusterVersion and MachineSets of the cluster to adopt, and reports
+// whether it can be adopted. hasCredentials tells whether cloud credentials will be provided for the cluster.
+func Introspect(ctx context.Context, remoteClient client.Client, hasCredentials bool) *Cluster {
+	c := &Cluster{
+		Report:      &hivev1.ClusterAdoptionReport{Valid: true},
+		MachineSets: map[string][]string{},
+	}
+
+	infra := &configv1.Infrastructure{}
+	if err := remoteClient.Get(ctx, types.NamespacedName{Name: clusterObjectName}, infra); err != nil {
+		c.AddCheck(CheckAPIReachable, hivev1.ClusterAdoptionCheckFailed, "error getting infrastructure: %v", err)
+		return c
+	}
+	c.AddCheck(CheckAPIReachable, hivev1.ClusterAdoptionCheckPassed, "")
+	c.Report.InfraID = infra.Status.InfrastructureName
+
+	dns := &configv1.DNS{}
+	if err := remoteClient.Get(ctx, types.NamespacedName{Name: clusterObjectName}, dns); err != nil {
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckFailed, "error getting dns: %v", err)
+		return c
+	}
+	c.Report.ClusterName, c.Report.BaseDomain, _ = strings.Cut(dns.Spec.BaseDomain, ".")
+
+	clusterVersion := &configv1.ClusterVersion{}
+	if err := remoteClient.Get(ctx, types.NamespacedName{Name: clusterVersionObjectName}, clusterVersion); err != nil {
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckFailed, "error getting clusterversion: %v", err)
+		return c
+	}
+	c.Report.ClusterID = string(clusterVersion.Spec.ClusterID)
+	c.Report.Version = clusterVersion.Status.Desired.Version
+
+	switch {
+	case c.Report.ClusterID == "":
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckFailed, "clusterversion has no cluster ID")
+	case c.Report.InfraID == "":
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckFailed, "infrastructure has no infrastructure name")
+	case c.Report.ClusterName == "" || c.Report.BaseDomain == "":
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckFailed, "cannot determine the cluster name and base domain from %q", dns.Spec.BaseDomain)
+	default:
+		c.AddCheck(CheckClusterIdentity, hivev1.ClusterAdoptionCheckPassed, "")
+	}
+
+	if !c.introspectPlatform(infra, dns) {
+		return c
+	}
+
+	if hasCredentials {
+		c.AddCheck(CheckCredentials, hivev1.ClusterAdoptionCheckPassed, "")
+	} else {
+		c.AddCheck(CheckCredentials, hivev1.ClusterAdoptionCheckFailed, "cloud credentials are required to adopt %s clusters", c.Report.Platform)
+	}
+
+	c.introspectMachineSets(ctx, remoteClient)
+	// The Azure platform status has no region; it is the location of the MachineSets.
+	if c.Platform.Azure != nil && c.Platform.Azure.Region == "" {
+		c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckFailed, "cannot determine the region from the machinesets")
+	}
+	return c
+}
+
+// introspectPlatform fills in the platform of the cluster. It returns false when the platform is not supported.
+func (c *Cluster) introspectPlatform(infra *configv1.Infrastructure, dns *configv1.DNS) bool {
+	status := infra.Status.PlatformStatus
+	if status == nil {
+		c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckFailed, "infrastructure has no platform status")
+		return false
+	}
+	c.Report.Platform = string(status.Type)
+	switch status.Type {
+	case configv1.AWSPlatformType:
+		if status.AWS == nil {
+			break
+		}
+		c.Report.Region = status.AWS.Region
+		c.Platform.AWS = &hivev1aws.Platform{Region: status.AWS.Region}
+		c.MetadataPlatform = &hivev1.ClusterPlatformMetadata{AWS: &hivev1aws.Metadata{}}
+	case configv1.GCPPlatformType:
+		if status.GCP == nil {
+			break
+		}
+		c.Report.Region = status.GCP.Region
+		c.Platform.GCP = &hivev1gcp.Platform{Region: status.GCP.Region}
+		c.MetadataPlatform = &hivev1.ClusterPlatformMetadata{GCP: &hivev1gcp.Metadata{}}
+	case configv1.AzurePlatformType:
+		if status.Azure == nil {
+			break
+		}
+		c.Platform.Azure = &hivev1azure.Platform{
+			CloudName:                   hivev1azure.CloudEnvironment(status.Azure.CloudName),
+			BaseDomainResourceGroupName: publicZoneResourceGroup(dns),
+		}
+		c.MetadataPlatform = &hivev1.ClusterPlatformMetadata{
+			Azure: &hivev1azure.Metadata{ResourceGroupName: ptr.To(status.Azure.ResourceGroupName)},
+		}
+	case configv1.NonePlatformType:
+		c.Platform.None = &hivev1none.Platform{}
+		c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckWarning, "clusters without a platform are adopted without MachinePools")
+		return false
+	default:
+		c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckFailed, "platform %s is not supported for adoption", status.Type)
+		return false
+	}
+	if c.Platform.AWS == nil && c.Platform.GCP == nil && c.Platform.Azure == nil {
+		c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckFailed, "infrastructure has no %s platform status", status.Type)
+		return false
+	}
+	c.AddCheck(CheckPlatform, hivev1.ClusterAdoptionCheckPassed, "")
+	return true
+}
+
+// publicZoneResourceGroup returns the resource group of the public DNS zone of an Azure cluster, from the zone ID.
+func publicZoneResourceGroup(dns *configv1.DNS) string {
+	if dns.Spec.PublicZone == nil {
+		return ""
+	}
+	parts := strings.Split(dns.Spec.PublicZone.ID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// introspectMachineSets maps the MachineSets of the cluster to MachinePools. The installer names MachineSets
+// <infraID>-<pool>-<zone>; MachineSets named otherwise cannot be mapped, and are reported in a warning. MachineSets
+// mapping to a pool name that MachinePools cannot have fail the check, as Hive would replace them if left unadopted.
+func (c *Cluster) introspectMachineSets(ctx context.Context, remoteClient client.Client) {
+	machineSets := &machinev1beta1.MachineSetList{}
+	if err := remoteClient.List(ctx, machineSets, client.InNamespace(machineAPINamespace)); err != nil {
+		c.AddCheck(CheckMachineSets, hivev1.ClusterAdoptionCheckWarning, "error listing machinesets, MachinePools are not created: %v", err)
+		return
+	}
+	sort.Slice(machineSets.Items, func(i, j int) bool { return machineSets.Items[i].Name < machineSets.Items[j].Name })
+
+	pools := map[string]*hivev1.MachinePoolSpec{}
+	var problems, invalid []string
+	for i := range machineSets.Items {
+		ms := &machineSets.Items[i]
+		platform, zone, suffix, err := c.machinePoolPlatform(ms)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", ms.Name, err))
+			continue
+		}
+		name, ok := strings.CutPrefix(ms.Name, c.Report.InfraID+"-")
+		if ok && suffix != "" {
+			name, ok = strings.CutSuffix(name, "-"+suffix)
+		}
+		if !ok || name == "" {
+			problems = append(problems, fmt.Sprintf("%s: name does not match %s-<pool>-%s", ms.Name, c.Report.InfraID, suffix))
+			continue
+		}
+		if c.Platform.GCP != nil && name == legacyWorkerPoolName {
+			name = workerPoolName
+		}
+		if name == masterPoolName || name == legacyWorkerPoolName {
+			invalid = append(invalid, fmt.Sprintf("%s: pool name cannot be %q", ms.Name, name))
+			continue
+		}
+		if existing, ok := pools[name]; ok {
+			if !samePlatform(existing.Platform, platform) {
+				problems = append(problems, fmt.Sprintf("%s: machine configuration differs from the other machinesets of pool %s", ms.Name, name))
+				continue
+			}
+			addZone(&existing.Platform, zone)
+			*existing.Replicas += int64(ptr.Deref(ms.Spec.Replicas, 0))
+		} else {
+			addZone(&platform, zone)
+			pools[name] = &hivev1.MachinePoolSpec{
+				Name:     name,
+				Replicas: ptr.To(int64(ptr.Deref(ms.Spec.Replicas, 0))),
+				Platform: platform,
+			}
+		}
+		c.MachineSets[name] = append(c.MachineSets[name], ms.Name)
+	}
+
+	for _, pool := range pools {
+		c.MachinePools = append(c.MachinePools, *pool)
+		c.Report.MachinePools = append(c.Report.MachinePools, pool.Name)
+	}
+	sort.Slice(c.MachinePools, func(i, j int) bool { return c.MachinePools[i].Name < c.MachinePools[j].Name })
+	sort.Strings(c.Report.MachinePools)
+
+	if len(invalid) > 0 {
+		c.AddCheck(CheckMachineSets, hivev1.ClusterAdoptionCheckFailed, "machinesets cannot be adopted into MachinePools: %s", strings.Join(invalid, "; "))
+		return
+	}
+	if len(problems) > 0 {
+		c.AddCheck(CheckMachineSets, hivev1.ClusterAdoptionCheckWarning, "machinesets not adopted into MachinePools: %s", strings.Join(problems, "; "))
+		return
+	}
+	c.AddCheck(CheckMachineSets, hivev1.ClusterAdoptionCheckPassed, "")
+}
+
+// machinePoolPlatform decodes the provider spec of a MachineSet into a MachinePool platform without zones. It also
+// returns the zone of the MachineSet, and the suffix the installer gives to the name of the MachineSet for its zone.
+func (c *Cluster) machinePoolPlatform(ms *machinev1beta1.MachineSet) (platform hivev1.MachinePoolPlatform, zone, suffix string, err error) {
+	providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value
+	if providerSpec == nil {
+		return platform, "", "", errors.New("no provider spec")
+	}
+	switch {
+	case c.Platform.AWS != nil:
+		spec := &machinev1beta1.AWSMachineProviderConfig{}
+		if err := json.Unmarshal(providerSpec.Raw, spec); err != nil {
+			return platform, "", "", errors.Wrap(err, "could not decode AWS provider spec")
+		}
+		platform.AWS = &hivev1aws.MachinePoolPlatform{InstanceType: spec.InstanceType}
+		for _, device := range spec.BlockDevices {
+			if device.DeviceName != nil || device.EBS == nil {
+				continue
+			}
+			platform.AWS.EC2RootVolume = hivev1aws.EC2RootVolume{
+				IOPS: int(ptr.Deref(device.EBS.Iops, 0)),
+				Size: int(ptr.Deref(device.EBS.VolumeSize, 0)),
+				Type: ptr.Deref(device.EBS.VolumeType, ""),
+			}
+		}
+		zone := spec.Placement.AvailabilityZone
+		return platform, zone, zone, nil
+	case c.Platform.GCP != nil:
+		spec := &machinev1beta1.GCPMachineProviderSpec{}
+		if err := json.Unmarshal(providerSpec.Raw, spec); err != nil {
+			return platform, "", "", errors.Wrap(err, "could not decode GCP provider spec")
+		}
+		platform.GCP = &hivev1gcp.MachinePool{InstanceType: spec.MachineType}
+		for _, disk := range spec.Disks {
+			if disk != nil && disk.Boot {
+				platform.GCP.OSDisk = hivev1gcp.OSDisk{DiskType: disk.Type, DiskSizeGB: disk.SizeGB}
+			}
+		}
+		// GCP MachineSets are named <infraID>-<pool>-<zone without the region>, such as mycluster-abc12-worker-a.
+		return platform, spec.Zone, strings.TrimPrefix(spec.Zone, c.Platform.GCP.Region+"-"), nil
+	case c.Platform.Azure != nil:
+		spec := &machinev1beta1.AzureMachineProviderSpec{}
+		if err := json.Unmarshal(providerSpec.Raw, spec); err != nil {
+			return platform, "", "", errors.Wrap(err, "could not decode Azure provider spec")
+		}
+		platform.Azure = &hivev1azure.MachinePool{
+			InstanceType: spec.VMSize,
+			OSDisk: hivev1azure.OSDisk{
+				DiskSizeGB: spec.OSDisk.DiskSizeGB,
+				DiskType:   spec.OSDisk.ManagedDisk.StorageAccountType,
+			},
+		}
+		if c.Report.Region == "" {
+			c.Report.Region = spec.Location
+			c.Platform.Azure.Region = spec.Location
+		}
+		// Azure MachineSets are named <infraID>-<pool>-<location><zone>.
+		return platform, spec.Zone, spec.Location + spec.Zone, nil
+	}
+	return platform, "", "", errors.New("unsupported platform")
+}
+
+// addZone adds the zone of a MachineSet to the zones of a MachinePool platform.
+func addZone(platform *hivev1.MachinePoolPlatform, zone string) {
+	if zone == "" {
+		return
+	}
+	switch {
+	case platform.AWS != nil:
+		platform.AWS.Zones = append(platform.AWS.Zones, zone)
+	case platform.GCP != nil:
+		platform.GCP.Zones = append(platform.GCP.Zones, zone)
+	case platform.Azure != nil:
+		platform.Azure.Zones = append(platform.Azure.Zones, zone)
+	}
+}
+
+// samePlatform tells whether two MachinePool platforms are the same, except for their zones.
+func samePlatform(a, b hivev1.MachinePoolPlatform) bool {
+	a, b = *a.DeepCopy(), *b.DeepCopy()
+	switch {
+	case a.AWS != nil && b.AWS != nil:
+		a.AWS.Zones, b.AWS.Zones = nil, nil
+	case a.GCP != nil && b.GCP != nil:
+		a.GCP.Zones, b.GCP.Zones = nil, nil
+	case a.Azure != nil && b.Azure != nil:
+		a.Azure.Zones, b.Azure.Zones = nil, nil
+	}
+	aJSON, _ := json.Marshal(a)
+	bJSON, _ := json.Marshal(b)
+	return string(aJSON) == string(bJSON)
+}
+
+// ClusterDeployment returns the ClusterDeployment adopting the cluster.
+func (c *Cluster) ClusterDeployment(namespace, name string, spec *hivev1.ClusterAdoptionSpec) *hivev1.ClusterDeployment {
+	platform := *c.Platform.DeepCopy()
+	if spec.CredentialsSecretRef != nil {
+		switch {
+		case platform.AWS != nil:
+			platform.AWS.CredentialsSecretRef = *spec.CredentialsSecretRef
+		case platform.GCP != nil:
+			platform.GCP.CredentialsSecretRef = *spec.CredentialsSecretRef
+		case platform.Azure != nil:
+			platform.Azure.CredentialsSecretRef = *spec.CredentialsSecretRef
+		}
+	}
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName:      c.Report.ClusterName,
+			BaseDomain:       c.Report.BaseDomain,
+			Platform:         platform,
+			PullSecretRef:    spec.PullSecretRef,
+			PreserveOnDelete: spec.PreserveOnDelete,
+			Installed:        true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				ClusterID:                c.Report.ClusterID,
+				InfraID:                  c.Report.InfraID,
+				AdminKubeconfigSecretRef: spec.AdminKubeconfigSecretRef,
+				Platform:                 c.MetadataPlatform.DeepCopy(),
+			},
+		},
+	}
+}
+
+// MachinePoolObjects returns the MachinePools reflecting the MachineSets of the cluster, for the ClusterDeployment.
+func (c *Cluster) MachinePoolObjects(cd *hivev1.ClusterDeployment) []*hivev1.MachinePool {
+	pools := make([]*hivev1.MachinePool, 0, len(c.MachinePools))
+	for _, spec := range c.MachinePools {
+		pool := &hivev1.MachinePool{
+			ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: fmt.Sprintf("%s-%s", cd.Name, spec.Name)},
+			Spec:       *spec.DeepCopy(),
+		}
+		pool.Spec.ClusterDeploymentRef = corev1.LocalObjectReference{Name: cd.Name}
+		pools = append(pools, pool)
+	}
+	return pools
+}
+
+// LabelMachineSets labels the MachineSets of the cluster with the MachinePool they are adopted into, so that the
+// MachinePool controller manages them rather than replacing them.
+func (c *Cluster) LabelMachineSets(ctx context.Context, remoteClient client.Client) error {
+	for pool, names := range c.MachineSets {
+		for _, name := range names {
+			ms := &machinev1beta1.MachineSet{}
+			if err := remoteClient.Get(ctx, types.NamespacedName{Namespace: machineAPINamespace, Name: name}, ms); err != nil {
+				return errors.Wrapf(err, "could not get machineset %s", name)
+			}
+			if ms.Labels[constants.MachineSetMachinePoolLabel] == pool && ms.Labels[constants.HiveManagedLabel] == "true" {
+				continue
+			}
+			if ms.Labels == nil {
+				ms.Labels = map[string]string{}
+			}
+			ms.Labels[constants.MachineSetMachinePoolLabel] = pool
+			ms.Labels[constants.HiveManagedLabel] = "true"
+			if err := remoteClient.Update(ctx, ms); err != nil {
+				return errors.Wrapf(err, "could not label machineset %s", name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package clusteradoption
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+)
+
+const testInfraID = "test-cluster-abc12"
+
+func testMachineSet(name string, providerSpec interface{}) *machinev1beta1.MachineSet {
+	raw, _ := json.Marshal(providerSpec)
+	return &machinev1beta1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: machineAPINamespace, Name: name},
+		Spec: machinev1beta1.MachineSetSpec{
+			Replicas: ptr.To[int32](1),
+			Template: machinev1beta1.MachineTemplateSpec{
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Raw: raw}},
+				},
+			},
+		},
+	}
+}
+
+func clusterObjects(platformStatus *configv1.PlatformStatus, publicZoneID string, machineSets ...runtime.Object) []runtime.Object {
+	dns := &configv1.DNS{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterObjectName},
+		Spec:       configv1.DNSSpec{BaseDomain: "test-cluster.example.com"},
+	}
+	if publicZoneID != "" {
+		dns.Spec.PublicZone = &configv1.DNSZone{ID: publicZoneID}
+	}
+	return append([]runtime.Object{
+		&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterObjectName},
+			Status: configv1.InfrastructureStatus{
+				InfrastructureName: testInfraID,
+				PlatformStatus:     platformStatus,
+			},
+		},
+		dns,
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterVersionObjectName},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: "test-cluster-id"},
+		},
+	}, machineSets...)
+}
+
+func gcpProviderSpec(zone string) *machinev1beta1.GCPMachineProviderSpec {
+	return &machinev1beta1.GCPMachineProviderSpec{
+		MachineType: "n2-standard-4",
+		Zone:        zone,
+		Disks:       []*machinev1beta1.GCPDisk{{Boot: true, SizeGB: 128, Type: "pd-ssd"}},
+	}
+}
+
+func azureProviderSpec(zone, vmSize string) *machinev1beta1.AzureMachineProviderSpec {
+	return &machinev1beta1.AzureMachineProviderSpec{
+		VMSize:   vmSize,
+		Location: "centralus",
+		Zone:     zone,
+		OSDisk: machinev1beta1.OSDisk{
+			DiskSizeGB:  128,
+			ManagedDisk: machinev1beta1.OSDiskManagedDiskParameters{StorageAccountType: "Premium_LRS"},
+		},
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	cases := []struct {
+		name             string
+		objects          []runtime.Object
+		hasCredentials   bool
+		expectValid      bool
+		expectRegion     string
+		expectChecks     map[string]hivev1.ClusterAdoptionCheckResult
+		validatePlatform func(*testing.T, *Cluster)
+	}{
+		{
+			name: "gcp",
+			objects: clusterObjects(
+				&configv1.PlatformStatus{
+					Type: configv1.GCPPlatformType,
+					GCP:  &configv1.GCPPlatformStatus{ProjectID: "test-project", Region: "us-central1"},
+				}, "",
+				testMachineSet(testInfraID+"-worker-a", gcpProviderSpec("us-central1-a")),
+				testMachineSet(testInfraID+"-worker-b", gcpProviderSpec("us-central1-b")),
+			),
+			hasCredentials: true,
+			expectValid:    true,
+			expectRegion:   "us-central1",
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckMachineSets: hivev1.ClusterAdoptionCheckPassed,
+			},
+		},
+		{
+			name: "gcp legacy worker machinesets",
+			objects: clusterObjects(
+				&configv1.PlatformStatus{
+					Type: configv1.GCPPlatformType,
+					GCP:  &configv1.GCPPlatformStatus{ProjectID: "test-project", Region: "us-central1"},
+				}, "",
+				testMachineSet(testInfraID+"-w-a", gcpProviderSpec("us-central1-a")),
+				testMachineSet(testInfraID+"-w-b", gcpProviderSpec("us-central1-b")),
+			),
+			hasCredentials: true,
+			expectValid:    true,
+			expectRegion:   "us-central1",
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckMachineSets: hivev1.ClusterAdoptionCheckPassed,
+			},
+			validatePlatform: func(t *testing.T, c *Cluster) {
+				assert.Equal(t, []string{"worker"}, c.Report.MachinePools, "unexpected machine pools")
+				assert.Equal(t, []string{testInfraID + "-w-a", testInfraID + "-w-b"}, c.MachineSets["worker"], "unexpected worker machinesets")
+			},
+		},
+		{
+			name: "master machinesets",
+			objects: clusterObjects(
+				&configv1.PlatformStatus{
+					Type: configv1.GCPPlatformType,
+					GCP:  &configv1.GCPPlatformStatus{ProjectID: "test-project", Region: "us-central1"},
+				}, "",
+				testMachineSet(testInfraID+"-worker-a", gcpProviderSpec("us-central1-a")),
+				testMachineSet(testInfraID+"-master-a", gcpProviderSpec("us-central1-a")),
+			),
+			hasCredentials: true,
+			expectRegion:   "us-central1",
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckMachineSets: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name: "azure",
+			objects: clusterObjects(
+				&configv1.PlatformStatus{
+					Type:  configv1.AzurePlatformType,
+					Azure: &configv1.AzurePlatformStatus{ResourceGroupName: "test-cluster-abc12-rg", CloudName: configv1.AzurePublicCloud},
+				},
+				"/subscriptions/sub/resourceGroups/os4-common/providers/Microsoft.Network/dnszones/example.com",
+				testMachineSet(testInfraID+"-worker-centralus1", azureProviderSpec("1", "Standard_D4s_v3")),
+				testMachineSet(testInfraID+"-worker-centralus2", azureProviderSpec("2", "Standard_D4s_v3")),
+				testMachineSet(testInfraID+"-infra-centralus1", azureProviderSpec("1", "Standard_D8s_v3")),
+				testMachineSet(testInfraID+"-infra-centralus2", azureProviderSpec("2", "Standard_D16s_v3")),
+			),
+			hasCredentials: true,
+			expectValid:    true,
+			expectRegion:   "centralus",
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckMachineSets: hivev1.ClusterAdoptionCheckWarning,
+			},
+			validatePlatform: func(t *testing.T, c *Cluster) {
+				require.NotNil(t, c.Platform.Azure, "expected Azure platform")
+				assert.Equal(t, "os4-common", c.Platform.Azure.BaseDomainResourceGroupName, "unexpected base domain resource group")
+				assert.Equal(t, "test-cluster-abc12-rg", *c.MetadataPlatform.Azure.ResourceGroupName, "unexpected resource group")
+				assert.Equal(t, []string{"infra", "worker"}, c.Report.MachinePools, "unexpected machine pools")
+				require.Len(t, c.MachinePools, 2)
+				infra, worker := c.MachinePools[0], c.MachinePools[1]
+				assert.Equal(t, []string{"1"}, infra.Platform.Azure.Zones, "unexpected infra zones")
+				assert.Equal(t, []string{"1", "2"}, worker.Platform.Azure.Zones, "unexpected worker zones")
+				assert.Equal(t, "Premium_LRS", worker.Platform.Azure.OSDisk.DiskType, "unexpected disk type")
+				assert.Equal(t, int64(2), *worker.Replicas, "unexpected worker replicas")
+			},
+		},
+		{
+			name: "missing credentials",
+			objects: clusterObjects(&configv1.PlatformStatus{
+				Type: configv1.AWSPlatformType,
+				AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+			}, ""),
+			expectRegion: "us-east-1",
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckCredentials: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name:           "unsupported platform",
+			objects:        clusterObjects(&configv1.PlatformStatus{Type: configv1.VSpherePlatformType}, ""),
+			hasCredentials: true,
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckPlatform: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name:           "none platform",
+			objects:        clusterObjects(&configv1.PlatformStatus{Type: configv1.NonePlatformType}, ""),
+			hasCredentials: true,
+			expectValid:    true,
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				CheckPlatform: hivev1.ClusterAdoptionCheckWarning,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			remoteClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(tc.objects...).Build()
+			c := Introspect(context.TODO(), remoteClient, tc.hasCredentials)
+			assert.Equal(t, tc.expectValid, c.Report.Valid, "unexpected validity: %v", c.Report.Checks)
+			assert.Equal(t, tc.expectRegion, c.Report.Region, "unexpected region")
+			assert.Equal(t, "test-cluster", c.Report.ClusterName, "unexpected cluster name")
+			for name, expected := range tc.expectChecks {
+				// A check may be reported more than once; the result is the last one that did not pass.
+				var result hivev1.ClusterAdoptionCheckResult
+				for _, check := range c.Report.Checks {
+					if check.Name == name && (result == "" || check.Result != hivev1.ClusterAdoptionCheckPassed) {
+						result = check.Result
+					}
+				}
+				assert.Equal(t, expected, result, "unexpected result of check %s", name)
+			}
+			if tc.validatePlatform != nil {
+				tc.validatePlatform(t, c)
+			}
+		})
+	}
+}
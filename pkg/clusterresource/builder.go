@@ -75,6 +75,9 @@ type Builder struct {
 	// HibernateAfter is the duration after which a running cluster should be automatically hibernated.
 	HibernateAfter *time.Duration
 
+	// PowerSchedule keeps the cluster running during recurring time windows and hibernating outside of them.
+	PowerSchedule *hivev1.PowerSchedule
+
 	// InstallAttemptsLimit is the maximum number of times Hive will attempt to install the cluster.
 	InstallAttemptsLimit *int32
 
@@ -329,6 +332,9 @@ func (o *Builder) generateClusterDeployment() *hivev1.ClusterDeployment {
 	if o.HibernateAfter != nil {
 		cd.Spec.HibernateAfter = &metav1.Duration{Duration: *o.HibernateAfter}
 	}
+	if o.PowerSchedule != nil {
+		cd.Spec.PowerSchedule = o.PowerSchedule.DeepCopy()
+	}
 
 	cd.Spec.InstallAttemptsLimit = o.InstallAttemptsLimit
 
@@ -44,9 +44,17 @@ const (
 	// MachinePoolNameLabel is the label that is used to identify the MachinePool which owns a particular resource.
 	MachinePoolNameLabel = "hive.openshift.io/machine-pool-name"
 
+	// MachineSetMachinePoolLabel is the label set on the MachineSets in a target cluster to identify the MachinePool
+	// they belong to. Unlike MachinePoolNameLabel, its value is the pool name from the MachinePool spec.
+	MachineSetMachinePoolLabel = "hive.openshift.io/machine-pool"
+
 	// ClusterDeploymentNameLabel is the label that is used to identify a relationship to a given cluster deployment object.
 	ClusterDeploymentNameLabel = "hive.openshift.io/cluster-deployment-name"
 
+	// ClusterDeploymentNamespaceLabel is the label that is used, along with ClusterDeploymentNameLabel, to identify a
+	// relationship to a given cluster deployment object from resources in another namespace.
+	ClusterDeploymentNamespaceLabel = "hive.openshift.io/cluster-deployment-namespace"
+
 	// ClusterDeprovisionNameLabel is the label that is used to identify a relationship to a given cluster deprovision object.
 	ClusterDeprovisionNameLabel = "hive.openshift.io/cluster-deprovision-name"
 
@@ -82,6 +90,15 @@ const (
 	// JobTypeProvision is used as a value of JobTypeLabel that says the Job is specifically running the provisioner.
 	JobTypeProvision = "provision"
 
+	// JobTypePreInstallHook is used as a value of JobTypeLabel that says the Job is running a ClusterDeployment's pre-install hook.
+	JobTypePreInstallHook = "pre-install-hook"
+
+	// JobTypePostInstallHook is used as a value of JobTypeLabel that says the Job is running a ClusterDeployment's post-install hook.
+	JobTypePostInstallHook = "post-install-hook"
+
+	// InstallHookNameLabel is the label that is used to identify which of a ClusterDeployment's install hooks a Job is running.
+	InstallHookNameLabel = "hive.openshift.io/install-hook-name"
+
 	// DNSZoneTypeLabel is the label that is used to identify what a DNSZone is being used for.
 	DNSZoneTypeLabel = "hive.openshift.io/dnszone-type"
 
@@ -112,6 +129,9 @@ const (
 	// SyncSetTypeIdentityProvider is used as a value of SyncSetTypeLabel that says the syncset is specifically used to distribute identity provider information.
 	SyncSetTypeIdentityProvider = "identityprovider"
 
+	// SyncSetTypeAPIServerTunnel is used as a value of SyncSetTypeLabel that says the syncset is specifically used to distribute the API server tunnel agent.
+	SyncSetTypeAPIServerTunnel = "apiservertunnel"
+
 	// GlobalPullSecret is the environment variable for controllers to get the global pull secret
 	GlobalPullSecret = "GLOBAL_PULL_SECRET"
 
@@ -173,10 +193,34 @@ const (
 	// cannot be deleted. The annotation must be removed in order to delete the ClusterDeployment.
 	ProtectedDeleteAnnotation = "hive.openshift.io/protected-delete"
 
+	// DeleteAfterExtensionAnnotation is an annotation used on ClusterDeployments to extend the lifetime set by
+	// spec.deleteAfter or the hive.openshift.io/delete-after annotation. Its value is a duration, such as "24h",
+	// added to the lifetime.
+	DeleteAfterExtensionAnnotation = "hive.openshift.io/delete-after-extension"
+
 	// ProtectedDeleteEnvVar is the name of the environment variable used to tell the controller manager whether
 	// protected delete is enabled.
 	ProtectedDeleteEnvVar = "PROTECTED_DELETE"
 
+	// PreflightChecksEnvVar is the name of the environment variable used to tell the controller manager whether
+	// cloud quota and permission checks should be run before launching install attempts.
+	PreflightChecksEnvVar = "PREFLIGHT_CHECKS"
+
+	// SkipPreflightChecksAnnotation, if set to "true" on a ClusterDeployment, disables the cloud quota and permission
+	// checks that would otherwise be run before launching install attempts.
+	SkipPreflightChecksAnnotation = "hive.openshift.io/skip-preflight-checks"
+
+	// CustomInstallerBinaryName is the name under which a ClusterDeployment's custom installer binary is copied into
+	// the install pod's working directory.
+	CustomInstallerBinaryName = "custom-installer"
+
+	// OpenShiftInstallBinaryEnvVar is the environment variable through which a custom installer is given the path
+	// to the openshift-install binary.
+	OpenShiftInstallBinaryEnvVar = "OPENSHIFT_INSTALL_BINARY"
+
+	// InstallHookJobTemplateKey is the key in an install hook's ConfigMap under which the Job manifest is stored.
+	InstallHookJobTemplateKey = "job.yaml"
+
 	// RelocateAnnotation is an annotation used on ClusterDeployments and DNSZones to indicate that the resource
 	// is involved in a relocation between Hive instances.
 	// The value of the annotation has the format "{ClusterRelocate}/{Status}", where
@@ -431,11 +475,34 @@ const (
 	// configurations. See HiveConfig.Spec.MetricsConfig.
 	MetricsConfigFileEnvVar = "METRICS_CONFIG_FILE"
 
+	// CertificateIssuerConfigFileEnvVar points to a text file containing the configuration of the certificate
+	// issuer. See HiveConfig.Spec.CertificateIssuer.
+	CertificateIssuerConfigFileEnvVar = "CERTIFICATE_ISSUER_CONFIG_FILE"
+
+	// APIServerTunnelConfigFileEnvVar points to a text file containing the configuration of the API server tunnel
+	// endpoint. See HiveConfig.Spec.APIServerTunnel.
+	APIServerTunnelConfigFileEnvVar = "API_SERVER_TUNNEL_CONFIG_FILE"
+
+	// ArgoCDConfigFileEnvVar points to a text file containing the configuration of the ArgoCD integration. See
+	// HiveConfig.Spec.ArgoCD.
+	ArgoCDConfigFileEnvVar = "ARGOCD_CONFIG_FILE"
+
+	// GitOpsRegistrationConfigFileEnvVar points to a text file containing the GitOps registrations. See
+	// HiveConfig.Spec.GitOpsRegistrations.
+	GitOpsRegistrationConfigFileEnvVar = "GITOPS_REGISTRATION_CONFIG_FILE"
+
 	// HiveReleaseImageVerificationConfigMapNamespaceEnvVar is used to configure the config map that will be used
 	// to verify the release images being used for cluster deployments.
 	HiveReleaseImageVerificationConfigMapNamespaceEnvVar = "HIVE_RELEASE_IMAGE_VERIFICATION_CONFIGMAP_NS"
 	HiveReleaseImageVerificationConfigMapNameEnvVar      = "HIVE_RELEASE_IMAGE_VERIFICATION_CONFIGMAP_NAME"
 
+	// CostEstimationPriceTableConfigMapEnvVar is used to pass the name of the cost estimation price table ConfigMap,
+	// in the hive namespace, to the clustercost controller. Cost estimation is disabled when it is unset.
+	CostEstimationPriceTableConfigMapEnvVar = "HIVE_COST_ESTIMATION_PRICE_TABLE_CONFIGMAP"
+
+	// CostEstimationPriceTableKey is the key in the price table ConfigMap under which the prices are stored.
+	CostEstimationPriceTableKey = "prices.yaml"
+
 	// HiveConfigName is the one and only name for a HiveConfig supported in the cluster. Any others will be ignored.
 	HiveConfigName = "hive"
 
@@ -456,6 +523,22 @@ const (
 	// installing. Use with caution.
 	ResumeSkipsClusterOperatorsLabel = "hive.openshift.io/resume-skips-cluster-operators"
 
+	// GitOpsRegistrationLabel is used to label a ClusterDeployment. If set to "false", the cluster is not registered
+	// with the GitOps systems of HiveConfig.Spec.GitOpsRegistrations, and the objects registering it are deleted.
+	GitOpsRegistrationLabel = "hive.openshift.io/gitops-registration"
+
+	// GitOpsRegistrationNameLabel is the label used to identify the GitOps registration which created an object
+	// registering a cluster.
+	GitOpsRegistrationNameLabel = "hive.openshift.io/gitops-registration-name"
+
+	// GitOpsRegistrationHashAnnotation is the annotation holding the hash of the rendered object registering a
+	// cluster with a GitOps system. The object is only updated when the hash changes.
+	GitOpsRegistrationHashAnnotation = "hive.openshift.io/gitops-registration-hash"
+
+	// GitOpsRegisteredObjectsAnnotation is the annotation on a ClusterDeployment listing the objects registering it
+	// with GitOps systems, so that they can be deleted when it is deprovisioned or deregistered.
+	GitOpsRegisteredObjectsAnnotation = "hive.openshift.io/gitops-registered-objects"
+
 	// IBMCloudAPIKeySecretKey is a key used to store an api key token within a secret
 	IBMCloudAPIKeySecretKey = "ibmcloud_api_key"
 
@@ -517,8 +600,8 @@ const (
 
 	// OverrideMachinePoolPlatformAnnotation can be set to "true" on a MachinePool to bypass the validating admission
 	// hook that normally forbids modifying its Spec.Platform. By setting this annotation, you are taking responsibility
-	// for forcing rollout of such changes on the target cluster -- e.g. by deleting the Machines -- as the machine
-	// config operator will not do so.
+	// for forcing rollout of such changes on the target cluster -- e.g. by deleting the Machines, or by setting a
+	// rolloutStrategy on the MachinePool -- as the machine config operator will not do so.
 	OverrideMachinePoolPlatformAnnotation = "hive.openshift.io/override-machinepool-platform"
 
 	// MinimalInstallModeAnnotation, if set to "true" on a ClusterDeployment along with InstallerImageOverride, asks hive
@@ -0,0 +1,346 @@
+package apiservertunnel
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/apiservertunnel"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/images"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = hivev1.APIServerTunnelControllerName
+
+	tokenBytes = 32
+)
+
+// agentRegistry reports which agents are connected to the tunnel endpoint.
+type agentRegistry interface {
+	Connected(cd types.NamespacedName) bool
+}
+
+// Add creates a new APIServerTunnel controller and adds it to the manager with default RBAC. When the tunnel endpoint
+// is configured, it also adds the server of the endpoint to the manager.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+
+	config, err := ReadAPIServerTunnelConfig()
+	if err != nil {
+		logger.WithError(err).Error("could not read API server tunnel configuration")
+		return err
+	}
+
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileAPIServerTunnel{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		scheme: mgr.GetScheme(),
+		logger: logger,
+		config: config,
+	}
+
+	c, err := controller.New("apiservertunnel-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{}, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{})); err != nil {
+		logger.WithError(err).Error("Error watching ClusterDeployment")
+		return err
+	}
+
+	if config == nil {
+		return nil
+	}
+	if err := apiservertunnel.ValidateConfig(config); err != nil {
+		logger.WithError(err).Error("invalid API server tunnel configuration")
+		return err
+	}
+
+	// Reconcile ClusterDeployments when their agents connect or disconnect, to update their conditions.
+	agentChanges := make(chan event.TypedGenericEvent[*hivev1.ClusterDeployment], 100)
+	server := apiservertunnel.NewServer(r.Client, config, func(cd types.NamespacedName) {
+		agentChanges <- event.TypedGenericEvent[*hivev1.ClusterDeployment]{
+			Object: &hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: cd.Name}},
+		}
+	}, logger)
+	r.agents = server
+	if err := c.Watch(source.Channel(agentChanges, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{})); err != nil {
+		logger.WithError(err).Error("Error watching API server tunnel agents")
+		return err
+	}
+	if err := mgr.Add(server); err != nil {
+		logger.WithError(err).Error("Error adding API server tunnel server")
+		return err
+	}
+
+	return nil
+}
+
+// ReadAPIServerTunnelConfig reads the API server tunnel configuration from the file named by the
+// APIServerTunnelConfigFileEnvVar environment variable. It returns nil when the tunnel endpoint is not configured.
+func ReadAPIServerTunnelConfig() (*hivev1.APIServerTunnelConfig, error) {
+	path := os.Getenv(constants.APIServerTunnelConfigFileEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	fileBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the API server tunnel config file")
+	}
+	config := &hivev1.APIServerTunnelConfig{}
+	if err := json.Unmarshal(fileBytes, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+var _ reconcile.Reconciler = &ReconcileAPIServerTunnel{}
+
+// ReconcileAPIServerTunnel syncs the API server tunnel agent to the clusters of ClusterDeployments with the tunnel
+// enabled, and reports whether their agents are connected.
+type ReconcileAPIServerTunnel struct {
+	client.Client
+	scheme *runtime.Scheme
+
+	logger log.FieldLogger
+
+	config *hivev1.APIServerTunnelConfig
+
+	// agents is nil when the tunnel endpoint is not configured
+	agents agentRegistry
+}
+
+// Reconcile ensures the agent secret and SyncSet of a ClusterDeployment with the API server tunnel enabled, and
+// sets its APIServerTunnelConnected condition.
+func (r *ReconcileAPIServerTunnel) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cdLog := controllerutils.BuildControllerLogger(ControllerName, "clusterDeployment", request.NamespacedName)
+	cdLog.Debug("reconciling cluster deployment")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, cdLog)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := r.Get(ctx, request.NamespacedName, cd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster deployment")
+		return reconcile.Result{}, err
+	}
+	cdLog = controllerutils.AddLogFields(controllerutils.MetaObjectLogTagger{Object: cd}, cdLog)
+	if cd.DeletionTimestamp != nil {
+		// The agent secret and SyncSet are garbage collected with the ClusterDeployment.
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.ControlPlaneConfig.APIServerTunnel {
+		return reconcile.Result{}, r.cleanup(ctx, cd, cdLog)
+	}
+
+	if r.config == nil {
+		return reconcile.Result{}, r.setConnectedCondition(ctx, cd, corev1.ConditionFalse, "TunnelNotConfigured",
+			"the API server tunnel endpoint is not configured in HiveConfig", cdLog)
+	}
+	if err := r.syncAgentSecret(ctx, cd, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+	if err := r.syncAgentSyncSet(ctx, cd, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+	if r.agents.Connected(request.NamespacedName) {
+		return reconcile.Result{}, r.setConnectedCondition(ctx, cd, corev1.ConditionTrue, "AgentConnected",
+			"the agent is connected to the API server tunnel endpoint", cdLog)
+	}
+	return reconcile.Result{}, r.setConnectedCondition(ctx, cd, corev1.ConditionFalse, "AgentNotConnected",
+		"the agent is not connected to the API server tunnel endpoint", cdLog)
+}
+
+// syncAgentSecret ensures the secret holding the configuration of the agent, generating its token once.
+func (r *ReconcileAPIServerTunnel) syncAgentSecret(ctx context.Context, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	var ca []byte
+	if ref := r.config.ServingCertificateSecretRef; ref != nil {
+		servingSecret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: ref.Name}, servingSecret); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error getting API server tunnel serving certificate secret")
+			return err
+		}
+		ca = servingSecret.Data[apiservertunnel.AgentCAKey]
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: apiservertunnel.AgentSecretName(cd.Name)}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[constants.ClusterDeploymentNameLabel] = cd.Name
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		if len(secret.Data[apiservertunnel.AgentTokenKey]) == 0 {
+			token := make([]byte, tokenBytes)
+			if _, err := rand.Read(token); err != nil {
+				return err
+			}
+			secret.Data[apiservertunnel.AgentTokenKey] = []byte(hex.EncodeToString(token))
+		}
+		secret.Data[apiservertunnel.AgentURLKey] = []byte(r.config.URL)
+		secret.Data[apiservertunnel.AgentNamespaceKey] = []byte(cd.Namespace)
+		secret.Data[apiservertunnel.AgentNameKey] = []byte(cd.Name)
+		if len(ca) > 0 {
+			secret.Data[apiservertunnel.AgentCAKey] = ca
+		} else {
+			delete(secret.Data, apiservertunnel.AgentCAKey)
+		}
+		return controllerutil.SetControllerReference(cd, secret, r.scheme)
+	})
+	if err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error syncing API server tunnel agent secret")
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		cdLog.WithField("result", result).Info("synced API server tunnel agent secret")
+	}
+	return nil
+}
+
+// syncAgentSyncSet ensures the SyncSet syncing the agent and its secret to the remote cluster.
+func (r *ReconcileAPIServerTunnel) syncAgentSyncSet(ctx context.Context, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	var resources []runtime.RawExtension
+	for _, obj := range apiservertunnel.AgentResources(images.GetHiveImage(), images.GetHiveImagePullPolicy()) {
+		resources = append(resources, runtime.RawExtension{Object: obj})
+	}
+
+	syncSet := &hivev1.SyncSet{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: apiservertunnel.AgentSecretName(cd.Name)}}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, syncSet, func() error {
+		if syncSet.Labels == nil {
+			syncSet.Labels = map[string]string{}
+		}
+		syncSet.Labels[constants.ClusterDeploymentNameLabel] = cd.Name
+		syncSet.Labels[constants.SyncSetTypeLabel] = constants.SyncSetTypeAPIServerTunnel
+		if syncSet.Annotations == nil {
+			syncSet.Annotations = map[string]string{}
+		}
+		syncSet.Annotations[constants.SyncSetMetricsGroupAnnotation] = constants.SyncSetTypeAPIServerTunnel
+		syncSet.Spec = hivev1.SyncSetSpec{
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				Resources:         resources,
+				ResourceApplyMode: hivev1.SyncResourceApplyMode,
+				Secrets: []hivev1.SecretMapping{{
+					SourceRef: hivev1.SecretReference{Namespace: cd.Namespace, Name: apiservertunnel.AgentSecretName(cd.Name)},
+					TargetRef: hivev1.SecretReference{Namespace: apiservertunnel.AgentNamespace, Name: apiservertunnel.AgentName},
+				}},
+			},
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: cd.Name}},
+		}
+		return controllerutil.SetControllerReference(cd, syncSet, r.scheme)
+	})
+	if err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error syncing API server tunnel agent syncset")
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		cdLog.WithField("result", result).Info("synced API server tunnel agent syncset")
+	}
+	return nil
+}
+
+// cleanup deletes the agent secret and SyncSet, and the APIServerTunnelConnected condition, of a ClusterDeployment
+// whose API server tunnel is disabled.
+func (r *ReconcileAPIServerTunnel) cleanup(ctx context.Context, cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	name := apiservertunnel.AgentSecretName(cd.Name)
+	for _, obj := range []client.Object{
+		&hivev1.SyncSet{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: name}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: name}},
+	} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error getting API server tunnel agent resource")
+			return err
+		}
+		if !metav1.IsControlledBy(obj, cd) {
+			continue
+		}
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting API server tunnel agent resource")
+			return err
+		}
+		cdLog.WithField("name", name).Infof("deleted API server tunnel agent %T", obj)
+	}
+
+	conditions := make([]hivev1.ClusterDeploymentCondition, 0, len(cd.Status.Conditions))
+	for _, cond := range cd.Status.Conditions {
+		if cond.Type != hivev1.APIServerTunnelConnectedCondition {
+			conditions = append(conditions, cond)
+		}
+	}
+	if len(conditions) == len(cd.Status.Conditions) {
+		return nil
+	}
+	cd.Status.Conditions = conditions
+	if err := r.Status().Update(ctx, cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error removing APIServerTunnelConnected condition")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileAPIServerTunnel) setConnectedCondition(ctx context.Context, cd *hivev1.ClusterDeployment, status corev1.ConditionStatus, reason, message string, cdLog log.FieldLogger) error {
+	conditions, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.APIServerTunnelConnectedCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if !changed {
+		return nil
+	}
+	cd.Status.Conditions = conditions
+	if err := r.Status().Update(ctx, cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating APIServerTunnelConnected condition")
+		return err
+	}
+	cdLog.WithField("reason", reason).Info("updated APIServerTunnelConnected condition")
+	return nil
+}
@@ -0,0 +1,218 @@
+package apiservertunnel
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/apiservertunnel"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace         = "test-namespace"
+	testName              = "test-cluster"
+	testHiveNamespace     = "hive"
+	testServingSecretName = "tunnel-serving"
+	testURL               = "https://tunnel.example.com"
+	testToken             = "existing-token"
+	testCA                = "test-ca"
+)
+
+type fakeRegistry map[types.NamespacedName]bool
+
+func (f fakeRegistry) Connected(cd types.NamespacedName) bool {
+	return f[cd]
+}
+
+func withAPIServerTunnel(cd *hivev1.ClusterDeployment) {
+	cd.Spec.ControlPlaneConfig.APIServerTunnel = true
+}
+
+func testClusterDeployment(opts ...testcd.Option) *hivev1.ClusterDeployment {
+	return testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(append([]testcd.Option{testcd.Installed()}, opts...)...)
+}
+
+func tunnelCondition(status corev1.ConditionStatus, reason string) testcd.Option {
+	return testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+		Type:   hivev1.APIServerTunnelConnectedCondition,
+		Status: status,
+		Reason: reason,
+	})
+}
+
+func ownedBy(cd *hivev1.ClusterDeployment, obj metav1.Object) {
+	obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cd, hivev1.SchemeGroupVersion.WithKind("ClusterDeployment"))})
+}
+
+func TestReconcile(t *testing.T) {
+	servingSecret := testsecret.FullBuilder(testHiveNamespace, testServingSecretName, scheme.GetScheme()).Build(
+		testsecret.WithDataKeyValue(apiservertunnel.AgentCAKey, []byte(testCA)))
+	config := &hivev1.APIServerTunnelConfig{
+		URL:                         testURL,
+		ServingCertificateSecretRef: &corev1.LocalObjectReference{Name: testServingSecretName},
+	}
+	cdKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+
+	cases := []struct {
+		name              string
+		cd                *hivev1.ClusterDeployment
+		existing          []runtime.Object
+		existingOwned     bool
+		config            *hivev1.APIServerTunnelConfig
+		connected         bool
+		expectResources   bool
+		expectToken       string
+		expectCA          string
+		expectNoCondition bool
+		expectStatus      corev1.ConditionStatus
+		expectReason      string
+	}{
+		{
+			name:              "tunnel disabled",
+			cd:                testClusterDeployment(),
+			config:            config,
+			expectNoCondition: true,
+		},
+		{
+			name:         "tunnel not configured",
+			cd:           testClusterDeployment(withAPIServerTunnel),
+			expectStatus: corev1.ConditionFalse,
+			expectReason: "TunnelNotConfigured",
+		},
+		{
+			name:            "agent not connected",
+			cd:              testClusterDeployment(withAPIServerTunnel),
+			existing:        []runtime.Object{servingSecret},
+			config:          config,
+			expectResources: true,
+			expectCA:        testCA,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    "AgentNotConnected",
+		},
+		{
+			name:            "agent connected",
+			cd:              testClusterDeployment(withAPIServerTunnel, tunnelCondition(corev1.ConditionFalse, "AgentNotConnected")),
+			existing:        []runtime.Object{servingSecret},
+			config:          config,
+			connected:       true,
+			expectResources: true,
+			expectCA:        testCA,
+			expectStatus:    corev1.ConditionTrue,
+			expectReason:    "AgentConnected",
+		},
+		{
+			name: "existing token kept",
+			cd:   testClusterDeployment(withAPIServerTunnel),
+			existing: []runtime.Object{
+				servingSecret,
+				testsecret.FullBuilder(testNamespace, apiservertunnel.AgentSecretName(testName), scheme.GetScheme()).Build(
+					testsecret.WithDataKeyValue(apiservertunnel.AgentTokenKey, []byte(testToken))),
+			},
+			config:          config,
+			expectResources: true,
+			expectToken:     testToken,
+			expectCA:        testCA,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    "AgentNotConnected",
+		},
+		{
+			name:            "no serving certificate secret reference",
+			cd:              testClusterDeployment(withAPIServerTunnel),
+			config:          &hivev1.APIServerTunnelConfig{URL: testURL},
+			expectResources: true,
+			expectStatus:    corev1.ConditionFalse,
+			expectReason:    "AgentNotConnected",
+		},
+		{
+			name:              "tunnel disabled cleans up",
+			cd:                testClusterDeployment(tunnelCondition(corev1.ConditionTrue, "AgentConnected")),
+			existingOwned:     true,
+			config:            config,
+			expectNoCondition: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(constants.HiveNamespaceEnvVar, testHiveNamespace)
+			existing := append([]runtime.Object{tc.cd}, tc.existing...)
+			if tc.existingOwned {
+				secret := testsecret.FullBuilder(testNamespace, apiservertunnel.AgentSecretName(testName), scheme.GetScheme()).Build()
+				ownedBy(tc.cd, secret)
+				syncSet := &hivev1.SyncSet{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: apiservertunnel.AgentSecretName(testName)}}
+				ownedBy(tc.cd, syncSet)
+				existing = append(existing, secret, syncSet)
+			}
+			c := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+			r := &ReconcileAPIServerTunnel{
+				Client: c,
+				scheme: scheme.GetScheme(),
+				logger: log.WithField("controller", "apiservertunnel"),
+				config: tc.config,
+				agents: fakeRegistry{cdKey: tc.connected},
+			}
+
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: cdKey})
+			require.NoError(t, err, "unexpected error from Reconcile")
+
+			secret := &corev1.Secret{}
+			secretErr := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: apiservertunnel.AgentSecretName(testName)}, secret)
+			syncSet := &hivev1.SyncSet{}
+			syncSetErr := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: apiservertunnel.AgentSecretName(testName)}, syncSet)
+			if tc.expectResources {
+				require.NoError(t, secretErr, "expected the agent secret")
+				assert.Equal(t, testURL, string(secret.Data[apiservertunnel.AgentURLKey]), "unexpected URL")
+				assert.Equal(t, testNamespace, string(secret.Data[apiservertunnel.AgentNamespaceKey]), "unexpected namespace")
+				assert.Equal(t, testName, string(secret.Data[apiservertunnel.AgentNameKey]), "unexpected name")
+				assert.Equal(t, tc.expectCA, string(secret.Data[apiservertunnel.AgentCAKey]), "unexpected CA")
+				if tc.expectToken != "" {
+					assert.Equal(t, tc.expectToken, string(secret.Data[apiservertunnel.AgentTokenKey]), "expected the existing token to be kept")
+				} else {
+					assert.Len(t, secret.Data[apiservertunnel.AgentTokenKey], 2*tokenBytes, "expected a generated token")
+				}
+				assert.True(t, metav1.IsControlledBy(secret, tc.cd), "expected the secret to be owned by the cluster deployment")
+
+				require.NoError(t, syncSetErr, "expected the agent syncset")
+				assert.Equal(t, constants.SyncSetTypeAPIServerTunnel, syncSet.Labels[constants.SyncSetTypeLabel], "unexpected syncset type")
+				assert.Equal(t, []corev1.LocalObjectReference{{Name: testName}}, syncSet.Spec.ClusterDeploymentRefs, "unexpected cluster deployment refs")
+				assert.Len(t, syncSet.Spec.Resources, 3, "unexpected number of resources")
+				if assert.Len(t, syncSet.Spec.Secrets, 1, "expected one secret mapping") {
+					assert.Equal(t, hivev1.SecretReference{Namespace: apiservertunnel.AgentNamespace, Name: apiservertunnel.AgentName}, syncSet.Spec.Secrets[0].TargetRef, "unexpected secret target")
+				}
+				assert.True(t, metav1.IsControlledBy(syncSet, tc.cd), "expected the syncset to be owned by the cluster deployment")
+			} else {
+				assert.True(t, apierrors.IsNotFound(secretErr), "expected no agent secret")
+				assert.True(t, apierrors.IsNotFound(syncSetErr), "expected no agent syncset")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, c.Get(context.TODO(), client.ObjectKeyFromObject(tc.cd), cd))
+			cond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.APIServerTunnelConnectedCondition)
+			if tc.expectNoCondition {
+				assert.Nil(t, cond, "expected no APIServerTunnelConnected condition")
+				return
+			}
+			if assert.NotNil(t, cond, "expected APIServerTunnelConnected condition") {
+				assert.Equal(t, tc.expectStatus, cond.Status, "unexpected condition status")
+				assert.Equal(t, tc.expectReason, cond.Reason, "unexpected condition reason")
+			}
+		})
+	}
+}
@@ -34,8 +34,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/flowcontrol"
@@ -62,6 +66,8 @@ const (
 	argoCDServiceAccountName = "argocd-server"
 
 	adminKubeConfigKey = "kubeconfig"
+
+	argoCDSecretTypeLabel = "argocd.argoproj.io/secret-type"
 )
 
 // Add creates a new Argocdregister Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
@@ -73,21 +79,49 @@ func Add(mgr manager.Manager) error {
 		logger.WithError(err).Error("could not get controller configurations")
 		return err
 	}
-	return AddToManager(mgr, NewReconciler(mgr, logger, clientRateLimiter), concurrentReconciles, queueRateLimiter)
+	config, err := ReadArgoCDConfig()
+	if err != nil {
+		logger.WithError(err).Error("could not read ArgoCD configuration")
+		return err
+	}
+	return AddToManager(mgr, NewReconciler(mgr, logger, clientRateLimiter, config), concurrentReconciles, queueRateLimiter)
 }
 
 // NewReconciler returns a new reconcile.Reconciler
-func NewReconciler(mgr manager.Manager, logger log.FieldLogger, rateLimiter flowcontrol.RateLimiter) reconcile.Reconciler {
+func NewReconciler(mgr manager.Manager, logger log.FieldLogger, rateLimiter flowcontrol.RateLimiter, config *hivev1.ArgoCDConfig) reconcile.Reconciler {
 	r := &ArgoCDRegisterController{
 		Client:                 controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &rateLimiter),
 		scheme:                 mgr.GetScheme(),
 		restConfig:             mgr.GetConfig(),
 		logger:                 log.WithField("controller", ControllerName),
+		config:                 config,
 		tlsClientConfigBuilder: tlsClientConfigBuilderFunc,
 	}
 	return r
 }
 
+// ReadArgoCDConfig reads the ArgoCD configuration from the file named by the ArgoCDConfigFileEnvVar environment
+// variable. It returns nil when the file is not available, in which case the namespace of the ArgoCD instance is
+// read from the ArgoCDNamespaceEnvVar environment variable.
+func ReadArgoCDConfig() (*hivev1.ArgoCDConfig, error) {
+	path := os.Getenv(constants.ArgoCDConfigFileEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	fileBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the ArgoCD config file: %w", err)
+	}
+	config := &hivev1.ArgoCDConfig{}
+	if err := json.Unmarshal(fileBytes, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler, concurrentReconciles int, rateLimiter workqueue.RateLimiter) error {
 	// Create a new controller
@@ -118,6 +152,7 @@ type ArgoCDRegisterController struct {
 	scheme                 *runtime.Scheme
 	restConfig             *rest.Config
 	logger                 log.FieldLogger
+	config                 *hivev1.ArgoCDConfig
 	tlsClientConfigBuilder func(clientcmd.ClientConfig, log.FieldLogger) (TLSClientConfig, error)
 }
 
@@ -172,12 +207,6 @@ func (r *ArgoCDRegisterController) Reconcile(ctx context.Context, request reconc
 }
 
 func (r *ArgoCDRegisterController) reconcileCluster(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
-	// Check for ArgoCDNamespace env as it comes from hive config
-	argoCDNamespace := os.Getenv(constants.ArgoCDNamespaceEnvVar)
-	if len(argoCDNamespace) == 0 {
-		argoCDNamespace = argoCDDefaultNamespace
-	}
-
 	if cd.Status.APIURL == "" {
 		cdLog.Info("installed cluster does not have Status.APIURL set yet")
 		return reconcile.Result{}, fmt.Errorf("installed cluster does not have Status.APIURL set yet")
@@ -190,16 +219,14 @@ func (r *ArgoCDRegisterController) reconcileCluster(cd *hivev1.ClusterDeployment
 		return reconcile.Result{}, err
 	}
 
+	instances := r.instances()
+
 	// Return early if cluster deployment was deleted
 	if !cd.DeletionTimestamp.IsZero() {
 		if controllerutil.ContainsFinalizer(cd, hivev1.FinalizerArgoCDCluster) {
-			// Clean up secret
-			cdLog.Info("deleting ArgoCD cluster secret ", clusterSecretName)
-			secret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{Name: clusterSecretName, Namespace: argoCDNamespace},
-			}
-			if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
-				return reconcile.Result{}, fmt.Errorf("failed to delete ArgoCD cluster secret: %w", err)
+			// Clean up secrets
+			if err := r.deregister(cd, clusterSecretName, instances, nil, cdLog); err != nil {
+				return reconcile.Result{}, err
 			}
 			// Remove finalizer from cluster deployment
 			controllerutil.RemoveFinalizer(cd, hivev1.FinalizerArgoCDCluster)
@@ -210,41 +237,95 @@ func (r *ArgoCDRegisterController) reconcileCluster(cd *hivev1.ClusterDeployment
 		return reconcile.Result{}, nil
 	}
 
+	// Determine the instances the cluster is registered in. The cluster is neither registered in nor deregistered
+	// from an instance with an invalid selector.
+	registered, unselectable := sets.New[string](), sets.New[string]()
+	var selectorErrs []error
+	if r.hibernationPolicy() == hivev1.ArgoCDHibernationPolicyDeregister && isHibernated(cd) {
+		cdLog.Debug("not registering hibernated cluster")
+	} else {
+		for _, instance := range instances {
+			if instance.ClusterDeploymentSelector == nil {
+				registered.Insert(instance.Namespace)
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(instance.ClusterDeploymentSelector)
+			if err != nil {
+				cdLog.WithError(err).WithField("argoCDNamespace", instance.Namespace).Error("invalid cluster deployment selector")
+				unselectable.Insert(instance.Namespace)
+				selectorErrs = append(selectorErrs, fmt.Errorf("invalid cluster deployment selector of the ArgoCD instance in %s: %w", instance.Namespace, err))
+				continue
+			}
+			if selector.Matches(labels.Set(cd.Labels)) {
+				registered.Insert(instance.Namespace)
+			}
+		}
+	}
+
+	for _, instance := range instances {
+		if !registered.Has(instance.Namespace) {
+			continue
+		}
+		if err := r.register(cd, clusterSecretName, instance, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+	if err := r.deregister(cd, clusterSecretName, instances, registered.Union(unselectable), cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Ensure the cluster deployment has a finalizer for cleanup
+	if !controllerutil.ContainsFinalizer(cd, hivev1.FinalizerArgoCDCluster) {
+		controllerutil.AddFinalizer(cd, hivev1.FinalizerArgoCDCluster)
+		if err := r.Update(context.TODO(), cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment")
+			return reconcile.Result{Requeue: true}, nil
+		}
+	}
+
+	return reconcile.Result{}, utilerrors.NewAggregate(selectorErrs)
+}
+
+// register creates or updates the cluster secret of a ClusterDeployment in an ArgoCD instance.
+func (r *ArgoCDRegisterController) register(cd *hivev1.ClusterDeployment, clusterSecretName string, instance hivev1.ArgoCDInstance, cdLog log.FieldLogger) error {
+	cdLog = cdLog.WithField("argoCDNamespace", instance.Namespace)
+	serviceAccountName := instance.ServiceAccountName
+	if serviceAccountName == "" {
+		serviceAccountName = argoCDServiceAccountName
+	}
 	kubeConfigSecretName := cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name // HIVE-2485 ✓
-	argoCDServerConfigBytes, err := r.generateArgoCDServerConfig(kubeConfigSecretName, cd.Namespace, argoCDNamespace, cdLog)
+	argoCDServerConfigBytes, err := r.generateArgoCDServerConfig(kubeConfigSecretName, cd.Namespace, instance.Namespace, serviceAccountName, cdLog)
 	if err != nil {
-		return reconcile.Result{}, err
+		return err
 	}
 
 	data := make(map[string][]byte)
 	data["server"] = []byte(cd.Status.APIURL)
 	data["name"] = []byte(cd.Name)
 	data["config"] = argoCDServerConfigBytes
+	project := instance.Project
+	if project == "" && r.config != nil {
+		project = r.config.Project
+	}
+	if project != "" {
+		data["project"] = []byte(project)
+	}
 
 	argoClusterSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      clusterSecretName,
-			Namespace: argoCDNamespace,
-			Labels: map[string]string{
-				"argocd.argoproj.io/secret-type": "cluster",
-				constants.CreatedByHiveLabel:     "true",
-			},
+			Namespace: instance.Namespace,
+			Labels:    r.clusterSecretLabels(cd, cdLog),
 		},
 		Data: data,
 	}
 
-	// Copy all ClusterDeployment labels onto the ArgoCD cluster secret. This will hopefully
-	// allow for dynamic generation of ArgoCD Applications (via ArgoCD ApplicationSets).
-	for k, v := range cd.Labels {
-		argoClusterSecret.Labels[k] = v
-	}
-
 	existingArgoCDClusterSecret := &corev1.Secret{}
 	err = r.Get(context.Background(), types.NamespacedName{Name: argoClusterSecret.Name, Namespace: argoClusterSecret.Namespace}, existingArgoCDClusterSecret)
 	if err != nil && errors.IsNotFound(err) {
 		cdLog.Info("creating ArgoCD cluster secret ", argoClusterSecret.Name)
 		if err := r.Create(context.TODO(), argoClusterSecret); err != nil && !errors.IsAlreadyExists(err) {
-			return reconcile.Result{}, fmt.Errorf("error creating ArgoCD cluster secret %q: %w", argoClusterSecret.Name, err)
+			return fmt.Errorf("error creating ArgoCD cluster secret %q: %w", argoClusterSecret.Name, err)
 		}
 	}
 	if err == nil {
@@ -261,35 +342,142 @@ func (r *ArgoCDRegisterController) reconcileCluster(cd *hivev1.ClusterDeployment
 			cdLog.Infof("updating ArgoCD cluster secret %s", existingArgoCDClusterSecret.Name)
 			err = r.Update(context.Background(), existingArgoCDClusterSecret)
 			if err != nil {
-				return reconcile.Result{}, fmt.Errorf("failed to update secret %s: %w", existingArgoCDClusterSecret.Name, err)
+				return fmt.Errorf("failed to update secret %s: %w", existingArgoCDClusterSecret.Name, err)
 			}
 		}
 	}
+	return nil
+}
 
-	// Ensure the cluster deployment has a finalizer for cleanup
-	if !controllerutil.ContainsFinalizer(cd, hivev1.FinalizerArgoCDCluster) {
-		controllerutil.AddFinalizer(cd, hivev1.FinalizerArgoCDCluster)
+// deregister deletes the cluster secrets of a ClusterDeployment, except from the instances in the keep namespaces.
+// Cluster secrets are found by their ArgoCD secret type and ClusterDeployment labels, so secrets in instances removed
+// from the configuration are deleted too, and by name in the configured instances, for secrets created before they
+// were labelled. Other secrets carrying the ClusterDeployment labels, such as GitOps registration secrets, are left alone.
+func (r *ArgoCDRegisterController) deregister(cd *hivev1.ClusterDeployment, clusterSecretName string, instances []hivev1.ArgoCDInstance, keep sets.Set[string], cdLog log.FieldLogger) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(context.TODO(), secrets, client.MatchingLabels{
+		argoCDSecretTypeLabel:                     "cluster",
+		constants.CreatedByHiveLabel:              "true",
+		constants.ClusterDeploymentNameLabel:      cd.Name,
+		constants.ClusterDeploymentNamespaceLabel: cd.Namespace,
+	}); err != nil {
+		return fmt.Errorf("failed to list ArgoCD cluster secrets: %w", err)
+	}
+	candidates := sets.New[types.NamespacedName]()
+	for _, secret := range secrets.Items {
+		if secret.Name != clusterSecretName {
+			continue
+		}
+		candidates.Insert(types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+	}
+	for _, instance := range instances {
+		secret := &corev1.Secret{}
+		key := types.NamespacedName{Namespace: instance.Namespace, Name: clusterSecretName}
+		if err := r.Get(context.TODO(), key, secret); err == nil {
+			candidates.Insert(key)
+		} else if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get ArgoCD cluster secret: %w", err)
+		}
+	}
+	for _, key := range candidates.UnsortedList() {
+		if keep.Has(key.Namespace) {
+			continue
+		}
+		cdLog.WithField("argoCDNamespace", key.Namespace).Info("deleting ArgoCD cluster secret ", key.Name)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		}
+		if err := r.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ArgoCD cluster secret: %w", err)
+		}
+	}
+	return nil
+}
+
+// instances returns the ArgoCD instances the clusters are registered in.
+func (r *ArgoCDRegisterController) instances() []hivev1.ArgoCDInstance {
+	if r.config != nil && len(r.config.Instances) > 0 {
+		return r.config.Instances
+	}
+	// Check for ArgoCDNamespace env as it comes from hive config
+	argoCDNamespace := os.Getenv(constants.ArgoCDNamespaceEnvVar)
+	if r.config != nil && r.config.Namespace != "" {
+		argoCDNamespace = r.config.Namespace
 	}
-	if err := r.Update(context.TODO(), cd); err != nil {
-		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment")
-		return reconcile.Result{Requeue: true}, nil
+	if len(argoCDNamespace) == 0 {
+		argoCDNamespace = argoCDDefaultNamespace
 	}
+	return []hivev1.ArgoCDInstance{{Namespace: argoCDNamespace}}
+}
 
-	return reconcile.Result{}, nil
+func (r *ArgoCDRegisterController) hibernationPolicy() hivev1.ArgoCDHibernationPolicy {
+	if r.config == nil || r.config.HibernatedClusters == "" {
+		return hivev1.ArgoCDHibernationPolicyKeep
+	}
+	return r.config.HibernatedClusters
+}
+
+// clusterSecretLabels returns the labels of the cluster secrets of a ClusterDeployment.
+func (r *ArgoCDRegisterController) clusterSecretLabels(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) map[string]string {
+	secretLabels := map[string]string{}
+	if r.config == nil || len(r.config.ClusterLabels) == 0 {
+		// Copy all ClusterDeployment labels onto the ArgoCD cluster secret. This will hopefully
+		// allow for dynamic generation of ArgoCD Applications (via ArgoCD ApplicationSets).
+		for k, v := range cd.Labels {
+			secretLabels[k] = v
+		}
+	}
+	if r.config != nil {
+		for _, mapping := range r.config.ClusterLabels {
+			var value string
+			var ok bool
+			source := mapping.FromLabel
+			if source != "" {
+				value, ok = cd.Labels[source]
+			} else if source = mapping.FromAnnotation; source != "" {
+				value, ok = cd.Annotations[source]
+			}
+			if !ok {
+				continue
+			}
+			key := mapping.ToLabel
+			if key == "" {
+				key = source
+			}
+			if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+				cdLog.WithField("label", key).Warnf("not copying invalid label value: %s", strings.Join(errs, ", "))
+				continue
+			}
+			secretLabels[key] = value
+		}
+	}
+	secretLabels[argoCDSecretTypeLabel] = "cluster"
+	secretLabels[constants.CreatedByHiveLabel] = "true"
+	secretLabels[constants.ClusterDeploymentNameLabel] = cd.Name
+	secretLabels[constants.ClusterDeploymentNamespaceLabel] = cd.Namespace
+	return secretLabels
+}
+
+// isHibernated returns whether a cluster is hibernating, or is not running yet after resuming from hibernation.
+func isHibernated(cd *hivev1.ClusterDeployment) bool {
+	if cd.Spec.PowerState == hivev1.ClusterPowerStateHibernating {
+		return true
+	}
+	return cd.Status.PowerState != "" && cd.Status.PowerState != hivev1.ClusterPowerStateRunning
 }
 
-func (r *ArgoCDRegisterController) loadArgoCDServiceAccountToken(argoCDNamespace string) (string, error) {
+func (r *ArgoCDRegisterController) loadArgoCDServiceAccountToken(argoCDNamespace, serviceAccountName string) (string, error) {
 	serviceAccount := &corev1.ServiceAccount{}
 	err := r.Client.Get(context.Background(),
 		types.NamespacedName{
-			Name:      argoCDServiceAccountName,
+			Name:      serviceAccountName,
 			Namespace: argoCDNamespace,
 		}, serviceAccount)
 	if err != nil {
-		return "", fmt.Errorf("error looking up %s service account: %v", argoCDServiceAccountName, err)
+		return "", fmt.Errorf("error looking up %s service account: %v", serviceAccountName, err)
 	}
 	if len(serviceAccount.Secrets) == 0 {
-		return "", fmt.Errorf("%s service account has no secrets", argoCDServiceAccountName)
+		return "", fmt.Errorf("%s service account has no secrets", serviceAccountName)
 	}
 
 	secretName := ""
@@ -299,7 +487,7 @@ func (r *ArgoCDRegisterController) loadArgoCDServiceAccountToken(argoCDNamespace
 		}
 	}
 	if secretName == "" {
-		return "", fmt.Errorf("%s service account has no token secret", argoCDServiceAccountName)
+		return "", fmt.Errorf("%s service account has no token secret", serviceAccountName)
 	}
 
 	secret := &corev1.Secret{}
@@ -318,14 +506,14 @@ func (r *ArgoCDRegisterController) loadArgoCDServiceAccountToken(argoCDNamespace
 	return string(token), nil
 }
 
-func (r *ArgoCDRegisterController) generateArgoCDServerConfig(kubeconfigSecretName, kubeConfigSecretNamespace, argoCDNamespace string, cdLog log.FieldLogger) ([]byte, error) {
+func (r *ArgoCDRegisterController) generateArgoCDServerConfig(kubeconfigSecretName, kubeConfigSecretNamespace, argoCDNamespace, serviceAccountName string, cdLog log.FieldLogger) ([]byte, error) {
 	kubeconfig, err := controllerutils.LoadSecretData(r.Client, kubeconfigSecretName, kubeConfigSecretNamespace, adminKubeConfigKey)
 	if err != nil {
 		cdLog.WithError(err).Error("unable to load cluster admin kubeconfig")
 		return nil, err
 	}
 
-	managerBearerToken, err := r.loadArgoCDServiceAccountToken(argoCDNamespace)
+	managerBearerToken, err := r.loadArgoCDServiceAccountToken(argoCDNamespace, serviceAccountName)
 	if err != nil {
 		cdLog.WithError(err).Error("unable to load argocd service account token")
 		return nil, err
@@ -85,6 +85,7 @@ func TestArgoCDRegisterReconcile(t *testing.T) {
 		validate             func(client.Client, *testing.T)
 		reconcilerSetup      func(*ArgoCDRegisterController)
 		argoCDEnabled        bool
+		config               *hivev1.ArgoCDConfig
 	}{
 		{
 			name: "Create ArgoCD cluster secret",
@@ -187,6 +188,214 @@ func TestArgoCDRegisterReconcile(t *testing.T) {
 				assert.Nil(t, secret, "found unexepcted ArgoCD cluster secret")
 			},
 		},
+		{
+			name: "Project and mapped labels",
+			existing: append([]runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Annotations = map[string]string{
+						"example.com/owner":   "team-a",
+						"example.com/invalid": "not a label value",
+					}
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+			}, testArgoCDInstance(argoCDDefaultNamespace, argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config: &hivev1.ArgoCDConfig{
+				Project: "team-a",
+				ClusterLabels: []hivev1.ArgoCDClusterLabel{
+					{FromLabel: hivev1.HiveClusterRegionLabel, ToLabel: "region"},
+					{FromAnnotation: "example.com/owner"},
+					{FromAnnotation: "example.com/invalid"},
+					{FromLabel: "missing"},
+				},
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				secret := getSecret(c, secretName, argoCDDefaultNamespace)
+				if assert.NotNil(t, secret, "ArgoCD cluster secret not found") {
+					assert.Equal(t, "team-a", string(secret.Data["project"]), "unexpected project")
+					assert.Equal(t, map[string]string{
+						"argocd.argoproj.io/secret-type":          "cluster",
+						constants.CreatedByHiveLabel:              "true",
+						constants.ClusterDeploymentNameLabel:      testName,
+						constants.ClusterDeploymentNamespaceLabel: testNamespace,
+						"region":            "us-east-1",
+						"example.com/owner": "team-a",
+					}, secret.Labels, "unexpected labels")
+				}
+			},
+		},
+		{
+			name: "Multiple instances",
+			existing: append(append([]runtime.Object{
+				testClusterDeployment(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+			}, testArgoCDInstance("argocd-a", argoCDServiceAccountName)...), testArgoCDInstance("argocd-b", "gitops")...),
+			argoCDEnabled: true,
+			config: &hivev1.ArgoCDConfig{
+				Project: "default-project",
+				Instances: []hivev1.ArgoCDInstance{
+					{Namespace: "argocd-a"},
+					{
+						Namespace:          "argocd-b",
+						ServiceAccountName: "gitops",
+						Project:            "b-project",
+						ClusterDeploymentSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{hivev1.HiveClusterPlatformLabel: "aws"},
+						},
+					},
+					{
+						Namespace: "argocd-c",
+						ClusterDeploymentSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{hivev1.HiveClusterPlatformLabel: "gcp"},
+						},
+					},
+				},
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				if secret := getSecret(c, secretName, "argocd-a"); assert.NotNil(t, secret, "ArgoCD cluster secret not found in argocd-a") {
+					assert.Equal(t, "default-project", string(secret.Data["project"]), "unexpected project in argocd-a")
+				}
+				if secret := getSecret(c, secretName, "argocd-b"); assert.NotNil(t, secret, "ArgoCD cluster secret not found in argocd-b") {
+					assert.Equal(t, "b-project", string(secret.Data["project"]), "unexpected project in argocd-b")
+				}
+				assert.Nil(t, getSecret(c, secretName, "argocd-c"), "found unexpected ArgoCD cluster secret in argocd-c")
+				assert.Contains(t, cd.Finalizers, hivev1.FinalizerArgoCDCluster)
+			},
+		},
+		{
+			name: "Deregister hibernated cluster",
+			existing: append([]runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.PowerState = hivev1.ClusterPowerStateHibernating
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+				// Existing ArgoCD cluster secret, created before cluster secrets were labelled
+				testSecretWithNamespace(corev1.SecretTypeDockerConfigJson, "cluster-test-api.test.com-2774145043", argoCDDefaultNamespace, "test", "{}"),
+			}, testArgoCDInstance(argoCDDefaultNamespace, argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config: &hivev1.ArgoCDConfig{
+				HibernatedClusters: hivev1.ArgoCDHibernationPolicyDeregister,
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				assert.Nil(t, getSecret(c, secretName, argoCDDefaultNamespace), "found unexpected ArgoCD cluster secret")
+				assert.Contains(t, cd.Finalizers, hivev1.FinalizerArgoCDCluster)
+			},
+		},
+		{
+			name: "Keep hibernated cluster",
+			existing: append([]runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.PowerState = hivev1.ClusterPowerStateHibernating
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+			}, testArgoCDInstance(argoCDDefaultNamespace, argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config:        &hivev1.ArgoCDConfig{},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				assert.NotNil(t, getSecret(c, secretName, argoCDDefaultNamespace), "ArgoCD cluster secret not found")
+			},
+		},
+		{
+			name: "Delete ArgoCD cluster secret of removed instance",
+			existing: append([]runtime.Object{
+				testClusterDeployment(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+				func() *corev1.Secret {
+					s := testSecretWithNamespace(corev1.SecretTypeOpaque, "cluster-test-api.test.com-2774145043", "argocd-removed", "test", "{}")
+					s.Labels = map[string]string{
+						argoCDSecretTypeLabel:                     "cluster",
+						constants.CreatedByHiveLabel:              "true",
+						constants.ClusterDeploymentNameLabel:      testName,
+						constants.ClusterDeploymentNamespaceLabel: testNamespace,
+					}
+					return s
+				}(),
+			}, testArgoCDInstance("argocd-new", argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config: &hivev1.ArgoCDConfig{
+				Instances: []hivev1.ArgoCDInstance{{Namespace: "argocd-new"}},
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				assert.NotNil(t, getSecret(c, secretName, "argocd-new"), "ArgoCD cluster secret not found")
+				assert.Nil(t, getSecret(c, secretName, "argocd-removed"), "found unexpected ArgoCD cluster secret")
+			},
+		},
+		{
+			name: "Keep ArgoCD cluster secret of instance with invalid selector",
+			existing: append([]runtime.Object{
+				testClusterDeployment(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+				func() *corev1.Secret {
+					s := testSecretWithNamespace(corev1.SecretTypeOpaque, "cluster-test-api.test.com-2774145043", "argocd-invalid", "test", "{}")
+					s.Labels = map[string]string{
+						argoCDSecretTypeLabel:                     "cluster",
+						constants.CreatedByHiveLabel:              "true",
+						constants.ClusterDeploymentNameLabel:      testName,
+						constants.ClusterDeploymentNamespaceLabel: testNamespace,
+					}
+					return s
+				}(),
+			}, testArgoCDInstance("argocd-new", argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config: &hivev1.ArgoCDConfig{
+				Instances: []hivev1.ArgoCDInstance{
+					{Namespace: "argocd-new"},
+					{
+						Namespace: "argocd-invalid",
+						ClusterDeploymentSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "region", Operator: "Near"}},
+						},
+					},
+				},
+			},
+			expectErr: true,
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				assert.NotNil(t, getSecret(c, secretName, "argocd-new"), "ArgoCD cluster secret not found in argocd-new")
+				assert.NotNil(t, getSecret(c, secretName, "argocd-invalid"), "ArgoCD cluster secret not found in argocd-invalid")
+			},
+		},
+		{
+			name: "Keep other secrets with ClusterDeployment labels",
+			existing: append([]runtime.Object{
+				testClusterDeployment(),
+				testSecret(corev1.SecretTypeDockerConfigJson, "foo-lqmsh-admin-kubeconfig", "kubeconfig", "{}"),
+				func() *corev1.Secret {
+					s := testSecretWithNamespace(corev1.SecretTypeOpaque, "foo-kubeconfig", "flux-system", "value", "{}")
+					s.Labels = map[string]string{
+						constants.CreatedByHiveLabel:              "true",
+						constants.ClusterDeploymentNameLabel:      testName,
+						constants.ClusterDeploymentNamespaceLabel: testNamespace,
+					}
+					return s
+				}(),
+			}, testArgoCDInstance(argoCDDefaultNamespace, argoCDServiceAccountName)...),
+			argoCDEnabled: true,
+			config:        &hivev1.ArgoCDConfig{},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				secretName, _ := getPredictableSecretName(cd.Status.APIURL)
+				assert.NotNil(t, getSecret(c, secretName, argoCDDefaultNamespace), "ArgoCD cluster secret not found")
+				assert.NotNil(t, getSecret(c, "foo-kubeconfig", "flux-system"), "secret without the ArgoCD secret type was deleted")
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -207,6 +416,7 @@ func TestArgoCDRegisterReconcile(t *testing.T) {
 				scheme:     scheme,
 				logger:     logger,
 				restConfig: &rest.Config{},
+				config:     test.config,
 				tlsClientConfigBuilder: func(kubeConfig clientcmd.ClientConfig, _ log.FieldLogger) (TLSClientConfig, error) {
 					return TLSClientConfig{}, nil
 				},
@@ -333,8 +543,19 @@ func testServiceAccount(name, namespace string, secrets ...corev1.ObjectReferenc
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: argoCDDefaultNamespace,
+			Namespace: namespace,
 		},
 		Secrets: secrets,
 	}
 }
+
+// testArgoCDInstance returns the service account of an ArgoCD instance, and its token secret.
+func testArgoCDInstance(namespace, serviceAccountName string) []runtime.Object {
+	return []runtime.Object{
+		testServiceAccount(serviceAccountName, namespace,
+			corev1.ObjectReference{Kind: "Secret",
+				Name:      serviceAccountName + "-token",
+				Namespace: namespace}),
+		testSecretWithNamespace(corev1.SecretTypeDockerConfigJson, serviceAccountName+"-token", namespace, "token", "{}"),
+	}
+}
@@ -0,0 +1,252 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+const (
+	// acmeChallengeLabel is the label prepended to domains to name the TXT records of DNS-01 challenges.
+	acmeChallengeLabel = "_acme-challenge."
+
+	certificateKeyBits = 2048
+)
+
+// dnsPropagationDelay is how long to wait after publishing challenge records before asking the certificate authority
+// to validate them, exposed for testing.
+var dnsPropagationDelay = 30 * time.Second
+
+// issuer issues certificates for domains. Issuing a certificate takes several calls, so that no call waits for DNS
+// propagation or for the certificate authority.
+type issuer interface {
+	// CreateOrder creates an order for a certificate for the given domains, and publishes the TXT records proving
+	// control of the domains through solver. The returned order lacks the time the records were published.
+	CreateOrder(ctx context.Context, domains []string, solver dnsSolver, logger log.FieldLogger) (*hivev1.CertificateOrder, error)
+
+	// FinalizeOrder moves an order on once its challenge records had time to propagate: it accepts the challenges
+	// and, once the certificate authority validated them, returns a PEM encoded certificate chain and private key for
+	// the given domains. done is false while the order is still under way; once it is true, the order either issued
+	// the certificate or failed, and its challenge records were deleted.
+	FinalizeOrder(ctx context.Context, order *hivev1.CertificateOrder, domains []string, solver dnsSolver, logger log.FieldLogger) (certPEM, keyPEM []byte, done bool, err error)
+}
+
+// dnsSolver publishes the TXT records of DNS-01 challenges. It is implemented by the dnszone actuators.
+type dnsSolver interface {
+	SetTXTRecord(name string, values []string) error
+	DeleteTXTRecord(name string, values []string) error
+}
+
+// acmeIssuer issues certificates through an ACME (RFC 8555) certificate authority.
+type acmeIssuer struct {
+	client *acme.Client
+	email  string
+}
+
+var _ issuer = &acmeIssuer{}
+
+func newACMEIssuer(directoryURL, email string, accountKey crypto.Signer, httpClient *http.Client) *acmeIssuer {
+	return &acmeIssuer{
+		client: &acme.Client{
+			Key:          accountKey,
+			DirectoryURL: directoryURL,
+			HTTPClient:   httpClient,
+			UserAgent:    "hive",
+		},
+		email: email,
+	}
+}
+
+// CreateOrder implements the issuer interface.
+func (i *acmeIssuer) CreateOrder(ctx context.Context, domains []string, solver dnsSolver, logger log.FieldLogger) (*hivev1.CertificateOrder, error) {
+	if err := i.register(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to register ACME account")
+	}
+
+	order, err := i.client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ACME order")
+	}
+	logger = logger.WithField("order", order.URI)
+	logger.Info("created ACME order")
+
+	var pendingAuthzURLs []string
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := i.client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ACME authorization")
+		}
+		if authz.Status != acme.StatusValid {
+			pendingAuthzURLs = append(pendingAuthzURLs, authz.URI)
+		}
+	}
+	records, _, err := i.challengeRecords(ctx, pendingAuthzURLs)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range records {
+		logger.WithField("record", name).Info("publishing DNS-01 challenge record")
+		if err := solver.SetTXTRecord(name, values); err != nil {
+			deleteRecords(records, solver, logger)
+			return nil, errors.Wrap(err, "failed to publish DNS-01 challenge record")
+		}
+	}
+	return &hivev1.CertificateOrder{URL: order.URI, AuthorizationURLs: pendingAuthzURLs}, nil
+}
+
+// FinalizeOrder implements the issuer interface.
+func (i *acmeIssuer) FinalizeOrder(ctx context.Context, pending *hivev1.CertificateOrder, domains []string, solver dnsSolver, logger log.FieldLogger) ([]byte, []byte, bool, error) {
+	logger = logger.WithField("order", pending.URL)
+	order, err := i.client.GetOrder(ctx, pending.URL)
+	if err != nil {
+		if acmeErr, ok := err.(*acme.Error); ok && acmeErr.StatusCode == http.StatusNotFound {
+			return nil, nil, true, errors.Wrap(err, "ACME order no longer exists")
+		}
+		return nil, nil, false, errors.Wrap(err, "failed to get ACME order")
+	}
+
+	switch order.Status {
+	case acme.StatusPending:
+		// Accept the challenges not accepted yet; the certificate authority validates them asynchronously.
+		_, challenges, err := i.challengeRecords(ctx, pending.AuthorizationURLs)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for _, challenge := range challenges {
+			if challenge.Status != acme.StatusPending {
+				continue
+			}
+			logger.WithField("challenge", challenge.URI).Info("accepting DNS-01 challenge")
+			if _, err := i.client.Accept(ctx, challenge); err != nil {
+				return nil, nil, false, errors.Wrap(err, "failed to accept DNS-01 challenge")
+			}
+		}
+		return nil, nil, false, nil
+	case acme.StatusReady:
+		defer i.deleteChallengeRecords(ctx, pending, solver, logger)
+		certPEM, keyPEM, err := i.finalize(ctx, order, domains)
+		if err != nil {
+			return nil, nil, true, errors.Wrap(err, "failed to finalize ACME order")
+		}
+		logger.Info("certificate issued")
+		return certPEM, keyPEM, true, nil
+	case acme.StatusInvalid:
+		i.deleteChallengeRecords(ctx, pending, solver, logger)
+		if order.Error != nil {
+			return nil, nil, true, errors.Wrap(order.Error, "ACME order failed")
+		}
+		return nil, nil, true, errors.New("ACME order failed")
+	default:
+		// The order was finalized by an earlier attempt whose private key is lost.
+		i.deleteChallengeRecords(ctx, pending, solver, logger)
+		return nil, nil, true, fmt.Errorf("ACME order is %s after an interrupted finalization", order.Status)
+	}
+}
+
+// finalize submits the certificate request of an order whose authorizations are valid, and returns the issued
+// certificate chain and its private key.
+func (i *acmeIssuer) finalize(ctx context.Context, order *acme.Order, domains []string) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, certificateKeyBits)
+	if err != nil {
+		return nil, nil, err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: strings.TrimPrefix(domains[0], "*.")},
+		DNSNames: domains,
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	chain, _, err := i.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM, nil
+}
+
+// challengeRecords returns the TXT records proving control of the domains of the given authorizations, and their
+// DNS-01 challenges. The challenges of a domain and of its wildcard share the same record, which holds the values of
+// both.
+func (i *acmeIssuer) challengeRecords(ctx context.Context, authzURLs []string) (map[string][]string, []*acme.Challenge, error) {
+	records := map[string][]string{}
+	var challenges []*acme.Challenge
+	for _, authzURL := range authzURLs {
+		authz, err := i.client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to get ACME authorization")
+		}
+		challenge := dns01Challenge(authz)
+		if challenge == nil {
+			return nil, nil, fmt.Errorf("no dns-01 challenge offered for %s", authz.Identifier.Value)
+		}
+		value, err := i.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return nil, nil, err
+		}
+		name := acmeChallengeLabel + authz.Identifier.Value
+		records[name] = append(records[name], value)
+		challenges = append(challenges, challenge)
+	}
+	return records, challenges, nil
+}
+
+// deleteChallengeRecords deletes the TXT records published for an order. Failures are only logged, as they do not
+// prevent issuing certificates.
+func (i *acmeIssuer) deleteChallengeRecords(ctx context.Context, order *hivev1.CertificateOrder, solver dnsSolver, logger log.FieldLogger) {
+	records, _, err := i.challengeRecords(ctx, order.AuthorizationURLs)
+	if err != nil {
+		logger.WithError(err).Warn("failed to determine DNS-01 challenge records to delete")
+		return
+	}
+	deleteRecords(records, solver, logger)
+}
+
+func deleteRecords(records map[string][]string, solver dnsSolver, logger log.FieldLogger) {
+	for name, values := range records {
+		if err := solver.DeleteTXTRecord(name, values); err != nil {
+			logger.WithError(err).WithField("record", name).Warn("failed to delete DNS-01 challenge record")
+		}
+	}
+}
+
+// register registers the ACME account, or looks it up when the account key is already registered.
+func (i *acmeIssuer) register(ctx context.Context) error {
+	account := &acme.Account{}
+	if i.email != "" {
+		account.Contact = []string{"mailto:" + i.email}
+	}
+	_, err := i.client.Register(ctx, account, acme.AcceptTOS)
+	if err == acme.ErrAccountAlreadyExists {
+		return nil
+	}
+	return err
+}
+
+func dns01Challenge(authz *acme.Authorization) *acme.Challenge {
+	for _, challenge := range authz.Challenges {
+		if challenge.Type == "dns-01" {
+			return challenge
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// fakeACMEServer is a minimal ACME (RFC 8555) certificate authority. It validates DNS-01 challenges against the
+// records published in records, and does not verify request signatures.
+type fakeACMEServer struct {
+	t       *testing.T
+	server  *httptest.Server
+	client  *acme.Client
+	caKey   *ecdsa.PrivateKey
+	mu      sync.Mutex
+	records map[string][]string
+	// domains are the identifiers of the order, indexed like its authorizations.
+	domains []string
+	valid   []bool
+	cert    []byte
+}
+
+func newFakeACMEServer(t *testing.T) *fakeACMEServer {
+	s := &fakeACMEServer{t: t, records: map[string][]string{}}
+	var err error
+	s.caKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *fakeACMEServer) SetTXTRecord(name string, values []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[name] = values
+	return nil
+}
+
+func (s *fakeACMEServer) DeleteTXTRecord(name string, values []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, name)
+	return nil
+}
+
+func (s *fakeACMEServer) handle(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	url := s.server.URL
+	w.Header().Set("Replay-Nonce", fmt.Sprintf("nonce-%d", time.Now().UnixNano()))
+	if req.Method == http.MethodHead {
+		return
+	}
+	payload := s.payload(req)
+	w.Header().Set("Content-Type", "application/json")
+
+	var index int
+	switch path := req.URL.Path; {
+	case path == "/directory":
+		s.write(w, http.StatusOK, map[string]string{
+			"newNonce":   url + "/nonce",
+			"newAccount": url + "/account",
+			"newOrder":   url + "/new-order",
+		})
+	case path == "/account":
+		var account struct {
+			OnlyReturnExisting bool `json:"onlyReturnExisting"`
+		}
+		require.NoError(s.t, json.Unmarshal(payload, &account))
+		code := http.StatusCreated
+		if account.OnlyReturnExisting {
+			code = http.StatusOK
+		}
+		w.Header().Set("Location", url+"/account/1")
+		s.write(w, code, map[string]string{"status": "valid"})
+	case path == "/new-order":
+		var order struct {
+			Identifiers []acme.AuthzID `json:"identifiers"`
+		}
+		require.NoError(s.t, json.Unmarshal(payload, &order))
+		for _, id := range order.Identifiers {
+			s.domains = append(s.domains, id.Value)
+		}
+		s.valid = make([]bool, len(s.domains))
+		w.Header().Set("Location", url+"/order")
+		s.write(w, http.StatusCreated, s.order())
+	case path == "/order":
+		s.write(w, http.StatusOK, s.order())
+	case path == "/finalize":
+		var finalize struct {
+			CSR string `json:"csr"`
+		}
+		require.NoError(s.t, json.Unmarshal(payload, &finalize))
+		s.issue(finalize.CSR)
+		s.write(w, http.StatusOK, s.order())
+	case path == "/cert":
+		w.Header().Set("Content-Type", "application/pem-certificate-chain")
+		w.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.cert}))
+	case sscanf(path, "/authz/%d", &index):
+		s.write(w, http.StatusOK, s.authorization(index))
+	case sscanf(path, "/challenge/%d", &index):
+		s.validate(index)
+		s.write(w, http.StatusOK, s.authorization(index)["challenges"].([]map[string]string)[0])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func sscanf(path, format string, index *int) bool {
+	_, err := fmt.Sscanf(path, format, index)
+	return err == nil
+}
+
+// payload returns the decoded payload of a JWS request body.
+func (s *fakeACMEServer) payload(req *http.Request) []byte {
+	body, err := io.ReadAll(req.Body)
+	require.NoError(s.t, err)
+	if len(body) == 0 {
+		return nil
+	}
+	var jws struct {
+		Payload string `json:"payload"`
+	}
+	require.NoError(s.t, json.Unmarshal(body, &jws))
+	payload, err := base64.RawURLEncoding.DecodeString(jws.Payload)
+	require.NoError(s.t, err)
+	return payload
+}
+
+func (s *fakeACMEServer) write(w http.ResponseWriter, code int, body interface{}) {
+	w.WriteHeader(code)
+	require.NoError(s.t, json.NewEncoder(w).Encode(body))
+}
+
+func (s *fakeACMEServer) order() map[string]interface{} {
+	status := "ready"
+	var authzURLs []string
+	for i := range s.domains {
+		authzURLs = append(authzURLs, fmt.Sprintf("%s/authz/%d", s.server.URL, i))
+		if !s.valid[i] {
+			status = "pending"
+		}
+	}
+	order := map[string]interface{}{
+		"status":         status,
+		"authorizations": authzURLs,
+		"finalize":       s.server.URL + "/finalize",
+	}
+	if s.cert != nil {
+		order["status"] = "valid"
+		order["certificate"] = s.server.URL + "/cert"
+	}
+	return order
+}
+
+func (s *fakeACMEServer) authorization(index int) map[string]interface{} {
+	status := "pending"
+	if s.valid[index] {
+		status = "valid"
+	}
+	domain := s.domains[index]
+	return map[string]interface{}{
+		"status":     status,
+		"identifier": map[string]string{"type": "dns", "value": strings.TrimPrefix(domain, "*.")},
+		"wildcard":   strings.HasPrefix(domain, "*."),
+		"challenges": []map[string]string{{
+			"type":   "dns-01",
+			"url":    fmt.Sprintf("%s/challenge/%d", s.server.URL, index),
+			"token":  fmt.Sprintf("token-%d", index),
+			"status": status,
+		}},
+	}
+}
+
+// validate validates the challenge of an authorization when the expected value was published in its TXT record.
+func (s *fakeACMEServer) validate(index int) {
+	expected, err := s.client.DNS01ChallengeRecord(fmt.Sprintf("token-%d", index))
+	require.NoError(s.t, err)
+	for _, value := range s.records[acmeChallengeLabel+strings.TrimPrefix(s.domains[index], "*.")] {
+		if value == expected {
+			s.valid[index] = true
+		}
+	}
+}
+
+func (s *fakeACMEServer) issue(csrB64 string) {
+	der, err := base64.RawURLEncoding.DecodeString(csrB64)
+	require.NoError(s.t, err)
+	csr, err := x509.ParseCertificateRequest(der)
+	require.NoError(s.t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: csr.Subject.CommonName},
+		DNSNames:     csr.DNSNames,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+	s.cert, err = x509.CreateCertificate(rand.Reader, template, template, csr.PublicKey, s.caKey)
+	require.NoError(s.t, err)
+}
+
+func TestACMEIssuer(t *testing.T) {
+	server := newFakeACMEServer(t)
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	iss := newACMEIssuer(server.server.URL+"/directory", "admin@example.com", accountKey, nil)
+	server.client = iss.client
+	logger := log.WithField("test", t.Name())
+
+	domains := []string{"*.apps.test-cluster.example.com", "apps.test-cluster.example.com"}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	order, err := iss.CreateOrder(ctx, domains, server, logger)
+	require.NoError(t, err, "unexpected error creating order")
+	assert.Equal(t, server.server.URL+"/order", order.URL, "unexpected order URL")
+	assert.Len(t, order.AuthorizationURLs, 2, "unexpected authorizations")
+	if assert.Len(t, server.records, 1, "expected one challenge record") {
+		assert.Len(t, server.records[acmeChallengeLabel+"apps.test-cluster.example.com"], 2,
+			"challenge record should hold the values of the domain and its wildcard")
+	}
+
+	// The first call accepts the challenges, which the certificate authority validates asynchronously.
+	_, _, done, err := iss.FinalizeOrder(ctx, order, domains, server, logger)
+	require.NoError(t, err, "unexpected error accepting challenges")
+	assert.False(t, done, "order should still be under way")
+	assert.NotEmpty(t, server.records, "challenge records should be kept until the order completes")
+
+	certPEM, keyPEM, done, err := iss.FinalizeOrder(ctx, order, domains, server, logger)
+	require.NoError(t, err, "unexpected error finalizing order")
+	assert.True(t, done, "order should be done")
+
+	cert, err := parseCertificate(certPEM)
+	require.NoError(t, err, "issued certificate should parse")
+	assert.ElementsMatch(t, domains, cert.DNSNames, "unexpected certificate domains")
+	key, err := parsePrivateKey(keyPEM)
+	require.NoError(t, err, "issued private key should parse")
+	assert.Equal(t, cert.PublicKey, key.Public(), "private key should match the certificate")
+	assert.Empty(t, server.records, "challenge records should be deleted")
+}
+
+func TestACMEIssuerMissingOrder(t *testing.T) {
+	server := newFakeACMEServer(t)
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	iss := newACMEIssuer(server.server.URL+"/directory", "", accountKey, nil)
+
+	order := &hivev1.CertificateOrder{URL: server.server.URL + "/expired-order"}
+	_, _, done, err := iss.FinalizeOrder(context.Background(), order, []string{"apps.test-cluster.example.com"}, server,
+		log.WithField("test", t.Name()))
+	assert.Error(t, err, "expected an error for a missing order")
+	assert.True(t, done, "a missing order should be given up")
+}
@@ -0,0 +1,580 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/apis/hive/v1/metricsconfig"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/dnszone"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	ControllerName = hivev1.CertificateControllerName
+
+	// defaultRenewBefore is how long before they expire certificates are renewed, unless configured otherwise.
+	defaultRenewBefore = 30 * 24 * time.Hour
+
+	// stepTimeout bounds the time spent on each step of issuing a certificate.
+	stepTimeout = time.Minute
+
+	// orderPollInterval is how often an order is checked while the certificate authority validates its challenges.
+	orderPollInterval = 15 * time.Second
+
+	// retryInterval is how long to wait before retrying after a certificate could not be issued.
+	retryInterval = 5 * time.Minute
+
+	caCertificatesKey = "ca.crt"
+)
+
+var (
+	metricCertificateBundleExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_certificate_bundle_expiry_timestamp_seconds",
+		Help: "Expiry time of the generated certificate bundles of ClusterDeployments, in seconds since the epoch.",
+	}, []string{"namespace", "cluster_deployment", "certificate_bundle"})
+	metricCertificatesIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_certificates_issued_total",
+		Help: "Total number of certificates issued for certificate bundles, by result.",
+	}, []string{"result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricCertificateBundleExpiry)
+	metrics.Registry.MustRegister(metricCertificatesIssued)
+}
+
+// Add creates a new Certificate controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+
+	// Don't run the controller unless a certificate issuer has been configured.
+	config, err := ReadCertificateIssuerConfig()
+	if err != nil {
+		logger.WithError(err).Error("could not read certificate issuer configuration")
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	mConfig, err := hivemetrics.ReadMetricsConfig()
+	if err != nil {
+		logger.WithError(err).Error("error reading metrics config")
+		return err
+	}
+
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileCertificate{
+		Client:    controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		scheme:    mgr.GetScheme(),
+		logger:    logger,
+		config:    config,
+		newSolver: newDNSZoneSolver,
+		now:       time.Now,
+		reportExpiry: hivemetrics.IsClusterDeploymentMetricEnabled(
+			mConfig, metricsconfig.CertificateBundleExpiry),
+	}
+
+	c, err := controller.New("certificate-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{}, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{})); err != nil {
+		logger.WithError(err).Error("Error watching ClusterDeployment")
+		return err
+	}
+
+	return nil
+}
+
+// ReadCertificateIssuerConfig reads the certificate issuer configuration from the file named by the
+// CertificateIssuerConfigFileEnvVar environment variable. It returns nil when no certificate issuer is configured.
+func ReadCertificateIssuerConfig() (*hivev1.CertificateIssuerConfig, error) {
+	path := os.Getenv(constants.CertificateIssuerConfigFileEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	fileBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the certificate issuer config file")
+	}
+	config := &hivev1.CertificateIssuerConfig{}
+	if err := json.Unmarshal(fileBytes, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+var _ reconcile.Reconciler = &ReconcileCertificate{}
+
+// ReconcileCertificate issues and renews the generated certificate bundles of ClusterDeployments. The
+// controlplanecerts and remoteingress controllers roll the certificates out to the clusters.
+type ReconcileCertificate struct {
+	client.Client
+	scheme *runtime.Scheme
+
+	logger log.FieldLogger
+
+	config *hivev1.CertificateIssuerConfig
+
+	// issuer is created on first use, as it needs the ACME account key.
+	issuer     issuer
+	issuerLock sync.Mutex
+
+	// newSolver returns the solver of DNS-01 challenges for a DNSZone, exposed for testing
+	newSolver func(c client.Client, dnsZone *hivev1.DNSZone, logger log.FieldLogger) (dnsSolver, error)
+
+	// now returns the current time, exposed for testing
+	now func() time.Time
+
+	// reportExpiry is whether the admin opted into metricCertificateBundleExpiry, which is labelled with the namespace
+	// and name of each ClusterDeployment.
+	reportExpiry bool
+}
+
+// Reconcile issues the generated certificate bundles of a ClusterDeployment that are missing, or that expire soon.
+func (r *ReconcileCertificate) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cdLog := controllerutils.BuildControllerLogger(ControllerName, "clusterDeployment", request.NamespacedName)
+	cdLog.Info("reconciling cluster deployment")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, cdLog)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := r.Get(ctx, request.NamespacedName, cd); err != nil {
+		if apierrors.IsNotFound(err) {
+			cdLog.Debug("cluster deployment not found")
+			clearMetrics(request.Namespace, request.Name)
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster deployment")
+		return reconcile.Result{}, err
+	}
+	cdLog = controllerutils.AddLogFields(controllerutils.MetaObjectLogTagger{Object: cd}, cdLog)
+
+	if paused, err := strconv.ParseBool(cd.Annotations[constants.ReconcilePauseAnnotation]); err == nil && paused {
+		cdLog.Info("skipping reconcile due to ClusterDeployment pause annotation")
+		return reconcile.Result{}, nil
+	}
+	if cd.DeletionTimestamp != nil {
+		clearMetrics(cd.Namespace, cd.Name)
+		return reconcile.Result{}, nil
+	}
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster is not installed yet")
+		return reconcile.Result{}, nil
+	}
+
+	clearMetrics(cd.Namespace, cd.Name)
+	var statuses []hivev1.CertificateBundleStatus
+	var requeueAfter time.Duration
+	for _, bundle := range cd.Spec.CertificateBundles {
+		if !bundle.Generate {
+			continue
+		}
+		bundleLog := cdLog.WithField("certificateBundle", bundle.Name)
+		status, stepAfter, err := r.reconcileBundle(ctx, cd, bundle, bundleLog)
+		after := retryInterval
+		switch {
+		case err != nil:
+			bundleLog.WithError(err).Error("failed to generate certificate bundle")
+			status.Message = err.Error()
+		case stepAfter > 0:
+			after = stepAfter
+		default:
+			// Certificates valid for less than renewBefore are not renewed more often than retryInterval.
+			if untilRenewal := status.RenewalTime.Sub(r.now()); untilRenewal > after {
+				after = untilRenewal
+			}
+		}
+		if requeueAfter == 0 || after < requeueAfter {
+			requeueAfter = after
+		}
+		if r.reportExpiry && status.NotAfter != nil {
+			metricCertificateBundleExpiry.WithLabelValues(cd.Namespace, cd.Name, bundle.Name).
+				Set(float64(status.NotAfter.Unix()))
+		}
+		statuses = append(statuses, status)
+	}
+
+	if !equality.Semantic.DeepEqual(statuses, cd.Status.CertificateBundles) {
+		// Updating the status also triggers the controllers rolling the certificates out to the cluster.
+		cd.Status.CertificateBundles = statuses
+		if err := r.Status().Update(ctx, cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update certificate bundle status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	if requeueAfter == 0 {
+		return reconcile.Result{}, nil
+	}
+	cdLog.WithField("requeueAfter", requeueAfter).Debug("certificate bundles reconciled")
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// reconcileBundle issues the certificate of a generated certificate bundle when the certificate secret is missing,
+// does not cover the domains using the bundle, or is due for renewal. Issuing a certificate takes several reconciles,
+// with the ACME order under way recorded in the status; the returned duration is how long to wait before its next
+// step. The returned status describes the certificate in the secret, which is the previous one until the new one is
+// issued.
+func (r *ReconcileCertificate) reconcileBundle(ctx context.Context, cd *hivev1.ClusterDeployment, bundle hivev1.CertificateBundleSpec, logger log.FieldLogger) (hivev1.CertificateBundleStatus, time.Duration, error) {
+	// Until it completes or fails, the order is kept even when failing to move it on.
+	status := hivev1.CertificateBundleStatus{Name: bundle.Name}
+	for _, existing := range cd.Status.CertificateBundles {
+		if existing.Name == bundle.Name {
+			status.PendingOrder = existing.PendingOrder
+		}
+	}
+	pendingOrder := status.PendingOrder
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: cd.Namespace, Name: bundle.CertificateSecretRef.Name}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		return status, 0, err
+	}
+
+	var cert *x509.Certificate
+	if secret != nil {
+		if cert, err = parseCertificate(secret.Data[corev1.TLSCertKey]); err != nil {
+			logger.WithError(err).Info("certificate secret does not hold a valid certificate")
+		} else {
+			r.setCertificateStatus(&status, cert)
+		}
+	}
+
+	domains, err := r.bundleDomains(cd, bundle.Name)
+	if err != nil {
+		return status, 0, err
+	}
+	if len(domains) == 0 {
+		return status, 0, errors.New("certificate bundle is not used by the control plane or any ingress")
+	}
+	logger = logger.WithField("domains", domains)
+
+	if cert != nil && pendingOrder == nil {
+		switch {
+		case !sets.NewString(cert.DNSNames...).Equal(sets.NewString(domains...)):
+			logger.WithField("certificateDomains", cert.DNSNames).Info("certificate domains changed")
+		case r.now().Before(status.RenewalTime.Time):
+			logger.Debug("certificate is up to date")
+			return status, 0, nil
+		default:
+			logger.WithField("notAfter", cert.NotAfter).Info("certificate is due for renewal")
+		}
+	}
+
+	if pendingOrder != nil {
+		// Give the challenge records time to propagate before asking the certificate authority to validate them.
+		if wait := pendingOrder.ChallengeRecordsPublishedTime.Add(dnsPropagationDelay).Sub(r.now()); wait > 0 {
+			logger.WithField("order", pendingOrder.URL).Debug("waiting for challenge records to propagate")
+			return status, wait, nil
+		}
+	}
+
+	solver, err := r.dnsSolver(cd, domains, logger)
+	if err != nil {
+		return status, 0, err
+	}
+	iss, err := r.getIssuer(ctx, logger)
+	if err != nil {
+		return status, 0, err
+	}
+	stepCtx, cancel := context.WithTimeout(ctx, stepTimeout)
+	defer cancel()
+
+	if pendingOrder == nil {
+		order, err := iss.CreateOrder(stepCtx, domains, solver, logger)
+		if err != nil {
+			metricCertificatesIssued.WithLabelValues("failure").Inc()
+			return status, 0, errors.Wrap(err, "failed to create certificate order")
+		}
+		order.ChallengeRecordsPublishedTime = metav1.NewTime(r.now())
+		status.PendingOrder = order
+		return status, dnsPropagationDelay, nil
+	}
+
+	certPEM, keyPEM, done, err := iss.FinalizeOrder(stepCtx, pendingOrder, domains, solver, logger)
+	if !done {
+		if err != nil {
+			return status, 0, errors.Wrap(err, "failed to check certificate order")
+		}
+		return status, orderPollInterval, nil
+	}
+	status.PendingOrder = nil
+	if err != nil {
+		metricCertificatesIssued.WithLabelValues("failure").Inc()
+		return status, 0, errors.Wrap(err, "failed to issue certificate")
+	}
+	metricCertificatesIssued.WithLabelValues("success").Inc()
+	if cert, err = parseCertificate(certPEM); err != nil {
+		return status, 0, errors.Wrap(err, "issued certificate is invalid")
+	}
+
+	if err := r.writeSecret(ctx, cd, bundle.CertificateSecretRef.Name, certPEM, keyPEM); err != nil {
+		return status, 0, errors.Wrap(err, "failed to save certificate")
+	}
+	logger.WithField("notAfter", cert.NotAfter).Info("saved issued certificate")
+	status = hivev1.CertificateBundleStatus{Name: bundle.Name}
+	r.setCertificateStatus(&status, cert)
+	return status, 0, nil
+}
+
+func (r *ReconcileCertificate) setCertificateStatus(status *hivev1.CertificateBundleStatus, cert *x509.Certificate) {
+	renewBefore := defaultRenewBefore
+	if r.config.RenewBefore != nil {
+		renewBefore = r.config.RenewBefore.Duration
+	}
+	status.Generated = true
+	status.NotAfter = &metav1.Time{Time: cert.NotAfter}
+	status.RenewalTime = &metav1.Time{Time: cert.NotAfter.Add(-renewBefore)}
+}
+
+// bundleDomains returns the domains of the control plane and ingresses using a certificate bundle. Ingresses get
+// wildcard certificates.
+func (r *ReconcileCertificate) bundleDomains(cd *hivev1.ClusterDeployment, bundleName string) ([]string, error) {
+	domains := sets.NewString()
+	servingCerts := cd.Spec.ControlPlaneConfig.ServingCertificates
+	if servingCerts.Default == bundleName {
+		apiURL, err := remoteclient.InitialURL(r.Client, cd)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch initial API URL")
+		}
+		u, err := url.Parse(apiURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse cluster's API URL")
+		}
+		domains.Insert(u.Hostname())
+	}
+	for _, additional := range servingCerts.Additional {
+		if additional.Name == bundleName {
+			domains.Insert(additional.Domain)
+		}
+	}
+	for _, ingress := range cd.Spec.Ingress {
+		if ingress.ServingCertificate == bundleName {
+			domains.Insert("*." + ingress.Domain)
+		}
+	}
+	return domains.List(), nil
+}
+
+// dnsSolver returns the solver of DNS-01 challenges in the DNSZone of the ClusterDeployment.
+func (r *ReconcileCertificate) dnsSolver(cd *hivev1.ClusterDeployment, domains []string, logger log.FieldLogger) (dnsSolver, error) {
+	if !cd.Spec.ManageDNS {
+		return nil, errors.New("certificate bundles can only be generated for clusters with manageDNS")
+	}
+	dnsZone := &hivev1.DNSZone{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: controllerutils.DNSZoneName(cd.Name)}, dnsZone); err != nil {
+		return nil, errors.Wrap(err, "failed to get DNSZone")
+	}
+	if cond := controllerutils.FindCondition(dnsZone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition); cond == nil || cond.Status != corev1.ConditionTrue {
+		return nil, errors.New("DNSZone is not available")
+	}
+	zone := controllerutils.Undotted(dnsZone.Spec.Zone)
+	for _, domain := range domains {
+		if domain = strings.TrimPrefix(domain, "*."); domain != zone && !strings.HasSuffix(domain, "."+zone) {
+			return nil, fmt.Errorf("domain %s is not in DNS zone %s", domain, zone)
+		}
+	}
+	return r.newSolver(r.Client, dnsZone, logger)
+}
+
+// newDNSZoneSolver returns the actuator of the DNSZone, which publishes challenge records in the zone.
+func newDNSZoneSolver(c client.Client, dnsZone *hivev1.DNSZone, logger log.FieldLogger) (dnsSolver, error) {
+	actuator, err := dnszone.NewActuator(c, dnsZone, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := actuator.Refresh(); err != nil {
+		return nil, err
+	}
+	return actuator, nil
+}
+
+// getIssuer returns the ACME issuer, loading or creating the ACME account key on first use.
+func (r *ReconcileCertificate) getIssuer(ctx context.Context, logger log.FieldLogger) (issuer, error) {
+	r.issuerLock.Lock()
+	defer r.issuerLock.Unlock()
+	if r.issuer != nil {
+		return r.issuer, nil
+	}
+	accountKey, err := r.accountKey(ctx, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get ACME account key")
+	}
+	httpClient, err := r.httpClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.issuer = newACMEIssuer(r.config.DirectoryURL, r.config.Email, accountKey, httpClient)
+	return r.issuer, nil
+}
+
+// accountKey returns the ACME account key from its secret, creating the secret with a new key when it does not exist.
+func (r *ReconcileCertificate) accountKey(ctx context.Context, logger log.FieldLogger) (crypto.Signer, error) {
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: r.config.AccountKeySecretRef.Name}
+	err := r.Get(ctx, name, secret)
+	if err == nil {
+		return parsePrivateKey(secret.Data[corev1.TLSPrivateKeyKey])
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: name.Namespace, Name: name.Name},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+		},
+	}
+	logger.WithField("secret", name).Info("creating ACME account key")
+	if err := r.Create(ctx, secret); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// httpClient returns the HTTP client for the ACME directory, trusting the configured certificate authorities.
+func (r *ReconcileCertificate) httpClient(ctx context.Context) (*http.Client, error) {
+	if r.config.CACertificatesSecretRef == nil {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	name := types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: r.config.CACertificatesSecretRef.Name}
+	if err := r.Get(ctx, name, secret); err != nil {
+		return nil, errors.Wrap(err, "failed to get ACME certificate authorities")
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(secret.Data[caCertificatesKey]) {
+		return nil, fmt.Errorf("no certificate authorities found in secret %s", name)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	return &http.Client{Transport: transport}, nil
+}
+
+// writeSecret saves an issued certificate to the secret of a certificate bundle. The secret is owned by the
+// ClusterDeployment.
+func (r *ReconcileCertificate) writeSecret(ctx context.Context, cd *hivev1.ClusterDeployment, name string, certPEM, keyPEM []byte) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: name}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[constants.ClusterDeploymentNameLabel] = cd.Name
+		if secret.CreationTimestamp.IsZero() {
+			secret.Type = corev1.SecretTypeTLS
+		}
+		secret.Data = map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		}
+		return controllerutil.SetControllerReference(cd, secret, r.scheme)
+	})
+	return err
+}
+
+func clearMetrics(namespace, name string) {
+	metricCertificateBundleExpiry.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "cluster_deployment": name})
+}
+
+// parseCertificate returns the first certificate of a PEM encoded certificate chain.
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
@@ -0,0 +1,414 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testdnszone "github.com/openshift/hive/pkg/test/dnszone"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace  = "test-namespace"
+	testName       = "test-cluster"
+	testBundleName = "ingress-bundle"
+	testSecretName = "ingress-bundle-secret"
+	testZone       = "test-cluster.example.com"
+	testDomain     = "*.apps.test-cluster.example.com"
+	testOrderURL   = "https://acme.example.com/order/1"
+)
+
+// fakeIssuer issues self-signed certificates valid for validity.
+type fakeIssuer struct {
+	t        *testing.T
+	validity time.Duration
+	// err is returned when creating orders.
+	err error
+	// finalizeErr fails orders when finalizing them.
+	finalizeErr error
+	// underway keeps orders under way when finalizing them.
+	underway bool
+	ordered  [][]string
+	issued   [][]string
+}
+
+func (i *fakeIssuer) CreateOrder(ctx context.Context, domains []string, solver dnsSolver, logger log.FieldLogger) (*hivev1.CertificateOrder, error) {
+	if i.err != nil {
+		return nil, i.err
+	}
+	i.ordered = append(i.ordered, domains)
+	return &hivev1.CertificateOrder{URL: testOrderURL}, nil
+}
+
+func (i *fakeIssuer) FinalizeOrder(ctx context.Context, order *hivev1.CertificateOrder, domains []string, solver dnsSolver, logger log.FieldLogger) ([]byte, []byte, bool, error) {
+	if i.underway {
+		return nil, nil, false, nil
+	}
+	if i.finalizeErr != nil {
+		return nil, nil, true, i.finalizeErr
+	}
+	i.issued = append(i.issued, domains)
+	certPEM, keyPEM := testCertificate(i.t, domains, time.Now().Add(i.validity))
+	return certPEM, keyPEM, true, nil
+}
+
+type fakeSolver struct{}
+
+func (fakeSolver) SetTXTRecord(name string, values []string) error    { return nil }
+func (fakeSolver) DeleteTXTRecord(name string, values []string) error { return nil }
+
+func testCertificate(t *testing.T, domains []string, notAfter time.Time) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func testSecret(t *testing.T, domains []string, notAfter time.Time) *corev1.Secret {
+	certPEM, keyPEM := testCertificate(t, domains, notAfter)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+}
+
+func testDNSZone(available bool) *hivev1.DNSZone {
+	status := corev1.ConditionFalse
+	if available {
+		status = corev1.ConditionTrue
+	}
+	return testdnszone.FullBuilder(testNamespace, controllerutils.DNSZoneName(testName), scheme.GetScheme()).Build(
+		testdnszone.WithZone(testZone),
+		testdnszone.WithCondition(hivev1.DNSZoneCondition{
+			Type:   hivev1.ZoneAvailableDNSZoneCondition,
+			Status: status,
+		}),
+	)
+}
+
+func withGeneratedBundle(generate bool) testcd.Option {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.CertificateBundles = []hivev1.CertificateBundleSpec{{
+			Name:                 testBundleName,
+			Generate:             generate,
+			CertificateSecretRef: corev1.LocalObjectReference{Name: testSecretName},
+		}}
+		cd.Spec.Ingress = []hivev1.ClusterIngress{{
+			Name:               "default",
+			Domain:             "apps." + testZone,
+			ServingCertificate: testBundleName,
+		}}
+	}
+}
+
+func withManageDNS(cd *hivev1.ClusterDeployment) {
+	cd.Spec.ManageDNS = true
+}
+
+// withPendingOrder records an order whose challenge records were published the given time ago.
+func withPendingOrder(publishedAgo time.Duration) testcd.Option {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.CertificateBundles = []hivev1.CertificateBundleStatus{{
+			Name: testBundleName,
+			PendingOrder: &hivev1.CertificateOrder{
+				URL:                           testOrderURL,
+				ChallengeRecordsPublishedTime: metav1.NewTime(time.Now().Add(-publishedAgo)),
+			},
+		}}
+	}
+}
+
+func TestReconcileCertificate(t *testing.T) {
+	renewBefore := 30 * 24 * time.Hour
+	cases := []struct {
+		name             string
+		cdOptions        []testcd.Option
+		existing         []runtime.Object
+		issuerErr        error
+		finalizeErr      error
+		orderUnderway    bool
+		expectOrdered    bool
+		expectPending    bool
+		expectIssued     bool
+		expectGenerated  bool
+		expectMessage    string
+		expectNotAfterIn time.Duration
+		expectRequeue    time.Duration
+	}{
+		{
+			name:      "no generated bundles",
+			cdOptions: []testcd.Option{withGeneratedBundle(false), withManageDNS},
+			existing:  []runtime.Object{testDNSZone(true)},
+		},
+		{
+			name:          "missing certificate",
+			cdOptions:     []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing:      []runtime.Object{testDNSZone(true)},
+			expectOrdered: true,
+			expectPending: true,
+			expectRequeue: dnsPropagationDelay,
+		},
+		{
+			name:      "valid certificate",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{testDomain}, time.Now().Add(45*24*time.Hour)),
+			},
+			expectGenerated:  true,
+			expectNotAfterIn: 45 * 24 * time.Hour,
+			expectRequeue:    15 * 24 * time.Hour,
+		},
+		{
+			name:      "certificate due for renewal",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{testDomain}, time.Now().Add(10*24*time.Hour)),
+			},
+			expectOrdered:    true,
+			expectPending:    true,
+			expectGenerated:  true,
+			expectNotAfterIn: 10 * 24 * time.Hour,
+			expectRequeue:    dnsPropagationDelay,
+		},
+		{
+			name:      "certificate for other domains",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{"*.apps.other.example.com"}, time.Now().Add(45*24*time.Hour)),
+			},
+			expectOrdered:    true,
+			expectPending:    true,
+			expectGenerated:  true,
+			expectNotAfterIn: 45 * 24 * time.Hour,
+			expectRequeue:    dnsPropagationDelay,
+		},
+		{
+			name:          "challenge records propagating",
+			cdOptions:     []testcd.Option{withGeneratedBundle(true), withManageDNS, withPendingOrder(10 * time.Second)},
+			existing:      []runtime.Object{testDNSZone(true)},
+			expectPending: true,
+			expectRequeue: dnsPropagationDelay - 10*time.Second,
+		},
+		{
+			name:          "order under way",
+			cdOptions:     []testcd.Option{withGeneratedBundle(true), withManageDNS, withPendingOrder(time.Minute)},
+			existing:      []runtime.Object{testDNSZone(true)},
+			orderUnderway: true,
+			expectPending: true,
+			expectRequeue: orderPollInterval,
+		},
+		{
+			name:      "order completed",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS, withPendingOrder(time.Minute)},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{testDomain}, time.Now().Add(10*24*time.Hour)),
+			},
+			expectIssued:     true,
+			expectGenerated:  true,
+			expectNotAfterIn: 90 * 24 * time.Hour,
+			expectRequeue:    60 * 24 * time.Hour,
+		},
+		{
+			name:      "order failure keeps the previous certificate",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS, withPendingOrder(time.Minute)},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{testDomain}, time.Now().Add(10*24*time.Hour)),
+			},
+			finalizeErr:      errors.New("challenge invalid"),
+			expectGenerated:  true,
+			expectMessage:    "failed to issue certificate: challenge invalid",
+			expectNotAfterIn: 10 * 24 * time.Hour,
+			expectRequeue:    retryInterval,
+		},
+		{
+			name:          "dns not managed",
+			cdOptions:     []testcd.Option{withGeneratedBundle(true)},
+			expectMessage: "certificate bundles can only be generated for clusters with manageDNS",
+			expectRequeue: retryInterval,
+		},
+		{
+			name:          "dns zone not available",
+			cdOptions:     []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing:      []runtime.Object{testDNSZone(false)},
+			expectMessage: "DNSZone is not available",
+			expectRequeue: retryInterval,
+		},
+		{
+			name: "domain outside of the dns zone",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS, func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.Ingress[0].Domain = "apps.example.org"
+			}},
+			existing:      []runtime.Object{testDNSZone(true)},
+			expectMessage: "domain apps.example.org is not in DNS zone test-cluster.example.com",
+			expectRequeue: retryInterval,
+		},
+		{
+			name:      "order creation failure keeps the previous certificate",
+			cdOptions: []testcd.Option{withGeneratedBundle(true), withManageDNS},
+			existing: []runtime.Object{
+				testDNSZone(true),
+				testSecret(t, []string{testDomain}, time.Now().Add(10*24*time.Hour)),
+			},
+			issuerErr:        errors.New("rate limited"),
+			expectGenerated:  true,
+			expectMessage:    "failed to create certificate order: rate limited",
+			expectNotAfterIn: 10 * 24 * time.Hour,
+			expectRequeue:    retryInterval,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cdOptions := append([]testcd.Option{testcd.Installed()}, tc.cdOptions...)
+			cd := testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(cdOptions...)
+			c := testfake.NewFakeClientBuilder().WithRuntimeObjects(append(tc.existing, cd)...).Build()
+			iss := &fakeIssuer{
+				t:           t,
+				validity:    90 * 24 * time.Hour,
+				err:         tc.issuerErr,
+				finalizeErr: tc.finalizeErr,
+				underway:    tc.orderUnderway,
+			}
+			r := &ReconcileCertificate{
+				Client: c,
+				scheme: scheme.GetScheme(),
+				logger: log.WithField("controller", ControllerName),
+				config: &hivev1.CertificateIssuerConfig{RenewBefore: &metav1.Duration{Duration: renewBefore}},
+				issuer: iss,
+				newSolver: func(client.Client, *hivev1.DNSZone, log.FieldLogger) (dnsSolver, error) {
+					return fakeSolver{}, nil
+				},
+				now: time.Now,
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}})
+			require.NoError(t, err, "unexpected error from Reconcile")
+			assert.InDelta(t, tc.expectRequeue, result.RequeueAfter, float64(time.Second), "unexpected requeue")
+
+			if tc.expectOrdered {
+				assert.Equal(t, [][]string{{testDomain}}, iss.ordered, "unexpected orders")
+			} else {
+				assert.Empty(t, iss.ordered, "no order should be created")
+			}
+
+			if tc.expectIssued {
+				assert.Equal(t, [][]string{{testDomain}}, iss.issued, "unexpected issued certificates")
+				secret := &corev1.Secret{}
+				require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testSecretName}, secret))
+				cert, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+				require.NoError(t, err, "secret should hold the issued certificate")
+				assert.Equal(t, []string{testDomain}, cert.DNSNames, "unexpected certificate domains")
+				assert.NotEmpty(t, secret.Data[corev1.TLSPrivateKeyKey], "secret should hold the private key")
+				if assert.Len(t, secret.OwnerReferences, 1) {
+					assert.Equal(t, testName, secret.OwnerReferences[0].Name, "secret should be owned by the clusterdeployment")
+				}
+			} else {
+				assert.Empty(t, iss.issued, "no certificate should be issued")
+			}
+
+			updated := &hivev1.ClusterDeployment{}
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, updated))
+			if !tc.expectGenerated && tc.expectMessage == "" && !tc.expectPending {
+				assert.Empty(t, updated.Status.CertificateBundles, "no certificate bundle status expected")
+				return
+			}
+			require.Len(t, updated.Status.CertificateBundles, 1, "expected one certificate bundle status")
+			status := updated.Status.CertificateBundles[0]
+			assert.Equal(t, testBundleName, status.Name, "unexpected certificate bundle name")
+			assert.Equal(t, tc.expectGenerated, status.Generated, "unexpected generated")
+			assert.Equal(t, tc.expectMessage, status.Message, "unexpected message")
+			if tc.expectPending {
+				if assert.NotNil(t, status.PendingOrder, "expected a pending order") {
+					assert.Equal(t, testOrderURL, status.PendingOrder.URL, "unexpected pending order")
+					assert.False(t, status.PendingOrder.ChallengeRecordsPublishedTime.IsZero(), "expected the time challenge records were published")
+				}
+			} else {
+				assert.Nil(t, status.PendingOrder, "unexpected pending order")
+			}
+			if tc.expectNotAfterIn == 0 {
+				assert.Nil(t, status.NotAfter, "unexpected notAfter")
+				return
+			}
+			if assert.NotNil(t, status.NotAfter, "expected notAfter") && assert.NotNil(t, status.RenewalTime, "expected renewalTime") {
+				assert.WithinDuration(t, time.Now().Add(tc.expectNotAfterIn), status.NotAfter.Time, time.Minute, "unexpected notAfter")
+				assert.Equal(t, status.NotAfter.Add(-renewBefore), status.RenewalTime.Time, "unexpected renewalTime")
+			}
+		})
+	}
+}
+
+func TestCertificateBundleExpiryMetricOptIn(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			metricCertificateBundleExpiry.Reset()
+			defer metricCertificateBundleExpiry.Reset()
+			cd := testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(
+				testcd.Installed(), withGeneratedBundle(true), withManageDNS)
+			c := testfake.NewFakeClientBuilder().WithRuntimeObjects(
+				cd, testDNSZone(true), testSecret(t, []string{testDomain}, time.Now().Add(45*24*time.Hour))).Build()
+			r := &ReconcileCertificate{
+				Client:       c,
+				scheme:       scheme.GetScheme(),
+				logger:       log.WithField("controller", ControllerName),
+				config:       &hivev1.CertificateIssuerConfig{},
+				issuer:       &fakeIssuer{t: t},
+				now:          time.Now,
+				reportExpiry: enabled,
+			}
+
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}})
+			require.NoError(t, err, "unexpected error from Reconcile")
+
+			expected := 0
+			if enabled {
+				expected = 1
+			}
+			assert.Equal(t, expected, testutil.CollectAndCount(metricCertificateBundleExpiry), "unexpected number of series")
+		})
+	}
+}
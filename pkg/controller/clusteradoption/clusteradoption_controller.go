@@ -0,0 +1,206 @@
+package clusteradoption
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/clusteradoption"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = hivev1.ClusterAdoptionControllerName
+
+	// validationInterval is how often a cluster that is not adopted yet is validated again.
+	validationInterval = 10 * time.Minute
+)
+
+// Add creates a new ClusterAdoption controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileClusterAdoption{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		logger: logger,
+	}
+	r.remoteClusterAPIClientBuilder = func(secret *corev1.Secret) remoteclient.Builder {
+		return remoteclient.NewBuilderFromKubeconfig(r.Client, secret, ControllerName)
+	}
+
+	c, err := controller.New("clusteradoption-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, r.logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to the spec of ClusterAdoption. Status updates are ignored, since every validation updates the
+	// status.
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterAdoption{},
+		&handler.TypedEnqueueRequestForObject[*hivev1.ClusterAdoption]{},
+		predicate.TypedGenerationChangedPredicate[*hivev1.ClusterAdoption]{})); err != nil {
+		logger.WithError(err).Error("Error watching ClusterAdoption")
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterAdoption{}
+
+// ReconcileClusterAdoption validates running clusters for adoption, and adopts them.
+type ReconcileClusterAdoption struct {
+	client.Client
+
+	logger log.FieldLogger
+
+	// remoteClusterAPIClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the API server of the cluster to adopt, from its admin kubeconfig secret
+	remoteClusterAPIClientBuilder func(secret *corev1.Secret) remoteclient.Builder
+}
+
+// Reconcile introspects the cluster of a ClusterAdoption and reports whether it can be adopted. When the adoption is
+// requested and the cluster is valid, it labels the MachineSets of the cluster and creates the MachinePools and the
+// ClusterDeployment adopting it.
+func (r *ReconcileClusterAdoption) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := controllerutils.BuildControllerLogger(ControllerName, "clusterAdoption", request.NamespacedName)
+	logger.Info("reconciling cluster adoption")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, logger)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	adoption := &hivev1.ClusterAdoption{}
+	if err := r.Get(ctx, request.NamespacedName, adoption); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("cluster adoption not found")
+			return reconcile.Result{}, nil
+		}
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster adoption")
+		return reconcile.Result{}, err
+	}
+	if adoption.DeletionTimestamp != nil {
+		logger.Debug("cluster adoption is being deleted")
+		return reconcile.Result{}, nil
+	}
+	if adoption.Status.ClusterDeploymentRef != nil {
+		logger.Debug("cluster already adopted")
+		return reconcile.Result{}, nil
+	}
+
+	cluster, remoteClient, err := r.validate(ctx, adoption, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	adoption.Status.Report = cluster.Report
+	adoption.Status.LastValidationTime = &metav1.Time{Time: time.Now()}
+	if err := r.Status().Update(ctx, adoption); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster adoption status")
+		return reconcile.Result{}, err
+	}
+	if !adoption.Spec.Adopt || !cluster.Report.Valid {
+		logger.WithField("valid", cluster.Report.Valid).Debug("cluster not adopted")
+		return reconcile.Result{RequeueAfter: validationInterval}, nil
+	}
+
+	if err := r.adopt(ctx, adoption, cluster, remoteClient, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+	adoption.Status.ClusterDeploymentRef = &corev1.LocalObjectReference{Name: adoption.Name}
+	if err := r.Status().Update(ctx, adoption); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster adoption status")
+		return reconcile.Result{}, err
+	}
+	logger.Info("cluster adopted")
+	return reconcile.Result{}, nil
+}
+
+// validate introspects the cluster to adopt, and checks that the hub can adopt it. The remote client is nil when the
+// cluster could not be reached.
+func (r *ReconcileClusterAdoption) validate(ctx context.Context, adoption *hivev1.ClusterAdoption, logger log.FieldLogger) (*clusteradoption.Cluster, client.Client, error) {
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: adoption.Namespace, Name: adoption.Spec.AdminKubeconfigSecretRef.Name}, secret); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting admin kubeconfig secret")
+		return nil, nil, err
+	}
+	remoteClient, err := r.remoteClusterAPIClientBuilder(secret).Build()
+	if err != nil {
+		logger.WithError(err).Info("cannot connect to the cluster to adopt")
+		cluster := &clusteradoption.Cluster{Report: &hivev1.ClusterAdoptionReport{}}
+		cluster.AddCheck(clusteradoption.CheckAPIReachable, hivev1.ClusterAdoptionCheckFailed, "cannot connect to the cluster: %v", err)
+		return cluster, nil, nil
+	}
+
+	cluster := clusteradoption.Introspect(ctx, remoteClient, adoption.Spec.CredentialsSecretRef != nil)
+	if ref := adoption.Spec.CredentialsSecretRef; ref != nil {
+		if err := r.Get(ctx, types.NamespacedName{Namespace: adoption.Namespace, Name: ref.Name}, &corev1.Secret{}); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting credentials secret")
+				return nil, nil, err
+			}
+			cluster.AddCheck(clusteradoption.CheckCredentials, hivev1.ClusterAdoptionCheckFailed, "credentials secret %s not found", ref.Name)
+		}
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	switch err := r.Get(ctx, types.NamespacedName{Namespace: adoption.Namespace, Name: adoption.Name}, cd); {
+	case apierrors.IsNotFound(err):
+		cluster.AddCheck(clusteradoption.CheckClusterDeployment, hivev1.ClusterAdoptionCheckPassed, "")
+	case err != nil:
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster deployment")
+		return nil, nil, err
+	case cd.Spec.ClusterMetadata != nil && cd.Spec.ClusterMetadata.ClusterID == cluster.Report.ClusterID:
+		// Created by a previous reconcile which failed to record it.
+		cluster.AddCheck(clusteradoption.CheckClusterDeployment, hivev1.ClusterAdoptionCheckPassed, "")
+	default:
+		cluster.AddCheck(clusteradoption.CheckClusterDeployment, hivev1.ClusterAdoptionCheckFailed, "a different clusterdeployment named %s already exists", adoption.Name)
+	}
+	return cluster, remoteClient, nil
+}
+
+// adopt labels the MachineSets of the cluster, and creates the MachinePools and the ClusterDeployment adopting it.
+// The MachineSets are labeled first, so that the MachinePool controller manages them rather than creating new ones.
+func (r *ReconcileClusterAdoption) adopt(ctx context.Context, adoption *hivev1.ClusterAdoption, cluster *clusteradoption.Cluster, remoteClient client.Client, logger log.FieldLogger) error {
+	if err := cluster.LabelMachineSets(ctx, remoteClient); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error labeling machinesets")
+		return err
+	}
+
+	cd := cluster.ClusterDeployment(adoption.Namespace, adoption.Name, &adoption.Spec)
+	cd.Labels = adoption.Labels
+	for _, pool := range cluster.MachinePoolObjects(cd) {
+		if err := r.Create(ctx, pool); err != nil && !apierrors.IsAlreadyExists(err) {
+			logger.WithError(err).WithField("machinePool", pool.Name).Log(controllerutils.LogLevel(err), "error creating machine pool")
+			return err
+		}
+	}
+	if err := r.Create(ctx, cd); err != nil && !apierrors.IsAlreadyExists(err) {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error creating cluster deployment")
+		return err
+	}
+	return nil
+}
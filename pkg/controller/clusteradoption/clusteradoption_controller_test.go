@@ -0,0 +1,307 @@
+package clusteradoption
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/clusteradoption"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace       = "test-namespace"
+	testName            = "test-cluster"
+	testInfraID         = "test-cluster-abc12"
+	testClusterID       = "0a1b2c3d-4e5f-6a7b-8c9d-0e1f2a3b4c5d"
+	kubeconfigSecret    = "test-kubeconfig"
+	credentialsSecret   = "test-creds"
+	machineAPINamespace = "openshift-machine-api"
+)
+
+type adoptionOption func(*hivev1.ClusterAdoption)
+
+func testAdoption(opts ...adoptionOption) *hivev1.ClusterAdoption {
+	adoption := &hivev1.ClusterAdoption{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testName,
+			Labels:    map[string]string{"fleet": "test"},
+		},
+		Spec: hivev1.ClusterAdoptionSpec{
+			AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: kubeconfigSecret},
+			CredentialsSecretRef:     &corev1.LocalObjectReference{Name: credentialsSecret},
+			PreserveOnDelete:         true,
+		},
+	}
+	for _, o := range opts {
+		o(adoption)
+	}
+	return adoption
+}
+
+func withAdopt(adoption *hivev1.ClusterAdoption) {
+	adoption.Spec.Adopt = true
+}
+
+func withoutCredentials(adoption *hivev1.ClusterAdoption) {
+	adoption.Spec.CredentialsSecretRef = nil
+}
+
+func withAdopted(adoption *hivev1.ClusterAdoption) {
+	adoption.Status.ClusterDeploymentRef = &corev1.LocalObjectReference{Name: testName}
+}
+
+func testMachineSet(name, zone string, replicas int32) *machinev1beta1.MachineSet {
+	providerSpec, _ := json.Marshal(&machinev1beta1.AWSMachineProviderConfig{
+		InstanceType: "m6i.xlarge",
+		Placement:    machinev1beta1.Placement{AvailabilityZone: zone, Region: "us-east-1"},
+		BlockDevices: []machinev1beta1.BlockDeviceMappingSpec{{
+			EBS: &machinev1beta1.EBSBlockDeviceSpec{
+				VolumeSize: ptr.To[int64](120),
+				VolumeType: ptr.To("gp3"),
+			},
+		}},
+	})
+	return &machinev1beta1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: machineAPINamespace, Name: name},
+		Spec: machinev1beta1.MachineSetSpec{
+			Replicas: ptr.To(replicas),
+			Template: machinev1beta1.MachineTemplateSpec{
+				Spec: machinev1beta1.MachineSpec{
+					ProviderSpec: machinev1beta1.ProviderSpec{Value: &runtime.RawExtension{Raw: providerSpec}},
+				},
+			},
+		},
+	}
+}
+
+func remoteObjects() []runtime.Object {
+	return []runtime.Object{
+		&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status: configv1.InfrastructureStatus{
+				InfrastructureName: testInfraID,
+				PlatformStatus: &configv1.PlatformStatus{
+					Type: configv1.AWSPlatformType,
+					AWS:  &configv1.AWSPlatformStatus{Region: "us-east-1"},
+				},
+			},
+		},
+		&configv1.DNS{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.DNSSpec{BaseDomain: "test-cluster.example.com"},
+		},
+		&configv1.ClusterVersion{
+			ObjectMeta: metav1.ObjectMeta{Name: "version"},
+			Spec:       configv1.ClusterVersionSpec{ClusterID: testClusterID},
+			Status: configv1.ClusterVersionStatus{
+				Desired: configv1.Release{Version: "4.15.2"},
+			},
+		},
+		testMachineSet(testInfraID+"-worker-us-east-1a", "us-east-1a", 2),
+		testMachineSet(testInfraID+"-worker-us-east-1b", "us-east-1b", 1),
+		testMachineSet("custom", "us-east-1a", 1),
+	}
+}
+
+func checkResult(report *hivev1.ClusterAdoptionReport, name string) hivev1.ClusterAdoptionCheckResult {
+	var result hivev1.ClusterAdoptionCheckResult
+	for _, check := range report.Checks {
+		if check.Name == name {
+			result = check.Result
+		}
+	}
+	return result
+}
+
+func TestReconcileClusterAdoption(t *testing.T) {
+	cases := []struct {
+		name           string
+		adoption       *hivev1.ClusterAdoption
+		existing       []runtime.Object
+		buildErr       error
+		expectValid    bool
+		expectChecks   map[string]hivev1.ClusterAdoptionCheckResult
+		expectAdopted  bool
+		expectNoReport bool
+	}{
+		{
+			name:        "validate only",
+			adoption:    testAdoption(),
+			expectValid: true,
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				clusteradoption.CheckAPIReachable:      hivev1.ClusterAdoptionCheckPassed,
+				clusteradoption.CheckClusterIdentity:   hivev1.ClusterAdoptionCheckPassed,
+				clusteradoption.CheckPlatform:          hivev1.ClusterAdoptionCheckPassed,
+				clusteradoption.CheckCredentials:       hivev1.ClusterAdoptionCheckPassed,
+				clusteradoption.CheckMachineSets:       hivev1.ClusterAdoptionCheckWarning,
+				clusteradoption.CheckClusterDeployment: hivev1.ClusterAdoptionCheckPassed,
+			},
+		},
+		{
+			name:          "adopt",
+			adoption:      testAdoption(withAdopt),
+			expectValid:   true,
+			expectAdopted: true,
+		},
+		{
+			name:     "no credentials",
+			adoption: testAdoption(withAdopt, withoutCredentials),
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				clusteradoption.CheckCredentials: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name: "credentials secret missing",
+			adoption: testAdoption(withAdopt, func(adoption *hivev1.ClusterAdoption) {
+				adoption.Spec.CredentialsSecretRef.Name = "missing-creds"
+			}),
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				clusteradoption.CheckCredentials: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name:     "different clusterdeployment exists",
+			adoption: testAdoption(withAdopt),
+			existing: []runtime.Object{
+				testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(testcd.Installed()),
+			},
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				clusteradoption.CheckClusterDeployment: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name:     "unreachable",
+			adoption: testAdoption(withAdopt),
+			buildErr: errors.New("connection refused"),
+			expectChecks: map[string]hivev1.ClusterAdoptionCheckResult{
+				clusteradoption.CheckAPIReachable: hivev1.ClusterAdoptionCheckFailed,
+			},
+		},
+		{
+			name:           "already adopted",
+			adoption:       testAdoption(withAdopt, withAdopted),
+			expectAdopted:  true,
+			expectNoReport: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := append(tc.existing,
+				testsecret.FullBuilder(testNamespace, kubeconfigSecret, scheme.GetScheme()).Build(
+					testsecret.WithDataKeyValue(constants.KubeconfigSecretKey, []byte("kubeconfig")),
+				),
+				testsecret.FullBuilder(testNamespace, credentialsSecret, scheme.GetScheme()).Build(),
+				tc.adoption,
+			)
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+			remoteClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(remoteObjects()...).Build()
+			mockCtrl := gomock.NewController(t)
+			r := &ReconcileClusterAdoption{
+				Client: fakeClient,
+				logger: log.WithField("controller", "clusteradoption"),
+				remoteClusterAPIClientBuilder: func(*corev1.Secret) remoteclient.Builder {
+					builder := remoteclientmock.NewMockBuilder(mockCtrl)
+					if tc.buildErr != nil {
+						builder.EXPECT().Build().Return(nil, tc.buildErr)
+					} else {
+						builder.EXPECT().Build().Return(remoteClient, nil)
+					}
+					return builder
+				},
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName},
+			})
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			adoption := &hivev1.ClusterAdoption{}
+			require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(tc.adoption), adoption))
+			if tc.expectNoReport {
+				assert.Nil(t, adoption.Status.Report, "unexpected report")
+			} else {
+				require.NotNil(t, adoption.Status.Report, "expected report")
+				assert.NotNil(t, adoption.Status.LastValidationTime, "expected last validation time")
+				assert.Equal(t, tc.expectValid, adoption.Status.Report.Valid, "unexpected validity")
+				for name, expected := range tc.expectChecks {
+					assert.Equal(t, expected, checkResult(adoption.Status.Report, name), "unexpected result of check %s", name)
+				}
+			}
+
+			if !tc.expectAdopted {
+				assert.Nil(t, adoption.Status.ClusterDeploymentRef, "unexpected clusterdeployment reference")
+				assert.Equal(t, validationInterval, result.RequeueAfter, "unexpected requeue")
+				return
+			}
+			require.NotNil(t, adoption.Status.ClusterDeploymentRef, "expected clusterdeployment reference")
+			assert.Zero(t, result.RequeueAfter, "unexpected requeue")
+			if tc.expectNoReport {
+				return
+			}
+
+			report := adoption.Status.Report
+			assert.Equal(t, testName, report.ClusterName, "unexpected cluster name")
+			assert.Equal(t, "example.com", report.BaseDomain, "unexpected base domain")
+			assert.Equal(t, "4.15.2", report.Version, "unexpected version")
+			assert.Equal(t, []string{"worker"}, report.MachinePools, "unexpected machine pools")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cd))
+			assert.True(t, cd.Spec.Installed, "expected installed clusterdeployment")
+			assert.True(t, cd.Spec.PreserveOnDelete, "expected preserveOnDelete")
+			assert.Equal(t, "test", cd.Labels["fleet"], "expected labels of the adoption")
+			require.NotNil(t, cd.Spec.ClusterMetadata, "expected cluster metadata")
+			assert.Equal(t, testClusterID, cd.Spec.ClusterMetadata.ClusterID, "unexpected cluster ID")
+			assert.Equal(t, testInfraID, cd.Spec.ClusterMetadata.InfraID, "unexpected infra ID")
+			assert.Equal(t, kubeconfigSecret, cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name, "unexpected kubeconfig secret")
+			require.NotNil(t, cd.Spec.Platform.AWS, "expected AWS platform")
+			assert.Equal(t, "us-east-1", cd.Spec.Platform.AWS.Region, "unexpected region")
+			assert.Equal(t, credentialsSecret, cd.Spec.Platform.AWS.CredentialsSecretRef.Name, "unexpected credentials secret")
+
+			pool := &hivev1.MachinePool{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName + "-worker"}, pool))
+			assert.Equal(t, testName, pool.Spec.ClusterDeploymentRef.Name, "unexpected clusterdeployment of pool")
+			assert.Equal(t, int64(3), ptr.Deref(pool.Spec.Replicas, 0), "unexpected replicas")
+			require.NotNil(t, pool.Spec.Platform.AWS, "expected AWS pool platform")
+			assert.Equal(t, "m6i.xlarge", pool.Spec.Platform.AWS.InstanceType, "unexpected instance type")
+			assert.Equal(t, []string{"us-east-1a", "us-east-1b"}, pool.Spec.Platform.AWS.Zones, "unexpected zones")
+			assert.Equal(t, 120, pool.Spec.Platform.AWS.EC2RootVolume.Size, "unexpected root volume size")
+
+			machineSets := &machinev1beta1.MachineSetList{}
+			require.NoError(t, remoteClient.List(context.TODO(), machineSets))
+			for _, ms := range machineSets.Items {
+				if ms.Name == "custom" {
+					assert.NotContains(t, ms.Labels, constants.MachineSetMachinePoolLabel, "unexpected label on unmapped machineset")
+					continue
+				}
+				assert.Equal(t, "worker", ms.Labels[constants.MachineSetMachinePoolLabel], "unexpected machine pool label on %s", ms.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package clustercost
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	installergcp "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	installertypes "github.com/openshift/installer/pkg/types"
+	installerawsdefaults "github.com/openshift/installer/pkg/types/aws/defaults"
+	installerazuredefaults "github.com/openshift/installer/pkg/types/azure/defaults"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	ControllerName = hivev1.ClusterCostControllerName
+
+	// costUpdateInterval is how often a cluster's cost estimate is brought up to date when nothing affecting it
+	// changes.
+	costUpdateInterval = time.Hour
+
+	defaultControlPlaneReplicas = 3
+)
+
+// priceTable is the content of the price table ConfigMap.
+type priceTable struct {
+	Currency string `json:"currency,omitempty"`
+	// Prices are the hourly prices of instance types, by platform then instance type.
+	Prices map[string]map[string]float64 `json:"prices"`
+}
+
+// Add creates a new ClusterCost controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+
+	// Don't run the controller unless a price table has been configured.
+	priceTableName := os.Getenv(constants.CostEstimationPriceTableConfigMapEnvVar)
+	if priceTableName == "" {
+		return nil
+	}
+
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+	return AddToManager(mgr, NewReconciler(mgr, clientRateLimiter, priceTableName), concurrentReconciles, queueRateLimiter)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager, rateLimiter flowcontrol.RateLimiter, priceTableName string) reconcile.Reconciler {
+	return &ReconcileClusterCost{
+		Client:         controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &rateLimiter),
+		logger:         log.WithField("controller", ControllerName),
+		priceTableName: priceTableName,
+		now:            time.Now,
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler, concurrentReconciles int, rateLimiter workqueue.RateLimiter) error {
+	c, err := controller.New("clustercost-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, log.WithField("controller", ControllerName)),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             rateLimiter,
+	})
+	if err != nil {
+		log.WithField("controller", ControllerName).WithError(err).Error("Error creating new clustercost controller")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{}, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{}))
+	if err != nil {
+		log.WithField("controller", ControllerName).WithError(err).Error("Error watching cluster deployment")
+		return err
+	}
+
+	// Watch for changes to MachinePools, which change the cluster's hourly cost
+	err = c.Watch(source.Kind(mgr.GetCache(), &hivev1.MachinePool{}, handler.TypedEnqueueRequestsFromMapFunc(
+		func(ctx context.Context, pool *hivev1.MachinePool) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{
+				Namespace: pool.Namespace,
+				Name:      pool.Spec.ClusterDeploymentRef.Name,
+			}}}
+		})))
+	if err != nil {
+		log.WithField("controller", ControllerName).WithError(err).Error("Error watching machine pools")
+		return err
+	}
+	return nil
+}
+
+// ReconcileClusterCost is the reconciler for cluster cost estimates. It syncs on ClusterDeployment resources and
+// keeps their cost estimates up to date.
+type ReconcileClusterCost struct {
+	client.Client
+	logger log.FieldLogger
+
+	// priceTableName is the name of the price table ConfigMap in the hive namespace.
+	priceTableName string
+
+	// now returns the current time, exposed for testing
+	now func() time.Time
+}
+
+// Reconcile brings the cost estimate of a ClusterDeployment up to date.
+func (r *ReconcileClusterCost) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := controllerutils.BuildControllerLogger(ControllerName, "clusterDeployment", request.NamespacedName)
+	logger.Info("reconciling cluster deployment")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, logger)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(ctx, request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("cluster deployment not found")
+			return reconcile.Result{}, nil
+		}
+		logger.WithError(err).Error("Error getting cluster deployment")
+		return reconcile.Result{}, err
+	}
+	logger = controllerutils.AddLogFields(controllerutils.MetaObjectLogTagger{Object: cd}, logger)
+	if paused, err := strconv.ParseBool(cd.Annotations[constants.ReconcilePauseAnnotation]); err == nil && paused {
+		logger.Info("skipping reconcile due to ClusterDeployment pause annotation")
+		return reconcile.Result{}, nil
+	}
+	if !cd.DeletionTimestamp.IsZero() {
+		logger.Debug("cluster deployment is being deleted")
+		return reconcile.Result{}, nil
+	}
+	if !cd.Spec.Installed {
+		logger.Debug("cluster is not installed yet")
+		return reconcile.Result{}, nil
+	}
+
+	prices, err := r.loadPriceTable(ctx)
+	if err != nil {
+		logger.WithError(err).Error("could not load price table")
+		return reconcile.Result{}, err
+	}
+	hourlyCost, unpriced, err := r.hourlyCost(ctx, cd, prices, logger)
+	if err != nil {
+		logger.WithError(err).Error("could not calculate hourly cost")
+		return reconcile.Result{}, err
+	}
+
+	// Status times are stored to the second, so work to the second so that no time is counted twice.
+	now := r.now().Truncate(time.Second)
+	hibernatingCond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition)
+	est := cd.Status.CostEstimate.DeepCopy()
+	if est == nil {
+		est = &hivev1.ClusterCostEstimate{
+			CostSummary:     hivev1.CostSummary{TotalCost: controllerutils.FormatCost(0)},
+			LastUpdatedTime: metav1.NewTime(now),
+		}
+	}
+	sinceUpdate := now.Sub(est.LastUpdatedTime.Time)
+	// The time since the last update is accrued at the hourly cost in effect then, so the estimate must be brought
+	// up to date whenever the hourly cost changes. Hibernation transitions are worked out from the Hibernating
+	// condition, but only the latest one, so the estimate must also be brought up to date after each transition.
+	transitioned := hibernatingCond != nil && hibernatingCond.LastTransitionTime.After(est.LastUpdatedTime.Time)
+	accrue(est, hibernatingCond, now)
+	est.Currency = prices.Currency
+	est.HourlyCost = controllerutils.FormatCost(hourlyCost)
+	est.UnpricedInstanceTypes = unpriced
+
+	orig := cd.Status.CostEstimate
+	if orig != nil && sinceUpdate < costUpdateInterval && !transitioned &&
+		orig.Currency == est.Currency &&
+		orig.HourlyCost == est.HourlyCost &&
+		reflect.DeepEqual(orig.UnpricedInstanceTypes, est.UnpricedInstanceTypes) {
+		logger.Debug("cost estimate is recent enough")
+		return reconcile.Result{RequeueAfter: costUpdateInterval - sinceUpdate}, nil
+	}
+
+	logger.WithField("hourlyCost", est.HourlyCost).WithField("totalCost", est.TotalCost).Info("updating cost estimate")
+	cd.Status.CostEstimate = est
+	if err := r.Status().Update(ctx, cd); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cost estimate")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: costUpdateInterval}, nil
+}
+
+// accrue brings the estimate's totals up to now, at the estimate's hourly cost. The time since the last update is
+// split between running and hibernating at the Hibernating condition's last transition. Clusters are counted as
+// hibernating from when they start stopping until they start resuming.
+func accrue(est *hivev1.ClusterCostEstimate, hibernatingCond *hivev1.ClusterDeploymentCondition, now time.Time) {
+	last := est.LastUpdatedTime.Time
+	if !now.After(last) {
+		return
+	}
+	hibernating := hibernatingCond != nil && hibernatingCond.Status == corev1.ConditionTrue
+	transition := last
+	if hibernatingCond != nil && hibernatingCond.LastTransitionTime.After(last) && hibernatingCond.LastTransitionTime.Time.Before(now) {
+		transition = hibernatingCond.LastTransitionTime.Time
+	}
+	// Before the transition the cluster was in the opposite state to the one it is in now.
+	running, hibernated := transition.Sub(last), now.Sub(transition)
+	if !hibernating {
+		running, hibernated = hibernated, running
+	}
+	est.RunningSeconds += int64(running.Seconds())
+	est.HibernatingSeconds += int64(hibernated.Seconds())
+	est.TotalCost = controllerutils.FormatCost(
+		controllerutils.ParseCost(est.TotalCost) + controllerutils.ParseCost(est.HourlyCost)*running.Hours())
+	est.LastUpdatedTime = metav1.NewTime(now)
+}
+
+func (r *ReconcileClusterCost) loadPriceTable(ctx context.Context) (*priceTable, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: controllerutils.GetHiveNamespace(), Name: r.priceTableName}, cm); err != nil {
+		return nil, errors.Wrap(err, "could not get price table configmap")
+	}
+	prices := &priceTable{}
+	if err := yaml.Unmarshal([]byte(cm.Data[constants.CostEstimationPriceTableKey]), prices); err != nil {
+		return nil, errors.Wrap(err, "could not parse price table")
+	}
+	return prices, nil
+}
+
+// hourlyCost returns the cost per hour of running the cluster's control plane and MachinePools, along with the
+// instance types left out because they are not in the price table.
+func (r *ReconcileClusterCost) hourlyCost(ctx context.Context, cd *hivev1.ClusterDeployment, prices *priceTable, logger log.FieldLogger) (float64, []string, error) {
+	machines := map[string]int64{}
+	if instanceType, count := r.controlPlaneMachines(ctx, cd, logger); instanceType != "" {
+		machines[instanceType] += count
+	}
+
+	pools := &hivev1.MachinePoolList{}
+	if err := r.List(ctx, pools, client.InNamespace(cd.Namespace)); err != nil {
+		return 0, nil, errors.Wrap(err, "could not list machine pools")
+	}
+	for i := range pools.Items {
+		pool := &pools.Items[i]
+		if pool.Spec.ClusterDeploymentRef.Name != cd.Name || !pool.DeletionTimestamp.IsZero() {
+			continue
+		}
+		instanceType := machinePoolInstanceType(pool)
+		if instanceType == "" {
+			logger.WithField("machinePool", pool.Name).Debug("machine pool has no instance type")
+			continue
+		}
+		machines[instanceType] += machinePoolReplicas(pool)
+	}
+
+	platformPrices := prices.Prices[cd.Labels[hivev1.HiveClusterPlatformLabel]]
+	var cost float64
+	var unpriced []string
+	for instanceType, count := range machines {
+		price, ok := platformPrices[instanceType]
+		if !ok {
+			unpriced = append(unpriced, instanceType)
+			continue
+		}
+		cost += price * float64(count)
+	}
+	sort.Strings(unpriced)
+	return cost, unpriced, nil
+}
+
+// controlPlaneMachines returns the instance type and number of the cluster's control plane machines, as given by
+// its install-config. An empty instance type is returned if they cannot be determined, e.g. for adopted clusters.
+func (r *ReconcileClusterCost) controlPlaneMachines(ctx context.Context, cd *hivev1.ClusterDeployment, logger log.FieldLogger) (string, int64) {
+	if cd.Spec.Provisioning == nil || cd.Spec.Provisioning.InstallConfigSecretRef == nil {
+		logger.Debug("no install-config; leaving the control plane out of the cost estimate")
+		return "", 0
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.Provisioning.InstallConfigSecretRef.Name}, secret); err != nil {
+		logger.WithError(err).Warn("could not get install-config; leaving the control plane out of the cost estimate")
+		return "", 0
+	}
+	ic := &installertypes.InstallConfig{}
+	if err := yaml.Unmarshal(secret.Data["install-config.yaml"], ic); err != nil {
+		logger.WithError(err).Warn("could not parse install-config; leaving the control plane out of the cost estimate")
+		return "", 0
+	}
+	replicas := int64(defaultControlPlaneReplicas)
+	if ic.ControlPlane != nil && ic.ControlPlane.Replicas != nil {
+		replicas = *ic.ControlPlane.Replicas
+	}
+	return controlPlaneInstanceType(ic), replicas
+}
+
+// controlPlaneInstanceType returns the install-config's control plane instance type, applying the installer's
+// defaults.
+func controlPlaneInstanceType(ic *installertypes.InstallConfig) string {
+	cp := ic.ControlPlane
+	if cp == nil {
+		cp = &installertypes.MachinePool{}
+	}
+	arch := cp.Architecture
+	if arch == "" {
+		arch = installertypes.ArchitectureAMD64
+	}
+	switch {
+	case ic.AWS != nil:
+		if cp.Platform.AWS != nil && cp.Platform.AWS.InstanceType != "" {
+			return cp.Platform.AWS.InstanceType
+		}
+		if p := ic.AWS.DefaultMachinePlatform; p != nil && p.InstanceType != "" {
+			return p.InstanceType
+		}
+		topology := configv1.HighlyAvailableTopologyMode
+		if cp.Replicas != nil && *cp.Replicas == 1 {
+			topology = configv1.SingleReplicaTopologyMode
+		}
+		if types := installerawsdefaults.InstanceTypes(ic.AWS.Region, arch, topology); len(types) > 0 {
+			return types[0]
+		}
+	case ic.GCP != nil:
+		if cp.Platform.GCP != nil && cp.Platform.GCP.InstanceType != "" {
+			return cp.Platform.GCP.InstanceType
+		}
+		if p := ic.GCP.DefaultMachinePlatform; p != nil && p.InstanceType != "" {
+			return p.InstanceType
+		}
+		return installergcp.DefaultInstanceTypeForArch(arch)
+	case ic.Azure != nil:
+		if cp.Platform.Azure != nil && cp.Platform.Azure.InstanceType != "" {
+			return cp.Platform.Azure.InstanceType
+		}
+		if p := ic.Azure.DefaultMachinePlatform; p != nil && p.InstanceType != "" {
+			return p.InstanceType
+		}
+		return installerazuredefaults.ControlPlaneInstanceType(ic.Azure.CloudName, ic.Azure.Region, arch)
+	}
+	return ""
+}
+
+func machinePoolInstanceType(pool *hivev1.MachinePool) string {
+	switch p := pool.Spec.Platform; {
+	case p.AWS != nil:
+		return p.AWS.InstanceType
+	case p.GCP != nil:
+		return p.GCP.InstanceType
+	case p.Azure != nil:
+		return p.Azure.InstanceType
+	case p.IBMCloud != nil:
+		return p.IBMCloud.InstanceType
+	}
+	return ""
+}
+
+// machinePoolReplicas returns the number of machines in the pool: the configured replicas, or for autoscaling pools
+// the current replicas, falling back to the minimum.
+func machinePoolReplicas(pool *hivev1.MachinePool) int64 {
+	if pool.Spec.Autoscaling != nil {
+		if pool.Status.Replicas > 0 {
+			return int64(pool.Status.Replicas)
+		}
+		return int64(pool.Spec.Autoscaling.MinReplicas)
+	}
+	if pool.Spec.Replicas != nil {
+		return *pool.Spec.Replicas
+	}
+	return 0
+}
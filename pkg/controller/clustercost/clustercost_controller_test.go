@@ -0,0 +1,248 @@
+package clustercost
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	testmp "github.com/openshift/hive/pkg/test/machinepool"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testName                  = "cluster1"
+	testNamespace             = "cluster1namespace"
+	testPriceTableName        = "prices"
+	testInstallConfigSecret   = "install-config"
+	testUnpricedInstanceType  = "x1.32xlarge"
+	testPricedWorkerInstance  = "m5.large"
+	testPriceTableContent     = "currency: USD\nprices:\n  aws:\n    m5.xlarge: 0.2\n    m5.large: 0.1\n"
+	testInstallConfigContents = `apiVersion: v1
+metadata:
+  name: cluster1
+controlPlane:
+  name: master
+  replicas: 3
+  platform:
+    aws:
+      type: m5.xlarge
+platform:
+  aws:
+    region: us-east-1
+`
+)
+
+var testNow = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func testClusterDeployment(opts ...testcd.Option) *hivev1.ClusterDeployment {
+	cd := testcd.Build(append([]testcd.Option{
+		testcd.WithName(testName),
+		testcd.WithNamespace(testNamespace),
+		testcd.WithLabel(hivev1.HiveClusterPlatformLabel, "aws"),
+		testcd.Installed(),
+	}, opts...)...)
+	cd.Spec.Provisioning = &hivev1.Provisioning{
+		InstallConfigSecretRef: &corev1.LocalObjectReference{Name: testInstallConfigSecret},
+	}
+	return cd
+}
+
+func withCostEstimate(est *hivev1.ClusterCostEstimate) testcd.Option {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.CostEstimate = est
+	}
+}
+
+func withHibernating(status corev1.ConditionStatus, transition time.Time) testcd.Option {
+	return testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+		Type:               hivev1.ClusterHibernatingCondition,
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(transition),
+	})
+}
+
+func testWorkerPool(instanceType string, replicas int64) *hivev1.MachinePool {
+	return testmp.FullBuilder(testNamespace, "worker-"+instanceType, testName, scheme.GetScheme()).Build(
+		testmp.WithReplicas(replicas),
+		testmp.WithAWSInstanceType(instanceType),
+	)
+}
+
+func testExisting() []runtime.Object {
+	return []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: constants.DefaultHiveNamespace, Name: testPriceTableName},
+			Data:       map[string]string{constants.CostEstimationPriceTableKey: testPriceTableContent},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testInstallConfigSecret},
+			Data:       map[string][]byte{"install-config.yaml": []byte(testInstallConfigContents)},
+		},
+		testWorkerPool(testPricedWorkerInstance, 2),
+	}
+}
+
+func TestClusterCostReconcile(t *testing.T) {
+	log.SetLevel(log.DebugLevel)
+
+	tests := []struct {
+		name           string
+		cd             *hivev1.ClusterDeployment
+		existing       []runtime.Object
+		expectNoEst    bool
+		expectHourly   string
+		expectTotal    string
+		expectRunning  int64
+		expectHiber    int64
+		expectUnpriced []string
+		expectUpdated  bool
+		expectRequeue  time.Duration
+	}{
+		{
+			name:        "not installed",
+			cd:          testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+			expectNoEst: true,
+		},
+		{
+			name:           "first estimate",
+			cd:             testClusterDeployment(),
+			existing:       []runtime.Object{testWorkerPool(testUnpricedInstanceType, 1)},
+			expectHourly:   "0.8000",
+			expectTotal:    "0.0000",
+			expectUnpriced: []string{testUnpricedInstanceType},
+			expectUpdated:  true,
+			expectRequeue:  costUpdateInterval,
+		},
+		{
+			name: "accrues running time",
+			cd: testClusterDeployment(withCostEstimate(&hivev1.ClusterCostEstimate{
+				CostSummary:     hivev1.CostSummary{Currency: "USD", HourlyCost: "0.8000", TotalCost: "1.0000"},
+				RunningSeconds:  3600,
+				LastUpdatedTime: metav1.NewTime(testNow.Add(-2 * time.Hour)),
+			})),
+			expectHourly:  "0.8000",
+			expectTotal:   "2.6000",
+			expectRunning: 3600 + 2*3600,
+			expectUpdated: true,
+			expectRequeue: costUpdateInterval,
+		},
+		{
+			name: "splits time at hibernation",
+			cd: testClusterDeployment(
+				withHibernating(corev1.ConditionTrue, testNow.Add(-30*time.Minute)),
+				withCostEstimate(&hivev1.ClusterCostEstimate{
+					CostSummary:     hivev1.CostSummary{Currency: "USD", HourlyCost: "0.8000", TotalCost: "0.0000"},
+					LastUpdatedTime: metav1.NewTime(testNow.Add(-90 * time.Minute)),
+				}),
+			),
+			expectHourly:  "0.8000",
+			expectTotal:   "0.8000",
+			expectRunning: 3600,
+			expectHiber:   1800,
+			expectUpdated: true,
+			expectRequeue: costUpdateInterval,
+		},
+		{
+			name: "accrues hibernating time",
+			cd: testClusterDeployment(
+				withHibernating(corev1.ConditionTrue, testNow.Add(-5*time.Hour)),
+				withCostEstimate(&hivev1.ClusterCostEstimate{
+					CostSummary:     hivev1.CostSummary{Currency: "USD", HourlyCost: "0.8000", TotalCost: "0.5000"},
+					LastUpdatedTime: metav1.NewTime(testNow.Add(-2 * time.Hour)),
+				}),
+			),
+			expectHourly:  "0.8000",
+			expectTotal:   "0.5000",
+			expectHiber:   2 * 3600,
+			expectUpdated: true,
+			expectRequeue: costUpdateInterval,
+		},
+		{
+			name: "recent estimate not updated",
+			cd: testClusterDeployment(withCostEstimate(&hivev1.ClusterCostEstimate{
+				CostSummary:     hivev1.CostSummary{Currency: "USD", HourlyCost: "0.8000", TotalCost: "1.0000"},
+				LastUpdatedTime: metav1.NewTime(testNow.Add(-10 * time.Minute)),
+			})),
+			expectHourly:  "0.8000",
+			expectTotal:   "1.0000",
+			expectRequeue: 50 * time.Minute,
+		},
+		{
+			name: "recent estimate updated when hourly cost changes",
+			cd: testClusterDeployment(withCostEstimate(&hivev1.ClusterCostEstimate{
+				CostSummary:     hivev1.CostSummary{Currency: "USD", HourlyCost: "0.6000", TotalCost: "1.0000"},
+				LastUpdatedTime: metav1.NewTime(testNow.Add(-30 * time.Minute)),
+			})),
+			expectHourly:  "0.8000",
+			expectTotal:   "1.3000",
+			expectRunning: 1800,
+			expectUpdated: true,
+			expectRequeue: costUpdateInterval,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append(testExisting(), test.existing...)
+			existing = append(existing, test.cd)
+			c := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+			rcc := &ReconcileClusterCost{
+				Client:         c,
+				logger:         log.WithField("controller", ControllerName),
+				priceTableName: testPriceTableName,
+				now:            func() time.Time { return testNow.Add(300 * time.Millisecond) },
+			}
+
+			result, err := rcc.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName},
+			})
+			require.NoError(t, err, "unexpected error from Reconcile")
+			assert.Equal(t, test.expectRequeue, result.RequeueAfter, "unexpected requeue")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cd))
+			est := cd.Status.CostEstimate
+			if test.expectNoEst {
+				assert.Nil(t, est, "expected no cost estimate")
+				return
+			}
+			require.NotNil(t, est, "expected a cost estimate")
+			assert.Equal(t, "USD", est.Currency, "unexpected currency")
+			assert.Equal(t, test.expectHourly, est.HourlyCost, "unexpected hourly cost")
+			assert.Equal(t, test.expectTotal, est.TotalCost, "unexpected total cost")
+			assert.Equal(t, test.expectUnpriced, est.UnpricedInstanceTypes, "unexpected unpriced instance types")
+			if test.expectUpdated {
+				assert.Equal(t, test.expectRunning, est.RunningSeconds, "unexpected running seconds")
+				assert.Equal(t, test.expectHiber, est.HibernatingSeconds, "unexpected hibernating seconds")
+				assert.True(t, est.LastUpdatedTime.Time.Equal(testNow), "unexpected last updated time %v", est.LastUpdatedTime)
+			} else {
+				assert.True(t, est.LastUpdatedTime.Time.Before(testNow), "estimate should not have been updated")
+			}
+		})
+	}
+}
+
+func TestMachinePoolReplicas(t *testing.T) {
+	pool := testWorkerPool(testPricedWorkerInstance, 2)
+	assert.Equal(t, int64(2), machinePoolReplicas(pool), "unexpected replicas for fixed pool")
+
+	pool.Spec.Replicas = nil
+	pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 5}
+	assert.Equal(t, int64(1), machinePoolReplicas(pool), "autoscaling pool without status should use minimum")
+
+	pool.Status.Replicas = 4
+	assert.Equal(t, int64(4), machinePoolReplicas(pool), "autoscaling pool should use current replicas")
+}
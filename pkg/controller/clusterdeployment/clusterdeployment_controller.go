@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/pointer"
@@ -71,6 +72,8 @@ var (
 		hivev1.ProvisionStoppedCondition,
 		hivev1.AuthenticationFailureClusterDeploymentCondition,
 		hivev1.RequirementsMetCondition,
+		hivev1.PreflightChecksFailedCondition,
+		hivev1.InstallProgressingCondition,
 		hivev1.ProvisionedCondition,
 
 		// ClusterInstall conditions copied over to cluster deployment
@@ -108,6 +111,10 @@ const (
 	failureReasonNotListed            = "FailureReasonNotRetryable"
 	provisionNotStoppedReason         = "ProvisionNotStopped"
 
+	installerStartingReason = "InstallerStarting"
+	installCompleteReason   = "InstallComplete"
+	installFailedReason     = "InstallFailed"
+
 	deleteAfterAnnotation    = "hive.openshift.io/delete-after" // contains a duration after which the cluster should be cleaned up.
 	tryInstallOnceAnnotation = "hive.openshift.io/try-install-once"
 
@@ -145,6 +152,7 @@ func NewReconciler(mgr manager.Manager, logger log.FieldLogger, rateLimiter flow
 		expectations:                            controllerutils.NewExpectations(logger),
 		watchingClusterInstall:                  map[string]struct{}{},
 		validateCredentialsForClusterDeployment: controllerutils.ValidateCredentialsForClusterDeployment,
+		eventRecorder:                           mgr.GetEventRecorderFor("clusterdeployment-controller"),
 	}
 	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
 		return remoteclient.NewBuilder(r.Client, cd, ControllerName)
@@ -156,6 +164,11 @@ func NewReconciler(mgr manager.Manager, logger log.FieldLogger, rateLimiter flow
 		r.protectedDelete = true
 	}
 
+	if preflightChecks, err := strconv.ParseBool(os.Getenv(constants.PreflightChecksEnvVar)); preflightChecks && err == nil {
+		logger.Info("Preflight checks enabled")
+		r.preflightCheck = runPreflightChecks
+	}
+
 	verifier, err := LoadReleaseImageVerifier(mgr.GetConfig())
 	if err == nil {
 		logger.Info("Release Image verification enabled")
@@ -278,6 +291,10 @@ type ReconcileClusterDeployment struct {
 	// Any error will prevent a release image from being accessed.
 	releaseImageVerifier verify.Interface
 
+	// preflightCheck, if set, is called to verify the cloud account has the quota and permissions to install
+	// the cluster before each provision is started. Left nil when pre-flight checks are disabled.
+	preflightCheck func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error
+
 	protectedDelete bool
 
 	// nodeSelector is copied from the hive-controllers pod and must be included in any Jobs we create from here.
@@ -285,6 +302,9 @@ type ReconcileClusterDeployment struct {
 
 	// tolerations is copied from the hive-controllers pod and must be included in any Jobs we create from here.
 	tolerations *[]corev1.Toleration
+
+	// eventRecorder records the events warning about the expiry of ClusterDeployments.
+	eventRecorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and makes changes based on the state read
@@ -569,38 +589,19 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 		return r.syncDeletedClusterDeployment(cd, cdLog)
 	}
 
-	// Check for the delete-after annotation, and if the cluster has expired, delete it
-	deleteAfter, ok := cd.Annotations[deleteAfterAnnotation]
-	if ok {
-		cdLog.Debugf("found delete after annotation: %s", deleteAfter)
-		dur, err := time.ParseDuration(deleteAfter)
-		if err != nil {
-			cdLog.WithError(err).WithField("deleteAfter", deleteAfter).Infof("error parsing %s as a duration", deleteAfterAnnotation)
-			return reconcile.Result{}, fmt.Errorf("error parsing %s as a duration: %v", deleteAfterAnnotation, err)
-		}
-		if !cd.CreationTimestamp.IsZero() {
-			expiry := cd.CreationTimestamp.Add(dur)
-			cdLog.Debugf("cluster expires at: %s", expiry)
-			if time.Now().After(expiry) {
-				cdLog.WithField("expiry", expiry).Info("cluster has expired, issuing delete")
-				err := controllerutils.SafeDelete(r, context.TODO(), cd)
-				if err != nil {
-					cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting expired cluster")
-				}
-				return reconcile.Result{}, err
-			}
-
-			defer func() {
-				// We have an expiry time but we're not expired yet. Set requeueAfter to the expiry time
-				// so that we requeue cluster for deletion once reconcile has completed
-				result, returnErr = controllerutils.EnsureRequeueAtLeastWithin(
-					time.Until(cd.CreationTimestamp.Add(dur)),
-					result,
-					returnErr,
-				)
-			}()
-
-		}
+	// Check for a lifetime, and if the cluster has expired, delete it
+	if deleted, requeueAt, err := r.reconcileExpiry(cd, cdLog); err != nil || deleted {
+		return reconcile.Result{}, err
+	} else if !requeueAt.IsZero() {
+		defer func() {
+			// We have an expiry time but we're not expired yet. Set requeueAfter to the expiry time, or to the
+			// time to warn about it, so that we requeue cluster once reconcile has completed
+			result, returnErr = controllerutils.EnsureRequeueAtLeastWithin(
+				time.Until(requeueAt),
+				result,
+				returnErr,
+			)
+		}()
 	}
 
 	if !controllerutils.HasFinalizer(cd, hivev1.FinalizerDeprovision) {
@@ -1375,6 +1376,17 @@ func (r *ReconcileClusterDeployment) ensureClusterDeprovisioned(cd *hivev1.Clust
 		return false, err
 	}
 
+	// The deprovision request is named after the ClusterDeployment, so dry runs of that name are refused by the
+	// clusterdeprovision controller without listing anything. Replace such a dry run with a real deprovision request.
+	if existingRequest.Spec.DryRun {
+		cdLog.Info("deleting dry run deprovision request to deprovision the cluster")
+		if err := r.Delete(context.TODO(), existingRequest); err != nil && !apierrors.IsNotFound(err) {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting dry run deprovision request")
+			return false, err
+		}
+		return false, nil
+	}
+
 	authenticationFailureCondition := controllerutils.FindCondition(existingRequest.Status.Conditions, hivev1.AuthenticationFailureClusterDeprovisionCondition)
 	if authenticationFailureCondition != nil {
 		var conds []hivev1.ClusterDeploymentCondition
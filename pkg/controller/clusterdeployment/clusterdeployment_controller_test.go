@@ -41,12 +41,14 @@ import (
 	"golang.org/x/crypto/openpgp"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -285,6 +287,38 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Provisioning provision reports install progress",
+			existing: []runtime.Object{
+				testInstallConfigSecretAWS(),
+				testClusterDeploymentWithDefaultConditions(testClusterDeploymentWithInitializedConditions(testClusterDeploymentWithProvision())),
+				testProvision(
+					tcp.WithStage(hivev1.ClusterProvisionStageProvisioning),
+					tcp.WithProgress(hivev1.ClusterProvisionPhaseBootstrapping, 35),
+				),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "no clusterdeployment found") {
+					testassert.AssertConditions(t, cd, []hivev1.ClusterDeploymentCondition{
+						{
+							Type:    hivev1.InstallProgressingCondition,
+							Status:  corev1.ConditionTrue,
+							Reason:  string(hivev1.ClusterProvisionPhaseBootstrapping),
+							Message: "Installer phase Bootstrapping, install 35% complete",
+						},
+						{
+							Type:    hivev1.ProvisionedCondition,
+							Status:  corev1.ConditionFalse,
+							Reason:  hivev1.ProvisionedReasonProvisioning,
+							Message: "Cluster is provisioning",
+						},
+					})
+				}
+			},
+		},
 		{
 			name: "Parse server URL from admin kubeconfig",
 			existing: []runtime.Object{
@@ -367,6 +401,45 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Completed provision waits for post-install hooks",
+			existing: []runtime.Object{
+				testInstallConfigSecretAWS(),
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeploymentWithInitializedConditions(testClusterDeploymentWithProvision())
+					cd.Spec.Provisioning.PostInstallHooks = []hivev1.InstallHook{
+						{Name: "smoke-test", JobTemplateRef: corev1.LocalObjectReference{Name: "smoke-test-job"}},
+					}
+					return cd
+				}(),
+				testSuccessfulProvision(tcp.WithMetadata(`{"aws": {"hostedZoneRole": "account-b-role"}}`)),
+				testMetadataConfigMap(),
+				testInstallHookJobTemplate("smoke-test-job"),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					assert.False(t, cd.Spec.Installed, "expected cluster not to be installed until hooks succeed")
+					testassert.AssertConditions(t, cd, []hivev1.ClusterDeploymentCondition{
+						{
+							Type:    hivev1.PostInstallHooksFailedCondition,
+							Status:  corev1.ConditionUnknown,
+							Reason:  installHookRunningReason,
+							Message: "Running post-install hook smoke-test",
+						},
+						{
+							Type:    hivev1.ProvisionedCondition,
+							Status:  corev1.ConditionTrue,
+							Reason:  hivev1.ProvisionedReasonProvisioned,
+							Message: "Cluster is provisioned",
+						},
+					})
+				}
+			},
+		},
 		{
 			name: "Completed provision with protected delete",
 			existing: []runtime.Object{
@@ -1734,7 +1807,8 @@ platform:
 				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
 			},
 			expectExplicitRequeue: true,
-			expectedRequeueAfter:  8 * time.Hour,
+			// Requeued to warn about the expiry an hour before it.
+			expectedRequeueAfter: 7 * time.Hour,
 		},
 		{
 			name: "Wait after failed provision",
@@ -2396,6 +2470,32 @@ platform:
 				}})
 			},
 		},
+		{
+			name: "replace completed dry run deprovision",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeploymentWithInitializedConditions(testClusterDeployment())
+					cd.Spec.Installed = true
+					now := metav1.Now()
+					cd.DeletionTimestamp = &now
+					return cd
+				}(),
+				testclusterdeprovision.Build(
+					testclusterdeprovision.WithNamespace(testNamespace),
+					testclusterdeprovision.WithName(testName),
+					testclusterdeprovision.WithDryRun(),
+					testclusterdeprovision.Completed(),
+				),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				require.NotNil(t, cd, "could not get ClusterDeployment")
+				assert.Contains(t, cd.Finalizers, hivev1.FinalizerDeprovision, "expected deprovision finalizer to remain")
+				deprovision := &hivev1.ClusterDeprovision{}
+				err := c.Get(context.TODO(), client.ObjectKey{Name: testName, Namespace: testNamespace}, deprovision)
+				assert.True(t, apierrors.IsNotFound(err), "expected dry run deprovision to be deleted")
+			},
+		},
 		{
 			name: "deprovision failed",
 			existing: []runtime.Object{
@@ -3430,6 +3530,7 @@ platform:
 				releaseImageVerifier: test.riVerifier,
 				nodeSelector:         &map[string]string{},
 				tolerations:          &[]corev1.Toleration{},
+				eventRecorder:        record.NewFakeRecorder(10),
 			}
 
 			if test.reconcilerSetup != nil {
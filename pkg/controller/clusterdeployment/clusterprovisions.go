@@ -108,6 +108,12 @@ func (r *ReconcileClusterDeployment) startNewProvision(
 	if cd.Spec.InstallAttemptsLimit != nil && cd.Status.InstallRestarts >= int(*cd.Spec.InstallAttemptsLimit) {
 		return setProvisionStoppedTrue(installAttemptsLimitReachedReason, "Install attempts limit reached")
 	}
+	if lastFailedProvision != nil {
+		if cond := controllerutils.FindCondition(lastFailedProvision.Status.Conditions, hivev1.ClusterProvisionFailedCondition); cond != nil &&
+			cond.Status == corev1.ConditionTrue && cond.Reason == hivev1.CustomInstallerFatalFailureReason {
+			return setProvisionStoppedTrue(hivev1.CustomInstallerFatalFailureReason, cond.Message)
+		}
+	}
 	shouldRetry, err := r.shouldRetryBasedOnFailureReason(lastFailedProvision, logger)
 	if err != nil {
 		logger.WithError(err).Error("failed to determine whether to retry based on provision failure reason")
@@ -134,6 +140,20 @@ func (r *ReconcileClusterDeployment) startNewProvision(
 		return reconcile.Result{}, nil
 	}
 
+	if result, err := r.checkPreflight(cd, logger); result != nil || err != nil {
+		if result == nil {
+			result = &reconcile.Result{}
+		}
+		return *result, err
+	}
+
+	if result, err := r.runInstallHooks(cd, preInstallHookStage, logger); result != nil || err != nil {
+		if result == nil {
+			result = &reconcile.Result{}
+		}
+		return *result, err
+	}
+
 	if err := controllerutils.SetupClusterInstallServiceAccount(r, cd.Namespace, logger); err != nil {
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "error setting up service account and role")
 		return reconcile.Result{}, err
@@ -518,6 +538,11 @@ func (r *ReconcileClusterDeployment) reconcileProvisioningProvision(cd *hivev1.C
 	); err != nil {
 		return reconcile.Result{}, err
 	}
+	status, reason, message := installProgressingConditionFor(provision)
+	if err := r.updateCondition(cd, hivev1.InstallProgressingCondition, status, reason, message, cdLog); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update InstallProgressingCondition")
+		return reconcile.Result{}, err
+	}
 	return reconcile.Result{}, nil
 }
 
@@ -544,6 +569,17 @@ func (r *ReconcileClusterDeployment) reconcileFailedProvision(cd *hivev1.Cluster
 		controllerutils.UpdateConditionIfReasonOrMessageChange,
 	)
 	cd.Status.Conditions = newConditions
+	status, progressReason, progressMessage := installProgressingConditionFor(provision)
+	newConditions, progressChange := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.InstallProgressingCondition,
+		status,
+		progressReason,
+		progressMessage,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	cd.Status.Conditions = newConditions
+	condChange = condChange || progressChange
 
 	timeUntilNextProvision := time.Until(nextProvisionTime)
 	if timeUntilNextProvision.Seconds() > 0 {
@@ -593,6 +629,19 @@ func (r *ReconcileClusterDeployment) reconcileCompletedProvision(cd *hivev1.Clus
 		statusChange = true
 		cd.Status.Conditions = conds
 	}
+	status, reason, message := installProgressingConditionFor(provision)
+	conds, changed = controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.InstallProgressingCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if changed {
+		statusChange = true
+		cd.Status.Conditions = conds
+	}
 	if statusChange {
 		if err := r.Status().Update(context.TODO(), cd); err != nil {
 			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster deployment status")
@@ -604,6 +653,13 @@ func (r *ReconcileClusterDeployment) reconcileCompletedProvision(cd *hivev1.Clus
 		return reconcile.Result{}, nil
 	}
 
+	if result, err := r.runInstallHooks(cd, postInstallHookStage, cdLog); result != nil || err != nil {
+		if result == nil {
+			result = &reconcile.Result{}
+		}
+		return *result, err
+	}
+
 	cd.Spec.Installed = true
 
 	if r.protectedDelete {
@@ -639,6 +695,27 @@ func (r *ReconcileClusterDeployment) reconcileCompletedProvision(cd *hivev1.Clus
 	return reconcile.Result{}, nil
 }
 
+// installProgressingConditionFor returns the InstallProgressing condition status, reason, and message mirroring the
+// installer progress reported on the provision.
+func installProgressingConditionFor(provision *hivev1.ClusterProvision) (corev1.ConditionStatus, string, string) {
+	progress := provision.Status.Progress
+	switch provision.Spec.Stage {
+	case hivev1.ClusterProvisionStageComplete:
+		return corev1.ConditionFalse, installCompleteReason, fmt.Sprintf("Provision %s completed", provision.Name)
+	case hivev1.ClusterProvisionStageFailed:
+		if progress == nil {
+			return corev1.ConditionFalse, installFailedReason, fmt.Sprintf("Provision %s failed", provision.Name)
+		}
+		return corev1.ConditionFalse, installFailedReason,
+			fmt.Sprintf("Provision %s failed during installer phase %s", provision.Name, progress.Phase)
+	}
+	if progress == nil {
+		return corev1.ConditionTrue, installerStartingReason, "Waiting for the installer to report progress"
+	}
+	return corev1.ConditionTrue, string(progress.Phase),
+		fmt.Sprintf("Installer phase %s, install %d%% complete", progress.Phase, progress.Percent)
+}
+
 func getClusterImageSetFromProvisioning(cd *hivev1.ClusterDeployment) string {
 	if cd.Spec.Provisioning.ImageSetRef != nil {
 		return cd.Spec.Provisioning.ImageSetRef.Name
@@ -0,0 +1,143 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// expiryWarningPeriod is how long before its expiry a warning event is emitted for a ClusterDeployment.
+	expiryWarningPeriod = time.Hour
+
+	clusterExpiringReason      = "ClusterExpiring"
+	clusterExpiredReason       = "ClusterExpired"
+	clusterExpiryBlockedReason = "ClusterExpiryBlocked"
+)
+
+// reconcileExpiry deletes the ClusterDeployment once its lifetime has elapsed, and warns about it beforehand. The
+// expiry is recorded in the status of the ClusterDeployment. It returns whether the ClusterDeployment was deleted and,
+// when it has not expired yet, the time at which it must be reconciled again.
+func (r *ReconcileClusterDeployment) reconcileExpiry(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (deleted bool, requeueAt time.Time, err error) {
+	expiry, err := clusterExpiry(cd)
+	if err != nil {
+		cdLog.WithError(err).Info("error determining cluster expiry")
+		return false, time.Time{}, err
+	}
+
+	statusChanged := false
+	if expiry.IsZero() {
+		if cd.Status.ExpiryTimestamp != nil || cd.Status.ExpiryWarningTimestamp != nil {
+			cd.Status.ExpiryTimestamp = nil
+			cd.Status.ExpiryWarningTimestamp = nil
+			statusChanged = true
+		}
+		return false, time.Time{}, r.updateExpiryStatus(cd, statusChanged, cdLog)
+	}
+	if cd.Status.ExpiryTimestamp == nil || !cd.Status.ExpiryTimestamp.Time.Equal(expiry) {
+		cd.Status.ExpiryTimestamp = &metav1.Time{Time: expiry}
+		statusChanged = true
+	}
+	cdLog = cdLog.WithField("expiry", expiry)
+	cdLog.Debug("cluster has an expiry")
+	now := time.Now()
+	warned := cd.Status.ExpiryWarningTimestamp != nil
+
+	if !now.Before(expiry) {
+		blockedReason := expiryBlockedReason(cd)
+		if blockedReason == "" {
+			cdLog.Info("cluster has expired, issuing delete")
+			if err := controllerutils.SafeDelete(r, context.TODO(), cd); err != nil {
+				cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting expired cluster")
+				return false, time.Time{}, err
+			}
+			r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, clusterExpiredReason, "ClusterDeployment expired at %s and was deleted", expiry.UTC().Format(time.RFC3339))
+			return true, time.Time{}, nil
+		}
+		// Warn once about the blocked deletion, rather than on every reconcile.
+		if !warned || cd.Status.ExpiryWarningTimestamp.Time.Before(expiry) {
+			cdLog.WithField("reason", blockedReason).Warn("cluster has expired but will not be deleted")
+			r.eventRecorder.Eventf(cd, corev1.EventTypeWarning, clusterExpiryBlockedReason, "ClusterDeployment expired at %s but was not deleted because %s", expiry.UTC().Format(time.RFC3339), blockedReason)
+			cd.Status.ExpiryWarningTimestamp = &metav1.Time{Time: now}
+			statusChanged = true
+		}
+		return false, time.Time{}, r.updateExpiryStatus(cd, statusChanged, cdLog)
+	}
+
+	warnAt := expiry.Add(-expiryWarningPeriod)
+	if now.Before(warnAt) {
+		return false, warnAt, r.updateExpiryStatus(cd, statusChanged, cdLog)
+	}
+	// Warn again if the lifetime was extended after the previous warning.
+	if !warned || cd.Status.ExpiryWarningTimestamp.Time.Before(warnAt) {
+		cdLog.Info("cluster is about to expire")
+		r.eventRecorder.Eventf(cd, corev1.EventTypeWarning, clusterExpiringReason,
+			"ClusterDeployment will be deleted at %s. Set the %s annotation to extend its lifetime.",
+			expiry.UTC().Format(time.RFC3339), constants.DeleteAfterExtensionAnnotation)
+		cd.Status.ExpiryWarningTimestamp = &metav1.Time{Time: now}
+		statusChanged = true
+	}
+	return false, expiry, r.updateExpiryStatus(cd, statusChanged, cdLog)
+}
+
+func (r *ReconcileClusterDeployment) updateExpiryStatus(cd *hivev1.ClusterDeployment, changed bool, cdLog log.FieldLogger) error {
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster expiry status")
+		return err
+	}
+	return nil
+}
+
+// clusterExpiry returns the time after which the ClusterDeployment must be deleted, or the zero time if it has no
+// lifetime.
+func clusterExpiry(cd *hivev1.ClusterDeployment) (time.Time, error) {
+	if cd.CreationTimestamp.IsZero() {
+		return time.Time{}, nil
+	}
+	var lifetime time.Duration
+	switch deleteAfter, ok := cd.Annotations[deleteAfterAnnotation]; {
+	case cd.Spec.DeleteAfter != nil:
+		lifetime = cd.Spec.DeleteAfter.Duration
+	case ok:
+		var err error
+		if lifetime, err = time.ParseDuration(deleteAfter); err != nil {
+			return time.Time{}, fmt.Errorf("error parsing %s as a duration: %v", deleteAfterAnnotation, err)
+		}
+	default:
+		return time.Time{}, nil
+	}
+	if extension, ok := cd.Annotations[constants.DeleteAfterExtensionAnnotation]; ok {
+		dur, err := time.ParseDuration(extension)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing %s as a duration: %v", constants.DeleteAfterExtensionAnnotation, err)
+		}
+		lifetime += dur
+	}
+	return cd.CreationTimestamp.Add(lifetime), nil
+}
+
+// expiryBlockedReason returns why an expired ClusterDeployment must not be deleted, if it must not. PreserveOnDelete
+// only blocks the expiry set by DeleteAfter: clusters expiring through the older delete-after annotation are deleted,
+// and kept in the cloud, as they always were.
+func expiryBlockedReason(cd *hivev1.ClusterDeployment) string {
+	if protected, err := strconv.ParseBool(cd.Annotations[constants.ProtectedDeleteAnnotation]); protected && err == nil {
+		return fmt.Sprintf("it has the %s annotation", constants.ProtectedDeleteAnnotation)
+	}
+	if cd.Spec.PreserveOnDelete && cd.Spec.DeleteAfter != nil {
+		return "preserveOnDelete is set"
+	}
+	return ""
+}
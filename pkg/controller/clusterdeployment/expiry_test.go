@@ -0,0 +1,170 @@
+package clusterdeployment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+func TestReconcileExpiry(t *testing.T) {
+	created := func(ago time.Duration, deleteAfter time.Duration) *hivev1.ClusterDeployment {
+		cd := testClusterDeployment()
+		cd.CreationTimestamp = metav1.NewTime(time.Now().Add(-ago))
+		cd.Spec.DeleteAfter = &metav1.Duration{Duration: deleteAfter}
+		return cd
+	}
+
+	tests := []struct {
+		name                string
+		cd                  *hivev1.ClusterDeployment
+		expectDeleted       bool
+		expectRequeueWithin time.Duration
+		expectExpiry        bool
+		expectEvent         string
+	}{
+		{
+			name: "no lifetime",
+			cd:   testClusterDeployment(),
+		},
+		{
+			name:                "not expiring soon",
+			cd:                  created(time.Hour, 4*time.Hour),
+			expectRequeueWithin: 2 * time.Hour,
+			expectExpiry:        true,
+		},
+		{
+			name:                "expiring soon",
+			cd:                  created(time.Hour, 90*time.Minute),
+			expectRequeueWithin: 30 * time.Minute,
+			expectExpiry:        true,
+			expectEvent:         clusterExpiringReason,
+		},
+		{
+			name: "already warned",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 90*time.Minute)
+				cd.Status.ExpiryWarningTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+				return cd
+			}(),
+			expectRequeueWithin: 30 * time.Minute,
+			expectExpiry:        true,
+		},
+		{
+			name: "extended",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 90*time.Minute)
+				cd.Annotations = map[string]string{constants.DeleteAfterExtensionAnnotation: "2h"}
+				return cd
+			}(),
+			expectRequeueWithin: 90 * time.Minute,
+			expectExpiry:        true,
+		},
+		{
+			name: "spec takes precedence over annotation",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 4*time.Hour)
+				cd.Annotations = map[string]string{deleteAfterAnnotation: "5m"}
+				return cd
+			}(),
+			expectRequeueWithin: 2 * time.Hour,
+			expectExpiry:        true,
+		},
+		{
+			name:          "expired",
+			cd:            created(time.Hour, 30*time.Minute),
+			expectDeleted: true,
+			expectEvent:   clusterExpiredReason,
+		},
+		{
+			name: "expired but protected",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 30*time.Minute)
+				cd.Annotations = map[string]string{constants.ProtectedDeleteAnnotation: "true"}
+				return cd
+			}(),
+			expectExpiry: true,
+			expectEvent:  clusterExpiryBlockedReason,
+		},
+		{
+			name: "expired but preserved on delete",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 30*time.Minute)
+				cd.Spec.PreserveOnDelete = true
+				return cd
+			}(),
+			expectExpiry: true,
+			expectEvent:  clusterExpiryBlockedReason,
+		},
+		{
+			name: "expired through annotation and preserved on delete",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 30*time.Minute)
+				cd.Spec.DeleteAfter = nil
+				cd.Annotations = map[string]string{deleteAfterAnnotation: "30m"}
+				cd.Spec.PreserveOnDelete = true
+				return cd
+			}(),
+			expectDeleted: true,
+			expectEvent:   clusterExpiredReason,
+		},
+		{
+			name: "expired and blocked deletion already reported",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := created(time.Hour, 30*time.Minute)
+				cd.Spec.PreserveOnDelete = true
+				cd.Status.ExpiryWarningTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+				return cd
+			}(),
+			expectExpiry: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.cd.Finalizers = []string{hivev1.FinalizerDeprovision}
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(test.cd).Build()
+			recorder := record.NewFakeRecorder(10)
+			rcd := &ReconcileClusterDeployment{
+				Client:        fakeClient,
+				scheme:        scheme.GetScheme(),
+				logger:        log.WithField("controller", "clusterDeployment"),
+				eventRecorder: recorder,
+			}
+
+			deleted, requeueAt, err := rcd.reconcileExpiry(test.cd, rcd.logger)
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, test.expectDeleted, deleted, "unexpected deleted")
+			if test.expectRequeueWithin == 0 {
+				assert.True(t, requeueAt.IsZero(), "unexpected requeue at %s", requeueAt)
+			} else {
+				assert.InDelta(t, test.expectRequeueWithin, time.Until(requeueAt), float64(10*time.Second), "unexpected requeue")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: testNamespace, Name: testName}, cd))
+			assert.Equal(t, test.expectDeleted, cd.DeletionTimestamp != nil, "unexpected deletion")
+			assert.Equal(t, test.expectExpiry, cd.Status.ExpiryTimestamp != nil, "unexpected expiry timestamp")
+
+			select {
+			case event := <-recorder.Events:
+				if assert.NotEmpty(t, test.expectEvent, "unexpected event %q", event) {
+					assert.Contains(t, event, test.expectEvent, "unexpected event")
+				}
+			default:
+				assert.Empty(t, test.expectEvent, "expected an event")
+			}
+		})
+	}
+}
@@ -0,0 +1,309 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	apihelpers "github.com/openshift/hive/apis/helpers"
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	k8slabels "github.com/openshift/hive/pkg/util/labels"
+)
+
+const (
+	installHooksSucceededReason      = "InstallHooksSucceeded"
+	installHookRunningReason         = "InstallHookRunning"
+	installHookFailedReason          = "InstallHookFailed"
+	installHookTemplateInvalidReason = "InstallHookTemplateInvalid"
+
+	installHookKubeconfigVolumeName = "cluster-kubeconfig"
+	installHookKubeconfigDir        = "/etc/hive/cluster"
+)
+
+// installHookStage describes when a list of install hooks is run.
+type installHookStage struct {
+	name      string
+	jobType   string
+	condition hivev1.ClusterDeploymentConditionType
+	hooks     func(*hivev1.Provisioning) []hivev1.InstallHook
+}
+
+var (
+	preInstallHookStage = installHookStage{
+		name:      "pre-install",
+		jobType:   constants.JobTypePreInstallHook,
+		condition: hivev1.PreInstallHooksFailedCondition,
+		hooks:     func(p *hivev1.Provisioning) []hivev1.InstallHook { return p.PreInstallHooks },
+	}
+	postInstallHookStage = installHookStage{
+		name:      "post-install",
+		jobType:   constants.JobTypePostInstallHook,
+		condition: hivev1.PostInstallHooksFailedCondition,
+		hooks:     func(p *hivev1.Provisioning) []hivev1.InstallHook { return p.PostInstallHooks },
+	}
+)
+
+// installHookTemplateError is returned when an install hook's Job template cannot be used.
+type installHookTemplateError struct {
+	message string
+}
+
+func (e *installHookTemplateError) Error() string {
+	return e.message
+}
+
+// runInstallHooks runs the ClusterDeployment's install hooks for the given stage one at a time, in order. It returns
+// nil once all of them have succeeded; otherwise it returns the result with which reconciling should stop.
+func (r *ReconcileClusterDeployment) runInstallHooks(cd *hivev1.ClusterDeployment, stage installHookStage, logger log.FieldLogger) (*reconcile.Result, error) {
+	if cd.Spec.Provisioning == nil {
+		return nil, nil
+	}
+	hooks := stage.hooks(cd.Spec.Provisioning)
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+	for _, hook := range hooks {
+		jobKey := client.ObjectKey{Namespace: cd.Namespace, Name: installHookJobName(cd, stage, hook)}
+		jobLog := logger.WithField("installHook", hook.Name).WithField("job", jobKey.Name)
+
+		existingJob := &batchv1.Job{}
+		switch err := r.Get(context.TODO(), jobKey, existingJob); {
+		case apierrors.IsNotFound(err):
+			job, err := r.generateInstallHookJob(cd, stage, hook, jobKey.Name)
+			var tmplErr *installHookTemplateError
+			if errors.As(err, &tmplErr) {
+				jobLog.WithError(err).Warn("invalid install hook job template")
+				// The template's ConfigMap is not watched, so check back periodically for it to be fixed.
+				return &reconcile.Result{RequeueAfter: defaultRequeueTime}, r.updateCondition(
+					cd, stage.condition, corev1.ConditionTrue, installHookTemplateInvalidReason,
+					fmt.Sprintf("Invalid job template for %s hook %s: %v", stage.name, hook.Name, err), logger)
+			}
+			if err != nil {
+				jobLog.WithError(err).Error("could not generate install hook job")
+				return nil, err
+			}
+			if err := controllerutils.SetupClusterInstallHookServiceAccount(r, cd.Namespace, jobLog); err != nil {
+				jobLog.WithError(err).Error("error setting up service account for install hook")
+				return nil, err
+			}
+			if err := controllerutil.SetControllerReference(cd, job, r.scheme); err != nil {
+				jobLog.WithError(err).Error("error setting controller reference on job")
+				return nil, err
+			}
+			jobLog.Infof("creating %s hook job", stage.name)
+			if err := r.Create(context.TODO(), job); err != nil {
+				jobLog.WithError(err).Log(controllerutils.LogLevel(err), "error creating job")
+				return nil, err
+			}
+			return &reconcile.Result{}, r.updateCondition(
+				cd, stage.condition, corev1.ConditionUnknown, installHookRunningReason,
+				fmt.Sprintf("Running %s hook %s", stage.name, hook.Name), logger)
+		case err != nil:
+			jobLog.WithError(err).Error("cannot get job")
+			return nil, err
+		case !existingJob.DeletionTimestamp.IsZero():
+			jobLog.Debug("install hook job is being deleted. Will recreate once deleted")
+			return &reconcile.Result{RequeueAfter: defaultRequeueTime}, nil
+		case controllerutils.IsFailed(existingJob):
+			jobLog.Info("install hook job failed")
+			return &reconcile.Result{}, r.updateCondition(
+				cd, stage.condition, corev1.ConditionTrue, installHookFailedReason,
+				fmt.Sprintf("Job %s for %s hook %s failed; delete the job to run the hook again", existingJob.Name, stage.name, hook.Name),
+				logger)
+		case !controllerutils.IsSuccessful(existingJob):
+			jobLog.Debug("install hook job still running")
+			return &reconcile.Result{}, r.updateCondition(
+				cd, stage.condition, corev1.ConditionUnknown, installHookRunningReason,
+				fmt.Sprintf("Running %s hook %s", stage.name, hook.Name), logger)
+		}
+	}
+	return nil, r.updateCondition(
+		cd, stage.condition, corev1.ConditionFalse, installHooksSucceededReason,
+		fmt.Sprintf("All %s hooks succeeded", stage.name), logger)
+}
+
+func installHookJobName(cd *hivev1.ClusterDeployment, stage installHookStage, hook hivev1.InstallHook) string {
+	return apihelpers.GetResourceName(cd.Name, fmt.Sprintf("%s-%s", stage.jobType, hook.Name))
+}
+
+// generateInstallHookJob builds the Job for an install hook from its template, giving it the cluster's details.
+func (r *ReconcileClusterDeployment) generateInstallHookJob(cd *hivev1.ClusterDeployment, stage installHookStage, hook hivev1.InstallHook, name string) (*batchv1.Job, error) {
+	cm := &corev1.ConfigMap{}
+	switch err := r.Get(context.TODO(), client.ObjectKey{Namespace: cd.Namespace, Name: hook.JobTemplateRef.Name}, cm); {
+	case apierrors.IsNotFound(err):
+		return nil, &installHookTemplateError{message: fmt.Sprintf("configmap %s not found", hook.JobTemplateRef.Name)}
+	case err != nil:
+		return nil, errors.Wrap(err, "could not get job template configmap")
+	}
+	manifest, ok := cm.Data[constants.InstallHookJobTemplateKey]
+	if !ok {
+		return nil, &installHookTemplateError{
+			message: fmt.Sprintf("configmap %s has no %s key", cm.Name, constants.InstallHookJobTemplateKey),
+		}
+	}
+	template := &batchv1.Job{}
+	if err := yaml.Unmarshal([]byte(manifest), template); err != nil {
+		return nil, &installHookTemplateError{message: fmt.Sprintf("could not parse job manifest: %v", err)}
+	}
+	if err := validateInstallHookPodSpec(&template.Spec.Template.Spec, hook); err != nil {
+		return nil, err
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "CLUSTER_DEPLOYMENT_NAME", Value: cd.Name},
+		{Name: "CLUSTER_DEPLOYMENT_NAMESPACE", Value: cd.Namespace},
+		{Name: "CLUSTER_NAME", Value: cd.Spec.ClusterName},
+		{Name: "BASE_DOMAIN", Value: cd.Spec.BaseDomain},
+	}
+	var volume *corev1.Volume
+	if stage.jobType == constants.JobTypePostInstallHook {
+		if cd.Spec.ClusterMetadata == nil {
+			return nil, errors.New("cluster metadata not yet available")
+		}
+		env = append(env,
+			corev1.EnvVar{Name: "CLUSTER_ID", Value: cd.Spec.ClusterMetadata.ClusterID},
+			corev1.EnvVar{Name: "INFRA_ID", Value: cd.Spec.ClusterMetadata.InfraID},
+			corev1.EnvVar{Name: "API_URL", Value: cd.Status.APIURL},
+			corev1.EnvVar{Name: "KUBECONFIG", Value: filepath.Join(installHookKubeconfigDir, constants.KubeconfigSecretKey)},
+		)
+		volume = &corev1.Volume{
+			Name: installHookKubeconfigVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name,
+				},
+			},
+		}
+	}
+
+	job := &batchv1.Job{}
+	job.Name = name
+	job.Namespace = cd.Namespace
+	job.Labels = template.Labels
+	job.Annotations = template.Annotations
+	job.Spec = template.Spec
+	job.Labels = k8slabels.AddLabel(job.Labels, constants.ClusterDeploymentNameLabel, cd.Name)
+	job.Labels = k8slabels.AddLabel(job.Labels, constants.JobTypeLabel, stage.jobType)
+	job.Labels = k8slabels.AddLabel(job.Labels, constants.InstallHookNameLabel, hook.Name)
+
+	podSpec := &job.Spec.Template.Spec
+	if podSpec.RestartPolicy == "" {
+		podSpec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	restrictInstallHookPodSpec(podSpec)
+	if volume != nil {
+		podSpec.Volumes = append(podSpec.Volumes, *volume)
+	}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			// Variables set by the template come later so that they take precedence.
+			containers[i].Env = append(append([]corev1.EnvVar{}, env...), containers[i].Env...)
+			if volume != nil {
+				containers[i].VolumeMounts = append(containers[i].VolumeMounts, corev1.VolumeMount{
+					Name:      installHookKubeconfigVolumeName,
+					MountPath: installHookKubeconfigDir,
+					ReadOnly:  true,
+				})
+			}
+		}
+	}
+	return job, nil
+}
+
+// validateInstallHookPodSpec rejects install hook pod specs using Secrets not listed in the hook's SecretRefs, which
+// the users able to edit the ConfigMap may not be allowed to read, and volumes other than those known to only expose
+// data of the namespace through the kube API.
+func validateInstallHookPodSpec(podSpec *corev1.PodSpec, hook hivev1.InstallHook) error {
+	allowed := sets.New[string]()
+	for _, ref := range hook.SecretRefs {
+		allowed.Insert(ref.Name)
+	}
+	checkSecret := func(where, name string) error {
+		if !allowed.Has(name) {
+			return &installHookTemplateError{message: fmt.Sprintf("%s: secret %s is not listed in the secretRefs of the hook", where, name)}
+		}
+		return nil
+	}
+
+	for _, v := range podSpec.Volumes {
+		where := fmt.Sprintf("volume %s", v.Name)
+		switch {
+		case v.Secret != nil:
+			if err := checkSecret(where, v.Secret.SecretName); err != nil {
+				return err
+			}
+		case v.Projected != nil:
+			for _, source := range v.Projected.Sources {
+				if source.Secret == nil {
+					continue
+				}
+				if err := checkSecret(where, source.Secret.Name); err != nil {
+					return err
+				}
+			}
+		case v.ConfigMap != nil, v.DownwardAPI != nil, v.EmptyDir != nil, v.PersistentVolumeClaim != nil, v.Ephemeral != nil:
+		default:
+			return &installHookTemplateError{message: fmt.Sprintf("%s: only secret, projected, configMap, downwardAPI, emptyDir, persistentVolumeClaim and ephemeral volumes are allowed", where)}
+		}
+	}
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for _, c := range containers {
+			for _, e := range c.EnvFrom {
+				if e.SecretRef == nil {
+					continue
+				}
+				if err := checkSecret(fmt.Sprintf("container %s envFrom", c.Name), e.SecretRef.Name); err != nil {
+					return err
+				}
+			}
+			for _, e := range c.Env {
+				if e.ValueFrom == nil || e.ValueFrom.SecretKeyRef == nil {
+					continue
+				}
+				if err := checkSecret(fmt.Sprintf("container %s env %s", c.Name, e.Name), e.ValueFrom.SecretKeyRef.Name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// restrictInstallHookPodSpec drops the parts of an install hook's pod spec which would give it more privileges than
+// the user creating the ConfigMap has: the hook runs as Hive's service account without any permissions, without
+// access to the host, and with the security context defaulted by the cluster.
+func restrictInstallHookPodSpec(podSpec *corev1.PodSpec) {
+	podSpec.ServiceAccountName = controllerutils.InstallHookServiceAccountName
+	podSpec.DeprecatedServiceAccount = ""
+	podSpec.AutomountServiceAccountToken = ptr.To(false)
+	podSpec.HostNetwork = false
+	podSpec.HostPID = false
+	podSpec.HostIPC = false
+	podSpec.HostUsers = nil
+	podSpec.SecurityContext = nil
+	podSpec.EphemeralContainers = nil
+	for _, containers := range [][]corev1.Container{podSpec.InitContainers, podSpec.Containers} {
+		for i := range containers {
+			containers[i].SecurityContext = nil
+			for j := range containers[i].Ports {
+				containers[i].Ports[j].HostPort = 0
+				containers[i].Ports[j].HostIP = ""
+			}
+		}
+	}
+}
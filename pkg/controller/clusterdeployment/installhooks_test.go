@@ -0,0 +1,433 @@
+package clusterdeployment
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const testInstallHookJobManifest = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: ignored
+  labels:
+    app: hook
+spec:
+  backoffLimit: 2
+  template:
+    spec:
+      containers:
+      - name: hook
+        image: quay.io/example/hook:latest
+        env:
+        - name: CLUSTER_NAME
+          value: overridden
+`
+
+const testPrivilegedInstallHookJobManifest = `apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      serviceAccountName: hive-controllers
+      hostNetwork: true
+      hostPID: true
+      securityContext:
+        runAsUser: 0
+      containers:
+      - name: hook
+        image: quay.io/example/hook:latest
+        securityContext:
+          privileged: true
+        ports:
+        - containerPort: 8080
+          hostPort: 80
+`
+
+const testHostPathInstallHookJobManifest = `apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      containers:
+      - name: hook
+        image: quay.io/example/hook:latest
+      volumes:
+      - name: host
+        hostPath:
+          path: /
+`
+
+const testSecretInstallHookJobManifest = `apiVersion: batch/v1
+kind: Job
+spec:
+  template:
+    spec:
+      containers:
+      - name: hook
+        image: quay.io/example/hook:latest
+        envFrom:
+        - secretRef:
+            name: hook-creds
+        volumeMounts:
+        - name: creds
+          mountPath: /etc/creds
+      volumes:
+      - name: creds
+        secret:
+          secretName: hook-creds
+`
+
+func testInstallHookJobTemplate(name string) *corev1.ConfigMap {
+	return testInstallHookJobTemplateWithManifest(name, testInstallHookJobManifest)
+}
+
+func testInstallHookJobTemplateWithManifest(name, manifest string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+		},
+		Data: map[string]string{
+			constants.InstallHookJobTemplateKey: manifest,
+		},
+	}
+}
+
+func testInstallHookJob(stage installHookStage, hookName string, conditionType batchv1.JobConditionType) *batchv1.Job {
+	cd := testClusterDeployment()
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      installHookJobName(cd, stage, hivev1.InstallHook{Name: hookName}),
+			Namespace: testNamespace,
+		},
+	}
+	if conditionType != "" {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+	}
+	return job
+}
+
+func TestRunInstallHooks(t *testing.T) {
+	hooks := []hivev1.InstallHook{
+		{Name: "first", JobTemplateRef: corev1.LocalObjectReference{Name: "first-job"}},
+		{Name: "second", JobTemplateRef: corev1.LocalObjectReference{Name: "second-job"}},
+	}
+	tests := []struct {
+		name            string
+		stage           installHookStage
+		noHooks         bool
+		secretRefs      []corev1.LocalObjectReference
+		existing        []runtime.Object
+		expectResult    bool
+		expectRequeue   bool
+		expectCondition corev1.ConditionStatus
+		expectReason    string
+		expectJob       string
+		validateJob     func(*testing.T, *batchv1.Job)
+	}{
+		{
+			name:    "no hooks",
+			stage:   preInstallHookStage,
+			noHooks: true,
+		},
+		{
+			name:            "missing template",
+			stage:           preInstallHookStage,
+			expectResult:    true,
+			expectRequeue:   true,
+			expectCondition: corev1.ConditionTrue,
+			expectReason:    installHookTemplateInvalidReason,
+		},
+		{
+			name:            "creates first pre-install job",
+			stage:           preInstallHookStage,
+			existing:        []runtime.Object{testInstallHookJobTemplate("first-job")},
+			expectResult:    true,
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    installHookRunningReason,
+			expectJob:       "first",
+			validateJob: func(t *testing.T, job *batchv1.Job) {
+				assert.Equal(t, constants.JobTypePreInstallHook, job.Labels[constants.JobTypeLabel], "unexpected job type label")
+				assert.Equal(t, "hook", job.Labels["app"], "template label not kept")
+				assert.Equal(t, int32(2), *job.Spec.BackoffLimit, "template spec not kept")
+				assert.Equal(t, corev1.RestartPolicyNever, job.Spec.Template.Spec.RestartPolicy, "unexpected restart policy")
+				assert.Empty(t, job.Spec.Template.Spec.Volumes, "pre-install hooks should not mount the kubeconfig")
+				env := job.Spec.Template.Spec.Containers[0].Env
+				assert.Contains(t, env, corev1.EnvVar{Name: "CLUSTER_DEPLOYMENT_NAME", Value: testName}, "missing hive env var")
+				// The template's own value comes last so that it wins.
+				assert.Equal(t, corev1.EnvVar{Name: "CLUSTER_NAME", Value: "overridden"}, env[len(env)-1], "template env var not kept last")
+			},
+		},
+		{
+			name:            "restricts privileged template",
+			stage:           preInstallHookStage,
+			existing:        []runtime.Object{testInstallHookJobTemplateWithManifest("first-job", testPrivilegedInstallHookJobManifest)},
+			expectResult:    true,
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    installHookRunningReason,
+			expectJob:       "first",
+			validateJob: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				assert.Equal(t, controllerutils.InstallHookServiceAccountName, podSpec.ServiceAccountName, "unexpected service account")
+				if assert.NotNil(t, podSpec.AutomountServiceAccountToken, "expected service account token not to be mounted") {
+					assert.False(t, *podSpec.AutomountServiceAccountToken, "expected service account token not to be mounted")
+				}
+				assert.False(t, podSpec.HostNetwork, "unexpected host network")
+				assert.False(t, podSpec.HostPID, "unexpected host PID")
+				assert.Nil(t, podSpec.SecurityContext, "unexpected pod security context")
+				assert.Nil(t, podSpec.Containers[0].SecurityContext, "unexpected container security context")
+				assert.Zero(t, podSpec.Containers[0].Ports[0].HostPort, "unexpected host port")
+				assert.Equal(t, int32(8080), podSpec.Containers[0].Ports[0].ContainerPort, "container port not kept")
+			},
+		},
+		{
+			name:            "rejects hostPath volumes",
+			stage:           preInstallHookStage,
+			existing:        []runtime.Object{testInstallHookJobTemplateWithManifest("first-job", testHostPathInstallHookJobManifest)},
+			expectResult:    true,
+			expectRequeue:   true,
+			expectCondition: corev1.ConditionTrue,
+			expectReason:    installHookTemplateInvalidReason,
+		},
+		{
+			name:            "allows secrets listed in secretRefs",
+			stage:           preInstallHookStage,
+			secretRefs:      []corev1.LocalObjectReference{{Name: "hook-creds"}},
+			existing:        []runtime.Object{testInstallHookJobTemplateWithManifest("first-job", testSecretInstallHookJobManifest)},
+			expectResult:    true,
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    installHookRunningReason,
+			expectJob:       "first",
+			validateJob: func(t *testing.T, job *batchv1.Job) {
+				podSpec := job.Spec.Template.Spec
+				if assert.Len(t, podSpec.Volumes, 1, "expected secret volume") {
+					assert.Equal(t, "hook-creds", podSpec.Volumes[0].Secret.SecretName, "unexpected secret volume")
+				}
+			},
+		},
+		{
+			name:            "rejects secrets not listed in secretRefs",
+			stage:           preInstallHookStage,
+			secretRefs:      []corev1.LocalObjectReference{{Name: "other-creds"}},
+			existing:        []runtime.Object{testInstallHookJobTemplateWithManifest("first-job", testSecretInstallHookJobManifest)},
+			expectResult:    true,
+			expectRequeue:   true,
+			expectCondition: corev1.ConditionTrue,
+			expectReason:    installHookTemplateInvalidReason,
+		},
+		{
+			name:  "first job running",
+			stage: preInstallHookStage,
+			existing: []runtime.Object{
+				testInstallHookJobTemplate("first-job"),
+				testInstallHookJob(preInstallHookStage, "first", ""),
+			},
+			expectResult:    true,
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    installHookRunningReason,
+		},
+		{
+			name:  "first job failed",
+			stage: preInstallHookStage,
+			existing: []runtime.Object{
+				testInstallHookJobTemplate("first-job"),
+				testInstallHookJob(preInstallHookStage, "first", batchv1.JobFailed),
+			},
+			expectResult:    true,
+			expectCondition: corev1.ConditionTrue,
+			expectReason:    installHookFailedReason,
+		},
+		{
+			name:  "creates second post-install job after first succeeds",
+			stage: postInstallHookStage,
+			existing: []runtime.Object{
+				testInstallHookJobTemplate("second-job"),
+				testInstallHookJob(postInstallHookStage, "first", batchv1.JobComplete),
+			},
+			expectResult:    true,
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    installHookRunningReason,
+			expectJob:       "second",
+			validateJob: func(t *testing.T, job *batchv1.Job) {
+				assert.Equal(t, constants.JobTypePostInstallHook, job.Labels[constants.JobTypeLabel], "unexpected job type label")
+				podSpec := job.Spec.Template.Spec
+				if assert.Len(t, podSpec.Volumes, 1, "expected kubeconfig volume") {
+					assert.Equal(t, adminKubeconfigSecret, podSpec.Volumes[0].Secret.SecretName, "unexpected kubeconfig secret")
+				}
+				assert.Len(t, podSpec.Containers[0].VolumeMounts, 1, "expected kubeconfig volume mount")
+				assert.Contains(t, podSpec.Containers[0].Env,
+					corev1.EnvVar{Name: "KUBECONFIG", Value: "/etc/hive/cluster/kubeconfig"}, "missing KUBECONFIG")
+				assert.Contains(t, podSpec.Containers[0].Env,
+					corev1.EnvVar{Name: "INFRA_ID", Value: testClusterDeployment().Spec.ClusterMetadata.InfraID}, "missing INFRA_ID")
+			},
+		},
+		{
+			name:  "all jobs succeeded",
+			stage: postInstallHookStage,
+			existing: []runtime.Object{
+				testInstallHookJob(postInstallHookStage, "first", batchv1.JobComplete),
+				testInstallHookJob(postInstallHookStage, "second", batchv1.JobComplete),
+			},
+			expectCondition: corev1.ConditionFalse,
+			expectReason:    installHooksSucceededReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cd := testClusterDeploymentWithInitializedConditions(testClusterDeployment())
+			if !test.noHooks {
+				hooks := append([]hivev1.InstallHook{}, hooks...)
+				for i := range hooks {
+					hooks[i].SecretRefs = test.secretRefs
+				}
+				cd.Spec.Provisioning.PreInstallHooks = hooks
+				cd.Spec.Provisioning.PostInstallHooks = hooks
+			}
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(append(test.existing, cd)...).Build()
+			rcd := &ReconcileClusterDeployment{
+				Client: fakeClient,
+				scheme: scheme.GetScheme(),
+				logger: log.WithField("controller", "clusterDeployment"),
+			}
+			result, err := rcd.runInstallHooks(cd, test.stage, rcd.logger)
+			require.NoError(t, err, "unexpected error")
+			if test.expectResult {
+				if assert.NotNil(t, result, "expected a result") && test.expectRequeue {
+					assert.Equal(t, defaultRequeueTime, result.RequeueAfter, "unexpected requeue")
+				}
+			} else {
+				assert.Nil(t, result, "expected reconcile to proceed")
+			}
+
+			cd = getCDFromClient(fakeClient)
+			cond := controllerutils.FindCondition(cd.Status.Conditions, test.stage.condition)
+			if test.expectCondition == "" {
+				assert.Nil(t, cond, "unexpected %s condition", test.stage.condition)
+			} else if assert.NotNil(t, cond, "missing %s condition", test.stage.condition) {
+				assert.Equal(t, test.expectCondition, cond.Status, "unexpected condition status")
+				assert.Equal(t, test.expectReason, cond.Reason, "unexpected condition reason")
+			}
+
+			if test.expectJob != "" {
+				job := &batchv1.Job{}
+				err := fakeClient.Get(context.TODO(), client.ObjectKey{
+					Namespace: testNamespace,
+					Name:      installHookJobName(cd, test.stage, hivev1.InstallHook{Name: test.expectJob}),
+				}, job)
+				require.NoError(t, err, "expected hook job to be created")
+				assert.Equal(t, test.expectJob, job.Labels[constants.InstallHookNameLabel], "unexpected hook name label")
+				assert.Equal(t, testName, job.Labels[constants.ClusterDeploymentNameLabel], "unexpected cluster deployment label")
+				if test.validateJob != nil {
+					test.validateJob(t, job)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateInstallHookPodSpec(t *testing.T) {
+	hook := hivev1.InstallHook{
+		Name:       "hook",
+		SecretRefs: []corev1.LocalObjectReference{{Name: "hook-creds"}},
+	}
+	secretVolume := func(name string) corev1.VolumeSource {
+		return corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: name}}
+	}
+	projectedVolume := func(name string) corev1.VolumeSource {
+		return corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+			{ConfigMap: &corev1.ConfigMapProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "config"}}},
+			{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: name}}},
+		}}}
+	}
+	secretKeyRef := func(name string) []corev1.EnvVar {
+		return []corev1.EnvVar{{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  "token",
+		}}}}
+	}
+	envFrom := func(name string) []corev1.EnvFromSource {
+		return []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}}}
+	}
+	tests := []struct {
+		name      string
+		podSpec   corev1.PodSpec
+		expectErr bool
+	}{
+		{
+			name: "allowed volumes",
+			podSpec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "secret", VolumeSource: secretVolume("hook-creds")},
+				{Name: "projected", VolumeSource: projectedVolume("hook-creds")},
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+				{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			}},
+		},
+		{
+			name:      "secret volume not listed",
+			podSpec:   corev1.PodSpec{Volumes: []corev1.Volume{{Name: "secret", VolumeSource: secretVolume("admin-kubeconfig")}}},
+			expectErr: true,
+		},
+		{
+			name:      "projected secret not listed",
+			podSpec:   corev1.PodSpec{Volumes: []corev1.Volume{{Name: "projected", VolumeSource: projectedVolume("admin-kubeconfig")}}},
+			expectErr: true,
+		},
+		{
+			name: "hostPath volume",
+			podSpec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}},
+			}},
+			expectErr: true,
+		},
+		{
+			name: "csi volume",
+			podSpec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "csi", VolumeSource: corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{Driver: "secrets-store.csi.k8s.io"}}},
+			}},
+			expectErr: true,
+		},
+		{
+			name: "allowed environment",
+			podSpec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "hook", Env: secretKeyRef("hook-creds"), EnvFrom: envFrom("hook-creds")},
+			}},
+		},
+		{
+			name:      "secretKeyRef not listed",
+			podSpec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "hook", Env: secretKeyRef("admin-password")}}},
+			expectErr: true,
+		},
+		{
+			name:      "envFrom secret not listed in init container",
+			podSpec:   corev1.PodSpec{InitContainers: []corev1.Container{{Name: "init", EnvFrom: envFrom("admin-password")}}},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateInstallHookPodSpec(&test.podSpec, hook)
+			if test.expectErr {
+				var tmplErr *installHookTemplateError
+				assert.ErrorAs(t, err, &tmplErr, "expected template error")
+			} else {
+				assert.NoError(t, err, "unexpected error")
+			}
+		})
+	}
+}
@@ -42,6 +42,14 @@ var (
 		},
 	)
 
+	metricPreflightChecksFailed = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hive_cluster_deployment_preflight_checks_failed_total",
+			Help: "Counter incremented every time pre-flight checks prevent a provision from starting.",
+		},
+		[]string{"platform", "reason"},
+	)
+
 	// Declare the metrics which allow optional labels to be added.
 	// They are defined later once the hive config has been read.
 	metricCompletedInstallJobRestarts hivemetrics.HistogramVecWithDynamicLabels
@@ -118,6 +126,7 @@ func registerMetrics(mConfig *metricsconfig.MetricsConfig, log log.FieldLogger)
 	metrics.Registry.MustRegister(metricInstallDelaySeconds)
 	metrics.Registry.MustRegister(metricImageSetDelaySeconds)
 	metrics.Registry.MustRegister(metricDNSDelaySeconds)
+	metrics.Registry.MustRegister(metricPreflightChecksFailed)
 
 	metricProvisionFailedTerminal.Register()
 	metricCompletedInstallJobRestarts.Register()
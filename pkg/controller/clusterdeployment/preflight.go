@@ -0,0 +1,709 @@
+package clusterdeployment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+	installeraws "github.com/openshift/installer/pkg/asset/installconfig/aws"
+	installergcp "github.com/openshift/installer/pkg/asset/installconfig/gcp"
+	"github.com/openshift/installer/pkg/quota"
+	installertypes "github.com/openshift/installer/pkg/types"
+	installerawsdefaults "github.com/openshift/installer/pkg/types/aws/defaults"
+	installerazuredefaults "github.com/openshift/installer/pkg/types/azure/defaults"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+const (
+	preflightInsufficientQuotaReason       = "InsufficientQuota"
+	preflightInsufficientPermissionsReason = "InsufficientPermissions"
+	preflightChecksPassedReason            = "PreflightChecksPassed"
+	preflightChecksInconclusiveReason      = "PreflightChecksInconclusive"
+
+	// preflightRecheckInterval is how long we wait before re-running failed pre-flight checks.
+	preflightRecheckInterval = 10 * time.Minute
+
+	// defaultControlPlaneReplicas and defaultComputeReplicas mirror the installer's defaults for machine pools
+	// which do not specify replicas.
+	defaultControlPlaneReplicas = 3
+	defaultComputeReplicas      = 3
+
+	awsVPCMaxElasticIPsAttribute = "vpc-max-elastic-ips"
+	awsServiceQuotasEC2Service   = "ec2"
+	awsServiceQuotasVPCService   = "vpc"
+	// awsOnDemandStandardVCPUsQuota is the Service Quotas code of the running on-demand standard instances vCPU quota.
+	awsOnDemandStandardVCPUsQuota = "L-1216C47A"
+	// awsVPCsQuota is the Service Quotas code of the VPCs per region quota.
+	awsVPCsQuota = "L-F678F1CE"
+	// awsStandardInstanceFamilies are the first letters of the instance families counted by the standard instances
+	// vCPU quota.
+	awsStandardInstanceFamilies = "acdhimrtz"
+	gcpCPUsQuota                = "CPUS"
+	gcpNetworksQuota            = "NETWORKS"
+	azureCoresQuota             = "cores"
+)
+
+// awsNonStandardInstanceFamilyPrefixes are the instance families starting like standard families that have their own
+// vCPU quotas.
+var awsNonStandardInstanceFamilyPrefixes = []string{"dl", "hpc", "inf", "trn"}
+
+// gcpRequiredPermissions is a representative set of the permissions the installer needs in the GCP project.
+// It is not exhaustive, but catches credentials lacking the roles called out in the installer docs.
+var gcpRequiredPermissions = []string{
+	"compute.addresses.create",
+	"compute.firewalls.create",
+	"compute.forwardingRules.create",
+	"compute.instances.create",
+	"compute.networks.create",
+	"compute.subnetworks.create",
+	"dns.changes.create",
+	"dns.managedZones.create",
+	"iam.serviceAccounts.create",
+	"resourcemanager.projects.setIamPolicy",
+	"storage.buckets.create",
+}
+
+// preflightFailure is returned by pre-flight checks when the cloud account cannot support the install.
+// Any other error means the checks themselves could not be completed.
+type preflightFailure struct {
+	reason  string
+	message string
+}
+
+func (f *preflightFailure) Error() string {
+	return f.message
+}
+
+// checkPreflight runs the pre-flight checks for the ClusterDeployment and records the result in the
+// PreflightChecksFailed condition. A nil result means provisioning may proceed; otherwise the caller should
+// return it without creating a ClusterProvision.
+func (r *ReconcileClusterDeployment) checkPreflight(cd *hivev1.ClusterDeployment, logger log.FieldLogger) (*reconcile.Result, error) {
+	if r.preflightCheck == nil {
+		return nil, nil
+	}
+	if skip, _ := strconv.ParseBool(cd.Annotations[constants.SkipPreflightChecksAnnotation]); skip {
+		logger.Debug("skipping pre-flight checks per annotation")
+		return nil, nil
+	}
+	ic := r.getInstallConfig(cd, logger)
+	if ic == nil {
+		// getInstallConfig logged
+		return nil, nil
+	}
+
+	status, reason, message := corev1.ConditionFalse, preflightChecksPassedReason, "Pre-flight checks passed"
+	var pf *preflightFailure
+	switch err := r.preflightCheck(r.Client, cd, ic, logger); {
+	case errors.As(err, &pf):
+		logger.WithField("reason", pf.reason).WithError(err).Info("pre-flight checks failed; not creating a new provision")
+		status, reason, message = corev1.ConditionTrue, pf.reason, pf.message
+		metricPreflightChecksFailed.WithLabelValues(getClusterPlatform(cd), pf.reason).Inc()
+	case err != nil:
+		// Don't hold up the install because we couldn't, e.g., read a quota. The installer will fail on its own
+		// if there is really a problem.
+		logger.WithError(err).Warn("could not complete pre-flight checks; proceeding with provision")
+		reason, message = preflightChecksInconclusiveReason, fmt.Sprintf("Pre-flight checks could not be completed: %v", err)
+	}
+
+	conditions, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.PreflightChecksFailedCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if changed {
+		cd.Status.Conditions = conditions
+		if err := r.Status().Update(context.TODO(), cd); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster deployment status")
+			return &reconcile.Result{}, err
+		}
+	}
+	if status == corev1.ConditionTrue {
+		return &reconcile.Result{RequeueAfter: preflightRecheckInterval}, nil
+	}
+	return nil, nil
+}
+
+// runPreflightChecks checks that the cloud account behind the ClusterDeployment's credentials has the quota and
+// permissions needed to install the cluster described by the install-config. Platforms without pre-flight checks
+// always pass.
+func runPreflightChecks(c client.Client, cd *hivev1.ClusterDeployment, ic *installertypes.InstallConfig, logger log.FieldLogger) error {
+	switch {
+	case cd.Spec.Platform.AWS != nil && ic.AWS != nil:
+		return runAWSPreflightChecks(c, cd, ic, logger)
+	case cd.Spec.Platform.GCP != nil && ic.GCP != nil:
+		secret, err := getPlatformCredentialsSecret(c, cd.Namespace, cd.Spec.Platform.GCP.CredentialsSecretRef.Name)
+		if err != nil {
+			return err
+		}
+		gcpClient, err := gcpclient.NewClientFromSecret(secret)
+		if err != nil {
+			return errors.Wrap(err, "failed to create GCP client")
+		}
+		if err := checkGCPPermissions(gcpClient); err != nil {
+			return err
+		}
+		return checkGCPQuotas(gcpClient, ic, logger)
+	case cd.Spec.Platform.Azure != nil && ic.Azure != nil:
+		secret, err := getPlatformCredentialsSecret(c, cd.Namespace, cd.Spec.Platform.Azure.CredentialsSecretRef.Name)
+		if err != nil {
+			return err
+		}
+		azureClient, err := azureclient.NewClientFromSecret(secret, cd.Spec.Platform.Azure.CloudName.Name())
+		if err != nil {
+			return errors.Wrap(err, "failed to create Azure client")
+		}
+		// We have no Azure authorization client to check role assignments with, so only quotas are checked.
+		return checkAzureQuotas(azureClient, ic, logger)
+	}
+	return nil
+}
+
+func getPlatformCredentialsSecret(c client.Client, namespace, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch platform credentials secret")
+	}
+	return secret, nil
+}
+
+func runAWSPreflightChecks(c client.Client, cd *hivev1.ClusterDeployment, ic *installertypes.InstallConfig, logger log.FieldLogger) error {
+	region := cd.Spec.Platform.AWS.Region
+	// Permissions can only be simulated for static credentials; assumed roles are skipped.
+	if secretName := cd.Spec.Platform.AWS.CredentialsSecretRef.Name; secretName != "" {
+		secret, err := getPlatformCredentialsSecret(c, cd.Namespace, secretName)
+		if err != nil {
+			return err
+		}
+		ssn, err := awsclient.NewSessionFromSecret(secret, region)
+		if err != nil {
+			return errors.Wrap(err, "failed to create AWS session")
+		}
+		if err := validateAWSPermissions(ssn, ic); err != nil {
+			return &preflightFailure{
+				reason:  preflightInsufficientPermissionsReason,
+				message: fmt.Sprintf("AWS credentials cannot be used to install the cluster: %v", err),
+			}
+		}
+	}
+
+	awsClient, err := awsclient.New(c, awsclient.Options{
+		Region: region,
+		CredentialsSource: awsclient.CredentialsSource{
+			Secret: &awsclient.SecretCredentialsSource{
+				Namespace: cd.Namespace,
+				Ref:       &cd.Spec.Platform.AWS.CredentialsSecretRef,
+			},
+			AssumeRole: &awsclient.AssumeRoleCredentialsSource{
+				SecretRef: corev1.SecretReference{
+					Name:      controllerutils.AWSServiceProviderSecretName(""),
+					Namespace: controllerutils.GetHiveNamespace(),
+				},
+				Role: cd.Spec.Platform.AWS.CredentialsAssumeRole,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create AWS client")
+	}
+	return checkAWSQuotas(awsClient, ic, logger)
+}
+
+// validateAWSPermissions simulates the IAM policies of the session's principal against the actions the
+// installer needs for the install-config. Overridden in tests.
+var validateAWSPermissions = func(ssn *session.Session, ic *installertypes.InstallConfig) error {
+	return installeraws.ValidateCreds(ssn, installeraws.RequiredPermissionGroups(ic), ic.AWS.Region)
+}
+
+// checkAWSQuotas checks the Service Quotas for running on-demand standard instance vCPUs and, when the installer is
+// creating the VPC, for VPCs, along with the Elastic IPs for the NAT gateways the installer creates, one per
+// availability zone.
+func checkAWSQuotas(awsClient awsclient.Client, ic *installertypes.InstallConfig, logger log.FieldLogger) error {
+	region := ic.AWS.Region
+	var quotas []quota.Quota
+	var constraints []quota.Constraint
+
+	vcpuQuotas, vcpuConstraints, err := awsVCPUQuotas(awsClient, ic, logger)
+	if err != nil {
+		return err
+	}
+	quotas = append(quotas, vcpuQuotas...)
+	constraints = append(constraints, vcpuConstraints...)
+
+	if len(ic.AWS.Subnets) > 0 {
+		logger.Debug("install-config uses existing subnets; skipping VPC and Elastic IP quota checks")
+		return checkQuotas(quotas, constraints)
+	}
+
+	vpcLimit, err := getAWSServiceQuota(awsClient, awsServiceQuotasVPCService, awsVPCsQuota, logger)
+	if err != nil {
+		return err
+	}
+	if vpcLimit >= 0 {
+		vpcs, err := awsClient.DescribeVpcs(&ec2.DescribeVpcsInput{})
+		if err != nil {
+			return errors.Wrap(err, "failed to describe VPCs")
+		}
+		quotas = append(quotas, quota.Quota{
+			Service: awsServiceQuotasVPCService,
+			Name:    awsVPCsQuota,
+			Region:  region,
+			InUse:   int64(len(vpcs.Vpcs)),
+			Limit:   vpcLimit,
+		})
+		constraints = append(constraints, quota.Constraint{Name: awsVPCsQuota, Region: region, Count: 1})
+	}
+
+	zones := sets.NewString()
+	pools := []*installertypes.MachinePool{ic.ControlPlane}
+	for i := range ic.Compute {
+		pools = append(pools, &ic.Compute[i])
+	}
+	for _, pool := range pools {
+		if pool != nil && pool.Platform.AWS != nil {
+			zones.Insert(pool.Platform.AWS.Zones...)
+		}
+	}
+	if ic.AWS.DefaultMachinePlatform != nil {
+		zones.Insert(ic.AWS.DefaultMachinePlatform.Zones...)
+	}
+	zoneCount := int64(zones.Len())
+	if zoneCount == 0 {
+		out, err := awsClient.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+			Filters: []*ec2.Filter{
+				{Name: aws.String("region-name"), Values: []*string{aws.String(region)}},
+				{Name: aws.String("state"), Values: []*string{aws.String("available")}},
+				{Name: aws.String("zone-type"), Values: []*string{aws.String("availability-zone")}},
+			},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to describe availability zones")
+		}
+		zoneCount = int64(len(out.AvailabilityZones))
+	}
+
+	attrs, err := awsClient.DescribeAccountAttributes(&ec2.DescribeAccountAttributesInput{
+		AttributeNames: []*string{aws.String(awsVPCMaxElasticIPsAttribute)},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to describe account attributes")
+	}
+	var limit int64 = -1
+	for _, attr := range attrs.AccountAttributes {
+		if aws.StringValue(attr.AttributeName) != awsVPCMaxElasticIPsAttribute || len(attr.AttributeValues) == 0 {
+			continue
+		}
+		if limit, err = strconv.ParseInt(aws.StringValue(attr.AttributeValues[0].AttributeValue), 10, 64); err != nil {
+			return errors.Wrapf(err, "could not parse %s", awsVPCMaxElasticIPsAttribute)
+		}
+	}
+	if limit < 0 {
+		return errors.Errorf("account attribute %s not found", awsVPCMaxElasticIPsAttribute)
+	}
+	addresses, err := awsClient.DescribeAddresses(&ec2.DescribeAddressesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("domain"), Values: []*string{aws.String("vpc")}}},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to describe addresses")
+	}
+	quotas = append(quotas, quota.Quota{
+		Service: "ec2",
+		Name:    awsVPCMaxElasticIPsAttribute,
+		Region:  region,
+		InUse:   int64(len(addresses.Addresses)),
+		Limit:   limit,
+	})
+	constraints = append(constraints, quota.Constraint{Name: awsVPCMaxElasticIPsAttribute, Region: region, Count: zoneCount})
+
+	return checkQuotas(quotas, constraints)
+}
+
+// awsVCPUQuotas returns the running on-demand standard instance vCPU quota, with the vCPUs of the running on-demand
+// standard instances of the region in use, and the vCPUs the install needs from it. Instances of other families, such
+// as GPU instances, have their own quotas, which are not checked.
+func awsVCPUQuotas(awsClient awsclient.Client, ic *installertypes.InstallConfig, logger log.FieldLogger) ([]quota.Quota, []quota.Constraint, error) {
+	region := ic.AWS.Region
+	instanceType := func(pool *installertypes.MachinePool) string {
+		if pool != nil && pool.Platform.AWS != nil && pool.Platform.AWS.InstanceType != "" {
+			return pool.Platform.AWS.InstanceType
+		}
+		if ic.AWS.DefaultMachinePlatform != nil && ic.AWS.DefaultMachinePlatform.InstanceType != "" {
+			return ic.AWS.DefaultMachinePlatform.InstanceType
+		}
+		var arch installertypes.Architecture
+		if pool != nil {
+			arch = pool.Architecture
+		}
+		topology := configv1.HighlyAvailableTopologyMode
+		if pool == ic.ControlPlane && pool != nil && pool.Replicas != nil && *pool.Replicas == 1 {
+			topology = configv1.SingleReplicaTopologyMode
+		}
+		return installerawsdefaults.InstanceTypes(region, arch, topology)[0]
+	}
+
+	reqs := installMachineRequirements(ic, instanceType)
+	instanceTypes := sets.NewString()
+	for _, req := range reqs {
+		if isAWSStandardInstanceType(req.instanceType) {
+			instanceTypes.Insert(req.instanceType)
+		}
+	}
+	if instanceTypes.Len() == 0 {
+		return nil, nil, nil
+	}
+	out, err := awsClient.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice(instanceTypes.List()),
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to describe instance types")
+	}
+	vcpus := map[string]int64{}
+	for _, it := range out.InstanceTypes {
+		if it.VCpuInfo != nil {
+			vcpus[aws.StringValue(it.InstanceType)] = aws.Int64Value(it.VCpuInfo.DefaultVCpus)
+		}
+	}
+	var required int64
+	for _, req := range reqs {
+		if !instanceTypes.Has(req.instanceType) {
+			continue
+		}
+		count, ok := vcpus[req.instanceType]
+		if !ok {
+			return nil, nil, errors.Errorf("instance type %s not found in region %s", req.instanceType, region)
+		}
+		required += req.count * count
+	}
+
+	limit, err := getAWSServiceQuota(awsClient, awsServiceQuotasEC2Service, awsOnDemandStandardVCPUsQuota, logger)
+	if err != nil || limit < 0 {
+		return nil, nil, err
+	}
+	var inUse int64
+	err = awsClient.DescribeInstancesPages(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{"pending", "running"})}},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				// Spot instances count towards the spot instance requests quota.
+				if aws.StringValue(instance.InstanceLifecycle) == ec2.InstanceLifecycleTypeSpot ||
+					!isAWSStandardInstanceType(aws.StringValue(instance.InstanceType)) || instance.CpuOptions == nil {
+					continue
+				}
+				inUse += aws.Int64Value(instance.CpuOptions.CoreCount) * aws.Int64Value(instance.CpuOptions.ThreadsPerCore)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to describe instances")
+	}
+	return []quota.Quota{{
+			Service: awsServiceQuotasEC2Service,
+			Name:    awsOnDemandStandardVCPUsQuota,
+			Region:  region,
+			InUse:   inUse,
+			Limit:   limit,
+		}},
+		[]quota.Constraint{{Name: awsOnDemandStandardVCPUsQuota, Region: region, Count: required}},
+		nil
+}
+
+// getAWSServiceQuota returns the value of a Service Quota, falling back on its AWS default when it has not been
+// applied to the account. It returns -1 when the credentials may not read Service Quotas, so that the quota is not
+// checked.
+func getAWSServiceQuota(awsClient awsclient.Client, serviceCode, quotaCode string, logger log.FieldLogger) (int64, error) {
+	out, err := awsClient.GetServiceQuota(&servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(serviceCode),
+		QuotaCode:   aws.String(quotaCode),
+	})
+	var q *servicequotas.ServiceQuota
+	if err == nil {
+		q = out.Quota
+	} else if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == servicequotas.ErrCodeNoSuchResourceException {
+		defaultOut, defaultErr := awsClient.GetAWSDefaultServiceQuota(&servicequotas.GetAWSDefaultServiceQuotaInput{
+			ServiceCode: aws.String(serviceCode),
+			QuotaCode:   aws.String(quotaCode),
+		})
+		if defaultErr == nil {
+			q = defaultOut.Quota
+		}
+		err = defaultErr
+	}
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == servicequotas.ErrCodeAccessDeniedException {
+		logger.WithField("quota", quotaCode).Warn("credentials may not get service quotas; skipping quota check")
+		return -1, nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get service quota %s", quotaCode)
+	}
+	if q == nil || q.Value == nil {
+		return 0, errors.Errorf("service quota %s has no value", quotaCode)
+	}
+	return int64(*q.Value), nil
+}
+
+// isAWSStandardInstanceType returns true for the instance types counted by the running on-demand standard
+// (A, C, D, H, I, M, R, T, Z) instances quota.
+func isAWSStandardInstanceType(instanceType string) bool {
+	family := strings.SplitN(instanceType, ".", 2)[0]
+	for _, prefix := range awsNonStandardInstanceFamilyPrefixes {
+		if strings.HasPrefix(family, prefix) {
+			return false
+		}
+	}
+	return family != "" && strings.ContainsRune(awsStandardInstanceFamilies, rune(family[0]))
+}
+
+func checkGCPPermissions(gcpClient gcpclient.Client) error {
+	granted, err := gcpClient.TestIamPermissions(gcpRequiredPermissions)
+	if err != nil {
+		return errors.Wrap(err, "failed to test IAM permissions")
+	}
+	if missing := sets.NewString(gcpRequiredPermissions...).Difference(sets.NewString(granted...)); missing.Len() > 0 {
+		return &preflightFailure{
+			reason: preflightInsufficientPermissionsReason,
+			message: fmt.Sprintf("GCP credentials are missing permissions in project %s: %s",
+				gcpClient.GetProjectName(), strings.Join(missing.List(), ", ")),
+		}
+	}
+	return nil
+}
+
+// checkGCPQuotas checks the regional CPU quotas, including the machine family's own CPU quota where the region
+// has one, and the project's network quota when the installer is creating the VPC.
+func checkGCPQuotas(gcpClient gcpclient.Client, ic *installertypes.InstallConfig, logger log.FieldLogger) error {
+	region := ic.GCP.Region
+	var defaultPool *installertypes.MachinePool
+	if ic.GCP.DefaultMachinePlatform != nil {
+		defaultPool = &installertypes.MachinePool{}
+		defaultPool.Platform.GCP = ic.GCP.DefaultMachinePlatform
+	}
+	instanceType := func(pool *installertypes.MachinePool) string {
+		for _, p := range []*installertypes.MachinePool{pool, defaultPool} {
+			if p != nil && p.Platform.GCP != nil && p.Platform.GCP.InstanceType != "" {
+				return p.Platform.GCP.InstanceType
+			}
+		}
+		var arch installertypes.Architecture
+		if pool != nil {
+			arch = pool.Architecture
+		}
+		return installergcp.DefaultInstanceTypeForArch(arch)
+	}
+
+	zones, err := gcpClient.ListComputeZones(gcpclient.ListComputeZonesOptions{
+		Filter: fmt.Sprintf("region eq .*%s", region),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list compute zones")
+	}
+	if len(zones.Items) == 0 {
+		return errors.Errorf("no compute zones found in region %s", region)
+	}
+	zone := zones.Items[0].Name
+
+	cpus := map[string]int64{}
+	for _, req := range installMachineRequirements(ic, instanceType) {
+		if _, ok := cpus[req.instanceType]; !ok {
+			mt, err := gcpClient.GetMachineType(req.instanceType, zone)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get machine type %s", req.instanceType)
+			}
+			cpus[req.instanceType] = mt.GuestCpus
+		}
+	}
+
+	regionObj, err := gcpClient.GetRegion(region)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get region %s", region)
+	}
+	var quotas []quota.Quota
+	for _, q := range regionObj.Quotas {
+		quotas = append(quotas, quota.Quota{Service: "compute", Name: q.Metric, Region: region, InUse: int64(q.Usage), Limit: int64(q.Limit)})
+	}
+
+	familyCPUs := map[string]int64{}
+	var totalCPUs int64
+	for _, req := range installMachineRequirements(ic, instanceType) {
+		count := req.count * cpus[req.instanceType]
+		totalCPUs += count
+		familyCPUs[strings.ToUpper(strings.SplitN(req.instanceType, "-", 2)[0])+"_"+gcpCPUsQuota] += count
+	}
+	constraints := []quota.Constraint{{Name: gcpCPUsQuota, Region: region, Count: totalCPUs}}
+	for name, count := range familyCPUs {
+		for _, q := range quotas {
+			if q.Name == name {
+				constraints = append(constraints, quota.Constraint{Name: name, Region: region, Count: count})
+				break
+			}
+		}
+	}
+
+	if ic.GCP.Network == "" {
+		project, err := gcpClient.GetProject()
+		if err != nil {
+			return errors.Wrap(err, "failed to get project")
+		}
+		for _, q := range project.Quotas {
+			if q.Metric == gcpNetworksQuota {
+				quotas = append(quotas, quota.Quota{Service: "compute", Name: q.Metric, Region: "global", InUse: int64(q.Usage), Limit: int64(q.Limit)})
+				constraints = append(constraints, quota.Constraint{Name: gcpNetworksQuota, Region: "global", Count: 1})
+			}
+		}
+	} else {
+		logger.Debug("install-config uses an existing network; skipping network quota check")
+	}
+
+	return checkQuotas(quotas, constraints)
+}
+
+// checkAzureQuotas checks the regional vCPU quota and the vCPU quota of each VM family used by the install.
+func checkAzureQuotas(azureClient azureclient.Client, ic *installertypes.InstallConfig, logger log.FieldLogger) error {
+	ctx := context.TODO()
+	region := ic.Azure.Region
+	var defaultPool *installertypes.MachinePool
+	if ic.Azure.DefaultMachinePlatform != nil {
+		defaultPool = &installertypes.MachinePool{}
+		defaultPool.Platform.Azure = ic.Azure.DefaultMachinePlatform
+	}
+	instanceType := func(pool *installertypes.MachinePool) string {
+		for _, p := range []*installertypes.MachinePool{pool, defaultPool} {
+			if p != nil && p.Platform.Azure != nil && p.Platform.Azure.InstanceType != "" {
+				return p.Platform.Azure.InstanceType
+			}
+		}
+		var arch installertypes.Architecture
+		if pool != nil {
+			arch = pool.Architecture
+		}
+		if pool == ic.ControlPlane {
+			return installerazuredefaults.ControlPlaneInstanceType(ic.Azure.CloudName, region, arch)
+		}
+		return installerazuredefaults.ComputeInstanceType(ic.Azure.CloudName, region, arch)
+	}
+
+	familyCores := map[string]int64{}
+	var totalCores int64
+	for _, req := range installMachineRequirements(ic, instanceType) {
+		sku, err := azureClient.GetVirtualMachineSku(ctx, req.instanceType, region)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get SKU for %s", req.instanceType)
+		}
+		if sku == nil {
+			return errors.Errorf("instance type %s not found in region %s", req.instanceType, region)
+		}
+		var vcpus int64
+		if sku.Capabilities != nil {
+			for _, capability := range *sku.Capabilities {
+				if capability.Name != nil && *capability.Name == "vCPUs" && capability.Value != nil {
+					vcpus, _ = strconv.ParseInt(*capability.Value, 10, 64)
+				}
+			}
+		}
+		totalCores += req.count * vcpus
+		if sku.Family != nil {
+			familyCores[*sku.Family] += req.count * vcpus
+		}
+	}
+
+	var quotas []quota.Quota
+	page, err := azureClient.ListUsage(ctx, region)
+	for ; err == nil && page.NotDone(); err = page.NextWithContext(ctx) {
+		for _, usage := range page.Values() {
+			if usage.Name == nil || usage.Name.Value == nil || usage.CurrentValue == nil || usage.Limit == nil {
+				continue
+			}
+			quotas = append(quotas, quota.Quota{
+				Service: "compute",
+				Name:    *usage.Name.Value,
+				Region:  region,
+				InUse:   int64(*usage.CurrentValue),
+				Limit:   *usage.Limit,
+			})
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to list compute usage")
+	}
+
+	constraints := []quota.Constraint{{Name: azureCoresQuota, Region: region, Count: totalCores}}
+	for family, count := range familyCores {
+		constraints = append(constraints, quota.Constraint{Name: family, Region: region, Count: count})
+	}
+	return checkQuotas(quotas, constraints)
+}
+
+// machineRequirement is a number of machines of a single instance type that the installer will create.
+type machineRequirement struct {
+	instanceType string
+	count        int64
+}
+
+// installMachineRequirements returns the machines the installer will create for the install-config: the bootstrap
+// and control plane machines, plus the initial compute machines. The instanceType func resolves a machine pool to
+// its platform's instance type, applying defaults.
+func installMachineRequirements(ic *installertypes.InstallConfig, instanceType func(*installertypes.MachinePool) string) []machineRequirement {
+	controlPlane := int64(defaultControlPlaneReplicas)
+	if ic.ControlPlane != nil && ic.ControlPlane.Replicas != nil {
+		controlPlane = *ic.ControlPlane.Replicas
+	}
+	// The bootstrap machine uses the control plane instance type.
+	reqs := []machineRequirement{{instanceType: instanceType(ic.ControlPlane), count: controlPlane + 1}}
+	for i := range ic.Compute {
+		pool := &ic.Compute[i]
+		replicas := int64(defaultComputeReplicas)
+		if pool.Replicas != nil {
+			replicas = *pool.Replicas
+		}
+		if replicas > 0 {
+			reqs = append(reqs, machineRequirement{instanceType: instanceType(pool), count: replicas})
+		}
+	}
+	return reqs
+}
+
+// checkQuotas evaluates the constraints against the quotas. Constraints without a matching quota are ignored, as
+// we cannot say whether they will be met.
+func checkQuotas(quotas []quota.Quota, constraints []quota.Constraint) error {
+	reports, _ := quota.Check(quotas, constraints)
+	var failures []string
+	for _, report := range reports {
+		if report.Result == quota.NotAvailable {
+			failures = append(failures, fmt.Sprintf("%s in %s: %s", report.For.Name, report.For.Region, report.Message))
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Strings(failures)
+	return &preflightFailure{
+		reason:  preflightInsufficientQuotaReason,
+		message: "Insufficient quota to install the cluster: " + strings.Join(failures, "; "),
+	}
+}
@@ -0,0 +1,371 @@
+package clusterdeployment
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/servicequotas"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	compute "google.golang.org/api/compute/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	installertypes "github.com/openshift/installer/pkg/types"
+	installertypesaws "github.com/openshift/installer/pkg/types/aws"
+	installertypesgcp "github.com/openshift/installer/pkg/types/gcp"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	mockgcp "github.com/openshift/hive/pkg/gcpclient/mock"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+func TestCheckPreflight(t *testing.T) {
+	tests := []struct {
+		name            string
+		cd              *hivev1.ClusterDeployment
+		preflightCheck  func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error
+		expectResult    bool
+		expectRequeue   bool
+		expectCondition corev1.ConditionStatus
+		expectReason    string
+	}{
+		{
+			name:            "disabled",
+			cd:              testClusterDeploymentWithInitializedConditions(testClusterDeployment()),
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    hivev1.InitializedConditionReason,
+		},
+		{
+			name: "skipped by annotation",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeploymentWithInitializedConditions(testClusterDeployment())
+				cd.Annotations = map[string]string{constants.SkipPreflightChecksAnnotation: "true"}
+				return cd
+			}(),
+			preflightCheck: func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error {
+				return &preflightFailure{reason: preflightInsufficientQuotaReason, message: "no quota"}
+			},
+			expectCondition: corev1.ConditionUnknown,
+			expectReason:    hivev1.InitializedConditionReason,
+		},
+		{
+			name: "passed",
+			cd:   testClusterDeploymentWithInitializedConditions(testClusterDeployment()),
+			preflightCheck: func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error {
+				return nil
+			},
+			expectCondition: corev1.ConditionFalse,
+			expectReason:    preflightChecksPassedReason,
+		},
+		{
+			name: "insufficient quota",
+			cd:   testClusterDeploymentWithInitializedConditions(testClusterDeployment()),
+			preflightCheck: func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error {
+				return &preflightFailure{reason: preflightInsufficientQuotaReason, message: "no quota"}
+			},
+			expectResult:    true,
+			expectRequeue:   true,
+			expectCondition: corev1.ConditionTrue,
+			expectReason:    preflightInsufficientQuotaReason,
+		},
+		{
+			name: "inconclusive",
+			cd:   testClusterDeploymentWithInitializedConditions(testClusterDeployment()),
+			preflightCheck: func(client.Client, *hivev1.ClusterDeployment, *installertypes.InstallConfig, log.FieldLogger) error {
+				return errors.New("throttled")
+			},
+			expectCondition: corev1.ConditionFalse,
+			expectReason:    preflightChecksInconclusiveReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(test.cd, testInstallConfigSecretAWS()).Build()
+			rcd := &ReconcileClusterDeployment{
+				Client:         fakeClient,
+				scheme:         scheme.GetScheme(),
+				logger:         log.WithField("controller", "clusterDeployment"),
+				preflightCheck: test.preflightCheck,
+			}
+			result, err := rcd.checkPreflight(test.cd, rcd.logger)
+			require.NoError(t, err, "unexpected error")
+			if test.expectResult {
+				if assert.NotNil(t, result, "expected a result") && test.expectRequeue {
+					assert.Equal(t, preflightRecheckInterval, result.RequeueAfter, "unexpected requeue")
+				}
+			} else {
+				assert.Nil(t, result, "expected provisioning to proceed")
+			}
+			cd := getCDFromClient(fakeClient)
+			cond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.PreflightChecksFailedCondition)
+			if assert.NotNil(t, cond, "missing PreflightChecksFailed condition") {
+				assert.Equal(t, test.expectCondition, cond.Status, "unexpected condition status")
+				assert.Equal(t, test.expectReason, cond.Reason, "unexpected condition reason")
+			}
+		})
+	}
+}
+
+func TestCheckAWSQuotas(t *testing.T) {
+	accessDenied := awserr.New(servicequotas.ErrCodeAccessDeniedException, "access denied", nil)
+	tests := []struct {
+		name             string
+		subnets          []string
+		zones            []string
+		vcpuLimit        float64
+		vcpuQuotaErr     error
+		vcpuDefaultLimit float64
+		instances        []*ec2.Instance
+		vpcLimit         float64
+		vpcQuotaErr      error
+		vpcs             int
+		eipLimit         string
+		eipsInUse        int
+		expectFailure    bool
+	}{
+		{
+			name:      "enough quota",
+			zones:     []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuLimit: 64,
+			instances: []*ec2.Instance{testAWSInstance("m5.4xlarge", 8, 2)},
+			vpcLimit:  5,
+			vpcs:      4,
+			eipLimit:  "5",
+			eipsInUse: 2,
+		},
+		{
+			name:      "not enough vCPUs",
+			zones:     []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuLimit: 32,
+			// Only the on-demand standard instances count towards the quota.
+			instances: []*ec2.Instance{
+				testAWSInstance("m5.2xlarge", 4, 2),
+				testAWSInstance("g4dn.4xlarge", 8, 2),
+				func() *ec2.Instance {
+					i := testAWSInstance("m5.4xlarge", 8, 2)
+					i.InstanceLifecycle = aws.String(ec2.InstanceLifecycleTypeSpot)
+					return i
+				}(),
+			},
+			vpcLimit:      5,
+			eipLimit:      "5",
+			expectFailure: true,
+		},
+		{
+			name:             "default vCPU quota",
+			zones:            []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuQuotaErr:     awserr.New(servicequotas.ErrCodeNoSuchResourceException, "not applied", nil),
+			vcpuDefaultLimit: 5,
+			vpcLimit:         5,
+			eipLimit:         "5",
+			expectFailure:    true,
+		},
+		{
+			name:          "not enough VPCs",
+			zones:         []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuLimit:     64,
+			vpcLimit:      5,
+			vpcs:          5,
+			eipLimit:      "5",
+			expectFailure: true,
+		},
+		{
+			name:          "not enough elastic IPs",
+			zones:         []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuLimit:     64,
+			vpcLimit:      5,
+			eipLimit:      "5",
+			eipsInUse:     3,
+			expectFailure: true,
+		},
+		{
+			name:         "service quotas not readable",
+			zones:        []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			vcpuQuotaErr: accessDenied,
+			vpcQuotaErr:  accessDenied,
+			eipLimit:     "5",
+		},
+		{
+			name:          "existing subnets",
+			subnets:       []string{"subnet-1"},
+			vcpuLimit:     16,
+			expectFailure: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			awsClient := mockaws.NewMockClient(mockCtrl)
+			ic := &installertypes.InstallConfig{
+				ControlPlane: &installertypes.MachinePool{
+					Replicas: pointer.Int64(3),
+					Platform: installertypes.MachinePoolPlatform{
+						AWS: &installertypesaws.MachinePool{Zones: test.zones},
+					},
+				},
+				Compute: []installertypes.MachinePool{{Replicas: pointer.Int64(3)}},
+			}
+			ic.AWS = &installertypesaws.Platform{
+				Region:                 "us-east-1",
+				Subnets:                test.subnets,
+				DefaultMachinePlatform: &installertypesaws.MachinePool{InstanceType: "m6i.xlarge"},
+			}
+
+			// 3 control plane machines, the bootstrap machine and 3 compute machines with 4 vCPUs each.
+			awsClient.EXPECT().DescribeInstanceTypes(gomock.Any()).Return(&ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []*ec2.InstanceTypeInfo{{
+					InstanceType: aws.String("m6i.xlarge"),
+					VCpuInfo:     &ec2.VCpuInfo{DefaultVCpus: aws.Int64(4)},
+				}},
+			}, nil)
+			expectAWSServiceQuota(awsClient, awsServiceQuotasEC2Service, awsOnDemandStandardVCPUsQuota, test.vcpuLimit, test.vcpuQuotaErr, test.vcpuDefaultLimit)
+			if test.vcpuQuotaErr != accessDenied {
+				awsClient.EXPECT().DescribeInstancesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+						fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: test.instances}}}, true)
+						return nil
+					})
+			}
+			if len(test.subnets) == 0 {
+				expectAWSServiceQuota(awsClient, awsServiceQuotasVPCService, awsVPCsQuota, test.vpcLimit, test.vpcQuotaErr, 0)
+				if test.vpcQuotaErr != accessDenied {
+					awsClient.EXPECT().DescribeVpcs(gomock.Any()).Return(&ec2.DescribeVpcsOutput{Vpcs: make([]*ec2.Vpc, test.vpcs)}, nil)
+				}
+				awsClient.EXPECT().DescribeAccountAttributes(gomock.Any()).Return(&ec2.DescribeAccountAttributesOutput{
+					AccountAttributes: []*ec2.AccountAttribute{{
+						AttributeName:   aws.String(awsVPCMaxElasticIPsAttribute),
+						AttributeValues: []*ec2.AccountAttributeValue{{AttributeValue: aws.String(test.eipLimit)}},
+					}},
+				}, nil)
+				addresses := make([]*ec2.Address, test.eipsInUse)
+				awsClient.EXPECT().DescribeAddresses(gomock.Any()).Return(&ec2.DescribeAddressesOutput{Addresses: addresses}, nil)
+			}
+			err := checkAWSQuotas(awsClient, ic, log.WithField("test", test.name))
+			assertPreflightResult(t, err, test.expectFailure, preflightInsufficientQuotaReason)
+		})
+	}
+}
+
+func TestIsAWSStandardInstanceType(t *testing.T) {
+	for instanceType, expected := range map[string]bool{
+		"m6i.xlarge":   true,
+		"c5d.2xlarge":  true,
+		"t3.medium":    true,
+		"g4dn.xlarge":  false,
+		"p3.2xlarge":   false,
+		"inf1.xlarge":  false,
+		"dl1.24xlarge": false,
+		"x2idn.16xl":   false,
+	} {
+		assert.Equal(t, expected, isAWSStandardInstanceType(instanceType), "unexpected result for %s", instanceType)
+	}
+}
+
+func testAWSInstance(instanceType string, cores, threadsPerCore int64) *ec2.Instance {
+	return &ec2.Instance{
+		InstanceType: aws.String(instanceType),
+		CpuOptions:   &ec2.CpuOptions{CoreCount: aws.Int64(cores), ThreadsPerCore: aws.Int64(threadsPerCore)},
+	}
+}
+
+// expectAWSServiceQuota expects the Service Quota to be read, returning the limit, or the error and the AWS default
+// limit when the error is that the quota has not been applied.
+func expectAWSServiceQuota(awsClient *mockaws.MockClient, serviceCode, quotaCode string, limit float64, err error, defaultLimit float64) {
+	input := &servicequotas.GetServiceQuotaInput{ServiceCode: aws.String(serviceCode), QuotaCode: aws.String(quotaCode)}
+	if err == nil {
+		awsClient.EXPECT().GetServiceQuota(input).Return(&servicequotas.GetServiceQuotaOutput{
+			Quota: &servicequotas.ServiceQuota{Value: aws.Float64(limit)},
+		}, nil)
+		return
+	}
+	awsClient.EXPECT().GetServiceQuota(input).Return(nil, err)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == servicequotas.ErrCodeNoSuchResourceException {
+		awsClient.EXPECT().GetAWSDefaultServiceQuota(gomock.Any()).Return(&servicequotas.GetAWSDefaultServiceQuotaOutput{
+			Quota: &servicequotas.ServiceQuota{Value: aws.Float64(defaultLimit)},
+		}, nil)
+	}
+}
+
+func TestCheckGCPQuotas(t *testing.T) {
+	tests := []struct {
+		name          string
+		cpuLimit      float64
+		familyLimit   float64
+		expectFailure bool
+	}{
+		{
+			name:        "enough CPUs",
+			cpuLimit:    100,
+			familyLimit: 100,
+		},
+		{
+			name:          "not enough regional CPUs",
+			cpuLimit:      20,
+			familyLimit:   100,
+			expectFailure: true,
+		},
+		{
+			name:          "not enough family CPUs",
+			cpuLimit:      100,
+			familyLimit:   20,
+			expectFailure: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			gcpClient := mockgcp.NewMockClient(mockCtrl)
+			ic := &installertypes.InstallConfig{
+				ControlPlane: &installertypes.MachinePool{Replicas: pointer.Int64(3)},
+				Compute:      []installertypes.MachinePool{{Replicas: pointer.Int64(3)}},
+			}
+			ic.GCP = &installertypesgcp.Platform{
+				Region:                 "us-east1",
+				Network:                "existing",
+				DefaultMachinePlatform: &installertypesgcp.MachinePool{InstanceType: "n2-standard-4"},
+			}
+			gcpClient.EXPECT().ListComputeZones(gomock.Any()).Return(&compute.ZoneList{Items: []*compute.Zone{{Name: "us-east1-b"}}}, nil)
+			gcpClient.EXPECT().GetMachineType("n2-standard-4", "us-east1-b").Return(&compute.MachineType{GuestCpus: 4}, nil)
+			gcpClient.EXPECT().GetRegion("us-east1").Return(&compute.Region{Quotas: []*compute.Quota{
+				{Metric: gcpCPUsQuota, Limit: test.cpuLimit},
+				{Metric: "N2_CPUS", Limit: test.familyLimit},
+			}}, nil)
+			// 4 control plane + bootstrap machines and 3 compute machines with 4 vCPUs each.
+			err := checkGCPQuotas(gcpClient, ic, log.WithField("test", test.name))
+			assertPreflightResult(t, err, test.expectFailure, preflightInsufficientQuotaReason)
+		})
+	}
+}
+
+func TestCheckGCPPermissions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	gcpClient := mockgcp.NewMockClient(mockCtrl)
+	gcpClient.EXPECT().TestIamPermissions(gcpRequiredPermissions).Return(gcpRequiredPermissions[1:], nil)
+	gcpClient.EXPECT().GetProjectName().Return("test-project")
+	err := checkGCPPermissions(gcpClient)
+	assertPreflightResult(t, err, true, preflightInsufficientPermissionsReason)
+	assert.Contains(t, err.Error(), gcpRequiredPermissions[0], "missing permission not reported")
+}
+
+func assertPreflightResult(t *testing.T, err error, expectFailure bool, expectReason string) {
+	if !expectFailure {
+		assert.NoError(t, err, "unexpected error")
+		return
+	}
+	var pf *preflightFailure
+	if assert.True(t, errors.As(err, &pf), "expected a pre-flight failure, got %v", err) {
+		assert.Equal(t, expectReason, pf.reason, "unexpected failure reason")
+	}
+}
@@ -14,3 +14,10 @@ type Actuator interface {
 	// TestCredentials returns nil if the credential check succeeds. Otherwise returns the error.
 	TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error
 }
+
+// ResourceLister is implemented by the actuators of the cloud providers supporting dry runs and leak checks:
+// AWS, GCP and Azure.
+type ResourceLister interface {
+	// ListResources returns the identifiers of the cloud resources of the cluster, which the uninstaller deletes.
+	ListResources(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) ([]string, error)
+}
@@ -0,0 +1,337 @@
+package clusterdeprovision
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gophercloud/utils/v2/openstack/clientconfig"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/dns/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient"
+	mockazure "github.com/openshift/hive/pkg/azureclient/mock"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/gcpclient"
+	"github.com/openshift/hive/pkg/ibmclient"
+	mockibm "github.com/openshift/hive/pkg/ibmclient/mock"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+)
+
+const testCloudsYAML = `clouds:
+  openstack:
+    auth:
+      auth_url: https://openstack.example.com:5000
+      username: user
+      password: pass
+`
+
+func TestActuatorTestCredentials(t *testing.T) {
+	testErr := fmt.Errorf("invalid credentials")
+	cases := []struct {
+		name      string
+		platform  hivev1.ClusterDeprovisionPlatform
+		existing  []runtime.Object
+		actuator  func(m *mocks, ibmClient *mockibm.MockAPI) Actuator
+		expectErr bool
+	}{
+		{
+			name:     "gcp valid",
+			platform: hivev1.ClusterDeprovisionPlatform{GCP: &hivev1.GCPClusterDeprovision{Region: "us-central1"}},
+			actuator: func(m *mocks, _ *mockibm.MockAPI) Actuator {
+				m.mockGCPClient.EXPECT().ListComputeZones(gcpclient.ListComputeZonesOptions{MaxResults: 1}).Return(nil, nil)
+				return &gcpActuator{gcpClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (gcpclient.Client, error) {
+					return m.mockGCPClient, nil
+				}}
+			},
+		},
+		{
+			name:     "gcp invalid",
+			platform: hivev1.ClusterDeprovisionPlatform{GCP: &hivev1.GCPClusterDeprovision{Region: "us-central1"}},
+			actuator: func(m *mocks, _ *mockibm.MockAPI) Actuator {
+				m.mockGCPClient.EXPECT().ListComputeZones(gomock.Any()).Return(nil, testErr)
+				return &gcpActuator{gcpClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (gcpclient.Client, error) {
+					return m.mockGCPClient, nil
+				}}
+			},
+			expectErr: true,
+		},
+		{
+			name:     "azure valid",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{}},
+			actuator: func(m *mocks, _ *mockibm.MockAPI) Actuator {
+				m.mockAzureClient.EXPECT().ListResourceSKUs(gomock.Any(), "").Return(nil, nil)
+				return &azureActuator{azureClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (azureclient.Client, error) {
+					return m.mockAzureClient, nil
+				}}
+			},
+		},
+		{
+			name:     "azure invalid",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{}},
+			actuator: func(m *mocks, _ *mockibm.MockAPI) Actuator {
+				m.mockAzureClient.EXPECT().ListResourceSKUs(gomock.Any(), "").Return(nil, testErr)
+				return &azureActuator{azureClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (azureclient.Client, error) {
+					return m.mockAzureClient, nil
+				}}
+			},
+			expectErr: true,
+		},
+		{
+			name:     "ibmcloud invalid",
+			platform: hivev1.ClusterDeprovisionPlatform{IBMCloud: &hivev1.IBMClusterDeprovision{Region: "us-south"}},
+			actuator: func(_ *mocks, ibmClient *mockibm.MockAPI) Actuator {
+				ibmClient.EXPECT().GetAuthenticatorAPIKeyDetails(gomock.Any()).Return(nil, testErr)
+				return &ibmCloudActuator{ibmCloudClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (ibmclient.API, error) {
+					return ibmClient, nil
+				}}
+			},
+			expectErr: true,
+		},
+		{
+			name: "vsphere invalid",
+			platform: hivev1.ClusterDeprovisionPlatform{VSphere: &hivev1.VSphereClusterDeprovision{
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "vsphere-creds"},
+				VCenter:              "vcenter.example.com",
+			}},
+			actuator: func(*mocks, *mockibm.MockAPI) Actuator {
+				return &vSphereActuator{validateCredentialsFn: func(_ client.Client, _ string, credentialsSecretRef, _ corev1.LocalObjectReference, vcenter string, _ log.FieldLogger) (bool, error) {
+					return credentialsSecretRef.Name != "vsphere-creds" || vcenter != "vcenter.example.com", nil
+				}}
+			},
+			expectErr: true,
+		},
+		{
+			name: "openstack valid",
+			platform: hivev1.ClusterDeprovisionPlatform{OpenStack: &hivev1.OpenStackClusterDeprovision{
+				Cloud:                 "openstack",
+				CredentialsSecretRef:  &corev1.LocalObjectReference{Name: "openstack-creds"},
+				CertificatesSecretRef: &corev1.LocalObjectReference{Name: "openstack-certs"},
+			}},
+			existing: []runtime.Object{
+				testsecret.Build(
+					testsecret.WithName("openstack-creds"),
+					testsecret.WithNamespace(testNamespace),
+					testsecret.WithDataKeyValue(constants.OpenStackCredentialsName, []byte(testCloudsYAML)),
+				),
+				testsecret.Build(
+					testsecret.WithName("openstack-certs"),
+					testsecret.WithNamespace(testNamespace),
+					testsecret.WithDataKeyValue("ca.crt", []byte("test-ca")),
+				),
+			},
+			actuator: func(*mocks, *mockibm.MockAPI) Actuator {
+				return &openStackActuator{authenticateFn: func(_ context.Context, opts *clientconfig.ClientOpts) error {
+					clouds, err := opts.YAMLOpts.LoadCloudsYAML()
+					if err != nil {
+						return err
+					}
+					cloud := clouds[opts.Cloud]
+					if cloud.AuthInfo == nil || cloud.AuthInfo.Username != "user" || cloud.CACertFile != "test-ca\n" {
+						return testErr
+					}
+					return nil
+				}}
+			},
+		},
+		{
+			name: "openstack missing cloud",
+			platform: hivev1.ClusterDeprovisionPlatform{OpenStack: &hivev1.OpenStackClusterDeprovision{
+				Cloud:                "other",
+				CredentialsSecretRef: &corev1.LocalObjectReference{Name: "openstack-creds"},
+			}},
+			existing: []runtime.Object{
+				testsecret.Build(
+					testsecret.WithName("openstack-creds"),
+					testsecret.WithNamespace(testNamespace),
+					testsecret.WithDataKeyValue(constants.OpenStackCredentialsName, []byte(testCloudsYAML)),
+				),
+			},
+			actuator: func(*mocks, *mockibm.MockAPI) Actuator {
+				return &openStackActuator{authenticateFn: func(context.Context, *clientconfig.ClientOpts) error {
+					return nil
+				}}
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := setupDefaultMocks(t, false, tc.existing...)
+			actuator := tc.actuator(m, mockibm.NewMockAPI(m.mockCtrl))
+			deprovision := testClusterDeprovision()
+			deprovision.Spec.Platform = tc.platform
+			require.True(t, actuator.CanHandle(deprovision), "actuator cannot handle deprovision")
+
+			err := actuator.TestCredentials(deprovision, m.fakeKubeClient, log.WithField("controller", "clusterDeprovision"))
+			if tc.expectErr {
+				assert.Error(t, err, "expected error testing credentials")
+			} else {
+				assert.NoError(t, err, "unexpected error testing credentials")
+			}
+		})
+	}
+}
+
+func TestActuatorListResources(t *testing.T) {
+	testErr := fmt.Errorf("access denied")
+	gcpActuatorFn := func(m *mocks) ResourceLister {
+		return &gcpActuator{gcpClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (gcpclient.Client, error) {
+			return m.mockGCPClient, nil
+		}}
+	}
+	azureActuatorFn := func(m *mocks) ResourceLister {
+		m.mockAzureClient.EXPECT().SubscriptionID().Return("test-subscription").AnyTimes()
+		return &azureActuator{azureClientFn: func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (azureclient.Client, error) {
+			return m.mockAzureClient, nil
+		}}
+	}
+	azureResourceGroup := func(m *mocks, resourceGroupName string, err error, ids ...string) {
+		var page azureclient.ResourcePage
+		if err == nil {
+			page = testResourcePage(m, ids...)
+		}
+		m.mockAzureClient.EXPECT().ListResources(gomock.Any(), resourceGroupName, "").Return(page, err)
+	}
+	azureTagged := func(m *mocks, ids ...string) {
+		m.mockAzureClient.EXPECT().ListResources(gomock.Any(), "", "tagName eq 'kubernetes.io_cluster.test-infra-id' and tagValue eq 'owned'").
+			Return(testResourcePage(m, ids...), nil)
+	}
+	cases := []struct {
+		name              string
+		platform          hivev1.ClusterDeprovisionPlatform
+		actuator          func(m *mocks) ResourceLister
+		expectedResources []string
+		expectErr         bool
+	}{
+		{
+			name:     "gcp",
+			platform: hivev1.ClusterDeprovisionPlatform{GCP: &hivev1.GCPClusterDeprovision{Region: "us-central1"}},
+			actuator: func(m *mocks) ResourceLister {
+				m.mockGCPClient.EXPECT().GetProjectName().Return("test-project").AnyTimes()
+				m.mockGCPClient.EXPECT().ListComputeResources(`name : "test-infra-id-*"`).Return([]string{
+					"https://www.googleapis.com/compute/v1/projects/test-project/zones/us-central1-a/instances/test-infra-id-master-0",
+					"https://www.googleapis.com/compute/v1/projects/test-project/global/networks/test-infra-id-network",
+				}, nil)
+				m.mockGCPClient.EXPECT().ListManagedZones(gcpclient.ListManagedZonesOptions{}).Return(&dns.ManagedZonesListResponse{
+					ManagedZones:  []*dns.ManagedZone{{Name: "test-infra-id-private-zone"}, {Name: "other-zone"}},
+					NextPageToken: "next",
+				}, nil)
+				m.mockGCPClient.EXPECT().ListManagedZones(gcpclient.ListManagedZonesOptions{PageToken: "next"}).Return(&dns.ManagedZonesListResponse{
+					ManagedZones: []*dns.ManagedZone{{Name: "test-infra-id-other-zone"}},
+				}, nil)
+				return gcpActuatorFn(m)
+			},
+			expectedResources: []string{
+				"https://www.googleapis.com/compute/v1/projects/test-project/global/networks/test-infra-id-network",
+				"https://www.googleapis.com/compute/v1/projects/test-project/zones/us-central1-a/instances/test-infra-id-master-0",
+				"projects/test-project/managedZones/test-infra-id-other-zone",
+				"projects/test-project/managedZones/test-infra-id-private-zone",
+			},
+		},
+		{
+			name:     "gcp list error",
+			platform: hivev1.ClusterDeprovisionPlatform{GCP: &hivev1.GCPClusterDeprovision{Region: "us-central1"}},
+			actuator: func(m *mocks) ResourceLister {
+				m.mockGCPClient.EXPECT().ListComputeResources(gomock.Any()).Return(nil, testErr)
+				return gcpActuatorFn(m)
+			},
+			expectErr: true,
+		},
+		{
+			name:     "azure",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{}},
+			actuator: func(m *mocks) ResourceLister {
+				azureResourceGroup(m, "test-infra-id-rg", nil,
+					"/subscriptions/test-subscription/resourceGroups/test-infra-id-rg/providers/Microsoft.Compute/virtualMachines/test-infra-id-master-0",
+				)
+				azureTagged(m,
+					"/subscriptions/test-subscription/resourceGroups/test-infra-id-rg/providers/Microsoft.Compute/virtualMachines/test-infra-id-master-0",
+					"/subscriptions/test-subscription/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/test-infra-id",
+				)
+				return azureActuatorFn(m)
+			},
+			expectedResources: []string{
+				"/subscriptions/test-subscription/resourceGroups/network-rg/providers/Microsoft.Network/loadBalancers/test-infra-id",
+				"/subscriptions/test-subscription/resourceGroups/test-infra-id-rg",
+				"/subscriptions/test-subscription/resourceGroups/test-infra-id-rg/providers/Microsoft.Compute/virtualMachines/test-infra-id-master-0",
+			},
+		},
+		{
+			name: "azure custom resource group",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{
+				ResourceGroupName: ptr.To("custom-rg"),
+			}},
+			actuator: func(m *mocks) ResourceLister {
+				azureResourceGroup(m, "custom-rg", nil)
+				azureTagged(m)
+				return azureActuatorFn(m)
+			},
+			expectedResources: []string{"/subscriptions/test-subscription/resourceGroups/custom-rg"},
+		},
+		{
+			name:     "azure resource group deleted",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{}},
+			actuator: func(m *mocks) ResourceLister {
+				azureResourceGroup(m, "test-infra-id-rg", autorest.DetailedError{StatusCode: http.StatusNotFound})
+				azureTagged(m)
+				return azureActuatorFn(m)
+			},
+			expectedResources: []string{},
+		},
+		{
+			name:     "azure list error",
+			platform: hivev1.ClusterDeprovisionPlatform{Azure: &hivev1.AzureClusterDeprovision{}},
+			actuator: func(m *mocks) ResourceLister {
+				azureResourceGroup(m, "test-infra-id-rg", autorest.DetailedError{StatusCode: http.StatusForbidden})
+				return azureActuatorFn(m)
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := setupDefaultMocks(t, false)
+			lister := tc.actuator(m)
+			deprovision := testClusterDeprovision()
+			deprovision.Spec.Platform = tc.platform
+
+			resources, err := lister.ListResources(deprovision, m.fakeKubeClient, log.WithField("controller", "clusterDeprovision"))
+			if tc.expectErr {
+				assert.Error(t, err, "expected error listing resources")
+				return
+			}
+			require.NoError(t, err, "unexpected error listing resources")
+			assert.Equal(t, tc.expectedResources, resources, "unexpected resources")
+		})
+	}
+}
+
+// testResourcePage returns a single page of Azure resources with the given IDs.
+func testResourcePage(m *mocks, ids ...string) azureclient.ResourcePage {
+	page := mockazure.NewMockResourcePage(m.mockCtrl)
+	values := make([]resources.GenericResourceExpanded, len(ids))
+	for i := range ids {
+		values[i].ID = &ids[i]
+	}
+	gomock.InOrder(
+		page.EXPECT().NotDone().Return(true),
+		page.EXPECT().NotDone().Return(false),
+	)
+	page.EXPECT().Values().Return(values).AnyTimes()
+	page.EXPECT().NextWithContext(gomock.Any()).Return(nil).AnyTimes()
+	return page
+}
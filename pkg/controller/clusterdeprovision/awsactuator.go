@@ -1,6 +1,8 @@
 package clusterdeprovision
 
 import (
+	"fmt"
+
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,13 +18,14 @@ func init() {
 	registerActuator(&awsActuator{awsClientFn: getAWSClient})
 }
 
-// Ensure AWSActuator implements the Actuator interface. This will fail at compile time when false.
+// Ensure AWSActuator implements the Actuator and ResourceLister interfaces. This will fail at compile time when false.
 var _ Actuator = &awsActuator{}
+var _ ResourceLister = &awsActuator{}
 
 // AWSActuator manages getting the desired state, getting the current state and reconciling the two.
 type awsActuator struct {
-	// awsClientFn is the function to build an AWS client, here for testing
-	awsClientFn func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (awsclient.Client, error)
+	// awsClientFn is the function to build an AWS client in a region, here for testing
+	awsClientFn func(*hivev1.ClusterDeprovision, string, client.Client, log.FieldLogger) (awsclient.Client, error)
 }
 
 // CanHandle returns true if the actuator can handle a particular ClusterDeprovision
@@ -32,7 +35,7 @@ func (a *awsActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) b
 
 // TestCredentials ensures that the the aws credentials are usable.
 func (a *awsActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
-	awsClient, err := a.awsClientFn(clusterDeprovision, c, logger)
+	awsClient, err := a.awsClientFn(clusterDeprovision, clusterDeprovision.Spec.Platform.AWS.Region, c, logger)
 	if err != nil {
 		return err
 	}
@@ -48,9 +51,32 @@ func (a *awsActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovis
 	return nil
 }
 
-func getAWSClient(cd *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) (awsclient.Client, error) {
+// ListResources returns the ARNs of the resources tagged for the cluster, as matched by the uninstaller.
+func (a *awsActuator) ListResources(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) ([]string, error) {
+	region := clusterDeprovision.Spec.Platform.AWS.Region
+	awsClient, err := a.awsClientFn(clusterDeprovision, region, c, logger)
+	if err != nil {
+		return nil, err
+	}
+	var globalClient awsclient.Client
+	if globalRegion := awsclient.GlobalRegion(region); globalRegion != region {
+		if globalClient, err = a.awsClientFn(clusterDeprovision, globalRegion, c, logger); err != nil {
+			return nil, err
+		}
+	}
+	tags := map[string]string{
+		fmt.Sprintf("kubernetes.io/cluster/%s", clusterDeprovision.Spec.InfraID):                        "owned",
+		fmt.Sprintf("sigs.k8s.io/cluster-api-provider-aws/cluster/%s", clusterDeprovision.Spec.InfraID): "owned",
+	}
+	if clusterDeprovision.Spec.ClusterID != "" {
+		tags["openshiftClusterID"] = clusterDeprovision.Spec.ClusterID
+	}
+	return awsclient.ListTaggedResources(awsClient, globalClient, tags)
+}
+
+func getAWSClient(cd *hivev1.ClusterDeprovision, region string, c client.Client, logger log.FieldLogger) (awsclient.Client, error) {
 	options := awsclient.Options{
-		Region: cd.Spec.Platform.AWS.Region,
+		Region: region,
 		CredentialsSource: awsclient.CredentialsSource{
 			Secret: &awsclient.SecretCredentialsSource{
 				Namespace: cd.Namespace,
@@ -0,0 +1,72 @@
+package clusterdeprovision
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient"
+)
+
+func init() {
+	registerActuator(&azureActuator{azureClientFn: getAzureClient})
+}
+
+// Ensure azureActuator implements the Actuator and ResourceLister interfaces. This will fail at compile time when false.
+var _ Actuator = &azureActuator{}
+var _ ResourceLister = &azureActuator{}
+
+type azureActuator struct {
+	// azureClientFn is the function to build an Azure client, here for testing
+	azureClientFn func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (azureclient.Client, error)
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeprovision
+func (a *azureActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) bool {
+	return clusterDeprovision.Spec.Platform.Azure != nil
+}
+
+// TestCredentials ensures that the Azure credentials are usable by listing the resource SKUs of the subscription.
+// Only the first page is fetched.
+func (a *azureActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
+	azureClient, err := a.azureClientFn(clusterDeprovision, c, logger)
+	if err != nil {
+		return err
+	}
+	_, err = azureClient.ListResourceSKUs(context.TODO(), "")
+	return err
+}
+
+// ListResources returns the IDs of the resources the uninstaller deletes: the resource group of the cluster, with all
+// of its resources, and the resources of the subscription tagged as owned by the cluster.
+func (a *azureActuator) ListResources(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) ([]string, error) {
+	azureClient, err := a.azureClientFn(clusterDeprovision, c, logger)
+	if err != nil {
+		return nil, err
+	}
+	var resourceGroupName string
+	if clusterDeprovision.Spec.Platform.Azure.ResourceGroupName != nil {
+		resourceGroupName = *clusterDeprovision.Spec.Platform.Azure.ResourceGroupName
+	}
+	return azureclient.ListClusterResources(context.TODO(), azureClient, clusterDeprovision.Spec.InfraID, resourceGroupName, logger)
+}
+
+func getAzureClient(cd *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) (azureclient.Client, error) {
+	if cd.Spec.Platform.Azure.CredentialsSecretRef == nil {
+		return nil, errors.New("Azure credentials secret is not set in ClusterDeprovision")
+	}
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), client.ObjectKey{Name: cd.Spec.Platform.Azure.CredentialsSecretRef.Name, Namespace: cd.Namespace}, secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch Azure credentials secret")
+	}
+	var cloudName string
+	if cd.Spec.Platform.Azure.CloudName != nil {
+		cloudName = cd.Spec.Platform.Azure.CloudName.Name()
+	}
+	return azureclient.NewClientFromSecret(secret, cloudName)
+}
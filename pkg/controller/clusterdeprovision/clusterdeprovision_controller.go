@@ -15,6 +15,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 
@@ -40,6 +41,15 @@ const (
 	jobHashAnnotation             = "hive.openshift.io/jobhash"
 	authenticationFailedReason    = "AuthenticationFailed"
 	authenticationSucceededReason = "AuthenticationSucceeded"
+	dryRunNameConflictReason      = "NameConflict"
+
+	// maxListedResources is the maximum number of resources listed in the status of a ClusterDeprovision.
+	maxListedResources = 500
+
+	// maxEventMessageLength is the maximum length of an event message.
+	maxEventMessageLength = 1024
+
+	resourcesLeakedReason = "ResourcesLeaked"
 )
 
 var (
@@ -50,6 +60,12 @@ var (
 			Buckets: []float64{60, 300, 600, 1200, 1800, 2400, 3000, 3600},
 		},
 	)
+	metricDeprovisionsLeakedResources = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "hive_cluster_deprovisions_leaked_resources_total",
+			Help: "Counter incremented every time a completed deprovision left cloud resources of the cluster behind.",
+		},
+	)
 
 	// actuators is a list of available actuators for this controller
 	// It is populated via the registerActuator function
@@ -65,6 +81,7 @@ func registerActuator(a Actuator) {
 
 func init() {
 	metrics.Registry.MustRegister(metricUninstallJobDuration)
+	metrics.Registry.MustRegister(metricDeprovisionsLeakedResources)
 }
 
 // Add creates a new ClusterDeprovision Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -99,6 +116,7 @@ func newReconciler(mgr manager.Manager, rateLimiter flowcontrol.RateLimiter) (re
 		Client:               controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &rateLimiter),
 		scheme:               mgr.GetScheme(),
 		deprovisionsDisabled: deprovisionsDisabled,
+		eventRecorder:        mgr.GetEventRecorderFor("clusterdeprovision-controller"),
 	}, nil
 }
 
@@ -145,6 +163,8 @@ type ReconcileClusterDeprovision struct {
 
 	// tolerations is copied from the hive-controllers pod and must be included in any Jobs we create from here.
 	tolerations *[]corev1.Toleration
+
+	eventRecorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeprovision object and makes changes based on the state read
@@ -202,6 +222,11 @@ func (r *ReconcileClusterDeprovision) Reconcile(ctx context.Context, request rec
 		return reconcile.Result{}, nil
 	}
 
+	// Dry runs only list resources, so they do not need a ClusterDeployment being deleted.
+	if instance.Spec.DryRun {
+		return r.reconcileDryRun(instance, rLog)
+	}
+
 	// Check if there is a ClusterDeployment owning this Deprovision, if so look it up and
 	// make sure it has a deletion timestamp. Otherwise bail out as a safety check.
 	oRef := metav1.GetControllerOf(instance)
@@ -363,6 +388,13 @@ func (r *ReconcileClusterDeprovision) Reconcile(ctx context.Context, request rec
 			controllerutils.UpdateConditionIfReasonOrMessageChange,
 		)
 		instance.Status.Conditions = conditions
+		// Leaks are only counted and reported once, when the status recording them is first updated.
+		leakCondition := controllerutils.FindCondition(instance.Status.Conditions, hivev1.ResourcesLeakedClusterDeprovisionCondition)
+		leakReported := leakCondition != nil && leakCondition.Status == corev1.ConditionTrue
+		var leakedResources []string
+		if lister, ok := actuator.(ResourceLister); ok {
+			leakedResources = r.checkLeakedResources(instance, lister, rLog)
+		}
 
 		// jobDuration calculates the time elapsed since the uninstall job started for deprovision job
 		jobDuration := existingJob.Status.CompletionTime.Time.Sub(existingJob.Status.StartTime.Time)
@@ -374,6 +406,12 @@ func (r *ReconcileClusterDeprovision) Reconcile(ctx context.Context, request rec
 			return reconcile.Result{}, err
 		}
 		metricUninstallJobDuration.Observe(float64(jobDuration.Seconds()))
+		if len(leakedResources) > 0 && !leakReported {
+			metricDeprovisionsLeakedResources.Inc()
+			// The ClusterDeprovision is deleted along with the ClusterDeployment, so the leaked resources are also
+			// reported in an event, which is kept for the event TTL of the API server.
+			r.eventRecorder.Event(instance, corev1.EventTypeWarning, resourcesLeakedReason, leakedResourcesMessage(leakedResources))
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -425,6 +463,144 @@ func (r *ReconcileClusterDeprovision) Reconcile(ctx context.Context, request rec
 	return reconcile.Result{}, nil
 }
 
+// reconcileDryRun lists the resources the deprovision would delete in the status of the ClusterDeprovision, and
+// completes it.
+func (r *ReconcileClusterDeprovision) reconcileDryRun(instance *hivev1.ClusterDeprovision, logger log.FieldLogger) (reconcile.Result, error) {
+	// The real deprovision of a ClusterDeployment is named after it, and replaces a dry run of the same name.
+	cd := &hivev1.ClusterDeployment{}
+	switch err := r.Get(context.TODO(), types.NamespacedName{Namespace: instance.Namespace, Name: instance.Name}, cd); {
+	case err == nil:
+		logger.Warn("dry run is named after a clusterdeployment")
+		instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+			instance.Status.Conditions,
+			hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+			corev1.ConditionTrue,
+			dryRunNameConflictReason,
+			fmt.Sprintf("ClusterDeployment %s is deprovisioned under this name; give the dry run a distinct name such as %s-dry-run", cd.Name, cd.Name),
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		instance.Status.Completed = true
+		return reconcile.Result{}, r.Status().Update(context.TODO(), instance)
+	case !errors.IsNotFound(err):
+		logger.WithError(err).Error("error looking up clusterdeployment named like the dry run")
+		return reconcile.Result{}, err
+	}
+
+	lister, ok := r.getActuator(instance).(ResourceLister)
+	if !ok {
+		logger.Warn("dry runs are not supported for this provider")
+		instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+			instance.Status.Conditions,
+			hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+			corev1.ConditionTrue,
+			"Unsupported",
+			"Dry runs are not supported on this platform",
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		instance.Status.Completed = true
+		return reconcile.Result{}, r.Status().Update(context.TODO(), instance)
+	}
+
+	resources, err := lister.ListResources(instance, r.Client, logger)
+	if err != nil {
+		logger.WithError(err).Error("could not list resources for dry run")
+		conditions, changed := controllerutils.SetClusterDeprovisionConditionWithChangeCheck(
+			instance.Status.Conditions,
+			hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+			corev1.ConditionTrue,
+			"ListFailed",
+			err.Error(),
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		if changed {
+			instance.Status.Conditions = conditions
+			if updateErr := r.Status().Update(context.TODO(), instance); updateErr != nil {
+				return reconcile.Result{}, updateErr
+			}
+		}
+		return reconcile.Result{}, err
+	}
+
+	logger.WithField("resources", len(resources)).Info("dry run completed")
+	instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+		instance.Status.Conditions,
+		hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+		corev1.ConditionFalse,
+		"ListSucceeded",
+		"Resources listed",
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	instance.Status.DryRunResources = newDeprovisionResources(resources)
+	instance.Status.Completed = true
+	if err := r.Status().Update(context.TODO(), instance); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating request status")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
+// checkLeakedResources records the resources of the cluster still found once the uninstall job succeeded in the
+// status of the ClusterDeprovision, and returns them. Failing to list them does not block the completion of the
+// deprovision.
+func (r *ReconcileClusterDeprovision) checkLeakedResources(instance *hivev1.ClusterDeprovision, lister ResourceLister, logger log.FieldLogger) []string {
+	resources, err := lister.ListResources(instance, r.Client, logger)
+	if err != nil {
+		logger.WithError(err).Warn("could not check for leaked resources")
+		instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+			instance.Status.Conditions,
+			hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+			corev1.ConditionTrue,
+			"ListFailed",
+			err.Error(),
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		return nil
+	}
+	if len(resources) == 0 {
+		instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+			instance.Status.Conditions,
+			hivev1.ResourcesLeakedClusterDeprovisionCondition,
+			corev1.ConditionFalse,
+			"NoResourcesLeaked",
+			"No resources of the cluster remain",
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		return nil
+	}
+	logger.WithField("resources", resources).Warn("resources of the cluster remain after deprovision")
+	instance.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
+		instance.Status.Conditions,
+		hivev1.ResourcesLeakedClusterDeprovisionCondition,
+		corev1.ConditionTrue,
+		"ResourcesLeaked",
+		fmt.Sprintf("%d resources of the cluster remain", len(resources)),
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	instance.Status.LeakedResources = newDeprovisionResources(resources)
+	return resources
+}
+
+// leakedResourcesMessage lists leaked resources in an event message, within the size limit of event messages.
+func leakedResourcesMessage(resources []string) string {
+	listed := resources
+	message := fmt.Sprintf("%d resources of the cluster remain after deprovision: %s", len(resources), strings.Join(listed, ", "))
+	for len(message) > maxEventMessageLength && len(listed) > 0 {
+		listed = listed[:len(listed)-1]
+		message = fmt.Sprintf("%d resources of the cluster remain after deprovision: %s and %d more",
+			len(resources), strings.Join(listed, ", "), len(resources)-len(listed))
+	}
+	return message
+}
+
+func newDeprovisionResources(resources []string) *hivev1.ClusterDeprovisionResources {
+	inventory := &hivev1.ClusterDeprovisionResources{Count: len(resources)}
+	if len(resources) > maxListedResources {
+		resources = resources[:maxListedResources]
+	}
+	inventory.Resources = resources
+	return inventory
+}
+
 func generateOwnershipUniqueKeys(owner hivev1.MetaRuntimeObject) []*controllerutils.OwnershipUniqueKey {
 	return []*controllerutils.OwnershipUniqueKey{
 		{
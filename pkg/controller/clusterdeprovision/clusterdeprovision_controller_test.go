@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,6 +46,15 @@ func init() {
 	log.SetLevel(log.DebugLevel)
 }
 
+// fakeS3 reports every bucket as existing.
+type fakeS3 struct {
+	s3iface.S3API
+}
+
+func (*fakeS3) HeadBucket(*s3.HeadBucketInput) (*s3.HeadBucketOutput, error) {
+	return &s3.HeadBucketOutput{}, nil
+}
+
 func TestClusterDeprovisionReconcile(t *testing.T) {
 
 	tests := []struct {
@@ -47,6 +62,11 @@ func TestClusterDeprovisionReconcile(t *testing.T) {
 		deployment            *hivev1.ClusterDeployment
 		deprovision           *hivev1.ClusterDeprovision
 		mockGetCallerIdentity bool
+		// mockListResources mocks the listing of the resources tagged for the cluster, which finds
+		// listedResources, or fails with listResourcesError.
+		mockListResources  bool
+		listedResources    []string
+		listResourcesError error
 		// The reconcile flow deletes the existing deprovision job if it failed, or if its spec
 		// needs to be changed. That's the only delete in the flow. Setting this field to `true`
 		// causes the test driver to mock out that deletion to return an error, allowing coverage
@@ -55,6 +75,7 @@ func TestClusterDeprovisionReconcile(t *testing.T) {
 		expectedGetCallerIdentityError error
 		existing                       []runtime.Object
 		validate                       func(t *testing.T, c client.Client)
+		expectedEvents                 []string
 		expectErr                      bool
 		deprovisionsDisabled           bool
 	}{
@@ -148,8 +169,176 @@ func TestClusterDeprovisionReconcile(t *testing.T) {
 				}),
 			},
 			mockGetCallerIdentity: true,
+			mockListResources:     true,
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+				assert.Nil(t, getClusterDeprovision(t, c).Status.LeakedResources, "unexpected leaked resources")
+			},
+		},
+		{
+			name:        "leaked resources when job is successful",
+			deprovision: testClusterDeprovision(),
+			deployment:  testDeletedClusterDeployment(),
+			existing: []runtime.Object{
+				testUninstallJob(batchv1.JobCondition{
+					Type:   batchv1.JobComplete,
+					Status: corev1.ConditionTrue,
+				}),
+			},
+			mockGetCallerIdentity: true,
+			mockListResources:     true,
+			listedResources:       []string{"arn:aws:s3:::test-bucket"},
+			expectedEvents:        []string{"Warning ResourcesLeaked 1 resources of the cluster remain after deprovision: arn:aws:s3:::test-bucket"},
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+				validateCondition(t, c, []hivev1.ClusterDeprovisionCondition{
+					{
+						Type:   hivev1.ResourcesLeakedClusterDeprovisionCondition,
+						Status: corev1.ConditionTrue,
+						Reason: "ResourcesLeaked",
+					},
+				})
+				req := getClusterDeprovision(t, c)
+				if assert.NotNil(t, req.Status.LeakedResources, "expected leaked resources") {
+					assert.Equal(t, 1, req.Status.LeakedResources.Count, "unexpected leaked resource count")
+					assert.Equal(t, []string{"arn:aws:s3:::test-bucket"}, req.Status.LeakedResources.Resources, "unexpected leaked resources")
+				}
+			},
+		},
+		{
+			name: "leaked resources already reported",
+			deprovision: func() *hivev1.ClusterDeprovision {
+				req := testClusterDeprovision()
+				req.Status.Conditions = []hivev1.ClusterDeprovisionCondition{{
+					Type:   hivev1.ResourcesLeakedClusterDeprovisionCondition,
+					Status: corev1.ConditionTrue,
+					Reason: "ResourcesLeaked",
+				}}
+				return req
+			}(),
+			deployment: testDeletedClusterDeployment(),
+			existing: []runtime.Object{
+				testUninstallJob(batchv1.JobCondition{
+					Type:   batchv1.JobComplete,
+					Status: corev1.ConditionTrue,
+				}),
+			},
+			mockGetCallerIdentity: true,
+			mockListResources:     true,
+			listedResources:       []string{"arn:aws:s3:::test-bucket"},
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+			},
+		},
+		{
+			name:        "completed when leak check fails",
+			deprovision: testClusterDeprovision(),
+			deployment:  testDeletedClusterDeployment(),
+			existing: []runtime.Object{
+				testUninstallJob(batchv1.JobCondition{
+					Type:   batchv1.JobComplete,
+					Status: corev1.ConditionTrue,
+				}),
+			},
+			mockGetCallerIdentity: true,
+			mockListResources:     true,
+			listResourcesError:    awserr.New("AccessDenied", "", fmt.Errorf("")),
+			validate: func(t *testing.T, c client.Client) {
+				validateCompleted(t, c)
+				validateCondition(t, c, []hivev1.ClusterDeprovisionCondition{
+					{
+						Type:   hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+						Status: corev1.ConditionTrue,
+						Reason: "ListFailed",
+					},
+				})
+			},
+		},
+		{
+			name: "dry run lists resources",
+			deprovision: func() *hivev1.ClusterDeprovision {
+				req := testClusterDeprovision()
+				req.Spec.DryRun = true
+				return req
+			}(),
+			deployment:        testDryRunClusterDeployment(),
+			mockListResources: true,
+			listedResources:   []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", "arn:aws:s3:::test-bucket"},
+			validate: func(t *testing.T, c client.Client) {
+				validateNoJobExists(t, c)
+				validateCompleted(t, c)
+				req := getClusterDeprovision(t, c)
+				if assert.NotNil(t, req.Status.DryRunResources, "expected dry run resources") {
+					assert.Equal(t, 2, req.Status.DryRunResources.Count, "unexpected dry run resource count")
+					assert.Equal(t, []string{"arn:aws:ec2:us-east-1:123456789012:vpc/vpc-1", "arn:aws:s3:::test-bucket"}, req.Status.DryRunResources.Resources, "unexpected dry run resources")
+				}
+			},
+		},
+		{
+			name: "dry run fails to list resources",
+			deprovision: func() *hivev1.ClusterDeprovision {
+				req := testClusterDeprovision()
+				req.Spec.DryRun = true
+				return req
+			}(),
+			deployment:         testDryRunClusterDeployment(),
+			mockListResources:  true,
+			listResourcesError: awserr.New("AccessDenied", "", fmt.Errorf("")),
+			validate: func(t *testing.T, c client.Client) {
+				validateNoJobExists(t, c)
+				validateNotCompleted(t, c)
+				validateCondition(t, c, []hivev1.ClusterDeprovisionCondition{
+					{
+						Type:   hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+						Status: corev1.ConditionTrue,
+						Reason: "ListFailed",
+					},
+				})
+			},
+			expectErr: true,
+		},
+		{
+			name: "dry run named after clusterdeployment",
+			deprovision: func() *hivev1.ClusterDeprovision {
+				req := testClusterDeprovision()
+				req.Spec.DryRun = true
+				return req
+			}(),
+			deployment: testClusterDeployment(),
+			validate: func(t *testing.T, c client.Client) {
+				validateNoJobExists(t, c)
+				validateCompleted(t, c)
+				validateCondition(t, c, []hivev1.ClusterDeprovisionCondition{
+					{
+						Type:   hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+						Status: corev1.ConditionTrue,
+						Reason: dryRunNameConflictReason,
+					},
+				})
+				assert.Nil(t, getClusterDeprovision(t, c).Status.DryRunResources, "expected no dry run resources")
+			},
+		},
+		{
+			name: "dry run unsupported",
+			deprovision: func() *hivev1.ClusterDeprovision {
+				req := testClusterDeprovision()
+				req.Spec.DryRun = true
+				req.Spec.Platform = hivev1.ClusterDeprovisionPlatform{
+					OpenStack: &hivev1.OpenStackClusterDeprovision{Cloud: "openstack"},
+				}
+				return req
+			}(),
+			deployment: testDryRunClusterDeployment(),
 			validate: func(t *testing.T, c client.Client) {
+				validateNoJobExists(t, c)
 				validateCompleted(t, c)
+				validateCondition(t, c, []hivev1.ClusterDeprovisionCondition{
+					{
+						Type:   hivev1.ResourceInventoryFailedClusterDeprovisionCondition,
+						Status: corev1.ConditionTrue,
+						Reason: "Unsupported",
+					},
+				})
 			},
 		},
 		{
@@ -292,17 +481,57 @@ func TestClusterDeprovisionReconcile(t *testing.T) {
 					Return(nil, test.expectedGetCallerIdentityError)
 			}
 
+			if test.mockListResources {
+				// The resources are listed for the infra ID tags and the cluster ID tag.
+				mocks.mockAWSClient.EXPECT().
+					GetResourcesPages(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ *resourcegroupstaggingapi.GetResourcesInput, fn func(*resourcegroupstaggingapi.GetResourcesOutput, bool) bool) error {
+						output := &resourcegroupstaggingapi.GetResourcesOutput{}
+						for _, arn := range test.listedResources {
+							output.ResourceTagMappingList = append(output.ResourceTagMappingList, &resourcegroupstaggingapi.ResourceTagMapping{
+								ResourceARN: aws.String(arn),
+							})
+						}
+						fn(output, true)
+						return test.listResourcesError
+					}).
+					MinTimes(1)
+				if test.listResourcesError == nil {
+					mocks.mockAWSClient.EXPECT().
+						GetS3API().
+						Return(&fakeS3{}).
+						AnyTimes()
+					mocks.mockAWSClient.EXPECT().
+						DescribeTagsPages(gomock.Any(), gomock.Any()).
+						DoAndReturn(func(input *ec2.DescribeTagsInput, fn func(*ec2.DescribeTagsOutput, bool) bool) error {
+							output := &ec2.DescribeTagsOutput{}
+							for _, id := range input.Filters[0].Values {
+								output.Tags = append(output.Tags, &ec2.TagDescription{ResourceId: id})
+							}
+							fn(output, true)
+							return nil
+						}).
+						AnyTimes()
+					mocks.mockAWSClient.EXPECT().
+						DescribeInstancesPages(gomock.Any(), gomock.Any()).
+						Return(nil).
+						AnyTimes()
+				}
+			}
+
+			recorder := record.NewFakeRecorder(10)
 			r := &ReconcileClusterDeprovision{
 				Client:               mocks.fakeKubeClient,
 				scheme:               scheme,
 				deprovisionsDisabled: test.deprovisionsDisabled,
 				nodeSelector:         &map[string]string{},
 				tolerations:          &[]corev1.Toleration{},
+				eventRecorder:        recorder,
 			}
 
 			// Save the list of actuators so that it can be restored at the end of this test
 			actuatorsSaved := actuators
-			actuators = []Actuator{&awsActuator{awsClientFn: func(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) (awsclient.Client, error) {
+			actuators = []Actuator{&awsActuator{awsClientFn: func(clusterDeprovision *hivev1.ClusterDeprovision, region string, c client.Client, logger log.FieldLogger) (awsclient.Client, error) {
 				return mocks.mockAWSClient, nil
 			}}}
 
@@ -319,6 +548,12 @@ func TestClusterDeprovisionReconcile(t *testing.T) {
 			if test.validate != nil {
 				test.validate(t, mocks.fakeKubeClient)
 			}
+			close(recorder.Events)
+			var events []string
+			for event := range recorder.Events {
+				events = append(events, event)
+			}
+			assert.Equal(t, test.expectedEvents, events, "unexpected events")
 
 			if test.expectErr {
 				assert.NotNil(t, err, "Expected error but didn't get one")
@@ -359,6 +594,14 @@ func testDeletedClusterDeployment() *hivev1.ClusterDeployment {
 	return cd
 }
 
+// testDryRunClusterDeployment returns a ClusterDeployment which is not named like the ClusterDeprovision, the way
+// dry runs must be named.
+func testDryRunClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Name = "dry-run-cluster"
+	return cd
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	return &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -450,6 +693,13 @@ func validateCondition(t *testing.T, c client.Client, expectedConditions []hivev
 	}
 }
 
+func getClusterDeprovision(t *testing.T, c client.Client) *hivev1.ClusterDeprovision {
+	req := &hivev1.ClusterDeprovision{}
+	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, req)
+	require.NoError(t, err, "unexpected error getting ClusterDeprovision")
+	return req
+}
+
 func validateCompleted(t *testing.T, c client.Client) {
 	req := &hivev1.ClusterDeprovision{}
 	err := c.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testName}, req)
@@ -460,3 +710,16 @@ func validateCompleted(t *testing.T, c client.Client) {
 		t.Errorf("request is expected to be in completed state")
 	}
 }
+
+func TestLeakedResourcesMessage(t *testing.T) {
+	assert.Equal(t, "2 resources of the cluster remain after deprovision: a, b", leakedResourcesMessage([]string{"a", "b"}), "unexpected message")
+
+	var resources []string
+	for i := 0; i < 100; i++ {
+		resources = append(resources, fmt.Sprintf("arn:aws:ec2:us-east-1:123456789012:volume/vol-%017d", i))
+	}
+	message := leakedResourcesMessage(resources)
+	assert.LessOrEqual(t, len(message), maxEventMessageLength, "message too long")
+	assert.Contains(t, message, resources[0], "expected first resource")
+	assert.Regexp(t, ` and \d+ more$`, message, "expected truncation")
+}
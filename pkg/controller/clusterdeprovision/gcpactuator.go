@@ -0,0 +1,63 @@
+package clusterdeprovision
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+func init() {
+	registerActuator(&gcpActuator{gcpClientFn: getGCPClient})
+}
+
+// Ensure gcpActuator implements the Actuator and ResourceLister interfaces. This will fail at compile time when false.
+var _ Actuator = &gcpActuator{}
+var _ ResourceLister = &gcpActuator{}
+
+type gcpActuator struct {
+	// gcpClientFn is the function to build a GCP client, here for testing
+	gcpClientFn func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (gcpclient.Client, error)
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeprovision
+func (a *gcpActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) bool {
+	return clusterDeprovision.Spec.Platform.GCP != nil
+}
+
+// TestCredentials ensures that the GCP credentials are usable by listing a compute zone of the project.
+func (a *gcpActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
+	gcpClient, err := a.gcpClientFn(clusterDeprovision, c, logger)
+	if err != nil {
+		return err
+	}
+	_, err = gcpClient.ListComputeZones(gcpclient.ListComputeZonesOptions{MaxResults: 1})
+	return err
+}
+
+// ListResources returns the self links of the compute resources, and the names of the DNS zones, named after the infra
+// ID of the cluster, as matched by the uninstaller.
+func (a *gcpActuator) ListResources(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) ([]string, error) {
+	gcpClient, err := a.gcpClientFn(clusterDeprovision, c, logger)
+	if err != nil {
+		return nil, err
+	}
+	return gcpclient.ListClusterResources(gcpClient, clusterDeprovision.Spec.InfraID)
+}
+
+func getGCPClient(cd *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) (gcpclient.Client, error) {
+	if cd.Spec.Platform.GCP.CredentialsSecretRef == nil {
+		return nil, errors.New("GCP credentials secret is not set in ClusterDeprovision")
+	}
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), client.ObjectKey{Name: cd.Spec.Platform.GCP.CredentialsSecretRef.Name, Namespace: cd.Namespace}, secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch GCP credentials secret")
+	}
+	return gcpclient.NewClientFromSecret(secret)
+}
@@ -0,0 +1,49 @@
+package clusterdeprovision
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/ibmclient"
+)
+
+func init() {
+	registerActuator(&ibmCloudActuator{ibmCloudClientFn: getIBMCloudClient})
+}
+
+// Ensure ibmCloudActuator implements the Actuator interface. This will fail at compile time when false.
+var _ Actuator = &ibmCloudActuator{}
+
+type ibmCloudActuator struct {
+	// ibmCloudClientFn is the function to build an IBM Cloud client, here for testing
+	ibmCloudClientFn func(*hivev1.ClusterDeprovision, client.Client, log.FieldLogger) (ibmclient.API, error)
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeprovision
+func (a *ibmCloudActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) bool {
+	return clusterDeprovision.Spec.Platform.IBMCloud != nil
+}
+
+// TestCredentials ensures that the IBM Cloud API key is usable by looking up its details.
+func (a *ibmCloudActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
+	ibmCloudClient, err := a.ibmCloudClientFn(clusterDeprovision, c, logger)
+	if err != nil {
+		return err
+	}
+	_, err = ibmCloudClient.GetAuthenticatorAPIKeyDetails(context.TODO())
+	return err
+}
+
+func getIBMCloudClient(cd *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) (ibmclient.API, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), client.ObjectKey{Name: cd.Spec.Platform.IBMCloud.CredentialsSecretRef.Name, Namespace: cd.Namespace}, secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch IBM Cloud credentials secret")
+	}
+	return ibmclient.NewClientFromSecret(secret)
+}
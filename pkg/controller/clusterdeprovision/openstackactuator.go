@@ -0,0 +1,95 @@
+package clusterdeprovision
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gophercloud/utils/v2/openstack/clientconfig"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+func init() {
+	registerActuator(&openStackActuator{authenticateFn: authenticateOpenStack})
+}
+
+// Ensure openStackActuator implements the Actuator interface. This will fail at compile time when false.
+var _ Actuator = &openStackActuator{}
+
+type openStackActuator struct {
+	// authenticateFn is the function to authenticate against the OpenStack identity service, here for testing
+	authenticateFn func(context.Context, *clientconfig.ClientOpts) error
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeprovision
+func (a *openStackActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) bool {
+	return clusterDeprovision.Spec.Platform.OpenStack != nil
+}
+
+// TestCredentials ensures that the cloud of the clouds.yaml in the credentials secret can authenticate against the
+// OpenStack identity service.
+func (a *openStackActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
+	platform := clusterDeprovision.Spec.Platform.OpenStack
+	if platform.CredentialsSecretRef == nil {
+		return errors.New("OpenStack credentials secret is not set in ClusterDeprovision")
+	}
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), client.ObjectKey{Name: platform.CredentialsSecretRef.Name, Namespace: clusterDeprovision.Namespace}, secret)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch OpenStack credentials secret")
+	}
+	cloudsYAML, ok := secret.Data[constants.OpenStackCredentialsName]
+	if !ok {
+		return errors.New("did not find credentials in the OpenStack credentials secret")
+	}
+	var clouds clientconfig.Clouds
+	if err := yaml.Unmarshal(cloudsYAML, &clouds); err != nil {
+		return errors.Wrap(err, "failed to unmarshal clouds.yaml from the OpenStack credentials secret")
+	}
+	cloud, ok := clouds.Clouds[platform.Cloud]
+	if !ok {
+		return errors.Errorf("no cloud %s found in the OpenStack credentials secret", platform.Cloud)
+	}
+	if platform.CertificatesSecretRef != nil {
+		buf := &bytes.Buffer{}
+		if err := controllerutils.TrustBundleFromSecretToWriter(c, clusterDeprovision.Namespace, platform.CertificatesSecretRef.Name, buf); err != nil {
+			return errors.Wrap(err, "failed to load trust bundle from CertificatesSecretRef")
+		}
+		// The OpenStack client accepts the contents of the CA certificates in place of their path.
+		cloud.CACertFile = buf.String()
+	}
+	return a.authenticateFn(context.TODO(), &clientconfig.ClientOpts{
+		Cloud:    platform.Cloud,
+		YAMLOpts: cloudsYAMLOpts{platform.Cloud: cloud},
+	})
+}
+
+func authenticateOpenStack(ctx context.Context, opts *clientconfig.ClientOpts) error {
+	_, err := clientconfig.NewServiceClient(ctx, "identity", opts)
+	return err
+}
+
+// cloudsYAMLOpts provides the clouds read from the credentials secret to the OpenStack client in place of a
+// clouds.yaml file.
+type cloudsYAMLOpts map[string]clientconfig.Cloud
+
+func (opts cloudsYAMLOpts) LoadCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return opts, nil
+}
+
+func (opts cloudsYAMLOpts) LoadSecureCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	// secure.yaml is optional so just pretend it doesn't exist
+	return nil, nil
+}
+
+func (opts cloudsYAMLOpts) LoadPublicCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return nil, fmt.Errorf("LoadPublicCloudsYAML() not implemented")
+}
@@ -0,0 +1,41 @@
+package clusterdeprovision
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+func init() {
+	registerActuator(&vSphereActuator{validateCredentialsFn: controllerutils.ValidateVSphereCredentials})
+}
+
+// Ensure vSphereActuator implements the Actuator interface. This will fail at compile time when false.
+var _ Actuator = &vSphereActuator{}
+
+type vSphereActuator struct {
+	// validateCredentialsFn is the function to log into the vCenter, here for testing
+	validateCredentialsFn func(c client.Client, namespace string, credentialsSecretRef, certificatesSecretRef corev1.LocalObjectReference, vcenter string, logger log.FieldLogger) (bool, error)
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeprovision
+func (a *vSphereActuator) CanHandle(clusterDeprovision *hivev1.ClusterDeprovision) bool {
+	return clusterDeprovision.Spec.Platform.VSphere != nil
+}
+
+// TestCredentials ensures that the vSphere credentials can log into the vCenter.
+func (a *vSphereActuator) TestCredentials(clusterDeprovision *hivev1.ClusterDeprovision, c client.Client, logger log.FieldLogger) error {
+	platform := clusterDeprovision.Spec.Platform.VSphere
+	valid, err := a.validateCredentialsFn(c, clusterDeprovision.Namespace, platform.CredentialsSecretRef, platform.CertificatesSecretRef, platform.VCenter, logger)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("vSphere credentials are not valid")
+	}
+	return nil
+}
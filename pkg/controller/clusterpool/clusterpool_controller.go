@@ -756,6 +756,7 @@ func (r *ReconcileClusterPool) createCluster(
 		InstallAttemptsLimit:  clp.Spec.InstallAttemptsLimit,
 		InstallerEnv:          clp.Spec.InstallerEnv,
 		SkipMachinePools:      clp.Spec.SkipMachinePools,
+		PowerSchedule:         clp.Spec.PowerSchedule,
 	}
 
 	if clp.Spec.HibernateAfter != nil {
@@ -1145,7 +1146,7 @@ func setDeletionPossibleCondition(pool *hivev1.ClusterPool, cds *cdCollection) b
 	return changed
 }
 
-// setStatusCounts sets the Size, Standby, and Ready status fields in clp according to cds.
+// setStatusCounts sets the Size, Standby, Ready, and CostEstimate status fields in clp according to cds.
 // The caller is responsible for pushing the changes back to the server.
 // The return indicates whether anything changed.
 func setStatusCounts(clp *hivev1.ClusterPool, cds *cdCollection) bool {
@@ -1153,6 +1154,7 @@ func setStatusCounts(clp *hivev1.ClusterPool, cds *cdCollection) bool {
 	clp.Status.Size = int32(len(cds.Unassigned(true)))
 	clp.Status.Standby = int32(len(cds.Standby()))
 	clp.Status.Ready = int32(len(cds.Assignable()))
+	clp.Status.CostEstimate = controllerutils.SumCostEstimates(cds.All())
 	return !reflect.DeepEqual(origStatus, &clp.Status)
 }
 
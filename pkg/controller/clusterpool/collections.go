@@ -438,6 +438,15 @@ func (cds *cdCollection) Names() []string {
 	return ret
 }
 
+// All returns all the ClusterDeployments in the cdCollection, sorted by name.
+func (cds *cdCollection) All() []*hivev1.ClusterDeployment {
+	ret := []*hivev1.ClusterDeployment{}
+	for _, cdName := range cds.Names() {
+		ret = append(ret, cds.byCDName[cdName])
+	}
+	return ret
+}
+
 // NumAssigned returns the number of ClusterDeployments assigned to claims.
 func (cds *cdCollection) NumAssigned() int {
 	return len(cds.byClaimName)
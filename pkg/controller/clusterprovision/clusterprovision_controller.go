@@ -399,6 +399,13 @@ func (r *ReconcileClusterProvision) reconcileFailedJob(instance *hivev1.ClusterP
 	if controllerutils.IsDeadlineExceeded(job) && reason == unknownReason {
 		reason, message = "AttemptDeadlineExceeded", "Install job failed due to deadline being exceeded for the attempt"
 	}
+	// A custom installer reports through its exit code whether the failure is worth retrying. Keep the install
+	// manager's classification of it.
+	if cond := controllerutils.FindCondition(instance.Status.Conditions, hivev1.ClusterProvisionFailedCondition); cond != nil &&
+		cond.Status == corev1.ConditionTrue &&
+		(cond.Reason == hivev1.CustomInstallerRetryableFailureReason || cond.Reason == hivev1.CustomInstallerFatalFailureReason) {
+		reason, message = cond.Reason, cond.Message
+	}
 	result, err := r.transitionStage(instance, hivev1.ClusterProvisionStageFailed, reason, message, pLog)
 	if err == nil {
 		// Increment a counter metric for this cluster type and error reason:
@@ -463,6 +470,7 @@ func (r *ReconcileClusterProvision) transitionStage(
 	}
 	if stage == hivev1.ClusterProvisionStageFailed || stage == hivev1.ClusterProvisionStageComplete {
 		r.logProvisionSuccessFailureMetric(stage, instance)
+		observeInstallPhaseDurations(instance)
 	}
 	if err := r.setStage(instance, stage, pLog); err != nil {
 		return reconcile.Result{}, err
@@ -644,3 +652,21 @@ func (r *ReconcileClusterProvision) logProvisionSuccessFailureMetric(
 	}
 	timeMetric.Observe(cd, fixedLabels, time.Since(instance.CreationTimestamp.Time).Seconds())
 }
+
+// observeInstallPhaseDurations reports how long the installer spent in each of the phases it finished, as reported
+// by the install manager on the provision status.
+func observeInstallPhaseDurations(instance *hivev1.ClusterProvision) {
+	if instance.Status.Progress == nil {
+		return
+	}
+	for _, phase := range instance.Status.Progress.Phases {
+		if phase.FinishedTime == nil || phase.Name == hivev1.ClusterProvisionPhaseComplete {
+			continue
+		}
+		metricInstallPhaseSeconds.Observe(
+			instance,
+			map[string]string{"phase": string(phase.Name)},
+			phase.FinishedTime.Sub(phase.StartedTime.Time).Seconds(),
+		)
+	}
+}
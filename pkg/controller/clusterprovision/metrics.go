@@ -16,6 +16,7 @@ var (
 
 	metricInstallFailureSeconds hivemetrics.HistogramVecWithDynamicLabels
 	metricInstallSuccessSeconds hivemetrics.HistogramVecWithDynamicLabels
+	metricInstallPhaseSeconds   hivemetrics.HistogramVecWithDynamicLabels
 )
 
 func registerMetrics(mConfig *metricsconfig.MetricsConfig, log log.FieldLogger) {
@@ -57,8 +58,19 @@ func registerMetrics(mConfig *metricsconfig.MetricsConfig, log log.FieldLogger)
 		mapClusterTypeLabelToValue,
 	)
 
+	metricInstallPhaseSeconds = *hivemetrics.NewHistogramVecWithDynamicLabels(
+		&prometheus.HistogramOpts{
+			Name:    "hive_cluster_provision_install_phase_duration_seconds",
+			Help:    "Time the installer spent in each phase of a cluster install, as parsed from the install log",
+			Buckets: []float64{60, 300, 600, 900, 1200, 1800, 2700, 3600},
+		},
+		[]string{"phase"},
+		mapClusterTypeLabelToValue,
+	)
+
 	metricInstallErrors.Register()
 	metricClusterProvisionsTotal.Register()
 	metricInstallFailureSeconds.Register()
 	metricInstallSuccessSeconds.Register()
+	metricInstallPhaseSeconds.Register()
 }
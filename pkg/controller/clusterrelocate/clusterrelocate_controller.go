@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -14,12 +15,14 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/diff"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -29,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
 	"github.com/openshift/hive/pkg/constants"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
@@ -37,6 +41,13 @@ import (
 
 const (
 	ControllerName = hivev1.ClusterRelocateControllerName
+
+	// defaultVerificationTimeout is how long the destination has to take ownership of a relocated cluster when the
+	// ClusterRelocate does not say.
+	defaultVerificationTimeout = time.Hour
+
+	// verificationInterval is how often the destination is checked while verifying a relocation.
+	verificationInterval = time.Minute
 )
 
 var (
@@ -69,8 +80,38 @@ var (
 	// HIVE-2080: Ignore (do not copy) ConfigMaps laid down by the kube-controller-manager
 	// in every namespace.
 	ignoreConfigMapNames = sets.NewString("kube-root-ca.crt", "openshift-service-ca.crt")
+
+	// forbiddenAdditionalKinds are the Hive kinds that cannot be relocated as additional resources. A ClusterDeployment
+	// and its DNSZone are only relocated along with the ClusterDeployment itself, and relocating ClusterPools or
+	// ClusterClaims would have the destination create or assign clusters on its own.
+	forbiddenAdditionalKinds = sets.NewString("ClusterDeployment", "DNSZone", "ClusterPool", "ClusterClaim")
 )
 
+// additionalResourcesForbiddenReason is the reason of the RelocationFailed condition when Hive is not allowed to copy
+// additional resources.
+const additionalResourcesForbiddenReason = "AdditionalResourcesForbidden"
+
+// additionalResourcesForbiddenError is returned when the RBAC of the source or destination cluster does not allow
+// copying the additional resources of a kind.
+type additionalResourcesForbiddenError struct {
+	res         hivev1.RelocateResourceSelector
+	destination bool
+	err         error
+}
+
+func (e *additionalResourcesForbiddenError) Error() string {
+	if e.destination {
+		return fmt.Sprintf("the destination kubeconfig is not allowed to copy %s (%s) resources; it needs the get, create and delete verbs on them in the destination cluster: %v",
+			e.res.Kind, e.res.APIVersion, e.err)
+	}
+	return fmt.Sprintf("the hive-controllers service account is not allowed to list %s (%s) resources; grant it the list verb on them in the source cluster: %v",
+		e.res.Kind, e.res.APIVersion, e.err)
+}
+
+func (e *additionalResourcesForbiddenError) Unwrap() error {
+	return e.err
+}
+
 func init() {
 	metrics.Registry.MustRegister(metricSuccessfulClusterRelocations)
 	metrics.Registry.MustRegister(metricAbortedClusterRelocations)
@@ -263,6 +304,16 @@ func (r *ReconcileClusterRelocate) reconcileSingleMatch(cd *hivev1.ClusterDeploy
 
 	logger = logger.WithField("clusterRelocate", desiredRelocate.Name)
 
+	// Do not start relocating while the ClusterRelocate is rolling back, nor after the relocation of the
+	// ClusterDeployment has been rolled back.
+	relocating := oldRelocateStatus == hivev1.RelocateOutgoing && oldRelocateName == desiredRelocate.Name
+	rollback := desiredRelocate.Spec.Rollback ||
+		clusterRelocationPhase(desiredRelocate, cd) == hivev1.ClusterRelocationPhaseRolledBack
+	if rollback && !relocating {
+		logger.Debug("not relocating since the relocation is rolled back")
+		return reconcile.Result{}, nil
+	}
+
 	kubeconfigSecret := &corev1.Secret{}
 	if err := r.Get(
 		context.Background(),
@@ -280,6 +331,8 @@ func (r *ReconcileClusterRelocate) reconcileSingleMatch(cd *hivev1.ClusterDeploy
 			fmt.Sprintf("missing kubeconfig secret for destination cluster: %v", err),
 			logger,
 		)
+		r.setClusterRelocationStatus(desiredRelocate.Name, cd, hivev1.ClusterRelocationPhaseFailed,
+			fmt.Sprintf("missing kubeconfig secret for destination cluster: %v", err), logger)
 		// return the error getting the kubeconfig secret rather than the update error
 		return reconcile.Result{}, errors.Wrap(err, "failed to get kubeconfig secret")
 	}
@@ -294,15 +347,24 @@ func (r *ReconcileClusterRelocate) reconcileSingleMatch(cd *hivev1.ClusterDeploy
 			fmt.Sprintf("could not connect to destination cluster: %v", err),
 			logger,
 		)
+		r.setClusterRelocationStatus(desiredRelocate.Name, cd, hivev1.ClusterRelocationPhaseFailed,
+			fmt.Sprintf("could not connect to destination cluster: %v", err), logger)
 		// return the error making the remote connection rather than the update error
 		return reconcile.Result{}, errors.Wrap(err, "could not create a client for the destination cluster")
 	}
 
+	if rollback {
+		if err := r.rollback(cd, desiredRelocate.Name, destClient, "rollback requested", logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to roll back relocation")
+		}
+		return reconcile.Result{}, nil
+	}
+
 	switch proceed, completed, err := r.checkForExistingClusterDeployment(cd, destClient, logger); {
 	case err != nil:
 		return reconcile.Result{}, err
 	case completed:
-		return r.finishRelocateCompletion(cd, desiredRelocate.Name, logger)
+		return r.verifyRelocation(cd, desiredRelocate, destClient, logger)
 	case !proceed:
 		return reconcile.Result{}, nil
 	}
@@ -310,21 +372,179 @@ func (r *ReconcileClusterRelocate) reconcileSingleMatch(cd *hivev1.ClusterDeploy
 	if err := r.setRelocateAnnotation(cd, desiredRelocate.Name, hivev1.RelocateOutgoing, logger); err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "could not set relocate status to outgoing")
 	}
+	if err := r.setClusterRelocationStatus(desiredRelocate.Name, cd, hivev1.ClusterRelocationPhaseCopying, "", logger); err != nil {
+		return reconcile.Result{}, err
+	}
 
 	// Copy resources to destination cluster
-	if err := r.copy(cd, destClient, logger); err != nil {
+	if err := r.copy(cd, desiredRelocate, destClient, logger); err != nil {
+		reason := "MoveFailed"
+		var forbiddenErr *additionalResourcesForbiddenError
+		if errors.As(err, &forbiddenErr) {
+			reason = additionalResourcesForbiddenReason
+		}
 		r.setRelocationFailedCondition(
 			cd,
 			corev1.ConditionTrue,
-			"MoveFailed",
+			reason,
 			err.Error(),
 			logger,
 		)
+		r.setClusterRelocationStatus(desiredRelocate.Name, cd, hivev1.ClusterRelocationPhaseFailed, err.Error(), logger)
 		// return the move error rather than the update error
 		return reconcile.Result{}, err
 	}
 
-	return r.finishRelocateCompletion(cd, desiredRelocate.Name, logger)
+	return r.verifyRelocation(cd, desiredRelocate, destClient, logger)
+}
+
+// verifyRelocation completes the relocation of a ClusterDeployment that has been copied to the destination cluster.
+// When the ClusterRelocate asks for verification, the relocation is only completed once the destination Hive instance
+// has taken ownership of the cluster, and is rolled back if it does not do so before the verification timeout.
+func (r *ReconcileClusterRelocate) verifyRelocation(cd *hivev1.ClusterDeployment, relocate *hivev1.ClusterRelocate, destClient client.Client, logger log.FieldLogger) (reconcile.Result, error) {
+	if relocate.Spec.Verification == nil {
+		return r.finishRelocateCompletion(cd, relocate.Name, logger)
+	}
+
+	// The source keeps away from the cluster while the destination takes ownership of it.
+	if err := r.setRelocateAnnotation(cd, relocate.Name, hivev1.RelocateOutgoing, logger); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "could not set relocate status to outgoing")
+	}
+
+	pending, err := checkDestinationOwnership(cd, destClient, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if pending == "" {
+		logger.Info("destination has taken ownership of the cluster")
+		return r.finishRelocateCompletion(cd, relocate.Name, logger)
+	}
+	logger = logger.WithField("pending", pending)
+
+	timeout := defaultVerificationTimeout
+	if relocate.Spec.Verification.Timeout != nil {
+		timeout = relocate.Spec.Verification.Timeout.Duration
+	}
+	verifyingSince := time.Now()
+	if status := clusterRelocationStatus(relocate, cd); status != nil && status.Phase == hivev1.ClusterRelocationPhaseVerifying {
+		verifyingSince = status.LastTransitionTime.Time
+	}
+	remaining := time.Until(verifyingSince.Add(timeout))
+	if remaining <= 0 {
+		logger.Warn("destination did not take ownership of the cluster in time")
+		if err := r.rollback(cd, relocate.Name, destClient, fmt.Sprintf("verification timed out: %s", pending), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to roll back relocation")
+		}
+		return reconcile.Result{}, nil
+	}
+
+	logger.Info("waiting for destination to take ownership of the cluster")
+	if err := r.setClusterRelocationStatus(relocate.Name, cd, hivev1.ClusterRelocationPhaseVerifying, pending, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+	if remaining > verificationInterval {
+		remaining = verificationInterval
+	}
+	return reconcile.Result{RequeueAfter: remaining}, nil
+}
+
+// checkDestinationOwnership checks whether the destination Hive instance has taken ownership of a relocated cluster:
+// it has released the incoming ClusterDeployment, can reach the cluster, and is syncing to it without failures. It
+// returns what the destination has yet to do, or an empty string when it has taken ownership.
+func checkDestinationOwnership(cd *hivev1.ClusterDeployment, destClient client.Client, logger log.FieldLogger) (string, error) {
+	cdKey := client.ObjectKeyFromObject(cd)
+	destCD := &hivev1.ClusterDeployment{}
+	if err := destClient.Get(context.Background(), cdKey, destCD); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to get clusterdeployment in destination cluster")
+		return "", errors.Wrap(err, "failed to get clusterdeployment in destination cluster")
+	}
+	if _, relocating := destCD.Annotations[constants.RelocateAnnotation]; relocating {
+		return "the destination has not released the incoming ClusterDeployment", nil
+	}
+	if cond := controllerutils.FindCondition(destCD.Status.Conditions, hivev1.UnreachableCondition); cond == nil || cond.Status != corev1.ConditionFalse {
+		return "the destination has not reached the cluster", nil
+	}
+
+	clusterSync := &hiveintv1alpha1.ClusterSync{}
+	switch err := destClient.Get(context.Background(), cdKey, clusterSync); {
+	case apierrors.IsNotFound(err):
+		return "the destination has not synced to the cluster", nil
+	case err != nil:
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to get clustersync in destination cluster")
+		return "", errors.Wrap(err, "failed to get clustersync in destination cluster")
+	}
+	switch cond := controllerutils.FindCondition(clusterSync.Status.Conditions, hiveintv1alpha1.ClusterSyncFailed); {
+	case cond == nil || cond.Status == corev1.ConditionUnknown:
+		return "the destination has not synced to the cluster", nil
+	case cond.Status == corev1.ConditionTrue:
+		return fmt.Sprintf("the destination is failing to sync to the cluster: %s", cond.Message), nil
+	}
+	return "", nil
+}
+
+// rollback hands a cluster whose relocation has not completed back to this Hive instance. The ClusterDeployment and
+// DNSZone copied to the destination cluster are marked as relocated away before they are deleted, so that the
+// destination Hive instance releases them without deprovisioning the cluster or deleting its DNS zone. The MachinePools
+// of the ClusterDeployment are deleted as well. Other copied resources are left in the destination cluster.
+func (r *ReconcileClusterRelocate) rollback(cd *hivev1.ClusterDeployment, relocateName string, destClient client.Client, reason string, logger log.FieldLogger) error {
+	logger = logger.WithField("reason", reason)
+	logger.Warn("rolling back relocation")
+
+	destObjs := []client.Object{
+		&hivev1.DNSZone{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: controllerutils.DNSZoneName(cd.Name)}},
+		&hivev1.ClusterDeployment{ObjectMeta: metav1.ObjectMeta{Namespace: cd.Namespace, Name: cd.Name}},
+	}
+	var found []client.Object
+	for _, obj := range destObjs {
+		logger := logger.WithField("type", reflect.TypeOf(obj)).WithField("resource", obj.GetName())
+		switch err := destClient.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); {
+		case apierrors.IsNotFound(err):
+			continue
+		case err != nil:
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "could not get resource from destination cluster")
+			return errors.Wrap(err, "could not get resource from destination cluster")
+		}
+		if controllerutils.SetRelocateAnnotation(obj, relocateName, hivev1.RelocateComplete) {
+			if err := destClient.Update(context.Background(), obj); err != nil {
+				logger.WithError(err).Log(controllerutils.LogLevel(err), "could not release resource in destination cluster")
+				return errors.Wrap(err, "could not release resource in destination cluster")
+			}
+		}
+		found = append(found, obj)
+	}
+	for _, obj := range found {
+		if err := destClient.Delete(context.Background(), obj); err != nil && !apierrors.IsNotFound(err) {
+			logger.WithError(err).WithField("resource", obj.GetName()).Log(controllerutils.LogLevel(err), "could not delete resource from destination cluster")
+			return errors.Wrap(err, "could not delete resource from destination cluster")
+		}
+	}
+
+	machinePools := &hivev1.MachinePoolList{}
+	if err := destClient.List(context.Background(), machinePools, client.InNamespace(cd.Namespace)); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not list machinepools in destination cluster")
+		return errors.Wrap(err, "could not list machinepools in destination cluster")
+	}
+	for i, mp := range machinePools.Items {
+		if mp.Spec.ClusterDeploymentRef.Name != cd.Name {
+			continue
+		}
+		if err := destClient.Delete(context.Background(), &machinePools.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			logger.WithError(err).WithField("machinePool", mp.Name).Log(controllerutils.LogLevel(err), "could not delete machinepool from destination cluster")
+			return errors.Wrap(err, "could not delete machinepool from destination cluster")
+		}
+	}
+
+	if err := r.clearRelocateAnnotation(cd, logger); err != nil {
+		return err
+	}
+	if err := r.setRelocationFailedCondition(cd, corev1.ConditionTrue, "RolledBack", reason, logger); err != nil {
+		return err
+	}
+	if err := r.setClusterRelocationStatus(relocateName, cd, hivev1.ClusterRelocationPhaseRolledBack, reason, logger); err != nil {
+		return err
+	}
+	recordMetricForAbortedRelocate(relocateName, "rolled_back")
+	return nil
 }
 
 // setRelocateAnnotation sets the relocate annotation on the ClusterDeployment as well as on the child DNSZone, if there
@@ -402,6 +622,10 @@ func (r *ReconcileClusterRelocate) finishRelocateCompletion(cd *hivev1.ClusterDe
 		return reconcile.Result{}, err
 	}
 
+	if err := r.setClusterRelocationStatus(relocateName, cd, hivev1.ClusterRelocationPhaseCompleted, "", logger); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Delete the ClusterDeployment since it has been successfully relocated to a new Hive instance
 	if err := r.Delete(context.Background(), cd); err != nil {
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not delete relocated clusterdeployment")
@@ -530,7 +754,7 @@ func (r *ReconcileClusterRelocate) checkForExistingClusterDeployment(cd *hivev1.
 	return
 }
 
-func (r *ReconcileClusterRelocate) copy(cd *hivev1.ClusterDeployment, destClient client.Client, logger log.FieldLogger) error {
+func (r *ReconcileClusterRelocate) copy(cd *hivev1.ClusterDeployment, relocate *hivev1.ClusterRelocate, destClient client.Client, logger log.FieldLogger) error {
 	// create namespace
 	switch err := destClient.Create(context.Background(), &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -553,6 +777,13 @@ func (r *ReconcileClusterRelocate) copy(cd *hivev1.ClusterDeployment, destClient
 		}
 	}
 
+	// copy additional resources
+	for _, res := range relocate.Spec.AdditionalResources {
+		if err := r.copyAdditionalResources(cd, destClient, res, logger); err != nil {
+			return errors.Wrapf(err, "failed to copy %s %s", res.APIVersion, res.Kind)
+		}
+	}
+
 	// copy dnszone
 	dnsZone, err := r.dnsZone(cd, logger)
 	if err != nil {
@@ -576,6 +807,39 @@ func (r *ReconcileClusterRelocate) copy(cd *hivev1.ClusterDeployment, destClient
 	return nil
 }
 
+// copyAdditionalResources copies the resources selected by one of the additional resources of a ClusterRelocate in the
+// namespace of the ClusterDeployment to the destination cluster
+func (r *ReconcileClusterRelocate) copyAdditionalResources(cd *hivev1.ClusterDeployment, destClient client.Client, res hivev1.RelocateResourceSelector, logger log.FieldLogger) error {
+	gv, err := schema.ParseGroupVersion(res.APIVersion)
+	if err != nil {
+		return errors.Wrap(err, "invalid API version")
+	}
+	if gv.Group == hivev1.SchemeGroupVersion.Group && forbiddenAdditionalKinds.Has(res.Kind) {
+		return errors.Errorf("%s resources cannot be relocated as additional resources", res.Kind)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&res.Selector)
+	if err != nil {
+		return errors.Wrap(err, "invalid selector")
+	}
+	objectList := &unstructured.UnstructuredList{}
+	objectList.SetGroupVersionKind(gv.WithKind(res.Kind + "List"))
+	logger = logger.WithField("type", reflect.TypeOf(objectList)).WithField("kind", res.Kind)
+	if err := r.List(context.Background(), objectList, client.InNamespace(cd.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not list resources")
+		if apierrors.IsForbidden(err) {
+			return &additionalResourcesForbiddenError{res: res, err: err}
+		}
+		return errors.Wrapf(err, "failed to list %T", objectList)
+	}
+	if err := r.copyListedResources(destClient, objectList, logger); err != nil {
+		if apierrors.IsForbidden(err) {
+			return &additionalResourcesForbiddenError{res: res, destination: true, err: err}
+		}
+		return err
+	}
+	return nil
+}
+
 // copyResources copies all of the resources of the given object type in the namespace of the ClusterDeployment to the
 // destination cluster
 func (r *ReconcileClusterRelocate) copyResources(cd *hivev1.ClusterDeployment, destClient client.Client, objectList client.ObjectList, logger log.FieldLogger) error {
@@ -584,6 +848,11 @@ func (r *ReconcileClusterRelocate) copyResources(cd *hivev1.ClusterDeployment, d
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not list resources")
 		return errors.Wrapf(err, "failed to list %T", objectList)
 	}
+	return r.copyListedResources(destClient, objectList, logger)
+}
+
+// copyListedResources copies the resources of a list to the destination cluster
+func (r *ReconcileClusterRelocate) copyListedResources(destClient client.Client, objectList client.ObjectList, logger log.FieldLogger) error {
 	objs, err := meta.ExtractList(objectList)
 	if err != nil {
 		logger.WithError(err).Error("could not extract resources from list")
@@ -640,7 +909,13 @@ func (r *ReconcileClusterRelocate) copyResource(obj runtime.Object, destClient c
 func (r *ReconcileClusterRelocate) replaceResourceIfChanged(destClient client.Client, srcObj client.Object, logger log.FieldLogger) error {
 	// Get the object from the destination cluster
 	objKey := client.ObjectKeyFromObject(srcObj)
-	destObj := reflect.New(reflect.TypeOf(srcObj).Elem()).Interface().(client.Object)
+	var destObj client.Object
+	if u, ok := srcObj.(*unstructured.Unstructured); ok {
+		destObj = &unstructured.Unstructured{}
+		destObj.GetObjectKind().SetGroupVersionKind(u.GroupVersionKind())
+	} else {
+		destObj = reflect.New(reflect.TypeOf(srcObj).Elem()).Interface().(client.Object)
+	}
 	if err := destClient.Get(context.Background(), objKey, destObj); err != nil {
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not get resource from destination cluster")
 		return errors.Wrap(err, "could not get resource from destination cluster")
@@ -691,7 +966,10 @@ func prepareForComparison(obj runtime.Object) (runtime.Object, error) {
 	obj = obj.DeepCopyObject()
 
 	// Clear the GroupVersionKind in case there are version mismatches between the source cluster and destination cluster.
-	obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	// Unstructured objects keep theirs, as it is the only way to know what they are.
+	if _, ok := obj.(*unstructured.Unstructured); !ok {
+		obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+	}
 
 	objMeta, err := meta.Accessor(obj)
 	if err != nil {
@@ -718,6 +996,8 @@ func prepareForComparison(obj runtime.Object) (runtime.Object, error) {
 		if err := replaceOutgoingToIncoming(t); err != nil {
 			return nil, errors.Wrap(err, "could not set relocate status to incoming")
 		}
+	case *unstructured.Unstructured:
+		unstructured.RemoveNestedField(t.Object, "status")
 	default:
 		return nil, errors.Errorf("unknown type to relocate: %T", t)
 	}
@@ -842,6 +1122,60 @@ func (r *ReconcileClusterRelocate) setRelocationFailedCondition(cd *hivev1.Clust
 	return nil
 }
 
+// clusterRelocationStatus returns the relocation progress of a ClusterDeployment recorded in the status of a
+// ClusterRelocate, or nil when there is none.
+func clusterRelocationStatus(relocate *hivev1.ClusterRelocate, cd *hivev1.ClusterDeployment) *hivev1.ClusterRelocationStatus {
+	for i, status := range relocate.Status.Clusters {
+		if status.Namespace == cd.Namespace && status.Name == cd.Name {
+			return &relocate.Status.Clusters[i]
+		}
+	}
+	return nil
+}
+
+func clusterRelocationPhase(relocate *hivev1.ClusterRelocate, cd *hivev1.ClusterDeployment) hivev1.ClusterRelocationPhase {
+	if status := clusterRelocationStatus(relocate, cd); status != nil {
+		return status.Phase
+	}
+	return ""
+}
+
+// setClusterRelocationStatus records the relocation progress of a ClusterDeployment in the status of the
+// ClusterRelocate driving its relocation.
+func (r *ReconcileClusterRelocate) setClusterRelocationStatus(relocateName string, cd *hivev1.ClusterDeployment, phase hivev1.ClusterRelocationPhase, message string, logger log.FieldLogger) error {
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		relocate := &hivev1.ClusterRelocate{}
+		switch err := r.Get(context.Background(), client.ObjectKey{Name: relocateName}, relocate); {
+		case apierrors.IsNotFound(err):
+			return nil
+		case err != nil:
+			return err
+		}
+		status := clusterRelocationStatus(relocate, cd)
+		if status == nil {
+			relocate.Status.Clusters = append(relocate.Status.Clusters, hivev1.ClusterRelocationStatus{
+				Namespace: cd.Namespace,
+				Name:      cd.Name,
+			})
+			status = &relocate.Status.Clusters[len(relocate.Status.Clusters)-1]
+		}
+		if status.Phase == phase && status.Message == message {
+			return nil
+		}
+		if status.Phase != phase {
+			status.Phase = phase
+			status.LastTransitionTime = metav1.Now()
+		}
+		status.Message = message
+		return r.Status().Update(context.Background(), relocate)
+	})
+	if err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not update clusterrelocate status")
+		return errors.Wrap(err, "could not update clusterrelocate status")
+	}
+	return nil
+}
+
 func recordMetricForAbortedRelocate(abortedRelocate, abortedReason string) {
 	if abortedReason == "" {
 		return
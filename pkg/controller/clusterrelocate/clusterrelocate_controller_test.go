@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
@@ -12,21 +13,27 @@ import (
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/remoteclient"
 	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
 	testassert "github.com/openshift/hive/pkg/test/assert"
 	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testcdc "github.com/openshift/hive/pkg/test/clusterdeploymentcustomization"
 	testcr "github.com/openshift/hive/pkg/test/clusterrelocate"
+	testcs "github.com/openshift/hive/pkg/test/clustersync"
 	testcm "github.com/openshift/hive/pkg/test/configmap"
 	testdnszone "github.com/openshift/hive/pkg/test/dnszone"
 	testfake "github.com/openshift/hive/pkg/test/fake"
@@ -83,6 +90,7 @@ func TestReconcileClusterRelocate_Reconcile_Movement(t *testing.T) {
 		testdnszone.WithZone("test-zone"),
 	)
 	jobBuilder := testjob.FullBuilder(namespace, "test-job", scheme)
+	cdcBuilder := testcdc.FullBuilder(namespace, "test-cdc", scheme)
 	namespaceBuilder := testnamespace.FullBuilder(namespace, scheme)
 
 	cases := []struct {
@@ -804,6 +812,41 @@ func TestReconcileClusterRelocate_Reconcile_Movement(t *testing.T) {
 				),
 			},
 		},
+		{
+			name: "additional resources",
+			cd: cdBuilder.Build(testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+				Type:   hivev1.RelocationFailedCondition,
+				Status: corev1.ConditionUnknown,
+			})),
+			srcResources: []runtime.Object{
+				crBuilder.Build(
+					testcr.WithAdditionalResources("hive.openshift.io/v1", "ClusterDeploymentCustomization", labelKey, labelValue),
+				),
+				cdcBuilder.Build(
+					testcdc.Generic(testgeneric.WithLabel(labelKey, labelValue)),
+					testcdc.WithPatch("/metadata/name", "replace", "test"),
+					testcdc.Available(),
+				),
+				cdcBuilder.Build(
+					testcdc.Generic(testgeneric.WithName("test-cdc-unselected")),
+				),
+			},
+			expectedResources: []client.Object{
+				namespaceBuilder.Build(),
+				cdBuilder.Build(
+					testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateIncoming)),
+				),
+				cdcBuilder.Build(
+					testcdc.Generic(testgeneric.WithLabel(labelKey, labelValue)),
+					testcdc.WithPatch("/metadata/name", "replace", "test"),
+				),
+			},
+			unexpectedResources: []client.Object{
+				cdcBuilder.Build(
+					testcdc.Generic(testgeneric.WithName("test-cdc-unselected")),
+				),
+			},
+		},
 		{
 			name: "no match",
 			cd:   cdBuilder.Build(),
@@ -1255,6 +1298,310 @@ func TestReconcileClusterRelocate_Reconcile_RelocateStatus(t *testing.T) {
 	}
 }
 
+func TestReconcileClusterRelocate_Reconcile_Verification(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.DebugLevel)
+
+	scheme := scheme.GetScheme()
+
+	cdBuilder := testcd.FullBuilder(namespace, cdName, scheme).GenericOptions(
+		testgeneric.WithLabel(labelKey, labelValue),
+	).Options(
+		func(cd *hivev1.ClusterDeployment) { cd.Spec.ManageDNS = true },
+		testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.RelocationFailedCondition,
+			Status: corev1.ConditionUnknown,
+		}),
+	)
+	crBuilder := testcr.FullBuilder(crName, scheme).Options(
+		testcr.WithKubeconfigSecret(kubeconfigNamespace, kubeconfigName),
+		testcr.WithClusterDeploymentSelector(labelKey, labelValue),
+		testcr.WithVerification(time.Hour),
+	)
+	dnsZoneBuilder := testdnszone.FullBuilder(namespace, controllerutils.DNSZoneName(cdName), scheme)
+	mpBuilder := testmp.FullBuilder(namespace, "test-pool", cdName, scheme)
+	csBuilder := testcs.FullBuilder(namespace, cdName, scheme)
+	reachable := testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+		Type:   hivev1.UnreachableCondition,
+		Status: corev1.ConditionFalse,
+	})
+	syncing := testcs.WithCondition(hiveintv1alpha1.ClusterSyncCondition{
+		Type:   hiveintv1alpha1.ClusterSyncFailed,
+		Status: corev1.ConditionFalse,
+	})
+
+	cases := []struct {
+		name                   string
+		cd                     *hivev1.ClusterDeployment
+		cr                     *hivev1.ClusterRelocate
+		destResources          []runtime.Object
+		expectDeleted          bool
+		expectRequeue          bool
+		expectedRelocateStatus hivev1.RelocateStatus
+		expectedReason         string
+		expectedPhase          hivev1.ClusterRelocationPhase
+		expectDestCD           bool
+	}{
+		{
+			name:                   "fresh clusterdeployment",
+			cd:                     cdBuilder.Build(),
+			cr:                     crBuilder.Build(),
+			expectRequeue:          true,
+			expectedRelocateStatus: hivev1.RelocateOutgoing,
+			expectedPhase:          hivev1.ClusterRelocationPhaseVerifying,
+			expectDestCD:           true,
+		},
+		{
+			name: "destination not syncing",
+			cd:   cdBuilder.Build(testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateOutgoing))),
+			cr:   crBuilder.Build(testcr.WithClusterStatus(namespace, cdName, hivev1.ClusterRelocationPhaseVerifying, time.Now())),
+			destResources: []runtime.Object{
+				cdBuilder.Build(reachable),
+				csBuilder.Build(testcs.WithCondition(hiveintv1alpha1.ClusterSyncCondition{
+					Type:   hiveintv1alpha1.ClusterSyncFailed,
+					Status: corev1.ConditionTrue,
+				})),
+			},
+			expectRequeue:          true,
+			expectedRelocateStatus: hivev1.RelocateOutgoing,
+			expectedPhase:          hivev1.ClusterRelocationPhaseVerifying,
+			expectDestCD:           true,
+		},
+		{
+			name: "destination took ownership",
+			cd:   cdBuilder.Build(testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateOutgoing))),
+			cr:   crBuilder.Build(testcr.WithClusterStatus(namespace, cdName, hivev1.ClusterRelocationPhaseVerifying, time.Now())),
+			destResources: []runtime.Object{
+				cdBuilder.Build(reachable),
+				csBuilder.Build(syncing),
+			},
+			expectDeleted: true,
+			expectedPhase: hivev1.ClusterRelocationPhaseCompleted,
+			expectDestCD:  true,
+		},
+		{
+			name: "verification timed out",
+			cd:   cdBuilder.Build(testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateOutgoing))),
+			cr: crBuilder.Build(testcr.WithClusterStatus(namespace, cdName, hivev1.ClusterRelocationPhaseVerifying,
+				time.Now().Add(-2*time.Hour))),
+			destResources: []runtime.Object{
+				cdBuilder.Build(testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateIncoming))),
+				dnsZoneBuilder.Build(),
+				mpBuilder.Build(),
+			},
+			expectedReason: "RolledBack",
+			expectedPhase:  hivev1.ClusterRelocationPhaseRolledBack,
+		},
+		{
+			name: "rollback requested",
+			cd:   cdBuilder.Build(testcd.Generic(withRelocateAnnotation(crName, hivev1.RelocateOutgoing))),
+			cr: crBuilder.Build(
+				testcr.WithRollback(),
+				testcr.WithClusterStatus(namespace, cdName, hivev1.ClusterRelocationPhaseVerifying, time.Now()),
+			),
+			destResources: []runtime.Object{
+				cdBuilder.Build(reachable),
+				csBuilder.Build(syncing),
+				dnsZoneBuilder.Build(),
+				mpBuilder.Build(),
+			},
+			expectedReason: "RolledBack",
+			expectedPhase:  hivev1.ClusterRelocationPhaseRolledBack,
+		},
+		{
+			name: "rollback requested before relocating",
+			cd:   cdBuilder.Build(),
+			cr:   crBuilder.Build(testcr.WithRollback()),
+		},
+		{
+			name: "rolled back",
+			cd:   cdBuilder.Build(),
+			cr: crBuilder.Build(testcr.WithClusterStatus(namespace, cdName, hivev1.ClusterRelocationPhaseRolledBack,
+				time.Now())),
+			expectedPhase: hivev1.ClusterRelocationPhaseRolledBack,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeconfigSecret := testsecret.FullBuilder(kubeconfigNamespace, "test-kubeconfig", scheme).Build(
+				testsecret.WithDataKeyValue("kubeconfig", []byte("some-kubeconfig-data")),
+			)
+			srcResources := []runtime.Object{tc.cd, tc.cr, kubeconfigSecret}
+			if _, relocateStatus, _ := controllerutils.IsRelocating(tc.cd); relocateStatus != "" {
+				srcResources = append(srcResources, dnsZoneBuilder.Build(testdnszone.Generic(withRelocateAnnotation(crName, relocateStatus))))
+			} else {
+				srcResources = append(srcResources, dnsZoneBuilder.Build())
+			}
+			srcClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(srcResources...).Build()
+			destClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(tc.destResources...).Build()
+
+			mockCtrl := gomock.NewController(t)
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			mockRemoteClientBuilder.EXPECT().Build().Return(destClient, nil).AnyTimes()
+
+			reconciler := &ReconcileClusterRelocate{
+				Client: srcClient,
+				logger: logger,
+				remoteClusterAPIClientBuilder: func(secret *corev1.Secret, cn hivev1.ControllerName) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+			}
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      cdName,
+					Namespace: namespace,
+				},
+			})
+			require.NoError(t, err, "unexpected error during reconcile")
+			assert.Equal(t, tc.expectRequeue, result.RequeueAfter > 0, "unexpected requeue")
+
+			cr := &hivev1.ClusterRelocate{}
+			require.NoError(t, srcClient.Get(context.Background(), client.ObjectKey{Name: crName}, cr), "unexpected error fetching clusterrelocate")
+			if tc.expectedPhase != "" {
+				if assert.Len(t, cr.Status.Clusters, 1, "unexpected cluster statuses") {
+					assert.Equal(t, tc.expectedPhase, cr.Status.Clusters[0].Phase, "unexpected relocation phase")
+				}
+			} else {
+				assert.Empty(t, cr.Status.Clusters, "unexpected cluster statuses")
+			}
+
+			destCD := &hivev1.ClusterDeployment{}
+			err = destClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: cdName}, destCD)
+			if tc.expectDestCD {
+				assert.NoError(t, err, "expected clusterdeployment in destination cluster")
+			} else {
+				assert.True(t, apierrors.IsNotFound(err), "unexpected clusterdeployment in destination cluster")
+			}
+			if tc.expectedPhase == hivev1.ClusterRelocationPhaseRolledBack && len(tc.destResources) > 0 {
+				err = destClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: controllerutils.DNSZoneName(cdName)}, &hivev1.DNSZone{})
+				assert.True(t, apierrors.IsNotFound(err), "expected dnszone to be deleted from destination cluster")
+				err = destClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: mpBuilder.Build().Name}, &hivev1.MachinePool{})
+				assert.True(t, apierrors.IsNotFound(err), "expected machinepool to be deleted from destination cluster")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = srcClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: cdName}, cd)
+			if tc.expectDeleted {
+				assert.True(t, apierrors.IsNotFound(err), "expected clusterdeployment to be deleted")
+				return
+			}
+			require.NoError(t, err, "unexpected error fetching clusterdeployment")
+			if tc.expectedRelocateStatus != "" {
+				assert.Equal(t, fmt.Sprintf("%s/%s", crName, tc.expectedRelocateStatus), cd.Annotations[constants.RelocateAnnotation], "unexpected relocate annotation on clusterdeployment")
+			} else {
+				assert.NotContains(t, cd.Annotations, constants.RelocateAnnotation, "unexpected relocate annotation on clusterdeployment")
+			}
+			if tc.expectedReason != "" {
+				cond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.RelocationFailedCondition)
+				if assert.NotNil(t, cond, "missing relocating condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					assert.Equal(t, tc.expectedReason, cond.Reason, "unexpected condition reason")
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileClusterRelocate_Reconcile_AdditionalResourcesForbidden(t *testing.T) {
+	logger := log.New()
+	logger.SetLevel(log.DebugLevel)
+
+	scheme := scheme.GetScheme()
+	forbidden := apierrors.NewForbidden(
+		schema.GroupResource{Group: "hive.openshift.io", Resource: "clusterdeploymentcustomizations"}, "", fmt.Errorf("RBAC denied"))
+
+	cases := []struct {
+		name            string
+		srcInterceptor  interceptor.Funcs
+		destInterceptor interceptor.Funcs
+		expectedMessage string
+	}{
+		{
+			name: "forbidden in source",
+			srcInterceptor: interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					if _, ok := list.(*unstructured.UnstructuredList); ok {
+						return forbidden
+					}
+					return c.List(ctx, list, opts...)
+				},
+			},
+			expectedMessage: "the hive-controllers service account is not allowed to list ClusterDeploymentCustomization (hive.openshift.io/v1) resources",
+		},
+		{
+			name: "forbidden in destination",
+			destInterceptor: interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if _, ok := obj.(*unstructured.Unstructured); ok {
+						return forbidden
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			},
+			expectedMessage: "the destination kubeconfig is not allowed to copy ClusterDeploymentCustomization (hive.openshift.io/v1) resources",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cd := testcd.FullBuilder(namespace, cdName, scheme).GenericOptions(
+				testgeneric.WithLabel(labelKey, labelValue),
+			).Build(testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+				Type:   hivev1.RelocationFailedCondition,
+				Status: corev1.ConditionUnknown,
+			}))
+			cr := testcr.FullBuilder(crName, scheme).Build(
+				testcr.WithKubeconfigSecret(kubeconfigNamespace, kubeconfigName),
+				testcr.WithClusterDeploymentSelector(labelKey, labelValue),
+				testcr.WithAdditionalResources("hive.openshift.io/v1", "ClusterDeploymentCustomization", labelKey, labelValue),
+			)
+			cdc := testcdc.FullBuilder(namespace, "test-cdc", scheme).Build(
+				testcdc.Generic(testgeneric.WithLabel(labelKey, labelValue)),
+			)
+			kubeconfigSecret := testsecret.FullBuilder(kubeconfigNamespace, kubeconfigName, scheme).Build(
+				testsecret.WithDataKeyValue("kubeconfig", []byte("some-kubeconfig-data")),
+			)
+			srcClient := testfake.NewFakeClientBuilder().
+				WithRuntimeObjects(cd, cr, cdc, kubeconfigSecret).
+				WithInterceptorFuncs(tc.srcInterceptor).
+				Build()
+			destClient := testfake.NewFakeClientBuilder().WithInterceptorFuncs(tc.destInterceptor).Build()
+
+			mockCtrl := gomock.NewController(t)
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			mockRemoteClientBuilder.EXPECT().Build().Return(destClient, nil).AnyTimes()
+
+			reconciler := &ReconcileClusterRelocate{
+				Client: srcClient,
+				logger: logger,
+				remoteClusterAPIClientBuilder: func(*corev1.Secret, hivev1.ControllerName) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+			}
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: cdName, Namespace: namespace},
+			})
+			require.Error(t, err, "expected an error copying additional resources")
+			assert.True(t, apierrors.IsForbidden(err), "expected the error to be forbidden")
+
+			updatedCD := &hivev1.ClusterDeployment{}
+			require.NoError(t, srcClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: cdName}, updatedCD))
+			cond := controllerutils.FindCondition(updatedCD.Status.Conditions, hivev1.RelocationFailedCondition)
+			if assert.NotNil(t, cond, "missing relocation failed condition") {
+				assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+				assert.Equal(t, additionalResourcesForbiddenReason, cond.Reason, "unexpected condition reason")
+				assert.Contains(t, cond.Message, tc.expectedMessage, "unexpected condition message")
+			}
+
+			updatedCR := &hivev1.ClusterRelocate{}
+			require.NoError(t, srcClient.Get(context.Background(), client.ObjectKey{Name: crName}, updatedCR))
+			if assert.Len(t, updatedCR.Status.Clusters, 1, "expected the status of the cluster") {
+				assert.Equal(t, hivev1.ClusterRelocationPhaseFailed, updatedCR.Status.Clusters[0].Phase, "unexpected phase")
+				assert.Contains(t, updatedCR.Status.Clusters[0].Message, tc.expectedMessage, "unexpected status message")
+			}
+		})
+	}
+}
+
 func withRelocateAnnotation(clusterRelocateName string, status hivev1.RelocateStatus) testgeneric.Option {
 	return testgeneric.WithAnnotation(
 		constants.RelocateAnnotation,
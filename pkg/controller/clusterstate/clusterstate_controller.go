@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	k8slabels "github.com/openshift/hive/pkg/util/labels"
@@ -22,12 +23,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	configv1 "github.com/openshift/api/config/v1"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/apis/hive/v1/metricsconfig"
 	"github.com/openshift/hive/pkg/constants"
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
@@ -39,6 +42,28 @@ const (
 	statusUpdateInterval = 10 * time.Minute
 )
 
+var (
+	metricOperatorConditionTransitions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_cluster_operator_condition_transitions_total",
+		Help: "Total number of transitions of the conditions of cluster operators, by the status transitioned to.",
+	}, []string{"operator", "condition", "status"})
+	metricOperatorConditionUnsteadyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "hive_cluster_operator_condition_unsteady_duration_seconds",
+		Help:    "Time cluster operator conditions spent out of their steady status, observed when they return to it.",
+		Buckets: prometheus.ExponentialBuckets(60, 4, 8),
+	}, []string{"operator", "condition", "status"})
+	metricOperatorConditionUnsteadySince = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_cluster_operator_condition_unsteady_since_timestamp_seconds",
+		Help: "Time since which a cluster operator condition has been out of its steady status, in seconds since the epoch.",
+	}, []string{"namespace", "cluster_deployment", "operator", "condition", "status"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricOperatorConditionTransitions)
+	metrics.Registry.MustRegister(metricOperatorConditionUnsteadyDuration)
+	metrics.Registry.MustRegister(metricOperatorConditionUnsteadySince)
+}
+
 // Add creates a new ClusterState controller and adds it to the manager with default RBAC.
 func Add(mgr manager.Manager) error {
 	logger := log.WithField("controller", ControllerName)
@@ -47,16 +72,23 @@ func Add(mgr manager.Manager) error {
 		logger.WithError(err).Error("could not get controller configurations")
 		return err
 	}
-	return AddToManager(mgr, NewReconciler(mgr, clientRateLimiter), concurrentReconciles, queueRateLimiter)
+	mConfig, err := hivemetrics.ReadMetricsConfig()
+	if err != nil {
+		logger.WithError(err).Error("error reading metrics config")
+		return err
+	}
+	return AddToManager(mgr, NewReconciler(mgr, clientRateLimiter, mConfig), concurrentReconciles, queueRateLimiter)
 }
 
 // NewReconciler returns a new reconcile.Reconciler
-func NewReconciler(mgr manager.Manager, rateLimiter flowcontrol.RateLimiter) reconcile.Reconciler {
+func NewReconciler(mgr manager.Manager, rateLimiter flowcontrol.RateLimiter, mConfig *metricsconfig.MetricsConfig) reconcile.Reconciler {
 	r := &ReconcileClusterState{
 		Client:       controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &rateLimiter),
 		scheme:       mgr.GetScheme(),
 		logger:       log.WithField("controller", ControllerName),
 		updateStatus: updateClusterStateStatus,
+		reportUnsteadySince: hivemetrics.IsClusterDeploymentMetricEnabled(
+			mConfig, metricsconfig.ClusterOperatorConditionUnsteadySince),
 	}
 	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
 		return remoteclient.NewBuilder(r.Client, cd, ControllerName)
@@ -98,6 +130,10 @@ type ReconcileClusterState struct {
 
 	// updateStatus updates a given cluster state's status, exposed for testing
 	updateStatus func(client.Client, *hivev1.ClusterState) error
+
+	// reportUnsteadySince is whether the admin opted into metricOperatorConditionUnsteadySince, which is labelled
+	// with the namespace and name of each ClusterDeployment.
+	reportUnsteadySince bool
 }
 
 // Reconcile ensures that a given ClusterState resource exists and reflects the state of cluster operators from its target cluster
@@ -115,6 +151,7 @@ func (r *ReconcileClusterState) Reconcile(ctx context.Context, request reconcile
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
 			logger.Debug("cluster deployment not found")
+			clearUnsteadySince(request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -222,7 +259,13 @@ func (r *ReconcileClusterState) syncOperatorStates(operators []configv1.ClusterO
 			Conditions: clusterOperator.Status.Conditions,
 		}
 	}
+	if r.reportUnsteadySince {
+		setUnsteadySince(st.Namespace, st.Name, operatorStates)
+	}
 	if operatorStatesChanged(logger, st.Status.ClusterOperators, operatorStates) {
+		transitions := conditionTransitions(st.Status.ClusterOperators, operatorStates)
+		observeTransitions(st.Status.ClusterOperators, transitions)
+		st.Status.History = appendHistory(st.Status.History, transitions)
 		st.Status.ClusterOperators = operatorStates
 		now := metav1.Now()
 		st.Status.LastUpdated = &now
@@ -238,6 +281,45 @@ func (r *ReconcileClusterState) syncOperatorStates(operators []configv1.ClusterO
 	}, nil
 }
 
+// observeTransitions records the transitions of the conditions of the cluster operators in the metrics.
+func observeTransitions(existing []hivev1.ClusterOperatorState, transitions []hivev1.ClusterOperatorConditionTransition) {
+	for _, transition := range transitions {
+		metricOperatorConditionTransitions.WithLabelValues(transition.Operator, string(transition.Type), string(transition.Status)).Inc()
+		if !IsSteady(transition.Type, transition.Status) {
+			continue
+		}
+		i := indexOfOperatorState(existing, transition.Operator)
+		if i < 0 {
+			continue
+		}
+		j := indexOfCondition(existing[i].Conditions, string(transition.Type))
+		if j < 0 {
+			continue
+		}
+		previous := existing[i].Conditions[j]
+		metricOperatorConditionUnsteadyDuration.WithLabelValues(transition.Operator, string(transition.Type), string(previous.Status)).
+			Observe(transition.LastTransitionTime.Sub(previous.LastTransitionTime.Time).Seconds())
+	}
+}
+
+// setUnsteadySince reports the cluster operator conditions of a cluster that are out of their steady status.
+func setUnsteadySince(namespace, name string, operatorStates []hivev1.ClusterOperatorState) {
+	clearUnsteadySince(namespace, name)
+	for _, operator := range operatorStates {
+		for _, condition := range operator.Conditions {
+			if IsSteady(condition.Type, condition.Status) {
+				continue
+			}
+			metricOperatorConditionUnsteadySince.WithLabelValues(namespace, name, operator.Name, string(condition.Type), string(condition.Status)).
+				Set(float64(condition.LastTransitionTime.Unix()))
+		}
+	}
+}
+
+func clearUnsteadySince(namespace, name string) {
+	metricOperatorConditionUnsteadySince.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "cluster_deployment": name})
+}
+
 func operatorStatesChanged(logger log.FieldLogger, existing, updated []hivev1.ClusterOperatorState) bool {
 	changed := false
 	existingNames := sets.NewString()
@@ -2,10 +2,12 @@ package clusterstate
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -83,6 +85,23 @@ func TestClusterStateReconcile(t *testing.T) {
 			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
 				st := cs(t, c)
 				validateStatus(t, st.Status, co("a"), co("b"), co("c"))
+				assert.Empty(t, st.Status.History, "unexpected history")
+			},
+		},
+		{
+			name: "unsteady operator first seen",
+			existing: []runtime.Object{
+				testClusterState(),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{co("a"), uco("b")},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateHistory(t, st.Status.History,
+					transition("b", configv1.OperatorAvailable, configv1.ConditionFalse),
+					transition("b", configv1.OperatorDegraded, configv1.ConditionTrue),
+				)
 			},
 		},
 		{
@@ -111,6 +130,59 @@ func TestClusterStateReconcile(t *testing.T) {
 			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
 				st := cs(t, c)
 				validateStatus(t, st.Status, co("a"), co("b"), uco("c"))
+				validateHistory(t, st.Status.History,
+					transition("c", configv1.OperatorAvailable, configv1.ConditionFalse),
+					transition("c", configv1.OperatorDegraded, configv1.ConditionTrue),
+				)
+			},
+		},
+		{
+			name: "recovered state",
+			existing: []runtime.Object{
+				testClusterStateWithHistory(
+					testClusterStateWithStatus(co("a"), uco("b")),
+					transition("b", configv1.OperatorAvailable, configv1.ConditionFalse),
+					transition("b", configv1.OperatorDegraded, configv1.ConditionTrue),
+				),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{co("a"), co("b")},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				validateStatus(t, st.Status, co("a"), co("b"))
+				validateHistory(t, st.Status.History,
+					transition("b", configv1.OperatorAvailable, configv1.ConditionFalse),
+					transition("b", configv1.OperatorDegraded, configv1.ConditionTrue),
+					transition("b", configv1.OperatorAvailable, configv1.ConditionTrue),
+					transition("b", configv1.OperatorDegraded, configv1.ConditionFalse),
+				)
+			},
+		},
+		{
+			name: "bounded history",
+			existing: []runtime.Object{
+				testClusterStateWithHistory(
+					testClusterStateWithStatus(co("a")),
+					func() []hivev1.ClusterOperatorConditionTransition {
+						history := make([]hivev1.ClusterOperatorConditionTransition, maxHistoryLength)
+						for i := range history {
+							history[i] = transition("old", configv1.OperatorDegraded, configv1.ConditionTrue)
+						}
+						return history
+					}()...,
+				),
+				testClusterDeployment(),
+				testKubeconfigSecret(),
+			},
+			remote: []runtime.Object{uco("a")},
+			validate: func(t *testing.T, c client.Client, result reconcile.Result) {
+				st := cs(t, c)
+				require.Len(t, st.Status.History, maxHistoryLength, "unexpected history length")
+				validateHistory(t, st.Status.History[maxHistoryLength-2:],
+					transition("a", configv1.OperatorAvailable, configv1.ConditionFalse),
+					transition("a", configv1.OperatorDegraded, configv1.ConditionTrue),
+				)
 			},
 		},
 		{
@@ -196,6 +268,40 @@ func TestClusterStateReconcile(t *testing.T) {
 	}
 }
 
+func TestUnsteadySinceMetricOptIn(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enabled=%t", enabled), func(t *testing.T) {
+			metricOperatorConditionUnsteadySince.Reset()
+			defer metricOperatorConditionUnsteadySince.Reset()
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(
+				testClusterState(), testClusterDeployment(), testKubeconfigSecret()).Build()
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(gomock.NewController(t))
+			mockRemoteClientBuilder.EXPECT().Build().Return(
+				testfake.NewFakeClientBuilder().WithRuntimeObjects(unavailableClusterOperator("a")).Build(), nil)
+			rcd := &ReconcileClusterState{
+				Client:                        fakeClient,
+				scheme:                        scheme.GetScheme(),
+				logger:                        log.WithField("controller", "clusterState"),
+				remoteClusterAPIClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder { return mockRemoteClientBuilder },
+				updateStatus:                  updateClusterStateStatus,
+				reportUnsteadySince:           enabled,
+			}
+
+			_, err := rcd.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace},
+			})
+			require.NoError(t, err)
+
+			expected := 0
+			if enabled {
+				// Available False and Degraded True
+				expected = 2
+			}
+			assert.Equal(t, expected, testutil.CollectAndCount(metricOperatorConditionUnsteadySince), "unexpected number of series")
+		})
+	}
+}
+
 func testClusterState() *hivev1.ClusterState {
 	return &hivev1.ClusterState{
 		ObjectMeta: metav1.ObjectMeta{
@@ -216,6 +322,20 @@ func testClusterStateWithStatus(operators ...*configv1.ClusterOperator) *hivev1.
 	return cs
 }
 
+func testClusterStateWithHistory(cs *hivev1.ClusterState, history ...hivev1.ClusterOperatorConditionTransition) *hivev1.ClusterState {
+	cs.Status.History = history
+	return cs
+}
+
+func transition(operator string, ctype configv1.ClusterStatusConditionType, status configv1.ConditionStatus) hivev1.ClusterOperatorConditionTransition {
+	return hivev1.ClusterOperatorConditionTransition{
+		Operator: operator,
+		Type:     ctype,
+		Status:   status,
+		Reason:   "Available",
+	}
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	return &hivev1.ClusterDeployment{
 		ObjectMeta: metav1.ObjectMeta{
@@ -318,3 +438,14 @@ func validateStatus(t *testing.T, status hivev1.ClusterStateStatus, operators ..
 		assert.ElementsMatch(t, status.ClusterOperators[i].Conditions, operators[i].Status.Conditions, "operator conditions don't match")
 	}
 }
+
+func validateHistory(t *testing.T, history []hivev1.ClusterOperatorConditionTransition, expected ...hivev1.ClusterOperatorConditionTransition) {
+	if !assert.Len(t, history, len(expected), "unexpected history length") {
+		return
+	}
+	for i := range history {
+		assert.Equal(t, expected[i].Operator, history[i].Operator, "unexpected operator of transition %d", i)
+		assert.Equal(t, expected[i].Type, history[i].Type, "unexpected condition of transition %d", i)
+		assert.Equal(t, expected[i].Status, history[i].Status, "unexpected status of transition %d", i)
+	}
+}
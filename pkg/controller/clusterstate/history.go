@@ -0,0 +1,164 @@
+package clusterstate
+
+import (
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// maxHistoryLength is the maximum number of condition transitions kept in the history of a ClusterState, shared by
+// all the conditions of all the cluster operators.
+const maxHistoryLength = 100
+
+// steadyStatus is the status of the standard cluster operator conditions when the operator is healthy and at rest.
+var steadyStatus = map[configv1.ClusterStatusConditionType]configv1.ConditionStatus{
+	configv1.OperatorAvailable:   configv1.ConditionTrue,
+	configv1.OperatorDegraded:    configv1.ConditionFalse,
+	configv1.OperatorProgressing: configv1.ConditionFalse,
+	configv1.OperatorUpgradeable: configv1.ConditionTrue,
+}
+
+// IsSteady returns whether a condition of a cluster operator is in its steady status. Conditions of non-standard types
+// are always steady.
+func IsSteady(ctype configv1.ClusterStatusConditionType, status configv1.ConditionStatus) bool {
+	steady, ok := steadyStatus[ctype]
+	return !ok || status == steady
+}
+
+// conditionTransitions returns the transitions of the conditions of the cluster operators between the existing and
+// the updated operator states. A condition without an existing status is compared to its steady status.
+func conditionTransitions(existing, updated []hivev1.ClusterOperatorState) []hivev1.ClusterOperatorConditionTransition {
+	var transitions []hivev1.ClusterOperatorConditionTransition
+	for _, operator := range updated {
+		var existingConditions []configv1.ClusterOperatorStatusCondition
+		if i := indexOfOperatorState(existing, operator.Name); i >= 0 {
+			existingConditions = existing[i].Conditions
+		}
+		for _, condition := range operator.Conditions {
+			if i := indexOfCondition(existingConditions, string(condition.Type)); i >= 0 {
+				if existingConditions[i].Status == condition.Status {
+					continue
+				}
+			} else if IsSteady(condition.Type, condition.Status) {
+				continue
+			}
+			transitions = append(transitions, hivev1.ClusterOperatorConditionTransition{
+				Operator:           operator.Name,
+				Type:               condition.Type,
+				Status:             condition.Status,
+				Reason:             condition.Reason,
+				LastTransitionTime: condition.LastTransitionTime,
+			})
+		}
+	}
+	return transitions
+}
+
+// appendHistory appends transitions to the history. Beyond maxHistoryLength, it drops the oldest transition of the
+// condition with the most transitions, so that a flapping condition does not evict the history of the others.
+func appendHistory(history, transitions []hivev1.ClusterOperatorConditionTransition) []hivev1.ClusterOperatorConditionTransition {
+	history = append(append([]hivev1.ClusterOperatorConditionTransition(nil), history...), transitions...)
+	type key struct {
+		operator string
+		ctype    configv1.ClusterStatusConditionType
+	}
+	counts := map[key]int{}
+	for _, transition := range history {
+		counts[key{operator: transition.Operator, ctype: transition.Type}]++
+	}
+	for len(history) > maxHistoryLength {
+		// The history is oldest first, so the first transition of the condition with the most transitions is the
+		// oldest one, and ties go to the condition whose oldest transition is the oldest.
+		drop := 0
+		for i, transition := range history {
+			k := key{operator: transition.Operator, ctype: transition.Type}
+			if counts[k] > counts[key{operator: history[drop].Operator, ctype: history[drop].Type}] {
+				drop = i
+			}
+		}
+		counts[key{operator: history[drop].Operator, ctype: history[drop].Type}]--
+		history = append(history[:drop], history[drop+1:]...)
+	}
+	return history
+}
+
+// ConditionSummary summarizes the history of a condition of a cluster operator over a period of time.
+type ConditionSummary struct {
+	// Operator is the name of the cluster operator
+	Operator string
+	// Type is the type of the condition
+	Type configv1.ClusterStatusConditionType
+	// Status is the last status of the condition
+	Status configv1.ConditionStatus
+	// UnsteadyDuration is how long the condition was not in its steady status during the period
+	UnsteadyDuration time.Duration
+	// Transitions is the number of transitions of the condition during the period
+	Transitions int
+}
+
+// SummarizeHistory summarizes the history of the conditions of the cluster operators between since and now. Before
+// its first transition in the history, a condition is assumed to be in its steady status. Only conditions that
+// transitioned, or were not in their steady status, during the period are returned, sorted by operator and type.
+func SummarizeHistory(history []hivev1.ClusterOperatorConditionTransition, since, now time.Time) []ConditionSummary {
+	type key struct {
+		operator string
+		ctype    configv1.ClusterStatusConditionType
+	}
+	type timeline struct {
+		summary ConditionSummary
+		from    time.Time
+	}
+	timelines := map[key]*timeline{}
+	// overlap returns how much of [from, to) is within [since, now).
+	overlap := func(from, to time.Time) time.Duration {
+		if from.Before(since) {
+			from = since
+		}
+		if to.After(now) {
+			to = now
+		}
+		if !to.After(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+	for _, transition := range history {
+		k := key{operator: transition.Operator, ctype: transition.Type}
+		tl, ok := timelines[k]
+		if !ok {
+			tl = &timeline{summary: ConditionSummary{
+				Operator: transition.Operator,
+				Type:     transition.Type,
+				Status:   steadyStatus[transition.Type],
+			}}
+			timelines[k] = tl
+		}
+		at := transition.LastTransitionTime.Time
+		if ok && !IsSteady(tl.summary.Type, tl.summary.Status) {
+			tl.summary.UnsteadyDuration += overlap(tl.from, at)
+		}
+		if !at.Before(since) && !at.After(now) {
+			tl.summary.Transitions++
+		}
+		tl.summary.Status = transition.Status
+		tl.from = at
+	}
+
+	var summaries []ConditionSummary
+	for _, tl := range timelines {
+		if !IsSteady(tl.summary.Type, tl.summary.Status) {
+			tl.summary.UnsteadyDuration += overlap(tl.from, now)
+		}
+		if tl.summary.Transitions > 0 || tl.summary.UnsteadyDuration > 0 {
+			summaries = append(summaries, tl.summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		return a.Operator < b.Operator || (a.Operator == b.Operator && a.Type < b.Type)
+	})
+	return summaries
+}
@@ -0,0 +1,149 @@
+package clusterstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+func TestSummarizeHistory(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-7 * 24 * time.Hour)
+	at := func(operator string, ctype configv1.ClusterStatusConditionType, status configv1.ConditionStatus, ago time.Duration) hivev1.ClusterOperatorConditionTransition {
+		return hivev1.ClusterOperatorConditionTransition{
+			Operator:           operator,
+			Type:               ctype,
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-ago)),
+		}
+	}
+
+	cases := []struct {
+		name     string
+		history  []hivev1.ClusterOperatorConditionTransition
+		expected []ConditionSummary
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "degraded and recovered",
+			history: []hivev1.ClusterOperatorConditionTransition{
+				at("ingress", configv1.OperatorDegraded, configv1.ConditionTrue, 48*time.Hour),
+				at("ingress", configv1.OperatorDegraded, configv1.ConditionFalse, 45*time.Hour),
+			},
+			expected: []ConditionSummary{{
+				Operator:         "ingress",
+				Type:             configv1.OperatorDegraded,
+				Status:           configv1.ConditionFalse,
+				UnsteadyDuration: 3 * time.Hour,
+				Transitions:      2,
+			}},
+		},
+		{
+			name: "still degraded",
+			history: []hivev1.ClusterOperatorConditionTransition{
+				at("ingress", configv1.OperatorDegraded, configv1.ConditionTrue, 2*time.Hour),
+			},
+			expected: []ConditionSummary{{
+				Operator:         "ingress",
+				Type:             configv1.OperatorDegraded,
+				Status:           configv1.ConditionTrue,
+				UnsteadyDuration: 2 * time.Hour,
+				Transitions:      1,
+			}},
+		},
+		{
+			name: "degraded since before the period",
+			history: []hivev1.ClusterOperatorConditionTransition{
+				at("dns", configv1.OperatorAvailable, configv1.ConditionFalse, 10*24*time.Hour),
+				at("dns", configv1.OperatorAvailable, configv1.ConditionTrue, 6*24*time.Hour),
+			},
+			expected: []ConditionSummary{{
+				Operator:         "dns",
+				Type:             configv1.OperatorAvailable,
+				Status:           configv1.ConditionTrue,
+				UnsteadyDuration: 24 * time.Hour,
+				Transitions:      1,
+			}},
+		},
+		{
+			name: "recovered before the period",
+			history: []hivev1.ClusterOperatorConditionTransition{
+				at("dns", configv1.OperatorAvailable, configv1.ConditionFalse, 10*24*time.Hour),
+				at("dns", configv1.OperatorAvailable, configv1.ConditionTrue, 9*24*time.Hour),
+			},
+		},
+		{
+			name: "several operators",
+			history: []hivev1.ClusterOperatorConditionTransition{
+				at("ingress", configv1.OperatorProgressing, configv1.ConditionTrue, 5*time.Hour),
+				at("dns", configv1.OperatorDegraded, configv1.ConditionTrue, 4*time.Hour),
+				at("ingress", configv1.OperatorProgressing, configv1.ConditionFalse, 4*time.Hour),
+				at("dns", configv1.OperatorDegraded, configv1.ConditionFalse, 3*time.Hour),
+				at("dns", configv1.OperatorDegraded, configv1.ConditionTrue, 2*time.Hour),
+			},
+			expected: []ConditionSummary{
+				{
+					Operator:         "dns",
+					Type:             configv1.OperatorDegraded,
+					Status:           configv1.ConditionTrue,
+					UnsteadyDuration: 3 * time.Hour,
+					Transitions:      3,
+				},
+				{
+					Operator:         "ingress",
+					Type:             configv1.OperatorProgressing,
+					Status:           configv1.ConditionFalse,
+					UnsteadyDuration: time.Hour,
+					Transitions:      2,
+				},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, SummarizeHistory(tc.history, since, now))
+		})
+	}
+}
+
+func TestAppendHistory(t *testing.T) {
+	transitions := func(operator string, n int) []hivev1.ClusterOperatorConditionTransition {
+		history := make([]hivev1.ClusterOperatorConditionTransition, n)
+		for i := range history {
+			history[i] = hivev1.ClusterOperatorConditionTransition{Operator: operator, Type: configv1.OperatorDegraded}
+		}
+		return history
+	}
+	count := func(history []hivev1.ClusterOperatorConditionTransition, operator string) int {
+		n := 0
+		for _, transition := range history {
+			if transition.Operator == operator {
+				n++
+			}
+		}
+		return n
+	}
+
+	// A quiet operator's transitions come first, then a flapping operator fills the history.
+	history := appendHistory(transitions("quiet", 2), transitions("flapping", maxHistoryLength-2))
+	assert.Len(t, history, maxHistoryLength, "unexpected history length")
+
+	history = appendHistory(history, transitions("flapping", 10))
+	assert.Len(t, history, maxHistoryLength, "unexpected history length")
+	assert.Equal(t, 2, count(history, "quiet"), "expected the quiet operator's transitions to be kept")
+	assert.Equal(t, "quiet", history[0].Operator, "expected the history to stay oldest first")
+
+	history = appendHistory(history, transitions("other", 3))
+	assert.Len(t, history, maxHistoryLength, "unexpected history length")
+	assert.Equal(t, 2, count(history, "quiet"), "expected the quiet operator's transitions to be kept")
+	assert.Equal(t, 3, count(history, "other"), "expected the new operator's transitions to be kept")
+}
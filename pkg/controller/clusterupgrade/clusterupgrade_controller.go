@@ -0,0 +1,507 @@
+package clusterupgrade
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = hivev1.ClusterUpgradeControllerName
+
+	clusterVersionObjectName = "version"
+
+	// clusterVersionFailing is the ClusterVersion condition set by the cluster-version-operator when an update is
+	// failing.
+	clusterVersionFailing configv1.ClusterStatusConditionType = "Failing"
+
+	// upgradeCheckInterval is how often the progress of a plan is checked while it is not completed.
+	upgradeCheckInterval = 2 * time.Minute
+
+	pausedReason                   = "Paused"
+	upgradeFailedReason            = "UpgradeFailed"
+	clusterOperatorsFailingReason  = "ClusterOperatorsFailing"
+	outsideMaintenanceWindowReason = "OutsideMaintenanceWindow"
+	invalidMaintenanceWindowReason = "InvalidMaintenanceWindow"
+	upgradesAllowedReason          = "UpgradesAllowed"
+	allClustersUpgradedReason      = "AllClustersUpgraded"
+	clustersPendingReason          = "ClustersPending"
+)
+
+var (
+	metricClusterUpgradesStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_cluster_upgrades_started_total",
+		Help: "Total number of cluster upgrades started by ClusterUpgradePlans.",
+	}, []string{"cluster_upgrade_plan"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricClusterUpgradesStarted)
+}
+
+// Add creates a new ClusterUpgrade controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileClusterUpgrade{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		logger: logger,
+	}
+	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewBuilder(r.Client, cd, ControllerName)
+	}
+
+	c, err := controller.New("clusterupgrade-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, r.logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to ClusterUpgradePlan
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterUpgradePlan{}, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterUpgradePlan]{})); err != nil {
+		logger.WithError(err).Error("Error watching ClusterUpgradePlan")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment that change which clusters a plan upgrades. Status updates are ignored,
+	// since every plan observes the progress of its clusters periodically.
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{},
+		handler.TypedEnqueueRequestsFromMapFunc(r.clusterDeploymentHandlerFunc),
+		predicate.TypedFuncs[*hivev1.ClusterDeployment]{UpdateFunc: clusterDeploymentUpdated})); err != nil {
+		logger.WithError(err).Error("Error watching ClusterDeployment")
+		return err
+	}
+
+	return nil
+}
+
+// clusterDeploymentUpdated returns true when an update of a ClusterDeployment may change whether it is upgraded by a
+// plan: a change to its labels, its installation, or its deletion.
+func clusterDeploymentUpdated(e event.TypedUpdateEvent[*hivev1.ClusterDeployment]) bool {
+	return !reflect.DeepEqual(e.ObjectOld.Labels, e.ObjectNew.Labels) ||
+		e.ObjectOld.Spec.Installed != e.ObjectNew.Spec.Installed ||
+		(e.ObjectOld.DeletionTimestamp == nil) != (e.ObjectNew.DeletionTimestamp == nil)
+}
+
+// clusterDeploymentHandlerFunc enqueues the ClusterUpgradePlans selecting the ClusterDeployment.
+func (r *ReconcileClusterUpgrade) clusterDeploymentHandlerFunc(ctx context.Context, cd *hivev1.ClusterDeployment) (requests []reconcile.Request) {
+	plans := &hivev1.ClusterUpgradePlanList{}
+	if err := r.List(ctx, plans); err != nil {
+		r.logger.WithError(err).Log(controllerutils.LogLevel(err), "failed to list clusterupgradeplans")
+		return
+	}
+	for _, plan := range plans.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&plan.Spec.ClusterDeploymentSelector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(cd.Labels)) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: plan.Name}})
+		}
+	}
+	return
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterUpgrade{}
+
+// ReconcileClusterUpgrade upgrades the clusters selected by ClusterUpgradePlans.
+type ReconcileClusterUpgrade struct {
+	client.Client
+
+	logger log.FieldLogger
+
+	// remoteClusterAPIClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClusterAPIClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+}
+
+// pendingUpgrade is a cluster whose upgrade can be started.
+type pendingUpgrade struct {
+	status         *hivev1.ClusterUpgradeStatus
+	remoteClient   client.Client
+	clusterVersion *configv1.ClusterVersion
+}
+
+// Reconcile observes the upgrade progress of the clusters selected by a ClusterUpgradePlan, and starts the upgrade of
+// pending clusters when the plan allows it.
+func (r *ReconcileClusterUpgrade) Reconcile(ctx context.Context, request reconcile.Request) (result reconcile.Result, returnErr error) {
+	logger := controllerutils.BuildControllerLogger(ControllerName, "clusterUpgradePlan", request.NamespacedName)
+	logger.Info("reconciling cluster upgrade plan")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, logger)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	plan := &hivev1.ClusterUpgradePlan{}
+	if err := r.Get(ctx, request.NamespacedName, plan); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("cluster upgrade plan not found")
+			return reconcile.Result{}, nil
+		}
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster upgrade plan")
+		return reconcile.Result{}, err
+	}
+	if plan.DeletionTimestamp != nil {
+		logger.Debug("cluster upgrade plan is being deleted")
+		return reconcile.Result{}, nil
+	}
+	original := plan.DeepCopy()
+
+	imageSet := &hivev1.ClusterImageSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: plan.Spec.ImageSetRef.Name}, imageSet); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting cluster image set")
+		return reconcile.Result{}, err
+	}
+	releaseImage := imageSet.Spec.ReleaseImage
+	logger = logger.WithField("releaseImage", releaseImage)
+
+	selector, err := metav1.LabelSelectorAsSelector(&plan.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		logger.WithError(err).Warn("cannot parse clusterdeployment selector")
+		return reconcile.Result{}, nil
+	}
+	cds := &hivev1.ClusterDeploymentList{}
+	if err := r.List(ctx, cds, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing cluster deployments")
+		return reconcile.Result{}, err
+	}
+	sort.Slice(cds.Items, func(i, j int) bool {
+		a, b := cds.Items[i], cds.Items[j]
+		return a.Namespace < b.Namespace || (a.Namespace == b.Namespace && a.Name < b.Name)
+	})
+
+	previous := map[types.NamespacedName]hivev1.ClusterUpgradeStatus{}
+	for _, status := range plan.Status.Clusters {
+		previous[types.NamespacedName{Namespace: status.Namespace, Name: status.Name}] = status
+	}
+	// statuses and clusters are indexed alike.
+	statuses := make([]hivev1.ClusterUpgradeStatus, 0, len(cds.Items))
+	clusters := make([]*hivev1.ClusterDeployment, 0, len(cds.Items))
+	for i := range cds.Items {
+		cd := &cds.Items[i]
+		if !cd.Spec.Installed || cd.DeletionTimestamp != nil {
+			continue
+		}
+		status, ok := previous[types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}]
+		if !ok {
+			status = hivev1.ClusterUpgradeStatus{Namespace: cd.Namespace, Name: cd.Name, Phase: hivev1.ClusterUpgradePhasePending}
+		}
+		statuses = append(statuses, status)
+		clusters = append(clusters, cd)
+	}
+
+	maxConcurrent := 1
+	if plan.Spec.MaxConcurrent != nil {
+		maxConcurrent = int(*plan.Spec.MaxConcurrent)
+	}
+
+	var pending []pendingUpgrade
+	var inProgress int
+	var failed, failingOperators []string
+	// tally counts a cluster that is not pending towards the batch in progress.
+	tally := func(i int) error {
+		status := &statuses[i]
+		cd := clusters[i]
+		switch status.Phase {
+		case hivev1.ClusterUpgradePhaseUpgrading:
+			inProgress++
+		case hivev1.ClusterUpgradePhaseFailed:
+			inProgress++
+			failed = append(failed, cd.Namespace+"/"+cd.Name)
+		case hivev1.ClusterUpgradePhaseCompleted:
+			settled, err := r.checkClusterOperators(cd, status)
+			if err != nil {
+				return err
+			}
+			if !settled {
+				// The cluster keeps its place in the batch until its ClusterOperators are known to be healthy.
+				inProgress++
+			} else if status.Message != "" {
+				failingOperators = append(failingOperators, cd.Namespace+"/"+cd.Name)
+			}
+		}
+		return nil
+	}
+
+	// Observe the clusters being upgraded.
+	for i := range statuses {
+		status := &statuses[i]
+		cd := clusters[i]
+		cdLog := logger.WithField("clusterDeployment", types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name})
+		if status.Phase == hivev1.ClusterUpgradePhaseUpgrading || status.Phase == hivev1.ClusterUpgradePhaseFailed {
+			r.observeClusterVersion(cd, status, releaseImage, cdLog)
+		}
+		if status.Phase == hivev1.ClusterUpgradePhasePending {
+			continue
+		}
+		if err := tally(i); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error checking cluster operators")
+			return reconcile.Result{}, err
+		}
+	}
+
+	now := time.Now()
+	pauseReason, pauseMessage, nextWindow := pauseUpgrades(plan, failed, failingOperators, now)
+	if pauseReason == "" {
+		// Only the pending clusters needed to fill the batch are observed. The others are observed on later
+		// reconciles, once there is room for them in the batch.
+		for i := range statuses {
+			if inProgress+len(pending) >= maxConcurrent {
+				break
+			}
+			status := &statuses[i]
+			if status.Phase != hivev1.ClusterUpgradePhasePending {
+				continue
+			}
+			cd := clusters[i]
+			cdLog := logger.WithField("clusterDeployment", types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name})
+			remoteClient, cv := r.observeClusterVersion(cd, status, releaseImage, cdLog)
+			if status.Phase == hivev1.ClusterUpgradePhasePending {
+				if cv != nil {
+					pending = append(pending, pendingUpgrade{status: status, remoteClient: remoteClient, clusterVersion: cv})
+				}
+				continue
+			}
+			if err := tally(i); err != nil {
+				cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error checking cluster operators")
+				return reconcile.Result{}, err
+			}
+		}
+		// Observing pending clusters may have found failed upgrades or degraded ClusterOperators.
+		pauseReason, pauseMessage, nextWindow = pauseUpgrades(plan, failed, failingOperators, now)
+	}
+
+	if pauseReason == "" {
+		for _, p := range pending {
+			if inProgress >= maxConcurrent {
+				break
+			}
+			if r.startUpgrade(p, plan, releaseImage, logger) {
+				inProgress++
+			}
+		}
+	}
+
+	completed := len(statuses) > 0
+	for _, status := range statuses {
+		completed = completed && status.Phase == hivev1.ClusterUpgradePhaseCompleted
+	}
+	plan.Status.Clusters = statuses
+	setPlanConditions(plan, pauseReason, pauseMessage, completed)
+	if !equality.Semantic.DeepEqual(original.Status, plan.Status) {
+		if err := r.Status().Update(ctx, plan); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster upgrade plan status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	switch {
+	case completed && inProgress == 0:
+		logger.Debug("all clusters upgraded")
+		return reconcile.Result{}, nil
+	case inProgress == 0 && !nextWindow.IsZero():
+		logger.WithField("nextWindow", nextWindow).Debug("waiting for the next maintenance window")
+		return reconcile.Result{RequeueAfter: time.Until(nextWindow)}, nil
+	default:
+		return reconcile.Result{RequeueAfter: upgradeCheckInterval}, nil
+	}
+}
+
+// pauseUpgrades returns the reason and message for not starting cluster upgrades, if any, and the start of the next
+// maintenance window when upgrades are paused outside of maintenance windows.
+func pauseUpgrades(plan *hivev1.ClusterUpgradePlan, failed, failingOperators []string, now time.Time) (reason, message string, nextWindow time.Time) {
+	switch {
+	case plan.Spec.Paused:
+		reason, message = pausedReason, "The plan is paused"
+	case len(failed) > 0:
+		reason, message = upgradeFailedReason, fmt.Sprintf("Upgrade failed for clusters: %s", strings.Join(failed, ", "))
+	case len(failingOperators) > 0:
+		reason, message = clusterOperatorsFailingReason,
+			fmt.Sprintf("Upgraded clusters have degraded or unavailable ClusterOperators: %s", strings.Join(failingOperators, ", "))
+	case len(plan.Spec.MaintenanceWindows) > 0:
+		active, _, next, err := controllerutils.EvaluateSchedule(plan.Spec.MaintenanceWindows, plan.Spec.TimeZone, now)
+		switch {
+		case err != nil:
+			reason, message = invalidMaintenanceWindowReason, err.Error()
+		case !active:
+			reason, message = outsideMaintenanceWindowReason, "Upgrades only start during maintenance windows"
+			nextWindow = next
+		}
+	}
+	return
+}
+
+// observeClusterVersion updates the status of a cluster that has not completed its upgrade from its ClusterVersion.
+// It returns the remote client and the ClusterVersion of the cluster, if the cluster could be reached.
+func (r *ReconcileClusterUpgrade) observeClusterVersion(cd *hivev1.ClusterDeployment, status *hivev1.ClusterUpgradeStatus, releaseImage string, logger log.FieldLogger) (client.Client, *configv1.ClusterVersion) {
+	if cd.Spec.PowerState == hivev1.ClusterPowerStateHibernating {
+		status.Message = "Cluster is hibernating"
+		return nil, nil
+	}
+	remoteClient, unreachable, _ := remoteclient.ConnectToRemoteCluster(cd, r.remoteClusterAPIClientBuilder(cd), r.Client, logger)
+	if unreachable {
+		status.Message = "Cluster is unreachable"
+		return nil, nil
+	}
+	cv := &configv1.ClusterVersion{}
+	if err := remoteClient.Get(context.TODO(), types.NamespacedName{Name: clusterVersionObjectName}, cv); err != nil {
+		logger.WithError(err).Warn("error fetching remote clusterversion object")
+		status.Message = fmt.Sprintf("Could not get ClusterVersion: %v", err)
+		return nil, nil
+	}
+
+	status.Version = cv.Status.Desired.Version
+	status.Message = ""
+	now := metav1.Now()
+	switch {
+	case len(cv.Status.History) > 0 && cv.Status.History[0].Image == releaseImage && cv.Status.History[0].State == configv1.CompletedUpdate:
+		if status.Phase != hivev1.ClusterUpgradePhaseCompleted {
+			logger.WithField("version", status.Version).Info("cluster upgrade completed")
+		}
+		status.Phase = hivev1.ClusterUpgradePhaseCompleted
+		if status.CompletionTime == nil {
+			status.CompletionTime = &now
+		}
+	case cv.Spec.DesiredUpdate != nil && cv.Spec.DesiredUpdate.Image == releaseImage:
+		status.Phase = hivev1.ClusterUpgradePhaseUpgrading
+		if status.StartTime == nil {
+			status.StartTime = &now
+		}
+		if failing := findClusterVersionCondition(cv, clusterVersionFailing); failing != nil && failing.Status == configv1.ConditionTrue {
+			status.Phase = hivev1.ClusterUpgradePhaseFailed
+			status.Message = failing.Message
+		} else if progressing := findClusterVersionCondition(cv, configv1.OperatorProgressing); progressing != nil {
+			status.Message = progressing.Message
+		}
+	default:
+		status.Phase = hivev1.ClusterUpgradePhasePending
+	}
+	return remoteClient, cv
+}
+
+// checkClusterOperators records the degraded or unavailable ClusterOperators of an upgraded cluster in its status
+// message, as last gathered by the clusterstate controller. It returns false when the ClusterOperators have not been
+// gathered since the upgrade completed.
+func (r *ReconcileClusterUpgrade) checkClusterOperators(cd *hivev1.ClusterDeployment, status *hivev1.ClusterUpgradeStatus) (bool, error) {
+	state := &hivev1.ClusterState{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, state); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Message = "Waiting for ClusterOperators to be reported"
+			return false, nil
+		}
+		return false, err
+	}
+	if state.Status.LastUpdated == nil || (status.CompletionTime != nil && state.Status.LastUpdated.Before(status.CompletionTime)) {
+		status.Message = "Waiting for ClusterOperators to be reported"
+		return false, nil
+	}
+	var failing []string
+	for _, co := range state.Status.ClusterOperators {
+		for _, cond := range co.Conditions {
+			if (cond.Type == configv1.OperatorDegraded && cond.Status == configv1.ConditionTrue) ||
+				(cond.Type == configv1.OperatorAvailable && cond.Status == configv1.ConditionFalse) {
+				failing = append(failing, co.Name)
+				break
+			}
+		}
+	}
+	status.Message = ""
+	if len(failing) > 0 {
+		status.Message = fmt.Sprintf("ClusterOperators degraded or unavailable: %s", strings.Join(failing, ", "))
+	}
+	return true, nil
+}
+
+// startUpgrade sets the desired update of the ClusterVersion of a pending cluster to the release of the plan.
+func (r *ReconcileClusterUpgrade) startUpgrade(p pendingUpgrade, plan *hivev1.ClusterUpgradePlan, releaseImage string, logger log.FieldLogger) bool {
+	cdLog := logger.WithField("clusterDeployment", types.NamespacedName{Namespace: p.status.Namespace, Name: p.status.Name})
+	p.clusterVersion.Spec.DesiredUpdate = &configv1.Update{
+		Image: releaseImage,
+		Force: plan.Spec.Force,
+	}
+	if err := p.remoteClient.Update(context.TODO(), p.clusterVersion); err != nil {
+		cdLog.WithError(err).Warn("error requesting cluster upgrade")
+		p.status.Message = fmt.Sprintf("Could not request upgrade: %v", err)
+		return false
+	}
+	cdLog.Info("requested cluster upgrade")
+	metricClusterUpgradesStarted.WithLabelValues(plan.Name).Inc()
+	now := metav1.Now()
+	p.status.Phase = hivev1.ClusterUpgradePhaseUpgrading
+	p.status.StartTime = &now
+	p.status.Message = "Upgrade requested"
+	return true
+}
+
+func setPlanConditions(plan *hivev1.ClusterUpgradePlan, pauseReason, pauseMessage string, completed bool) {
+	pauseStatus := corev1.ConditionTrue
+	if pauseReason == "" {
+		pauseStatus, pauseReason, pauseMessage = corev1.ConditionFalse, upgradesAllowedReason, "Cluster upgrades may start"
+	}
+	plan.Status.Conditions, _ = controllerutils.SetClusterUpgradePlanConditionWithChangeCheck(
+		plan.Status.Conditions,
+		hivev1.ClusterUpgradePlanPausedCondition,
+		pauseStatus,
+		pauseReason,
+		pauseMessage,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	completedStatus, completedReason, completedMessage := corev1.ConditionTrue, allClustersUpgradedReason, "All clusters have been upgraded"
+	if !completed {
+		completedStatus, completedReason, completedMessage = corev1.ConditionFalse, clustersPendingReason, "Some clusters have not been upgraded"
+	}
+	plan.Status.Conditions, _ = controllerutils.SetClusterUpgradePlanConditionWithChangeCheck(
+		plan.Status.Conditions,
+		hivev1.ClusterUpgradePlanCompletedCondition,
+		completedStatus,
+		completedReason,
+		completedMessage,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+}
+
+func findClusterVersionCondition(cv *configv1.ClusterVersion, conditionType configv1.ClusterStatusConditionType) *configv1.ClusterOperatorStatusCondition {
+	for i, c := range cv.Status.Conditions {
+		if c.Type == conditionType {
+			return &cv.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+package clusterupgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testPlanName     = "test-plan"
+	testImageSetName = "test-imageset"
+	testNamespace    = "test-namespace"
+	oldReleaseImage  = "quay.io/openshift-release-dev/ocp-release:4.15.1-x86_64"
+	newReleaseImage  = "quay.io/openshift-release-dev/ocp-release:4.15.2-x86_64"
+)
+
+type planOption func(*hivev1.ClusterUpgradePlan)
+
+func testPlan(opts ...planOption) *hivev1.ClusterUpgradePlan {
+	plan := &hivev1.ClusterUpgradePlan{
+		ObjectMeta: metav1.ObjectMeta{Name: testPlanName},
+		Spec: hivev1.ClusterUpgradePlanSpec{
+			ImageSetRef: hivev1.ClusterImageSetReference{Name: testImageSetName},
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"fleet": "test"},
+			},
+		},
+	}
+	for _, o := range opts {
+		o(plan)
+	}
+	return plan
+}
+
+func withClusterStatus(name string, phase hivev1.ClusterUpgradePhase, completedAgo time.Duration) planOption {
+	return func(plan *hivev1.ClusterUpgradePlan) {
+		plan.Status.Clusters = append(plan.Status.Clusters, hivev1.ClusterUpgradeStatus{
+			Namespace:      testNamespace,
+			Name:           name,
+			Phase:          phase,
+			CompletionTime: &metav1.Time{Time: time.Now().Add(-completedAgo)},
+		})
+	}
+}
+
+func testClusterDeployment(name string, opts ...testcd.Option) *hivev1.ClusterDeployment {
+	opts = append([]testcd.Option{
+		testcd.Installed(),
+		testcd.WithLabel("fleet", "test"),
+		testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.UnreachableCondition,
+			Status: corev1.ConditionFalse,
+		}),
+	}, opts...)
+	return testcd.FullBuilder(testNamespace, name, scheme.GetScheme()).Build(opts...)
+}
+
+type clusterVersionOption func(*configv1.ClusterVersion)
+
+func testClusterVersion(opts ...clusterVersionOption) *configv1.ClusterVersion {
+	cv := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterVersionObjectName},
+		Status: configv1.ClusterVersionStatus{
+			Desired: configv1.Release{Version: "4.15.1", Image: oldReleaseImage},
+			History: []configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: "4.15.1", Image: oldReleaseImage}},
+		},
+	}
+	for _, o := range opts {
+		o(cv)
+	}
+	return cv
+}
+
+func upgrading(cv *configv1.ClusterVersion) {
+	cv.Spec.DesiredUpdate = &configv1.Update{Image: newReleaseImage}
+	cv.Status.Desired = configv1.Release{Version: "4.15.2", Image: newReleaseImage}
+	cv.Status.History = append([]configv1.UpdateHistory{{State: configv1.PartialUpdate, Version: "4.15.2", Image: newReleaseImage}}, cv.Status.History...)
+}
+
+func upgraded(cv *configv1.ClusterVersion) {
+	upgrading(cv)
+	cv.Status.History[0].State = configv1.CompletedUpdate
+}
+
+func failing(cv *configv1.ClusterVersion) {
+	upgrading(cv)
+	cv.Status.Conditions = append(cv.Status.Conditions, configv1.ClusterOperatorStatusCondition{
+		Type:    clusterVersionFailing,
+		Status:  configv1.ConditionTrue,
+		Message: "Cluster operator etcd is degraded",
+	})
+}
+
+func testClusterState(name string, updatedAgo time.Duration, degraded ...string) *hivev1.ClusterState {
+	state := &hivev1.ClusterState{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Status: hivev1.ClusterStateStatus{
+			LastUpdated: &metav1.Time{Time: time.Now().Add(-updatedAgo)},
+		},
+	}
+	for _, co := range []string{"etcd", "kube-apiserver"} {
+		degradedStatus := configv1.ConditionFalse
+		for _, d := range degraded {
+			if d == co {
+				degradedStatus = configv1.ConditionTrue
+			}
+		}
+		state.Status.ClusterOperators = append(state.Status.ClusterOperators, hivev1.ClusterOperatorState{
+			Name: co,
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+				{Type: configv1.OperatorDegraded, Status: degradedStatus},
+			},
+		})
+	}
+	return state
+}
+
+func TestReconcileClusterUpgrade(t *testing.T) {
+	imageSet := &hivev1.ClusterImageSet{
+		ObjectMeta: metav1.ObjectMeta{Name: testImageSetName},
+		Spec:       hivev1.ClusterImageSetSpec{ReleaseImage: newReleaseImage},
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		plan            *hivev1.ClusterUpgradePlan
+		existing        []runtime.Object
+		clusterVersions map[string]*configv1.ClusterVersion
+
+		expectedPhases      map[string]hivev1.ClusterUpgradePhase
+		expectedPauseReason string
+		expectCompleted     bool
+		expectRequeueAfter  time.Duration
+	}{
+		{
+			name: "first batch started",
+			plan: testPlan(func(plan *hivev1.ClusterUpgradePlan) { plan.Spec.MaxConcurrent = pointer.Int32(2) }),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+				testClusterDeployment("cluster-c"),
+				testClusterDeployment("other", testcd.WithLabel("fleet", "other")),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(),
+				"cluster-b": testClusterVersion(),
+				"cluster-c": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseUpgrading,
+				"cluster-b": hivev1.ClusterUpgradePhaseUpgrading,
+				"cluster-c": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "batch in progress",
+			plan: testPlan(),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(upgrading),
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseUpgrading,
+				"cluster-b": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			// cluster-b has no ClusterVersion, so observing it fails the test.
+			name: "pending clusters not observed while the batch is full",
+			plan: testPlan(withClusterStatus("cluster-a", hivev1.ClusterUpgradePhaseUpgrading, 0)),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(upgrading),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseUpgrading,
+				"cluster-b": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "upgraded cluster waits for cluster operators",
+			plan: testPlan(),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(upgraded),
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseCompleted,
+				"cluster-b": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "next batch started once cluster operators are healthy",
+			plan: testPlan(withClusterStatus("cluster-a", hivev1.ClusterUpgradePhaseCompleted, time.Hour)),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+				testClusterState("cluster-a", 30*time.Minute),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseCompleted,
+				"cluster-b": hivev1.ClusterUpgradePhaseUpgrading,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "paused on degraded cluster operators",
+			plan: testPlan(withClusterStatus("cluster-a", hivev1.ClusterUpgradePhaseCompleted, time.Hour)),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+				testClusterState("cluster-a", 30*time.Minute, "etcd"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseCompleted,
+				"cluster-b": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: clusterOperatorsFailingReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "paused on failed upgrade",
+			plan: testPlan(func(plan *hivev1.ClusterUpgradePlan) { plan.Spec.MaxConcurrent = pointer.Int32(2) }),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(failing),
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseFailed,
+				"cluster-b": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: upgradeFailedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "paused by spec",
+			plan: testPlan(func(plan *hivev1.ClusterUpgradePlan) { plan.Spec.Paused = true }),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: pausedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "outside maintenance window",
+			plan: testPlan(func(plan *hivev1.ClusterUpgradePlan) {
+				plan.Spec.MaintenanceWindows = []hivev1.ScheduleWindow{{
+					Start: now.Add(time.Hour).UTC().Format("15:04"),
+					End:   now.Add(2 * time.Hour).UTC().Format("15:04"),
+				}}
+			}),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-a": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhasePending,
+			},
+			expectedPauseReason: outsideMaintenanceWindowReason,
+			expectRequeueAfter:  time.Hour,
+		},
+		{
+			name: "hibernating cluster skipped",
+			plan: testPlan(),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a", testcd.WithPowerState(hivev1.ClusterPowerStateHibernating)),
+				testClusterDeployment("cluster-b"),
+			},
+			clusterVersions: map[string]*configv1.ClusterVersion{
+				"cluster-b": testClusterVersion(),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhasePending,
+				"cluster-b": hivev1.ClusterUpgradePhaseUpgrading,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectRequeueAfter:  upgradeCheckInterval,
+		},
+		{
+			name: "all clusters upgraded",
+			plan: testPlan(
+				withClusterStatus("cluster-a", hivev1.ClusterUpgradePhaseCompleted, time.Hour),
+				withClusterStatus("cluster-b", hivev1.ClusterUpgradePhaseCompleted, time.Hour),
+			),
+			existing: []runtime.Object{
+				testClusterDeployment("cluster-a"),
+				testClusterDeployment("cluster-b"),
+				testClusterState("cluster-a", 30*time.Minute),
+				testClusterState("cluster-b", 30*time.Minute),
+			},
+			expectedPhases: map[string]hivev1.ClusterUpgradePhase{
+				"cluster-a": hivev1.ClusterUpgradePhaseCompleted,
+				"cluster-b": hivev1.ClusterUpgradePhaseCompleted,
+			},
+			expectedPauseReason: upgradesAllowedReason,
+			expectCompleted:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append([]runtime.Object{test.plan, imageSet}, test.existing...)
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+			mockCtrl := gomock.NewController(t)
+			remoteClients := map[string]client.Client{}
+			for name, cv := range test.clusterVersions {
+				remoteClients[name] = testfake.NewFakeClientBuilder().WithRuntimeObjects(cv).Build()
+			}
+			r := &ReconcileClusterUpgrade{
+				Client: fakeClient,
+				logger: log.WithField("controller", "clusterUpgrade"),
+				remoteClusterAPIClientBuilder: func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+					builder := remoteclientmock.NewMockBuilder(mockCtrl)
+					remoteClient, ok := remoteClients[cd.Name]
+					require.True(t, ok, "unexpected remote client for %s", cd.Name)
+					builder.EXPECT().Build().Return(remoteClient, nil)
+					return builder
+				},
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: testPlanName}})
+			require.NoError(t, err, "unexpected error from reconcile")
+			assert.InDelta(t, test.expectRequeueAfter, result.RequeueAfter, float64(time.Minute), "unexpected requeue after")
+
+			plan := &hivev1.ClusterUpgradePlan{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: testPlanName}, plan))
+			phases := map[string]hivev1.ClusterUpgradePhase{}
+			for _, status := range plan.Status.Clusters {
+				phases[status.Name] = status.Phase
+			}
+			assert.Equal(t, test.expectedPhases, phases, "unexpected cluster phases")
+
+			for name, remoteClient := range remoteClients {
+				cv := &configv1.ClusterVersion{}
+				require.NoError(t, remoteClient.Get(context.TODO(), types.NamespacedName{Name: clusterVersionObjectName}, cv))
+				if phases[name] == hivev1.ClusterUpgradePhasePending {
+					assert.Nil(t, cv.Spec.DesiredUpdate, "unexpected desired update for %s", name)
+				} else if assert.NotNil(t, cv.Spec.DesiredUpdate, "expected desired update for %s", name) {
+					assert.Equal(t, newReleaseImage, cv.Spec.DesiredUpdate.Image, "unexpected desired update image for %s", name)
+				}
+			}
+
+			paused := controllerutils.FindCondition(plan.Status.Conditions, hivev1.ClusterUpgradePlanPausedCondition)
+			if test.expectedPauseReason == upgradesAllowedReason {
+				// Conditions that are not true are not added.
+				assert.Nil(t, paused, "unexpected paused condition")
+			} else if assert.NotNil(t, paused, "expected paused condition") {
+				assert.Equal(t, corev1.ConditionTrue, paused.Status, "unexpected paused condition status")
+				assert.Equal(t, test.expectedPauseReason, paused.Reason, "unexpected paused condition reason")
+			}
+			completed := controllerutils.FindCondition(plan.Status.Conditions, hivev1.ClusterUpgradePlanCompletedCondition)
+			assert.Equal(t, test.expectCompleted, completed != nil && completed.Status == corev1.ConditionTrue, "unexpected completed condition")
+		})
+	}
+}
+
+func TestClusterDeploymentUpdated(t *testing.T) {
+	tests := []struct {
+		name     string
+		update   func(*hivev1.ClusterDeployment)
+		expected bool
+	}{
+		{
+			name:   "status update",
+			update: func(cd *hivev1.ClusterDeployment) { cd.Status.APIURL = "https://api.example.com:6443" },
+		},
+		{
+			name:     "label change",
+			update:   func(cd *hivev1.ClusterDeployment) { cd.Labels["fleet"] = "other" },
+			expected: true,
+		},
+		{
+			name:     "installed",
+			update:   func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = true },
+			expected: true,
+		},
+		{
+			name: "deleted",
+			update: func(cd *hivev1.ClusterDeployment) {
+				now := metav1.Now()
+				cd.DeletionTimestamp = &now
+			},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			old := testcd.FullBuilder(testNamespace, "cluster-a", scheme.GetScheme()).Build(testcd.WithLabel("fleet", "test"))
+			updated := old.DeepCopy()
+			test.update(updated)
+			assert.Equal(t, test.expected, clusterDeploymentUpdated(event.TypedUpdateEvent[*hivev1.ClusterDeployment]{ObjectOld: old, ObjectNew: updated}))
+		})
+	}
+}
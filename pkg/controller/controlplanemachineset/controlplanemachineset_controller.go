@@ -0,0 +1,308 @@
+package controlplanemachineset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	ControllerName = hivev1.ControlPlaneMachineSetControllerName
+
+	controlPlaneMachineSetName      = "cluster"
+	controlPlaneMachineSetNamespace = "openshift-machine-api"
+
+	// statusRefreshInterval is how often the status of the ControlPlaneMachineSet is refreshed, as changes on the
+	// remote cluster do not trigger reconciles.
+	statusRefreshInterval = 10 * time.Minute
+	// updateRefreshInterval is how often the status is refreshed while control plane machines are being replaced.
+	updateRefreshInterval = 2 * time.Minute
+)
+
+// Add creates a new ControlPlaneMachineSet controller and adds it to the Manager with default RBAC. The Manager will
+// set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+	return AddToManager(mgr, NewReconciler(mgr, clientRateLimiter), concurrentReconciles, queueRateLimiter)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager, rateLimiter flowcontrol.RateLimiter) reconcile.Reconciler {
+	r := &ReconcileControlPlaneMachineSet{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &rateLimiter),
+		scheme: mgr.GetScheme(),
+	}
+	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewBuilder(r.Client, cd, ControllerName)
+	}
+	return r
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler, concurrentReconciles int, rateLimiter workqueue.RateLimiter) error {
+	c, err := controller.New("controlplanemachineset-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, log.WithField("controller", ControllerName)),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             rateLimiter,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	return c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{}, &handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{}))
+}
+
+var _ reconcile.Reconciler = &ReconcileControlPlaneMachineSet{}
+
+// ReconcileControlPlaneMachineSet manages the ControlPlaneMachineSet of remote clusters according to
+// spec.controlPlaneConfig.machines of their ClusterDeployment, and reports its status.
+type ReconcileControlPlaneMachineSet struct {
+	client.Client
+	scheme *runtime.Scheme
+
+	// remoteClusterAPIClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClusterAPIClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+}
+
+// Reconcile updates the ControlPlaneMachineSet of the remote cluster of a ClusterDeployment and syncs its status.
+func (r *ReconcileControlPlaneMachineSet) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cdLog := controllerutils.BuildControllerLogger(ControllerName, "clusterDeployment", request.NamespacedName)
+	cdLog.Info("reconciling cluster deployment")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, cdLog)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := r.Get(context.TODO(), request.NamespacedName, cd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	cdLog = controllerutils.AddLogFields(controllerutils.MetaObjectLogTagger{Object: cd}, cdLog)
+
+	if paused, err := strconv.ParseBool(cd.Annotations[constants.ReconcilePauseAnnotation]); err == nil && paused {
+		cdLog.Info("skipping reconcile due to ClusterDeployment pause annotation")
+		return reconcile.Result{}, nil
+	}
+	if cd.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	spec := cd.Spec.ControlPlaneConfig.Machines
+	if spec == nil {
+		return reconcile.Result{}, r.updateStatus(cd, nil, cdLog)
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return reconcile.Result{}, nil
+	}
+
+	instanceTypeField := instanceTypeFieldForPlatform(cd)
+	if instanceTypeField == "" {
+		return reconcile.Result{}, r.updateStatus(cd, &hivev1.ControlPlaneMachinesStatus{
+			Message: "control plane machines can only be managed on AWS, Azure and GCP",
+		}, cdLog)
+	}
+
+	remoteClient, unreachable, requeue := remoteclient.ConnectToRemoteCluster(
+		cd,
+		r.remoteClusterAPIClientBuilder(cd),
+		r.Client,
+		cdLog,
+	)
+	if unreachable {
+		return reconcile.Result{Requeue: requeue}, nil
+	}
+
+	cpms := &machinev1.ControlPlaneMachineSet{}
+	switch err := remoteClient.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: controlPlaneMachineSetNamespace, Name: controlPlaneMachineSetName},
+		cpms,
+	); {
+	case apierrors.IsNotFound(err):
+		cdLog.Info("remote cluster has no ControlPlaneMachineSet")
+		return reconcile.Result{RequeueAfter: statusRefreshInterval}, r.updateStatus(cd, &hivev1.ControlPlaneMachinesStatus{
+			Message: "the cluster has no ControlPlaneMachineSet",
+		}, cdLog)
+	case err != nil:
+		cdLog.WithError(err).Error("error fetching remote ControlPlaneMachineSet")
+		return reconcile.Result{}, err
+	}
+
+	changed, err := applyControlPlaneMachinesSpec(cpms, spec, instanceTypeField, cdLog)
+	if err != nil {
+		cdLog.WithError(err).Error("could not apply control plane machines configuration")
+		return reconcile.Result{}, r.updateStatus(cd, &hivev1.ControlPlaneMachinesStatus{Message: err.Error()}, cdLog)
+	}
+	if changed {
+		cdLog.Info("updating remote ControlPlaneMachineSet")
+		if err := remoteClient.Update(context.TODO(), cpms); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update remote ControlPlaneMachineSet")
+			return reconcile.Result{}, err
+		}
+	}
+
+	status, err := controlPlaneMachinesStatus(cpms, spec, instanceTypeField)
+	if err != nil {
+		cdLog.WithError(err).Error("could not read the ControlPlaneMachineSet template")
+		status = &hivev1.ControlPlaneMachinesStatus{Message: err.Error()}
+	}
+	if err := r.updateStatus(cd, status, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if changed || status.UpdatedReplicas < status.Replicas {
+		return reconcile.Result{RequeueAfter: updateRefreshInterval}, nil
+	}
+	return reconcile.Result{RequeueAfter: statusRefreshInterval}, nil
+}
+
+// instanceTypeFieldForPlatform returns the provider spec field holding the instance type of machines on the
+// platform of the ClusterDeployment, or an empty string if ControlPlaneMachineSets are not supported there.
+func instanceTypeFieldForPlatform(cd *hivev1.ClusterDeployment) string {
+	switch {
+	case cd.Spec.Platform.AWS != nil:
+		return "instanceType"
+	case cd.Spec.Platform.Azure != nil:
+		return "vmSize"
+	case cd.Spec.Platform.GCP != nil:
+		return "machineType"
+	}
+	return ""
+}
+
+// applyControlPlaneMachinesSpec updates the ControlPlaneMachineSet according to spec, returning whether it changed.
+func applyControlPlaneMachinesSpec(cpms *machinev1.ControlPlaneMachineSet, spec *hivev1.ControlPlaneMachinesSpec, instanceTypeField string, logger log.FieldLogger) (bool, error) {
+	changed := false
+	if spec.Activate && cpms.Spec.State != machinev1.ControlPlaneMachineSetStateActive {
+		logger.Info("activating ControlPlaneMachineSet")
+		cpms.Spec.State = machinev1.ControlPlaneMachineSetStateActive
+		changed = true
+	}
+	if spec.UpdateStrategy != "" && cpms.Spec.Strategy.Type != machinev1.ControlPlaneMachineSetStrategyType(spec.UpdateStrategy) {
+		logger.WithField("strategy", spec.UpdateStrategy).Info("updating ControlPlaneMachineSet strategy")
+		cpms.Spec.Strategy.Type = machinev1.ControlPlaneMachineSetStrategyType(spec.UpdateStrategy)
+		changed = true
+	}
+	if spec.InstanceType == "" {
+		return changed, nil
+	}
+
+	providerSpec, err := templateProviderSpec(cpms)
+	if err != nil {
+		return false, err
+	}
+	if providerSpec[instanceTypeField] == spec.InstanceType {
+		return changed, nil
+	}
+	logger.WithField("instanceType", spec.InstanceType).WithField("previous", providerSpec[instanceTypeField]).Info("updating ControlPlaneMachineSet instance type")
+	providerSpec[instanceTypeField] = spec.InstanceType
+	raw, err := json.Marshal(providerSpec)
+	if err != nil {
+		return false, errors.Wrap(err, "could not encode the ControlPlaneMachineSet provider spec")
+	}
+	value := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine.Spec.ProviderSpec.Value
+	value.Raw = raw
+	value.Object = nil
+	return true, nil
+}
+
+// templateProviderSpec returns the provider spec of the machine template of the ControlPlaneMachineSet. It is
+// handled as unstructured JSON so that fields unknown to Hive are preserved.
+func templateProviderSpec(cpms *machinev1.ControlPlaneMachineSet) (map[string]interface{}, error) {
+	template := cpms.Spec.Template.OpenShiftMachineV1Beta1Machine
+	if template == nil || template.Spec.ProviderSpec.Value == nil {
+		return nil, fmt.Errorf("the ControlPlaneMachineSet has no %s machine template", machinev1.OpenShiftMachineV1Beta1MachineType)
+	}
+	value := template.Spec.ProviderSpec.Value
+	raw := value.Raw
+	if raw == nil && value.Object != nil {
+		var err error
+		if raw, err = json.Marshal(value.Object); err != nil {
+			return nil, errors.Wrap(err, "could not encode the ControlPlaneMachineSet provider spec")
+		}
+	}
+	providerSpec := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &providerSpec); err != nil {
+		return nil, errors.Wrap(err, "could not decode the ControlPlaneMachineSet provider spec")
+	}
+	return providerSpec, nil
+}
+
+// controlPlaneMachinesStatus summarizes the state of the ControlPlaneMachineSet.
+func controlPlaneMachinesStatus(cpms *machinev1.ControlPlaneMachineSet, spec *hivev1.ControlPlaneMachinesSpec, instanceTypeField string) (*hivev1.ControlPlaneMachinesStatus, error) {
+	providerSpec, err := templateProviderSpec(cpms)
+	if err != nil {
+		return nil, err
+	}
+	status := &hivev1.ControlPlaneMachinesStatus{
+		State:               string(cpms.Spec.State),
+		UpdateStrategy:      hivev1.ControlPlaneUpdateStrategyType(cpms.Spec.Strategy.Type),
+		Replicas:            cpms.Status.Replicas,
+		ReadyReplicas:       cpms.Status.ReadyReplicas,
+		UpdatedReplicas:     cpms.Status.UpdatedReplicas,
+		UnavailableReplicas: cpms.Status.UnavailableReplicas,
+	}
+	if instanceType, ok := providerSpec[instanceTypeField].(string); ok {
+		status.InstanceType = instanceType
+	}
+	switch degraded := meta.FindStatusCondition(cpms.Status.Conditions, "Degraded"); {
+	case cpms.Spec.State != machinev1.ControlPlaneMachineSetStateActive && (spec.InstanceType != "" || spec.UpdateStrategy != ""):
+		status.Message = "the ControlPlaneMachineSet is inactive, changes only take effect once it is activated"
+	case degraded != nil && degraded.Status == "True":
+		status.Message = fmt.Sprintf("the ControlPlaneMachineSet is degraded: %s", degraded.Message)
+	}
+	return status, nil
+}
+
+func (r *ReconcileControlPlaneMachineSet) updateStatus(cd *hivev1.ClusterDeployment, status *hivev1.ControlPlaneMachinesStatus, cdLog log.FieldLogger) error {
+	if reflect.DeepEqual(cd.Status.ControlPlaneMachines, status) {
+		return nil
+	}
+	cd.Status.ControlPlaneMachines = status
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update control plane machines status")
+		return err
+	}
+	return nil
+}
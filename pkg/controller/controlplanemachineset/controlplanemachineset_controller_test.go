@@ -0,0 +1,316 @@
+package controlplanemachineset
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	machinev1 "github.com/openshift/api/machine/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/apis/hive/v1/aws"
+	hivev1vsphere "github.com/openshift/hive/apis/hive/v1/vsphere"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testName        = "foo-lqmsh"
+	testClusterName = "bar"
+	testClusterID   = "testFooClusterUUID"
+	testNamespace   = "default"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestControlPlaneMachineSetReconcile(t *testing.T) {
+	tests := []struct {
+		name                 string
+		cd                   *hivev1.ClusterDeployment
+		cpms                 *machinev1.ControlPlaneMachineSet
+		noRemoteCall         bool
+		expectedStatus       *hivev1.ControlPlaneMachinesStatus
+		expectedState        machinev1.ControlPlaneMachineSetState
+		expectedStrategy     machinev1.ControlPlaneMachineSetStrategyType
+		expectedInstanceType string
+	}{
+		{
+			name:         "no machines configuration",
+			cd:           testClusterDeployment(nil),
+			noRemoteCall: true,
+		},
+		{
+			name: "status cleared when machines configuration removed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment(nil)
+				cd.Status.ControlPlaneMachines = &hivev1.ControlPlaneMachinesStatus{State: "Active"}
+				return cd
+			}(),
+			noRemoteCall: true,
+		},
+		{
+			name: "not installed",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{InstanceType: "m6i.2xlarge"})
+				cd.Spec.Installed = false
+				return cd
+			}(),
+			noRemoteCall: true,
+		},
+		{
+			name: "unsupported platform",
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{InstanceType: "m6i.2xlarge"})
+				cd.Spec.Platform = hivev1.Platform{VSphere: &hivev1vsphere.Platform{}}
+				return cd
+			}(),
+			noRemoteCall: true,
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				Message: "control plane machines can only be managed on AWS, Azure and GCP",
+			},
+		},
+		{
+			name: "no remote ControlPlaneMachineSet",
+			cd:   testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{InstanceType: "m6i.2xlarge"}),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				Message: "the cluster has no ControlPlaneMachineSet",
+			},
+		},
+		{
+			name: "status reported without changes",
+			cd:   testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{}),
+			cpms: testControlPlaneMachineSet(machinev1.ControlPlaneMachineSetStateActive),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				State:           "Active",
+				InstanceType:    "m5.xlarge",
+				UpdateStrategy:  hivev1.ControlPlaneUpdateStrategyRollingUpdate,
+				Replicas:        3,
+				ReadyReplicas:   3,
+				UpdatedReplicas: 3,
+			},
+			expectedState:        machinev1.ControlPlaneMachineSetStateActive,
+			expectedStrategy:     machinev1.RollingUpdate,
+			expectedInstanceType: "m5.xlarge",
+		},
+		{
+			name: "instance type and strategy updated",
+			cd: testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{
+				InstanceType:   "m6i.2xlarge",
+				UpdateStrategy: hivev1.ControlPlaneUpdateStrategyOnDelete,
+			}),
+			cpms: testControlPlaneMachineSet(machinev1.ControlPlaneMachineSetStateActive),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				State:           "Active",
+				InstanceType:    "m6i.2xlarge",
+				UpdateStrategy:  hivev1.ControlPlaneUpdateStrategyOnDelete,
+				Replicas:        3,
+				ReadyReplicas:   3,
+				UpdatedReplicas: 3,
+			},
+			expectedState:        machinev1.ControlPlaneMachineSetStateActive,
+			expectedStrategy:     machinev1.OnDelete,
+			expectedInstanceType: "m6i.2xlarge",
+		},
+		{
+			name: "inactive ControlPlaneMachineSet",
+			cd:   testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{InstanceType: "m6i.2xlarge"}),
+			cpms: testControlPlaneMachineSet(machinev1.ControlPlaneMachineSetStateInactive),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				State:           "Inactive",
+				InstanceType:    "m6i.2xlarge",
+				UpdateStrategy:  hivev1.ControlPlaneUpdateStrategyRollingUpdate,
+				Replicas:        3,
+				ReadyReplicas:   3,
+				UpdatedReplicas: 3,
+				Message:         "the ControlPlaneMachineSet is inactive, changes only take effect once it is activated",
+			},
+			expectedState:        machinev1.ControlPlaneMachineSetStateInactive,
+			expectedStrategy:     machinev1.RollingUpdate,
+			expectedInstanceType: "m6i.2xlarge",
+		},
+		{
+			name: "ControlPlaneMachineSet activated",
+			cd: testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{
+				InstanceType: "m6i.2xlarge",
+				Activate:     true,
+			}),
+			cpms: testControlPlaneMachineSet(machinev1.ControlPlaneMachineSetStateInactive),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				State:           "Active",
+				InstanceType:    "m6i.2xlarge",
+				UpdateStrategy:  hivev1.ControlPlaneUpdateStrategyRollingUpdate,
+				Replicas:        3,
+				ReadyReplicas:   3,
+				UpdatedReplicas: 3,
+			},
+			expectedState:        machinev1.ControlPlaneMachineSetStateActive,
+			expectedStrategy:     machinev1.RollingUpdate,
+			expectedInstanceType: "m6i.2xlarge",
+		},
+		{
+			name: "degraded ControlPlaneMachineSet",
+			cd:   testClusterDeployment(&hivev1.ControlPlaneMachinesSpec{}),
+			cpms: func() *machinev1.ControlPlaneMachineSet {
+				cpms := testControlPlaneMachineSet(machinev1.ControlPlaneMachineSetStateActive)
+				cpms.Status.UpdatedReplicas = 1
+				cpms.Status.Conditions = []metav1.Condition{{
+					Type:    "Degraded",
+					Status:  metav1.ConditionTrue,
+					Reason:  "UnmanagedNodes",
+					Message: "found 1 unmanaged node",
+				}}
+				return cpms
+			}(),
+			expectedStatus: &hivev1.ControlPlaneMachinesStatus{
+				State:           "Active",
+				InstanceType:    "m5.xlarge",
+				UpdateStrategy:  hivev1.ControlPlaneUpdateStrategyRollingUpdate,
+				Replicas:        3,
+				ReadyReplicas:   3,
+				UpdatedReplicas: 1,
+				Message:         "the ControlPlaneMachineSet is degraded: found 1 unmanaged node",
+			},
+			expectedState:        machinev1.ControlPlaneMachineSetStateActive,
+			expectedStrategy:     machinev1.RollingUpdate,
+			expectedInstanceType: "m5.xlarge",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(test.cd).Build()
+			var remoteObjects []runtime.Object
+			if test.cpms != nil {
+				remoteObjects = append(remoteObjects, test.cpms)
+			}
+			remoteClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(remoteObjects...).Build()
+			mockCtrl := gomock.NewController(t)
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			if !test.noRemoteCall {
+				mockRemoteClientBuilder.EXPECT().Build().Return(remoteClient, nil)
+			}
+			r := &ReconcileControlPlaneMachineSet{
+				Client:                        fakeClient,
+				scheme:                        scheme.GetScheme(),
+				remoteClusterAPIClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder { return mockRemoteClientBuilder },
+			}
+
+			namespacedName := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: namespacedName})
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), namespacedName, cd))
+			assert.Equal(t, test.expectedStatus, cd.Status.ControlPlaneMachines, "unexpected control plane machines status")
+
+			if test.cpms == nil {
+				return
+			}
+			cpms := &machinev1.ControlPlaneMachineSet{}
+			require.NoError(t, remoteClient.Get(context.TODO(), client.ObjectKeyFromObject(test.cpms), cpms))
+			assert.Equal(t, test.expectedState, cpms.Spec.State, "unexpected state")
+			assert.Equal(t, test.expectedStrategy, cpms.Spec.Strategy.Type, "unexpected strategy")
+			providerSpec, err := templateProviderSpec(cpms)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedInstanceType, providerSpec["instanceType"], "unexpected instance type")
+			assert.Equal(t, "ami-0123456789", providerSpec["ami"].(map[string]interface{})["id"], "provider spec fields not preserved")
+		})
+	}
+}
+
+func testClusterDeployment(machines *hivev1.ControlPlaneMachinesSpec) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       testName,
+			Namespace:  testNamespace,
+			Finalizers: []string{hivev1.FinalizerDeprovision},
+			UID:        types.UID("1234"),
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testClusterName,
+			Platform: hivev1.Platform{
+				AWS: &hivev1aws.Platform{
+					CredentialsSecretRef: corev1.LocalObjectReference{
+						Name: "aws-credentials",
+					},
+					Region: "us-east-1",
+				},
+			},
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				ClusterID: testClusterID,
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{
+					Name: "kubeconfig-secret",
+				},
+			},
+			ControlPlaneConfig: hivev1.ControlPlaneConfigSpec{
+				Machines: machines,
+			},
+			Installed: true,
+		},
+		Status: hivev1.ClusterDeploymentStatus{
+			Conditions: []hivev1.ClusterDeploymentCondition{{
+				Type:   hivev1.UnreachableCondition,
+				Status: corev1.ConditionFalse,
+			}},
+		},
+	}
+}
+
+func testControlPlaneMachineSet(state machinev1.ControlPlaneMachineSetState) *machinev1.ControlPlaneMachineSet {
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion":   "machine.openshift.io/v1beta1",
+		"kind":         "AWSMachineProviderConfig",
+		"instanceType": "m5.xlarge",
+		"ami":          map[string]interface{}{"id": "ami-0123456789"},
+	})
+	if err != nil {
+		log.WithError(err).Fatal("error encoding provider spec")
+	}
+	return &machinev1.ControlPlaneMachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controlPlaneMachineSetName,
+			Namespace: controlPlaneMachineSetNamespace,
+		},
+		Spec: machinev1.ControlPlaneMachineSetSpec{
+			State:    state,
+			Replicas: pointer.Int32(3),
+			Strategy: machinev1.ControlPlaneMachineSetStrategy{
+				Type: machinev1.RollingUpdate,
+			},
+			Template: machinev1.ControlPlaneMachineSetTemplate{
+				MachineType: machinev1.OpenShiftMachineV1Beta1MachineType,
+				OpenShiftMachineV1Beta1Machine: &machinev1.OpenShiftMachineV1Beta1MachineTemplate{
+					Spec: machinev1beta1.MachineSpec{
+						ProviderSpec: machinev1beta1.ProviderSpec{
+							Value: &runtime.RawExtension{Raw: raw},
+						},
+					},
+				},
+			},
+		},
+		Status: machinev1.ControlPlaneMachineSetStatus{
+			Replicas:        3,
+			ReadyReplicas:   3,
+			UpdatedReplicas: 3,
+		},
+	}
+}
@@ -1,5 +1,11 @@
 package dnszone
 
+import "strconv"
+
+// txtRecordTTL is the TTL in seconds of the TXT records set by actuators. It is short so that changes propagate
+// quickly.
+const txtRecordTTL = 60
+
 // Actuator interface is the interface that is used to add dns provider support to the dnszone controller.
 type Actuator interface {
 	// Create tells the actuator to make a zone in the dns provider.
@@ -25,4 +31,20 @@ type Actuator interface {
 
 	// SetConditionsForError sets conditions on the dnszone given a specific error
 	SetConditionsForError(err error) bool
+
+	// SetTXTRecord creates or replaces the TXT record with the given fully qualified name in the zone.
+	SetTXTRecord(name string, values []string) error
+
+	// DeleteTXTRecord deletes the TXT record with the given fully qualified name and values from the zone. Deleting a
+	// record that does not exist is not an error.
+	DeleteTXTRecord(name string, values []string) error
+}
+
+// txtRecordData returns the quoted character strings of the given TXT record values.
+func txtRecordData(values []string) []string {
+	data := make([]string, len(values))
+	for i, value := range values {
+		data[i] = strconv.Quote(value)
+	}
+	return data
 }
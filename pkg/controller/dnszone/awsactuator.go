@@ -534,6 +534,50 @@ func (a *AWSActuator) GetNameServers() ([]string, error) {
 	return result, nil
 }
 
+// SetTXTRecord creates or replaces a TXT record in the route53 hosted zone.
+func (a *AWSActuator) SetTXTRecord(name string, values []string) error {
+	return a.changeTXTRecord(route53.ChangeActionUpsert, name, values)
+}
+
+// DeleteTXTRecord deletes a TXT record from the route53 hosted zone.
+func (a *AWSActuator) DeleteTXTRecord(name string, values []string) error {
+	err := a.changeTXTRecord(route53.ChangeActionDelete, name, values)
+	// Route53 rejects the deletion of records that do not exist with an InvalidChangeBatch error.
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == route53.ErrCodeInvalidChangeBatch &&
+		strings.Contains(awsErr.Message(), "not found") {
+		a.logger.WithField("record", name).Debug("TXT record does not exist")
+		return nil
+	}
+	return err
+}
+
+func (a *AWSActuator) changeTXTRecord(action, name string, values []string) error {
+	if a.hostedZone == nil {
+		return errors.New("hostedZone is unpopulated")
+	}
+	records := make([]*route53.ResourceRecord, len(values))
+	for i, value := range txtRecordData(values) {
+		records[i] = &route53.ResourceRecord{Value: aws.String(value)}
+	}
+	logger := a.logger.WithField("id", a.hostedZone.Id).WithField("record", name).WithField("action", action)
+	logger.Info("changing TXT record")
+	_, err := a.awsClient.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: a.hostedZone.Id,
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action: aws.String(action),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name:            aws.String(controllerutils.Dotted(name)),
+					Type:            aws.String(route53.RRTypeTxt),
+					TTL:             aws.Int64(txtRecordTTL),
+					ResourceRecords: records,
+				},
+			}},
+		},
+	})
+	return err
+}
+
 // Exists determines if the route53 hosted zone corresponding to the DNSZone exists
 func (a *AWSActuator) Exists() (bool, error) {
 	return a.hostedZone != nil, nil
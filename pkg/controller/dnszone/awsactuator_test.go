@@ -184,3 +184,38 @@ func mockGetResourcePages(expect *mock.MockClientMockRecorder) {
 		f(getResourcesOutput, true)
 	})
 }
+
+// TestAWSActuatorTXTRecords tests that TXT records are changed in the hosted zone.
+func TestAWSActuatorTXTRecords(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	actuator := &AWSActuator{
+		logger:     log.WithField("controller", ControllerName),
+		awsClient:  mocks.mockAWSClient,
+		dnsZone:    validDNSZone(),
+		hostedZone: &route53.HostedZone{Id: aws.String("1234")},
+	}
+	expectChange := func(action string) *gomock.Call {
+		return mocks.mockAWSClient.EXPECT().ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+			HostedZoneId: aws.String("1234"),
+			ChangeBatch: &route53.ChangeBatch{
+				Changes: []*route53.Change{{
+					Action: aws.String(action),
+					ResourceRecordSet: &route53.ResourceRecordSet{
+						Name:            aws.String("_acme-challenge.apps.blah.example.com."),
+						Type:            aws.String("TXT"),
+						TTL:             aws.Int64(txtRecordTTL),
+						ResourceRecords: []*route53.ResourceRecord{{Value: aws.String(`"value"`)}},
+					},
+				}},
+			},
+		})
+	}
+
+	expectChange("UPSERT").Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+	assert.NoError(t, actuator.SetTXTRecord("_acme-challenge.apps.blah.example.com", []string{"value"}))
+
+	expectChange("DELETE").Return(nil, awserr.New(route53.ErrCodeInvalidChangeBatch,
+		"Tried to delete resource record set [name='_acme-challenge.apps.blah.example.com.', type='TXT'] but it was not found", nil))
+	assert.NoError(t, actuator.DeleteTXTRecord("_acme-challenge.apps.blah.example.com", []string{"value"}),
+		"deleting a missing record should not fail")
+}
@@ -3,6 +3,7 @@ package dnszone
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -195,6 +196,52 @@ func (a *AzureActuator) Refresh() error {
 	return nil
 }
 
+// SetTXTRecord implements the SetTXTRecord call of the actuator interface
+func (a *AzureActuator) SetTXTRecord(name string, values []string) error {
+	relativeName, err := a.relativeRecordName(name)
+	if err != nil {
+		return err
+	}
+	txtRecords := make([]dns.TxtRecord, len(values))
+	for i := range values {
+		txtRecords[i] = dns.TxtRecord{Value: &[]string{values[i]}}
+	}
+	ttl := int64(txtRecordTTL)
+	a.logger.WithField("zone", a.dnsZone.Spec.Zone).WithField("record", name).Info("setting TXT record")
+	_, err = a.azureClient.CreateOrUpdateRecordSet(context.TODO(), a.dnsZone.Spec.Azure.ResourceGroupName,
+		a.dnsZone.Spec.Zone, relativeName, dns.TXT, dns.RecordSet{
+			RecordSetProperties: &dns.RecordSetProperties{
+				TTL:        &ttl,
+				TxtRecords: &txtRecords,
+			},
+		})
+	return err
+}
+
+// DeleteTXTRecord implements the DeleteTXTRecord call of the actuator interface
+func (a *AzureActuator) DeleteTXTRecord(name string, values []string) error {
+	relativeName, err := a.relativeRecordName(name)
+	if err != nil {
+		return err
+	}
+	a.logger.WithField("zone", a.dnsZone.Spec.Zone).WithField("record", name).Info("deleting TXT record")
+	// Azure does not fail the deletion of record sets that do not exist.
+	return a.azureClient.DeleteRecordSet(context.TODO(), a.dnsZone.Spec.Azure.ResourceGroupName, a.dnsZone.Spec.Zone,
+		relativeName, dns.TXT)
+}
+
+// relativeRecordName returns the name of the record with the given fully qualified name, relative to the zone.
+func (a *AzureActuator) relativeRecordName(name string) (string, error) {
+	name, zone := controllerutils.Undotted(name), controllerutils.Undotted(a.dnsZone.Spec.Zone)
+	if name == zone {
+		return "@", nil
+	}
+	if !strings.HasSuffix(name, "."+zone) {
+		return "", fmt.Errorf("record %s is not in zone %s", name, zone)
+	}
+	return strings.TrimSuffix(name, "."+zone), nil
+}
+
 // UpdateMetadata implements the UpdateMetadata call of the actuator interface
 func (a *AzureActuator) UpdateMetadata() error {
 	return nil
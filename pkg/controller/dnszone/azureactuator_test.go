@@ -93,3 +93,30 @@ func mockDeleteAzureZone(mockCtrl *gomock.Controller, expect *mock.MockClientMoc
 	expect.ListRecordSetsByZone(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(recordSetPage, nil)
 	expect.DeleteZone(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 }
+
+// TestAzureActuatorTXTRecords tests that TXT records are set relative to the zone.
+func TestAzureActuatorTXTRecords(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	dnsZone := validAzureDNSZone()
+	actuator := &AzureActuator{
+		logger:      log.WithField("controller", ControllerName),
+		azureClient: mocks.mockAzureClient,
+		dnsZone:     dnsZone,
+	}
+	expect := mocks.mockAzureClient.EXPECT()
+	rg := dnsZone.Spec.Azure.ResourceGroupName
+
+	expect.CreateOrUpdateRecordSet(gomock.Any(), rg, "blah.example.com", "_acme-challenge.apps", dns.TXT, dns.RecordSet{
+		RecordSetProperties: &dns.RecordSetProperties{
+			TTL:        to.Int64Ptr(txtRecordTTL),
+			TxtRecords: &[]dns.TxtRecord{{Value: &[]string{"value"}}},
+		},
+	}).Return(dns.RecordSet{}, nil)
+	assert.NoError(t, actuator.SetTXTRecord("_acme-challenge.apps.blah.example.com", []string{"value"}))
+
+	expect.DeleteRecordSet(gomock.Any(), rg, "blah.example.com", "_acme-challenge.apps", dns.TXT).Return(nil)
+	assert.NoError(t, actuator.DeleteTXTRecord("_acme-challenge.apps.blah.example.com.", []string{"value"}))
+
+	assert.Error(t, actuator.SetTXTRecord("_acme-challenge.example.org", []string{"value"}),
+		"records outside of the zone should be rejected")
+}
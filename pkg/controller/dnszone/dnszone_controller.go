@@ -388,6 +388,11 @@ func shouldSync(desiredState *hivev1.DNSZone) (bool, time.Duration) {
 }
 
 func (r *ReconcileDNSZone) getActuator(dnsZone *hivev1.DNSZone, dnsLog log.FieldLogger) (Actuator, error) {
+	return NewActuator(r.Client, dnsZone, dnsLog)
+}
+
+// NewActuator returns the actuator for the DNS provider of the given DNSZone.
+func NewActuator(kubeClient client.Client, dnsZone *hivev1.DNSZone, dnsLog log.FieldLogger) (Actuator, error) {
 	if dnsZone.Spec.AWS != nil {
 		credentials := awsclient.CredentialsSource{
 			Secret: &awsclient.SecretCredentialsSource{
@@ -403,12 +408,12 @@ func (r *ReconcileDNSZone) getActuator(dnsZone *hivev1.DNSZone, dnsLog log.Field
 			},
 		}
 
-		return NewAWSActuator(dnsLog, r.Client, credentials, dnsZone, awsclient.New)
+		return NewAWSActuator(dnsLog, kubeClient, credentials, dnsZone, awsclient.New)
 	}
 
 	if dnsZone.Spec.GCP != nil {
 		secret := &corev1.Secret{}
-		err := r.Get(context.TODO(),
+		err := kubeClient.Get(context.TODO(),
 			types.NamespacedName{
 				Name:      dnsZone.Spec.GCP.CredentialsSecretRef.Name,
 				Namespace: dnsZone.Namespace,
@@ -423,7 +428,7 @@ func (r *ReconcileDNSZone) getActuator(dnsZone *hivev1.DNSZone, dnsLog log.Field
 
 	if dnsZone.Spec.Azure != nil {
 		secret := &corev1.Secret{}
-		err := r.Get(context.TODO(),
+		err := kubeClient.Get(context.TODO(),
 			types.NamespacedName{
 				Name:      dnsZone.Spec.Azure.CredentialsSecretRef.Name,
 				Namespace: dnsZone.Namespace,
@@ -2,6 +2,7 @@ package dnszone
 
 import (
 	"net/http"
+	"reflect"
 	"strings"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
@@ -194,6 +195,52 @@ func (a *GCPActuator) GetNameServers() ([]string, error) {
 	return result, nil
 }
 
+// SetTXTRecord implements the SetTXTRecord call of the actuator interface
+func (a *GCPActuator) SetTXTRecord(name string, values []string) error {
+	existing, err := a.getTXTRecord(name)
+	if err != nil {
+		return err
+	}
+	desired := &dns.ResourceRecordSet{
+		Name:    controllerutils.Dotted(name),
+		Type:    "TXT",
+		Ttl:     txtRecordTTL,
+		Rrdatas: txtRecordData(values),
+	}
+	if existing != nil && existing.Ttl == desired.Ttl && reflect.DeepEqual(existing.Rrdatas, desired.Rrdatas) {
+		return nil
+	}
+	a.logger.WithField("zoneName", a.managedZone.Name).WithField("record", name).Info("setting TXT record")
+	return a.gcpClient.UpdateResourceRecordSet(a.managedZone.Name, desired, existing)
+}
+
+// DeleteTXTRecord implements the DeleteTXTRecord call of the actuator interface
+func (a *GCPActuator) DeleteTXTRecord(name string, values []string) error {
+	existing, err := a.getTXTRecord(name)
+	if err != nil || existing == nil {
+		return err
+	}
+	a.logger.WithField("zoneName", a.managedZone.Name).WithField("record", name).Info("deleting TXT record")
+	return a.gcpClient.DeleteResourceRecordSet(a.managedZone.Name, existing)
+}
+
+func (a *GCPActuator) getTXTRecord(name string) (*dns.ResourceRecordSet, error) {
+	if a.managedZone == nil {
+		return nil, errors.New("managedZone is unpopulated")
+	}
+	resp, err := a.gcpClient.ListResourceRecordSets(a.managedZone.Name, gcpclient.ListResourceRecordSetsOptions{
+		Name: controllerutils.Dotted(name),
+		Type: "TXT",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Rrsets) == 0 {
+		return nil, nil
+	}
+	return resp.Rrsets[0], nil
+}
+
 // Refresh implements the Refresh call of the actuator interface
 func (a *GCPActuator) Refresh() error {
 	var zoneName string
@@ -5,6 +5,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
 	"github.com/openshift/hive/pkg/gcpclient/mock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -79,3 +80,42 @@ func mockDeleteGCPZone(expect *mock.MockClientMockRecorder) {
 	expect.ListResourceRecordSets(gomock.Any(), gomock.Any()).Return(&dns.ResourceRecordSetsListResponse{}, nil)
 	expect.DeleteManagedZone(gomock.Any()).Return(nil).Times(1)
 }
+
+// TestGCPActuatorTXTRecords tests that TXT records replace the existing record in the managed zone.
+func TestGCPActuatorTXTRecords(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	actuator := &GCPActuator{
+		logger:      log.WithField("controller", ControllerName),
+		gcpClient:   mocks.mockGCPClient,
+		dnsZone:     validDNSZone(),
+		managedZone: &dns.ManagedZone{Name: "hive-blah-example-com"},
+	}
+	existing := &dns.ResourceRecordSet{
+		Name:    "_acme-challenge.apps.blah.example.com.",
+		Type:    "TXT",
+		Ttl:     txtRecordTTL,
+		Rrdatas: []string{`"old"`},
+	}
+	desired := &dns.ResourceRecordSet{
+		Name:    "_acme-challenge.apps.blah.example.com.",
+		Type:    "TXT",
+		Ttl:     txtRecordTTL,
+		Rrdatas: []string{`"new1"`, `"new2"`},
+	}
+	listOpts := gcpclient.ListResourceRecordSetsOptions{Name: "_acme-challenge.apps.blah.example.com.", Type: "TXT"}
+	expect := mocks.mockGCPClient.EXPECT()
+
+	expect.ListResourceRecordSets("hive-blah-example-com", listOpts).
+		Return(&dns.ResourceRecordSetsListResponse{Rrsets: []*dns.ResourceRecordSet{existing}}, nil)
+	expect.UpdateResourceRecordSet("hive-blah-example-com", desired, existing).Return(nil)
+	assert.NoError(t, actuator.SetTXTRecord("_acme-challenge.apps.blah.example.com", []string{"new1", "new2"}))
+
+	expect.ListResourceRecordSets("hive-blah-example-com", listOpts).
+		Return(&dns.ResourceRecordSetsListResponse{Rrsets: []*dns.ResourceRecordSet{desired}}, nil)
+	expect.DeleteResourceRecordSet("hive-blah-example-com", desired).Return(nil)
+	assert.NoError(t, actuator.DeleteTXTRecord("_acme-challenge.apps.blah.example.com", []string{"new1", "new2"}))
+
+	expect.ListResourceRecordSets("hive-blah-example-com", listOpts).Return(&dns.ResourceRecordSetsListResponse{}, nil)
+	assert.NoError(t, actuator.DeleteTXTRecord("_acme-challenge.apps.blah.example.com", []string{"new1", "new2"}),
+		"deleting a missing record should not fail")
+}
@@ -0,0 +1,467 @@
+package fleethealth
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = hivev1.FleetHealthControllerName
+
+	// refreshInterval is how often the health of the clusters is summarized again.
+	refreshInterval = 5 * time.Minute
+
+	defaultMaxClustersPerCategory = 100
+	defaultPowerStateTimeout      = time.Hour
+)
+
+// categories are the categories of unhealthy clusters, in the order they are reported.
+var categories = []hivev1.FleetHealthCategory{
+	hivev1.FleetHealthUnreachable,
+	hivev1.FleetHealthSyncFailed,
+	hivev1.FleetHealthOperatorsDegraded,
+	hivev1.FleetHealthPowerStateStuck,
+	hivev1.FleetHealthMachinePoolErrors,
+}
+
+// machinePoolErrorConditions are the MachinePool conditions reporting an error when true.
+var machinePoolErrorConditions = []hivev1.MachinePoolConditionType{
+	hivev1.NotEnoughReplicasMachinePoolCondition,
+	hivev1.NoMachinePoolNameLeasesAvailable,
+	hivev1.InvalidSubnetsMachinePoolCondition,
+	hivev1.UnsupportedConfigurationMachinePoolCondition,
+}
+
+// Add creates a new FleetHealth controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileFleetHealth{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		logger: logger,
+	}
+
+	c, err := controller.New("fleethealth-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, r.logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to the spec of FleetHealth. The health of the clusters is summarized periodically rather than
+	// on every change to the resources it summarizes, which change often in large fleets.
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.FleetHealth{},
+		&handler.TypedEnqueueRequestForObject[*hivev1.FleetHealth]{},
+		predicate.TypedGenerationChangedPredicate[*hivev1.FleetHealth]{})); err != nil {
+		logger.WithError(err).Error("Error watching FleetHealth")
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileFleetHealth{}
+
+// ReconcileFleetHealth summarizes the health of the clusters selected by FleetHealths.
+type ReconcileFleetHealth struct {
+	client.Client
+
+	logger log.FieldLogger
+}
+
+// clusterResources are the resources describing the health of the clusters, indexed by ClusterDeployment.
+type clusterResources struct {
+	clusterSyncs  map[string]*hiveintv1alpha1.ClusterSync
+	clusterStates map[string]*hivev1.ClusterState
+	machinePools  map[string][]*hivev1.MachinePool
+}
+
+func clusterKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// Reconcile summarizes the health of the ClusterDeployments selected by a FleetHealth in its status.
+func (r *ReconcileFleetHealth) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := controllerutils.BuildControllerLogger(ControllerName, "fleetHealth", request.NamespacedName)
+	logger.Info("reconciling fleet health")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, logger)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	fh := &hivev1.FleetHealth{}
+	if err := r.Get(ctx, request.NamespacedName, fh); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("fleet health not found")
+			return reconcile.Result{}, nil
+		}
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error getting fleet health")
+		return reconcile.Result{}, err
+	}
+	if fh.DeletionTimestamp != nil {
+		logger.Debug("fleet health is being deleted")
+		return reconcile.Result{}, nil
+	}
+
+	cdSelector, err := metav1.LabelSelectorAsSelector(&fh.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		logger.WithError(err).Warn("cannot parse clusterdeployment selector")
+		return reconcile.Result{}, nil
+	}
+	nsSelector, err := metav1.LabelSelectorAsSelector(&fh.Spec.NamespaceSelector)
+	if err != nil {
+		logger.WithError(err).Warn("cannot parse namespace selector")
+		return reconcile.Result{}, nil
+	}
+
+	cds := &hivev1.ClusterDeploymentList{}
+	if err := r.List(ctx, cds, client.MatchingLabelsSelector{Selector: cdSelector}); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing cluster deployments")
+		return reconcile.Result{}, err
+	}
+	if !nsSelector.Empty() {
+		namespaces := &corev1.NamespaceList{}
+		if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: nsSelector}); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing namespaces")
+			return reconcile.Result{}, err
+		}
+		selected := sets.New[string]()
+		for _, ns := range namespaces.Items {
+			selected.Insert(ns.Name)
+		}
+		items := cds.Items[:0]
+		for _, cd := range cds.Items {
+			if selected.Has(cd.Namespace) {
+				items = append(items, cd)
+			}
+		}
+		cds.Items = items
+	}
+
+	resources, err := r.listClusterResources(ctx, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	fh.Status = summarize(fh, cds.Items, resources, time.Now())
+	if err := r.Status().Update(ctx, fh); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error updating fleet health status")
+		return reconcile.Result{}, err
+	}
+	logger.WithField("clusters", fh.Status.Clusters).WithField("unhealthy", fh.Status.UnhealthyClusters).Info("fleet health updated")
+	return reconcile.Result{RequeueAfter: refreshInterval}, nil
+}
+
+// listClusterResources lists the ClusterSyncs, ClusterStates and MachinePools of all the clusters.
+func (r *ReconcileFleetHealth) listClusterResources(ctx context.Context, logger log.FieldLogger) (*clusterResources, error) {
+	resources := &clusterResources{
+		clusterSyncs:  map[string]*hiveintv1alpha1.ClusterSync{},
+		clusterStates: map[string]*hivev1.ClusterState{},
+		machinePools:  map[string][]*hivev1.MachinePool{},
+	}
+	clusterSyncs := &hiveintv1alpha1.ClusterSyncList{}
+	if err := r.List(ctx, clusterSyncs); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing cluster syncs")
+		return nil, err
+	}
+	for i, cs := range clusterSyncs.Items {
+		resources.clusterSyncs[clusterKey(cs.Namespace, cs.Name)] = &clusterSyncs.Items[i]
+	}
+	clusterStates := &hivev1.ClusterStateList{}
+	if err := r.List(ctx, clusterStates); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing cluster states")
+		return nil, err
+	}
+	for i, st := range clusterStates.Items {
+		resources.clusterStates[clusterKey(st.Namespace, st.Name)] = &clusterStates.Items[i]
+	}
+	machinePools := &hivev1.MachinePoolList{}
+	if err := r.List(ctx, machinePools); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error listing machine pools")
+		return nil, err
+	}
+	for i, pool := range machinePools.Items {
+		key := clusterKey(pool.Namespace, pool.Spec.ClusterDeploymentRef.Name)
+		resources.machinePools[key] = append(resources.machinePools[key], &machinePools.Items[i])
+	}
+	return resources, nil
+}
+
+// summarize computes the health of the ClusterDeployments.
+func summarize(fh *hivev1.FleetHealth, cds []hivev1.ClusterDeployment, resources *clusterResources, now time.Time) hivev1.FleetHealthStatus {
+	maxClusters := int(ptr.Deref(fh.Spec.MaxClustersPerCategory, defaultMaxClustersPerCategory))
+	powerStateTimeout := defaultPowerStateTimeout
+	if fh.Spec.PowerStateTimeout != nil {
+		powerStateTimeout = fh.Spec.PowerStateTimeout.Duration
+	}
+
+	sort.Slice(cds, func(i, j int) bool {
+		a, b := cds[i], cds[j]
+		return a.Namespace < b.Namespace || (a.Namespace == b.Namespace && a.Name < b.Name)
+	})
+	byCategory := map[hivev1.FleetHealthCategory]*hivev1.FleetHealthCategoryStatus{}
+	for _, category := range categories {
+		byCategory[category] = &hivev1.FleetHealthCategoryStatus{Category: category}
+	}
+	status := hivev1.FleetHealthStatus{
+		Clusters:    int32(len(cds)),
+		LastUpdated: &metav1.Time{Time: now},
+	}
+	for i := range cds {
+		cd := &cds[i]
+		if !cd.Spec.Installed {
+			continue
+		}
+		key := clusterKey(cd.Namespace, cd.Name)
+		entries := map[hivev1.FleetHealthCategory]*hivev1.FleetHealthCluster{
+			hivev1.FleetHealthPowerStateStuck: powerStateStuck(cd, powerStateTimeout, now),
+		}
+		// Hibernating clusters are expected to be unreachable, and their state is stale.
+		if cd.Spec.PowerState != hivev1.ClusterPowerStateHibernating {
+			entries[hivev1.FleetHealthUnreachable] = unreachable(cd)
+			entries[hivev1.FleetHealthSyncFailed] = syncFailed(resources.clusterSyncs[key])
+			entries[hivev1.FleetHealthOperatorsDegraded] = operatorsDegraded(resources.clusterStates[key])
+			entries[hivev1.FleetHealthMachinePoolErrors] = machinePoolErrors(resources.machinePools[key])
+		}
+		healthy := true
+		for _, category := range categories {
+			entry := entries[category]
+			if entry == nil {
+				continue
+			}
+			healthy = false
+			cs := byCategory[category]
+			cs.Count++
+			if len(cs.Clusters) >= maxClusters {
+				continue
+			}
+			entry.Namespace = cd.Namespace
+			entry.Name = cd.Name
+			for _, k := range fh.Spec.LabelKeys {
+				if v, ok := cd.Labels[k]; ok {
+					if entry.Labels == nil {
+						entry.Labels = map[string]string{}
+					}
+					entry.Labels[k] = v
+				}
+			}
+			cs.Clusters = append(cs.Clusters, *entry)
+		}
+		if healthy {
+			status.HealthyClusters++
+		} else {
+			status.UnhealthyClusters++
+		}
+	}
+	for _, category := range categories {
+		status.Categories = append(status.Categories, *byCategory[category])
+	}
+	return status
+}
+
+func clusterDeploymentReference(cd *hivev1.ClusterDeployment) hivev1.FleetHealthResourceReference {
+	return hivev1.FleetHealthResourceReference{
+		APIVersion: hivev1.SchemeGroupVersion.String(),
+		Kind:       "ClusterDeployment",
+		Namespace:  cd.Namespace,
+		Name:       cd.Name,
+	}
+}
+
+// unreachable reports a cluster whose API cannot be reached.
+func unreachable(cd *hivev1.ClusterDeployment) *hivev1.FleetHealthCluster {
+	cond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.UnreachableCondition)
+	if cond == nil || cond.Status != corev1.ConditionTrue {
+		return nil
+	}
+	return &hivev1.FleetHealthCluster{
+		Since:     ptr.To(cond.LastTransitionTime),
+		Message:   cond.Message,
+		Resources: []hivev1.FleetHealthResourceReference{clusterDeploymentReference(cd)},
+	}
+}
+
+// syncFailed reports a cluster with SyncSets or SelectorSyncSets failing to apply.
+func syncFailed(cs *hiveintv1alpha1.ClusterSync) *hivev1.FleetHealthCluster {
+	if cs == nil {
+		return nil
+	}
+	var failed *hiveintv1alpha1.ClusterSyncCondition
+	for i, cond := range cs.Status.Conditions {
+		if cond.Type == hiveintv1alpha1.ClusterSyncFailed && cond.Status == corev1.ConditionTrue {
+			failed = &cs.Status.Conditions[i]
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+	entry := &hivev1.FleetHealthCluster{
+		Since:   ptr.To(failed.LastTransitionTime),
+		Message: failed.Message,
+		Resources: []hivev1.FleetHealthResourceReference{{
+			APIVersion: hiveintv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ClusterSync",
+			Namespace:  cs.Namespace,
+			Name:       cs.Name,
+		}},
+	}
+	for _, s := range cs.Status.SyncSets {
+		if s.Result == hiveintv1alpha1.FailureSyncSetResult {
+			entry.Resources = append(entry.Resources, hivev1.FleetHealthResourceReference{
+				APIVersion: hivev1.SchemeGroupVersion.String(),
+				Kind:       "SyncSet",
+				Namespace:  cs.Namespace,
+				Name:       s.Name,
+			})
+		}
+	}
+	for _, s := range cs.Status.SelectorSyncSets {
+		if s.Result == hiveintv1alpha1.FailureSyncSetResult {
+			entry.Resources = append(entry.Resources, hivev1.FleetHealthResourceReference{
+				APIVersion: hivev1.SchemeGroupVersion.String(),
+				Kind:       "SelectorSyncSet",
+				Name:       s.Name,
+			})
+		}
+	}
+	return entry
+}
+
+// operatorsDegraded reports a cluster with cluster operators degraded or unavailable.
+func operatorsDegraded(st *hivev1.ClusterState) *hivev1.FleetHealthCluster {
+	if st == nil {
+		return nil
+	}
+	var degraded, unavailable []string
+	var since *metav1.Time
+	for _, operator := range st.Status.ClusterOperators {
+		for _, cond := range operator.Conditions {
+			switch {
+			case cond.Type == configv1.OperatorDegraded && cond.Status == configv1.ConditionTrue:
+				degraded = append(degraded, operator.Name)
+			case cond.Type == configv1.OperatorAvailable && cond.Status == configv1.ConditionFalse:
+				unavailable = append(unavailable, operator.Name)
+			default:
+				continue
+			}
+			if since == nil || cond.LastTransitionTime.Before(since) {
+				since = ptr.To(cond.LastTransitionTime)
+			}
+		}
+	}
+	if len(degraded) == 0 && len(unavailable) == 0 {
+		return nil
+	}
+	var messages []string
+	if len(degraded) > 0 {
+		messages = append(messages, "degraded: "+strings.Join(degraded, ", "))
+	}
+	if len(unavailable) > 0 {
+		messages = append(messages, "unavailable: "+strings.Join(unavailable, ", "))
+	}
+	return &hivev1.FleetHealthCluster{
+		Since:   since,
+		Message: strings.Join(messages, "; "),
+		Resources: []hivev1.FleetHealthResourceReference{{
+			APIVersion: hivev1.SchemeGroupVersion.String(),
+			Kind:       "ClusterState",
+			Namespace:  st.Namespace,
+			Name:       st.Name,
+		}},
+	}
+}
+
+// powerStateStuck reports a cluster which has not reached the requested power state within the timeout. The
+// Hibernating condition tracks hibernation, and the Ready condition tracks resuming.
+func powerStateStuck(cd *hivev1.ClusterDeployment, timeout time.Duration, now time.Time) *hivev1.FleetHealthCluster {
+	var cond *hivev1.ClusterDeploymentCondition
+	var ignoredReasons sets.Set[string]
+	desired := "running"
+	if cd.Spec.PowerState == hivev1.ClusterPowerStateHibernating {
+		desired = "hibernating"
+		cond = controllerutils.FindCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition)
+		ignoredReasons = sets.New(hivev1.HibernatingReasonUnsupported, hivev1.HibernatingReasonPowerStatePaused)
+	} else {
+		cond = controllerutils.FindCondition(cd.Status.Conditions, hivev1.ClusterReadyCondition)
+		ignoredReasons = sets.New(hivev1.ReadyReasonPowerStatePaused)
+	}
+	if cond == nil || cond.Status != corev1.ConditionFalse || ignoredReasons.Has(cond.Reason) {
+		return nil
+	}
+	if now.Sub(cond.LastTransitionTime.Time) < timeout {
+		return nil
+	}
+	return &hivev1.FleetHealthCluster{
+		Since:     ptr.To(cond.LastTransitionTime),
+		Message:   fmt.Sprintf("not %s: %s: %s", desired, cond.Reason, cond.Message),
+		Resources: []hivev1.FleetHealthResourceReference{clusterDeploymentReference(cd)},
+	}
+}
+
+// machinePoolErrors reports a cluster with MachinePools reporting errors.
+func machinePoolErrors(pools []*hivev1.MachinePool) *hivev1.FleetHealthCluster {
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	var entry *hivev1.FleetHealthCluster
+	var messages []string
+	for _, pool := range pools {
+		failing := false
+		for _, ctype := range machinePoolErrorConditions {
+			cond := controllerutils.FindCondition(pool.Status.Conditions, ctype)
+			if cond == nil || cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			if entry == nil {
+				entry = &hivev1.FleetHealthCluster{}
+			}
+			if entry.Since == nil || cond.LastTransitionTime.Before(entry.Since) {
+				entry.Since = ptr.To(cond.LastTransitionTime)
+			}
+			messages = append(messages, fmt.Sprintf("%s: %s: %s", pool.Name, ctype, cond.Message))
+			failing = true
+		}
+		if failing {
+			entry.Resources = append(entry.Resources, hivev1.FleetHealthResourceReference{
+				APIVersion: hivev1.SchemeGroupVersion.String(),
+				Kind:       "MachinePool",
+				Namespace:  pool.Namespace,
+				Name:       pool.Name,
+			})
+		}
+	}
+	if entry != nil {
+		entry.Message = strings.Join(messages, "; ")
+	}
+	return entry
+}
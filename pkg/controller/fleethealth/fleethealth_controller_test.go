@@ -0,0 +1,405 @@
+package fleethealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveintv1alpha1 "github.com/openshift/hive/apis/hiveinternal/v1alpha1"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace = "test-namespace"
+	testName      = "test-fleet"
+)
+
+func testFleetHealth(opts ...func(*hivev1.FleetHealth)) *hivev1.FleetHealth {
+	fh := &hivev1.FleetHealth{
+		ObjectMeta: metav1.ObjectMeta{Name: testName},
+	}
+	for _, o := range opts {
+		o(fh)
+	}
+	return fh
+}
+
+func testClusterDeployment(name string, opts ...testcd.Option) *hivev1.ClusterDeployment {
+	return testcd.FullBuilder(testNamespace, name, scheme.GetScheme()).Build(append([]testcd.Option{testcd.Installed()}, opts...)...)
+}
+
+func condition(ctype hivev1.ClusterDeploymentConditionType, status corev1.ConditionStatus, reason string, ago time.Duration) testcd.Option {
+	return testcd.WithCondition(hivev1.ClusterDeploymentCondition{
+		Type:               ctype,
+		Status:             status,
+		Reason:             reason,
+		Message:            "test message",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-ago)),
+	})
+}
+
+func testClusterSync(name string, failedSyncSets, failedSelectorSyncSets []string) *hiveintv1alpha1.ClusterSync {
+	cs := &hiveintv1alpha1.ClusterSync{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Status: hiveintv1alpha1.ClusterSyncStatus{
+			Conditions: []hiveintv1alpha1.ClusterSyncCondition{{
+				Type:               hiveintv1alpha1.ClusterSyncFailed,
+				Status:             corev1.ConditionTrue,
+				Message:            "SyncSet failed to apply",
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+			}},
+			SyncSets: []hiveintv1alpha1.SyncStatus{{
+				Name:   "healthy-syncset",
+				Result: hiveintv1alpha1.SuccessSyncSetResult,
+			}},
+		},
+	}
+	for _, name := range failedSyncSets {
+		cs.Status.SyncSets = append(cs.Status.SyncSets, hiveintv1alpha1.SyncStatus{
+			Name:   name,
+			Result: hiveintv1alpha1.FailureSyncSetResult,
+		})
+	}
+	for _, name := range failedSelectorSyncSets {
+		cs.Status.SelectorSyncSets = append(cs.Status.SelectorSyncSets, hiveintv1alpha1.SyncStatus{
+			Name:   name,
+			Result: hiveintv1alpha1.FailureSyncSetResult,
+		})
+	}
+	return cs
+}
+
+func testClusterState(name string, operators ...hivev1.ClusterOperatorState) *hivev1.ClusterState {
+	return &hivev1.ClusterState{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Status:     hivev1.ClusterStateStatus{ClusterOperators: operators},
+	}
+}
+
+func operatorState(name string, degraded, available configv1.ConditionStatus) hivev1.ClusterOperatorState {
+	return hivev1.ClusterOperatorState{
+		Name: name,
+		Conditions: []configv1.ClusterOperatorStatusCondition{
+			{Type: configv1.OperatorDegraded, Status: degraded, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+			{Type: configv1.OperatorAvailable, Status: available, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour))},
+		},
+	}
+}
+
+func testMachinePool(cdName, pool string, conditions ...hivev1.MachinePoolConditionType) *hivev1.MachinePool {
+	mp := &hivev1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: cdName + "-" + pool},
+		Spec: hivev1.MachinePoolSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: cdName},
+			Name:                 pool,
+		},
+	}
+	for _, ctype := range conditions {
+		mp.Status.Conditions = append(mp.Status.Conditions, hivev1.MachinePoolCondition{
+			Type:               ctype,
+			Status:             corev1.ConditionTrue,
+			Message:            "test message",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+		})
+	}
+	return mp
+}
+
+func TestReconcileFleetHealth(t *testing.T) {
+	cases := []struct {
+		name              string
+		fleetHealth       *hivev1.FleetHealth
+		existing          []runtime.Object
+		expectedClusters  int32
+		expectedHealthy   int32
+		expectedUnhealthy int32
+		// expectedCategories are the names of the clusters listed in each category
+		expectedCategories map[hivev1.FleetHealthCategory][]string
+		// expectedCounts are the counts of the categories which differ from the number of clusters listed
+		expectedCounts map[hivev1.FleetHealthCategory]int32
+		validate       func(t *testing.T, status hivev1.FleetHealthStatus)
+	}{
+		{
+			name:        "no clusters",
+			fleetHealth: testFleetHealth(),
+		},
+		{
+			name:        "healthy and installing clusters",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("healthy", condition(hivev1.ClusterReadyCondition, corev1.ConditionTrue, "", time.Hour)),
+				testcd.FullBuilder(testNamespace, "installing", scheme.GetScheme()).Build(),
+				testClusterState("healthy", operatorState("ingress", configv1.ConditionFalse, configv1.ConditionTrue)),
+				testMachinePool("healthy", "worker"),
+			},
+			expectedClusters: 2,
+			expectedHealthy:  1,
+		},
+		{
+			name:        "unreachable",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("unreachable", condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testClusterDeployment("reachable", condition(hivev1.UnreachableCondition, corev1.ConditionFalse, "", time.Hour)),
+			},
+			expectedClusters:  2,
+			expectedHealthy:   1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthUnreachable: {"unreachable"},
+			},
+			validate: func(t *testing.T, status hivev1.FleetHealthStatus) {
+				entry := status.Categories[0].Clusters[0]
+				assert.Equal(t, "test message", entry.Message)
+				assert.NotNil(t, entry.Since)
+				assert.Equal(t, []hivev1.FleetHealthResourceReference{{
+					APIVersion: "hive.openshift.io/v1",
+					Kind:       "ClusterDeployment",
+					Namespace:  testNamespace,
+					Name:       "unreachable",
+				}}, entry.Resources)
+			},
+		},
+		{
+			name:        "sync failed",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("failing"),
+				testClusterSync("failing", []string{"bad-syncset"}, []string{"bad-selectorsyncset"}),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthSyncFailed: {"failing"},
+			},
+			validate: func(t *testing.T, status hivev1.FleetHealthStatus) {
+				assert.Equal(t, []hivev1.FleetHealthResourceReference{
+					{APIVersion: "hiveinternal.openshift.io/v1alpha1", Kind: "ClusterSync", Namespace: testNamespace, Name: "failing"},
+					{APIVersion: "hive.openshift.io/v1", Kind: "SyncSet", Namespace: testNamespace, Name: "bad-syncset"},
+					{APIVersion: "hive.openshift.io/v1", Kind: "SelectorSyncSet", Name: "bad-selectorsyncset"},
+				}, status.Categories[1].Clusters[0].Resources)
+			},
+		},
+		{
+			name:        "operators degraded",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("degraded"),
+				testClusterState("degraded",
+					operatorState("dns", configv1.ConditionFalse, configv1.ConditionTrue),
+					operatorState("ingress", configv1.ConditionTrue, configv1.ConditionTrue),
+					operatorState("network", configv1.ConditionFalse, configv1.ConditionFalse),
+				),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthOperatorsDegraded: {"degraded"},
+			},
+			validate: func(t *testing.T, status hivev1.FleetHealthStatus) {
+				assert.Equal(t, "degraded: ingress; unavailable: network", status.Categories[2].Clusters[0].Message)
+			},
+		},
+		{
+			name:        "power state stuck",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("stuck-hibernating",
+					testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+					condition(hivev1.ClusterHibernatingCondition, corev1.ConditionFalse, hivev1.HibernatingReasonStopping, 2*time.Hour)),
+				testClusterDeployment("hibernating-recently",
+					testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+					condition(hivev1.ClusterHibernatingCondition, corev1.ConditionFalse, hivev1.HibernatingReasonStopping, 10*time.Minute)),
+				testClusterDeployment("hibernation-unsupported",
+					testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+					condition(hivev1.ClusterHibernatingCondition, corev1.ConditionFalse, hivev1.HibernatingReasonUnsupported, 2*time.Hour)),
+				testClusterDeployment("stuck-resuming",
+					testcd.WithPowerState(hivev1.ClusterPowerStateRunning),
+					condition(hivev1.ClusterReadyCondition, corev1.ConditionFalse, hivev1.ReadyReasonWaitingForMachines, 2*time.Hour)),
+				testClusterDeployment("paused",
+					condition(hivev1.ClusterReadyCondition, corev1.ConditionFalse, hivev1.ReadyReasonPowerStatePaused, 2*time.Hour)),
+			},
+			expectedClusters:  5,
+			expectedHealthy:   3,
+			expectedUnhealthy: 2,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthPowerStateStuck: {"stuck-hibernating", "stuck-resuming"},
+			},
+		},
+		{
+			name: "power state timeout",
+			fleetHealth: testFleetHealth(func(fh *hivev1.FleetHealth) {
+				fh.Spec.PowerStateTimeout = &metav1.Duration{Duration: 5 * time.Minute}
+			}),
+			existing: []runtime.Object{
+				testClusterDeployment("hibernating-recently",
+					testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+					condition(hivev1.ClusterHibernatingCondition, corev1.ConditionFalse, hivev1.HibernatingReasonStopping, 10*time.Minute)),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthPowerStateStuck: {"hibernating-recently"},
+			},
+		},
+		{
+			name:        "hibernating clusters are not unreachable",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("hibernating",
+					testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+					condition(hivev1.ClusterHibernatingCondition, corev1.ConditionTrue, hivev1.HibernatingReasonHibernating, 2*time.Hour),
+					condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", 2*time.Hour)),
+				testClusterState("hibernating", operatorState("ingress", configv1.ConditionTrue, configv1.ConditionFalse)),
+			},
+			expectedClusters: 1,
+			expectedHealthy:  1,
+		},
+		{
+			name:        "machine pool errors",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("failing"),
+				testMachinePool("failing", "worker", hivev1.InvalidSubnetsMachinePoolCondition),
+				testMachinePool("failing", "infra"),
+				testMachinePool("failing", "gpu", hivev1.NotEnoughReplicasMachinePoolCondition),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthMachinePoolErrors: {"failing"},
+			},
+			validate: func(t *testing.T, status hivev1.FleetHealthStatus) {
+				entry := status.Categories[4].Clusters[0]
+				assert.Equal(t, "failing-gpu: NotEnoughReplicas: test message; failing-worker: InvalidSubnets: test message", entry.Message)
+				if assert.Len(t, entry.Resources, 2) {
+					assert.Equal(t, "failing-gpu", entry.Resources[0].Name)
+					assert.Equal(t, "failing-worker", entry.Resources[1].Name)
+				}
+			},
+		},
+		{
+			name:        "several categories",
+			fleetHealth: testFleetHealth(),
+			existing: []runtime.Object{
+				testClusterDeployment("failing", condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testClusterSync("failing", []string{"bad-syncset"}, nil),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthUnreachable: {"failing"},
+				hivev1.FleetHealthSyncFailed:  {"failing"},
+			},
+		},
+		{
+			name: "selectors and label keys",
+			fleetHealth: testFleetHealth(func(fh *hivev1.FleetHealth) {
+				fh.Spec.ClusterDeploymentSelector = metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+				fh.Spec.NamespaceSelector = metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}
+				fh.Spec.LabelKeys = []string{"env", "region"}
+			}),
+			existing: []runtime.Object{
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: map[string]string{"team": "a"}}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Labels: map[string]string{"team": "b"}}},
+				testClusterDeployment("prod",
+					testcd.WithLabel("env", "prod"),
+					testcd.WithLabel("region", "us-east-1"),
+					testcd.WithLabel("owner", "someone"),
+					condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testClusterDeployment("dev",
+					testcd.WithLabel("env", "dev"),
+					condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testcd.FullBuilder("other-namespace", "prod", scheme.GetScheme()).Build(
+					testcd.Installed(),
+					testcd.WithLabel("env", "prod"),
+					condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+			},
+			expectedClusters:  1,
+			expectedUnhealthy: 1,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthUnreachable: {"prod"},
+			},
+			validate: func(t *testing.T, status hivev1.FleetHealthStatus) {
+				assert.Equal(t, map[string]string{"env": "prod", "region": "us-east-1"}, status.Categories[0].Clusters[0].Labels)
+			},
+		},
+		{
+			name: "max clusters per category",
+			fleetHealth: testFleetHealth(func(fh *hivev1.FleetHealth) {
+				fh.Spec.MaxClustersPerCategory = ptr.To[int32](2)
+			}),
+			existing: []runtime.Object{
+				testClusterDeployment("c", condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testClusterDeployment("a", condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+				testClusterDeployment("b", condition(hivev1.UnreachableCondition, corev1.ConditionTrue, "", time.Hour)),
+			},
+			expectedClusters:  3,
+			expectedUnhealthy: 3,
+			expectedCategories: map[hivev1.FleetHealthCategory][]string{
+				hivev1.FleetHealthUnreachable: {"a", "b"},
+			},
+			expectedCounts: map[hivev1.FleetHealthCategory]int32{
+				hivev1.FleetHealthUnreachable: 3,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := append([]runtime.Object{tc.fleetHealth}, tc.existing...)
+			fakeClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+			r := &ReconcileFleetHealth{
+				Client: fakeClient,
+				logger: log.WithField("controller", "fleetHealth"),
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testName},
+			})
+			require.NoError(t, err, "unexpected error from Reconcile")
+			assert.Equal(t, refreshInterval, result.RequeueAfter, "unexpected requeue")
+
+			fh := &hivev1.FleetHealth{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: testName}, fh))
+			assert.Equal(t, tc.expectedClusters, fh.Status.Clusters, "unexpected clusters")
+			assert.Equal(t, tc.expectedHealthy, fh.Status.HealthyClusters, "unexpected healthy clusters")
+			assert.Equal(t, tc.expectedUnhealthy, fh.Status.UnhealthyClusters, "unexpected unhealthy clusters")
+			assert.NotNil(t, fh.Status.LastUpdated, "expected last updated")
+			if assert.Len(t, fh.Status.Categories, len(categories), "expected all the categories") {
+				for i, category := range categories {
+					cs := fh.Status.Categories[i]
+					assert.Equal(t, category, cs.Category, "unexpected category order")
+					var names []string
+					for _, c := range cs.Clusters {
+						names = append(names, c.Name)
+					}
+					assert.Equal(t, tc.expectedCategories[category], names, "unexpected clusters for %s", category)
+					count, ok := tc.expectedCounts[category]
+					if !ok {
+						count = int32(len(tc.expectedCategories[category]))
+					}
+					assert.Equal(t, count, cs.Count, "unexpected count for %s", category)
+				}
+			}
+			if tc.validate != nil {
+				tc.validate(t, fh.Status)
+			}
+		})
+	}
+}
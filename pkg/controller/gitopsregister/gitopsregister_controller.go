@@ -0,0 +1,498 @@
+package gitopsregister
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// ControllerName is the name of this controller
+	ControllerName = hivev1.GitOpsRegisterControllerName
+)
+
+// Add creates a new GitOpsRegister controller and adds it to the manager with default RBAC. The controller runs even
+// without GitOps registrations, to delete the objects of clusters registered before they were removed.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", ControllerName)
+
+	registrations, err := ReadGitOpsRegistrationConfig()
+	if err != nil {
+		logger.WithError(err).Error("could not read GitOps registration configuration")
+		return err
+	}
+
+	concurrentReconciles, clientRateLimiter, queueRateLimiter, err := controllerutils.GetControllerConfig(mgr.GetClient(), ControllerName)
+	if err != nil {
+		logger.WithError(err).Error("could not get controller configurations")
+		return err
+	}
+
+	r := &ReconcileGitOpsRegister{
+		Client:        controllerutils.NewClientWithMetricsOrDie(mgr, ControllerName, &clientRateLimiter),
+		logger:        logger,
+		registrations: registrations,
+	}
+
+	c, err := controller.New("gitopsregister-controller", mgr, controller.Options{
+		Reconciler:              controllerutils.NewDelayingReconciler(r, logger),
+		MaxConcurrentReconciles: concurrentReconciles,
+		RateLimiter:             queueRateLimiter,
+	})
+	if err != nil {
+		logger.WithError(err).Error("error creating controller")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	if err := c.Watch(source.Kind(mgr.GetCache(), &hivev1.ClusterDeployment{}, controllerutils.NewTypedRateLimitedUpdateEventHandler(&handler.TypedEnqueueRequestForObject[*hivev1.ClusterDeployment]{}, controllerutils.IsClusterDeploymentErrorUpdateEvent))); err != nil {
+		logger.WithError(err).Error("Error watching ClusterDeployment")
+		return err
+	}
+
+	// Watch for changes to admin kubeconfig secrets, which the rendered objects may embed.
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, handler.TypedEnqueueRequestsFromMapFunc(
+		func(ctx context.Context, secret *corev1.Secret) []reconcile.Request {
+			cdName := secret.Labels[constants.ClusterDeploymentNameLabel]
+			if cdName == "" || secret.Labels[constants.SecretTypeLabel] != constants.SecretTypeKubeConfig {
+				return nil
+			}
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: secret.Namespace, Name: cdName}}}
+		}))); err != nil {
+		logger.WithError(err).Error("Error watching admin kubeconfig secrets")
+		return err
+	}
+
+	return nil
+}
+
+// ReadGitOpsRegistrationConfig reads the GitOps registrations from the file named by the
+// GitOpsRegistrationConfigFileEnvVar environment variable. It returns nil when the file is not available.
+func ReadGitOpsRegistrationConfig() ([]hivev1.GitOpsRegistration, error) {
+	path := os.Getenv(constants.GitOpsRegistrationConfigFileEnvVar)
+	if path == "" {
+		return nil, nil
+	}
+	fileBytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the GitOps registration config file")
+	}
+	var registrations []hivev1.GitOpsRegistration
+	if err := json.Unmarshal(fileBytes, &registrations); err != nil {
+		return nil, err
+	}
+	return registrations, nil
+}
+
+// registeredObject identifies an object registering a cluster with a GitOps system. The list of the objects of a
+// ClusterDeployment is kept in its GitOpsRegisteredObjectsAnnotation.
+type registeredObject struct {
+	Registration string `json:"registration"`
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	Namespace    string `json:"namespace,omitempty"`
+	Name         string `json:"name"`
+}
+
+func (o registeredObject) String() string {
+	return o.Kind + " " + types.NamespacedName{Namespace: o.Namespace, Name: o.Name}.String()
+}
+
+var _ reconcile.Reconciler = &ReconcileGitOpsRegister{}
+
+// ReconcileGitOpsRegister registers installed ClusterDeployments with GitOps systems, by creating the objects rendered
+// from the templates of the GitOps registrations, and deletes those objects when the clusters are deprovisioned.
+type ReconcileGitOpsRegister struct {
+	client.Client
+
+	logger        log.FieldLogger
+	registrations []hivev1.GitOpsRegistration
+}
+
+// Reconcile creates or updates the objects registering a ClusterDeployment, and deletes the objects of the
+// registrations which no longer select it.
+func (r *ReconcileGitOpsRegister) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	cdLog := controllerutils.BuildControllerLogger(ControllerName, "clusterDeployment", request.NamespacedName)
+	cdLog.Info("reconciling cluster deployment")
+	recobsrv := hivemetrics.NewReconcileObserver(ControllerName, cdLog)
+	defer recobsrv.ObserveControllerReconcileTime()
+
+	cd := &hivev1.ClusterDeployment{}
+	if err := r.Get(ctx, request.NamespacedName, cd); err != nil {
+		if apierrors.IsNotFound(err) {
+			cdLog.Debug("cluster deployment not found")
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+	cdLog = controllerutils.AddLogFields(controllerutils.MetaObjectLogTagger{Object: cd}, cdLog)
+
+	if paused, err := strconv.ParseBool(cd.Annotations[constants.ReconcilePauseAnnotation]); err == nil && paused {
+		cdLog.Info("skipping reconcile due to ClusterDeployment pause annotation")
+		return reconcile.Result{}, nil
+	}
+
+	existing, err := registeredObjects(cd)
+	if err != nil {
+		// The objects can no longer be tracked, so start over rather than blocking the cluster.
+		cdLog.WithError(err).Error("could not parse the registered objects annotation")
+	}
+
+	if !cd.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(cd, hivev1.FinalizerGitOpsRegistration) {
+			return reconcile.Result{}, nil
+		}
+		if err := r.deleteObjects(ctx, cd, existing, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+		controllerutil.RemoveFinalizer(cd, hivev1.FinalizerGitOpsRegistration)
+		if err := r.Update(ctx, cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to remove finalizer")
+			return reconcile.Result{}, err
+		}
+		cdLog.Info("deleted the objects registering the cluster")
+		return reconcile.Result{}, nil
+	}
+
+	registered, syncErr := r.syncObjects(ctx, cd, existing, cdLog)
+
+	// Delete the objects of the registrations no longer selecting the cluster.
+	var stale []registeredObject
+	current := map[registeredObject]bool{}
+	for _, obj := range registered {
+		current[obj] = true
+	}
+	for _, obj := range existing {
+		if !current[obj] {
+			stale = append(stale, obj)
+		}
+	}
+	if err := r.deleteObjects(ctx, cd, stale, cdLog); err != nil {
+		// Keep tracking the objects which could not be deleted.
+		registered = append(registered, stale...)
+		syncErr = utilerrors.NewAggregate([]error{syncErr, err})
+	}
+
+	if err := r.updateRegisteredObjects(ctx, cd, registered); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update the registered objects")
+		return reconcile.Result{}, err
+	}
+	if syncErr != nil {
+		cdLog.WithError(syncErr).Error("failed to register cluster")
+		return reconcile.Result{}, syncErr
+	}
+	return reconcile.Result{}, nil
+}
+
+// syncObjects creates or updates the objects of the registrations selecting a ClusterDeployment, and returns the
+// objects registering it. The objects of a registration with an invalid selector, or which fails to render, are kept.
+func (r *ReconcileGitOpsRegister) syncObjects(ctx context.Context, cd *hivev1.ClusterDeployment, existing []registeredObject, cdLog log.FieldLogger) ([]registeredObject, error) {
+	if !cd.Spec.Installed || cd.Spec.ClusterMetadata == nil || cd.Status.APIURL == "" {
+		cdLog.Debug("cluster is not installed")
+		return nil, nil
+	}
+	if enabled, err := strconv.ParseBool(cd.Labels[constants.GitOpsRegistrationLabel]); err == nil && !enabled {
+		cdLog.Debug("GitOps registration is disabled for the cluster")
+		return nil, nil
+	}
+
+	var data *templateData
+	var registered []registeredObject
+	var pending []pendingObject
+	var errs []error
+	keepExisting := func(registrationName string) {
+		for _, obj := range existing {
+			if obj.Registration == registrationName {
+				registered = append(registered, obj)
+			}
+		}
+	}
+	for i := range r.registrations {
+		registration := &r.registrations[i]
+		regLog := cdLog.WithField("registration", registration.Name)
+		if selector := registration.ClusterDeploymentSelector; selector != nil {
+			s, err := metav1.LabelSelectorAsSelector(selector)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "registration %s: invalid cluster deployment selector", registration.Name))
+				keepExisting(registration.Name)
+				continue
+			}
+			if !s.Matches(labels.Set(cd.Labels)) {
+				continue
+			}
+		}
+
+		if data == nil {
+			var err error
+			if data, err = r.templateData(ctx, cd); err != nil {
+				return existing, err
+			}
+		}
+		objects, err := render(registration, data)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "registration %s", registration.Name))
+			keepExisting(registration.Name)
+			continue
+		}
+		for _, obj := range objects {
+			ref, err := r.objectRef(cd, registration.Name, obj)
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "registration %s", registration.Name))
+				continue
+			}
+			pending = append(pending, pendingObject{ref: ref, obj: obj, log: regLog})
+		}
+	}
+
+	// Record the objects and add the finalizer before creating any of them, so that the objects of a
+	// ClusterDeployment deleted in the meantime are still cleaned up.
+	if len(pending) > 0 {
+		tracked := append([]registeredObject{}, existing...)
+		for _, p := range pending {
+			tracked = append(tracked, p.ref)
+		}
+		if err := r.updateRegisteredObjects(ctx, cd, tracked); err != nil {
+			return existing, errors.Wrap(err, "failed to record the objects registering the cluster")
+		}
+	}
+
+	for _, p := range pending {
+		owned, err := r.applyObject(ctx, cd, p.ref, p.obj, p.log)
+		if owned {
+			registered = append(registered, p.ref)
+		}
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "registration %s", p.ref.Registration))
+		}
+	}
+	return registered, utilerrors.NewAggregate(errs)
+}
+
+// pendingObject is an object rendered for a ClusterDeployment, before it is created or updated.
+type pendingObject struct {
+	ref registeredObject
+	obj *unstructured.Unstructured
+	log log.FieldLogger
+}
+
+// templateData reads the admin kubeconfig of a ClusterDeployment, for the templates of the registrations.
+func (r *ReconcileGitOpsRegister) templateData(ctx context.Context, cd *hivev1.ClusterDeployment) (*templateData, error) {
+	secretName := cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: cd.Namespace, Name: secretName}, secret); err != nil {
+		return nil, errors.Wrap(err, "failed to get the admin kubeconfig secret")
+	}
+	return &templateData{
+		ClusterDeployment:    cd,
+		KubeconfigSecretName: secretName,
+		Kubeconfig:           string(secret.Data[constants.KubeconfigSecretKey]),
+	}, nil
+}
+
+// objectRef defaults the namespace of an object rendered for a ClusterDeployment, and returns a reference to it.
+func (r *ReconcileGitOpsRegister) objectRef(cd *hivev1.ClusterDeployment, registrationName string, obj *unstructured.Unstructured) (registeredObject, error) {
+	namespaced, err := r.IsObjectNamespaced(obj)
+	if err != nil {
+		return registeredObject{}, errors.Wrapf(err, "failed to determine the scope of %s %s", obj.GetKind(), obj.GetName())
+	}
+	if namespaced && obj.GetNamespace() == "" {
+		obj.SetNamespace(cd.Namespace)
+	}
+	return registeredObject{
+		Registration: registrationName,
+		APIVersion:   obj.GetAPIVersion(),
+		Kind:         obj.GetKind(),
+		Namespace:    obj.GetNamespace(),
+		Name:         obj.GetName(),
+	}, nil
+}
+
+// applyObject creates or updates an object rendered for a ClusterDeployment. It returns whether the object belongs
+// to the ClusterDeployment, even if updating it fails.
+func (r *ReconcileGitOpsRegister) applyObject(ctx context.Context, cd *hivev1.ClusterDeployment, ref registeredObject, obj *unstructured.Unstructured, regLog log.FieldLogger) (bool, error) {
+	objLog := regLog.WithField("object", ref.String())
+
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+	objLabels[constants.CreatedByHiveLabel] = "true"
+	objLabels[constants.ClusterDeploymentNameLabel] = cd.Name
+	objLabels[constants.ClusterDeploymentNamespaceLabel] = cd.Namespace
+	objLabels[constants.GitOpsRegistrationNameLabel] = ref.Registration
+	obj.SetLabels(objLabels)
+	hash, err := controllerutils.GetChecksumOfObject(obj.Object)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to compute the checksum of %s", ref)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[constants.GitOpsRegistrationHashAnnotation] = hash
+	obj.SetAnnotations(annotations)
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GroupVersionKind())
+	switch err := r.Get(ctx, client.ObjectKeyFromObject(obj), current); {
+	case apierrors.IsNotFound(err):
+		objLog.Info("creating object")
+		if err := r.Create(ctx, obj); err != nil {
+			return false, wrapForbidden(err, "failed to create %s", ref)
+		}
+		return true, nil
+	case err != nil:
+		return false, wrapForbidden(err, "failed to get %s", ref)
+	}
+
+	if !ownedBy(current, cd) {
+		return false, errors.Errorf("%s already exists and does not belong to the cluster deployment", ref)
+	}
+	if current.GetAnnotations()[constants.GitOpsRegistrationHashAnnotation] == hash {
+		return true, nil
+	}
+	objLog.Info("updating object")
+	obj.SetResourceVersion(current.GetResourceVersion())
+	if err := r.Update(ctx, obj); err != nil {
+		return true, wrapForbidden(err, "failed to update %s", ref)
+	}
+	return true, nil
+}
+
+// wrapForbidden wraps an error from the API server, pointing out the missing RBAC when the controller is not allowed
+// to manage the kind of a rendered object.
+func wrapForbidden(err error, format string, ref registeredObject) error {
+	if apierrors.IsForbidden(err) {
+		return errors.Wrapf(err, format+": the hive-controllers service account needs get, create, update and delete permissions on %s in %s", ref, ref.Kind, ref.APIVersion)
+	}
+	return errors.Wrapf(err, format, ref)
+}
+
+// deleteObjects deletes objects registering a ClusterDeployment. Objects which no longer belong to the
+// ClusterDeployment are left alone.
+func (r *ReconcileGitOpsRegister) deleteObjects(ctx context.Context, cd *hivev1.ClusterDeployment, objects []registeredObject, cdLog log.FieldLogger) error {
+	var errs []error
+	for _, ref := range objects {
+		objLog := cdLog.WithFields(log.Fields{"registration": ref.Registration, "object": ref.String()})
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, obj); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errs = append(errs, wrapForbidden(err, "failed to get %s", ref))
+			}
+			continue
+		}
+		if !ownedBy(obj, cd) {
+			objLog.Warn("not deleting object which does not belong to the cluster deployment")
+			continue
+		}
+		objLog.Info("deleting object")
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, wrapForbidden(err, "failed to delete %s", ref))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// updateRegisteredObjects records the objects registering a ClusterDeployment, and ensures it has a finalizer as long
+// as there are any.
+func (r *ReconcileGitOpsRegister) updateRegisteredObjects(ctx context.Context, cd *hivev1.ClusterDeployment, objects []registeredObject) error {
+	sort.Slice(objects, func(i, j int) bool {
+		a, b := objects[i], objects[j]
+		if a.Registration != b.Registration {
+			return a.Registration < b.Registration
+		}
+		if a.String() != b.String() {
+			return a.String() < b.String()
+		}
+		return a.APIVersion < b.APIVersion
+	})
+	// Drop duplicates, as the objects about to be created are recorded along with the existing ones.
+	for i := 1; i < len(objects); i++ {
+		if objects[i] == objects[i-1] {
+			objects = append(objects[:i], objects[i+1:]...)
+			i--
+		}
+	}
+	var annotation string
+	if len(objects) > 0 {
+		b, err := json.Marshal(objects)
+		if err != nil {
+			return err
+		}
+		annotation = string(b)
+	}
+
+	changed := false
+	if cd.Annotations[constants.GitOpsRegisteredObjectsAnnotation] != annotation {
+		if annotation == "" {
+			delete(cd.Annotations, constants.GitOpsRegisteredObjectsAnnotation)
+		} else {
+			if cd.Annotations == nil {
+				cd.Annotations = map[string]string{}
+			}
+			cd.Annotations[constants.GitOpsRegisteredObjectsAnnotation] = annotation
+		}
+		changed = true
+	}
+	if len(objects) > 0 {
+		changed = controllerutil.AddFinalizer(cd, hivev1.FinalizerGitOpsRegistration) || changed
+	} else {
+		changed = controllerutil.RemoveFinalizer(cd, hivev1.FinalizerGitOpsRegistration) || changed
+	}
+	if !changed {
+		return nil
+	}
+	return r.Update(ctx, cd)
+}
+
+// registeredObjects returns the objects registering a ClusterDeployment, from its GitOpsRegisteredObjectsAnnotation.
+func registeredObjects(cd *hivev1.ClusterDeployment) ([]registeredObject, error) {
+	annotation := cd.Annotations[constants.GitOpsRegisteredObjectsAnnotation]
+	if annotation == "" {
+		return nil, nil
+	}
+	var objects []registeredObject
+	if err := json.Unmarshal([]byte(annotation), &objects); err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// ownedBy returns whether an object was created to register a ClusterDeployment.
+func ownedBy(obj *unstructured.Unstructured, cd *hivev1.ClusterDeployment) bool {
+	objLabels := obj.GetLabels()
+	return objLabels[constants.CreatedByHiveLabel] == "true" &&
+		objLabels[constants.ClusterDeploymentNameLabel] == cd.Name &&
+		objLabels[constants.ClusterDeploymentNamespaceLabel] == cd.Namespace
+}
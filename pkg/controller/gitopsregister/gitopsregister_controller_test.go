@@ -0,0 +1,351 @@
+package gitopsregister
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	testgeneric "github.com/openshift/hive/pkg/test/generic"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNamespace            = "test-namespace"
+	testName                 = "test-cluster"
+	testKubeconfigSecretName = "test-cluster-admin-kubeconfig"
+	testKubeconfig           = "apiVersion: v1\nkind: Config\n"
+	fluxNamespace            = "flux-system"
+)
+
+// fluxTemplate renders a kubeconfig secret for Flux, and a config map standing in for the Flux Kustomization.
+const fluxTemplate = `apiVersion: v1
+kind: Secret
+metadata:
+  name: {{ .ClusterDeployment.Name }}-kubeconfig
+  namespace: flux-system
+stringData:
+  value: {{ .Kubeconfig | toJSON }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .ClusterDeployment.Name }}
+data:
+  kubeconfigSecret: {{ .KubeconfigSecretName }}
+  region: {{ index .ClusterDeployment.Labels "region" | toJSON }}
+`
+
+func testRegistration(name string) hivev1.GitOpsRegistration {
+	return hivev1.GitOpsRegistration{Name: name, Template: fluxTemplate}
+}
+
+func testClusterDeployment(opts ...testcd.Option) *hivev1.ClusterDeployment {
+	return testcd.FullBuilder(testNamespace, testName, scheme.GetScheme()).Build(append([]testcd.Option{
+		testcd.Installed(),
+		testcd.WithLabel("region", "us-east-1"),
+		func(cd *hivev1.ClusterDeployment) {
+			cd.Spec.ClusterMetadata = &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: testKubeconfigSecretName},
+			}
+			cd.Status.APIURL = "https://api.test-cluster.example.com:6443"
+		},
+	}, opts...)...)
+}
+
+func testKubeconfigSecret() *corev1.Secret {
+	return testsecret.FullBuilder(testNamespace, testKubeconfigSecretName, scheme.GetScheme()).Build(
+		testsecret.WithDataKeyValue(constants.KubeconfigSecretKey, []byte(testKubeconfig)),
+	)
+}
+
+// registered returns a ClusterDeployment option recording objects registering it.
+func registered(objects ...registeredObject) testcd.Option {
+	annotation, _ := json.Marshal(objects)
+	return func(cd *hivev1.ClusterDeployment) {
+		testcd.Generic(testgeneric.WithFinalizer(hivev1.FinalizerGitOpsRegistration))(cd)
+		testcd.WithAnnotation(constants.GitOpsRegisteredObjectsAnnotation, string(annotation))(cd)
+	}
+}
+
+// ownedConfigMap returns a config map created for the test ClusterDeployment by a registration.
+func ownedConfigMap(namespace, name, registration string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels: map[string]string{
+				constants.CreatedByHiveLabel:              "true",
+				constants.ClusterDeploymentNameLabel:      testName,
+				constants.ClusterDeploymentNamespaceLabel: testNamespace,
+				constants.GitOpsRegistrationNameLabel:     registration,
+			},
+		},
+	}
+}
+
+func configMapRef(namespace, name, registration string) registeredObject {
+	return registeredObject{Registration: registration, APIVersion: "v1", Kind: "ConfigMap", Namespace: namespace, Name: name}
+}
+
+func testRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func TestReconcileGitOpsRegister(t *testing.T) {
+	flux := testRegistration("flux")
+	cases := []struct {
+		name                  string
+		cd                    *hivev1.ClusterDeployment
+		registrations         []hivev1.GitOpsRegistration
+		existing              []runtime.Object
+		expectErr             bool
+		expectRegistered      []registeredObject
+		expectConfigMaps      []types.NamespacedName
+		expectNoConfigMaps    []types.NamespacedName
+		expectKubeconfigValue string
+		validate              func(t *testing.T, c client.Client)
+	}{
+		{
+			name:          "register cluster",
+			cd:            testClusterDeployment(),
+			registrations: []hivev1.GitOpsRegistration{flux},
+			expectRegistered: []registeredObject{
+				configMapRef(testNamespace, testName, "flux"),
+				{Registration: "flux", APIVersion: "v1", Kind: "Secret", Namespace: fluxNamespace, Name: testName + "-kubeconfig"},
+			},
+			expectKubeconfigValue: testKubeconfig,
+			validate: func(t *testing.T, c client.Client) {
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cm), "expected config map to be created")
+				assert.Equal(t, map[string]string{"kubeconfigSecret": testKubeconfigSecretName, "region": "us-east-1"}, cm.Data, "unexpected rendered data")
+				assert.Equal(t, "flux", cm.Labels[constants.GitOpsRegistrationNameLabel], "unexpected registration label")
+				assert.Equal(t, testName, cm.Labels[constants.ClusterDeploymentNameLabel], "unexpected cluster deployment label")
+				assert.NotEmpty(t, cm.Annotations[constants.GitOpsRegistrationHashAnnotation], "expected hash annotation")
+			},
+		},
+		{
+			name:          "update registered objects",
+			cd:            testClusterDeployment(registered(configMapRef(testNamespace, testName, "flux"))),
+			registrations: []hivev1.GitOpsRegistration{flux},
+			existing: []runtime.Object{
+				func() runtime.Object {
+					cm := ownedConfigMap(testNamespace, testName, "flux")
+					cm.Data = map[string]string{"region": "eu-west-1"}
+					return cm
+				}(),
+			},
+			expectRegistered: []registeredObject{
+				configMapRef(testNamespace, testName, "flux"),
+				{Registration: "flux", APIVersion: "v1", Kind: "Secret", Namespace: fluxNamespace, Name: testName + "-kubeconfig"},
+			},
+			validate: func(t *testing.T, c client.Client) {
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cm), "expected config map to exist")
+				assert.Equal(t, "us-east-1", cm.Data["region"], "expected config map to be updated")
+			},
+		},
+		{
+			name: "cluster not selected",
+			cd:   testClusterDeployment(),
+			registrations: []hivev1.GitOpsRegistration{func() hivev1.GitOpsRegistration {
+				r := testRegistration("flux")
+				r.ClusterDeploymentSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"region": "eu-west-1"}}
+				return r
+			}()},
+			expectNoConfigMaps: []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+		},
+		{
+			name:          "cluster not installed",
+			cd:            testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+			registrations: []hivev1.GitOpsRegistration{flux},
+		},
+		{
+			name: "registration disabled for cluster",
+			cd: testClusterDeployment(
+				testcd.WithLabel(constants.GitOpsRegistrationLabel, "false"),
+				registered(configMapRef(testNamespace, testName, "flux")),
+			),
+			registrations:      []hivev1.GitOpsRegistration{flux},
+			existing:           []runtime.Object{ownedConfigMap(testNamespace, testName, "flux")},
+			expectNoConfigMaps: []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+		},
+		{
+			name:          "registration removed",
+			cd:            testClusterDeployment(registered(configMapRef(fluxNamespace, "removed", "removed"))),
+			registrations: []hivev1.GitOpsRegistration{flux},
+			existing:      []runtime.Object{ownedConfigMap(fluxNamespace, "removed", "removed")},
+			expectRegistered: []registeredObject{
+				configMapRef(testNamespace, testName, "flux"),
+				{Registration: "flux", APIVersion: "v1", Kind: "Secret", Namespace: fluxNamespace, Name: testName + "-kubeconfig"},
+			},
+			expectConfigMaps:   []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+			expectNoConfigMaps: []types.NamespacedName{{Namespace: fluxNamespace, Name: "removed"}},
+		},
+		{
+			name: "cluster deleted",
+			cd: testClusterDeployment(
+				registered(configMapRef(testNamespace, testName, "flux")),
+				testcd.Generic(testgeneric.Deleted()),
+			),
+			registrations:      []hivev1.GitOpsRegistration{flux},
+			existing:           []runtime.Object{ownedConfigMap(testNamespace, testName, "flux")},
+			expectNoConfigMaps: []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+		},
+		{
+			name: "template error keeps registered objects",
+			cd:   testClusterDeployment(registered(configMapRef(testNamespace, testName, "flux"))),
+			registrations: []hivev1.GitOpsRegistration{{
+				Name:     "flux",
+				Template: "{{ .Missing }}",
+			}},
+			existing:         []runtime.Object{ownedConfigMap(testNamespace, testName, "flux")},
+			expectErr:        true,
+			expectRegistered: []registeredObject{configMapRef(testNamespace, testName, "flux")},
+			expectConfigMaps: []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+		},
+		{
+			name: "invalid selector keeps registered objects",
+			cd:   testClusterDeployment(registered(configMapRef(testNamespace, testName, "flux"))),
+			registrations: []hivev1.GitOpsRegistration{func() hivev1.GitOpsRegistration {
+				r := testRegistration("flux")
+				r.ClusterDeploymentSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key:      "region",
+					Operator: "Near",
+				}}}
+				return r
+			}()},
+			existing:         []runtime.Object{ownedConfigMap(testNamespace, testName, "flux")},
+			expectErr:        true,
+			expectRegistered: []registeredObject{configMapRef(testNamespace, testName, "flux")},
+			expectConfigMaps: []types.NamespacedName{{Namespace: testNamespace, Name: testName}},
+		},
+		{
+			name:          "existing object not owned",
+			cd:            testClusterDeployment(),
+			registrations: []hivev1.GitOpsRegistration{flux},
+			existing: []runtime.Object{&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testName},
+				Data:       map[string]string{"owner": "someone-else"},
+			}},
+			expectErr: true,
+			expectRegistered: []registeredObject{
+				{Registration: "flux", APIVersion: "v1", Kind: "Secret", Namespace: fluxNamespace, Name: testName + "-kubeconfig"},
+			},
+			validate: func(t *testing.T, c client.Client) {
+				cm := &corev1.ConfigMap{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cm), "expected config map to exist")
+				assert.Equal(t, map[string]string{"owner": "someone-else"}, cm.Data, "expected config map not to be overwritten")
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := append([]runtime.Object{tc.cd, testKubeconfigSecret()}, tc.existing...)
+			c := testfake.NewFakeClientBuilder().WithRESTMapper(testRESTMapper()).WithRuntimeObjects(existing...).Build()
+			r := &ReconcileGitOpsRegister{
+				Client:        c,
+				logger:        log.WithField("controller", ControllerName),
+				registrations: tc.registrations,
+			}
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: testName}})
+			if tc.expectErr {
+				assert.Error(t, err, "expected error from reconcile")
+			} else {
+				assert.NoError(t, err, "unexpected error from reconcile")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			if err := c.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testName}, cd); err == nil {
+				objects, err := registeredObjects(cd)
+				require.NoError(t, err, "unexpected error parsing registered objects")
+				assert.ElementsMatch(t, tc.expectRegistered, objects, "unexpected registered objects")
+				if len(tc.expectRegistered) > 0 {
+					assert.Contains(t, cd.Finalizers, hivev1.FinalizerGitOpsRegistration, "expected finalizer")
+				} else {
+					assert.NotContains(t, cd.Finalizers, hivev1.FinalizerGitOpsRegistration, "unexpected finalizer")
+				}
+			} else {
+				require.True(t, tc.cd.DeletionTimestamp != nil, "unexpected error getting cluster deployment: %v", err)
+			}
+
+			for _, key := range tc.expectConfigMaps {
+				assert.NoError(t, c.Get(context.Background(), key, &corev1.ConfigMap{}), "expected config map %s", key)
+			}
+			for _, key := range tc.expectNoConfigMaps {
+				err := c.Get(context.Background(), key, &corev1.ConfigMap{})
+				assert.True(t, apierrors.IsNotFound(err), "expected config map %s to be deleted", key)
+			}
+			if tc.expectKubeconfigValue != "" {
+				secret := &corev1.Secret{}
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: fluxNamespace, Name: testName + "-kubeconfig"}, secret), "expected kubeconfig secret")
+				value := string(secret.Data["value"])
+				if value == "" {
+					value = secret.StringData["value"]
+				}
+				assert.Equal(t, tc.expectKubeconfigValue, value, "unexpected kubeconfig")
+			}
+			if tc.validate != nil {
+				tc.validate(t, c)
+			}
+		})
+	}
+}
+
+func TestReconcileGitOpsRegisterRecordsObjectsBeforeCreating(t *testing.T) {
+	cdKey := types.NamespacedName{Namespace: testNamespace, Name: testName}
+	c := testfake.NewFakeClientBuilder().
+		WithRESTMapper(testRESTMapper()).
+		WithRuntimeObjects(testClusterDeployment(), testKubeconfigSecret()).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				cd := &hivev1.ClusterDeployment{}
+				require.NoError(t, c.Get(ctx, cdKey, cd), "unexpected error getting cluster deployment")
+				assert.Contains(t, cd.Finalizers, hivev1.FinalizerGitOpsRegistration, "expected finalizer before creating %s", obj.GetName())
+				objects, err := registeredObjects(cd)
+				require.NoError(t, err, "unexpected error parsing registered objects")
+				assert.Len(t, objects, 2, "expected objects to be recorded before creating %s", obj.GetName())
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, obj.GetName(), nil)
+			},
+		}).Build()
+	r := &ReconcileGitOpsRegister{
+		Client:        c,
+		logger:        log.WithField("controller", ControllerName),
+		registrations: []hivev1.GitOpsRegistration{testRegistration("flux")},
+	}
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: cdKey})
+	require.Error(t, err, "expected error from reconcile")
+	assert.Contains(t, err.Error(), "needs get, create, update and delete permissions", "expected error to point out the missing RBAC")
+
+	cd := &hivev1.ClusterDeployment{}
+	require.NoError(t, c.Get(context.Background(), cdKey, cd), "unexpected error getting cluster deployment")
+	objects, err := registeredObjects(cd)
+	require.NoError(t, err, "unexpected error parsing registered objects")
+	assert.Empty(t, objects, "expected objects which failed to be created not to be recorded")
+	assert.NotContains(t, cd.Finalizers, hivev1.FinalizerGitOpsRegistration, "unexpected finalizer")
+}
@@ -0,0 +1,73 @@
+package gitopsregister
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// templateData is the data the template of a GitOps registration is executed with.
+type templateData struct {
+	ClusterDeployment    *hivev1.ClusterDeployment
+	KubeconfigSecretName string
+	Kubeconfig           string
+}
+
+var templateFuncs = template.FuncMap{
+	// indent indents every line of a string, for instance to embed a kubeconfig in a YAML block scalar.
+	"indent": func(spaces int, s string) string {
+		pad := strings.Repeat(" ", spaces)
+		return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+	},
+	"base64": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	// toJSON encodes a value as JSON, which YAML parses as a flow scalar, list or map.
+	"toJSON": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// render executes the template of a GitOps registration, and decodes the objects it renders.
+func render(registration *hivev1.GitOpsRegistration, data *templateData) ([]*unstructured.Unstructured, error) {
+	tmpl, err := template.New(registration.Name).Funcs(templateFuncs).Option("missingkey=error").Parse(registration.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse template")
+	}
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return nil, errors.Wrap(err, "failed to execute template")
+	}
+
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(buf, 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to decode rendered objects")
+		}
+		// Skip empty documents
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, errors.Errorf("rendered object %d is missing its apiVersion, kind or name", len(objects)+1)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
@@ -144,6 +144,11 @@ func (a *azureActuator) MachinesStopped(cd *hivev1.ClusterDeployment, c client.C
 	return len(machines) == 0, azureMachineNames(machines), nil
 }
 
+// ReplaceMachines implements HibernationPreemptibleMachines interface.
+func (a *azureActuator) ReplaceMachines(cd *hivev1.ClusterDeployment, remoteClient client.Client, logger log.FieldLogger) (bool, error) {
+	return replacePreemptibleMachines(cd, remoteClient, logger)
+}
+
 func listAzureMachines(cd *hivev1.ClusterDeployment, azureClient azureclient.Client, states sets.String, logger log.FieldLogger) ([]compute.VirtualMachine, error) {
 	page, err := azureClient.ListAllVirtualMachines(context.TODO(), "true")
 	if err != nil {
@@ -244,6 +244,18 @@ func (r *hibernationReconciler) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, r.updateClusterDeploymentStatus(cd, cdLog)
 	}
 
+	// Apply the power schedule, if any. Like HibernateAfter, it only affects pool clusters once they're claimed.
+	nextTransition, err := r.reconcilePowerSchedule(cd, cdLog)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if !nextTransition.IsZero() {
+		defer func() {
+			// Requeue for the next scheduled power state transition
+			result, returnErr = controllerutils.EnsureRequeueAtLeastWithin(time.Until(nextTransition), result, returnErr)
+		}()
+	}
+
 	shouldHibernate := cd.Spec.PowerState == hivev1.ClusterPowerStateHibernating
 	// set readyToHibernate if hibernate after is ready to kick in hibernation
 	var readyToHibernate bool
@@ -256,13 +268,7 @@ func (r *hibernationReconciler) Reconcile(ctx context.Context, request reconcile
 		// - The last time the cluster resumed (status.conditions[Hibernating].lastTransitionTime if not hibernating (but see TODO))
 		// BUT pool clusters wait until they're claimed for HibernateAfter to have effect.
 		poolRef := cd.Spec.ClusterPoolRef
-		isUnclaimedPoolCluster := poolRef != nil && poolRef.PoolName != "" &&
-			// Upgrade note: If we hit this code path on a CD that was claimed before upgrading to
-			// where we introduced ClaimedTimestamp, then that CD was Hibernating when it was claimed
-			// (because that's the same time we introduced ClusterPool.RunningCount) so it's safe to
-			// just use installed/last-resumed as the baseline for hibernateAfter.
-			(poolRef.ClaimName == "" || poolRef.ClaimedTimestamp == nil)
-		if !isUnclaimedPoolCluster {
+		if !isUnclaimedPoolCluster(cd) {
 			hibernateAfterDur := cd.Spec.HibernateAfter.Duration
 			hibLog := cdLog.WithField("hibernateAfter", hibernateAfterDur)
 
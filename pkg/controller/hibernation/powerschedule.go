@@ -0,0 +1,99 @@
+package hibernation
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// reconcilePowerSchedule sets the power state of the ClusterDeployment according to its power schedule. The power
+// state is only set when a scheduled transition has passed since the last one applied, so that manual changes to
+// spec.powerState hold until the next scheduled transition. The next transition is recorded in status and returned.
+func (r *hibernationReconciler) reconcilePowerSchedule(cd *hivev1.ClusterDeployment, logger log.FieldLogger) (time.Time, error) {
+	if cd.Spec.PowerSchedule == nil || isUnclaimedPoolCluster(cd) {
+		if cd.Status.PowerSchedule == nil {
+			return time.Time{}, nil
+		}
+		cd.Status.PowerSchedule = nil
+		return time.Time{}, r.updateClusterDeploymentStatus(cd, logger)
+	}
+
+	schedule := cd.Spec.PowerSchedule
+	now := time.Now()
+	var running, nextRunning bool
+	var last, next time.Time
+	var err error
+	if schedule.Resume != "" || schedule.Hibernate != "" {
+		running, last, next, nextRunning, err = controllerutils.EvaluateCronSchedule(schedule.Resume, schedule.Hibernate, schedule.TimeZone, now)
+	} else {
+		running, last, next, err = controllerutils.EvaluateSchedule(schedule.RunningWindows, schedule.TimeZone, now)
+		nextRunning = !running
+	}
+	if err != nil {
+		// Invalid schedules are rejected by the webhook.
+		logger.WithError(err).Warn("ignoring invalid power schedule")
+		return time.Time{}, nil
+	}
+	scheduledState, nextState := hivev1.ClusterPowerStateHibernating, hivev1.ClusterPowerStateHibernating
+	if running {
+		scheduledState = hivev1.ClusterPowerStateRunning
+	}
+	if nextRunning {
+		nextState = hivev1.ClusterPowerStateRunning
+	}
+	schedLog := logger.WithField("scheduledPowerState", scheduledState)
+
+	status := &hivev1.PowerScheduleStatus{}
+	if cd.Status.PowerSchedule != nil {
+		status.LastTransition = cd.Status.PowerSchedule.LastTransition
+	}
+	// Apply the scheduled power state when the schedule is new, or when a transition has passed since the last one
+	// applied.
+	if status.LastTransition == nil || (!last.IsZero() && last.After(status.LastTransition.Time)) {
+		isHibernating := cd.Spec.PowerState == hivev1.ClusterPowerStateHibernating
+		if isHibernating != (scheduledState == hivev1.ClusterPowerStateHibernating) {
+			schedLog.Info("setting power state according to power schedule")
+			cd.Spec.PowerState = scheduledState
+			// Updating the ClusterDeployment replaces its in-memory status, so the status is computed afterwards.
+			if err := r.Update(context.TODO(), cd); err != nil {
+				schedLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update power state")
+				return time.Time{}, err
+			}
+		}
+		if last.IsZero() {
+			last = now
+		}
+		status.LastTransition = &metav1.Time{Time: last}
+	}
+	if !next.IsZero() {
+		status.NextTransition = &metav1.Time{Time: next}
+		status.NextPowerState = nextState
+	}
+
+	if old := cd.Status.PowerSchedule; old == nil || !old.LastTransition.Equal(status.LastTransition) ||
+		!old.NextTransition.Equal(status.NextTransition) || old.NextPowerState != status.NextPowerState {
+		schedLog.WithField("nextTransition", next).Debug("updating power schedule status")
+		cd.Status.PowerSchedule = status
+		if err := r.updateClusterDeploymentStatus(cd, logger); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return next, nil
+}
+
+// isUnclaimedPoolCluster returns whether the ClusterDeployment belongs to a ClusterPool and has not been claimed.
+func isUnclaimedPoolCluster(cd *hivev1.ClusterDeployment) bool {
+	poolRef := cd.Spec.ClusterPoolRef
+	return poolRef != nil && poolRef.PoolName != "" &&
+		// Upgrade note: If we hit this code path on a CD that was claimed before upgrading to
+		// where we introduced ClaimedTimestamp, then that CD was Hibernating when it was claimed
+		// (because that's the same time we introduced ClusterPool.RunningCount) so it's safe to
+		// just use installed/last-resumed as the baseline for hibernateAfter.
+		(poolRef.ClaimName == "" || poolRef.ClaimedTimestamp == nil)
+}
@@ -0,0 +1,178 @@
+package hibernation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+func TestReconcilePowerSchedule(t *testing.T) {
+	now := time.Now()
+	// runningAround returns a schedule running every day from the given time before now to the given time after now.
+	runningAround := func(before, after time.Duration) *hivev1.PowerSchedule {
+		return &hivev1.PowerSchedule{
+			RunningWindows: []hivev1.ScheduleWindow{{
+				Start: now.Add(-before).UTC().Format("15:04"),
+				End:   now.Add(after).UTC().Format("15:04"),
+			}},
+		}
+	}
+	// cronAround returns a schedule resuming every day at the given time before now and hibernating at the given time
+	// after now.
+	cronAround := func(before, after time.Duration) *hivev1.PowerSchedule {
+		daily := func(t time.Time) string {
+			return fmt.Sprintf("%d %d * * *", t.UTC().Minute(), t.UTC().Hour())
+		}
+		return &hivev1.PowerSchedule{
+			Resume:    daily(now.Add(-before)),
+			Hibernate: daily(now.Add(after)),
+		}
+	}
+	lastTransition := func(ago time.Duration) testcd.Option {
+		return func(cd *hivev1.ClusterDeployment) {
+			cd.Status.PowerSchedule = &hivev1.PowerScheduleStatus{LastTransition: &metav1.Time{Time: now.Add(-ago)}}
+		}
+	}
+
+	cdBuilder := testcd.FullBuilder(namespace, cdName, scheme.GetScheme()).Options(testcd.Installed())
+
+	tests := []struct {
+		name                   string
+		cd                     *hivev1.ClusterDeployment
+		expectedPowerState     hivev1.ClusterPowerState
+		expectedNextTransition time.Duration
+		expectedNextPowerState hivev1.ClusterPowerState
+		expectNoStatus         bool
+	}{
+		{
+			name:               "no schedule",
+			cd:                 cdBuilder.Build(testcd.WithPowerState(hivev1.ClusterPowerStateHibernating)),
+			expectedPowerState: hivev1.ClusterPowerStateHibernating,
+			expectNoStatus:     true,
+		},
+		{
+			name:           "schedule removed",
+			cd:             cdBuilder.Build(lastTransition(time.Hour)),
+			expectNoStatus: true,
+		},
+		{
+			name: "new schedule in running window",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateHibernating)),
+			expectedPowerState:     hivev1.ClusterPowerStateRunning,
+			expectedNextTransition: 2 * time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateHibernating,
+		},
+		{
+			name: "new schedule outside running window",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(-time.Hour, 2*time.Hour))),
+			expectedPowerState:     hivev1.ClusterPowerStateHibernating,
+			expectedNextTransition: time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateRunning,
+		},
+		{
+			name: "running window started",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+				lastTransition(10*time.Hour)),
+			expectedPowerState:     hivev1.ClusterPowerStateRunning,
+			expectedNextTransition: 2 * time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateHibernating,
+		},
+		{
+			name: "manual hibernation kept until next transition",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+				lastTransition(time.Hour-5*time.Minute)),
+			expectedPowerState:     hivev1.ClusterPowerStateHibernating,
+			expectedNextTransition: 2 * time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateHibernating,
+		},
+		{
+			name: "manual resume kept until next transition",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(-time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateRunning),
+				lastTransition(time.Minute)),
+			expectedPowerState:     hivev1.ClusterPowerStateRunning,
+			expectedNextTransition: time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateRunning,
+		},
+		{
+			name: "new cron schedule after resume",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(cronAround(time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateHibernating)),
+			expectedPowerState:     hivev1.ClusterPowerStateRunning,
+			expectedNextTransition: 2 * time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateHibernating,
+		},
+		{
+			name: "cron hibernation passed",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(cronAround(-time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateRunning),
+				lastTransition(30*time.Hour)),
+			expectedPowerState:     hivev1.ClusterPowerStateHibernating,
+			expectedNextTransition: time.Hour,
+			expectedNextPowerState: hivev1.ClusterPowerStateRunning,
+		},
+		{
+			name: "unclaimed pool cluster",
+			cd: cdBuilder.Build(
+				testcd.WithPowerSchedule(runningAround(time.Hour, 2*time.Hour)),
+				testcd.WithPowerState(hivev1.ClusterPowerStateHibernating),
+				testcd.WithClusterPoolReference(namespace, "pool", "")),
+			expectedPowerState: hivev1.ClusterPowerStateHibernating,
+			expectNoStatus:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := testfake.NewFakeClientBuilder().WithRuntimeObjects(test.cd).Build()
+			reconciler := hibernationReconciler{
+				Client: c,
+				logger: log.WithField("controller", "hibernation"),
+			}
+
+			next, err := reconciler.reconcilePowerSchedule(test.cd, reconciler.logger)
+			require.NoError(t, err, "unexpected error")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: cdName}, cd))
+			assert.Equal(t, test.expectedPowerState, cd.Spec.PowerState, "unexpected PowerState")
+			if test.expectNoStatus {
+				assert.Nil(t, cd.Status.PowerSchedule, "unexpected power schedule status")
+				assert.True(t, next.IsZero(), "unexpected next transition")
+				return
+			}
+			if assert.NotNil(t, cd.Status.PowerSchedule, "expected power schedule status") {
+				status := cd.Status.PowerSchedule
+				assert.NotNil(t, status.LastTransition, "expected last transition")
+				if assert.NotNil(t, status.NextTransition, "expected next transition") {
+					// Windows are scheduled to the minute.
+					assert.InDelta(t, test.expectedNextTransition, time.Until(status.NextTransition.Time), float64(time.Minute), "unexpected next transition")
+				}
+				assert.Equal(t, test.expectedNextPowerState, status.NextPowerState, "unexpected next power state")
+			}
+			assert.InDelta(t, test.expectedNextTransition, time.Until(next), float64(time.Minute), "unexpected returned next transition")
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	installazure "github.com/openshift/installer/pkg/asset/machines/azure"
 	installertypes "github.com/openshift/installer/pkg/types"
@@ -167,7 +168,26 @@ func (a *AzureActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *
 		useImageGallery,
 		// TODO: support adding userTags? https://issues.redhat.com/browse/HIVE-2143
 	)
-	return installerMachineSets, err == nil, errors.Wrap(err, "failed to generate machinesets")
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to generate machinesets")
+	}
+
+	if spotVMOptions := pool.Spec.Platform.Azure.SpotVMOptions; spotVMOptions != nil {
+		options := &machineapi.SpotVMOptions{}
+		if spotVMOptions.MaxPrice != nil {
+			maxPrice, err := resource.ParseQuantity(*spotVMOptions.MaxPrice)
+			if err != nil {
+				return nil, false, errors.Wrap(err, "invalid spot VM max price")
+			}
+			options.MaxPrice = &maxPrice
+		}
+		for _, ms := range installerMachineSets {
+			providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machineapi.AzureMachineProviderSpec)
+			providerSpec.SpotVMOptions = options.DeepCopy()
+		}
+	}
+
+	return installerMachineSets, true, nil
 }
 
 func (a *AzureActuator) getZones(region string, instanceType string) ([]string, error) {
@@ -130,6 +130,48 @@ func TestAzureActuator(t *testing.T) {
 				assert.True(t, providerSpec.AcceleratedNetworking, "expected accelerated networking")
 			},
 		},
+		{
+			name:              "spot VMs",
+			clusterDeployment: testAzureClusterDeployment(),
+			pool: func() *hivev1.MachinePool {
+				pool := testAzurePool()
+				pool.Spec.Platform.Azure.Zones = []string{"zone1"}
+				pool.Spec.Platform.Azure.SpotVMOptions = &hivev1azure.SpotVMOptions{MaxPrice: pointer.String("0.05")}
+				return pool
+			}(),
+			mockAzureClient: func(mockCtrl *gomock.Controller, client *mockazure.MockClient) {
+				mockGetVMCapabilities(client, "V1,V2")
+				mockListImagesByResourceGroup(client, []compute.Image{testAzureImage(compute.HyperVGenerationTypesV1)})
+			},
+			expectedMachineSetReplicas: map[string]int64{
+				generateAzureMachineSetName("zone1"): 3,
+			},
+			extraProviderSpecValidation: func(t *testing.T, providerSpec *machineapi.AzureMachineProviderSpec) {
+				if assert.NotNil(t, providerSpec.SpotVMOptions, "expected spot VM options") &&
+					assert.NotNil(t, providerSpec.SpotVMOptions.MaxPrice, "expected spot VM max price") {
+					assert.Equal(t, "50m", providerSpec.SpotVMOptions.MaxPrice.String(), "unexpected spot VM max price")
+				}
+			},
+		},
+		{
+			name:              "on-demand VMs",
+			clusterDeployment: testAzureClusterDeployment(),
+			pool: func() *hivev1.MachinePool {
+				pool := testAzurePool()
+				pool.Spec.Platform.Azure.Zones = []string{"zone1"}
+				return pool
+			}(),
+			mockAzureClient: func(mockCtrl *gomock.Controller, client *mockazure.MockClient) {
+				mockGetVMCapabilities(client, "V1,V2")
+				mockListImagesByResourceGroup(client, []compute.Image{testAzureImage(compute.HyperVGenerationTypesV1)})
+			},
+			expectedMachineSetReplicas: map[string]int64{
+				generateAzureMachineSetName("zone1"): 3,
+			},
+			extraProviderSpecValidation: func(t *testing.T, providerSpec *machineapi.AzureMachineProviderSpec) {
+				assert.Nil(t, providerSpec.SpotVMOptions, "unexpected spot VM options")
+			},
+		},
 		{
 			name:              "more replicas than zones",
 			clusterDeployment: testAzureClusterDeployment(),
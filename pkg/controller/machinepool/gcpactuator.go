@@ -24,6 +24,7 @@ import (
 	gcpprovider "github.com/openshift/machine-api-provider-gcp/pkg/apis/gcpprovider/v1beta1"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1gcp "github.com/openshift/hive/apis/hive/v1/gcp"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/gcpclient"
@@ -244,7 +245,20 @@ func (a *GCPActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hi
 		workerRole,
 		workerUserDataName,
 	)
-	return installerMachineSets, err == nil, errors.Wrap(err, "failed to generate machinesets")
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to generate machinesets")
+	}
+
+	if poolGCP.ProvisioningModel == hivev1gcp.ProvisioningModelPreemptible {
+		for _, ms := range installerMachineSets {
+			providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machineapi.GCPMachineProviderSpec)
+			providerSpec.Preemptible = true
+			// Preemptible instances cannot be live migrated.
+			providerSpec.OnHostMaintenance = machineapi.TerminateHostMaintenanceType
+		}
+	}
+
+	return installerMachineSets, true, nil
 }
 
 func (a *GCPActuator) getZones(region string) ([]string, error) {
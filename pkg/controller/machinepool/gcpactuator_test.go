@@ -240,6 +240,20 @@ func TestGCPActuator(t *testing.T) {
 				generateGCPMachineSetName("worker", "zone1"): 3,
 			},
 		},
+		{
+			name: "generate machinesets with preemptible instances",
+			pool: func() *hivev1.MachinePool {
+				pool := testGCPPool(testPoolName)
+				pool.Spec.Platform.GCP.ProvisioningModel = hivev1gcp.ProvisioningModelPreemptible
+				return pool
+			}(),
+			mockGCPClient: func(client *mockgcp.MockClient) {
+				mockListComputeZones(client, []string{"zone1"}, testRegion)
+			},
+			expectedMachineSetReplicas: map[string]int64{
+				generateGCPMachineSetName("worker", "zone1"): 3,
+			},
+		},
 		{
 			name: "generate machinesets with custom ServiceAccount",
 			pool: func() *hivev1.MachinePool {
@@ -382,6 +396,13 @@ func TestGCPActuator(t *testing.T) {
 						assert.Equal(t, ohm, string(gcpProvider.OnHostMaintenance))
 					}
 
+					// ProvisioningModel
+					preemptible := platform.ProvisioningModel == hivev1gcp.ProvisioningModelPreemptible
+					assert.Equal(t, preemptible, gcpProvider.Preemptible, "unexpected preemptible")
+					if preemptible {
+						assert.Equal(t, machineapi.TerminateHostMaintenanceType, gcpProvider.OnHostMaintenance, "preemptible instances must terminate on host maintenance")
+					}
+
 					// ServiceAccount
 					if assert.Equal(t, 1, len(gcpProvider.ServiceAccounts), "expected exactly one service account") {
 						if sa := platform.ServiceAccount; sa != "" {
@@ -884,7 +905,7 @@ func mockMachineSet(name string, machineType string, unstompedAnnotation bool, r
 			Name:      name,
 			Namespace: machineAPINamespace,
 			Labels: map[string]string{
-				machinePoolNameLabel:                       machineType,
+				constants.MachineSetMachinePoolLabel:       machineType,
 				"machine.openshift.io/cluster-api-cluster": testInfraID,
 				constants.HiveManagedLabel:                 "true",
 			},
@@ -54,7 +54,6 @@ import (
 
 const (
 	ControllerName             = hivev1.MachinePoolControllerName
-	machinePoolNameLabel       = "hive.openshift.io/machine-pool"
 	finalizer                  = "hive.openshift.io/remotemachineset"
 	masterMachineLabelSelector = "machine.openshift.io/cluster-api-machine-type=master"
 	defaultPollInterval        = 30 * time.Minute
@@ -357,6 +356,12 @@ func (r *ReconcileMachinePool) Reconcile(ctx context.Context, request reconcile.
 		return reconcile.Result{}, err
 	}
 
+	var activeSchedule string
+	var nextScheduleTransition time.Time
+	if pool.DeletionTimestamp == nil {
+		activeSchedule, nextScheduleTransition = applyScalingSchedules(pool, time.Now(), logger)
+	}
+
 	generatedMachineSets, proceed, err := r.generateMachineSets(pool, cd, masterMachine, remoteMachineSets, logger)
 	if err != nil {
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not generateMachineSets")
@@ -374,6 +379,13 @@ func (r *ReconcileMachinePool) Reconcile(ctx context.Context, request reconcile.
 		return *result, nil
 	}
 
+	if pool.Spec.SpotAllocation != nil {
+		if err := allocateSpotReplicas(pool, generatedMachineSets, remoteMachineSets, infrastructure, remoteClusterAPIClient, logger); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "could not allocateSpotReplicas")
+			return reconcile.Result{}, err
+		}
+	}
+
 	machineSets, err := r.syncMachineSets(pool, cd, generatedMachineSets, remoteMachineSets, infrastructure, remoteClusterAPIClient, logger)
 	if err != nil {
 		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not syncMachineSets")
@@ -392,7 +404,22 @@ func (r *ReconcileMachinePool) Reconcile(ctx context.Context, request reconcile.
 		return r.removeFinalizer(pool, logger)
 	}
 
-	return r.updatePoolStatusForMachineSets(pool, machineSets, remoteClusterAPIClient, logger)
+	var rollout *hivev1.MachinePoolRolloutStatus
+	if pool.Spec.RolloutStrategy != nil {
+		if rollout, err = r.rolloutMachineSets(pool, machineSets, remoteClusterAPIClient, logger); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "could not rolloutMachineSets")
+			return reconcile.Result{}, err
+		}
+	}
+
+	result, err := r.updatePoolStatusForMachineSets(pool, machineSets, rollout, activeSchedule, remoteClusterAPIClient, logger)
+	if !nextScheduleTransition.IsZero() {
+		// Reconcile when a scaling schedule window opens or closes, rather than waiting for the periodic source.
+		if untilTransition := time.Until(nextScheduleTransition); result.RequeueAfter == 0 || untilTransition < result.RequeueAfter {
+			result.RequeueAfter = untilTransition
+		}
+	}
+	return result, err
 }
 
 func (r *ReconcileMachinePool) getInfrastructure(remoteClusterAPIClient client.Client, logger log.FieldLogger) (*configv1.Infrastructure, error) {
@@ -485,6 +512,12 @@ func (r *ReconcileMachinePool) generateMachineSets(
 		return nil, false, nil
 	}
 
+	if pool.Spec.SpotAllocation != nil {
+		if generatedMachineSets, err = pairOnDemandMachineSets(generatedMachineSets); err != nil {
+			return nil, false, errors.Wrap(err, "could not generate on-demand machinesets")
+		}
+	}
+
 	for i, ms := range generatedMachineSets {
 		if pool.Spec.Autoscaling != nil {
 			min, _ := getMinMaxReplicasForMachineSet(pool, generatedMachineSets, i)
@@ -494,7 +527,7 @@ func (r *ReconcileMachinePool) generateMachineSets(
 		if ms.Labels == nil {
 			ms.Labels = make(map[string]string, 2)
 		}
-		ms.Labels[machinePoolNameLabel] = pool.Spec.Name
+		ms.Labels[constants.MachineSetMachinePoolLabel] = pool.Spec.Name
 		// Add the managed-by-Hive label:
 		ms.Labels[constants.HiveManagedLabel] = "true"
 
@@ -655,18 +688,20 @@ func matchFailureDomains(gMS *machineapi.MachineSet, rMS machineapi.MachineSet,
 
 // matchMachineSets decides whether gMS ("generated MachineSet") and rMS ("remote MachineSet" -- the one already extant
 // on the spoke cluster) are talking about the same machines. In certain cases, this may be true when
-// the names don't match. The function therefore relies on the hive machinePoolNameLabel to determine whether the MachineSets
-// are part of the same MachinePool. If the machinePoolNameLabels match, the function then confirms that the MachineSets belong
+// the names don't match. The function therefore relies on the hive MachineSetMachinePoolLabel to determine whether the MachineSets
+// are part of the same MachinePool. If the MachineSetMachinePoolLabels match, the function then confirms that the MachineSets belong
 // to the same Availability Zone (aka Failure Domain). This ensures that the MachineSets are referring to the same machines.
 // We can count on this because the upsteam generator guarentees at most one MachineSet per Failure Domain. HIVE-2254.
+// Pools with spot allocation have an on-demand and a spot MachineSet per Failure Domain, told apart by their capacity
+// type label.
 func matchMachineSets(gMS *machineapi.MachineSet, rMS machineapi.MachineSet, infrastructure *configv1.Infrastructure, logger log.FieldLogger) (bool, error) {
 
-	gLabel, gLabelExists := gMS.Labels[machinePoolNameLabel]
-	rLabel, rLabelExists := rMS.Labels[machinePoolNameLabel]
+	gLabel, gLabelExists := gMS.Labels[constants.MachineSetMachinePoolLabel]
+	rLabel, rLabelExists := rMS.Labels[constants.MachineSetMachinePoolLabel]
 
 	if !gLabelExists {
 		// panic because this should never happen
-		panic(fmt.Sprintf("generated MachineSet %v does not have a machinePoolNameLabel", gMS.Name))
+		panic(fmt.Sprintf("generated MachineSet %v does not have a MachineSetMachinePoolLabel", gMS.Name))
 	}
 	if !rLabelExists {
 		return false, nil
@@ -674,6 +709,9 @@ func matchMachineSets(gMS *machineapi.MachineSet, rMS machineapi.MachineSet, inf
 	if gLabel != rLabel {
 		return false, nil
 	}
+	if !capacityTypesMatch(gMS.Labels[machinePoolCapacityTypeLabel], rMS.Labels[machinePoolCapacityTypeLabel]) {
+		return false, nil
+	}
 
 	return matchFailureDomains(gMS, rMS, infrastructure, logger)
 }
@@ -735,13 +773,22 @@ func (r *ReconcileMachinePool) syncMachineSets(
 				resourcemerge.EnsureObjectMeta(&objectMetaModified, &rMS.ObjectMeta, ms.ObjectMeta)
 				msLog := logger.WithField("machineset", rMS.Name)
 
+				// Replicas surged by an ongoing rollout are kept until the rollout removes the surge.
+				var surge int32
+				if pool.Spec.RolloutStrategy != nil {
+					surge = rolloutSurge(&rMS)
+				} else if _, ok := rMS.Annotations[rolloutSurgeAnnotation]; ok {
+					delete(rMS.Annotations, rolloutSurgeAnnotation)
+					objectModified = true
+				}
 				if pool.Spec.Autoscaling == nil {
-					if *rMS.Spec.Replicas != *ms.Spec.Replicas {
+					desired := *ms.Spec.Replicas + surge
+					if *rMS.Spec.Replicas != desired {
 						msLog.WithFields(log.Fields{
-							"desired":  *ms.Spec.Replicas,
+							"desired":  desired,
 							"observed": *rMS.Spec.Replicas,
 						}).Info("replicas out of sync")
-						rMS.Spec.Replicas = ms.Spec.Replicas
+						rMS.Spec.Replicas = &desired
 						objectModified = true
 					}
 				} else {
@@ -750,6 +797,7 @@ func (r *ReconcileMachinePool) syncMachineSets(
 					// To ensure that the replicas falls within min and max regardless, Hive needs
 					// to set the replicas to explicitly be within the desired range.
 					min, max := getMinMaxReplicasForMachineSet(pool, generatedMachineSets, i)
+					max += surge
 					switch {
 					case rMS.Spec.Replicas == nil:
 						msLog.WithField("observed", nil).WithField("min", min).WithField("max", max).Info("setting replicas to min")
@@ -973,7 +1021,7 @@ func (r *ReconcileMachinePool) syncMachineAutoscalers(
 						Namespace: ms.Namespace,
 						Name:      ms.Name,
 						Labels: map[string]string{
-							machinePoolNameLabel: pool.Spec.Name,
+							constants.MachineSetMachinePoolLabel: pool.Spec.Name,
 						},
 					},
 					Spec: autoscalingv1beta1.MachineAutoscalerSpec{
@@ -1094,6 +1142,8 @@ func (r *ReconcileMachinePool) syncClusterAutoscaler(
 func (r *ReconcileMachinePool) updatePoolStatusForMachineSets(
 	pool *hivev1.MachinePool,
 	machineSets []*machineapi.MachineSet,
+	rollout *hivev1.MachinePoolRolloutStatus,
+	activeSchedule string,
 	remoteClusterAPIClient client.Client,
 	logger log.FieldLogger,
 ) (reconcile.Result, error) {
@@ -1118,6 +1168,10 @@ func (r *ReconcileMachinePool) updatePoolStatusForMachineSets(
 			ErrorReason:   (*string)(ms.Status.ErrorReason),
 			ErrorMessage:  ms.Status.ErrorMessage,
 		}
+		if pool.Spec.SpotAllocation != nil {
+			s.CapacityType = hivev1.MachineSetCapacityType(ms.Labels[machinePoolCapacityTypeLabel])
+			s.SpotFallbackReplicas, _ = spotFallback(ms)
+		}
 		if s.Replicas != s.ReadyReplicas && s.ErrorReason == nil {
 			r, m := summarizeMachinesError(remoteClusterAPIClient, ms, logger)
 			s.ErrorReason = &r
@@ -1139,6 +1193,13 @@ func (r *ReconcileMachinePool) updatePoolStatusForMachineSets(
 		}
 	}
 
+	pool.Status.Rollout = rollout
+	pool.Status.ActiveScalingSchedule = activeSchedule
+	if rollout != nil && rollout.OutdatedReplicas > 0 {
+		// Machines of the remote cluster cannot trigger reconcile, so poll while replacing outdated machines.
+		requeueAfter = rolloutPollInterval
+	}
+
 	pool.Status = updateOwnedLabelsAndTaints(pool)
 
 	if (len(origPool.Status.MachineSets) == 0 && len(pool.Status.MachineSets) == 0) ||
@@ -1327,7 +1388,7 @@ func baseMachinePool(pool *hivev1.MachinePool) *installertypes.MachinePool {
 func isControlledByMachinePool(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, obj metav1.Object) bool {
 	prefix := strings.Join([]string{cd.Spec.ClusterName, pool.Spec.Name, ""}, "-")
 	return strings.HasPrefix(obj.GetName(), prefix) ||
-		obj.GetLabels()[machinePoolNameLabel] == pool.Spec.Name
+		obj.GetLabels()[constants.MachineSetMachinePoolLabel] == pool.Spec.Name
 }
 
 func (r *ReconcileMachinePool) removeFinalizer(pool *hivev1.MachinePool, logger log.FieldLogger) (reconcile.Result, error) {
@@ -416,8 +416,8 @@ func TestRemoteMachineSetReconcile(t *testing.T) {
 							Namespace:  machineAPINamespace,
 							Generation: int64(0),
 							Labels: map[string]string{
-								"hive.openshift.io/managed": "true",
-								machinePoolNameLabel:        testPoolName,
+								"hive.openshift.io/managed":          "true",
+								constants.MachineSetMachinePoolLabel: testPoolName,
 							},
 						},
 						Spec: machineapi.MachineSetSpec{
@@ -1721,7 +1721,7 @@ func testMachineSetWithAZ(name string, machineType string, unstompedAnnotation b
 func testMachineSetNotManaged(name string, machineType string, unstompedAnnotation bool, replicas int, generation int, az string) *machineapi.MachineSet {
 
 	ms := testMachineSetWithAZ(name, machineType, unstompedAnnotation, replicas, generation, az)
-	delete(ms.Labels, machinePoolNameLabel) // remove machinePoolNameLabel
+	delete(ms.Labels, constants.MachineSetMachinePoolLabel)
 	return ms
 }
 
@@ -1736,7 +1736,7 @@ func testMachineSet(name string, machineType string, unstompedAnnotation bool, r
 			Name:      name,
 			Namespace: machineAPINamespace,
 			Labels: map[string]string{
-				machinePoolNameLabel:                       machineType,
+				constants.MachineSetMachinePoolLabel:       machineType,
 				"machine.openshift.io/cluster-api-cluster": testInfraID,
 				constants.HiveManagedLabel:                 "true",
 			},
@@ -1781,7 +1781,7 @@ func testMachineAutoscaler(name string, resourceVersion string, min, max int) *a
 			Name:            name,
 			ResourceVersion: resourceVersion,
 			Labels: map[string]string{
-				machinePoolNameLabel: "worker",
+				constants.MachineSetMachinePoolLabel: "worker",
 			},
 		},
 		Spec: autoscalingv1beta1.MachineAutoscalerSpec{
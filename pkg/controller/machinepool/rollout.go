@@ -0,0 +1,238 @@
+package machinepool
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	machineapi "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+const (
+	// rolloutSurgeAnnotation is set on remote MachineSets to the number of replicas added above their desired
+	// replicas while their outdated Machines are replaced.
+	rolloutSurgeAnnotation = "hive.openshift.io/rollout-surge"
+	// excludeNodeDrainingAnnotation makes the Machine API delete a Machine without draining its Node.
+	excludeNodeDrainingAnnotation = "machine.openshift.io/exclude-node-draining"
+
+	rolloutPollInterval = 2 * time.Minute
+)
+
+// rolloutSurge returns the number of replicas added to a remote MachineSet by an ongoing rollout.
+func rolloutSurge(ms *machineapi.MachineSet) int32 {
+	surge, err := strconv.ParseInt(ms.Annotations[rolloutSurgeAnnotation], 10, 32)
+	if err != nil || surge < 0 {
+		return 0
+	}
+	return int32(surge)
+}
+
+// rolloutMachineSets progressively replaces the Machines that no longer match the provider spec of their
+// MachineSet, for machine pools with a rollout strategy. For each MachineSet with outdated Machines, the replicas
+// are surged by maxSurge and outdated Machines are deleted, and so replaced by the MachineSet, as long as at most
+// maxUnavailable Machines are unavailable. Once all Machines are updated, the surge is removed.
+func (r *ReconcileMachinePool) rolloutMachineSets(
+	pool *hivev1.MachinePool,
+	machineSets []*machineapi.MachineSet,
+	remoteClusterAPIClient client.Client,
+	logger log.FieldLogger,
+) (*hivev1.MachinePoolRolloutStatus, error) {
+	strategy := pool.Spec.RolloutStrategy
+	status := &hivev1.MachinePoolRolloutStatus{}
+	for _, generatedMS := range machineSets {
+		// Reload the MachineSet as stored, so its provider spec compares with the ones of its Machines.
+		ms := &machineapi.MachineSet{}
+		if err := remoteClusterAPIClient.Get(
+			context.TODO(),
+			types.NamespacedName{Namespace: generatedMS.Namespace, Name: generatedMS.Name},
+			ms,
+		); err != nil {
+			return nil, errors.Wrapf(err, "could not get machineset %s", generatedMS.Name)
+		}
+		msLog := logger.WithField("machineset", ms.Name)
+
+		sel, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not create label selector for machineset %s", ms.Name)
+		}
+		machines := &machineapi.MachineList{}
+		if err := remoteClusterAPIClient.List(context.TODO(), machines,
+			client.InNamespace(ms.Namespace),
+			client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, errors.Wrapf(err, "could not list machines for machineset %s", ms.Name)
+		}
+
+		var outdated []*machineapi.Machine
+		var available int32
+		for i := range machines.Items {
+			m := &machines.Items[i]
+			if m.DeletionTimestamp != nil {
+				if err := skipDrainAfterTimeout(m, strategy.DrainTimeout, remoteClusterAPIClient, msLog); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			upToDate, err := providerSpecsEqual(m.Spec.ProviderSpec.Value, ms.Spec.Template.Spec.ProviderSpec.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "could not compare provider spec of machine %s", m.Name)
+			}
+			if upToDate {
+				status.UpdatedReplicas++
+			} else {
+				outdated = append(outdated, m)
+			}
+			if machineAvailable(m) {
+				available++
+			}
+		}
+		status.OutdatedReplicas += int32(len(outdated))
+
+		surge := rolloutSurge(ms)
+		desired := int32(0)
+		if ms.Spec.Replicas != nil {
+			desired = *ms.Spec.Replicas - surge
+		}
+		if desired < 0 {
+			desired = 0
+		}
+
+		newSurge := int32(0)
+		var maxUnavailable int32
+		if len(outdated) > 0 {
+			if newSurge, maxUnavailable, err = resolveRolloutBounds(strategy, desired); err != nil {
+				return nil, err
+			}
+		}
+		if newSurge != surge {
+			msLog.WithField("desired", desired).WithField("surge", newSurge).WithField("outdated", len(outdated)).Info("updating rollout surge")
+			if ms.Annotations == nil {
+				ms.Annotations = map[string]string{}
+			}
+			ms.Annotations[rolloutSurgeAnnotation] = strconv.Itoa(int(newSurge))
+			ms.Spec.Replicas = pointer.Int32(desired + newSurge)
+			if err := remoteClusterAPIClient.Update(context.TODO(), ms); err != nil {
+				return nil, errors.Wrapf(err, "could not update replicas of machineset %s", ms.Name)
+			}
+		}
+		if len(outdated) == 0 {
+			continue
+		}
+
+		// Unavailable outdated Machines can always be replaced. Available ones only while enough Machines remain
+		// available.
+		sort.SliceStable(outdated, func(i, j int) bool {
+			return !machineAvailable(outdated[i]) && machineAvailable(outdated[j])
+		})
+		budget := available - (desired - maxUnavailable)
+		for _, m := range outdated {
+			if machineAvailable(m) {
+				if budget <= 0 {
+					break
+				}
+				budget--
+			}
+			msLog.WithField("machine", m.Name).Info("deleting outdated machine")
+			if err := remoteClusterAPIClient.Delete(context.TODO(), m); err != nil {
+				return nil, errors.Wrapf(err, "could not delete outdated machine %s", m.Name)
+			}
+		}
+	}
+	return status, nil
+}
+
+// resolveRolloutBounds returns the surge and the number of unavailable Machines allowed when replacing the outdated
+// Machines of a MachineSet with the given desired replicas.
+func resolveRolloutBounds(strategy *hivev1.MachinePoolRolloutStrategy, desired int32) (surge, maxUnavailable int32, err error) {
+	defaultSurge := intstr.FromInt(1)
+	defaultUnavailable := intstr.FromInt(0)
+	maxSurgeValue, maxUnavailableValue := &defaultSurge, &defaultUnavailable
+	if strategy.MaxSurge != nil {
+		maxSurgeValue = strategy.MaxSurge
+	}
+	if strategy.MaxUnavailable != nil {
+		maxUnavailableValue = strategy.MaxUnavailable
+	}
+	s, err := intstr.GetScaledValueFromIntOrPercent(maxSurgeValue, int(desired), true)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid maxSurge")
+	}
+	u, err := intstr.GetScaledValueFromIntOrPercent(maxUnavailableValue, int(desired), false)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "invalid maxUnavailable")
+	}
+	if s == 0 && u == 0 {
+		// Percentages can both round to 0 for small MachineSets. Surge by one Machine to make progress.
+		s = 1
+	}
+	return int32(s), int32(u), nil
+}
+
+// skipDrainAfterTimeout makes the Machine API stop draining the Node of a Machine being deleted once the drain
+// timeout has passed.
+func skipDrainAfterTimeout(m *machineapi.Machine, drainTimeout *metav1.Duration, remoteClusterAPIClient client.Client, logger log.FieldLogger) error {
+	if drainTimeout == nil || time.Since(m.DeletionTimestamp.Time) < drainTimeout.Duration {
+		return nil
+	}
+	if _, ok := m.Annotations[excludeNodeDrainingAnnotation]; ok {
+		return nil
+	}
+	logger.WithField("machine", m.Name).Info("drain timeout passed, deleting machine without draining its node")
+	if m.Annotations == nil {
+		m.Annotations = map[string]string{}
+	}
+	m.Annotations[excludeNodeDrainingAnnotation] = ""
+	return errors.Wrapf(remoteClusterAPIClient.Update(context.TODO(), m), "could not skip draining of machine %s", m.Name)
+}
+
+// machineAvailable returns whether a Machine is running with a Node.
+func machineAvailable(m *machineapi.Machine) bool {
+	return m.DeletionTimestamp == nil &&
+		m.Status.Phase != nil && *m.Status.Phase == machineapi.PhaseRunning &&
+		m.Status.NodeRef != nil
+}
+
+// providerSpecsEqual compares provider specs semantically, as they may be stored as raw JSON or as objects.
+func providerSpecsEqual(a, b *runtime.RawExtension) (bool, error) {
+	aValue, err := providerSpecValue(a)
+	if err != nil {
+		return false, err
+	}
+	bValue, err := providerSpecValue(b)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aValue, bValue), nil
+}
+
+func providerSpecValue(ext *runtime.RawExtension) (interface{}, error) {
+	if ext == nil {
+		return nil, nil
+	}
+	raw := ext.Raw
+	if raw == nil && ext.Object != nil {
+		var err error
+		if raw, err = json.Marshal(ext.Object); err != nil {
+			return nil, err
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+	var value interface{}
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
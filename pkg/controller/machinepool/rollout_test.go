@@ -0,0 +1,225 @@
+package machinepool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	machineapi "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+func testRolloutMachine(name string, outdated, available bool, mutators ...func(*machineapi.Machine)) *machineapi.Machine {
+	m := testMachineSetMachine(name, testPoolName, testName)
+	if outdated {
+		pc := testAWSProviderSpec()
+		pc.InstanceType = "old-instance-type"
+		replaceMachineProviderSpec(pc)(m)
+	}
+	if available {
+		m.Status.Phase = pointer.String(machineapi.PhaseRunning)
+		m.Status.NodeRef = &corev1.ObjectReference{Name: name}
+	} else {
+		m.Status.Phase = pointer.String(machineapi.PhaseProvisioning)
+	}
+	for _, mutate := range mutators {
+		mutate(m)
+	}
+	return m
+}
+
+func replaceMachineProviderSpec(pc *machineapi.AWSMachineProviderConfig) func(*machineapi.Machine) {
+	rawAWSProviderSpec, err := encodeAWSMachineProviderSpec(pc, scheme.GetScheme())
+	if err != nil {
+		log.WithError(err).Fatal("error encoding custom machine provider spec")
+	}
+	return func(m *machineapi.Machine) {
+		m.Spec.ProviderSpec.Value = rawAWSProviderSpec
+	}
+}
+
+func withRolloutSurge(surge string) func(*machineapi.MachineSet) {
+	return func(ms *machineapi.MachineSet) {
+		ms.Annotations = map[string]string{rolloutSurgeAnnotation: surge}
+	}
+}
+
+func TestRolloutMachineSets(t *testing.T) {
+	cases := []struct {
+		name             string
+		strategy         hivev1.MachinePoolRolloutStrategy
+		machineSet       *machineapi.MachineSet
+		machines         []runtime.Object
+		expectedReplicas int32
+		expectedSurge    string
+		expectedDeleted  []string
+		expectedStatus   hivev1.MachinePoolRolloutStatus
+		expectNoDrain    []string
+	}{
+		{
+			name:       "all machines updated",
+			machineSet: testMachineSet(testName, testPoolName, false, 2, 0),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", false, true),
+				testRolloutMachine("machine-2", false, true),
+			},
+			expectedReplicas: 2,
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 2},
+		},
+		{
+			name:       "surge when machines are outdated",
+			machineSet: testMachineSet(testName, testPoolName, false, 2, 0),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", true, true),
+				testRolloutMachine("machine-2", true, true),
+			},
+			expectedReplicas: 3,
+			expectedSurge:    "1",
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{OutdatedReplicas: 2},
+		},
+		{
+			name:       "replace outdated machine once surged machine is available",
+			machineSet: testMachineSet(testName, testPoolName, false, 3, 0, withRolloutSurge("1")),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", true, true),
+				testRolloutMachine("machine-2", true, true),
+				testRolloutMachine("machine-3", false, true),
+			},
+			expectedReplicas: 3,
+			expectedSurge:    "1",
+			expectedDeleted:  []string{"machine-1"},
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 1, OutdatedReplicas: 2},
+		},
+		{
+			name:       "wait for surged machine to be available",
+			machineSet: testMachineSet(testName, testPoolName, false, 3, 0, withRolloutSurge("1")),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", true, true),
+				testRolloutMachine("machine-2", true, true),
+				testRolloutMachine("machine-3", false, false),
+			},
+			expectedReplicas: 3,
+			expectedSurge:    "1",
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 1, OutdatedReplicas: 2},
+		},
+		{
+			name:       "unavailable outdated machines replaced right away",
+			machineSet: testMachineSet(testName, testPoolName, false, 3, 0, withRolloutSurge("1")),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", true, true),
+				testRolloutMachine("machine-2", true, false),
+				testRolloutMachine("machine-3", false, false),
+			},
+			expectedReplicas: 3,
+			expectedSurge:    "1",
+			expectedDeleted:  []string{"machine-2"},
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 1, OutdatedReplicas: 2},
+		},
+		{
+			name: "replace within max unavailable without surge",
+			strategy: hivev1.MachinePoolRolloutStrategy{
+				MaxSurge:       intstrPtr(intstr.FromInt(0)),
+				MaxUnavailable: intstrPtr(intstr.FromString("50%")),
+			},
+			machineSet: testMachineSet(testName, testPoolName, false, 2, 0),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", true, true),
+				testRolloutMachine("machine-2", true, true),
+			},
+			expectedReplicas: 2,
+			expectedDeleted:  []string{"machine-1"},
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{OutdatedReplicas: 2},
+		},
+		{
+			name:       "surge removed once all machines are updated",
+			machineSet: testMachineSet(testName, testPoolName, false, 3, 0, withRolloutSurge("1")),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", false, true),
+				testRolloutMachine("machine-2", false, true),
+				testRolloutMachine("machine-3", false, true),
+			},
+			expectedReplicas: 2,
+			expectedSurge:    "0",
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 3},
+		},
+		{
+			name:       "drain skipped after drain timeout",
+			strategy:   hivev1.MachinePoolRolloutStrategy{DrainTimeout: &metav1.Duration{Duration: 10 * time.Minute}},
+			machineSet: testMachineSet(testName, testPoolName, false, 2, 0),
+			machines: []runtime.Object{
+				testRolloutMachine("machine-1", false, true),
+				testRolloutMachine("machine-2", false, true),
+				testRolloutMachine("machine-3", true, true, func(m *machineapi.Machine) {
+					m.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+					m.Finalizers = []string{"machine.machine.openshift.io"}
+				}),
+				testRolloutMachine("machine-4", true, true, func(m *machineapi.Machine) {
+					m.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+					m.Finalizers = []string{"machine.machine.openshift.io"}
+				}),
+			},
+			expectedReplicas: 2,
+			expectedStatus:   hivev1.MachinePoolRolloutStatus{UpdatedReplicas: 2},
+			expectNoDrain:    []string{"machine-3"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			remoteClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(append(tc.machines, tc.machineSet)...).Build()
+			pool := testMachinePool(func(mp *hivev1.MachinePool) {
+				mp.Spec.RolloutStrategy = &tc.strategy
+			})
+			r := &ReconcileMachinePool{}
+
+			status, err := r.rolloutMachineSets(pool, []*machineapi.MachineSet{tc.machineSet}, remoteClient, log.WithField("controller", "machinepool"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, *status, "unexpected rollout status")
+
+			ms := &machineapi.MachineSet{}
+			require.NoError(t, remoteClient.Get(context.TODO(), types.NamespacedName{Namespace: machineAPINamespace, Name: testName}, ms))
+			assert.Equal(t, tc.expectedReplicas, *ms.Spec.Replicas, "unexpected replicas")
+			assert.Equal(t, tc.expectedSurge, ms.Annotations[rolloutSurgeAnnotation], "unexpected surge")
+
+			for _, obj := range tc.machines {
+				name := obj.(*machineapi.Machine).Name
+				m := &machineapi.Machine{}
+				err := remoteClient.Get(context.TODO(), types.NamespacedName{Namespace: machineAPINamespace, Name: name}, m)
+				if contains(tc.expectedDeleted, name) {
+					assert.True(t, apierrors.IsNotFound(err), "expected machine %s to be deleted", name)
+					continue
+				}
+				require.NoError(t, err, "expected machine %s to exist", name)
+				_, noDrain := m.Annotations[excludeNodeDrainingAnnotation]
+				assert.Equal(t, contains(tc.expectNoDrain, name), noDrain, "unexpected drain annotation on machine %s", name)
+			}
+		})
+	}
+}
+
+func intstrPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package machinepool
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// applyScalingSchedules overrides the replicas or the autoscaling bounds of the machine pool with those of its first
+// active scaling schedule. Only the in-memory spec is changed. It returns the name of the active schedule, if any,
+// and the time of the next window start or end, when the pool must be reconciled again. Invalid schedules, which are
+// rejected by the webhook, are ignored.
+func applyScalingSchedules(pool *hivev1.MachinePool, now time.Time, logger log.FieldLogger) (string, time.Time) {
+	var active string
+	var nextTransition time.Time
+	for _, schedule := range pool.Spec.ScalingSchedules {
+		isActive, next, err := evaluateScalingSchedule(schedule, now)
+		if err != nil {
+			logger.WithError(err).WithField("schedule", schedule.Name).Warn("ignoring invalid scaling schedule")
+			continue
+		}
+		if !next.IsZero() && (nextTransition.IsZero() || next.Before(nextTransition)) {
+			nextTransition = next
+		}
+		if !isActive || active != "" {
+			continue
+		}
+		active = schedule.Name
+		switch {
+		case schedule.Replicas != nil && pool.Spec.Autoscaling == nil:
+			pool.Spec.Replicas = schedule.Replicas
+		case schedule.Autoscaling != nil && pool.Spec.Autoscaling != nil:
+			pool.Spec.Autoscaling = schedule.Autoscaling
+		}
+	}
+	if active != "" {
+		logger.WithField("schedule", active).Debug("scaling schedule active")
+	}
+	return active, nextTransition
+}
+
+// evaluateScalingSchedule returns whether the window of the scaling schedule is open at the given time, and when
+// the window next opens or closes.
+func evaluateScalingSchedule(schedule hivev1.MachinePoolScalingSchedule, now time.Time) (bool, time.Time, error) {
+	window := hivev1.ScheduleWindow{Days: schedule.Days, Start: schedule.Start, End: schedule.End}
+	active, _, next, err := controllerutils.EvaluateSchedule([]hivev1.ScheduleWindow{window}, schedule.TimeZone, now)
+	return active, next, err
+}
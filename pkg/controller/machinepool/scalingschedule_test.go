@@ -0,0 +1,167 @@
+package machinepool
+
+import (
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/utils/pointer"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+func TestEvaluateScalingSchedule(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	// January 8, 2024 is a Monday.
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 8, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name           string
+		schedule       hivev1.MachinePoolScalingSchedule
+		now            time.Time
+		expectedActive bool
+		expectedNext   time.Time
+	}{
+		{
+			name:           "inside daily window",
+			schedule:       hivev1.MachinePoolScalingSchedule{Start: "09:00", End: "17:00"},
+			now:            monday(12, 0),
+			expectedActive: true,
+			expectedNext:   monday(17, 0),
+		},
+		{
+			name:         "before daily window",
+			schedule:     hivev1.MachinePoolScalingSchedule{Start: "09:00", End: "17:00"},
+			now:          monday(7, 30),
+			expectedNext: monday(9, 0),
+		},
+		{
+			name:         "at window end",
+			schedule:     hivev1.MachinePoolScalingSchedule{Start: "09:00", End: "17:00"},
+			now:          monday(17, 0),
+			expectedNext: monday(9, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:           "overnight window started the previous day",
+			schedule:       hivev1.MachinePoolScalingSchedule{Start: "20:00", End: "08:00"},
+			now:            monday(3, 0),
+			expectedActive: true,
+			expectedNext:   monday(8, 0),
+		},
+		{
+			name: "overnight window started on a day not scheduled",
+			schedule: hivev1.MachinePoolScalingSchedule{
+				Days:  []hivev1.ScheduleDay{"Monday"},
+				Start: "20:00",
+				End:   "08:00",
+			},
+			now:          monday(3, 0),
+			expectedNext: monday(20, 0),
+		},
+		{
+			name: "next window later in the week",
+			schedule: hivev1.MachinePoolScalingSchedule{
+				Days:  []hivev1.ScheduleDay{"Saturday", "Sunday"},
+				Start: "00:00",
+				End:   "00:00",
+			},
+			now:          monday(12, 0),
+			expectedNext: monday(0, 0).AddDate(0, 0, 5),
+		},
+		{
+			name:           "time zone",
+			schedule:       hivev1.MachinePoolScalingSchedule{Start: "09:00", End: "17:00", TimeZone: "Europe/Paris"},
+			now:            monday(8, 30),
+			expectedActive: true,
+			expectedNext:   time.Date(2024, time.January, 8, 17, 0, 0, 0, paris),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			active, next, err := evaluateScalingSchedule(tc.schedule, tc.now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedActive, active, "unexpected active")
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next transition: expected %s, got %s", tc.expectedNext, next)
+		})
+	}
+}
+
+func TestApplyScalingSchedules(t *testing.T) {
+	now := time.Date(2024, time.January, 8, 22, 0, 0, 0, time.UTC)
+	night := hivev1.MachinePoolScalingSchedule{Name: "night", Start: "20:00", End: "08:00", Replicas: pointer.Int64(2)}
+	lunch := hivev1.MachinePoolScalingSchedule{Name: "lunch", Start: "12:00", End: "13:00", Replicas: pointer.Int64(5)}
+
+	cases := []struct {
+		name                string
+		pool                *hivev1.MachinePool
+		expectedActive      string
+		expectedReplicas    *int64
+		expectedAutoscaling *hivev1.MachinePoolAutoscaling
+		expectedNext        time.Time
+	}{
+		{
+			name:             "no schedules",
+			pool:             testMachinePool(),
+			expectedReplicas: pointer.Int64(3),
+		},
+		{
+			name: "replicas overridden",
+			pool: testMachinePool(func(mp *hivev1.MachinePool) {
+				mp.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{lunch, night}
+			}),
+			expectedActive:   "night",
+			expectedReplicas: pointer.Int64(2),
+			expectedNext:     time.Date(2024, time.January, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "first active schedule wins",
+			pool: testMachinePool(func(mp *hivev1.MachinePool) {
+				lateNight := hivev1.MachinePoolScalingSchedule{Name: "late-night", Start: "21:00", End: "06:00", Replicas: pointer.Int64(1)}
+				mp.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{lateNight, night}
+			}),
+			expectedActive:   "late-night",
+			expectedReplicas: pointer.Int64(1),
+			expectedNext:     time.Date(2024, time.January, 9, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "autoscaling bounds overridden",
+			pool: testMachinePool(func(mp *hivev1.MachinePool) {
+				mp.Spec.Replicas = nil
+				mp.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 10}
+				mp.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:        "night",
+					Start:       "20:00",
+					End:         "08:00",
+					Autoscaling: &hivev1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 2},
+				}}
+			}),
+			expectedActive:      "night",
+			expectedAutoscaling: &hivev1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 2},
+			expectedNext:        time.Date(2024, time.January, 9, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "invalid schedule ignored",
+			pool: testMachinePool(func(mp *hivev1.MachinePool) {
+				invalid := night
+				invalid.TimeZone = "Mars/Olympus_Mons"
+				mp.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{invalid}
+			}),
+			expectedReplicas: pointer.Int64(3),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			active, next := applyScalingSchedules(tc.pool, now, log.WithField("controller", "machinepool"))
+			assert.Equal(t, tc.expectedActive, active, "unexpected active schedule")
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next transition: expected %s, got %s", tc.expectedNext, next)
+			assert.Equal(t, tc.expectedReplicas, tc.pool.Spec.Replicas, "unexpected replicas")
+			assert.Equal(t, tc.expectedAutoscaling, tc.pool.Spec.Autoscaling, "unexpected autoscaling")
+		})
+	}
+}
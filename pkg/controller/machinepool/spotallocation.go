@@ -0,0 +1,245 @@
+package machinepool
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machineapi "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+const (
+	// machinePoolCapacityTypeLabel is set on the MachineSets of machine pools mixing on-demand and spot capacity,
+	// to tell apart the on-demand and spot MachineSets of a zone.
+	machinePoolCapacityTypeLabel = "hive.openshift.io/machine-pool-capacity-type"
+	// spotFallbackReplicasAnnotation is set on the on-demand MachineSets of machine pools mixing on-demand and spot
+	// capacity, to the number of replicas that run on on-demand capacity because spot capacity was unavailable.
+	spotFallbackReplicasAnnotation = "hive.openshift.io/spot-fallback-replicas"
+	// spotFallbackTimeAnnotation is set on the on-demand MachineSets of machine pools mixing on-demand and spot
+	// capacity, to the time replicas last fell back to on-demand capacity.
+	spotFallbackTimeAnnotation = "hive.openshift.io/spot-fallback-time"
+	// onDemandMachineSetSuffix is appended to the names of the generated MachineSets to name their on-demand
+	// counterparts. It is kept short as names of machines are limited on some clouds.
+	onDemandMachineSetSuffix = "-od"
+
+	defaultSpotFallbackRetryInterval = time.Hour
+)
+
+// pairOnDemandMachineSets returns the MachineSets to use for a machine pool with spot allocation: each of the
+// generated MachineSets, which run on spot capacity, followed by an on-demand copy for the same zone.
+func pairOnDemandMachineSets(generatedMachineSets []*machineapi.MachineSet) ([]*machineapi.MachineSet, error) {
+	machineSets := make([]*machineapi.MachineSet, 0, 2*len(generatedMachineSets))
+	for _, spotMS := range generatedMachineSets {
+		onDemandMS := spotMS.DeepCopy()
+		if err := clearSpotOptions(onDemandMS); err != nil {
+			return nil, err
+		}
+		onDemandMS.Name = spotMS.Name + onDemandMachineSetSuffix
+		if _, ok := onDemandMS.Spec.Selector.MatchLabels[capiMachineSetKey]; ok {
+			onDemandMS.Spec.Selector.MatchLabels[capiMachineSetKey] = onDemandMS.Name
+		}
+		if _, ok := onDemandMS.Spec.Template.Labels[capiMachineSetKey]; ok {
+			onDemandMS.Spec.Template.Labels[capiMachineSetKey] = onDemandMS.Name
+		}
+
+		setCapacityType(spotMS, hivev1.MachineSetCapacitySpot)
+		setCapacityType(onDemandMS, hivev1.MachineSetCapacityOnDemand)
+		machineSets = append(machineSets, spotMS, onDemandMS)
+	}
+	return machineSets, nil
+}
+
+func setCapacityType(ms *machineapi.MachineSet, capacityType hivev1.MachineSetCapacityType) {
+	if ms.Labels == nil {
+		ms.Labels = map[string]string{}
+	}
+	ms.Labels[machinePoolCapacityTypeLabel] = string(capacityType)
+}
+
+// clearSpotOptions makes the machines of a generated MachineSet run on on-demand capacity.
+func clearSpotOptions(ms *machineapi.MachineSet) error {
+	if ms.Spec.Template.Spec.ProviderSpec.Value == nil {
+		return fmt.Errorf("machineset %s has no provider spec", ms.Name)
+	}
+	switch providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(type) {
+	case *machineapi.AWSMachineProviderConfig:
+		providerSpec.SpotMarketOptions = nil
+	case *machineapi.AzureMachineProviderSpec:
+		providerSpec.SpotVMOptions = nil
+	case *machineapi.GCPMachineProviderSpec:
+		providerSpec.Preemptible = false
+	default:
+		return fmt.Errorf("spot allocation is not supported for the provider spec of machineset %s", ms.Name)
+	}
+	return nil
+}
+
+// capacityTypesMatch decides whether generated and remote MachineSets with the given capacity type labels run on
+// the same kind of capacity. MachineSets without the label were generated without spot allocation and run on the
+// capacity configured in the platform, which is spot capacity when the pool uses or used spot allocation.
+func capacityTypesMatch(gCapacityType, rCapacityType string) bool {
+	switch {
+	case gCapacityType == rCapacityType:
+		return true
+	case gCapacityType == "":
+		return rCapacityType == string(hivev1.MachineSetCapacitySpot)
+	case rCapacityType == "":
+		return gCapacityType == string(hivev1.MachineSetCapacitySpot)
+	}
+	return false
+}
+
+// allocateSpotReplicas splits the replicas of a machine pool with spot allocation between the paired spot and
+// on-demand MachineSets returned by pairOnDemandMachineSets. The on-demand base replicas and the remaining spot
+// replicas are each spread across the zones. When spot machines of a zone fail, the spot replicas that are not
+// running fall back to the on-demand MachineSet of the zone until the fallback retry interval has passed.
+func allocateSpotReplicas(
+	pool *hivev1.MachinePool,
+	machineSets []*machineapi.MachineSet,
+	remoteMachineSets *machineapi.MachineSetList,
+	infrastructure *configv1.Infrastructure,
+	remoteClusterAPIClient client.Client,
+	logger log.FieldLogger,
+) error {
+	zones := len(machineSets) / 2
+	if zones == 0 {
+		return nil
+	}
+	replicas := int32(1)
+	if pool.Spec.Replicas != nil {
+		replicas = int32(*pool.Spec.Replicas)
+	}
+	onDemandBase := pool.Spec.SpotAllocation.OnDemandBaseReplicas
+	if onDemandBase > replicas {
+		onDemandBase = replicas
+	}
+	retryInterval := defaultSpotFallbackRetryInterval
+	if d := pool.Spec.SpotAllocation.FallbackRetryInterval; d != nil {
+		retryInterval = d.Duration
+	}
+	now := time.Now()
+
+	findRemote := func(ms *machineapi.MachineSet) (*machineapi.MachineSet, error) {
+		for i, rMS := range remoteMachineSets.Items {
+			found, err := matchMachineSets(ms, rMS, infrastructure, logger)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				return &remoteMachineSets.Items[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	for zone := 0; zone < zones; zone++ {
+		spotMS, onDemandMS := machineSets[2*zone], machineSets[2*zone+1]
+		spotReplicas := replicasForZone(replicas-onDemandBase, zones, zone)
+		onDemandReplicas := replicasForZone(onDemandBase, zones, zone)
+
+		rSpotMS, err := findRemote(spotMS)
+		if err != nil {
+			return err
+		}
+		rOnDemandMS, err := findRemote(onDemandMS)
+		if err != nil {
+			return err
+		}
+
+		var fallback int32
+		var fallbackTime time.Time
+		if rOnDemandMS != nil {
+			fallback, fallbackTime = spotFallback(rOnDemandMS)
+		}
+		msLog := logger.WithField("machineset", spotMS.Name)
+		if rSpotMS != nil {
+			running, failed, err := countSpotMachines(remoteClusterAPIClient, rSpotMS)
+			if err != nil {
+				msLog.WithError(err).Error("failed to list machines for the machineset")
+				return err
+			}
+			switch {
+			case failed > 0:
+				if running > spotReplicas {
+					running = spotReplicas
+				}
+				if unavailable := spotReplicas - running; unavailable > fallback {
+					msLog.WithField("failed", failed).WithField("fallback", unavailable).Info("spot capacity unavailable, falling back to on-demand capacity")
+					fallback = unavailable
+					fallbackTime = now
+				}
+			case fallback > 0 && now.Sub(fallbackTime) >= retryInterval:
+				msLog.WithField("fallback", fallback).Info("retrying spot capacity")
+				fallback = 0
+			}
+		}
+		if fallback > spotReplicas {
+			fallback = spotReplicas
+		}
+
+		spotMS.Spec.Replicas = pointer.Int32(spotReplicas - fallback)
+		onDemandMS.Spec.Replicas = pointer.Int32(onDemandReplicas + fallback)
+		if onDemandMS.Annotations == nil {
+			onDemandMS.Annotations = map[string]string{}
+		}
+		onDemandMS.Annotations[spotFallbackReplicasAnnotation] = strconv.Itoa(int(fallback))
+		if !fallbackTime.IsZero() {
+			onDemandMS.Annotations[spotFallbackTimeAnnotation] = fallbackTime.UTC().Format(time.RFC3339)
+		}
+	}
+	return nil
+}
+
+// spotFallback returns the number of replicas of an on-demand MachineSet that fell back from spot capacity, and
+// when they last did.
+func spotFallback(ms *machineapi.MachineSet) (int32, time.Time) {
+	fallback, err := strconv.ParseInt(ms.Annotations[spotFallbackReplicasAnnotation], 10, 32)
+	if err != nil || fallback < 0 {
+		return 0, time.Time{}
+	}
+	// An unparseable time retries spot capacity right away.
+	fallbackTime, _ := time.Parse(time.RFC3339, ms.Annotations[spotFallbackTimeAnnotation])
+	return int32(fallback), fallbackTime
+}
+
+// countSpotMachines returns the number of machines of a MachineSet that are running or being provisioned, and the
+// number of machines that failed.
+func countSpotMachines(remoteClusterAPIClient client.Client, ms *machineapi.MachineSet) (running, failed int32, err error) {
+	sel, err := metav1.LabelSelectorAsSelector(&ms.Spec.Selector)
+	if err != nil {
+		return 0, 0, err
+	}
+	list := &machineapi.MachineList{}
+	if err := remoteClusterAPIClient.List(context.TODO(), list,
+		client.InNamespace(ms.GetNamespace()),
+		client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return 0, 0, err
+	}
+	for _, m := range list.Items {
+		switch {
+		case m.Status.Phase != nil && *m.Status.Phase == machineapi.PhaseFailed:
+			failed++
+		case m.DeletionTimestamp == nil:
+			running++
+		}
+	}
+	return running, failed, nil
+}
+
+// replicasForZone spreads replicas across zones, giving the remainder to the first zones.
+func replicasForZone(replicas int32, zones, zone int) int32 {
+	n := replicas / int32(zones)
+	if int32(zone) < replicas%int32(zones) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,220 @@
+package machinepool
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machineapi "github.com/openshift/api/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+)
+
+func testSpotMachineSet(name, az string) *machineapi.MachineSet {
+	pc := testAWSProviderSpec()
+	pc.Placement.AvailabilityZone = az
+	pc.SpotMarketOptions = &machineapi.SpotMarketOptions{}
+	return testMachineSet(name, testPoolName, false, 1, 0, func(ms *machineapi.MachineSet) {
+		ms.Spec.Template.Spec.ProviderSpec.Value = &runtime.RawExtension{Object: pc}
+	})
+}
+
+func testRemoteMachineSet(name, az string, capacityType hivev1.MachineSetCapacityType, replicas int) *machineapi.MachineSet {
+	ms := testMachineSetWithAZ(name, testPoolName, false, replicas, 0, az)
+	ms.Labels[machinePoolCapacityTypeLabel] = string(capacityType)
+	return ms
+}
+
+func testMachineInPhase(name, machineSetName, phase string) *machineapi.Machine {
+	m := testMachineSetMachine(name, testPoolName, machineSetName)
+	m.Status.Phase = pointer.String(phase)
+	return m
+}
+
+func withSpotFallback(replicas int, since time.Time) func(*machineapi.MachineSet) {
+	return func(ms *machineapi.MachineSet) {
+		ms.Annotations = map[string]string{
+			spotFallbackReplicasAnnotation: strconv.Itoa(replicas),
+			spotFallbackTimeAnnotation:     since.UTC().Format(time.RFC3339),
+		}
+	}
+}
+
+func TestPairOnDemandMachineSets(t *testing.T) {
+	machineSets, err := pairOnDemandMachineSets([]*machineapi.MachineSet{
+		testSpotMachineSet("foo-12345-worker-us-east-1a", "us-east-1a"),
+		testSpotMachineSet("foo-12345-worker-us-east-1b", "us-east-1b"),
+	})
+	require.NoError(t, err)
+	require.Len(t, machineSets, 4)
+
+	for i, expected := range []struct {
+		name         string
+		capacityType hivev1.MachineSetCapacityType
+		spot         bool
+	}{
+		{name: "foo-12345-worker-us-east-1a", capacityType: hivev1.MachineSetCapacitySpot, spot: true},
+		{name: "foo-12345-worker-us-east-1a-od", capacityType: hivev1.MachineSetCapacityOnDemand},
+		{name: "foo-12345-worker-us-east-1b", capacityType: hivev1.MachineSetCapacitySpot, spot: true},
+		{name: "foo-12345-worker-us-east-1b-od", capacityType: hivev1.MachineSetCapacityOnDemand},
+	} {
+		ms := machineSets[i]
+		assert.Equal(t, expected.name, ms.Name, "unexpected name")
+		assert.Equal(t, string(expected.capacityType), ms.Labels[machinePoolCapacityTypeLabel], "unexpected capacity type for %s", ms.Name)
+		assert.Equal(t, expected.name, ms.Spec.Selector.MatchLabels[capiMachineSetKey], "unexpected selector for %s", ms.Name)
+		assert.Equal(t, expected.name, ms.Spec.Template.Labels[capiMachineSetKey], "unexpected template labels for %s", ms.Name)
+		providerSpec := ms.Spec.Template.Spec.ProviderSpec.Value.Object.(*machineapi.AWSMachineProviderConfig)
+		assert.Equal(t, expected.spot, providerSpec.SpotMarketOptions != nil, "unexpected spot market options for %s", ms.Name)
+	}
+}
+
+func TestCapacityTypesMatch(t *testing.T) {
+	spot, onDemand := string(hivev1.MachineSetCapacitySpot), string(hivev1.MachineSetCapacityOnDemand)
+	cases := []struct {
+		generated, remote string
+		expected          bool
+	}{
+		{generated: "", remote: "", expected: true},
+		{generated: spot, remote: spot, expected: true},
+		{generated: onDemand, remote: onDemand, expected: true},
+		{generated: spot, remote: "", expected: true},
+		{generated: "", remote: spot, expected: true},
+		{generated: onDemand, remote: "", expected: false},
+		{generated: "", remote: onDemand, expected: false},
+		{generated: spot, remote: onDemand, expected: false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, capacityTypesMatch(tc.generated, tc.remote), "generated %q, remote %q", tc.generated, tc.remote)
+	}
+}
+
+func TestAllocateSpotReplicas(t *testing.T) {
+	const (
+		spotA     = "foo-12345-worker-us-east-1a"
+		onDemandA = spotA + onDemandMachineSetSuffix
+	)
+	recently := time.Now().Add(-10 * time.Minute)
+	longAgo := time.Now().Add(-2 * time.Hour)
+
+	cases := []struct {
+		name                     string
+		remoteExisting           []runtime.Object
+		expectedReplicas         []int32
+		expectedFallback         []string
+		expectFallbackTimeUpdate bool
+	}{
+		{
+			name:             "no remote machinesets",
+			expectedReplicas: []int32{2, 1, 1, 1},
+			expectedFallback: []string{"0", "0"},
+		},
+		{
+			name: "spot machines running",
+			remoteExisting: []runtime.Object{
+				testRemoteMachineSet(spotA, "us-east-1a", hivev1.MachineSetCapacitySpot, 2),
+				testMachineInPhase("machine-1", spotA, "Running"),
+				testMachineInPhase("machine-2", spotA, "Provisioning"),
+			},
+			expectedReplicas: []int32{2, 1, 1, 1},
+			expectedFallback: []string{"0", "0"},
+		},
+		{
+			name: "spot machine failed",
+			remoteExisting: []runtime.Object{
+				testRemoteMachineSet(spotA, "us-east-1a", hivev1.MachineSetCapacitySpot, 2),
+				testRemoteMachineSet(onDemandA, "us-east-1a", hivev1.MachineSetCapacityOnDemand, 1),
+				testMachineInPhase("machine-1", spotA, "Running"),
+				testMachineInPhase("machine-2", spotA, machineapi.PhaseFailed),
+			},
+			expectedReplicas:         []int32{1, 2, 1, 1},
+			expectedFallback:         []string{"1", "0"},
+			expectFallbackTimeUpdate: true,
+		},
+		{
+			name: "legacy unlabeled spot machineset with failed machines",
+			remoteExisting: []runtime.Object{
+				testMachineSetWithAZ(spotA, testPoolName, false, 2, 0, "us-east-1a"),
+				testMachineInPhase("machine-1", spotA, machineapi.PhaseFailed),
+				testMachineInPhase("machine-2", spotA, machineapi.PhaseFailed),
+			},
+			expectedReplicas:         []int32{0, 3, 1, 1},
+			expectedFallback:         []string{"2", "0"},
+			expectFallbackTimeUpdate: true,
+		},
+		{
+			name: "recent fallback kept",
+			remoteExisting: []runtime.Object{
+				testRemoteMachineSet(spotA, "us-east-1a", hivev1.MachineSetCapacitySpot, 1),
+				func() *machineapi.MachineSet {
+					ms := testRemoteMachineSet(onDemandA, "us-east-1a", hivev1.MachineSetCapacityOnDemand, 2)
+					withSpotFallback(1, recently)(ms)
+					return ms
+				}(),
+				testMachineInPhase("machine-1", spotA, "Running"),
+			},
+			expectedReplicas: []int32{1, 2, 1, 1},
+			expectedFallback: []string{"1", "0"},
+		},
+		{
+			name: "spot capacity retried after the fallback retry interval",
+			remoteExisting: []runtime.Object{
+				testRemoteMachineSet(spotA, "us-east-1a", hivev1.MachineSetCapacitySpot, 1),
+				func() *machineapi.MachineSet {
+					ms := testRemoteMachineSet(onDemandA, "us-east-1a", hivev1.MachineSetCapacityOnDemand, 2)
+					withSpotFallback(1, longAgo)(ms)
+					return ms
+				}(),
+				testMachineInPhase("machine-1", spotA, "Running"),
+			},
+			expectedReplicas: []int32{2, 1, 1, 1},
+			expectedFallback: []string{"0", "0"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool := testMachinePool(func(mp *hivev1.MachinePool) {
+				mp.Spec.Replicas = pointer.Int64(5)
+				mp.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{OnDemandBaseReplicas: 2}
+			})
+			machineSets, err := pairOnDemandMachineSets([]*machineapi.MachineSet{
+				testSpotMachineSet(spotA, "us-east-1a"),
+				testSpotMachineSet("foo-12345-worker-us-east-1b", "us-east-1b"),
+			})
+			require.NoError(t, err)
+
+			infra := &configv1.Infrastructure{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+			remoteClient := testfake.NewFakeClientBuilder().WithRuntimeObjects(append(tc.remoteExisting, infra)...).Build()
+			remoteMachineSets := &machineapi.MachineSetList{}
+			for _, obj := range tc.remoteExisting {
+				if ms, ok := obj.(*machineapi.MachineSet); ok {
+					remoteMachineSets.Items = append(remoteMachineSets.Items, *ms)
+				}
+			}
+
+			err = allocateSpotReplicas(pool, machineSets, remoteMachineSets, infra, remoteClient, log.WithField("controller", "machinepool"))
+			require.NoError(t, err)
+
+			for i, ms := range machineSets {
+				assert.Equal(t, tc.expectedReplicas[i], *ms.Spec.Replicas, "unexpected replicas for %s", ms.Name)
+			}
+			for zone := range tc.expectedFallback {
+				onDemandMS := machineSets[2*zone+1]
+				assert.Equal(t, tc.expectedFallback[zone], onDemandMS.Annotations[spotFallbackReplicasAnnotation], "unexpected fallback for %s", onDemandMS.Name)
+			}
+			if tc.expectFallbackTimeUpdate {
+				_, fallbackTime := spotFallback(machineSets[1])
+				assert.WithinDuration(t, time.Now(), fallbackTime, time.Minute, "unexpected fallback time")
+			}
+		})
+	}
+}
@@ -406,3 +406,112 @@ func newClusterSyncFailingCollector(client client.Client, minimum time.Duration,
 		dynamicLabels: labels,
 	}
 }
+
+// cost estimate metrics collected through a custom prometheus collector
+type clusterCostCollector struct {
+	client client.Client
+
+	// metricClusterDeploymentHourlyCost and metricClusterDeploymentCost are the estimated hourly and total costs
+	// of each ClusterDeployment.
+	metricClusterDeploymentHourlyCost *prometheus.Desc
+	metricClusterDeploymentCost       *prometheus.Desc
+
+	// metricClusterPoolHourlyCost and metricClusterPoolCost are the estimated hourly and total costs of the
+	// ClusterDeployments in each ClusterPool, claimed or not.
+	metricClusterPoolHourlyCost *prometheus.Desc
+	metricClusterPoolCost       *prometheus.Desc
+
+	// metricClaimNamespaceHourlyCost and metricClaimNamespaceCost are the estimated hourly and total costs of the
+	// claimed ClusterDeployments in each namespace holding ClusterClaims.
+	metricClaimNamespaceHourlyCost *prometheus.Desc
+	metricClaimNamespaceCost       *prometheus.Desc
+}
+
+// Collect collects the metrics for clusterCostCollector
+func (cc clusterCostCollector) Collect(ch chan<- prometheus.Metric) {
+	ccLog := log.WithField("controller", "metrics")
+	ccLog.Info("calculating cost estimate metrics")
+
+	clusterDeployments := &hivev1.ClusterDeploymentList{}
+	if err := cc.client.List(context.Background(), clusterDeployments); err != nil {
+		ccLog.WithError(err).Error("error listing cluster deployments")
+		return
+	}
+	claimedByNamespace := map[string][]*hivev1.ClusterDeployment{}
+	for i := range clusterDeployments.Items {
+		cd := &clusterDeployments.Items[i]
+		est := cd.Status.CostEstimate
+		if est == nil {
+			continue
+		}
+		clusterType := GetLabelValue(cd, hivev1.HiveClusterTypeLabel)
+		ch <- prometheus.MustNewConstMetric(cc.metricClusterDeploymentHourlyCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(est.HourlyCost), cd.Name, cd.Namespace, clusterType, est.Currency)
+		ch <- prometheus.MustNewConstMetric(cc.metricClusterDeploymentCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(est.TotalCost), cd.Name, cd.Namespace, clusterType, est.Currency)
+		// ClusterClaims live in their ClusterPool's namespace.
+		if poolRef := cd.Spec.ClusterPoolRef; poolRef != nil && poolRef.ClaimName != "" {
+			claimedByNamespace[poolRef.Namespace] = append(claimedByNamespace[poolRef.Namespace], cd)
+		}
+	}
+	for namespace, cds := range claimedByNamespace {
+		sum := controllerutils.SumCostEstimates(cds)
+		ch <- prometheus.MustNewConstMetric(cc.metricClaimNamespaceHourlyCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(sum.HourlyCost), namespace, sum.Currency)
+		ch <- prometheus.MustNewConstMetric(cc.metricClaimNamespaceCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(sum.TotalCost), namespace, sum.Currency)
+	}
+
+	clusterPools := &hivev1.ClusterPoolList{}
+	if err := cc.client.List(context.Background(), clusterPools); err != nil {
+		ccLog.WithError(err).Error("error listing cluster pools")
+		return
+	}
+	for _, clp := range clusterPools.Items {
+		est := clp.Status.CostEstimate
+		if est == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(cc.metricClusterPoolHourlyCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(est.HourlyCost), clp.Namespace, clp.Name, est.Currency)
+		ch <- prometheus.MustNewConstMetric(cc.metricClusterPoolCost, prometheus.GaugeValue,
+			controllerutils.ParseCost(est.TotalCost), clp.Namespace, clp.Name, est.Currency)
+	}
+}
+
+func (cc clusterCostCollector) Describe(ch chan<- *prometheus.Desc) {
+	prometheus.DescribeByCollect(cc, ch)
+}
+
+func newClusterCostCollector(client client.Client) prometheus.Collector {
+	cdLabels := []string{"cluster_deployment", "namespace", "cluster_type", "currency"}
+	poolLabels := []string{"clusterpool_namespace", "clusterpool_name", "currency"}
+	namespaceLabels := []string{"namespace", "currency"}
+	return clusterCostCollector{
+		client: client,
+		metricClusterDeploymentHourlyCost: prometheus.NewDesc(
+			"hive_cluster_deployment_estimated_hourly_cost",
+			"Estimated cost per hour of running a cluster.",
+			cdLabels, nil),
+		metricClusterDeploymentCost: prometheus.NewDesc(
+			"hive_cluster_deployment_estimated_cost",
+			"Estimated cost of a cluster since it was installed.",
+			cdLabels, nil),
+		metricClusterPoolHourlyCost: prometheus.NewDesc(
+			"hive_clusterpool_estimated_hourly_cost",
+			"Estimated cost per hour of running the clusters in a cluster pool.",
+			poolLabels, nil),
+		metricClusterPoolCost: prometheus.NewDesc(
+			"hive_clusterpool_estimated_cost",
+			"Estimated cost of the clusters currently in a cluster pool since they were installed.",
+			poolLabels, nil),
+		metricClaimNamespaceHourlyCost: prometheus.NewDesc(
+			"hive_clusterclaim_namespace_estimated_hourly_cost",
+			"Estimated cost per hour of running the clusters claimed from a namespace.",
+			namespaceLabels, nil),
+		metricClaimNamespaceCost: prometheus.NewDesc(
+			"hive_clusterclaim_namespace_estimated_cost",
+			"Estimated cost of the clusters claimed from a namespace since they were installed.",
+			namespaceLabels, nil),
+	}
+}
@@ -893,3 +893,66 @@ func metricPrettyWithValue(d *dto.Metric) string {
 	}
 	return fmt.Sprintf("%s %d", labels, value)
 }
+
+func TestClusterCostCollector(t *testing.T) {
+	scheme := scheme.GetScheme()
+
+	withCost := func(hourly, total string) testcd.Option {
+		return func(cd *hivev1.ClusterDeployment) {
+			cd.Status.CostEstimate = &hivev1.ClusterCostEstimate{
+				CostSummary: hivev1.CostSummary{Currency: "USD", HourlyCost: hourly, TotalCost: total},
+			}
+		}
+	}
+	pool := &hivev1.ClusterPool{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "pools", Name: "pool-1"},
+		Status: hivev1.ClusterPoolStatus{
+			CostEstimate: &hivev1.CostSummary{Currency: "USD", HourlyCost: "3.0000", TotalCost: "30.0000"},
+		},
+	}
+	existing := []runtime.Object{
+		testcd.FullBuilder("cd-1", "cd-1", scheme).Build(withCost("1.0000", "10.0000"),
+			testcd.WithClusterPoolReference("pools", "pool-1", "claim-1")),
+		testcd.FullBuilder("cd-2", "cd-2", scheme).Build(withCost("2.0000", "20.0000"),
+			testcd.WithClusterPoolReference("pools", "pool-1", "claim-2")),
+		testcd.FullBuilder("cd-3", "cd-3", scheme).Build(withCost("4.0000", "40.0000"),
+			testcd.WithUnclaimedClusterPoolReference("pools", "pool-1")),
+		testcd.FullBuilder("cd-4", "cd-4", scheme).Build(),
+		pool,
+	}
+	c := testfake.NewFakeClientBuilder().WithRuntimeObjects(existing...).Build()
+	collect := newClusterCostCollector(c)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		collect.Collect(ch)
+		close(ch)
+	}()
+	got := map[string][]string{}
+	for sample := range ch {
+		var d dto.Metric
+		require.NoError(t, sample.Write(&d))
+		name := strings.Split(sample.Desc().String(), "\"")[1]
+		got[name] = append(got[name], metricPrettyWithValue(&d))
+	}
+
+	assert.Equal(t, []string{
+		"cluster_deployment = cd-1 cluster_type = unspecified currency = USD namespace = cd-1 1",
+		"cluster_deployment = cd-2 cluster_type = unspecified currency = USD namespace = cd-2 2",
+		"cluster_deployment = cd-3 cluster_type = unspecified currency = USD namespace = cd-3 4",
+	}, got["hive_cluster_deployment_estimated_hourly_cost"], "unexpected cluster deployment hourly costs")
+	assert.Equal(t, []string{
+		"cluster_deployment = cd-1 cluster_type = unspecified currency = USD namespace = cd-1 10",
+		"cluster_deployment = cd-2 cluster_type = unspecified currency = USD namespace = cd-2 20",
+		"cluster_deployment = cd-3 cluster_type = unspecified currency = USD namespace = cd-3 40",
+	}, got["hive_cluster_deployment_estimated_cost"], "unexpected cluster deployment costs")
+	assert.Equal(t, []string{"clusterpool_name = pool-1 clusterpool_namespace = pools currency = USD 3"},
+		got["hive_clusterpool_estimated_hourly_cost"], "unexpected cluster pool hourly cost")
+	assert.Equal(t, []string{"clusterpool_name = pool-1 clusterpool_namespace = pools currency = USD 30"},
+		got["hive_clusterpool_estimated_cost"], "unexpected cluster pool cost")
+	// Only claimed clusters count towards the claim namespace.
+	assert.Equal(t, []string{"currency = USD namespace = pools 3"},
+		got["hive_clusterclaim_namespace_estimated_hourly_cost"], "unexpected claim namespace hourly cost")
+	assert.Equal(t, []string{"currency = USD namespace = pools 30"},
+		got["hive_clusterclaim_namespace_estimated_cost"], "unexpected claim namespace cost")
+}
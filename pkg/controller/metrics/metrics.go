@@ -199,6 +199,10 @@ func Add(mgr manager.Manager) error {
 	metrics.Registry.MustRegister(newProvisioningUnderwayInstallRestartsCollector(mgr.GetClient(), 1))
 	// TODO: Add deprovisioning underway metric to set of optional duration-based metrics
 	metrics.Registry.MustRegister(newDeprovisioningUnderwaySecondsCollector(mgr.GetClient()))
+	// Cost estimates are only maintained when a price table has been configured.
+	if os.Getenv(constants.CostEstimationPriceTableConfigMapEnvVar) != "" {
+		metrics.Registry.MustRegister(newClusterCostCollector(mgr.GetClient()))
+	}
 
 	return mgr.Add(mc)
 }
@@ -774,6 +778,20 @@ func ReadMetricsConfig() (*metricsconfig.MetricsConfig, error) {
 	return config, nil
 }
 
+// IsClusterDeploymentMetricEnabled returns whether the admin opted into an optional metric reported for each
+// ClusterDeployment via HiveConfig.Spec.MetricsConfig.ClusterDeploymentMetrics.
+func IsClusterDeploymentMetricEnabled(config *metricsconfig.MetricsConfig, metric metricsconfig.ClusterDeploymentMetricType) bool {
+	if config == nil {
+		return false
+	}
+	for _, m := range config.ClusterDeploymentMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
 // logHistogramDurationMetric should be used to log duration metrics of Histogram type
 // It accounts for cluster deployment name, namespace, platform, version and cluster pool namespace as labels
 func logHistogramDurationMetric(metric *prometheus.HistogramVec, cd *hivev1.ClusterDeployment, time float64) {
@@ -0,0 +1,117 @@
+package azureactuator
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// networkAPIVersion is the Microsoft.Network API version used for the resources that are managed
+	// through the generic resources client.
+	networkAPIVersion = "2023-09-01"
+)
+
+var (
+	requeueLater = reconcile.Result{RequeueAfter: 1 * time.Minute}
+)
+
+// initialURL returns the initial API URL for the ClusterProvision.
+func initialURL(c client.Client, key client.ObjectKey) (string, error) {
+	kubeconfigSecret := &corev1.Secret{}
+	if err := c.Get(
+		context.Background(),
+		key,
+		kubeconfigSecret,
+	); err != nil {
+		return "", err
+	}
+	cfg, err := controllerutils.RestConfigFromSecret(kubeconfigSecret, true)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load the kubeconfig")
+	}
+
+	u, err := url.Parse(cfg.Host)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the kubeconfig")
+	}
+	return strings.TrimSuffix(u.Hostname(), "."), nil
+}
+
+// clusterResourceGroup returns the resource group in which the cluster resources were created.
+func clusterResourceGroup(metadata *hivev1.ClusterMetadata) string {
+	if metadata.Platform != nil &&
+		metadata.Platform.Azure != nil &&
+		metadata.Platform.Azure.ResourceGroupName != nil &&
+		*metadata.Platform.Azure.ResourceGroupName != "" {
+		return *metadata.Platform.Azure.ResourceGroupName
+	}
+	return metadata.InfraID + "-rg"
+}
+
+// networkResourceID returns the ID of a Microsoft.Network resource.
+func networkResourceID(subscriptionID, resourceGroup, resourceType, name string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network/%s/%s",
+		subscriptionID, resourceGroup, resourceType, name)
+}
+
+// virtualNetworkFromSubnetID returns the ID of the virtual network containing the subnet with the given ID.
+func virtualNetworkFromSubnetID(subnetID string) (string, error) {
+	idx := strings.Index(strings.ToLower(subnetID), "/subnets/")
+	if idx <= 0 {
+		return "", fmt.Errorf("invalid subnet ID %q", subnetID)
+	}
+	return subnetID[:idx], nil
+}
+
+// resourceName returns the last segment of a resource ID.
+func resourceName(resourceID string) string {
+	return resourceID[strings.LastIndex(resourceID, "/")+1:]
+}
+
+func initPrivateLinkStatus(cd *hivev1.ClusterDeployment) {
+	if cd.Status.Platform == nil {
+		cd.Status.Platform = &hivev1.PlatformStatus{}
+	}
+	if cd.Status.Platform.Azure == nil {
+		cd.Status.Platform.Azure = &hivev1azure.PlatformStatus{}
+	}
+	if cd.Status.Platform.Azure.PrivateLink == nil {
+		cd.Status.Platform.Azure.PrivateLink = &hivev1azure.PrivateLinkStatus{}
+	}
+}
+
+func updatePrivateLinkStatus(client *client.Client, cd *hivev1.ClusterDeployment) error {
+	var retryBackoff = wait.Backoff{
+		Steps:    5,
+		Duration: 1 * time.Second,
+		Factor:   1.0,
+		Jitter:   0.1,
+	}
+	return retry.RetryOnConflict(retryBackoff, func() error {
+		curr := &hivev1.ClusterDeployment{}
+		err := (*client).Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}, curr)
+		if err != nil {
+			return err
+		}
+
+		initPrivateLinkStatus(curr)
+		curr.Status.Platform.Azure.PrivateLink = cd.Status.Platform.Azure.PrivateLink
+		return (*client).Status().Update(context.TODO(), curr)
+	})
+}
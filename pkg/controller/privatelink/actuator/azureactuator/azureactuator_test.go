@@ -0,0 +1,212 @@
+package azureactuator
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/azureclient/mock"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	testcd "github.com/openshift/hive/pkg/test/clusterdeployment"
+	testsecret "github.com/openshift/hive/pkg/test/secret"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	testNS                = "test-namespace"
+	testCDName            = "test-cd"
+	testInfraID           = "test-infra"
+	testAPIDomain         = "api.test-cluster"
+	testRegion            = "eastus"
+	testHubSubscription   = "hub-sub"
+	testSpokeSubscription = "spoke-sub"
+	testHubResourceGroup  = "hub-rg"
+	testHubCredsSecret    = "hub-creds"
+	testSpokeCredsSecret  = "spoke-creds"
+	testKubeconfigSecret  = "test-kubeconfig"
+
+	testKubeconfig = `apiVersion: v1
+clusters:
+- cluster:
+    server: https://api.test-cluster:6443
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: admin
+  name: admin
+current-context: admin
+kind: Config
+users:
+- name: admin`
+)
+
+var (
+	testEndpointSubnet  = "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/endpoints/subnets/endpoints-1"
+	testEndpointVNet    = "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/endpoints"
+	testAssociatedVNet  = "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/hive"
+	testPrivateEndpoint = networkResourceID(testHubSubscription, testHubResourceGroup, "privateEndpoints", testInfraID+"-pe")
+	testPrivateLinkSvc  = networkResourceID(testSpokeSubscription, testInfraID+"-rg", "privateLinkServices", testInfraID+"-pls")
+	testPrivateDNSZone  = "/subscriptions/hub-sub/resourceGroups/hub-rg/providers/Microsoft.Network/privateDnsZones/" + testAPIDomain
+)
+
+func notFoundErr() error {
+	return autorest.DetailedError{StatusCode: http.StatusNotFound}
+}
+
+func testConfig() *hivev1.AzurePrivateLinkConfig {
+	return &hivev1.AzurePrivateLinkConfig{
+		CredentialsSecretRef: corev1.LocalObjectReference{Name: testHubCredsSecret},
+		ResourceGroupName:    testHubResourceGroup,
+		EndpointSubnetInventory: []hivev1.AzurePrivateLinkSubnet{{
+			Subnet: testEndpointSubnet,
+			Region: testRegion,
+		}},
+		AssociatedVirtualNetworks: []string{testAssociatedVNet},
+	}
+}
+
+func testMetadata() *hivev1.ClusterMetadata {
+	return &hivev1.ClusterMetadata{
+		InfraID:                  testInfraID,
+		AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: testKubeconfigSecret},
+	}
+}
+
+func testClusterDeployment(opts ...testcd.Option) *hivev1.ClusterDeployment {
+	cd := testcd.FullBuilder(testNS, testCDName, scheme.GetScheme()).Build(
+		testcd.WithAzurePlatform(&hivev1azure.Platform{
+			Region:               testRegion,
+			CredentialsSecretRef: corev1.LocalObjectReference{Name: testSpokeCredsSecret},
+			PrivateLink:          &hivev1azure.PrivateLink{Enabled: true},
+		}),
+		testcd.WithClusterMetadata(testMetadata()),
+	)
+	cd.Status.Conditions, _ = controllerutils.InitializeClusterDeploymentConditions(cd.Status.Conditions, []hivev1.ClusterDeploymentConditionType{
+		hivev1.PrivateLinkReadyClusterDeploymentCondition,
+		hivev1.PrivateLinkFailedClusterDeploymentCondition,
+	})
+	for _, opt := range opts {
+		opt(cd)
+	}
+	return cd
+}
+
+func withPrivateLinkStatus(status *hivev1azure.PrivateLinkStatus) testcd.Option {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.Platform = &hivev1.PlatformStatus{Azure: &hivev1azure.PlatformStatus{PrivateLink: status}}
+	}
+}
+
+// testSecrets returns the credentials of the hub and the spoke, and the admin kubeconfig of the cluster.
+func testSecrets() []client.Object {
+	return []client.Object{
+		testsecret.FullBuilder(controllerutils.GetHiveNamespace(), testHubCredsSecret, scheme.GetScheme()).Build(),
+		testsecret.FullBuilder(testNS, testSpokeCredsSecret, scheme.GetScheme()).Build(),
+		testsecret.FullBuilder(testNS, testKubeconfigSecret, scheme.GetScheme()).Build(
+			testsecret.WithDataKeyValue("kubeconfig", []byte(testKubeconfig)),
+		),
+	}
+}
+
+// mockClientFn returns an azureClientFn handing out the hub or the spoke client depending on the credentials used.
+func mockClientFn(hub, spoke azureclient.Client) azureClientFn {
+	return func(secret *corev1.Secret, cloudName string) (azureclient.Client, error) {
+		if secret.Name == testHubCredsSecret {
+			return hub, nil
+		}
+		return spoke, nil
+	}
+}
+
+func newMockClients(mockCtrl *gomock.Controller) (*mock.MockClient, *mock.MockClient) {
+	hub := mock.NewMockClient(mockCtrl)
+	hub.EXPECT().SubscriptionID().Return(testHubSubscription).AnyTimes()
+	spoke := mock.NewMockClient(mockCtrl)
+	spoke.EXPECT().SubscriptionID().Return(testSpokeSubscription).AnyTimes()
+	return hub, spoke
+}
+
+// mockVirtualNetworkLinkPage returns a single page listing the links to the given virtual networks.
+func mockVirtualNetworkLinkPage(mockCtrl *gomock.Controller, vnetIDs ...string) azureclient.VirtualNetworkLinkPage {
+	var links []privatedns.VirtualNetworkLink
+	for _, vnetID := range vnetIDs {
+		links = append(links, privatedns.VirtualNetworkLink{
+			Name: to.StringPtr(virtualNetworkLinkName(strings.ToLower(vnetID))),
+			VirtualNetworkLinkProperties: &privatedns.VirtualNetworkLinkProperties{
+				VirtualNetwork: &privatedns.SubResource{ID: to.StringPtr(vnetID)},
+			},
+		})
+	}
+	page := mock.NewMockVirtualNetworkLinkPage(mockCtrl)
+	gomock.InOrder(
+		page.EXPECT().NotDone().Return(true),
+		page.EXPECT().NotDone().Return(false),
+	)
+	page.EXPECT().Values().Return(links)
+	page.EXPECT().NextWithContext(gomock.Any()).Return(nil)
+	return page
+}
+
+func getClusterDeployment(t *testing.T, c client.Client) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{}
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: testNS, Name: testCDName}, cd))
+	return cd
+}
+
+func privateLinkStatus(cd *hivev1.ClusterDeployment) *hivev1azure.PrivateLinkStatus {
+	if cd.Status.Platform == nil || cd.Status.Platform.Azure == nil {
+		return nil
+	}
+	return cd.Status.Platform.Azure.PrivateLink
+}
+
+func TestClusterResourceGroup(t *testing.T) {
+	cases := []struct {
+		name     string
+		metadata *hivev1.ClusterMetadata
+		expected string
+	}{{
+		name:     "default",
+		metadata: testMetadata(),
+		expected: testInfraID + "-rg",
+	}, {
+		name: "from metadata",
+		metadata: &hivev1.ClusterMetadata{
+			InfraID: testInfraID,
+			Platform: &hivev1.ClusterPlatformMetadata{
+				Azure: &hivev1azure.Metadata{ResourceGroupName: ptr.To("custom-rg")},
+			},
+		},
+		expected: "custom-rg",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, clusterResourceGroup(test.metadata))
+		})
+	}
+}
+
+func TestVirtualNetworkFromSubnetID(t *testing.T) {
+	vnet, err := virtualNetworkFromSubnetID(testEndpointSubnet)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpointVNet, vnet)
+
+	_, err = virtualNetworkFromSubnetID("endpoints-1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,53 @@
+package azureactuator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hive/pkg/azureclient"
+)
+
+type azureClientFn func(secret *corev1.Secret, cloudName string) (azureclient.Client, error)
+
+func newAzureClient(client client.Client, clientFn azureClientFn, secretName string, secretNamespace string, cloudName string) (azureclient.Client, error) {
+	if clientFn == nil {
+		clientFn = azureclient.NewClientFromSecret
+	}
+
+	secret := &corev1.Secret{}
+	err := client.Get(context.TODO(),
+		types.NamespacedName{
+			Name:      secretName,
+			Namespace: secretNamespace,
+		},
+		secret)
+	if err != nil {
+		return nil, err
+	}
+	return clientFn(secret, cloudName)
+}
+
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	var de autorest.DetailedError
+	return errors.As(err, &de) && de.StatusCode == http.StatusNotFound
+}
+
+// convertProperties converts the JSON properties of a generic resource into out.
+func convertProperties(properties interface{}, out interface{}) error {
+	raw, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,341 @@
+package azureactuator
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/controller/privatelink/actuator"
+	"github.com/openshift/hive/pkg/controller/privatelink/conditions"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// Ensure AzureHubActuator implements the Actuator interface. This will fail at compile time when false.
+var _ actuator.Actuator = &AzureHubActuator{}
+
+// AzureHubActuator manages a private DNS zone for the API domain of the cluster in the hub subscription, and
+// links it to the virtual networks that must be able to reach the cluster through its private endpoint.
+type AzureHubActuator struct {
+	client *client.Client
+
+	config *hivev1.AzurePrivateLinkConfig
+
+	azureClientHub azureclient.Client
+}
+
+func NewAzureHubActuator(
+	client *client.Client,
+	config *hivev1.AzurePrivateLinkConfig,
+	azureClientFn azureClientFn,
+	logger log.FieldLogger) (*AzureHubActuator, error) {
+
+	actuator := &AzureHubActuator{
+		client: client,
+		config: config,
+	}
+
+	if config == nil {
+		return nil, errors.New("unable to create Azure actuator: config is empty")
+	}
+
+	hubClient, err := newAzureClient(*client, azureClientFn, config.CredentialsSecretRef.Name, controllerutils.GetHiveNamespace(), config.CloudName.Name())
+	if err != nil {
+		return nil, err
+	}
+	actuator.azureClientHub = hubClient
+
+	return actuator, nil
+}
+
+// Cleanup is the actuator interface for cleaning up the cloud resources.
+func (a *AzureHubActuator) Cleanup(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, logger log.FieldLogger) error {
+	if err := a.cleanupPrivateDNSZone(cd, metadata, logger); err != nil {
+		return errors.Wrap(err, "error cleaning up private DNS zone")
+	}
+
+	return nil
+}
+
+// CleanupRequired is the actuator interface for determining if cleanup is required.
+func (a *AzureHubActuator) CleanupRequired(cd *hivev1.ClusterDeployment) bool {
+	// There is nothing to do when PrivateLink is undefined.
+	// This either means it was never enabled, or it was already cleaned up.
+	if cd.Status.Platform == nil ||
+		cd.Status.Platform.Azure == nil ||
+		cd.Status.Platform.Azure.PrivateLink == nil {
+		return false
+	}
+
+	// There is nothing to do when deleting a ClusterDeployment with PreserveOnDelete and PrivateLink enabled.
+	if cd.DeletionTimestamp != nil &&
+		cd.Spec.PreserveOnDelete &&
+		cd.Spec.Platform.Azure != nil &&
+		cd.Spec.Platform.Azure.PrivateLink != nil &&
+		cd.Spec.Platform.Azure.PrivateLink.Enabled {
+		return false
+	}
+
+	return cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone != ""
+}
+
+// Reconcile is the actuator interface for reconciling the cloud resources.
+func (a *AzureHubActuator) Reconcile(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, dnsRecord *actuator.DnsRecord, logger log.FieldLogger) (reconcile.Result, error) {
+	logger.Debug("reconciling hub resources")
+
+	apiDomain, err := initialURL(*a.client,
+		client.ObjectKey{Namespace: cd.Namespace, Name: metadata.AdminKubeconfigSecretRef.Name})
+	if err != nil {
+		logger.WithError(err).Error("could not get API URL from kubeconfig")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "CouldNotCalculateAPIDomain", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, err
+	}
+
+	logger.Debug("reconciling Private DNS Zone")
+	zoneModified, err := a.ensurePrivateDNSZone(cd, apiDomain)
+	if err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private DNS Zone")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "PrivateDNSZoneReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private DNS Zone")
+	}
+	if zoneModified {
+		err := conditions.SetReadyConditionWithRetry(*a.client, cd, corev1.ConditionFalse,
+			"ReconciledPrivateDNSZone",
+			"reconciled the Private DNS Zone",
+			logger)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+	}
+
+	logger.Debug("reconciling Private DNS Zone records")
+	if err := a.reconcilePrivateDNSZoneRecords(apiDomain, dnsRecord); err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private DNS Zone records")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "PrivateDNSZoneRecordsReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private DNS Zone records")
+	}
+
+	logger.Debug("reconciling Private DNS Zone virtual network links")
+	linksModified, err := a.reconcileVirtualNetworkLinks(metadata, apiDomain, logger)
+	if err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private DNS Zone virtual network links")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "VirtualNetworkLinksReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private DNS Zone virtual network links")
+	}
+	if linksModified {
+		err := conditions.SetReadyConditionWithRetry(*a.client, cd, corev1.ConditionFalse,
+			"ReconciledVirtualNetworkLinks",
+			"reconciled the Private DNS Zone virtual network links",
+			logger)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// ShouldSync is the actuator interface to determine if there are changes that need to be made.
+func (a *AzureHubActuator) ShouldSync(cd *hivev1.ClusterDeployment) bool {
+	return cd.Status.Platform == nil ||
+		cd.Status.Platform.Azure == nil ||
+		cd.Status.Platform.Azure.PrivateLink == nil ||
+		cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone == ""
+}
+
+// ensurePrivateDNSZone creates the private DNS zone for the API domain if it does not already exist.
+func (a *AzureHubActuator) ensurePrivateDNSZone(cd *hivev1.ClusterDeployment, apiDomain string) (bool, error) {
+	modified := false
+
+	zone, err := a.azureClientHub.GetPrivateZone(context.TODO(), a.config.ResourceGroupName, apiDomain)
+	if isNotFound(err) {
+		zone, err = a.azureClientHub.CreateOrUpdatePrivateZone(context.TODO(), a.config.ResourceGroupName, apiDomain)
+		if err != nil {
+			return false, errors.Wrap(err, "error creating the Private DNS Zone")
+		}
+		modified = true
+	} else if err != nil {
+		return false, err
+	}
+
+	initPrivateLinkStatus(cd)
+	if zoneID := to.String(zone.ID); cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone != zoneID {
+		cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone = zoneID
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return false, errors.Wrap(err, "error updating clusterdeployment status with PrivateDNSZone")
+		}
+		modified = true
+	}
+
+	return modified, nil
+}
+
+// reconcilePrivateDNSZoneRecords points the apex of the private DNS zone at the private endpoint.
+func (a *AzureHubActuator) reconcilePrivateDNSZoneRecords(apiDomain string, dnsRecord *actuator.DnsRecord) error {
+	if len(dnsRecord.IpAddress) == 0 {
+		return errors.New("no IP address for the API domain")
+	}
+	aRecords := make([]privatedns.ARecord, 0, len(dnsRecord.IpAddress))
+	for _, ip := range dnsRecord.IpAddress {
+		aRecords = append(aRecords, privatedns.ARecord{Ipv4Address: to.StringPtr(ip)})
+	}
+	_, err := a.azureClientHub.CreateOrUpdatePrivateRecordSet(context.TODO(), a.config.ResourceGroupName, apiDomain, "@", privatedns.A, privatedns.RecordSet{
+		RecordSetProperties: &privatedns.RecordSetProperties{
+			TTL:      to.Int64Ptr(10),
+			ARecords: &aRecords,
+		},
+	})
+	return err
+}
+
+// reconcileVirtualNetworkLinks links the private DNS zone to the associated virtual networks and to the virtual
+// network of the private endpoint, and removes links to any other virtual network.
+func (a *AzureHubActuator) reconcileVirtualNetworkLinks(metadata *hivev1.ClusterMetadata, apiDomain string, logger log.FieldLogger) (bool, error) {
+	desired := sets.New[string]()
+	for _, vnet := range a.config.AssociatedVirtualNetworks {
+		desired.Insert(strings.ToLower(vnet))
+	}
+	endpointVNet, err := a.endpointVirtualNetwork(metadata)
+	if err != nil {
+		return false, err
+	}
+	desired.Insert(strings.ToLower(endpointVNet))
+
+	links, err := a.listVirtualNetworkLinks(apiDomain)
+	if err != nil {
+		return false, err
+	}
+
+	modified := false
+	current := sets.New[string]()
+	for _, link := range links {
+		vnet := ""
+		if link.VirtualNetworkLinkProperties != nil && link.VirtualNetworkLinkProperties.VirtualNetwork != nil {
+			vnet = strings.ToLower(to.String(link.VirtualNetworkLinkProperties.VirtualNetwork.ID))
+		}
+		if desired.Has(vnet) {
+			current.Insert(vnet)
+			continue
+		}
+		logger.WithField("virtualNetwork", vnet).Debug("removing Private DNS Zone virtual network link")
+		if err := a.azureClientHub.DeleteVirtualNetworkLink(context.TODO(), a.config.ResourceGroupName, apiDomain, to.String(link.Name)); err != nil && !isNotFound(err) {
+			return false, errors.Wrapf(err, "error deleting virtual network link %s", to.String(link.Name))
+		}
+		modified = true
+	}
+
+	for _, vnet := range sets.List(desired.Difference(current)) {
+		logger.WithField("virtualNetwork", vnet).Debug("adding Private DNS Zone virtual network link")
+		if _, err := a.azureClientHub.CreateOrUpdateVirtualNetworkLink(context.TODO(), a.config.ResourceGroupName, apiDomain, virtualNetworkLinkName(vnet), vnet); err != nil {
+			return false, errors.Wrapf(err, "error linking virtual network %s", vnet)
+		}
+		modified = true
+	}
+
+	return modified, nil
+}
+
+// endpointVirtualNetwork returns the ID of the virtual network of the private endpoint of the cluster.
+func (a *AzureHubActuator) endpointVirtualNetwork(metadata *hivev1.ClusterMetadata) (string, error) {
+	endpoint, err := a.azureClientHub.GetResourceByID(context.TODO(), privateEndpointID(a.azureClientHub, a.config, metadata), networkAPIVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "error getting the Private Endpoint")
+	}
+	properties := &privateEndpointProperties{}
+	if err := convertProperties(endpoint.Properties, properties); err != nil {
+		return "", errors.Wrap(err, "error reading the Private Endpoint")
+	}
+	if properties.Subnet == nil {
+		return "", errors.New("the Private Endpoint has no subnet")
+	}
+	return virtualNetworkFromSubnetID(properties.Subnet.ID)
+}
+
+func (a *AzureHubActuator) listVirtualNetworkLinks(apiDomain string) ([]privatedns.VirtualNetworkLink, error) {
+	var links []privatedns.VirtualNetworkLink
+	page, err := a.azureClientHub.ListVirtualNetworkLinks(context.TODO(), a.config.ResourceGroupName, apiDomain)
+	if err != nil {
+		return nil, err
+	}
+	for page.NotDone() {
+		links = append(links, page.Values()...)
+		if err := page.NextWithContext(context.TODO()); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// cleanupPrivateDNSZone deletes the virtual network links of the private DNS zone, and then the zone itself.
+func (a *AzureHubActuator) cleanupPrivateDNSZone(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, logger log.FieldLogger) error {
+	logger.Debug("cleaning up Private DNS Zone")
+
+	var apiDomain string
+	if cd.Status.Platform != nil &&
+		cd.Status.Platform.Azure != nil &&
+		cd.Status.Platform.Azure.PrivateLink != nil &&
+		cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone != "" {
+		apiDomain = resourceName(cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone)
+	} else {
+		domain, err := initialURL(*a.client,
+			client.ObjectKey{Namespace: cd.Namespace, Name: metadata.AdminKubeconfigSecretRef.Name})
+		if err != nil {
+			return errors.Wrap(err, "could not get API URL from kubeconfig")
+		}
+		apiDomain = domain
+	}
+
+	links, err := a.listVirtualNetworkLinks(apiDomain)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "error listing the Private DNS Zone virtual network links")
+	}
+	for _, link := range links {
+		if err := a.azureClientHub.DeleteVirtualNetworkLink(context.TODO(), a.config.ResourceGroupName, apiDomain, to.String(link.Name)); err != nil && !isNotFound(err) {
+			return errors.Wrapf(err, "error deleting virtual network link %s", to.String(link.Name))
+		}
+	}
+
+	if err := a.azureClientHub.DeletePrivateZone(context.TODO(), a.config.ResourceGroupName, apiDomain); err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "error deleting the Private DNS Zone")
+	}
+
+	initPrivateLinkStatus(cd)
+	if cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone != "" {
+		cd.Status.Platform.Azure.PrivateLink.PrivateDNSZone = ""
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return errors.Wrap(err, "error updating clusterdeployment after cleanup of PrivateDNSZone")
+		}
+	}
+
+	return nil
+}
+
+// virtualNetworkLinkName returns the name of the link to the virtual network with the given ID. The checksum of
+// the ID keeps the names of virtual networks with the same name in different resource groups apart.
+func virtualNetworkLinkName(vnetID string) string {
+	return fmt.Sprintf("%s-%08x", resourceName(vnetID), crc32.ChecksumIEEE([]byte(vnetID)))
+}
@@ -0,0 +1,304 @@
+package azureactuator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/privatedns/mgmt/2018-09-01/privatedns"
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	"github.com/openshift/hive/pkg/azureclient/mock"
+	"github.com/openshift/hive/pkg/controller/privatelink/actuator"
+	testassert "github.com/openshift/hive/pkg/test/assert"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+)
+
+func Test_AzureHubActuator_Reconcile(t *testing.T) {
+	// mockEndpoint returns the private endpoint of the cluster, in the subnet of the inventory.
+	mockEndpoint := func(m *mock.MockClient) {
+		m.EXPECT().GetResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).Return(resources.GenericResource{
+			Properties: map[string]interface{}{
+				"subnet": map[string]interface{}{"id": testEndpointSubnet},
+			},
+		}, nil)
+	}
+	mockRecords := func(m *mock.MockClient) {
+		m.EXPECT().CreateOrUpdatePrivateRecordSet(gomock.Any(), testHubResourceGroup, testAPIDomain, "@", privatedns.A, privatedns.RecordSet{
+			RecordSetProperties: &privatedns.RecordSetProperties{
+				TTL:      to.Int64Ptr(10),
+				ARecords: &[]privatedns.ARecord{{Ipv4Address: to.StringPtr("10.0.0.4")}},
+			},
+		}).Return(privatedns.RecordSet{}, nil)
+	}
+	mockExistingZone := func(m *mock.MockClient) {
+		m.EXPECT().GetPrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).
+			Return(privatedns.PrivateZone{ID: to.StringPtr(testPrivateDNSZone)}, nil)
+	}
+
+	cases := []struct {
+		name string
+
+		existing         *hivev1.ClusterDeployment
+		dnsRecord        *actuator.DnsRecord
+		configureHub     func(*gomock.Controller, *mock.MockClient)
+		expectConditions []hivev1.ClusterDeploymentCondition
+		expectStatus     *hivev1azure.PrivateLinkStatus
+		err              string
+	}{{
+		name: "create private DNS zone",
+
+		existing:  testClusterDeployment(),
+		dnsRecord: &actuator.DnsRecord{IpAddress: []string{"10.0.0.4"}},
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().GetPrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(privatedns.PrivateZone{}, notFoundErr())
+			m.EXPECT().CreateOrUpdatePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(privatedns.PrivateZone{ID: to.StringPtr(testPrivateDNSZone)}, nil)
+			mockRecords(m)
+			mockEndpoint(m)
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl), nil)
+			for _, vnet := range []string{testAssociatedVNet, testEndpointVNet} {
+				vnet = strings.ToLower(vnet)
+				m.EXPECT().CreateOrUpdateVirtualNetworkLink(gomock.Any(), testHubResourceGroup, testAPIDomain, virtualNetworkLinkName(vnet), vnet).
+					Return(privatedns.VirtualNetworkLink{}, nil)
+			}
+		},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionFalse,
+			Reason: "ReconciledVirtualNetworkLinks",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+	}, {
+		name: "existing private DNS zone",
+
+		existing:  testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		dnsRecord: &actuator.DnsRecord{IpAddress: []string{"10.0.0.4"}},
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			mockExistingZone(m)
+			mockRecords(m)
+			mockEndpoint(m)
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl, testAssociatedVNet, testEndpointVNet), nil)
+		},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionUnknown,
+			Reason: "Initialized",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+	}, {
+		name: "remove link to virtual network no longer associated",
+
+		existing:  testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		dnsRecord: &actuator.DnsRecord{IpAddress: []string{"10.0.0.4"}},
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			staleVNet := "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/old"
+			mockExistingZone(m)
+			mockRecords(m)
+			mockEndpoint(m)
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl, testAssociatedVNet, testEndpointVNet, staleVNet), nil)
+			m.EXPECT().DeleteVirtualNetworkLink(gomock.Any(), testHubResourceGroup, testAPIDomain, virtualNetworkLinkName(strings.ToLower(staleVNet))).
+				Return(nil)
+		},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionFalse,
+			Reason: "ReconciledVirtualNetworkLinks",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+	}, {
+		name: "failed to create private DNS zone",
+
+		existing:  testClusterDeployment(),
+		dnsRecord: &actuator.DnsRecord{IpAddress: []string{"10.0.0.4"}},
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().GetPrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(privatedns.PrivateZone{}, notFoundErr())
+			m.EXPECT().CreateOrUpdatePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(privatedns.PrivateZone{}, errors.New("quota exceeded"))
+		},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:    hivev1.PrivateLinkFailedClusterDeploymentCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  "PrivateDNSZoneReconcileFailed",
+			Message: "error creating the Private DNS Zone: quota exceeded",
+		}},
+		err: "failed to reconcile the Private DNS Zone: error creating the Private DNS Zone: quota exceeded",
+	}, {
+		name: "no IP address from link actuator",
+
+		existing:  testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		dnsRecord: &actuator.DnsRecord{},
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			mockExistingZone(m)
+		},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkFailedClusterDeploymentCondition,
+			Status: corev1.ConditionTrue,
+			Reason: "PrivateDNSZoneRecordsReconcileFailed",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+		err:          "failed to reconcile the Private DNS Zone records: no IP address for the API domain",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			hub, spoke := newMockClients(mockCtrl)
+			if test.configureHub != nil {
+				test.configureHub(mockCtrl, hub)
+			}
+
+			fakeClient := testfake.NewFakeClientBuilder().
+				WithObjects(append(testSecrets(), test.existing)...).
+				WithStatusSubresource(test.existing).
+				Build()
+			c := client.Client(fakeClient)
+			hubActuator, err := NewAzureHubActuator(&c, testConfig(), mockClientFn(hub, spoke), log.StandardLogger())
+			require.NoError(t, err, "unexpected error creating the hub actuator")
+
+			_, err = hubActuator.Reconcile(test.existing, testMetadata(), test.dnsRecord, log.StandardLogger())
+			if test.err == "" {
+				assert.NoError(t, err, "unexpected error from Reconcile")
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+
+			cd := getClusterDeployment(t, fakeClient)
+			testassert.AssertConditions(t, cd, test.expectConditions)
+			assert.Equal(t, test.expectStatus, privateLinkStatus(cd), "unexpected private link status")
+		})
+	}
+}
+
+func Test_AzureHubActuator_Cleanup(t *testing.T) {
+	cases := []struct {
+		name string
+
+		existing     *hivev1.ClusterDeployment
+		configureHub func(*gomock.Controller, *mock.MockClient)
+		expectStatus *hivev1azure.PrivateLinkStatus
+		err          string
+	}{{
+		name: "delete links and private DNS zone",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl, testAssociatedVNet), nil)
+			m.EXPECT().DeleteVirtualNetworkLink(gomock.Any(), testHubResourceGroup, testAPIDomain, virtualNetworkLinkName(strings.ToLower(testAssociatedVNet))).
+				Return(nil)
+			m.EXPECT().DeletePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).Return(nil)
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{},
+	}, {
+		name: "private DNS zone already deleted",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(nil, notFoundErr())
+			m.EXPECT().DeletePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).Return(notFoundErr())
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{},
+	}, {
+		name: "zone from kubeconfig when not in status",
+
+		existing: testClusterDeployment(),
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl), nil)
+			m.EXPECT().DeletePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).Return(nil)
+		},
+		expectStatus: nil,
+	}, {
+		name: "failed to delete private DNS zone",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		configureHub: func(ctrl *gomock.Controller, m *mock.MockClient) {
+			m.EXPECT().ListVirtualNetworkLinks(gomock.Any(), testHubResourceGroup, testAPIDomain).
+				Return(mockVirtualNetworkLinkPage(ctrl), nil)
+			m.EXPECT().DeletePrivateZone(gomock.Any(), testHubResourceGroup, testAPIDomain).Return(errors.New("zone locked"))
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+		err:          "error cleaning up private DNS zone: error deleting the Private DNS Zone: zone locked",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			hub, spoke := newMockClients(mockCtrl)
+			test.configureHub(mockCtrl, hub)
+
+			fakeClient := testfake.NewFakeClientBuilder().
+				WithObjects(append(testSecrets(), test.existing)...).
+				WithStatusSubresource(test.existing).
+				Build()
+			c := client.Client(fakeClient)
+			hubActuator, err := NewAzureHubActuator(&c, testConfig(), mockClientFn(hub, spoke), log.StandardLogger())
+			require.NoError(t, err, "unexpected error creating the hub actuator")
+
+			err = hubActuator.Cleanup(test.existing, testMetadata(), log.StandardLogger())
+			if test.err == "" {
+				assert.NoError(t, err, "unexpected error from Cleanup")
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+
+			assert.Equal(t, test.expectStatus, privateLinkStatus(getClusterDeployment(t, fakeClient)), "unexpected private link status")
+		})
+	}
+}
+
+func Test_AzureHubActuator_CleanupRequired(t *testing.T) {
+	cases := []struct {
+		name     string
+		cd       *hivev1.ClusterDeployment
+		expected bool
+	}{{
+		name: "no status",
+		cd:   testClusterDeployment(),
+	}, {
+		name:     "private DNS zone",
+		cd:       testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+		expected: true,
+	}, {
+		name: "only link resources",
+		cd:   testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateEndpoint: testPrivateEndpoint})),
+	}, {
+		name: "deleted with preserveOnDelete",
+		cd: testClusterDeployment(
+			withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone}),
+			func(cd *hivev1.ClusterDeployment) {
+				now := metav1.Now()
+				cd.Spec.PreserveOnDelete = true
+				cd.DeletionTimestamp = &now
+			},
+		),
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, (&AzureHubActuator{}).CleanupRequired(test.cd))
+		})
+	}
+}
+
+func Test_AzureHubActuator_ShouldSync(t *testing.T) {
+	a := &AzureHubActuator{}
+	assert.True(t, a.ShouldSync(testClusterDeployment()), "expected sync without status")
+	assert.False(t, a.ShouldSync(testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone}))),
+		"expected no sync with private DNS zone")
+}
@@ -0,0 +1,465 @@
+package azureactuator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/controller/privatelink/actuator"
+	"github.com/openshift/hive/pkg/controller/privatelink/conditions"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// Ensure AzureLinkActuator implements the Actuator interface. This will fail at compile time when false.
+var _ actuator.Actuator = &AzureLinkActuator{}
+
+// AzureLinkActuator creates a private link service for the internal API load balancer of the cluster, and a
+// private endpoint for it in the hub subscription.
+type AzureLinkActuator struct {
+	client *client.Client
+
+	config *hivev1.AzurePrivateLinkConfig
+
+	azureClientHub   azureclient.Client
+	azureClientSpoke azureclient.Client
+}
+
+type subResource struct {
+	ID string `json:"id,omitempty"`
+}
+
+type loadBalancerProperties struct {
+	FrontendIPConfigurations []struct {
+		ID         string `json:"id"`
+		Properties struct {
+			Subnet *subResource `json:"subnet,omitempty"`
+		} `json:"properties"`
+	} `json:"frontendIPConfigurations"`
+}
+
+type subnetProperties struct {
+	IPConfigurations []subResource `json:"ipConfigurations,omitempty"`
+}
+
+type privateEndpointProperties struct {
+	Subnet                        *subResource  `json:"subnet,omitempty"`
+	NetworkInterfaces             []subResource `json:"networkInterfaces,omitempty"`
+	PrivateLinkServiceConnections []struct {
+		Properties struct {
+			PrivateLinkServiceConnectionState struct {
+				Status string `json:"status"`
+			} `json:"privateLinkServiceConnectionState"`
+		} `json:"properties"`
+	} `json:"privateLinkServiceConnections,omitempty"`
+}
+
+type networkInterfaceProperties struct {
+	IPConfigurations []struct {
+		Properties struct {
+			PrivateIPAddress string `json:"privateIPAddress"`
+		} `json:"properties"`
+	} `json:"ipConfigurations"`
+}
+
+func NewAzureLinkActuator(
+	client *client.Client,
+	config *hivev1.AzurePrivateLinkConfig,
+	cd *hivev1.ClusterDeployment,
+	azureClientFn azureClientFn,
+	logger log.FieldLogger) (*AzureLinkActuator, error) {
+
+	actuator := &AzureLinkActuator{
+		client: client,
+		config: config,
+	}
+
+	if config == nil {
+		return nil, errors.New("unable to create Azure actuator: config is empty")
+	}
+
+	if cd == nil || cd.Spec.Platform.Azure == nil {
+		return nil, errors.New("unable to create Azure actuator: cluster deployment spec does not contain Azure platform")
+	}
+
+	hubClient, err := newAzureClient(*client, azureClientFn, config.CredentialsSecretRef.Name, controllerutils.GetHiveNamespace(), config.CloudName.Name())
+	if err != nil {
+		return nil, err
+	}
+	actuator.azureClientHub = hubClient
+
+	spokeClient, err := newAzureClient(*client, azureClientFn, cd.Spec.Platform.Azure.CredentialsSecretRef.Name, cd.Namespace, cd.Spec.Platform.Azure.CloudName.Name())
+	if err != nil {
+		return nil, err
+	}
+	actuator.azureClientSpoke = spokeClient
+
+	return actuator, nil
+}
+
+// Cleanup is the actuator interface for cleaning up the cloud resources.
+func (a *AzureLinkActuator) Cleanup(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, logger log.FieldLogger) error {
+	if err := a.cleanupPrivateEndpoint(cd, metadata, logger); err != nil {
+		return errors.Wrap(err, "error cleaning up private endpoint")
+	}
+
+	if err := a.cleanupPrivateLinkService(cd, metadata, logger); err != nil {
+		return errors.Wrap(err, "error cleaning up private link service")
+	}
+
+	return nil
+}
+
+// CleanupRequired is the actuator interface for determining if cleanup is required.
+func (a *AzureLinkActuator) CleanupRequired(cd *hivev1.ClusterDeployment) bool {
+	// There is nothing to do when PrivateLink is undefined.
+	// This either means it was never enabled, or it was already cleaned up.
+	if cd.Status.Platform == nil ||
+		cd.Status.Platform.Azure == nil ||
+		cd.Status.Platform.Azure.PrivateLink == nil {
+		return false
+	}
+
+	// There is nothing to do when deleting a ClusterDeployment with PreserveOnDelete and PrivateLink enabled.
+	// The private link resources are left behind along with the rest of the cluster's cloud resources.
+	if cd.DeletionTimestamp != nil &&
+		cd.Spec.PreserveOnDelete &&
+		cd.Spec.Platform.Azure.PrivateLink != nil &&
+		cd.Spec.Platform.Azure.PrivateLink.Enabled {
+		return false
+	}
+
+	return cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint != "" ||
+		cd.Status.Platform.Azure.PrivateLink.PrivateLinkService != ""
+}
+
+// Reconcile is the actuator interface for reconciling the cloud resources.
+func (a *AzureLinkActuator) Reconcile(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, dnsRecord *actuator.DnsRecord, logger log.FieldLogger) (reconcile.Result, error) {
+	logger.Debug("reconciling link resources")
+
+	lbID := networkResourceID(a.azureClientSpoke.SubscriptionID(), clusterResourceGroup(metadata), "loadBalancers", metadata.InfraID+"-internal")
+	lb, err := a.azureClientSpoke.GetResourceByID(context.TODO(), lbID, networkAPIVersion)
+	if isNotFound(err) {
+		logger.Debug("waiting for cluster internal load balancer to be provisioned, will retry soon.")
+		return requeueLater, nil
+	}
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to find the cluster internal load balancer")
+	}
+	lbProperties := &loadBalancerProperties{}
+	if err := convertProperties(lb.Properties, lbProperties); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to read the cluster internal load balancer")
+	}
+	var frontendIPConfigID, subnetID string
+	for _, fip := range lbProperties.FrontendIPConfigurations {
+		if fip.Properties.Subnet != nil && fip.Properties.Subnet.ID != "" {
+			frontendIPConfigID, subnetID = fip.ID, fip.Properties.Subnet.ID
+			break
+		}
+	}
+	if frontendIPConfigID == "" {
+		logger.Debug("waiting for cluster internal load balancer frontend to be provisioned, will retry soon.")
+		return requeueLater, nil
+	}
+
+	logger.Debug("reconciling Private Link Service Subnet")
+	if err := a.ensureSubnetNetworkPolicies(subnetID); err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private Link Service Subnet")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "PrivateLinkServiceSubnetReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private Link Service Subnet")
+	}
+
+	logger.Debug("reconciling Private Link Service")
+	serviceModified, serviceID, err := a.ensurePrivateLinkService(cd, metadata, frontendIPConfigID, subnetID)
+	if err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private Link Service")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "PrivateLinkServiceReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private Link Service")
+	}
+	if serviceModified {
+		err := conditions.SetReadyConditionWithRetry(*a.client, cd, corev1.ConditionFalse,
+			"ReconciledPrivateLinkService",
+			"reconciled the Private Link Service",
+			logger)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+	}
+
+	logger.Debug("reconciling Private Endpoint")
+	endpointModified, endpoint, err := a.ensurePrivateEndpoint(cd, metadata, serviceID)
+	if err != nil {
+		logger.WithError(err).Error("failed to reconcile the Private Endpoint")
+
+		if err := conditions.SetErrConditionWithRetry(*a.client, cd, "PrivateEndpointReconcileFailed", errors.New(controllerutils.ErrorScrub(err)), logger); err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+		return reconcile.Result{}, errors.Wrap(err, "failed to reconcile the Private Endpoint")
+	}
+	if endpointModified {
+		err := conditions.SetReadyConditionWithRetry(*a.client, cd, corev1.ConditionFalse,
+			"ReconciledPrivateEndpoint",
+			"reconciled the Private Endpoint",
+			logger)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to update condition on cluster deployment")
+		}
+	}
+
+	if len(endpoint.PrivateLinkServiceConnections) == 0 ||
+		endpoint.PrivateLinkServiceConnections[0].Properties.PrivateLinkServiceConnectionState.Status != "Approved" {
+		logger.Debug("waiting for the Private Endpoint connection to be approved, will retry soon.")
+		return requeueLater, nil
+	}
+
+	ipAddress, err := a.privateEndpointIPAddress(endpoint)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to get the Private Endpoint IP address")
+	}
+	if ipAddress == "" {
+		logger.Debug("waiting for the Private Endpoint IP address to be assigned, will retry soon.")
+		return requeueLater, nil
+	}
+
+	// Set the DNS IP Addresses for the hub actuator.
+	dnsRecord.IpAddress = []string{ipAddress}
+
+	return reconcile.Result{}, nil
+}
+
+// ShouldSync is the actuator interface to determine if there are changes that need to be made.
+func (a *AzureLinkActuator) ShouldSync(cd *hivev1.ClusterDeployment) bool {
+	return cd.Status.Platform == nil ||
+		cd.Status.Platform.Azure == nil ||
+		cd.Status.Platform.Azure.PrivateLink == nil ||
+		cd.Status.Platform.Azure.PrivateLink.PrivateLinkService == "" ||
+		cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint == ""
+}
+
+// ensureSubnetNetworkPolicies disables the private link service network policies on the subnet of the cluster
+// internal load balancer, which Azure requires before a private link service can use the subnet.
+func (a *AzureLinkActuator) ensureSubnetNetworkPolicies(subnetID string) error {
+	subnet, err := a.azureClientSpoke.GetResourceByID(context.TODO(), subnetID, networkAPIVersion)
+	if err != nil {
+		return errors.Wrap(err, "error getting the Private Link Service Subnet")
+	}
+	properties, ok := subnet.Properties.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("unexpected properties for subnet %s", subnetID)
+	}
+	if properties["privateLinkServiceNetworkPolicies"] == "Disabled" {
+		return nil
+	}
+
+	properties["privateLinkServiceNetworkPolicies"] = "Disabled"
+	if _, err := a.azureClientSpoke.CreateOrUpdateResourceByID(context.TODO(), subnetID, networkAPIVersion, resources.GenericResource{
+		Properties: properties,
+	}); err != nil {
+		return errors.Wrap(err, "error disabling the private link service network policies of the subnet")
+	}
+	return nil
+}
+
+// ensurePrivateLinkService creates the private link service if it does not already exist.
+func (a *AzureLinkActuator) ensurePrivateLinkService(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, frontendIPConfigID string, subnetID string) (bool, string, error) {
+	modified := false
+
+	serviceName := metadata.InfraID + "-pls"
+	serviceID := networkResourceID(a.azureClientSpoke.SubscriptionID(), clusterResourceGroup(metadata), "privateLinkServices", serviceName)
+	_, err := a.azureClientSpoke.GetResourceByID(context.TODO(), serviceID, networkAPIVersion)
+	if isNotFound(err) {
+		hubSubscription := []string{a.azureClientHub.SubscriptionID()}
+		_, err := a.azureClientSpoke.CreateOrUpdateResourceByID(context.TODO(), serviceID, networkAPIVersion, resources.GenericResource{
+			Location: to.StringPtr(cd.Spec.Platform.Azure.Region),
+			Properties: map[string]interface{}{
+				"loadBalancerFrontendIpConfigurations": []subResource{{ID: frontendIPConfigID}},
+				"ipConfigurations": []interface{}{
+					map[string]interface{}{
+						"name": serviceName,
+						"properties": map[string]interface{}{
+							"subnet":                    subResource{ID: subnetID},
+							"privateIPAllocationMethod": "Dynamic",
+							"primary":                   true,
+						},
+					},
+				},
+				"visibility":   map[string]interface{}{"subscriptions": hubSubscription},
+				"autoApproval": map[string]interface{}{"subscriptions": hubSubscription},
+			},
+		})
+		if err != nil {
+			return false, "", errors.Wrap(err, "error creating the Private Link Service")
+		}
+		modified = true
+	} else if err != nil {
+		return false, "", err
+	}
+
+	initPrivateLinkStatus(cd)
+	if cd.Status.Platform.Azure.PrivateLink.PrivateLinkService != serviceID {
+		cd.Status.Platform.Azure.PrivateLink.PrivateLinkService = serviceID
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return false, "", errors.Wrap(err, "error updating clusterdeployment status with PrivateLinkService")
+		}
+		modified = true
+	}
+
+	return modified, serviceID, nil
+}
+
+// cleanupPrivateLinkService deletes the private link service.
+func (a *AzureLinkActuator) cleanupPrivateLinkService(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, logger log.FieldLogger) error {
+	logger.Debug("cleaning up Private Link Service")
+
+	serviceID := networkResourceID(a.azureClientSpoke.SubscriptionID(), clusterResourceGroup(metadata), "privateLinkServices", metadata.InfraID+"-pls")
+	err := a.azureClientSpoke.DeleteResourceByID(context.TODO(), serviceID, networkAPIVersion)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "error deleting the Private Link Service")
+	}
+
+	initPrivateLinkStatus(cd)
+	if cd.Status.Platform.Azure.PrivateLink.PrivateLinkService != "" {
+		cd.Status.Platform.Azure.PrivateLink.PrivateLinkService = ""
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return errors.Wrap(err, "error updating clusterdeployment after cleanup of PrivateLinkService")
+		}
+	}
+
+	return nil
+}
+
+// ensurePrivateEndpoint creates the private endpoint in the hub if it does not already exist.
+func (a *AzureLinkActuator) ensurePrivateEndpoint(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, serviceID string) (bool, *privateEndpointProperties, error) {
+	modified := false
+
+	endpointID := privateEndpointID(a.azureClientHub, a.config, metadata)
+	endpoint, err := a.azureClientHub.GetResourceByID(context.TODO(), endpointID, networkAPIVersion)
+	if isNotFound(err) {
+		subnetID, err := chooseSubnetForEndpoint(a.azureClientHub, *a.config, cd.Spec.Platform.Azure.Region)
+		if err != nil {
+			return false, nil, errors.Wrap(err, "error choosing a Subnet for the Private Endpoint")
+		}
+		endpoint, err = a.azureClientHub.CreateOrUpdateResourceByID(context.TODO(), endpointID, networkAPIVersion, resources.GenericResource{
+			Location: to.StringPtr(cd.Spec.Platform.Azure.Region),
+			Properties: map[string]interface{}{
+				"subnet": subResource{ID: subnetID},
+				"privateLinkServiceConnections": []interface{}{
+					map[string]interface{}{
+						"name": resourceName(serviceID),
+						"properties": map[string]interface{}{
+							"privateLinkServiceId": serviceID,
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return false, nil, errors.Wrap(err, "error creating the Private Endpoint")
+		}
+		modified = true
+	} else if err != nil {
+		return false, nil, err
+	}
+
+	properties := &privateEndpointProperties{}
+	if err := convertProperties(endpoint.Properties, properties); err != nil {
+		return false, nil, errors.Wrap(err, "error reading the Private Endpoint")
+	}
+
+	initPrivateLinkStatus(cd)
+	if cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint != endpointID {
+		cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint = endpointID
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return false, nil, errors.Wrap(err, "error updating clusterdeployment status with PrivateEndpoint")
+		}
+		modified = true
+	}
+
+	return modified, properties, nil
+}
+
+// cleanupPrivateEndpoint deletes the private endpoint.
+func (a *AzureLinkActuator) cleanupPrivateEndpoint(cd *hivev1.ClusterDeployment, metadata *hivev1.ClusterMetadata, logger log.FieldLogger) error {
+	logger.Debug("cleaning up Private Endpoint")
+
+	err := a.azureClientHub.DeleteResourceByID(context.TODO(), privateEndpointID(a.azureClientHub, a.config, metadata), networkAPIVersion)
+	if err != nil && !isNotFound(err) {
+		return errors.Wrap(err, "error deleting the Private Endpoint")
+	}
+
+	initPrivateLinkStatus(cd)
+	if cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint != "" {
+		cd.Status.Platform.Azure.PrivateLink.PrivateEndpoint = ""
+		if err := updatePrivateLinkStatus(a.client, cd); err != nil {
+			return errors.Wrap(err, "error updating clusterdeployment after cleanup of PrivateEndpoint")
+		}
+	}
+
+	return nil
+}
+
+// privateEndpointIPAddress returns the private IP address of the network interface of the private endpoint.
+func (a *AzureLinkActuator) privateEndpointIPAddress(endpoint *privateEndpointProperties) (string, error) {
+	if len(endpoint.NetworkInterfaces) == 0 {
+		return "", nil
+	}
+	nic, err := a.azureClientHub.GetResourceByID(context.TODO(), endpoint.NetworkInterfaces[0].ID, networkAPIVersion)
+	if err != nil {
+		return "", err
+	}
+	properties := &networkInterfaceProperties{}
+	if err := convertProperties(nic.Properties, properties); err != nil {
+		return "", err
+	}
+	if len(properties.IPConfigurations) == 0 {
+		return "", nil
+	}
+	return properties.IPConfigurations[0].Properties.PrivateIPAddress, nil
+}
+
+// privateEndpointID returns the ID of the private endpoint of the cluster in the hub.
+func privateEndpointID(hubClient azureclient.Client, config *hivev1.AzurePrivateLinkConfig, metadata *hivev1.ClusterMetadata) string {
+	return networkResourceID(hubClient.SubscriptionID(), config.ResourceGroupName, "privateEndpoints", metadata.InfraID+"-pe")
+}
+
+// chooseSubnetForEndpoint returns the subnet in the cluster region with the fewest IP configurations, spreading
+// the private endpoints across the subnets of the inventory.
+func chooseSubnetForEndpoint(azureClient azureclient.Client, config hivev1.AzurePrivateLinkConfig, region string) (string, error) {
+	chosen, fewest := "", -1
+	for _, candidate := range config.EndpointSubnetInventory {
+		if !strings.EqualFold(candidate.Region, region) {
+			continue
+		}
+		subnet, err := azureClient.GetResourceByID(context.TODO(), candidate.Subnet, networkAPIVersion)
+		if err != nil {
+			return "", errors.Wrapf(err, "error getting subnet %s", candidate.Subnet)
+		}
+		properties := &subnetProperties{}
+		if err := convertProperties(subnet.Properties, properties); err != nil {
+			return "", errors.Wrapf(err, "error reading subnet %s", candidate.Subnet)
+		}
+		if fewest < 0 || len(properties.IPConfigurations) < fewest {
+			chosen, fewest = candidate.Subnet, len(properties.IPConfigurations)
+		}
+	}
+	if chosen == "" {
+		return "", fmt.Errorf("no supported subnet in inventory for region %s", region)
+	}
+	return chosen, nil
+}
@@ -0,0 +1,387 @@
+package azureactuator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hivev1azure "github.com/openshift/hive/apis/hive/v1/azure"
+	"github.com/openshift/hive/pkg/azureclient/mock"
+	"github.com/openshift/hive/pkg/controller/privatelink/actuator"
+	testassert "github.com/openshift/hive/pkg/test/assert"
+	testfake "github.com/openshift/hive/pkg/test/fake"
+)
+
+var (
+	testLoadBalancer     = networkResourceID(testSpokeSubscription, testInfraID+"-rg", "loadBalancers", testInfraID+"-internal")
+	testFrontendIPConfig = testLoadBalancer + "/frontendIPConfigurations/internal-lb-ip-v4"
+	testClusterSubnet    = "/subscriptions/spoke-sub/resourceGroups/test-infra-rg/providers/Microsoft.Network/virtualNetworks/test-infra-vnet/subnets/test-infra-master-subnet"
+	testEndpointNIC      = "/subscriptions/hub-sub/resourceGroups/hub-rg/providers/Microsoft.Network/networkInterfaces/test-infra-pe-nic"
+)
+
+// mockEndpointResource returns a private endpoint with a connection in the given state and its network interface.
+func mockEndpointResource(status string) resources.GenericResource {
+	return resources.GenericResource{
+		Properties: map[string]interface{}{
+			"subnet":            map[string]interface{}{"id": testEndpointSubnet},
+			"networkInterfaces": []interface{}{map[string]interface{}{"id": testEndpointNIC}},
+			"privateLinkServiceConnections": []interface{}{map[string]interface{}{
+				"properties": map[string]interface{}{
+					"privateLinkServiceConnectionState": map[string]interface{}{"status": status},
+				},
+			}},
+		},
+	}
+}
+
+func Test_AzureLinkActuator_Reconcile(t *testing.T) {
+	// mockLoadBalancer returns the cluster internal load balancer with its frontend in the cluster subnet, and the
+	// subnet with the given private link service network policies.
+	mockLoadBalancer := func(m *mock.MockClient, networkPolicies string) {
+		m.EXPECT().GetResourceByID(gomock.Any(), testLoadBalancer, networkAPIVersion).Return(resources.GenericResource{
+			Properties: map[string]interface{}{
+				"frontendIPConfigurations": []interface{}{map[string]interface{}{
+					"id": testFrontendIPConfig,
+					"properties": map[string]interface{}{
+						"subnet": map[string]interface{}{"id": testClusterSubnet},
+					},
+				}},
+			},
+		}, nil)
+		m.EXPECT().GetResourceByID(gomock.Any(), testClusterSubnet, networkAPIVersion).Return(resources.GenericResource{
+			Properties: map[string]interface{}{"privateLinkServiceNetworkPolicies": networkPolicies},
+		}, nil)
+	}
+	mockCreateService := func(m *mock.MockClient) {
+		m.EXPECT().CreateOrUpdateResourceByID(gomock.Any(), testClusterSubnet, networkAPIVersion, resources.GenericResource{
+			Properties: map[string]interface{}{"privateLinkServiceNetworkPolicies": "Disabled"},
+		}).Return(resources.GenericResource{}, nil)
+		m.EXPECT().GetResourceByID(gomock.Any(), testPrivateLinkSvc, networkAPIVersion).
+			Return(resources.GenericResource{}, notFoundErr())
+		m.EXPECT().CreateOrUpdateResourceByID(gomock.Any(), testPrivateLinkSvc, networkAPIVersion, gomock.Any()).
+			Return(resources.GenericResource{}, nil)
+	}
+	mockCreateEndpoint := func(m *mock.MockClient) {
+		m.EXPECT().GetResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).
+			Return(resources.GenericResource{}, notFoundErr())
+		m.EXPECT().GetResourceByID(gomock.Any(), testEndpointSubnet, networkAPIVersion).
+			Return(resources.GenericResource{Properties: map[string]interface{}{}}, nil)
+	}
+
+	cases := []struct {
+		name string
+
+		existing         *hivev1.ClusterDeployment
+		configureHub     func(*mock.MockClient)
+		configureSpoke   func(*mock.MockClient)
+		expectResult     reconcile.Result
+		expectDNSRecord  *actuator.DnsRecord
+		expectConditions []hivev1.ClusterDeploymentCondition
+		expectStatus     *hivev1azure.PrivateLinkStatus
+		err              string
+	}{{
+		name: "load balancer not provisioned",
+
+		existing: testClusterDeployment(),
+		configureSpoke: func(m *mock.MockClient) {
+			m.EXPECT().GetResourceByID(gomock.Any(), testLoadBalancer, networkAPIVersion).
+				Return(resources.GenericResource{}, notFoundErr())
+		},
+		expectResult:    requeueLater,
+		expectDNSRecord: &actuator.DnsRecord{},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionUnknown,
+			Reason: "Initialized",
+		}},
+	}, {
+		name: "create private link service and private endpoint",
+
+		existing: testClusterDeployment(),
+		configureSpoke: func(m *mock.MockClient) {
+			mockLoadBalancer(m, "Enabled")
+			mockCreateService(m)
+		},
+		configureHub: func(m *mock.MockClient) {
+			mockCreateEndpoint(m)
+			m.EXPECT().CreateOrUpdateResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion, gomock.Any()).
+				Return(mockEndpointResource("Pending"), nil)
+		},
+		expectResult:    requeueLater,
+		expectDNSRecord: &actuator.DnsRecord{},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionFalse,
+			Reason: "ReconciledPrivateEndpoint",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		},
+	}, {
+		name: "existing private link service and approved private endpoint",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		})),
+		configureSpoke: func(m *mock.MockClient) {
+			mockLoadBalancer(m, "Disabled")
+			m.EXPECT().GetResourceByID(gomock.Any(), testPrivateLinkSvc, networkAPIVersion).
+				Return(resources.GenericResource{}, nil)
+		},
+		configureHub: func(m *mock.MockClient) {
+			m.EXPECT().GetResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).
+				Return(mockEndpointResource("Approved"), nil)
+			m.EXPECT().GetResourceByID(gomock.Any(), testEndpointNIC, networkAPIVersion).Return(resources.GenericResource{
+				Properties: map[string]interface{}{
+					"ipConfigurations": []interface{}{map[string]interface{}{
+						"properties": map[string]interface{}{"privateIPAddress": "10.0.0.4"},
+					}},
+				},
+			}, nil)
+		},
+		expectDNSRecord: &actuator.DnsRecord{IpAddress: []string{"10.0.0.4"}},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:   hivev1.PrivateLinkReadyClusterDeploymentCondition,
+			Status: corev1.ConditionUnknown,
+			Reason: "Initialized",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		},
+	}, {
+		name: "failed to create private endpoint",
+
+		existing: testClusterDeployment(),
+		configureSpoke: func(m *mock.MockClient) {
+			mockLoadBalancer(m, "Enabled")
+			mockCreateService(m)
+		},
+		configureHub: func(m *mock.MockClient) {
+			mockCreateEndpoint(m)
+			m.EXPECT().CreateOrUpdateResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion, gomock.Any()).
+				Return(resources.GenericResource{}, errors.New("subnet full"))
+		},
+		expectDNSRecord: &actuator.DnsRecord{},
+		expectConditions: []hivev1.ClusterDeploymentCondition{{
+			Type:    hivev1.PrivateLinkFailedClusterDeploymentCondition,
+			Status:  corev1.ConditionTrue,
+			Reason:  "PrivateEndpointReconcileFailed",
+			Message: "error creating the Private Endpoint: subnet full",
+		}},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateLinkService: testPrivateLinkSvc},
+		err:          "failed to reconcile the Private Endpoint: error creating the Private Endpoint: subnet full",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			hub, spoke := newMockClients(mockCtrl)
+			if test.configureHub != nil {
+				test.configureHub(hub)
+			}
+			if test.configureSpoke != nil {
+				test.configureSpoke(spoke)
+			}
+
+			fakeClient := testfake.NewFakeClientBuilder().
+				WithObjects(append(testSecrets(), test.existing)...).
+				WithStatusSubresource(test.existing).
+				Build()
+			c := client.Client(fakeClient)
+			linkActuator, err := NewAzureLinkActuator(&c, testConfig(), test.existing, mockClientFn(hub, spoke), log.StandardLogger())
+			require.NoError(t, err, "unexpected error creating the link actuator")
+
+			dnsRecord := &actuator.DnsRecord{}
+			result, err := linkActuator.Reconcile(test.existing, testMetadata(), dnsRecord, log.StandardLogger())
+			if test.err == "" {
+				assert.NoError(t, err, "unexpected error from Reconcile")
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+			assert.Equal(t, test.expectResult, result, "unexpected reconcile result")
+			assert.Equal(t, test.expectDNSRecord, dnsRecord, "unexpected DNS record")
+
+			cd := getClusterDeployment(t, fakeClient)
+			testassert.AssertConditions(t, cd, test.expectConditions)
+			assert.Equal(t, test.expectStatus, privateLinkStatus(cd), "unexpected private link status")
+		})
+	}
+}
+
+func Test_AzureLinkActuator_Cleanup(t *testing.T) {
+	cases := []struct {
+		name string
+
+		existing       *hivev1.ClusterDeployment
+		configureHub   func(*mock.MockClient)
+		configureSpoke func(*mock.MockClient)
+		expectStatus   *hivev1azure.PrivateLinkStatus
+		err            string
+	}{{
+		name: "delete private endpoint and private link service",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+			PrivateDNSZone:     testPrivateDNSZone,
+		})),
+		configureHub: func(m *mock.MockClient) {
+			m.EXPECT().DeleteResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).Return(nil)
+		},
+		configureSpoke: func(m *mock.MockClient) {
+			m.EXPECT().DeleteResourceByID(gomock.Any(), testPrivateLinkSvc, networkAPIVersion).Return(nil)
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone},
+	}, {
+		name: "already deleted",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		})),
+		configureHub: func(m *mock.MockClient) {
+			m.EXPECT().DeleteResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).Return(notFoundErr())
+		},
+		configureSpoke: func(m *mock.MockClient) {
+			m.EXPECT().DeleteResourceByID(gomock.Any(), testPrivateLinkSvc, networkAPIVersion).Return(notFoundErr())
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{},
+	}, {
+		name: "failed to delete private endpoint",
+
+		existing: testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		})),
+		configureHub: func(m *mock.MockClient) {
+			m.EXPECT().DeleteResourceByID(gomock.Any(), testPrivateEndpoint, networkAPIVersion).Return(errors.New("endpoint locked"))
+		},
+		expectStatus: &hivev1azure.PrivateLinkStatus{
+			PrivateLinkService: testPrivateLinkSvc,
+			PrivateEndpoint:    testPrivateEndpoint,
+		},
+		err: "error cleaning up private endpoint: error deleting the Private Endpoint: endpoint locked",
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			hub, spoke := newMockClients(mockCtrl)
+			if test.configureHub != nil {
+				test.configureHub(hub)
+			}
+			if test.configureSpoke != nil {
+				test.configureSpoke(spoke)
+			}
+
+			fakeClient := testfake.NewFakeClientBuilder().
+				WithObjects(append(testSecrets(), test.existing)...).
+				WithStatusSubresource(test.existing).
+				Build()
+			c := client.Client(fakeClient)
+			linkActuator, err := NewAzureLinkActuator(&c, testConfig(), test.existing, mockClientFn(hub, spoke), log.StandardLogger())
+			require.NoError(t, err, "unexpected error creating the link actuator")
+
+			err = linkActuator.Cleanup(test.existing, testMetadata(), log.StandardLogger())
+			if test.err == "" {
+				assert.NoError(t, err, "unexpected error from Cleanup")
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+
+			assert.Equal(t, test.expectStatus, privateLinkStatus(getClusterDeployment(t, fakeClient)), "unexpected private link status")
+		})
+	}
+}
+
+func Test_AzureLinkActuator_CleanupRequired(t *testing.T) {
+	cases := []struct {
+		name     string
+		cd       *hivev1.ClusterDeployment
+		expected bool
+	}{{
+		name: "no status",
+		cd:   testClusterDeployment(),
+	}, {
+		name:     "private endpoint",
+		cd:       testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateEndpoint: testPrivateEndpoint})),
+		expected: true,
+	}, {
+		name:     "private link service",
+		cd:       testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateLinkService: testPrivateLinkSvc})),
+		expected: true,
+	}, {
+		name: "only hub resources",
+		cd:   testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateDNSZone: testPrivateDNSZone})),
+	}, {
+		name: "deleted with preserveOnDelete",
+		cd: testClusterDeployment(
+			withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateEndpoint: testPrivateEndpoint}),
+			func(cd *hivev1.ClusterDeployment) {
+				now := metav1.Now()
+				cd.Spec.PreserveOnDelete = true
+				cd.DeletionTimestamp = &now
+			},
+		),
+	}}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, (&AzureLinkActuator{}).CleanupRequired(test.cd))
+		})
+	}
+}
+
+func Test_AzureLinkActuator_ShouldSync(t *testing.T) {
+	a := &AzureLinkActuator{}
+	assert.True(t, a.ShouldSync(testClusterDeployment()), "expected sync without status")
+	assert.True(t, a.ShouldSync(testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{PrivateLinkService: testPrivateLinkSvc}))),
+		"expected sync without private endpoint")
+	assert.False(t, a.ShouldSync(testClusterDeployment(withPrivateLinkStatus(&hivev1azure.PrivateLinkStatus{
+		PrivateLinkService: testPrivateLinkSvc,
+		PrivateEndpoint:    testPrivateEndpoint,
+	}))), "expected no sync with private link service and private endpoint")
+}
+
+func Test_chooseSubnetForEndpoint(t *testing.T) {
+	busySubnet := "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/endpoints/subnets/endpoints-0"
+	otherRegionSubnet := "/subscriptions/hub-sub/resourceGroups/hub-network/providers/Microsoft.Network/virtualNetworks/endpoints/subnets/endpoints-west"
+
+	config := testConfig()
+	config.EndpointSubnetInventory = []hivev1.AzurePrivateLinkSubnet{
+		{Subnet: busySubnet, Region: testRegion},
+		{Subnet: otherRegionSubnet, Region: "westus"},
+		{Subnet: testEndpointSubnet, Region: "EastUS"},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	m := mock.NewMockClient(mockCtrl)
+	m.EXPECT().GetResourceByID(gomock.Any(), busySubnet, networkAPIVersion).Return(resources.GenericResource{
+		Properties: map[string]interface{}{
+			"ipConfigurations": []interface{}{map[string]interface{}{"id": "ip-1"}, map[string]interface{}{"id": "ip-2"}},
+		},
+	}, nil)
+	m.EXPECT().GetResourceByID(gomock.Any(), testEndpointSubnet, networkAPIVersion).Return(resources.GenericResource{
+		Properties: map[string]interface{}{
+			"ipConfigurations": []interface{}{map[string]interface{}{"id": "ip-3"}},
+		},
+	}, nil)
+
+	subnet, err := chooseSubnetForEndpoint(m, *config, testRegion)
+	require.NoError(t, err)
+	assert.Equal(t, testEndpointSubnet, subnet, "expected the subnet with the fewest IP configurations")
+
+	_, err = chooseSubnetForEndpoint(m, *config, "centralus")
+	assert.EqualError(t, err, "no supported subnet in inventory for region centralus")
+}
@@ -29,6 +29,7 @@ import (
 	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
 	"github.com/openshift/hive/pkg/controller/privatelink/actuator"
 	"github.com/openshift/hive/pkg/controller/privatelink/actuator/awsactuator"
+	"github.com/openshift/hive/pkg/controller/privatelink/actuator/azureactuator"
 	"github.com/openshift/hive/pkg/controller/privatelink/actuator/gcpactuator"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 )
@@ -141,6 +142,17 @@ func (r *PrivateLinkReconciler) Reconcile(ctx context.Context, request reconcile
 		if cd.Spec.Platform.GCP.PrivateServiceConnect != nil {
 			privateLinkEnabled = cd.Spec.Platform.GCP.PrivateServiceConnect.Enabled
 		}
+	case cd.Spec.Platform.Azure != nil:
+		spokePlatformType = configv1.AzurePlatformType
+		if cd.Spec.Platform.Azure.PrivateLink != nil {
+			privateLinkEnabled = cd.Spec.Platform.Azure.PrivateLink.Enabled
+		}
+		// Azure Private Link is opt-in, so don't require the hub configuration for clusters that
+		// have never used it.
+		if !privateLinkEnabled && (cd.Status.Platform == nil || cd.Status.Platform.Azure == nil || cd.Status.Platform.Azure.PrivateLink == nil) {
+			logger.Debug("cluster deployment does not have private link enabled, so skipping")
+			return reconcile.Result{}, nil
+		}
 	default:
 		logger.Debug("controller cannot service the clusterdeployment, so skipping")
 		return reconcile.Result{}, nil
@@ -151,10 +163,10 @@ func (r *PrivateLinkReconciler) Reconcile(ctx context.Context, request reconcile
 		return reconcile.Result{}, errors.Wrap(err, "could not get link actuator")
 	}
 
-	// TODO: Determine and use hub platform type
-	// Ideally we would get the hub platform type from the cluster infrastructure. However,
-	// the hive service account does not have access. For now, we hard-code aws platform.
-	var hubPlatformType = configv1.AWSPlatformType
+	hubPlatformType, err := hubPlatform(r.controllerconfig, spokePlatformType)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "could not get hub platform")
+	}
 
 	privateLink.hubActuator, err = CreateActuator(r.Client, hubPlatformType, actuator.ActuatorTypeHub, nil, r.controllerconfig, logger)
 	if err != nil {
@@ -164,6 +176,22 @@ func (r *PrivateLinkReconciler) Reconcile(ctx context.Context, request reconcile
 	return privateLink.Reconcile(privateLinkEnabled)
 }
 
+// hubPlatform returns the platform of the hub actuator for clusters of the given platform.
+// Ideally we would get the hub platform type from the cluster infrastructure. However,
+// the hive service account does not have access, so it is configured, defaulting to aws.
+func hubPlatform(config *hivev1.PrivateLinkConfig, spokePlatformType configv1.PlatformType) (configv1.PlatformType, error) {
+	if config == nil || config.HubPlatform == "" {
+		return configv1.AWSPlatformType, nil
+	}
+	platform := configv1.PlatformType(config.HubPlatform)
+	// The Azure hub links its private DNS zones to the virtual network of the private endpoint created by the
+	// Azure link actuator.
+	if platform == configv1.AzurePlatformType && spokePlatformType != configv1.AzurePlatformType {
+		return "", fmt.Errorf("the %s hub does not support %s clusters", platform, spokePlatformType)
+	}
+	return platform, nil
+}
+
 // CreateActuator creates an actuator based on the cloud platform and actuator type.
 func CreateActuator(
 	client client.Client,
@@ -191,6 +219,17 @@ func CreateActuator(
 		if actuatorType == actuator.ActuatorTypeLink {
 			return gcpactuator.NewGCPLinkActuator(&client, gcpConfig, cd, nil, logger)
 		}
+	case configv1.AzurePlatformType:
+		var azureConfig *hivev1.AzurePrivateLinkConfig
+		if config != nil {
+			azureConfig = config.Azure
+		}
+		switch actuatorType {
+		case actuator.ActuatorTypeHub:
+			return azureactuator.NewAzureHubActuator(&client, azureConfig, nil, logger)
+		case actuator.ActuatorTypeLink:
+			return azureactuator.NewAzureLinkActuator(&client, azureConfig, cd, nil, logger)
+		}
 	}
 	return nil, fmt.Errorf("unable to create privatelink actuator, invalid actuator type: %s/%s", platform, actuatorType)
 }
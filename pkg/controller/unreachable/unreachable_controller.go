@@ -21,6 +21,7 @@ package unreachable
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"time"
 
@@ -173,6 +174,23 @@ func (r *ReconcileRemoteMachineSet) Reconcile(ctx context.Context, request recon
 		return reconcile.Result{}, nil
 	}
 
+	// A cluster reached through the API server tunnel is unreachable as long as its agent is not connected. There is
+	// no need to requeue: the ClusterDeployment is synced again when the agent connects.
+	if cd.Spec.ControlPlaneConfig.APIServerTunnel {
+		cond := controllerutils.FindCondition(cd.Status.Conditions, hivev1.APIServerTunnelConnectedCondition)
+		if cond == nil || cond.Status != corev1.ConditionTrue {
+			if !remoteclient.SetUnreachableCondition(cd, errors.New("the API server tunnel agent is not connected")) {
+				return reconcile.Result{}, nil
+			}
+			cdLog.Info("API server tunnel agent is not connected, marking cluster unreachable")
+			if err := r.Status().Update(context.TODO(), cd); err != nil {
+				cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment with unreachable condition")
+				return reconcile.Result{}, err
+			}
+			return reconcile.Result{}, nil
+		}
+	}
+
 	// Check whether, prior to this reconciliation, the remote cluster was considered unreachable. Also, get the
 	// last time that the unreachable check was performed.
 	wasUnreachable, lastCheck := remoteclient.Unreachable(cd)
@@ -229,6 +229,30 @@ func TestReconcile(t *testing.T) {
 			expectedActiveOverrideStatus: corev1.ConditionFalse,
 			expectRequeue:                true,
 		},
+		{
+			name: "api server tunnel agent not connected",
+			cd: buildClusterDeployment(
+				withUnreachableCondition(corev1.ConditionFalse, time.Now()),
+				withActiveAPIURLOverrideCondition(corev1.ConditionUnknown),
+				withAPIServerTunnel(),
+				withAPIServerTunnelConnectedCondition(corev1.ConditionFalse),
+			),
+			expectedUnreachableStatus:    corev1.ConditionTrue,
+			expectedActiveOverrideStatus: corev1.ConditionUnknown,
+		},
+		{
+			name: "api server tunnel agent connected",
+			cd: buildClusterDeployment(
+				withUnreachableCondition(corev1.ConditionTrue, time.Now()),
+				withActiveAPIURLOverrideCondition(corev1.ConditionUnknown),
+				withAPIServerTunnel(),
+				withAPIServerTunnelConnectedCondition(corev1.ConditionTrue),
+			),
+			errorConnecting:              pointer.BoolPtr(false),
+			expectedUnreachableStatus:    corev1.ConditionFalse,
+			expectedActiveOverrideStatus: corev1.ConditionUnknown,
+			expectRequeueAfter:           true,
+		},
 	}
 
 	for _, test := range tests {
@@ -323,3 +347,18 @@ func withAPIURLOverride() testcd.Option {
 		clusterDeployment.Spec.ControlPlaneConfig.APIURLOverride = "some-api-url"
 	}
 }
+
+func withAPIServerTunnel() testcd.Option {
+	return func(clusterDeployment *hivev1.ClusterDeployment) {
+		clusterDeployment.Spec.ControlPlaneConfig.APIServerTunnel = true
+	}
+}
+
+func withAPIServerTunnelConnectedCondition(status corev1.ConditionStatus) testcd.Option {
+	return testcd.WithCondition(
+		hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.APIServerTunnelConnectedCondition,
+			Status: status,
+		},
+	)
+}
@@ -656,6 +656,54 @@ func SetClusterDeprovisionConditionWithChangeCheck(
 	return conditions, changed
 }
 
+// SetClusterUpgradePlanConditionWithChangeCheck sets a condition on a ClusterUpgradePlan resource's status
+// It returns the conditions as well a boolean indicating whether there was a change made
+// to the conditions.
+func SetClusterUpgradePlanConditionWithChangeCheck(
+	conditions []hivev1.ClusterUpgradePlanCondition,
+	conditionType hivev1.ClusterUpgradePlanConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+	updateConditionCheck UpdateConditionCheck,
+) ([]hivev1.ClusterUpgradePlanCondition, bool) {
+	changed := false
+	now := metav1.Now()
+	existingCondition := FindCondition(conditions, conditionType)
+	if existingCondition == nil {
+		if status == corev1.ConditionTrue {
+			conditions = append(
+				conditions,
+				hivev1.ClusterUpgradePlanCondition{
+					Type:               conditionType,
+					Status:             status,
+					Reason:             reason,
+					Message:            message,
+					LastTransitionTime: now,
+					LastProbeTime:      now,
+				},
+			)
+			changed = true
+		}
+	} else {
+		if shouldUpdateCondition(
+			existingCondition.Status, existingCondition.Reason, existingCondition.Message,
+			status, reason, message,
+			updateConditionCheck,
+		) {
+			if existingCondition.Status != status {
+				existingCondition.LastTransitionTime = now
+			}
+			existingCondition.Status = status
+			existingCondition.Reason = reason
+			existingCondition.Message = message
+			existingCondition.LastProbeTime = now
+			changed = true
+		}
+	}
+	return conditions, changed
+}
+
 // SetClusterInstallConditionWithChangeCheck sets a condition in the list of status conditions
 // for a ClusterInstall implementation.
 // It returns the resulting conditions as well a boolean indicating whether there was a change made
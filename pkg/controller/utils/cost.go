@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strconv"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// ParseCost parses a cost from a CostSummary. Malformed costs are treated as zero.
+func ParseCost(cost string) float64 {
+	v, err := strconv.ParseFloat(cost, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// FormatCost formats a cost for a CostSummary. Costs keep more precision than currencies usually do so that small
+// amounts accrued between updates are not lost to rounding.
+func FormatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 4, 64)
+}
+
+// SumCostEstimates adds up the cost estimates of the given ClusterDeployments. It returns nil if none of them have
+// a cost estimate.
+func SumCostEstimates(cds []*hivev1.ClusterDeployment) *hivev1.CostSummary {
+	var sum *hivev1.CostSummary
+	var hourly, total float64
+	for _, cd := range cds {
+		est := cd.Status.CostEstimate
+		if est == nil {
+			continue
+		}
+		if sum == nil {
+			sum = &hivev1.CostSummary{Currency: est.Currency}
+		}
+		hourly += ParseCost(est.HourlyCost)
+		total += ParseCost(est.TotalCost)
+	}
+	if sum != nil {
+		sum.HourlyCost = FormatCost(hourly)
+		sum.TotalCost = FormatCost(total)
+	}
+	return sum
+}
@@ -27,43 +27,13 @@ import (
 // Note: It simply checks that the username/password (or equivalent) can authenticate,
 // not that the credentials have any specific permissions.
 func ValidateCredentialsForClusterDeployment(kubeClient client.Client, cd *hivev1.ClusterDeployment, logger log.FieldLogger) (bool, error) {
-	secret := &corev1.Secret{}
-
 	switch getClusterPlatform(cd) {
 	case constants.PlatformVSphere:
-		secretKey := types.NamespacedName{Name: cd.Spec.Platform.VSphere.CredentialsSecretRef.Name, Namespace: cd.Namespace}
-		if err := kubeClient.Get(context.TODO(), secretKey, secret); err != nil {
-			logger.WithError(err).Error("failed to read in ClusterDeployment's platform creds")
-			return false, err
-		}
-
-		var rootCAFiles []string
-		if cd.Spec.Platform.VSphere.CertificatesSecretRef.Name != "" {
-			certificatesSecret := &corev1.Secret{}
-			certificatesKey := types.NamespacedName{Name: cd.Spec.Platform.VSphere.CertificatesSecretRef.Name, Namespace: cd.Namespace}
-			err := kubeClient.Get(context.TODO(), certificatesKey, certificatesSecret)
-			if err != nil {
-				logger.WithError(err).Error("failed to read in vSphere certificates")
-				return false, err
-			}
-
-			rootCAFiles, err = createRootCAFiles(certificatesSecret)
-			defer func() {
-				for _, filename := range rootCAFiles {
-					os.Remove(filename)
-				}
-			}()
-			if err != nil {
-				logger.WithError(err).Error("failed to create root CA files")
-				return false, err
-			}
-
-		}
-
-		return validateVSphereCredentials(cd.Spec.Platform.VSphere.VCenter,
-			string(secret.Data[constants.UsernameSecretKey]),
-			string(secret.Data[constants.PasswordSecretKey]),
-			rootCAFiles,
+		return ValidateVSphereCredentials(kubeClient,
+			cd.Namespace,
+			cd.Spec.Platform.VSphere.CredentialsSecretRef,
+			cd.Spec.Platform.VSphere.CertificatesSecretRef,
+			cd.Spec.Platform.VSphere.VCenter,
 			logger)
 	default:
 		// If we have no platform-specific credentials verification
@@ -73,6 +43,46 @@ func ValidateCredentialsForClusterDeployment(kubeClient client.Client, cd *hivev
 	}
 }
 
+// ValidateVSphereCredentials verifies that the vSphere credentials in the given secret can authenticate into the
+// vCenter, trusting the CA certificates in the certificates secret, if any.
+func ValidateVSphereCredentials(kubeClient client.Client, namespace string, credentialsSecretRef, certificatesSecretRef corev1.LocalObjectReference, vcenter string, logger log.FieldLogger) (bool, error) {
+	secret := &corev1.Secret{}
+	secretKey := types.NamespacedName{Name: credentialsSecretRef.Name, Namespace: namespace}
+	if err := kubeClient.Get(context.TODO(), secretKey, secret); err != nil {
+		logger.WithError(err).Error("failed to read in vSphere platform creds")
+		return false, err
+	}
+
+	var rootCAFiles []string
+	if certificatesSecretRef.Name != "" {
+		certificatesSecret := &corev1.Secret{}
+		certificatesKey := types.NamespacedName{Name: certificatesSecretRef.Name, Namespace: namespace}
+		err := kubeClient.Get(context.TODO(), certificatesKey, certificatesSecret)
+		if err != nil {
+			logger.WithError(err).Error("failed to read in vSphere certificates")
+			return false, err
+		}
+
+		rootCAFiles, err = createRootCAFiles(certificatesSecret)
+		defer func() {
+			for _, filename := range rootCAFiles {
+				os.Remove(filename)
+			}
+		}()
+		if err != nil {
+			logger.WithError(err).Error("failed to create root CA files")
+			return false, err
+		}
+
+	}
+
+	return validateVSphereCredentials(vcenter,
+		string(secret.Data[constants.UsernameSecretKey]),
+		string(secret.Data[constants.PasswordSecretKey]),
+		rootCAFiles,
+		logger)
+}
+
 // createRootCAFiles creates a temporary file for each key/value pair in the Secret's Data.
 // Caller is responsible for cleaning up the created files.
 func createRootCAFiles(certificateSecret *corev1.Secret) ([]string, error) {
@@ -27,6 +27,9 @@ const (
 	UninstallServiceAccountName = "cluster-uninstaller"
 	uninstallRoleName           = "cluster-uninstaller"
 	uninstallRoleBindingName    = "cluster-uninstaller"
+
+	// InstallHookServiceAccountName will be a service account without any permissions, used to run install hooks.
+	InstallHookServiceAccountName = "cluster-install-hook"
 )
 
 var (
@@ -105,6 +108,15 @@ func SetupClusterUninstallServiceAccount(c client.Client, namespace string, logg
 	return nil
 }
 
+// SetupClusterInstallHookServiceAccount ensures a service account exists to run install hooks. It is not bound to any
+// role, so the hooks cannot act on the hub cluster.
+func SetupClusterInstallHookServiceAccount(c client.Client, namespace string, logger log.FieldLogger) error {
+	if err := setupServiceAccount(c, InstallHookServiceAccountName, namespace, logger); err != nil {
+		return errors.Wrap(err, "failed to setup service account")
+	}
+	return nil
+}
+
 func setupRoleBinding(c client.Client, name, namespace string, role, serviceaccount string, logger log.FieldLogger) error {
 	switch err := c.Get(context.Background(), client.ObjectKey{Name: name, Namespace: namespace}, &rbacv1.RoleBinding{}); {
 	case apierrors.IsNotFound(err):
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/util/cron"
+)
+
+// ScheduleTimeLayout is the layout of the start and end times of schedule windows.
+const ScheduleTimeLayout = "15:04"
+
+// EvaluateSchedule returns whether the given time is within any of the recurring windows, evaluated in the given IANA
+// time zone (UTC if empty), along with the last and the next times at which that changes. The zero time is returned
+// for a transition that is more than a week away.
+func EvaluateSchedule(windows []hivev1.ScheduleWindow, timeZone string, now time.Time) (active bool, last, next time.Time, err error) {
+	location := time.UTC
+	if timeZone != "" {
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return false, time.Time{}, time.Time{}, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+	now = now.In(location)
+
+	type interval struct{ start, end time.Time }
+	var intervals []interval
+	for _, window := range windows {
+		start, err := time.Parse(ScheduleTimeLayout, window.Start)
+		if err != nil {
+			return false, time.Time{}, time.Time{}, fmt.Errorf("invalid start %q: %w", window.Start, err)
+		}
+		end, err := time.Parse(ScheduleTimeLayout, window.End)
+		if err != nil {
+			return false, time.Time{}, time.Time{}, fmt.Errorf("invalid end %q: %w", window.End, err)
+		}
+		// Cover the windows starting from a week before, so the last transition is found, to a week after.
+		for offset := -8; offset <= 7; offset++ {
+			day := now.AddDate(0, 0, offset)
+			if !scheduledOnDay(window.Days, day.Weekday()) {
+				continue
+			}
+			windowStart := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, location)
+			windowEnd := time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, location)
+			if !windowEnd.After(windowStart) {
+				windowEnd = windowEnd.AddDate(0, 0, 1)
+			}
+			intervals = append(intervals, interval{start: windowStart, end: windowEnd})
+		}
+	}
+	activeAt := func(t time.Time) bool {
+		for _, i := range intervals {
+			if !t.Before(i.start) && t.Before(i.end) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Windows may overlap or be adjacent, so only the boundaries at which the state changes are transitions.
+	var boundaries []time.Time
+	for _, i := range intervals {
+		boundaries = append(boundaries, i.start, i.end)
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Before(boundaries[j]) })
+	active = activeAt(now)
+	for _, b := range boundaries {
+		if activeAt(b) == activeAt(b.Add(-time.Nanosecond)) || b.Sub(now) > 7*24*time.Hour || now.Sub(b) > 7*24*time.Hour {
+			continue
+		}
+		if b.After(now) {
+			if next.IsZero() {
+				next = b
+			}
+		} else {
+			last = b
+		}
+	}
+	return active, last, next, nil
+}
+
+// EvaluateCronSchedule returns whether a schedule switching on at the times of the "on" cron expression and off at
+// the times of the "off" one, evaluated in the given IANA time zone (UTC if empty), is on at the given time. It also
+// returns the last and the next times matched by either expression, and whether the schedule is on after the next
+// one. When both expressions match the same time, the schedule switches off. The zero time is returned for a time
+// more than cron.Horizon away.
+func EvaluateCronSchedule(on, off string, timeZone string, now time.Time) (active bool, last, next time.Time, nextActive bool, err error) {
+	location := time.UTC
+	if timeZone != "" {
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return false, time.Time{}, time.Time{}, false, fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+		}
+	}
+	onSchedule, err := cron.Parse(on)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, false, fmt.Errorf("invalid cron expression %q: %w", on, err)
+	}
+	offSchedule, err := cron.Parse(off)
+	if err != nil {
+		return false, time.Time{}, time.Time{}, false, fmt.Errorf("invalid cron expression %q: %w", off, err)
+	}
+	now = now.In(location)
+
+	lastOn, lastOff := onSchedule.Prev(now), offSchedule.Prev(now)
+	active = lastOn.After(lastOff)
+	last = lastOff
+	if active {
+		last = lastOn
+	}
+	nextOn, nextOff := onSchedule.Next(now), offSchedule.Next(now)
+	switch {
+	case nextOff.IsZero() && nextOn.IsZero():
+	case nextOff.IsZero() || (!nextOn.IsZero() && nextOn.Before(nextOff)):
+		next, nextActive = nextOn, true
+	default:
+		next = nextOff
+	}
+	return active, last, next, nextActive, nil
+}
+
+func scheduledOnDay(days []hivev1.ScheduleDay, weekday time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, day := range days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
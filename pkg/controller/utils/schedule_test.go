@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+func TestEvaluateSchedule(t *testing.T) {
+	weekdays := []hivev1.ScheduleDay{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"}
+	// January 12, 2024 is a Friday.
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 12, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name           string
+		windows        []hivev1.ScheduleWindow
+		timeZone       string
+		now            time.Time
+		expectedActive bool
+		expectedLast   time.Time
+		expectedNext   time.Time
+		expectError    bool
+	}{
+		{
+			name:           "weekday window",
+			windows:        []hivev1.ScheduleWindow{{Days: weekdays, Start: "08:00", End: "19:00"}},
+			now:            friday(12, 0),
+			expectedActive: true,
+			expectedLast:   friday(8, 0),
+			expectedNext:   friday(19, 0),
+		},
+		{
+			name:         "weekend",
+			windows:      []hivev1.ScheduleWindow{{Days: weekdays, Start: "08:00", End: "19:00"}},
+			now:          friday(20, 0),
+			expectedLast: friday(19, 0),
+			expectedNext: friday(8, 0).AddDate(0, 0, 3),
+		},
+		{
+			name: "overlapping windows",
+			windows: []hivev1.ScheduleWindow{
+				{Start: "08:00", End: "12:00"},
+				{Start: "11:00", End: "15:00"},
+			},
+			now:            friday(11, 30),
+			expectedActive: true,
+			expectedLast:   friday(8, 0),
+			expectedNext:   friday(15, 0),
+		},
+		{
+			name: "adjacent windows",
+			windows: []hivev1.ScheduleWindow{
+				{Start: "08:00", End: "12:00"},
+				{Start: "12:00", End: "15:00"},
+			},
+			now:          friday(16, 0),
+			expectedLast: friday(15, 0),
+			expectedNext: friday(8, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:           "always active",
+			windows:        []hivev1.ScheduleWindow{{Start: "00:00", End: "00:00"}},
+			now:            friday(12, 0),
+			expectedActive: true,
+		},
+		{
+			name:    "never active",
+			windows: []hivev1.ScheduleWindow{},
+			now:     friday(12, 0),
+		},
+		{
+			name:           "time zone",
+			windows:        []hivev1.ScheduleWindow{{Days: weekdays, Start: "08:00", End: "19:00"}},
+			timeZone:       "America/New_York",
+			now:            friday(23, 0),
+			expectedActive: true,
+			expectedLast:   friday(13, 0),
+			expectedNext:   friday(0, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:        "invalid time zone",
+			windows:     []hivev1.ScheduleWindow{{Start: "08:00", End: "19:00"}},
+			timeZone:    "Mars/Olympus_Mons",
+			now:         friday(12, 0),
+			expectError: true,
+		},
+		{
+			name:        "invalid time",
+			windows:     []hivev1.ScheduleWindow{{Start: "8am", End: "19:00"}},
+			now:         friday(12, 0),
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			active, last, next, err := EvaluateSchedule(tc.windows, tc.timeZone, tc.now)
+			if tc.expectError {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.expectedActive, active, "unexpected active")
+			assert.True(t, tc.expectedLast.Equal(last), "unexpected last transition: expected %s, got %s", tc.expectedLast, last)
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next transition: expected %s, got %s", tc.expectedNext, next)
+		})
+	}
+}
+
+func TestEvaluateCronSchedule(t *testing.T) {
+	// January 12, 2024 is a Friday.
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 12, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name               string
+		on, off            string
+		timeZone           string
+		now                time.Time
+		expectedActive     bool
+		expectedLast       time.Time
+		expectedNext       time.Time
+		expectedNextActive bool
+		expectError        bool
+	}{
+		{
+			name:           "weekday hours",
+			on:             "0 8 * * 1-5",
+			off:            "0 19 * * 1-5",
+			now:            friday(12, 0),
+			expectedActive: true,
+			expectedLast:   friday(8, 0),
+			expectedNext:   friday(19, 0),
+		},
+		{
+			name:               "weekend",
+			on:                 "0 8 * * 1-5",
+			off:                "0 19 * * 1-5",
+			now:                friday(20, 0),
+			expectedLast:       friday(19, 0),
+			expectedNext:       friday(8, 0).AddDate(0, 0, 3),
+			expectedNextActive: true,
+		},
+		{
+			name:               "before resume",
+			on:                 "30 7 * * mon-fri",
+			off:                "0 18 * * mon-fri",
+			now:                friday(7, 0),
+			expectedLast:       friday(18, 0).AddDate(0, 0, -1),
+			expectedNext:       friday(7, 30),
+			expectedNextActive: true,
+		},
+		{
+			name:               "repeated resume",
+			on:                 "0 */4 * * *",
+			off:                "0 0 * * 6",
+			now:                friday(9, 0),
+			expectedActive:     true,
+			expectedLast:       friday(8, 0),
+			expectedNext:       friday(12, 0),
+			expectedNextActive: true,
+		},
+		{
+			name:         "hibernate wins ties",
+			on:           "0 8 * * *",
+			off:          "0 8 * * 5",
+			now:          friday(9, 0),
+			expectedLast: friday(8, 0),
+			expectedNext: friday(8, 0).AddDate(0, 0, 1),
+			// Saturday 08:00 only resumes.
+			expectedNextActive: true,
+		},
+		{
+			name:           "time zone",
+			on:             "0 8 * * 1-5",
+			off:            "0 19 * * 1-5",
+			timeZone:       "America/New_York",
+			now:            friday(23, 0),
+			expectedActive: true,
+			expectedLast:   friday(13, 0),
+			expectedNext:   friday(0, 0).AddDate(0, 0, 1),
+		},
+		{
+			name:        "invalid time zone",
+			on:          "0 8 * * *",
+			off:         "0 19 * * *",
+			timeZone:    "Mars/Olympus_Mons",
+			now:         friday(12, 0),
+			expectError: true,
+		},
+		{
+			name:        "invalid expression",
+			on:          "0 8 * *",
+			off:         "0 19 * * *",
+			now:         friday(12, 0),
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			active, last, next, nextActive, err := EvaluateCronSchedule(tc.on, tc.off, tc.timeZone, tc.now)
+			if tc.expectError {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.expectedActive, active, "unexpected active")
+			assert.True(t, tc.expectedLast.Equal(last), "unexpected last transition: expected %s, got %s", tc.expectedLast, last)
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next transition: expected %s, got %s", tc.expectedNext, next)
+			assert.Equal(t, tc.expectedNextActive, nextActive, "unexpected next active")
+		})
+	}
+}
@@ -51,6 +51,8 @@ type Client interface {
 
 	ListComputeInstances(ListComputeInstancesOptions, func(*compute.InstanceAggregatedList) error) error
 
+	ListComputeResources(filter string) ([]string, error)
+
 	StopInstance(*compute.Instance) error
 
 	StartInstance(*compute.Instance) error
@@ -89,6 +91,14 @@ type Client interface {
 
 	ListAddresses(region string, opts ListAddressesOptions) (*compute.AddressList, error)
 
+	GetRegion(region string) (*compute.Region, error)
+
+	GetProject() (*compute.Project, error)
+
+	GetMachineType(machineType string, zone string) (*compute.MachineType, error)
+
+	TestIamPermissions(permissions []string) ([]string, error)
+
 	GetProjectName() string
 }
 
@@ -287,6 +297,147 @@ func (c *gcpClient) ListComputeInstances(opts ListComputeInstancesOptions, pages
 	return nil
 }
 
+// ListComputeResources returns the self links of the compute resources of the project matching the filter, among the
+// types of compute resources the installer creates: instances, disks, images, instance groups, addresses, forwarding
+// rules, target pools, backend services, health checks, firewalls, networks, subnetworks and routers.
+func (c *gcpClient) ListComputeResources(filter string) ([]string, error) {
+	ctx := context.TODO()
+	project := c.projectName
+	var selfLinks []string
+	lists := map[string]func() error{
+		"instances": func() error {
+			return c.computeClient.Instances.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.InstanceAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.Instances {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"disks": func() error {
+			return c.computeClient.Disks.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.DiskAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.Disks {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"images": func() error {
+			return c.computeClient.Images.List(project).Filter(filter).Pages(ctx, func(list *compute.ImageList) error {
+				for _, r := range list.Items {
+					selfLinks = append(selfLinks, r.SelfLink)
+				}
+				return nil
+			})
+		},
+		"instance groups": func() error {
+			return c.computeClient.InstanceGroups.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.InstanceGroupAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.InstanceGroups {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"addresses": func() error {
+			return c.computeClient.Addresses.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.AddressAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.Addresses {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"forwarding rules": func() error {
+			return c.computeClient.ForwardingRules.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.ForwardingRuleAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.ForwardingRules {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"target pools": func() error {
+			return c.computeClient.TargetPools.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.TargetPoolAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.TargetPools {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"backend services": func() error {
+			return c.computeClient.BackendServices.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.BackendServiceAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.BackendServices {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"health checks": func() error {
+			return c.computeClient.HealthChecks.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.HealthChecksAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.HealthChecks {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"firewalls": func() error {
+			return c.computeClient.Firewalls.List(project).Filter(filter).Pages(ctx, func(list *compute.FirewallList) error {
+				for _, r := range list.Items {
+					selfLinks = append(selfLinks, r.SelfLink)
+				}
+				return nil
+			})
+		},
+		"networks": func() error {
+			return c.computeClient.Networks.List(project).Filter(filter).Pages(ctx, func(list *compute.NetworkList) error {
+				for _, r := range list.Items {
+					selfLinks = append(selfLinks, r.SelfLink)
+				}
+				return nil
+			})
+		},
+		"subnetworks": func() error {
+			return c.computeClient.Subnetworks.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.SubnetworkAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.Subnetworks {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+		"routers": func() error {
+			return c.computeClient.Routers.AggregatedList(project).Filter(filter).Pages(ctx, func(list *compute.RouterAggregatedList) error {
+				for _, scoped := range list.Items {
+					for _, r := range scoped.Routers {
+						selfLinks = append(selfLinks, r.SelfLink)
+					}
+				}
+				return nil
+			})
+		},
+	}
+	for kind, list := range lists {
+		if err := list(); err != nil {
+			return nil, errors.Wrapf(err, "failed to list compute %s", kind)
+		}
+	}
+	return selfLinks, nil
+}
+
 func (c *gcpClient) StopInstance(instance *compute.Instance) error {
 	zone := instanceZone(instance)
 	_, err := c.computeClient.Instances.Stop(c.projectName, zone, instance.Name).Do()
@@ -636,6 +787,41 @@ func (c *gcpClient) GetSubnet(subnet string, region string) (*compute.Subnetwork
 	return c.computeClient.Subnetworks.Get(c.projectName, region, subnet).Context(ctx).Do()
 }
 
+func (c *gcpClient) GetRegion(region string) (*compute.Region, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.Regions.Get(c.projectName, region).Context(ctx).Do()
+}
+
+func (c *gcpClient) GetProject() (*compute.Project, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.Projects.Get(c.projectName).Context(ctx).Do()
+}
+
+func (c *gcpClient) GetMachineType(machineType string, zone string) (*compute.MachineType, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.MachineTypes.Get(c.projectName, zone, machineType).Context(ctx).Do()
+}
+
+// TestIamPermissions returns the subset of the given permissions that the credentials hold on the project.
+func (c *gcpClient) TestIamPermissions(permissions []string) ([]string, error) {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	resp, err := c.cloudResourceManagerClient.Projects.TestIamPermissions(c.projectName, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: permissions,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Permissions, nil
+}
+
 func (c *gcpClient) GetProjectName() string {
 	return c.projectName
 }
@@ -297,6 +297,21 @@ func (mr *MockClientMockRecorder) GetForwardingRule(forwardingRule, region inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForwardingRule", reflect.TypeOf((*MockClient)(nil).GetForwardingRule), forwardingRule, region)
 }
 
+// GetMachineType mocks base method.
+func (m *MockClient) GetMachineType(machineType, zone string) (*compute.MachineType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMachineType", machineType, zone)
+	ret0, _ := ret[0].(*compute.MachineType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMachineType indicates an expected call of GetMachineType.
+func (mr *MockClientMockRecorder) GetMachineType(machineType, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMachineType", reflect.TypeOf((*MockClient)(nil).GetMachineType), machineType, zone)
+}
+
 // GetManagedZone mocks base method.
 func (m *MockClient) GetManagedZone(managedZone string) (*dns.ManagedZone, error) {
 	m.ctrl.T.Helper()
@@ -327,6 +342,21 @@ func (mr *MockClientMockRecorder) GetNetwork(name interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetwork", reflect.TypeOf((*MockClient)(nil).GetNetwork), name)
 }
 
+// GetProject mocks base method.
+func (m *MockClient) GetProject() (*compute.Project, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProject")
+	ret0, _ := ret[0].(*compute.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProject indicates an expected call of GetProject.
+func (mr *MockClientMockRecorder) GetProject() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProject", reflect.TypeOf((*MockClient)(nil).GetProject))
+}
+
 // GetProjectName mocks base method.
 func (m *MockClient) GetProjectName() string {
 	m.ctrl.T.Helper()
@@ -341,6 +371,21 @@ func (mr *MockClientMockRecorder) GetProjectName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjectName", reflect.TypeOf((*MockClient)(nil).GetProjectName))
 }
 
+// GetRegion mocks base method.
+func (m *MockClient) GetRegion(region string) (*compute.Region, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegion", region)
+	ret0, _ := ret[0].(*compute.Region)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegion indicates an expected call of GetRegion.
+func (mr *MockClientMockRecorder) GetRegion(region interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegion", reflect.TypeOf((*MockClient)(nil).GetRegion), region)
+}
+
 // GetServiceAttachment mocks base method.
 func (m *MockClient) GetServiceAttachment(serviceAttachment, region string) (*compute.ServiceAttachment, error) {
 	m.ctrl.T.Helper()
@@ -415,6 +460,21 @@ func (mr *MockClientMockRecorder) ListComputeInstances(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComputeInstances", reflect.TypeOf((*MockClient)(nil).ListComputeInstances), arg0, arg1)
 }
 
+// ListComputeResources mocks base method.
+func (m *MockClient) ListComputeResources(filter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListComputeResources", filter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListComputeResources indicates an expected call of ListComputeResources.
+func (mr *MockClientMockRecorder) ListComputeResources(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListComputeResources", reflect.TypeOf((*MockClient)(nil).ListComputeResources), filter)
+}
+
 // ListComputeZones mocks base method.
 func (m *MockClient) ListComputeZones(arg0 gcpclient.ListComputeZonesOptions) (*compute.ZoneList, error) {
 	m.ctrl.T.Helper()
@@ -488,6 +548,21 @@ func (mr *MockClientMockRecorder) StopInstance(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstance", reflect.TypeOf((*MockClient)(nil).StopInstance), arg0)
 }
 
+// TestIamPermissions mocks base method.
+func (m *MockClient) TestIamPermissions(permissions []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TestIamPermissions", permissions)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TestIamPermissions indicates an expected call of TestIamPermissions.
+func (mr *MockClientMockRecorder) TestIamPermissions(permissions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestIamPermissions", reflect.TypeOf((*MockClient)(nil).TestIamPermissions), permissions)
+}
+
 // UpdateResourceRecordSet mocks base method.
 func (m *MockClient) UpdateResourceRecordSet(managedZone string, addRecordSet, removeRecordSet *dns.ResourceRecordSet) error {
 	m.ctrl.T.Helper()
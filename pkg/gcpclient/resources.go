@@ -0,0 +1,37 @@
+package gcpclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ListClusterResources returns the sorted self links of the compute resources, and the names of the DNS zones, named
+// after the infra ID of a cluster, the way the uninstaller matches the resources of a cluster.
+func ListClusterResources(c Client, infraID string) ([]string, error) {
+	prefix := infraID + "-"
+	resources, err := c.ListComputeResources(fmt.Sprintf("name : \"%s*\"", prefix))
+	if err != nil {
+		return nil, err
+	}
+	opts := ListManagedZonesOptions{}
+	for {
+		zones, err := c.ListManagedZones(opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list managed zones")
+		}
+		for _, zone := range zones.ManagedZones {
+			if strings.HasPrefix(zone.Name, prefix) {
+				resources = append(resources, fmt.Sprintf("projects/%s/managedZones/%s", c.GetProjectName(), zone.Name))
+			}
+		}
+		if zones.NextPageToken == "" {
+			break
+		}
+		opts.PageToken = zones.NextPageToken
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
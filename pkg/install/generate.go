@@ -381,6 +381,25 @@ func InstallerPodSpec(
 		})
 	}
 
+	if ci := cd.Spec.Provisioning.CustomInstaller; ci != nil {
+		image := ci.Image
+		if image == "" {
+			image = installerImage
+		}
+		binary := "/output/" + constants.CustomInstallerBinaryName
+		initContainers = append(initContainers, corev1.Container{
+			Name:            "custom-installer",
+			Image:           image,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Env:             env,
+			Command:         []string{"/bin/sh", "-c"},
+			// Copy then rename, as above, so the install manager never sees a partially copied binary. The paths are
+			// passed as positional parameters rather than in the script, so they are never interpreted by the shell.
+			Args:         []string{`cp -v "$1" "$2.tmp" && mv -v "$2.tmp" "$2"`, "custom-installer", ci.Path, binary},
+			VolumeMounts: volumeMounts,
+		})
+	}
+
 	containers := []corev1.Container{
 		{
 			Name:            "installer",
@@ -7,6 +7,7 @@ import (
 	hiveassert "github.com/openshift/hive/pkg/test/assert"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -107,6 +108,40 @@ func TestInstallerPodSpec(t *testing.T) {
 				assert.NoError(t, actualError)
 			},
 		},
+		{
+			name: "Custom installer",
+			clusterDeployment: &hivev1.ClusterDeployment{
+				Spec: hivev1.ClusterDeploymentSpec{
+					Provisioning: &hivev1.Provisioning{
+						InstallConfigSecretRef: &corev1.LocalObjectReference{Name: "foo"},
+						CustomInstaller: &hivev1.CustomInstaller{
+							Image: "example.com/wrapper:latest",
+							Path:  "/usr/bin/wrapper",
+						},
+					},
+				},
+				Status: hivev1.ClusterDeploymentStatus{
+					InstallerImage: &installerImage,
+					CLIImage:       &cliImage,
+				},
+			},
+			provisionName: "testprovision",
+			validate: func(t *testing.T, actualPodSpec *corev1.PodSpec, actualError error) {
+				require.NoError(t, actualError)
+				var found bool
+				for _, container := range actualPodSpec.InitContainers {
+					if container.Name != "custom-installer" {
+						continue
+					}
+					found = true
+					assert.Equal(t, "example.com/wrapper:latest", container.Image, "unexpected custom installer image")
+					if assert.Len(t, container.Args, 4, "unexpected custom installer args") {
+						assert.Equal(t, []string{"/usr/bin/wrapper", "/output/custom-installer"}, container.Args[2:], "unexpected custom installer copy")
+					}
+				}
+				assert.True(t, found, "missing custom installer init container")
+			},
+		},
 	}
 
 	for _, test := range tests {
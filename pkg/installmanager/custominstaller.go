@@ -0,0 +1,141 @@
+package installmanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/pointer"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/utils"
+)
+
+// customInstallerError is returned when the custom installer exits nonzero.
+type customInstallerError struct {
+	exitCode  int
+	retryable bool
+}
+
+func (e *customInstallerError) Error() string {
+	if e.retryable {
+		return fmt.Sprintf("custom installer exited with retryable exit code %d", e.exitCode)
+	}
+	return fmt.Sprintf("custom installer exited with fatal exit code %d", e.exitCode)
+}
+
+// runCustomInstaller runs the ClusterDeployment's custom installer in place of `openshift-install create cluster`,
+// then publishes the outputs it is required to leave in the work dir. See hivev1.CustomInstaller for the contract.
+func (m *InstallManager) runCustomInstaller() error {
+	args := m.customInstaller.Args
+	if len(args) == 0 {
+		args = []string{"create", "cluster"}
+	}
+	m.log.Info("running custom installer")
+	err := m.runInstallerCommand(
+		filepath.Join(m.binaryDir, constants.CustomInstallerBinaryName),
+		[]string{fmt.Sprintf("%s=%s", constants.OpenShiftInstallBinaryEnvVar, filepath.Join(m.binaryDir, "openshift-install"))},
+		args...,
+	)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		ciErr := &customInstallerError{exitCode: exitErr.ExitCode()}
+		for _, code := range m.customInstaller.RetryableExitCodes {
+			if int(code) == ciErr.exitCode {
+				ciErr.retryable = true
+			}
+		}
+		reason := hivev1.CustomInstallerFatalFailureReason
+		if ciErr.retryable {
+			reason = hivev1.CustomInstallerRetryableFailureReason
+		}
+		if err := m.setProvisionFailedCondition(reason, ciErr.Error()); err != nil {
+			// Not fatal; the failure will still be reported, just without the exit code classification.
+			m.log.WithError(err).Warn("could not record custom installer failure on clusterprovision")
+		}
+		return ciErr
+	}
+	if err != nil {
+		return err
+	}
+	return m.publishCustomInstallerOutputs()
+}
+
+// setProvisionFailedCondition records why the install failed for the clusterprovision controller to pick up once
+// the install job fails.
+func (m *InstallManager) setProvisionFailedCondition(reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		provision := &hivev1.ClusterProvision{}
+		if err := m.DynamicClient.Get(context.Background(), types.NamespacedName{Namespace: m.Namespace, Name: m.ClusterProvisionName}, provision); err != nil {
+			return err
+		}
+		provision.Status.Conditions = utils.SetClusterProvisionCondition(
+			provision.Status.Conditions,
+			hivev1.ClusterProvisionFailedCondition,
+			corev1.ConditionTrue,
+			reason,
+			message,
+			utils.UpdateConditionAlways,
+		)
+		return m.DynamicClient.Status().Update(context.Background(), provision)
+	})
+}
+
+// publishCustomInstallerOutputs refreshes the cluster metadata and admin credentials published before the install
+// from what the custom installer left in the work dir, in case it replaced them.
+func (m *InstallManager) publishCustomInstallerOutputs() error {
+	metadataBytes, metadata, err := m.readClusterMetadata(m)
+	if err != nil {
+		return errors.Wrap(err, "custom installer did not leave valid cluster metadata")
+	}
+	if err := m.updateClusterProvision(
+		m,
+		func(provision *hivev1.ClusterProvision) {
+			provision.Spec.MetadataJSON = metadataBytes
+			provision.Spec.InfraID = pointer.String(metadata.InfraID)
+			provision.Spec.ClusterID = pointer.String(metadata.ClusterID)
+		},
+	); err != nil {
+		return errors.Wrap(err, "error updating cluster provision with custom installer cluster metadata")
+	}
+
+	kubeconfig, err := os.ReadFile(filepath.Join(m.WorkDir, adminKubeConfigRelativePath))
+	if err != nil {
+		return errors.Wrap(err, "custom installer did not leave an admin kubeconfig")
+	}
+	if err := m.refreshSecretData(fmt.Sprintf(adminKubeConfigSecretStringTemplate, m.ClusterProvisionName), "kubeconfig", kubeconfig); err != nil {
+		return err
+	}
+	password, err := m.loadAdminPassword(m)
+	if err != nil {
+		return errors.Wrap(err, "custom installer did not leave an admin password")
+	}
+	return m.refreshSecretData(fmt.Sprintf(adminPasswordSecretStringTemplate, m.ClusterProvisionName), "password", []byte(password))
+}
+
+func (m *InstallManager) refreshSecretData(name, key string, value []byte) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		secret := &corev1.Secret{}
+		if err := m.DynamicClient.Get(context.Background(), types.NamespacedName{Namespace: m.Namespace, Name: name}, secret); err != nil {
+			return errors.Wrapf(err, "error getting secret %s", name)
+		}
+		if bytes.Equal(secret.Data[key], value) {
+			return nil
+		}
+		m.log.WithField("secret", name).Info("updating secret with custom installer output")
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = value
+		return m.DynamicClient.Update(context.Background(), secret)
+	})
+}
@@ -0,0 +1,112 @@
+package installmanager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/utils"
+)
+
+const fakeCustomInstallerBinary = `#!/bin/sh
+[ "$1 $2" = "create cluster" ] || exit 99
+[ -x "$%s" ] || exit 98
+exit %d
+`
+
+func TestRunCustomInstaller(t *testing.T) {
+	tests := []struct {
+		name           string
+		exitCode       int
+		retryableCodes []int32
+		expectReason   string
+	}{
+		{
+			name:           "retryable failure",
+			exitCode:       3,
+			retryableCodes: []int32{2, 3},
+			expectReason:   hivev1.CustomInstallerRetryableFailureReason,
+		},
+		{
+			name:           "fatal failure",
+			exitCode:       4,
+			retryableCodes: []int32{2, 3},
+			expectReason:   hivev1.CustomInstallerFatalFailureReason,
+		},
+		{
+			name:         "fatal failure without retryable codes",
+			exitCode:     1,
+			expectReason: hivev1.CustomInstallerFatalFailureReason,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "installmanagertest")
+			require.NoError(t, err)
+			defer os.RemoveAll(tempDir)
+			defer os.Remove(installerConsoleLogFilePath)
+
+			require.NoError(t, writeFakeBinary(filepath.Join(tempDir, installerBinary), "#!/bin/sh\n"))
+			require.NoError(t, writeFakeBinary(filepath.Join(tempDir, constants.CustomInstallerBinaryName),
+				fmt.Sprintf(fakeCustomInstallerBinary, constants.OpenShiftInstallBinaryEnvVar, test.exitCode)))
+
+			mocks := setupDefaultMocks(t, testClusterProvision())
+			im := InstallManager{
+				LogLevel:             "debug",
+				sleep:                dummySleep,
+				WorkDir:              tempDir,
+				ClusterProvisionName: testProvisionName,
+				Namespace:            testNamespace,
+				DynamicClient:        mocks.fakeKubeClient,
+				binaryDir:            tempDir,
+				customInstaller: &hivev1.CustomInstaller{
+					Path:               "/usr/bin/wrapper",
+					RetryableExitCodes: test.retryableCodes,
+				},
+			}
+			require.NoError(t, im.Complete([]string{}))
+
+			err = im.provisionCluster(&im)
+			var ciErr *customInstallerError
+			if assert.ErrorAs(t, err, &ciErr, "expected a custom installer error") {
+				assert.Equal(t, test.exitCode, ciErr.exitCode, "unexpected exit code")
+			}
+
+			provision := &hivev1.ClusterProvision{}
+			require.NoError(t, mocks.fakeKubeClient.Get(context.Background(),
+				types.NamespacedName{Namespace: testNamespace, Name: testProvisionName}, provision))
+			cond := utils.FindCondition(provision.Status.Conditions, hivev1.ClusterProvisionFailedCondition)
+			if assert.NotNil(t, cond, "missing ClusterProvisionFailed condition") {
+				assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+				assert.Equal(t, test.expectReason, cond.Reason, "unexpected condition reason")
+			}
+		})
+	}
+}
+
+func TestRefreshSecretDataWithoutData(t *testing.T) {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "admin-password"}}
+	mocks := setupDefaultMocks(t, secret)
+	im := InstallManager{
+		LogLevel:      "debug",
+		Namespace:     testNamespace,
+		DynamicClient: mocks.fakeKubeClient,
+	}
+	require.NoError(t, im.Complete([]string{}))
+
+	require.NoError(t, im.refreshSecretData(secret.Name, "password", []byte("secret")))
+
+	require.NoError(t, mocks.fakeKubeClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: secret.Name}, secret))
+	assert.Equal(t, []byte("secret"), secret.Data["password"], "unexpected secret data")
+}
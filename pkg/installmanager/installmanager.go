@@ -128,6 +128,7 @@ type InstallManager struct {
 	loadSecrets                      func(*InstallManager, *hivev1.ClusterDeployment)
 	cleanupFailedProvision           func(dynamicClient client.Client, cd *hivev1.ClusterDeployment, infraID string, logger log.FieldLogger) error
 	updateClusterProvision           func(*InstallManager, provisionMutation) error
+	updateClusterProvisionProgress   func(*InstallManager, *hivev1.ClusterProvisionProgress) error
 	readClusterMetadata              func(*InstallManager) ([]byte, *installertypes.ClusterMetadata, error)
 	uploadAdminKubeconfig            func(*InstallManager) (*corev1.Secret, error)
 	uploadAdminPassword              func(*InstallManager) (*corev1.Secret, error)
@@ -137,6 +138,7 @@ type InstallManager struct {
 	waitForProvisioningStage         func(*InstallManager) error
 	waitForInstallCompleteExecutions int
 	binaryDir                        string
+	customInstaller                  *hivev1.CustomInstaller
 	actuator                         LogUploaderActuator
 	sleep                            func(time.Duration)
 }
@@ -214,6 +216,7 @@ func (m *InstallManager) Complete(args []string) error {
 	// Connect up structure's function pointers
 	m.loadSecrets = loadSecrets
 	m.updateClusterProvision = updateClusterProvisionWithRetries
+	m.updateClusterProvisionProgress = updateClusterProvisionProgress
 	m.readClusterMetadata = readClusterMetadata
 	m.uploadAdminKubeconfig = uploadAdminKubeconfig
 	m.uploadAdminPassword = uploadAdminPassword
@@ -332,6 +335,9 @@ func (m *InstallManager) Run() error {
 	}
 
 	m.ClusterName = cd.Spec.ClusterName
+	if cd.Spec.Provisioning != nil {
+		m.customInstaller = cd.Spec.Provisioning.CustomInstaller
+	}
 
 	if err := m.copyInstallerBinaries(cd); err != nil {
 		m.log.WithError(err).Error("error waiting for/copying binaries")
@@ -620,6 +626,9 @@ func (m *InstallManager) copyInstallerBinaries(cd *hivev1.ClusterDeployment) err
 	if minimal, err := strconv.ParseBool(cd.Annotations[constants.MinimalInstallModeAnnotation]); err != nil || !minimal {
 		fileList = append(fileList, filepath.Join(m.WorkDir, "oc"))
 	}
+	if m.customInstaller != nil {
+		fileList = append(fileList, filepath.Join(m.WorkDir, constants.CustomInstallerBinaryName))
+	}
 
 	// copy each binary to our container user's home dir to avoid situations
 	// where the /output workdir may be mounted with noexec. (surfaced when using kind
@@ -1125,9 +1134,16 @@ func patchWorkerMachineSetManifest(manifestBytes []byte, pool *hivev1.MachinePoo
 	return modifiedBytes, nil
 }
 
-// provisionCluster invokes the openshift-install create cluster command to provision resources
-// in the cloud.
+// provisionCluster invokes the openshift-install create cluster command, or the ClusterDeployment's
+// custom installer, to provision resources in the cloud.
 func provisionCluster(m *InstallManager) error {
+	if m.customInstaller != nil {
+		if err := m.runCustomInstaller(); err != nil {
+			m.log.WithError(err).Error("error provisioning cluster with custom installer")
+			return err
+		}
+		return nil
+	}
 
 	m.log.Info("running openshift-install create cluster")
 
@@ -1150,8 +1166,17 @@ func provisionCluster(m *InstallManager) error {
 
 func (m *InstallManager) runOpenShiftInstallCommand(args ...string) error {
 	m.log.WithField("args", args).Info("running openshift-install binary")
-	cmd := exec.Command(filepath.Join(m.binaryDir, "openshift-install"), args...)
+	return m.runInstallerCommand(filepath.Join(m.binaryDir, "openshift-install"), nil, args...)
+}
+
+// runInstallerCommand runs an installer binary in the work dir, with any extra environment variables given,
+// capturing its output in the installer console log.
+func (m *InstallManager) runInstallerCommand(binary string, extraEnv []string, args ...string) error {
+	cmd := exec.Command(binary, args...)
 	cmd.Dir = m.WorkDir
+	if len(extraEnv) > 0 {
+		cmd.Env = append(os.Environ(), extraEnv...)
+	}
 
 	// save the commands' stdout/stderr to a file
 	stdOutAndErrOutput, err := os.OpenFile(installerConsoleLogFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
@@ -1182,7 +1207,8 @@ func (m *InstallManager) runOpenShiftInstallCommand(args ...string) error {
 }
 
 // tailFullInstallLog streams the full install log to standard out so that
-// the log can be seen from the pods logs.
+// the log can be seen from the pods logs. Along the way it tracks the installer's
+// progress and publishes it to the ClusterProvision status.
 func (m *InstallManager) tailFullInstallLog(scrubInstallLog bool) {
 	logfileName := filepath.Join(m.WorkDir, installerFullLogFile)
 	m.waitForFiles([]string{logfileName})
@@ -1197,6 +1223,7 @@ func (m *InstallManager) tailFullInstallLog(scrubInstallLog bool) {
 
 	r := bufio.NewReader(logfile)
 	fullLine := ""
+	tracker := newInstallProgressTracker()
 
 	// Set up additional log fields
 	suffix := ""
@@ -1244,6 +1271,13 @@ func (m *InstallManager) tailFullInstallLog(scrubInstallLog bool) {
 		} else {
 			fmt.Println(fullLine + suffix)
 		}
+		if tracker.observe(fullLine) {
+			m.log.WithField("phase", tracker.progress.Phase).WithField("percent", tracker.progress.Percent).Info("install progressed")
+			if err := m.updateClusterProvisionProgress(m, tracker.progress); err != nil {
+				// Not fatal; we'll publish the progress again on the next change.
+				m.log.WithError(err).Warn("could not publish install progress")
+			}
+		}
 		// clear out the line buffer so we can start again
 		fullLine = ""
 	}
@@ -36,7 +36,6 @@ import (
 )
 
 const (
-	machinePoolNameLabel = "hive.openshift.io/machine-pool"
 	testDeploymentName   = "test-deployment"
 	testProvisionName    = "test-provision"
 	testNamespace        = "test-namespace"
@@ -1119,7 +1118,7 @@ spec:
 				machineSet, _ := machineSetObj.(*machineapi.MachineSet)
 
 				assert.Equal(t, "true", machineSet.ObjectMeta.Labels[constants.HiveManagedLabel])
-				assert.Equal(t, pool.Spec.Name, machineSet.ObjectMeta.Labels[machinePoolNameLabel], "expected label %s to be %s", machinePoolNameLabel, pool.Spec.Name)
+				assert.Equal(t, pool.Spec.Name, machineSet.ObjectMeta.Labels[constants.MachineSetMachinePoolLabel], "expected label %s to be %s", constants.MachineSetMachinePoolLabel, pool.Spec.Name)
 
 				if tc.testExistingLabels != nil {
 					for k, v := range tc.testExistingLabels {
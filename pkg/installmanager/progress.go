@@ -0,0 +1,138 @@
+package installmanager
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+// installPhaseMarker maps a line in the installer log to the phase the installer enters when it logs that line.
+type installPhaseMarker struct {
+	phase hivev1.ClusterProvisionPhase
+	regex *regexp.Regexp
+	// percent is the overall install progress at the start of the phase.
+	percent int32
+}
+
+// installPhaseMarkers are in the order the installer reaches the phases.
+var installPhaseMarkers = []installPhaseMarker{
+	{
+		phase:   hivev1.ClusterProvisionPhaseInfrastructureProvisioning,
+		regex:   regexp.MustCompile(`Creating infrastructure resources`),
+		percent: 5,
+	},
+	{
+		phase:   hivev1.ClusterProvisionPhaseWaitingForAPI,
+		regex:   regexp.MustCompile(`Waiting up to \S+ (\(until [^)]*\) )?for the Kubernetes API at`),
+		percent: 25,
+	},
+	{
+		phase:   hivev1.ClusterProvisionPhaseBootstrapping,
+		regex:   regexp.MustCompile(`API v\S+ up`),
+		percent: 35,
+	},
+	{
+		phase:   hivev1.ClusterProvisionPhaseOperatorsProgressing,
+		regex:   regexp.MustCompile(`It is now safe to remove the bootstrap resources|Waiting up to \S+ (\(until [^)]*\) )?for the cluster at \S+ to initialize`),
+		percent: 60,
+	},
+	{
+		phase:   hivev1.ClusterProvisionPhaseComplete,
+		regex:   regexp.MustCompile(`Install complete!`),
+		percent: 100,
+	},
+}
+
+// clusterVersionProgressRegex matches the cluster version's rollout progress, which the installer logs while waiting
+// for the cluster to initialize, e.g. "Working towards 4.15.0: 654 of 863 done (75% complete)".
+var clusterVersionProgressRegex = regexp.MustCompile(`Working towards \S+: \d+ of \d+ done \((\d+)% complete\)`)
+
+// installProgressTracker follows the installer's progress through the lines of its log.
+type installProgressTracker struct {
+	progress *hivev1.ClusterProvisionProgress
+	now      func() time.Time
+}
+
+func newInstallProgressTracker() *installProgressTracker {
+	return &installProgressTracker{now: time.Now}
+}
+
+// observe updates the progress for a line of the installer log, returning true if the progress changed.
+func (t *installProgressTracker) observe(line string) bool {
+	current := -1
+	if t.progress != nil {
+		for i, marker := range installPhaseMarkers {
+			if marker.phase == t.progress.Phase {
+				current = i
+			}
+		}
+	}
+	// Phases only move forward, so only look for later phases.
+	for i := len(installPhaseMarkers) - 1; i > current; i-- {
+		if installPhaseMarkers[i].regex.MatchString(line) {
+			t.enterPhase(installPhaseMarkers[i])
+			return true
+		}
+	}
+	if current < 0 || installPhaseMarkers[current].phase != hivev1.ClusterProvisionPhaseOperatorsProgressing {
+		return false
+	}
+	m := clusterVersionProgressRegex.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	cvPercent, err := strconv.ParseInt(m[1], 10, 32)
+	if err != nil {
+		return false
+	}
+	// Scale the cluster version's progress to the span between this phase and the next.
+	start, end := installPhaseMarkers[current].percent, installPhaseMarkers[current+1].percent
+	percent := start + int32(cvPercent)*(end-start-1)/100
+	if percent <= t.progress.Percent {
+		return false
+	}
+	t.progress.Percent = percent
+	return true
+}
+
+func (t *installProgressTracker) enterPhase(marker installPhaseMarker) {
+	now := metav1.NewTime(t.now())
+	if t.progress == nil {
+		t.progress = &hivev1.ClusterProvisionProgress{}
+	}
+	if n := len(t.progress.Phases); n > 0 && t.progress.Phases[n-1].FinishedTime == nil {
+		t.progress.Phases[n-1].FinishedTime = &now
+	}
+	t.progress.Phase = marker.phase
+	t.progress.Percent = marker.percent
+	phase := hivev1.ClusterProvisionPhaseStatus{Name: marker.phase, StartedTime: now}
+	if marker.phase == hivev1.ClusterProvisionPhaseComplete {
+		phase.FinishedTime = &now
+	}
+	t.progress.Phases = append(t.progress.Phases, phase)
+}
+
+// updateClusterProvisionProgress publishes the install progress to the ClusterProvision status. It works on its own
+// copy of the ClusterProvision as it is called while the main install flow may be updating m.ClusterProvision.
+func updateClusterProvisionProgress(m *InstallManager, progress *hivev1.ClusterProvisionProgress) error {
+	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		provision := &hivev1.ClusterProvision{}
+		if err := m.DynamicClient.Get(context.Background(), types.NamespacedName{Namespace: m.Namespace, Name: m.ClusterProvisionName}, provision); err != nil {
+			m.log.WithError(err).Warn("error reading in fresh clusterprovision")
+			return err
+		}
+		provision.Status.Progress = progress.DeepCopy()
+		return m.DynamicClient.Status().Update(context.Background(), provision)
+	}); err != nil {
+		m.log.WithError(err).Error("error trying to update clusterprovision progress")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package installmanager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+)
+
+func TestInstallProgressTracker(t *testing.T) {
+	lines := []struct {
+		line          string
+		expectChanged bool
+		expectPhase   hivev1.ClusterProvisionPhase
+		expectPercent int32
+	}{
+		{
+			line: `time="2024-05-01T10:00:00Z" level=info msg="Consuming Install Config from target directory"`,
+		},
+		{
+			line:          `time="2024-05-01T10:01:00Z" level=info msg="Creating infrastructure resources..."`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseInfrastructureProvisioning,
+			expectPercent: 5,
+		},
+		{
+			line:          `time="2024-05-01T10:05:00Z" level=info msg="Waiting up to 20m0s (until 10:25AM UTC) for the Kubernetes API at https://api.test.example.com:6443..."`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseWaitingForAPI,
+			expectPercent: 25,
+		},
+		{
+			line:          `time="2024-05-01T10:08:00Z" level=info msg="API v1.29.4+4a87b53 up"`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseBootstrapping,
+			expectPercent: 35,
+		},
+		{
+			// A repeated marker for an earlier phase must not move progress backwards.
+			line:          `time="2024-05-01T10:09:00Z" level=info msg="Creating infrastructure resources..."`,
+			expectPhase:   hivev1.ClusterProvisionPhaseBootstrapping,
+			expectPercent: 35,
+		},
+		{
+			line:          `time="2024-05-01T10:20:00Z" level=info msg="It is now safe to remove the bootstrap resources"`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseOperatorsProgressing,
+			expectPercent: 60,
+		},
+		{
+			line:          `time="2024-05-01T10:21:00Z" level=info msg="Waiting up to 40m0s (until 11:01AM UTC) for the cluster at https://api.test.example.com:6443 to initialize..."`,
+			expectPhase:   hivev1.ClusterProvisionPhaseOperatorsProgressing,
+			expectPercent: 60,
+		},
+		{
+			line:          `time="2024-05-01T10:30:00Z" level=debug msg="Still waiting for the cluster to initialize: Working towards 4.16.0: 654 of 863 done (75% complete)"`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseOperatorsProgressing,
+			expectPercent: 89,
+		},
+		{
+			line:          `time="2024-05-01T10:40:00Z" level=info msg="Install complete!"`,
+			expectChanged: true,
+			expectPhase:   hivev1.ClusterProvisionPhaseComplete,
+			expectPercent: 100,
+		},
+	}
+
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	tracker := newInstallProgressTracker()
+	tracker.now = func() time.Time { return now }
+	for _, l := range lines {
+		now = now.Add(time.Minute)
+		changed := tracker.observe(l.line)
+		assert.Equal(t, l.expectChanged, changed, "unexpected change for line %q", l.line)
+		if l.expectPhase == "" {
+			assert.Nil(t, tracker.progress, "expected no progress for line %q", l.line)
+			continue
+		}
+		require.NotNil(t, tracker.progress, "expected progress for line %q", l.line)
+		assert.Equal(t, l.expectPhase, tracker.progress.Phase, "unexpected phase for line %q", l.line)
+		assert.Equal(t, l.expectPercent, tracker.progress.Percent, "unexpected percent for line %q", l.line)
+	}
+
+	phases := tracker.progress.Phases
+	if assert.Len(t, phases, 5, "unexpected number of phases") {
+		for i, phase := range phases {
+			if assert.NotNil(t, phase.FinishedTime, "phase %s not finished", phase.Name) && i+1 < len(phases) {
+				assert.Equal(t, phases[i+1].StartedTime, *phase.FinishedTime, "phase %s should finish when the next starts", phase.Name)
+			}
+		}
+	}
+}
@@ -1035,6 +1035,15 @@ rules:
   - update
   - patch
   - delete
+- apiGroups:
+  - kustomize.toolkit.fluxcd.io
+  resources:
+  - kustomizations
+  verbs:
+  - get
+  - create
+  - update
+  - delete
 - apiGroups:
   - velero.io
   resources:
@@ -1143,6 +1152,16 @@ spec:
   - name: profiling
     port: 6060
     protocol: TCP
+  # The API server tunnel endpoint, to which the agents on the remote clusters connect when HiveConfig enables
+  # apiServerTunnel, and the proxy through which Hive controllers connect to those remote clusters. Only the tunnel
+  # port may be exposed outside of the cluster; the proxy requires the token of the hive-apiserver-tunnel-proxy secret
+  # and is only meant for the Hive controller pods.
+  - name: tunnel
+    port: 8443
+    protocol: TCP
+  - name: tunnel-proxy
+    port: 8444
+    protocol: TCP
 `)
 
 func configControllersServiceYamlBytes() ([]byte, error) {
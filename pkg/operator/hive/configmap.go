@@ -126,6 +126,50 @@ var metricsConfigConfigMapInfo = configMapInfo{
 	},
 }
 
+var certificateIssuerConfigMapInfo = configMapInfo{
+	name:                 "hive-certificate-issuer-config",
+	nameKey:              "hive-certificate-issuer-config",
+	mountPath:            "/data/certificate-issuer-config",
+	envVar:               constants.CertificateIssuerConfigFileEnvVar,
+	volumeSourceOptional: true,
+	getData: func(instance *hivev1.HiveConfig) (interface{}, error) {
+		return instance.Spec.CertificateIssuer, nil
+	},
+}
+
+var apiServerTunnelConfigMapInfo = configMapInfo{
+	name:                 "hive-apiserver-tunnel-config",
+	nameKey:              "hive-apiserver-tunnel-config",
+	mountPath:            "/data/apiserver-tunnel-config",
+	envVar:               constants.APIServerTunnelConfigFileEnvVar,
+	volumeSourceOptional: true,
+	getData: func(instance *hivev1.HiveConfig) (interface{}, error) {
+		return instance.Spec.APIServerTunnel, nil
+	},
+}
+
+var argoCDConfigMapInfo = configMapInfo{
+	name:                 "hive-argocd-config",
+	nameKey:              "hive-argocd-config",
+	mountPath:            "/data/argocd-config",
+	envVar:               constants.ArgoCDConfigFileEnvVar,
+	volumeSourceOptional: true,
+	getData: func(instance *hivev1.HiveConfig) (interface{}, error) {
+		return &instance.Spec.ArgoCD, nil
+	},
+}
+
+var gitOpsRegistrationConfigMapInfo = configMapInfo{
+	name:                 "hive-gitops-registration-config",
+	nameKey:              "hive-gitops-registration-config",
+	mountPath:            "/data/gitops-registration-config",
+	envVar:               constants.GitOpsRegistrationConfigFileEnvVar,
+	volumeSourceOptional: true,
+	getData: func(instance *hivev1.HiveConfig) (interface{}, error) {
+		return instance.Spec.GitOpsRegistrations, nil
+	},
+}
+
 func (r *ReconcileHiveConfig) supportedContractsConfigMapInfo() configMapInfo {
 	f := func(instance *hivev1.HiveConfig) (interface{}, error) {
 		supported := map[string][]contracts.ContractImplementation{}
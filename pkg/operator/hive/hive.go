@@ -155,6 +155,10 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h resource.Helper
 	addConfigVolume(&hiveDeployment.Spec.Template.Spec, privateLinkConfigMapInfo, hiveContainer)
 	addConfigVolume(&hiveDeployment.Spec.Template.Spec, failedProvisionConfigMapInfo, hiveContainer)
 	addConfigVolume(&hiveDeployment.Spec.Template.Spec, metricsConfigConfigMapInfo, hiveContainer)
+	addConfigVolume(&hiveDeployment.Spec.Template.Spec, certificateIssuerConfigMapInfo, hiveContainer)
+	addConfigVolume(&hiveDeployment.Spec.Template.Spec, apiServerTunnelConfigMapInfo, hiveContainer)
+	addConfigVolume(&hiveDeployment.Spec.Template.Spec, argoCDConfigMapInfo, hiveContainer)
+	addConfigVolume(&hiveDeployment.Spec.Template.Spec, gitOpsRegistrationConfigMapInfo, hiveContainer)
 
 	// This triggers the clusterdeployment controller to copy the secret into the CD's namespace.
 	// It would be neat if it did that purely based on the FailedProvisionConfig ConfigMap, to
@@ -246,6 +250,14 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h resource.Helper
 		})
 	}
 
+	if instance.Spec.PreflightChecks == hivev1.PreflightChecksEnabled {
+		hLog.Info("Preflight checks enabled")
+		hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
+			Name:  constants.PreflightChecksEnvVar,
+			Value: "true",
+		})
+	}
+
 	if instance.Spec.ReleaseImageVerificationConfigMapRef != nil {
 		hLog.Info("Release Image verification enabled")
 		hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
@@ -257,6 +269,14 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h resource.Helper
 		})
 	}
 
+	if instance.Spec.CostEstimation != nil {
+		hLog.Info("Cost estimation enabled")
+		hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
+			Name:  constants.CostEstimationPriceTableConfigMapEnvVar,
+			Value: instance.Spec.CostEstimation.PriceTableConfigMapRef.Name,
+		})
+	}
+
 	if err := r.includeAdditionalCAs(hLog, h, instance, hiveDeployment, hiveContainer, namespacesToClean); err != nil {
 		return err
 	}
@@ -0,0 +1,206 @@
+package remoteclient
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/util/scheme"
+)
+
+const (
+	// cacheIdleTimeout is how long an entry of the client cache is kept without being used. It bounds the entries
+	// kept for deleted ClusterDeployments.
+	cacheIdleTimeout = 30 * time.Minute
+
+	invalidationChanged     = "changed"
+	invalidationUnreachable = "unreachable"
+	invalidationIdle        = "idle"
+)
+
+var (
+	metricClientCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_client_cache_requests_total",
+		Help: "Counter incremented for each remote client built, by whether the connection to the remote cluster was cached.",
+	}, []string{"controller", "result"})
+	metricClientCacheInvalidations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_client_cache_invalidations_total",
+		Help: "Counter incremented for each cached connection to a remote cluster discarded.",
+	}, []string{"reason"})
+	metricClientCacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hive_remote_client_cache_entries",
+		Help: "Number of remote clusters with a cached connection.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricClientCacheRequests)
+	metrics.Registry.MustRegister(metricClientCacheInvalidations)
+	metrics.Registry.MustRegister(metricClientCacheEntries)
+}
+
+// sharedCache is the client cache shared by all the controllers of the process.
+var sharedCache = newClientCache()
+
+// clientCache caches, per ClusterDeployment, the connection to its remote cluster: the transport, with its pooled
+// connections and TLS sessions, and the REST mapper discovered from the API of the cluster. The controllers building
+// remote clients for a ClusterDeployment share them, rather than building and discovering them for each reconcile.
+type clientCache struct {
+	mu        sync.Mutex
+	entries   map[types.NamespacedName]*cacheEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// cacheKey identifies how a cache entry connects to the remote cluster. The entry is discarded when the key of the
+// ClusterDeployment changes, for instance when its admin kubeconfig secret is updated.
+type cacheKey struct {
+	kubeconfigResourceVersion string
+	host                      string
+	connectivity              string
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	config    *rest.Config
+	transport http.RoundTripper
+	mapper    meta.RESTMapper
+	lastUsed  time.Time
+
+	mu      sync.Mutex
+	clients map[hivev1.ControllerName]client.Client
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{
+		entries: map[types.NamespacedName]*cacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// get returns the entry of a ClusterDeployment if it was built with the same key, discarding it otherwise.
+func (c *clientCache) get(cd types.NamespacedName, key cacheKey, controllerName hivev1.ControllerName) *cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	entry := c.entries[cd]
+	if entry != nil && entry.key != key {
+		c.remove(cd, invalidationChanged)
+		entry = nil
+	}
+	if entry == nil {
+		metricClientCacheRequests.WithLabelValues(controllerName.String(), "miss").Inc()
+		return nil
+	}
+	metricClientCacheRequests.WithLabelValues(controllerName.String(), "hit").Inc()
+	entry.lastUsed = c.now()
+	return entry
+}
+
+// add caches the entry of a ClusterDeployment, replacing any previous one.
+func (c *clientCache) add(cd types.NamespacedName, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if previous := c.entries[cd]; previous != nil && previous != entry {
+		utilnet.CloseIdleConnectionsFor(previous.transport)
+	}
+	entry.lastUsed = c.now()
+	c.entries[cd] = entry
+	metricClientCacheEntries.Set(float64(len(c.entries)))
+}
+
+// invalidate discards the entry of a ClusterDeployment, if any.
+func (c *clientCache) invalidate(cd types.NamespacedName, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remove(cd, reason)
+}
+
+func (c *clientCache) remove(cd types.NamespacedName, reason string) {
+	entry := c.entries[cd]
+	if entry == nil {
+		return
+	}
+	delete(c.entries, cd)
+	utilnet.CloseIdleConnectionsFor(entry.transport)
+	metricClientCacheInvalidations.WithLabelValues(reason).Inc()
+	metricClientCacheEntries.Set(float64(len(c.entries)))
+}
+
+// sweep discards the entries which have not been used for cacheIdleTimeout. It runs at most once a minute.
+func (c *clientCache) sweep() {
+	now := c.now()
+	if now.Sub(c.lastSweep) < time.Minute {
+		return
+	}
+	c.lastSweep = now
+	for cd, entry := range c.entries {
+		if now.Sub(entry.lastUsed) > cacheIdleTimeout {
+			c.remove(cd, invalidationIdle)
+		}
+	}
+}
+
+// newCacheEntry builds the transport and the REST mapper of a remote cluster. The REST mapper discovers the API of
+// the cluster lazily, and again when a kind is not found, so it stays accurate as CRDs are added to the cluster.
+func newCacheEntry(cfg *rest.Config, key cacheKey) (*cacheEntry, error) {
+	transport, err := rest.TransportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	entry := &cacheEntry{
+		key:       key,
+		config:    cfg,
+		transport: transport,
+		clients:   map[hivev1.ControllerName]client.Client{},
+	}
+	if entry.mapper, err = apiutil.NewDynamicRESTMapper(cfg, &http.Client{Transport: transport, Timeout: cfg.Timeout}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// httpClient returns an HTTP client using the shared transport of the entry, reporting the requests of a controller
+// in its metrics.
+func (e *cacheEntry) httpClient(controllerName hivev1.ControllerName) *http.Client {
+	return &http.Client{
+		Transport: &utils.ControllerMetricsTripper{
+			RoundTripper: e.transport,
+			Controller:   controllerName,
+			Remote:       true,
+		},
+		Timeout: e.config.Timeout,
+	}
+}
+
+// client returns the controller-runtime client of a controller, built once per entry.
+func (e *cacheEntry) client(controllerName hivev1.ControllerName) (client.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if c, ok := e.clients[controllerName]; ok {
+		return c, nil
+	}
+	c, err := client.New(e.config, client.Options{
+		Scheme:     scheme.GetScheme(),
+		HTTPClient: e.httpClient(controllerName),
+		Mapper:     e.mapper,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c = client.WithFieldOwner(c, "hive2-"+string(controllerName))
+	e.clients[controllerName] = c
+	return c, nil
+}
@@ -0,0 +1,218 @@
+package remoteclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	otherControllerName hivev1.ControllerName = "other-controller-name"
+)
+
+// testAPIServer serves the discovery of the core API, and config maps, counting discovery requests.
+type testAPIServer struct {
+	*httptest.Server
+	discoveryRequests atomic.Int32
+}
+
+func newTestAPIServer(t *testing.T) *testAPIServer {
+	s := &testAPIServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.URL.Path == "/api":
+			s.discoveryRequests.Add(1)
+			w.Write([]byte(`{"kind":"APIVersions","versions":["v1"]}`))
+		case req.URL.Path == "/apis":
+			w.Write([]byte(`{"kind":"APIGroupList","apiVersion":"v1","groups":[]}`))
+		case req.URL.Path == "/api/v1":
+			w.Write([]byte(`{"kind":"APIResourceList","groupVersion":"v1","resources":[` +
+				`{"name":"configmaps","singularName":"configmap","namespaced":true,"kind":"ConfigMap","verbs":["get"]}]}`))
+		case strings.HasPrefix(req.URL.Path, "/api/v1/namespaces/test/configmaps/"):
+			w.Write([]byte(`{"kind":"ConfigMap","apiVersion":"v1","metadata":{"namespace":"test","name":"` +
+				strings.TrimPrefix(req.URL.Path, "/api/v1/namespaces/test/configmaps/") + `"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"NotFound","code":404}`))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func kubeconfigSecretFor(t *testing.T, host, resourceVersion string) *corev1.Secret {
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"cluster": {Server: host}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"admin": {Token: "test-token"}},
+		Contexts:       map[string]*clientcmdapi.Context{"admin": {Cluster: "cluster", AuthInfo: "admin"}},
+		CurrentContext: "admin",
+	})
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       testNamespace,
+			Name:            testKubeconfigSecretName,
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string][]byte{constants.KubeconfigSecretKey: kubeconfig},
+	}
+}
+
+func resetSharedCache(t *testing.T) *clientCache {
+	previous := sharedCache
+	sharedCache = newClientCache()
+	t.Cleanup(func() { sharedCache = previous })
+	return sharedCache
+}
+
+func getConfigMap(t *testing.T, c client.Client, name string) {
+	cm := &corev1.ConfigMap{}
+	if assert.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: name}, cm), "unexpected error using remote client") {
+		assert.Equal(t, name, cm.Name, "unexpected config map")
+	}
+}
+
+func Test_builder_Build_cache(t *testing.T) {
+	cache := resetSharedCache(t)
+	server := newTestAPIServer(t)
+	cd := testClusterDeployment()
+	cdKey := client.ObjectKeyFromObject(cd)
+	c := fakeClient(cd, kubeconfigSecretFor(t, server.URL, "1"))
+
+	first, err := NewBuilder(c, cd, testControllerName).Build()
+	require.NoError(t, err, "unexpected error building first client")
+	getConfigMap(t, first, "first")
+	assert.Equal(t, int32(1), server.discoveryRequests.Load(), "expected the first client to verify reachability")
+	require.Contains(t, cache.entries, cdKey, "expected the connection to be cached")
+
+	again, err := NewBuilder(c, cd, testControllerName).Build()
+	require.NoError(t, err, "unexpected error building cached client")
+	assert.Same(t, first, again, "expected the cached client of the controller")
+
+	other, err := NewBuilder(c, cd, otherControllerName).Build()
+	require.NoError(t, err, "unexpected error building client of another controller")
+	assert.NotSame(t, first, other, "expected a client per controller")
+	getConfigMap(t, other, "other")
+	assert.Equal(t, int32(1), server.discoveryRequests.Load(), "expected cached clients not to verify reachability again")
+
+	_, err = NewBuilder(c, cd, testControllerName).UsePrimaryAPIURL().Build()
+	require.NoError(t, err, "unexpected error building client for primary API URL")
+	assert.Equal(t, int32(2), server.discoveryRequests.Load(), "expected clients for an explicit API URL to verify reachability")
+
+	kubeClient, err := NewBuilder(c, cd, testControllerName).BuildKubeClient()
+	require.NoError(t, err, "unexpected error building kube client")
+	_, err = kubeClient.CoreV1().ConfigMaps("test").Get(context.Background(), "kube", metav1.GetOptions{})
+	assert.NoError(t, err, "unexpected error using kube client")
+	assert.Equal(t, int32(2), server.discoveryRequests.Load(), "expected kube clients not to verify reachability")
+}
+
+func Test_builder_Build_cacheInvalidation(t *testing.T) {
+	cases := []struct {
+		name       string
+		invalidate func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client
+		expectErr  bool
+	}{
+		{
+			name: "kubeconfig secret changed",
+			invalidate: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client {
+				return fakeClient(cd, kubeconfigSecretFor(t, server.URL, "2"))
+			},
+		},
+		{
+			name: "API URL changed",
+			invalidate: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client {
+				setAPIURLOverride(cd, server.URL+"/")
+				return c
+			},
+		},
+		{
+			name: "proxy changed",
+			invalidate: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client {
+				cd.Spec.ControlPlaneConfig.APIServerProxy = &hivev1.APIServerProxy{URL: server.URL}
+				return c
+			},
+		},
+		{
+			name: "unreachable",
+			invalidate: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client {
+				cd.Status.Conditions = nil
+				_, unreachable, _ := ConnectToRemoteCluster(cd, NewBuilder(c, cd, testControllerName), c, log.WithField("test", "unreachable"))
+				assert.True(t, unreachable, "expected the cluster to be unreachable")
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{Type: hivev1.UnreachableCondition, Status: corev1.ConditionFalse}}
+				return c
+			},
+		},
+		{
+			name: "connection failure",
+			invalidate: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, server *testAPIServer) client.Client {
+				server.Close()
+				return c
+			},
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := resetSharedCache(t)
+			server := newTestAPIServer(t)
+			cd := testClusterDeployment()
+			cdKey := client.ObjectKeyFromObject(cd)
+			c := fakeClient(cd, kubeconfigSecretFor(t, server.URL, "1"))
+
+			first, err := NewBuilder(c, cd, testControllerName).Build()
+			require.NoError(t, err, "unexpected error building first client")
+			entry := cache.entries[cdKey]
+			require.NotNil(t, entry, "expected the connection to be cached")
+
+			c = tc.invalidate(t, c, cd, server)
+
+			rebuilt, err := NewBuilder(c, cd, testControllerName).UsePrimaryAPIURL().Build()
+			if tc.expectErr {
+				assert.Error(t, err, "expected an error connecting to the cluster")
+				assert.NotContains(t, cache.entries, cdKey, "expected the connection to be discarded")
+				return
+			}
+			require.NoError(t, err, "unexpected error rebuilding client")
+			assert.NotSame(t, first, rebuilt, "expected a new client")
+			assert.NotSame(t, entry, cache.entries[cdKey], "expected a new connection to be cached")
+		})
+	}
+}
+
+func Test_clientCache_sweep(t *testing.T) {
+	now := time.Now()
+	cache := newClientCache()
+	cache.now = func() time.Time { return now }
+	used := types.NamespacedName{Namespace: testNamespace, Name: "used"}
+	idle := types.NamespacedName{Namespace: testNamespace, Name: "idle"}
+	key := cacheKey{host: "https://api.example.com:6443"}
+	cache.add(used, &cacheEntry{key: key, transport: http.DefaultTransport})
+	cache.add(idle, &cacheEntry{key: key, transport: http.DefaultTransport})
+
+	now = now.Add(cacheIdleTimeout / 2)
+	assert.NotNil(t, cache.get(used, key, testControllerName), "expected the used entry to be cached")
+
+	now = now.Add(cacheIdleTimeout/2 + time.Minute)
+	assert.NotNil(t, cache.get(used, key, testControllerName), "expected the used entry to be kept")
+	assert.Nil(t, cache.get(idle, key, testControllerName), "expected the idle entry to be discarded")
+	assert.NotContains(t, cache.entries, idle, "expected the idle entry to be discarded")
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/pkg/errors"
@@ -21,14 +22,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	"github.com/openshift/hive/pkg/apiservertunnel"
 	"github.com/openshift/hive/pkg/controller/utils"
-	"github.com/openshift/hive/pkg/util/scheme"
 )
 
 // Builder is used to build API clients to the remote cluster
 type Builder interface {
 	// Build will return a static controller-runtime client for the remote cluster.
-	// It is also responsible for verifying reachability of client, and will fail if unreachable.
+	// It is also responsible for verifying reachability of client, and will fail if unreachable. Connections to the
+	// remote cluster are cached and shared by all controllers, and only verified when they are first established.
 	Build() (client.Client, error)
 
 	// BuildDynamic will return a dynamic kubeclient for the remote cluster.
@@ -106,6 +108,7 @@ func connectToRemoteCluster(
 ) (remoteClient interface{}, unreachable, requeue bool) {
 	if u, _ := Unreachable(cd); u {
 		logger.Debug("skipping cluster with unreachable condition")
+		sharedCache.invalidate(client.ObjectKeyFromObject(cd), invalidationUnreachable)
 		unreachable = true
 		return
 	}
@@ -114,6 +117,7 @@ func connectToRemoteCluster(
 	if err == nil {
 		return
 	}
+	sharedCache.invalidate(client.ObjectKeyFromObject(cd), invalidationUnreachable)
 	unreachable = true
 	logger.WithError(err).Info("remote cluster is unreachable")
 	SetUnreachableCondition(cd, err)
@@ -198,36 +202,37 @@ const (
 	secondaryURL
 )
 
+// Build returns the client of the controller from the shared client cache, verifying the reachability of the remote
+// cluster when its connection is not cached yet. Clients built for an explicitly selected API URL always verify the
+// reachability of the remote cluster, since that is how the unreachable controller checks connectivity.
 func (b *builder) Build() (client.Client, error) {
-	cfg, err := b.RESTConfig()
+	entry, cached, err := b.cacheEntry()
 	if err != nil {
 		return nil, err
 	}
-	// Verify reachability of client
-	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
-	if err != nil {
-		return nil, err
-	}
-	_, err = restmapper.GetAPIGroupResources(dc)
-	if err != nil {
-		return nil, err
-	}
-	c, err := client.New(cfg, client.Options{
-		Scheme: scheme.GetScheme(),
-	})
-	if err != nil {
-		return nil, err
+	if !cached || b.urlToUse != activeURL {
+		// Verify reachability of client
+		dc, err := discovery.NewDiscoveryClientForConfigAndClient(entry.config, entry.httpClient(b.controllerName))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := restmapper.GetAPIGroupResources(dc); err != nil {
+			sharedCache.invalidate(client.ObjectKeyFromObject(b.cd), invalidationUnreachable)
+			return nil, err
+		}
+		sharedCache.add(client.ObjectKeyFromObject(b.cd), entry)
 	}
-	return client.WithFieldOwner(c, "hive2-"+string(b.controllerName)), nil
+	return entry.client(b.controllerName)
 }
 
+// BuildDynamic returns a dynamic client sharing the cached connection to the remote cluster, if any.
 func (b *builder) BuildDynamic() (dynamic.Interface, error) {
-	cfg, err := b.RESTConfig()
+	entry, _, err := b.cacheEntry()
 	if err != nil {
 		return nil, err
 	}
 
-	client, err := dynamic.NewForConfig(cfg)
+	client, err := dynamic.NewForConfigAndClient(entry.config, entry.httpClient(b.controllerName))
 	if err != nil {
 		return nil, err
 	}
@@ -235,13 +240,14 @@ func (b *builder) BuildDynamic() (dynamic.Interface, error) {
 	return client, nil
 }
 
+// BuildKubeClient returns a kubernetes client sharing the cached connection to the remote cluster, if any.
 func (b *builder) BuildKubeClient() (kubeclient.Interface, error) {
-	cfg, err := b.RESTConfig()
+	entry, _, err := b.cacheEntry()
 	if err != nil {
 		return nil, err
 	}
 
-	client, err := kubeclient.NewForConfig(cfg)
+	client, err := kubeclient.NewForConfigAndClient(entry.config, entry.httpClient(b.controllerName))
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +255,20 @@ func (b *builder) BuildKubeClient() (kubeclient.Interface, error) {
 	return client, nil
 }
 
+// cacheEntry returns the cached connection to the remote cluster, or a new one, not cached yet, if there is none or
+// the way to connect to the remote cluster changed.
+func (b *builder) cacheEntry() (entry *cacheEntry, cached bool, err error) {
+	cfg, key, err := b.restConfig()
+	if err != nil {
+		return nil, false, err
+	}
+	if entry := sharedCache.get(client.ObjectKeyFromObject(b.cd), key, b.controllerName); entry != nil {
+		return entry, true, nil
+	}
+	entry, err = newCacheEntry(cfg, key)
+	return entry, false, err
+}
+
 func (b *builder) UsePrimaryAPIURL() Builder {
 	b.urlToUse = primaryURL
 	return b
@@ -260,12 +280,25 @@ func (b *builder) UseSecondaryAPIURL() Builder {
 }
 
 func (b *builder) RESTConfig() (*rest.Config, error) {
-	cfg, err := unadulteratedRESTConfig(b.c, b.cd)
+	cfg, _, err := b.restConfig()
 	if err != nil {
 		return nil, err
 	}
-
 	utils.AddControllerMetricsTransportWrapper(cfg, b.controllerName, true)
+	return cfg, nil
+}
+
+// restConfig returns the config for a REST client that connects to the remote cluster, without the metrics of the
+// controller, and the key identifying how it connects to the remote cluster in the client cache.
+func (b *builder) restConfig() (*rest.Config, cacheKey, error) {
+	kubeconfigSecret, err := getKubeconfigSecret(b.c, b.cd)
+	if err != nil {
+		return nil, cacheKey{}, err
+	}
+	cfg, err := utils.RestConfigFromSecret(kubeconfigSecret, false)
+	if err != nil {
+		return nil, cacheKey{}, err
+	}
 
 	if override := b.cd.Spec.ControlPlaneConfig.APIURLOverride; override != "" {
 		if b.urlToUse == primaryURL ||
@@ -274,7 +307,26 @@ func (b *builder) RESTConfig() (*rest.Config, error) {
 		}
 	}
 
-	if override := b.cd.Spec.ControlPlaneConfig.APIServerIPOverride; override != "" {
+	key := cacheKey{kubeconfigResourceVersion: kubeconfigSecret.ResourceVersion, host: cfg.Host}
+	switch cp := b.cd.Spec.ControlPlaneConfig; {
+	case cp.APIServerTunnel:
+		token, tokenVersion, err := apiservertunnel.ProxyToken(b.c)
+		if err != nil {
+			return nil, cacheKey{}, err
+		}
+		cfg.Dial = apiservertunnel.NewDialer(token, b.cd.Namespace, b.cd.Name)
+		// The tunnel proxy is in the hive namespace, never behind the proxy of the environment.
+		cfg.Proxy = func(*http.Request) (*url.URL, error) { return nil, nil }
+		key.connectivity = "tunnel " + tokenVersion
+	case cp.APIServerProxy != nil:
+		proxyURL, err := apiServerProxyURL(b.c, b.cd)
+		if err != nil {
+			return nil, cacheKey{}, err
+		}
+		cfg.Proxy = http.ProxyURL(proxyURL)
+		key.connectivity = "proxy " + proxyURL.String()
+	case cp.APIServerIPOverride != "":
+		override := cp.APIServerIPOverride
 		dialer := &net.Dialer{
 			Timeout:   30 * time.Second,
 			KeepAlive: 30 * time.Second,
@@ -284,12 +336,43 @@ func (b *builder) RESTConfig() (*rest.Config, error) {
 		// https://github.com/kubernetes/kubernetes/issues/118703#issuecomment-1595072383
 		// TODO: Revert or adapt when upstream fix is available
 		cfg.Proxy = machnet.NewProxierWithNoProxyCIDR(http.ProxyFromEnvironment)
+		key.connectivity = "ip " + override
 	}
 
-	return cfg, nil
+	return cfg, key, nil
+}
+
+// apiServerProxyURL returns the URL of the proxy to the API server of the remote cluster, with the credentials of the
+// proxy, if any.
+func apiServerProxyURL(c client.Client, cd *hivev1.ClusterDeployment) (*url.URL, error) {
+	proxy := cd.Spec.ControlPlaneConfig.APIServerProxy
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid API server proxy URL")
+	}
+	if proxy.CredentialsSecretRef != nil {
+		secret := &corev1.Secret{}
+		if err := c.Get(
+			context.Background(),
+			client.ObjectKey{Namespace: cd.Namespace, Name: proxy.CredentialsSecretRef.Name},
+			secret,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not get API server proxy credentials secret")
+		}
+		proxyURL.User = url.UserPassword(string(secret.Data["username"]), string(secret.Data["password"]))
+	}
+	return proxyURL, nil
 }
 
 func unadulteratedRESTConfig(c client.Client, cd *hivev1.ClusterDeployment) (*rest.Config, error) {
+	kubeconfigSecret, err := getKubeconfigSecret(c, cd)
+	if err != nil {
+		return nil, err
+	}
+	return utils.RestConfigFromSecret(kubeconfigSecret, false)
+}
+
+func getKubeconfigSecret(c client.Client, cd *hivev1.ClusterDeployment) (*corev1.Secret, error) {
 	kubeconfigSecret := &corev1.Secret{}
 	if err := c.Get(
 		context.Background(),
@@ -299,5 +382,5 @@ func unadulteratedRESTConfig(c client.Client, cd *hivev1.ClusterDeployment) (*re
 	); err != nil {
 		return nil, errors.Wrap(err, "could not get admin kubeconfig secret")
 	}
-	return utils.RestConfigFromSecret(kubeconfigSecret, false)
+	return kubeconfigSecret, nil
 }
@@ -203,6 +203,13 @@ func WithHibernateAfter(dur time.Duration) Option {
 	}
 }
 
+// WithPowerSchedule sets the specified power schedule on the supplied object.
+func WithPowerSchedule(schedule *hivev1.PowerSchedule) Option {
+	return func(clusterDeployment *hivev1.ClusterDeployment) {
+		clusterDeployment.Spec.PowerSchedule = schedule
+	}
+}
+
 // WithAWSPlatform sets the specified aws platform on the supplied object.
 func WithAWSPlatform(platform *hivev1aws.Platform) Option {
 	return func(clusterDeployment *hivev1.ClusterDeployment) {
@@ -89,6 +89,12 @@ func Completed() Option {
 	}
 }
 
+func WithDryRun() Option {
+	return func(clusterDeprovision *hivev1.ClusterDeprovision) {
+		clusterDeprovision.Spec.DryRun = true
+	}
+}
+
 func WithAuthenticationFailure() Option {
 	return func(clusterDeprovision *hivev1.ClusterDeprovision) {
 		clusterDeprovision.Status.Conditions = controllerutils.SetClusterDeprovisionCondition(
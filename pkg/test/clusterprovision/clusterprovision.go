@@ -96,6 +96,15 @@ func WithStage(stage hivev1.ClusterProvisionStage) Option {
 	}
 }
 
+func WithProgress(phase hivev1.ClusterProvisionPhase, percent int32) Option {
+	return func(clusterProvision *hivev1.ClusterProvision) {
+		clusterProvision.Status.Progress = &hivev1.ClusterProvisionProgress{
+			Phase:   phase,
+			Percent: percent,
+		}
+	}
+}
+
 func Successful(clusterID, infraID, kubeconfigSecretName, passwordSecretName string) Option {
 	return func(clusterProvision *hivev1.ClusterProvision) {
 		clusterProvision.Spec.Stage = hivev1.ClusterProvisionStageComplete
@@ -1,6 +1,8 @@
 package clusterrelocate
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -87,3 +89,40 @@ func WithClusterDeploymentSelector(key, value string) Option {
 		}
 	}
 }
+
+func WithAdditionalResources(apiVersion, kind, key, value string) Option {
+	return func(clusterRelocate *hivev1.ClusterRelocate) {
+		clusterRelocate.Spec.AdditionalResources = append(clusterRelocate.Spec.AdditionalResources, hivev1.RelocateResourceSelector{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{key: value},
+			},
+		})
+	}
+}
+
+func WithVerification(timeout time.Duration) Option {
+	return func(clusterRelocate *hivev1.ClusterRelocate) {
+		clusterRelocate.Spec.Verification = &hivev1.RelocateVerification{
+			Timeout: &metav1.Duration{Duration: timeout},
+		}
+	}
+}
+
+func WithRollback() Option {
+	return func(clusterRelocate *hivev1.ClusterRelocate) {
+		clusterRelocate.Spec.Rollback = true
+	}
+}
+
+func WithClusterStatus(namespace, name string, phase hivev1.ClusterRelocationPhase, lastTransitionTime time.Time) Option {
+	return func(clusterRelocate *hivev1.ClusterRelocate) {
+		clusterRelocate.Status.Clusters = append(clusterRelocate.Status.Clusters, hivev1.ClusterRelocationStatus{
+			Namespace:          namespace,
+			Name:               name,
+			Phase:              phase,
+			LastTransitionTime: metav1.NewTime(lastTransitionTime),
+		})
+	}
+}
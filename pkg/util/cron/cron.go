@@ -0,0 +1,201 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Horizon is how far from a given time Next and Prev look for a time matching a schedule.
+const Horizon = 366 * 24 * time.Hour
+
+// Schedule is a parsed cron expression in the standard five-field format: minute, hour, day of month, month and day
+// of week. Fields accept "*", values, ranges ("1-5"), steps ("*/15", "8-18/2") and comma-separated lists of those.
+// Months and days of the week may be given by their three-letter English names, and Sunday is either 0 or 7. The
+// @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly shorthands are accepted as well.
+type Schedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// When both the day of month and the day of week are restricted, a day matching either of them matches.
+	restrictedDayOfMonth, restrictedDayOfWeek bool
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteBounds     = fieldBounds{name: "minute", min: 0, max: 59}
+	hourBounds       = fieldBounds{name: "hour", min: 0, max: 23}
+	dayOfMonthBounds = fieldBounds{name: "day of month", min: 1, max: 31}
+	monthBounds      = fieldBounds{name: "month", min: 1, max: 12,
+		names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	// Day of week 7 is accepted for Sunday and folded into 0.
+	dayOfWeekBounds = fieldBounds{name: "day of week", min: 0, max: 7,
+		names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+
+	shorthands = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Parse parses a cron expression.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expanded, ok := shorthands[strings.ToLower(expr)]; ok {
+		expr = expanded
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d in %q", len(fields), expr)
+	}
+	s := &Schedule{}
+	var err error
+	if s.minutes, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hours, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.daysOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return nil, err
+	}
+	if s.months, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return nil, err
+	}
+	if s.daysOfWeek&(1<<7) != 0 {
+		s.daysOfWeek = s.daysOfWeek&^(1<<7) | 1
+	}
+	s.restrictedDayOfMonth = !strings.HasPrefix(fields[2], "*")
+	s.restrictedDayOfWeek = !strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// parseField returns the bit set of the values matched by a field.
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid %s step %q", bounds.name, stepPart)
+			}
+		}
+		var low, high int
+		switch lowPart, highPart, isRange := strings.Cut(rangePart, "-"); {
+		case rangePart == "*":
+			low, high = bounds.min, bounds.max
+		case isRange:
+			var err error
+			if low, err = parseValue(lowPart, bounds); err != nil {
+				return 0, err
+			}
+			if high, err = parseValue(highPart, bounds); err != nil {
+				return 0, err
+			}
+			if high < low {
+				return 0, fmt.Errorf("invalid %s range %q", bounds.name, rangePart)
+			}
+		default:
+			var err error
+			if low, err = parseValue(rangePart, bounds); err != nil {
+				return 0, err
+			}
+			high = low
+			// As in other cron implementations, "N/step" runs from N to the maximum.
+			if hasStep {
+				high = bounds.max
+			}
+		}
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(value string, bounds fieldBounds) (int, error) {
+	for i, name := range bounds.names {
+		if name != "" && strings.EqualFold(value, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < bounds.min || v > bounds.max {
+		return 0, fmt.Errorf("invalid %s %q", bounds.name, value)
+	}
+	return v, nil
+}
+
+// Next returns the first time matching the schedule strictly after t, in the location of t. The zero time is returned
+// when there is none within the Horizon.
+func (s *Schedule) Next(t time.Time) time.Time {
+	return s.find(t, 1)
+}
+
+// Prev returns the last time matching the schedule at or before t, in the location of t. The zero time is returned
+// when there is none within the Horizon.
+func (s *Schedule) Prev(t time.Time) time.Time {
+	return s.find(t, -1)
+}
+
+// find scans the days from that of t in the given direction, and the minutes of the matching days, for the closest
+// match to t.
+func (s *Schedule) find(t time.Time, direction int) time.Time {
+	t = t.Truncate(time.Minute)
+	limit := t.Add(time.Duration(direction) * Horizon)
+	for day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()); ; day = day.AddDate(0, 0, direction) {
+		if (direction > 0 && day.After(limit)) || (direction < 0 && day.Before(limit.AddDate(0, 0, -1))) {
+			return time.Time{}
+		}
+		if !s.matchesDay(day) {
+			continue
+		}
+		for i := 0; i < 24*60; i++ {
+			minuteOfDay := i
+			if direction < 0 {
+				minuteOfDay = 24*60 - 1 - i
+			}
+			hour, minute := minuteOfDay/60, minuteOfDay%60
+			if s.hours&(1<<hour) == 0 || s.minutes&(1<<minute) == 0 {
+				continue
+			}
+			candidate := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, t.Location())
+			// Skip the times that do not exist on the day because of a daylight saving time change.
+			if candidate.Hour() != hour || candidate.Minute() != minute {
+				continue
+			}
+			if (direction > 0 && candidate.After(t)) || (direction < 0 && !candidate.After(t)) {
+				if (direction > 0 && candidate.After(limit)) || (direction < 0 && candidate.Before(limit)) {
+					return time.Time{}
+				}
+				return candidate
+			}
+		}
+	}
+}
+
+func (s *Schedule) matchesDay(day time.Time) bool {
+	if s.months&(1<<int(day.Month())) == 0 {
+		return false
+	}
+	dayOfMonth := s.daysOfMonth&(1<<day.Day()) != 0
+	dayOfWeek := s.daysOfWeek&(1<<int(day.Weekday())) != 0
+	if s.restrictedDayOfMonth && s.restrictedDayOfWeek {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
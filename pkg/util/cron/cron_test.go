@@ -0,0 +1,116 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name        string
+		expr        string
+		expectError bool
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "values, ranges, steps and lists", expr: "0,30 8-18/2 1 */3 1-5"},
+		{name: "names", expr: "0 8 * jan-jun MON-fri"},
+		{name: "sunday as 7", expr: "0 0 * * 7"},
+		{name: "shorthand", expr: "@daily"},
+		{name: "too few fields", expr: "0 8 * *", expectError: true},
+		{name: "too many fields", expr: "0 0 8 * * *", expectError: true},
+		{name: "out of range", expr: "60 * * * *", expectError: true},
+		{name: "reversed range", expr: "0 18-8 * * *", expectError: true},
+		{name: "zero step", expr: "*/0 * * * *", expectError: true},
+		{name: "unknown name", expr: "0 0 * * someday", expectError: true},
+		{name: "unknown shorthand", expr: "@fortnightly", expectError: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.expr)
+			if tc.expectError {
+				assert.Error(t, err, "expected error")
+			} else {
+				assert.NoError(t, err, "unexpected error")
+			}
+		})
+	}
+}
+
+func TestNextAndPrev(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err, "unexpected error loading location")
+	// January 12, 2024 is a Friday.
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, time.January, 12, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name         string
+		expr         string
+		t            time.Time
+		expectedNext time.Time
+		expectedPrev time.Time
+	}{
+		{
+			name:         "weekdays",
+			expr:         "0 8 * * 1-5",
+			t:            friday(12, 0),
+			expectedNext: friday(8, 0).AddDate(0, 0, 3),
+			expectedPrev: friday(8, 0),
+		},
+		{
+			name:         "at a match",
+			expr:         "0 8 * * *",
+			t:            friday(8, 0),
+			expectedNext: friday(8, 0).AddDate(0, 0, 1),
+			expectedPrev: friday(8, 0),
+		},
+		{
+			name:         "steps",
+			expr:         "*/15 * * * *",
+			t:            friday(12, 7),
+			expectedNext: friday(12, 15),
+			expectedPrev: friday(12, 0),
+		},
+		{
+			name:         "day of month or day of week",
+			expr:         "0 0 15 * sun",
+			t:            friday(12, 0),
+			expectedNext: friday(0, 0).AddDate(0, 0, 2),
+			expectedPrev: friday(0, 0).AddDate(0, 0, -5),
+		},
+		{
+			name:         "sunday as 7",
+			expr:         "0 0 * * 7",
+			t:            friday(12, 0),
+			expectedNext: friday(0, 0).AddDate(0, 0, 2),
+			expectedPrev: friday(0, 0).AddDate(0, 0, -5),
+		},
+		{
+			name:         "leap day",
+			expr:         "0 0 29 2 *",
+			t:            friday(12, 0),
+			expectedNext: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			// The previous leap day is more than the horizon away.
+		},
+		{
+			name:         "skipped daylight saving time",
+			expr:         "30 2 * * *",
+			t:            time.Date(2024, time.March, 9, 12, 0, 0, 0, newYork),
+			expectedNext: time.Date(2024, time.March, 11, 2, 30, 0, 0, newYork),
+			expectedPrev: time.Date(2024, time.March, 9, 2, 30, 0, 0, newYork),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			require.NoError(t, err, "unexpected error")
+			next, prev := s.Next(tc.t), s.Prev(tc.t)
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next: expected %s, got %s", tc.expectedNext, next)
+			assert.True(t, tc.expectedPrev.Equal(prev), "unexpected prev: expected %s, got %s", tc.expectedPrev, prev)
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	oappsv1 "github.com/openshift/api/apps/v1"
 	orbacv1 "github.com/openshift/api/authorization/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1alpha1 "github.com/openshift/api/machine/v1alpha1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	ingresscontroller "github.com/openshift/api/operator/v1"
@@ -59,6 +60,7 @@ func init() {
 	hiveintv1alpha1.AddToScheme(hive_scheme)
 	hivev1.AddToScheme(hive_scheme)
 	ingresscontroller.AddToScheme(hive_scheme)
+	machinev1.Install(hive_scheme)
 	// For some reason machinev1alpha1.Install() doesn't add any types.
 	// Mimic how installer registers OpenstackProviderSpec:
 	hive_scheme.AddKnownTypes(machinev1alpha1.GroupVersion,
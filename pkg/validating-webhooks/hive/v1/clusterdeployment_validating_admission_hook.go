@@ -3,9 +3,11 @@ package v1
 import (
 	"fmt"
 	"net/http"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -35,6 +37,7 @@ import (
 	"github.com/openshift/hive/pkg/controller/awsprivatelink"
 	"github.com/openshift/hive/pkg/manageddns"
 	"github.com/openshift/hive/pkg/util/contracts"
+	"github.com/openshift/hive/pkg/util/cron"
 )
 
 const (
@@ -47,7 +50,7 @@ const (
 )
 
 var (
-	mutableFields = []string{"CertificateBundles", "ClusterMetadata", "ControlPlaneConfig", "Ingress", "Installed", "PreserveOnDelete", "ClusterPoolRef", "PowerState", "HibernateAfter", "InstallAttemptsLimit", "Platform.AgentBareMetal.AgentSelector", "Platform.AWS.PrivateLink.AdditionalAllowedPrincipals"}
+	mutableFields = []string{"CertificateBundles", "ClusterMetadata", "ControlPlaneConfig", "Ingress", "Installed", "PreserveOnDelete", "ClusterPoolRef", "PowerState", "HibernateAfter", "PowerSchedule", "InstallAttemptsLimit", "Platform.AgentBareMetal.AgentSelector", "Platform.AWS.PrivateLink.AdditionalAllowedPrincipals"}
 )
 
 // ClusterDeploymentValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
@@ -294,6 +297,9 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 
 	allErrs = append(allErrs, validateClusterPlatform(specPath.Child("platform"), cd.Spec.Platform)...)
 	allErrs = append(allErrs, validateCanManageDNSForClusterPlatform(specPath, cd.Spec)...)
+	allErrs = append(allErrs, validateControlPlaneMachines(specPath.Child("controlPlaneConfig", "machines"), cd.Spec)...)
+	allErrs = append(allErrs, validateDeleteAfter(specPath.Child("deleteAfter"), cd)...)
+	allErrs = append(allErrs, validatePowerSchedule(specPath.Child("powerSchedule"), cd.Spec.PowerSchedule)...)
 
 	if cd.Spec.Platform.AWS != nil {
 		allErrs = append(allErrs, validateAWSPrivateLink(specPath.Child("platform", "aws"), cd.Spec.Platform.AWS, a.awsPrivateLinkConfig)...)
@@ -309,6 +315,15 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 		if cd.Spec.Provisioning.ManifestsConfigMapRef != nil && cd.Spec.Provisioning.ManifestsSecretRef != nil {
 			allErrs = append(allErrs, field.Invalid(specPath.Child("provisioning", "manifestsConfigMapRef"), cd.Spec.Provisioning.ManifestsConfigMapRef.Name, "manifestsConfigMapRef and manifestsSecretRef are mutually exclusive"))
 		}
+		allErrs = append(allErrs, validateInstallHooks(specPath.Child("provisioning", "preInstallHooks"), cd.Spec.Provisioning.PreInstallHooks)...)
+		allErrs = append(allErrs, validateInstallHooks(specPath.Child("provisioning", "postInstallHooks"), cd.Spec.Provisioning.PostInstallHooks)...)
+		if ci := cd.Spec.Provisioning.CustomInstaller; ci != nil {
+			if ci.Path == "" {
+				allErrs = append(allErrs, field.Required(specPath.Child("provisioning", "customInstaller", "path"), "must specify the path of the custom installer binary"))
+			} else if !path.IsAbs(ci.Path) || path.Clean(ci.Path) != ci.Path {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("provisioning", "customInstaller", "path"), ci.Path, "must be an absolute, clean path"))
+			}
+		}
 	}
 
 	if cd.Spec.ClusterInstallRef != nil {
@@ -373,6 +388,29 @@ func validateAWSPrivateLink(path *field.Path, platform *hivev1aws.Platform, conf
 	return allErrs
 }
 
+func validateInstallHooks(path *field.Path, hooks []hivev1.InstallHook) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.New[string]()
+	for i, hook := range hooks {
+		hookPath := path.Index(i)
+		if hook.Name == "" {
+			allErrs = append(allErrs, field.Required(hookPath.Child("name"), "must specify a name for the hook"))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(hook.Name) {
+				allErrs = append(allErrs, field.Invalid(hookPath.Child("name"), hook.Name, msg))
+			}
+			if names.Has(hook.Name) {
+				allErrs = append(allErrs, field.Duplicate(hookPath.Child("name"), hook.Name))
+			}
+			names.Insert(hook.Name)
+		}
+		if hook.JobTemplateRef.Name == "" {
+			allErrs = append(allErrs, field.Required(hookPath.Child("jobTemplateRef", "name"), "must specify the configmap containing the job template"))
+		}
+	}
+	return allErrs
+}
+
 /* TODO: move to explicit validation for AgentClusterInstall */
 /*
 func validateAgentInstallStrategy(specPath *field.Path, cd *hivev1.ClusterDeployment) field.ErrorList {
@@ -559,6 +597,86 @@ func validateClusterPlatform(path *field.Path, platform hivev1.Platform) field.E
 	return allErrs
 }
 
+// validateControlPlaneMachines validates the management of the ControlPlaneMachineSet of the cluster, which is only
+// supported on AWS, Azure and GCP.
+func validateControlPlaneMachines(path *field.Path, spec hivev1.ClusterDeploymentSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	machines := spec.ControlPlaneConfig.Machines
+	if machines == nil {
+		return allErrs
+	}
+	if spec.Platform.AWS == nil && spec.Platform.Azure == nil && spec.Platform.GCP == nil {
+		allErrs = append(allErrs, field.Forbidden(path, "control plane machines can only be managed on AWS, Azure and GCP"))
+	}
+	switch machines.UpdateStrategy {
+	case "", hivev1.ControlPlaneUpdateStrategyRollingUpdate, hivev1.ControlPlaneUpdateStrategyOnDelete:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("updateStrategy"), machines.UpdateStrategy, []string{
+			string(hivev1.ControlPlaneUpdateStrategyRollingUpdate),
+			string(hivev1.ControlPlaneUpdateStrategyOnDelete),
+		}))
+	}
+	return allErrs
+}
+
+// validateDeleteAfter validates the lifetime of the cluster and its extension.
+func validateDeleteAfter(path *field.Path, cd *hivev1.ClusterDeployment) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if cd.Spec.DeleteAfter != nil && cd.Spec.DeleteAfter.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(path, cd.Spec.DeleteAfter.Duration.String(), "must be positive"))
+	}
+	if extension, ok := cd.Annotations[constants.DeleteAfterExtensionAnnotation]; ok {
+		annotationPath := field.NewPath("metadata", "annotations", constants.DeleteAfterExtensionAnnotation)
+		if dur, err := time.ParseDuration(extension); err != nil {
+			allErrs = append(allErrs, field.Invalid(annotationPath, extension, err.Error()))
+		} else if dur < 0 {
+			allErrs = append(allErrs, field.Invalid(annotationPath, extension, "cannot be negative"))
+		}
+	}
+	return allErrs
+}
+
+// validatePowerSchedule validates the running windows or cron expressions, and the time zone of a power schedule.
+func validatePowerSchedule(path *field.Path, schedule *hivev1.PowerSchedule) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if schedule == nil {
+		return allErrs
+	}
+	usesCron := schedule.Resume != "" || schedule.Hibernate != ""
+	switch {
+	case usesCron && len(schedule.RunningWindows) > 0:
+		allErrs = append(allErrs, field.Forbidden(path.Child("runningWindows"), "cannot be set together with resume and hibernate"))
+	case !usesCron && len(schedule.RunningWindows) == 0:
+		allErrs = append(allErrs, field.Required(path.Child("runningWindows"), "must specify at least one running window, or resume and hibernate"))
+	}
+	if usesCron {
+		for _, expr := range []struct {
+			name, value string
+		}{{"resume", schedule.Resume}, {"hibernate", schedule.Hibernate}} {
+			if expr.value == "" {
+				allErrs = append(allErrs, field.Required(path.Child(expr.name), "resume and hibernate must be set together"))
+			} else if _, err := cron.Parse(expr.value); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child(expr.name), expr.value, fmt.Sprintf("must be a cron expression: %v", err)))
+			}
+		}
+	}
+	for i, window := range schedule.RunningWindows {
+		for _, t := range []struct {
+			name, value string
+		}{{"start", window.Start}, {"end", window.End}} {
+			if _, err := time.Parse("15:04", t.value); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("runningWindows").Index(i).Child(t.name), t.value, "must be a time of day in HH:MM format"))
+			}
+		}
+	}
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("timeZone"), schedule.TimeZone, "must be an IANA time zone"))
+		}
+	}
+	return allErrs
+}
+
 func validateCanManageDNSForClusterPlatform(specPath *field.Path, spec hivev1.ClusterDeploymentSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
 	canManageDNS := false
@@ -708,6 +826,10 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateUpdate(admissionSpec
 		}
 	}
 
+	allErrs = append(allErrs, validateControlPlaneMachines(specPath.Child("controlPlaneConfig", "machines"), cd.Spec)...)
+	allErrs = append(allErrs, validateDeleteAfter(specPath.Child("deleteAfter"), cd)...)
+	allErrs = append(allErrs, validatePowerSchedule(specPath.Child("powerSchedule"), cd.Spec.PowerSchedule)...)
+
 	// Validate the ClusterPoolRef:
 	switch oldPoolRef, newPoolRef := oldObject.Spec.ClusterPoolRef, cd.Spec.ClusterPoolRef; {
 	case oldPoolRef != nil && newPoolRef != nil:
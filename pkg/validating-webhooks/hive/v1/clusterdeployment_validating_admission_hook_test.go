@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -994,6 +995,183 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: false,
 		},
+		{
+			name: "create with delete after",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.DeleteAfter = &metav1.Duration{Duration: 8 * time.Hour}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "create with non-positive delete after",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.DeleteAfter = &metav1.Duration{}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name:      "update with delete after extension",
+			oldObject: validAWSClusterDeployment(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Annotations = map[string]string{constants.DeleteAfterExtensionAnnotation: "24h"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: true,
+		},
+		{
+			name:      "update with invalid delete after extension",
+			oldObject: validAWSClusterDeployment(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Annotations = map[string]string{constants.DeleteAfterExtensionAnnotation: "a day"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
+		{
+			name:      "update with power schedule",
+			oldObject: validAWSClusterDeployment(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					TimeZone: "Europe/Paris",
+					RunningWindows: []hivev1.ScheduleWindow{{
+						Days:  []hivev1.ScheduleDay{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday"},
+						Start: "08:00",
+						End:   "19:00",
+					}},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: true,
+		},
+		{
+			name: "create with invalid power schedule time",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					RunningWindows: []hivev1.ScheduleWindow{{Start: "8am", End: "19:00"}},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "create with power schedule without running windows",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "create with cron power schedule",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					Resume:    "0 8 * * mon-fri",
+					Hibernate: "0 19 * * mon-fri",
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "create with invalid power schedule cron expression",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					Resume:    "0 8 * *",
+					Hibernate: "0 19 * * 1-5",
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "create with power schedule resume without hibernate",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{Resume: "0 8 * * 1-5"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "create with power schedule running windows and cron expressions",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					RunningWindows: []hivev1.ScheduleWindow{{Start: "08:00", End: "19:00"}},
+					Resume:         "0 8 * * 1-5",
+					Hibernate:      "0 19 * * 1-5",
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "create with control plane machines",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.ControlPlaneConfig.Machines = &hivev1.ControlPlaneMachinesSpec{
+					InstanceType:   "m6i.2xlarge",
+					UpdateStrategy: hivev1.ControlPlaneUpdateStrategyOnDelete,
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "create with control plane machines on unsupported platform",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validVSphereClusterDeployment()
+				cd.Spec.ControlPlaneConfig.Machines = &hivev1.ControlPlaneMachinesSpec{InstanceType: "large"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name:      "update with unsupported control plane update strategy",
+			oldObject: validGCPClusterDeployment(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPClusterDeployment()
+				cd.Spec.ControlPlaneConfig.Machines = &hivev1.ControlPlaneMachinesSpec{UpdateStrategy: "Recreate"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
+		{
+			name:      "update control plane instance type",
+			oldObject: validGCPClusterDeployment(),
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validGCPClusterDeployment()
+				cd.Spec.ControlPlaneConfig.Machines = &hivev1.ControlPlaneMachinesSpec{InstanceType: "n2-standard-8"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: true,
+		},
 		{
 			name: "create with no cloud platforms",
 			newObject: func() *hivev1.ClusterDeployment {
@@ -1429,6 +1607,74 @@ func TestClusterDeploymentValidate(t *testing.T) {
 			operation:       admissionv1beta1.Create,
 			expectedAllowed: false,
 		},
+		{
+			name: "install hooks",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.PreInstallHooks = []hivev1.InstallHook{
+					{Name: "register", JobTemplateRef: corev1.LocalObjectReference{Name: "register-job"}},
+				}
+				cd.Spec.Provisioning.PostInstallHooks = []hivev1.InstallHook{
+					{Name: "smoke-test", JobTemplateRef: corev1.LocalObjectReference{Name: "smoke-test-job"}},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "install hooks with duplicate names",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.PostInstallHooks = []hivev1.InstallHook{
+					{Name: "smoke-test", JobTemplateRef: corev1.LocalObjectReference{Name: "smoke-test-job"}},
+					{Name: "smoke-test", JobTemplateRef: corev1.LocalObjectReference{Name: "other-job"}},
+				}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "custom installer",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.CustomInstaller = &hivev1.CustomInstaller{Path: "/usr/bin/wrapper"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name: "custom installer with relative path",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.CustomInstaller = &hivev1.CustomInstaller{Path: "bin/wrapper"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "custom installer with unclean path",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.CustomInstaller = &hivev1.CustomInstaller{Path: "/usr/bin/../bin/wrapper"}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
+		{
+			name: "install hook without job template",
+			newObject: func() *hivev1.ClusterDeployment {
+				cd := validAWSClusterDeployment()
+				cd.Spec.Provisioning.PreInstallHooks = []hivev1.InstallHook{{Name: "register"}}
+				return cd
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: false,
+		},
 		{
 			// This gets caught by the "Spec is immutable except [... not Provisioning ...]" check
 			name:      "manifestsConfigMapRef and manifestsSecretRef mutually exclusive (update)",
@@ -178,6 +178,7 @@ func (a *ClusterPoolValidatingAdmissionHook) validateCreate(admissionSpec *admis
 	specPath := field.NewPath("spec")
 
 	allErrs = append(allErrs, validateClusterPlatform(specPath, newObject.Spec.Platform)...)
+	allErrs = append(allErrs, validatePowerSchedule(specPath.Child("powerSchedule"), newObject.Spec.PowerSchedule)...)
 
 	if len(allErrs) > 0 {
 		status := errors.NewInvalid(schemaGVK(admissionSpec.Kind).GroupKind(), admissionSpec.Name, allErrs).Status()
@@ -238,6 +239,7 @@ func (a *ClusterPoolValidatingAdmissionHook) validateUpdate(admissionSpec *admis
 	specPath := field.NewPath("spec")
 
 	allErrs = append(allErrs, validateClusterPlatform(specPath, newObject.Spec.Platform)...)
+	allErrs = append(allErrs, validatePowerSchedule(specPath.Child("powerSchedule"), newObject.Spec.PowerSchedule)...)
 
 	if len(allErrs) > 0 {
 		contextLogger.WithError(allErrs.ToAggregate()).Info("failed validation")
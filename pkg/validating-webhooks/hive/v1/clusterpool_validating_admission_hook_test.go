@@ -102,6 +102,32 @@ func TestClusterPoolValidate(t *testing.T) {
 			operation:       admissionv1beta1.Update,
 			expectedAllowed: true,
 		},
+		{
+			name: "Test create with power schedule",
+			newObject: func() *hivev1.ClusterPool {
+				pool := validAWSClusterPool()
+				pool.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					RunningWindows: []hivev1.ScheduleWindow{{Start: "08:00", End: "19:00"}},
+				}
+				return pool
+			}(),
+			operation:       admissionv1beta1.Create,
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test update with invalid power schedule time zone",
+			oldObject: validAWSClusterPool(),
+			newObject: func() *hivev1.ClusterPool {
+				pool := validAWSClusterPool()
+				pool.Spec.PowerSchedule = &hivev1.PowerSchedule{
+					TimeZone:       "Mars/Olympus_Mons",
+					RunningWindows: []hivev1.ScheduleWindow{{Start: "08:00", End: "19:00"}},
+				}
+				return pool
+			}(),
+			operation:       admissionv1beta1.Update,
+			expectedAllowed: false,
+		},
 		{
 			name:            "Test unable to marshal new object during create",
 			newObjectRaw:    []byte{0},
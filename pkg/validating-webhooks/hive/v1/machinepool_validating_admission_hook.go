@@ -4,16 +4,20 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -321,21 +325,138 @@ func validateMachinePoolSpecInvariants(spec *hivev1.MachinePoolSpec, fldPath *fi
 		allErrs = append(allErrs, field.Invalid(platformPath, spec.Platform, fmt.Sprintf("multiple platforms specified: %s", platforms)))
 	}
 	if spec.Autoscaling != nil {
-		autoscalingPath := fldPath.Child("autoscaling")
-		if numberOfMachineSets == 0 {
-			if spec.Autoscaling.MinReplicas < 1 && !validZeroSizeAutoscalingMinReplicas {
-				allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), spec.Autoscaling.MinReplicas, "minimum replicas must be at least 1"))
+		allErrs = append(allErrs, validateAutoscaling(spec.Autoscaling, numberOfMachineSets, validZeroSizeAutoscalingMinReplicas, fldPath.Child("autoscaling"))...)
+	}
+	if spec.SpotAllocation != nil {
+		allErrs = append(allErrs, validateSpotAllocation(spec, fldPath.Child("spotAllocation"))...)
+	}
+	if spec.RolloutStrategy != nil {
+		allErrs = append(allErrs, validateRolloutStrategy(spec.RolloutStrategy, fldPath.Child("rolloutStrategy"))...)
+	}
+	allErrs = append(allErrs, validateScalingSchedules(spec, numberOfMachineSets, validZeroSizeAutoscalingMinReplicas, fldPath.Child("scalingSchedules"))...)
+	allErrs = append(allErrs, metavalidation.ValidateLabels(spec.Labels, fldPath.Child("labels"))...)
+	return allErrs
+}
+
+func validateAutoscaling(autoscaling *hivev1.MachinePoolAutoscaling, numberOfMachineSets int, validZeroSizeAutoscalingMinReplicas bool, autoscalingPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if numberOfMachineSets == 0 {
+		if autoscaling.MinReplicas < 1 && !validZeroSizeAutoscalingMinReplicas {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), autoscaling.MinReplicas, "minimum replicas must be at least 1"))
+		}
+	} else {
+		if autoscaling.MinReplicas < int32(numberOfMachineSets) && !validZeroSizeAutoscalingMinReplicas {
+			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), autoscaling.MinReplicas, "minimum replicas must be at least the number of zones"))
+		}
+	}
+	if autoscaling.MinReplicas > autoscaling.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), autoscaling.MinReplicas, "minimum replicas must not be greater than maximum replicas"))
+	}
+	return allErrs
+}
+
+func validateScalingSchedules(spec *hivev1.MachinePoolSpec, numberOfMachineSets int, validZeroSizeAutoscalingMinReplicas bool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, schedule := range spec.ScalingSchedules {
+		schedulePath := fldPath.Index(i)
+		switch {
+		case schedule.Name == "":
+			allErrs = append(allErrs, field.Required(schedulePath.Child("name"), "must have a name"))
+		case names.Has(schedule.Name):
+			allErrs = append(allErrs, field.Duplicate(schedulePath.Child("name"), schedule.Name))
+		}
+		names.Insert(schedule.Name)
+		for _, t := range []struct {
+			name, value string
+		}{{"start", schedule.Start}, {"end", schedule.End}} {
+			if _, err := time.Parse("15:04", t.value); err != nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child(t.name), t.value, "must be a time of day in HH:MM format"))
 			}
-		} else {
-			if spec.Autoscaling.MinReplicas < int32(numberOfMachineSets) && !validZeroSizeAutoscalingMinReplicas {
-				allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), spec.Autoscaling.MinReplicas, "minimum replicas must be at least the number of zones"))
+		}
+		if schedule.TimeZone != "" {
+			if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("timeZone"), schedule.TimeZone, "must be an IANA time zone"))
 			}
 		}
-		if spec.Autoscaling.MinReplicas > spec.Autoscaling.MaxReplicas {
-			allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minReplicas"), spec.Autoscaling.MinReplicas, "minimum replicas must not be greater than maximum replicas"))
+		switch {
+		case schedule.Replicas != nil && schedule.Autoscaling != nil:
+			allErrs = append(allErrs, field.Invalid(schedulePath, schedule.Name, "replicas and autoscaling must not both be specified"))
+		case schedule.Replicas != nil:
+			if spec.Autoscaling != nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("replicas"), *schedule.Replicas, "replicas must not be specified when the machine pool uses autoscaling"))
+			}
+			if *schedule.Replicas < 0 {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("replicas"), *schedule.Replicas, "replicas count must not be negative"))
+			}
+		case schedule.Autoscaling != nil:
+			if spec.Autoscaling == nil {
+				allErrs = append(allErrs, field.Invalid(schedulePath.Child("autoscaling"), schedule.Autoscaling, "autoscaling must not be specified when the machine pool does not use autoscaling"))
+			}
+			allErrs = append(allErrs, validateAutoscaling(schedule.Autoscaling, numberOfMachineSets, validZeroSizeAutoscalingMinReplicas, schedulePath.Child("autoscaling"))...)
+		default:
+			allErrs = append(allErrs, field.Required(schedulePath, "must specify replicas or autoscaling"))
 		}
 	}
-	allErrs = append(allErrs, metavalidation.ValidateLabels(spec.Labels, fldPath.Child("labels"))...)
+	return allErrs
+}
+
+func validateRolloutStrategy(strategy *hivev1.MachinePoolRolloutStrategy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	// Scale against 100 replicas so that non-zero percentages are told apart from 0.
+	scaled := func(value *intstr.IntOrString, defaultValue int, fldPath *field.Path) int {
+		if value == nil {
+			return defaultValue
+		}
+		v, err := intstr.GetScaledValueFromIntOrPercent(value, 100, true)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath, value.String(), "must be an integer or a percentage"))
+		case v < 0:
+			allErrs = append(allErrs, field.Invalid(fldPath, value.String(), "must not be negative"))
+		}
+		return v
+	}
+	maxSurge := scaled(strategy.MaxSurge, 1, fldPath.Child("maxSurge"))
+	maxUnavailable := scaled(strategy.MaxUnavailable, 0, fldPath.Child("maxUnavailable"))
+	if maxSurge == 0 && maxUnavailable == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath, strategy, "maxSurge and maxUnavailable must not both be 0"))
+	}
+	if d := strategy.DrainTimeout; d != nil && d.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("drainTimeout"), d.Duration.String(), "drain timeout must be positive"))
+	}
+	return allErrs
+}
+
+func validateSpotAllocation(spec *hivev1.MachinePoolSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if spec.Autoscaling != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, spec.SpotAllocation, "spotAllocation must not be specified when autoscaling is specified"))
+	}
+	spotConfigured := false
+	switch {
+	case spec.Platform.AWS != nil:
+		spotConfigured = spec.Platform.AWS.SpotMarketOptions != nil
+	case spec.Platform.Azure != nil:
+		spotConfigured = spec.Platform.Azure.SpotVMOptions != nil
+	case spec.Platform.GCP != nil:
+		spotConfigured = spec.Platform.GCP.ProvisioningModel == hivev1gcp.ProvisioningModelPreemptible
+	}
+	if !spotConfigured {
+		allErrs = append(allErrs, field.Invalid(fldPath, spec.SpotAllocation, "spotAllocation requires spot capacity to be configured in the aws, azure or gcp platform"))
+	}
+	replicas := int64(1)
+	if spec.Replicas != nil {
+		replicas = *spec.Replicas
+	}
+	if base := spec.SpotAllocation.OnDemandBaseReplicas; base < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("onDemandBaseReplicas"), base, "on-demand base replicas must not be negative"))
+	} else if int64(base) > replicas {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("onDemandBaseReplicas"), base, "on-demand base replicas must not be greater than replicas"))
+	}
+	if d := spec.SpotAllocation.FallbackRetryInterval; d != nil && d.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("fallbackRetryInterval"), d.Duration.String(), "fallback retry interval must be positive"))
+	}
 	return allErrs
 }
 
@@ -373,6 +494,9 @@ func validateGCPMachinePoolPlatformInvariants(platform *hivev1gcp.MachinePool, f
 	if platform.InstanceType == "" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("instanceType"), "instance type is required"))
 	}
+	if platform.ProvisioningModel == hivev1gcp.ProvisioningModelPreemptible && platform.OnHostMaintenance == "Migrate" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("onHostMaintenance"), platform.OnHostMaintenance, "preemptible instances cannot be live migrated"))
+	}
 	return allErrs
 }
 
@@ -391,6 +515,18 @@ func validateAzureMachinePoolPlatformInvariants(platform *hivev1azure.MachinePoo
 	if osDisk.DiskSizeGB <= 0 {
 		allErrs = append(allErrs, field.Invalid(osDiskPath.Child("iops"), osDisk.DiskSizeGB, "disk size must be positive"))
 	}
+	if spot := platform.SpotVMOptions; spot != nil {
+		spotPath := fldPath.Child("spotVMOptions")
+		if spot.MaxPrice != nil {
+			maxPrice, err := resource.ParseQuantity(*spot.MaxPrice)
+			switch {
+			case err != nil:
+				allErrs = append(allErrs, field.Invalid(spotPath.Child("maxPrice"), *spot.MaxPrice, "max price must be a number"))
+			case maxPrice.Sign() <= 0 && maxPrice.Cmp(resource.MustParse("-1")) != 0:
+				allErrs = append(allErrs, field.Invalid(spotPath.Child("maxPrice"), *spot.MaxPrice, "max price must be positive, or -1 to only evict for capacity"))
+			}
+		}
+	}
 	return allErrs
 }
 
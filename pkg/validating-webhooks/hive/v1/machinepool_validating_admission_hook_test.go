@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -11,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
@@ -415,6 +417,24 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 				return pool
 			}(),
 		},
+		{
+			name: "preemptible GCP pool",
+			provision: func() *hivev1.MachinePool {
+				pool := testGCPMachinePool()
+				pool.Spec.Platform.GCP.ProvisioningModel = hivev1gcp.ProvisioningModelPreemptible
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "preemptible GCP pool with live migration",
+			provision: func() *hivev1.MachinePool {
+				pool := testGCPMachinePool()
+				pool.Spec.Platform.GCP.ProvisioningModel = hivev1gcp.ProvisioningModelPreemptible
+				pool.Spec.Platform.GCP.OnHostMaintenance = "Migrate"
+				return pool
+			}(),
+		},
 		{
 			name: "explicit Azure zones",
 			provision: func() *hivev1.MachinePool {
@@ -448,6 +468,251 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 				return pool
 			}(),
 		},
+		{
+			name: "Azure spot VMs",
+			provision: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool()
+				pool.Spec.Platform.Azure.SpotVMOptions = &hivev1azure.SpotVMOptions{MaxPrice: pointer.String("0.05")}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "Azure spot VMs evicted only for capacity",
+			provision: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool()
+				pool.Spec.Platform.Azure.SpotVMOptions = &hivev1azure.SpotVMOptions{MaxPrice: pointer.String("-1")}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "invalid Azure spot VM max price",
+			provision: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool()
+				pool.Spec.Platform.Azure.SpotVMOptions = &hivev1azure.SpotVMOptions{MaxPrice: pointer.String("-2")}
+				return pool
+			}(),
+		},
+		{
+			name: "spot allocation with AWS spot instances",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Replicas = pointer.Int64(3)
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{}
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{OnDemandBaseReplicas: 1}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "spot allocation with GCP preemptible instances",
+			provision: func() *hivev1.MachinePool {
+				pool := testGCPMachinePool()
+				pool.Spec.Replicas = pointer.Int64(3)
+				pool.Spec.Platform.GCP.ProvisioningModel = hivev1gcp.ProvisioningModelPreemptible
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{
+					OnDemandBaseReplicas:  3,
+					FallbackRetryInterval: &metav1.Duration{Duration: 30 * time.Minute},
+				}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "spot allocation without spot capacity",
+			provision: func() *hivev1.MachinePool {
+				pool := testAzureMachinePool()
+				pool.Spec.Replicas = pointer.Int64(3)
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{OnDemandBaseReplicas: 1}
+				return pool
+			}(),
+		},
+		{
+			name: "spot allocation with autoscaling",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 3}
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{}
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{OnDemandBaseReplicas: 1}
+				return pool
+			}(),
+		},
+		{
+			name: "spot allocation with more on-demand base replicas than replicas",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Replicas = pointer.Int64(2)
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{}
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{OnDemandBaseReplicas: 3}
+				return pool
+			}(),
+		},
+		{
+			name: "spot allocation with negative fallback retry interval",
+			provision: func() *hivev1.MachinePool {
+				pool := testAWSMachinePool()
+				pool.Spec.Replicas = pointer.Int64(2)
+				pool.Spec.Platform.AWS.SpotMarketOptions = &hivev1aws.SpotMarketOptions{}
+				pool.Spec.SpotAllocation = &hivev1.MachinePoolSpotAllocation{
+					FallbackRetryInterval: &metav1.Duration{Duration: -time.Minute},
+				}
+				return pool
+			}(),
+		},
+		{
+			name: "rollout strategy",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxSurge, maxUnavailable := intstr.FromString("25%"), intstr.FromInt(1)
+				pool.Spec.RolloutStrategy = &hivev1.MachinePoolRolloutStrategy{
+					MaxSurge:       &maxSurge,
+					MaxUnavailable: &maxUnavailable,
+					DrainTimeout:   &metav1.Duration{Duration: 10 * time.Minute},
+				}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "default rollout strategy",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.RolloutStrategy = &hivev1.MachinePoolRolloutStrategy{}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "rollout strategy without surge nor unavailability",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxSurge := intstr.FromString("0%")
+				pool.Spec.RolloutStrategy = &hivev1.MachinePoolRolloutStrategy{MaxSurge: &maxSurge}
+				return pool
+			}(),
+		},
+		{
+			name: "rollout strategy with invalid max surge",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxSurge := intstr.FromString("one")
+				pool.Spec.RolloutStrategy = &hivev1.MachinePoolRolloutStrategy{MaxSurge: &maxSurge}
+				return pool
+			}(),
+		},
+		{
+			name: "rollout strategy with negative max unavailable",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				maxUnavailable := intstr.FromInt(-1)
+				pool.Spec.RolloutStrategy = &hivev1.MachinePoolRolloutStrategy{MaxUnavailable: &maxUnavailable}
+				return pool
+			}(),
+		},
+		{
+			name: "scaling schedule",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				replicas := int64(2)
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:     "night",
+					Days:     []hivev1.ScheduleDay{"Monday", "Tuesday"},
+					Start:    "20:00",
+					End:      "08:00",
+					TimeZone: "Europe/Paris",
+					Replicas: &replicas,
+				}}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "scaling schedule with autoscaling bounds",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 10}
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:        "night",
+					Start:       "20:00",
+					End:         "08:00",
+					Autoscaling: &hivev1.MachinePoolAutoscaling{MinReplicas: 1, MaxReplicas: 3},
+				}}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "scaling schedule with replicas for autoscaling pool",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				replicas := int64(2)
+				pool.Spec.Autoscaling = &hivev1.MachinePoolAutoscaling{MinReplicas: 3, MaxReplicas: 10}
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:     "night",
+					Start:    "20:00",
+					End:      "08:00",
+					Replicas: &replicas,
+				}}
+				return pool
+			}(),
+		},
+		{
+			name: "scaling schedule without override",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:  "night",
+					Start: "20:00",
+					End:   "08:00",
+				}}
+				return pool
+			}(),
+		},
+		{
+			name: "scaling schedule with invalid time",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				replicas := int64(2)
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:     "night",
+					Start:    "8pm",
+					End:      "08:00",
+					Replicas: &replicas,
+				}}
+				return pool
+			}(),
+		},
+		{
+			name: "scaling schedule with invalid time zone",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				replicas := int64(2)
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{{
+					Name:     "night",
+					Start:    "20:00",
+					End:      "08:00",
+					TimeZone: "Mars/Olympus_Mons",
+					Replicas: &replicas,
+				}}
+				return pool
+			}(),
+		},
+		{
+			name: "scaling schedules with duplicate names",
+			provision: func() *hivev1.MachinePool {
+				pool := testMachinePool()
+				replicas := int64(2)
+				schedule := hivev1.MachinePoolScalingSchedule{
+					Name:     "night",
+					Start:    "20:00",
+					End:      "08:00",
+					Replicas: &replicas,
+				}
+				pool.Spec.ScalingSchedules = []hivev1.MachinePoolScalingSchedule{schedule, schedule}
+				return pool
+			}(),
+		},
 		{
 			name: "valid labels",
 			provision: func() *hivev1.MachinePool {